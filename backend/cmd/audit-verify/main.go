@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/option"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/config"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/services"
+)
+
+// audit-verify checks the activity log hash chain of each tenant and reports the first broken link
+// (removed entries, modified entries, checkpoints that do not match or have an invalid signature).
+// With -checkpoint, a signed checkpoint of the head is created for every valid chain.
+//
+// Exits with status 1 when a chain is broken.
+//
+// Usage: go run ./cmd/audit-verify [-tenant <id>] [-from-checkpoint] [-max 50000] [-checkpoint]
+func main() {
+	tenantID := flag.String("tenant", "", "Verify a single tenant (default: all tenants)")
+	fromCheckpoint := flag.Bool("from-checkpoint", false, "Start after the latest signed checkpoint")
+	maxEntries := flag.Int("max", 0, "Maximum entries verified per tenant (default 50000)")
+	createCheckpoint := flag.Bool("checkpoint", false, "Sign a checkpoint of the head of every valid chain")
+	flag.Parse()
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+	if cfg.AuditCheckpointKey == "" {
+		log.Println("⚠️  AUDIT_CHECKPOINT_KEY not set - checkpoint signatures will not be verified")
+	}
+
+	ctx := context.Background()
+	client, err := firestore.NewClientWithDatabase(ctx, cfg.FirebaseProjectID, cfg.FirestoreDatabase, option.WithCredentialsFile(cfg.FirebaseCredentials))
+	if err != nil {
+		log.Fatalf("Failed to initialize Firestore: %v", err)
+	}
+	defer client.Close()
+
+	auditChainService := services.NewAuditChainService(
+		repositories.NewActivityLogRepository(client),
+		repositories.NewAuditCheckpointRepository(client),
+		repositories.NewTenantRepository(client),
+		cfg.AuditCheckpointKey,
+	)
+
+	tenantIDs := []string{*tenantID}
+	if *tenantID == "" {
+		tenantIDs, err = listTenantIDs(ctx, client)
+		if err != nil {
+			log.Fatalf("Failed to list tenants: %v", err)
+		}
+	}
+
+	broken := 0
+	for _, tid := range tenantIDs {
+		report, err := auditChainService.VerifyChain(ctx, services.VerifyAuditChainInput{
+			TenantID:             tid,
+			FromLatestCheckpoint: *fromCheckpoint,
+			MaxEntries:           *maxEntries,
+		})
+		if err != nil {
+			log.Fatalf("Failed to verify tenant %s: %v", tid, err)
+		}
+
+		if !report.Valid {
+			broken++
+			b := report.FirstBreak
+			log.Printf("❌ %s: broken at sequence %d (log %s): %s (expected %q, got %q)", tid, b.Sequence, b.LogID, b.Reason, b.Expected, b.Actual)
+			continue
+		}
+
+		status := "complete"
+		if !report.Complete {
+			status = "partial - increase -max or use -from-checkpoint"
+		}
+		log.Printf("✅ %s: %d entries verified (sequences %d-%d of %d, %d checkpoints, %s)",
+			tid, report.EntriesVerified, report.FromSequence, report.LastSequence, report.HeadSequence, report.CheckpointsVerified, status)
+
+		if *createCheckpoint && report.HeadSequence > 0 {
+			checkpoint, err := auditChainService.CreateCheckpoint(ctx, tid, "system")
+			if err != nil {
+				log.Printf("⚠️  %s: checkpoint not created: %v", tid, err)
+				continue
+			}
+			log.Printf("🔏 %s: checkpoint %s signed (key %s)", tid, checkpoint.ID, checkpoint.KeyID)
+		}
+	}
+
+	if broken > 0 {
+		log.Printf("❌ %d of %d tenant chains are broken", broken, len(tenantIDs))
+		os.Exit(1)
+	}
+	log.Printf("✅ %d tenant chains verified", len(tenantIDs))
+}
+
+// listTenantIDs returns every tenant (active or not)
+func listTenantIDs(ctx context.Context, client *firestore.Client) ([]string, error) {
+	refs, err := client.Collection("tenants").DocumentRefs(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		ids = append(ids, ref.ID)
+	}
+	return ids, nil
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/option"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/config"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/services"
+)
+
+// market-snapshots generates the monthly market analytics snapshots for every active
+// tenant plus the anonymized marketplace snapshot. Schedule it monthly (e.g., Cloud Scheduler
+// on the 1st at 03:00) so analytics endpoints never scan properties per request.
+//
+// Usage: go run ./cmd/market-snapshots [-period 2026-09]
+func main() {
+	period := flag.String("period", "", "Period to compute (YYYY-MM). Default: previous month")
+	flag.Parse()
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	ctx := context.Background()
+	client, err := firestore.NewClientWithDatabase(ctx, cfg.FirebaseProjectID, cfg.FirestoreDatabase, option.WithCredentialsFile(cfg.FirebaseCredentials))
+	if err != nil {
+		log.Fatalf("Failed to initialize Firestore: %v", err)
+	}
+	defer client.Close()
+
+	analyticsService := services.NewMarketAnalyticsService(
+		repositories.NewMarketSnapshotRepository(client),
+		repositories.NewPropertyRepository(client),
+		repositories.NewTenantRepository(client),
+		repositories.NewActivityLogRepository(client),
+	)
+
+	snapshot, err := analyticsService.GenerateMarketplaceSnapshot(ctx, *period)
+	if err != nil {
+		log.Fatalf("Failed to generate market snapshots: %v", err)
+	}
+
+	log.Printf("✅ Market snapshots for %s generated (%d properties, %d marketplace segments)",
+		snapshot.Period, snapshot.TotalProperties, len(snapshot.Segments))
+}
@@ -0,0 +1,255 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"flag"
+	"fmt"
+	"log"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/config"
+	"github.com/altatech/ecosistema-imob/backend/internal/pii"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// pii-rotate brings encrypted personal data up to date with the current key:
+//   - plaintext values (written before encryption was enabled) are encrypted
+//   - values wrapped with an old key are re-encrypted with the current key
+//   - missing or stale blind indexes (*_bidx) are recomputed
+//
+// Key rotation procedure:
+//  1. go run ./cmd/pii-rotate -new-key          (prints a new random key)
+//  2. add it to the key file and set current_key_id to it (keep the old keys)
+//  3. restart the API, then run: go run ./cmd/pii-rotate [-dry-run]
+//  4. once it reports 0 pending documents, remove the old key from the file
+//
+// Usage: go run ./cmd/pii-rotate [-dry-run] [-tenant <id>] [-batch 200]
+func main() {
+	dryRun := flag.Bool("dry-run", false, "Only report what would change")
+	tenantID := flag.String("tenant", "", "Process a single tenant (default: all tenants)")
+	batchSize := flag.Int("batch", 200, "Writes per batch")
+	newKey := flag.Bool("new-key", false, "Print a new random 32-byte key (base64) and exit")
+	flag.Parse()
+
+	if *newKey {
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			log.Fatalf("Failed to generate key: %v", err)
+		}
+		fmt.Println(base64.StdEncoding.EncodeToString(key))
+		return
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+	if cfg.PIIKeyFile == "" {
+		log.Fatalf("PII_KEY_FILE is required")
+	}
+
+	provider, err := pii.LoadLocalKeyProvider(cfg.PIIKeyFile)
+	if err != nil {
+		log.Fatalf("Failed to load PII keys: %v", err)
+	}
+	encryptor := pii.NewEncryptor(provider)
+
+	ctx := context.Background()
+	client, err := firestore.NewClientWithDatabase(ctx, cfg.FirebaseProjectID, cfg.FirestoreDatabase, option.WithCredentialsFile(cfg.FirebaseCredentials))
+	if err != nil {
+		log.Fatalf("Failed to initialize Firestore: %v", err)
+	}
+	defer client.Close()
+
+	tenantIDs := []string{*tenantID}
+	if *tenantID == "" {
+		tenantIDs, err = listTenantIDs(ctx, client)
+		if err != nil {
+			log.Fatalf("Failed to list tenants: %v", err)
+		}
+	}
+
+	collections := map[string]map[string]pii.FieldKind{
+		"owners":                repositories.OwnerPIIFields,
+		"leads":                 repositories.LeadPIIFields,
+		"contacts":              repositories.ContactPIIFields,
+		"users":                 repositories.UserPIIFields,
+		"data_subject_requests": repositories.DataSubjectRequestPIIFields,
+		"whatsapp_threads":      repositories.WhatsAppThreadPIIFields,
+	}
+
+	log.Printf("🔐 Rotating PII to key %s (dry-run: %v, tenants: %d)", provider.CurrentKeyID(), *dryRun, len(tenantIDs))
+
+	totalScanned, totalUpdated := 0, 0
+	for _, tid := range tenantIDs {
+		for collection, fields := range collections {
+			scanned, updated, err := rotateCollection(ctx, client, encryptor, tid, collection, fields, *batchSize, *dryRun)
+			if err != nil {
+				log.Fatalf("Failed to rotate %s of tenant %s: %v", collection, tid, err)
+			}
+			if updated > 0 {
+				log.Printf("  %s/%s: %d of %d documents updated", tid, collection, updated, scanned)
+			}
+			totalScanned += scanned
+			totalUpdated += updated
+		}
+
+		scanned, updated, err := rotateLeadMessages(ctx, client, encryptor, tid, *batchSize, *dryRun)
+		if err != nil {
+			log.Fatalf("Failed to rotate conversation messages of tenant %s: %v", tid, err)
+		}
+		if updated > 0 {
+			log.Printf("  %s/leads/*/messages: %d of %d documents updated", tid, updated, scanned)
+		}
+		totalScanned += scanned
+		totalUpdated += updated
+
+		rekeyed, err := rekeyWhatsAppThreads(ctx, client, encryptor, tid, *dryRun)
+		if err != nil {
+			log.Fatalf("Failed to re-key whatsapp threads of tenant %s: %v", tid, err)
+		}
+		if rekeyed > 0 {
+			log.Printf("  %s/whatsapp_threads: %d threads keyed by phone blind index", tid, rekeyed)
+		}
+		totalUpdated += rekeyed
+	}
+
+	if *dryRun {
+		log.Printf("✅ Dry run: %d of %d documents pending rotation", totalUpdated, totalScanned)
+		return
+	}
+	log.Printf("✅ Rotation complete: %d of %d documents updated", totalUpdated, totalScanned)
+}
+
+// listTenantIDs returns every tenant (active or not)
+func listTenantIDs(ctx context.Context, client *firestore.Client) ([]string, error) {
+	refs, err := client.Collection("tenants").DocumentRefs(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		ids = append(ids, ref.ID)
+	}
+	return ids, nil
+}
+
+// rotateCollection re-encrypts the PII fields of every document in a tenant collection
+func rotateCollection(ctx context.Context, client *firestore.Client, encryptor *pii.Encryptor, tenantID, collection string, fields map[string]pii.FieldKind, batchSize int, dryRun bool) (int, int, error) {
+	iter := client.Collection(fmt.Sprintf("tenants/%s/%s", tenantID, collection)).Documents(ctx)
+	defer iter.Stop()
+
+	scanned, updated := 0, 0
+	batch := client.Batch()
+	pending := 0
+
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return scanned, updated, err
+		}
+		scanned++
+
+		updates, err := repositories.RotatePIIFields(ctx, encryptor, fields, doc.Data())
+		if err != nil {
+			return scanned, updated, fmt.Errorf("document %s: %w", doc.Ref.ID, err)
+		}
+		if updates == nil {
+			continue
+		}
+		updated++
+
+		if dryRun {
+			continue
+		}
+
+		batch.Set(doc.Ref, updates, firestore.MergeAll)
+		pending++
+
+		if pending >= batchSize {
+			if _, err := batch.Commit(ctx); err != nil {
+				return scanned, updated, fmt.Errorf("failed to commit batch: %w", err)
+			}
+			batch = client.Batch()
+			pending = 0
+		}
+	}
+
+	if pending > 0 {
+		if _, err := batch.Commit(ctx); err != nil {
+			return scanned, updated, fmt.Errorf("failed to commit batch: %w", err)
+		}
+	}
+
+	return scanned, updated, nil
+}
+
+// rotateLeadMessages re-encrypts the sender/recipient of the conversation messages of every lead of a tenant
+func rotateLeadMessages(ctx context.Context, client *firestore.Client, encryptor *pii.Encryptor, tenantID string, batchSize int, dryRun bool) (int, int, error) {
+	refs, err := client.Collection(fmt.Sprintf("tenants/%s/leads", tenantID)).DocumentRefs(ctx).GetAll()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	totalScanned, totalUpdated := 0, 0
+	for _, ref := range refs {
+		scanned, updated, err := rotateCollection(ctx, client, encryptor, tenantID, "leads/"+ref.ID+"/messages", repositories.ConversationMessagePIIFields, batchSize, dryRun)
+		if err != nil {
+			return totalScanned, totalUpdated, err
+		}
+		totalScanned += scanned
+		totalUpdated += updated
+	}
+	return totalScanned, totalUpdated, nil
+}
+
+// rekeyWhatsAppThreads moves the WhatsApp threads written before encryption was enabled (document ID = wa_id)
+// to the phone blind index document ID
+func rekeyWhatsAppThreads(ctx context.Context, client *firestore.Client, encryptor *pii.Encryptor, tenantID string, dryRun bool) (int, error) {
+	collection := client.Collection(fmt.Sprintf("tenants/%s/whatsapp_threads", tenantID))
+	docs, err := collection.Documents(ctx).GetAll()
+	if err != nil {
+		return 0, err
+	}
+
+	rekeyed := 0
+	for _, doc := range docs {
+		if !isWhatsAppID(doc.Ref.ID) {
+			continue // Already keyed by blind index
+		}
+		rekeyed++
+		if dryRun {
+			continue
+		}
+
+		batch := client.Batch()
+		batch.Set(collection.Doc(encryptor.BlindIndex(pii.FieldPhone, doc.Ref.ID)), doc.Data())
+		batch.Delete(doc.Ref)
+		if _, err := batch.Commit(ctx); err != nil {
+			return rekeyed, fmt.Errorf("failed to re-key thread %s: %w", doc.Ref.ID, err)
+		}
+	}
+	return rekeyed, nil
+}
+
+// isWhatsAppID reports whether a document ID is a WhatsApp ID (phone digits) rather than a blind index (64 hex chars)
+func isWhatsAppID(id string) bool {
+	if id == "" || len(id) > 15 {
+		return false
+	}
+	for _, r := range id {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
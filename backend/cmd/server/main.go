@@ -19,6 +19,7 @@ import (
 	"github.com/altatech/ecosistema-imob/backend/internal/config"
 	"github.com/altatech/ecosistema-imob/backend/internal/handlers"
 	"github.com/altatech/ecosistema-imob/backend/internal/middleware"
+	"github.com/altatech/ecosistema-imob/backend/internal/pii"
 	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
 	"github.com/altatech/ecosistema-imob/backend/internal/services"
 	"github.com/altatech/ecosistema-imob/backend/internal/storage"
@@ -45,8 +46,14 @@ func main() {
 
 	log.Println("Firebase initialized successfully")
 
+	// Initialize field-level PII encryption (optional)
+	piiEncryptor, err := initializePIIEncryptor(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize PII encryption: %v", err)
+	}
+
 	// Initialize repositories
-	repos := initializeRepositories(firestoreClient)
+	repos := initializeRepositories(firestoreClient, piiEncryptor)
 	log.Println("Repositories initialized")
 
 	// Initialize services
@@ -60,9 +67,16 @@ func main() {
 	// Initialize middleware
 	authMiddleware := middleware.NewAuthMiddleware(authClient)
 	tenantMiddleware := middleware.NewTenantMiddleware(repos.TenantRepo)
+	piiAccessMiddleware := middleware.NewPIIAccessMiddleware(repos.UserRepo)
+	rateLimitStore := initializeRateLimitStore(cfg, repos)
+	apiKeyMiddleware := middleware.NewAPIKeyMiddleware(authMiddleware, services.APIKeyService, rateLimitStore)
+	hostTenantMiddleware, err := middleware.NewHostTenantMiddleware(services.DomainService, cfg.TrustedProxies)
+	if err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
 
 	// Setup router
-	router := setupRouter(cfg, handlers, authMiddleware, tenantMiddleware)
+	router := setupRouter(cfg, handlers, authMiddleware, apiKeyMiddleware, tenantMiddleware, hostTenantMiddleware, piiAccessMiddleware, rateLimitStore)
 	log.Println("Router configured")
 
 	// Create HTTP server
@@ -141,11 +155,86 @@ type Repositories struct {
 	ActivityLogRepo               *repositories.ActivityLogRepository
 	OwnerConfirmationTokenRepo    *repositories.OwnerConfirmationTokenRepository    // PROMPT 08
 	ScheduledConfirmationRepo     *repositories.ScheduledConfirmationRepository     // Monthly confirmations
+	MarketSnapshotRepo            *repositories.MarketSnapshotRepository            // Market analytics
+	MetricsRepo                   *repositories.MetricsRepository                   // View tracking
+	OwnerReportRepo               *repositories.OwnerReportRepository               // Monthly owner reports
+	OwnerPortalTokenRepo          *repositories.OwnerPortalTokenRepository          // Owner portal
+	PropertyDocumentRepo          *repositories.PropertyDocumentRepository          // Owner portal documents
+	SaleAuthorizationRepo         *repositories.SaleAuthorizationRepository         // Sale authorizations
+	DataSubjectRequestRepo        *repositories.DataSubjectRequestRepository        // LGPD data subject requests
+	RetentionRunRepo              *repositories.RetentionRunRepository              // LGPD retention sweeps
+	ConsentRepo                   *repositories.ConsentRepository                   // LGPD consent ledger
+	AuditCheckpointRepo           *repositories.AuditCheckpointRepository           // Activity log hash chain checkpoints
+	WebhookRepo                   *repositories.WebhookRepository                   // Outbound webhooks
+	OutboxRepo                    *repositories.OutboxRepository                    // Transactional outbox (domain events)
+	APIKeyRepo                    *repositories.APIKeyRepository                    // Partner API keys
+	RateLimitRepo                 *repositories.RateLimitRepository                 // Shared rate limit counters
+	UsageRepo                     *repositories.UsageRepository                     // Billing usage metering
+	InvoiceRepo                   *repositories.InvoiceRepository                   // Billing invoices
+	TenantDomainRepo              *repositories.TenantDomainRepository              // Tenant custom domains
+	WhatsAppLinkRepo              *repositories.WhatsAppLinkRepository              // WhatsApp click-tracking short links
+	ConversationRepo              *repositories.ConversationRepository              // Lead conversation history (messages)
+	InboundEmailRepo              *repositories.InboundEmailRepository              // Portal lead notification e-mails
+	ContactRepo                   *repositories.ContactRepository                   // Contacts (leads deduplicated by person)
+	PIIEncryptor                  *pii.Encryptor                                    // Field-level PII encryption (nil = disabled)
+}
+
+// initializePIIEncryptor loads the PII key provider
+// Development uses a local key file (PII_KEY_FILE); production deployments plug a
+// pii.KMSClient into pii.NewKMSKeyProvider instead. Returns nil when not configured.
+func initializePIIEncryptor(cfg *config.Config) (*pii.Encryptor, error) {
+	if cfg.PIIKeyFile == "" {
+		if cfg.IsProduction() {
+			log.Println("⚠️  PII_KEY_FILE not set - personal data will be stored in plaintext")
+		}
+		return nil, nil
+	}
+
+	provider, err := pii.LoadLocalKeyProvider(cfg.PIIKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("🔐 PII encryption enabled (current key: %s)", provider.CurrentKeyID())
+	return pii.NewEncryptor(provider), nil
+}
+
+// initializeRateLimitStore selects where rate limit counters are kept (RATE_LIMIT_STORE)
+// "memory" counts per process; "firestore" shares the counters between every replica
+func initializeRateLimitStore(cfg *config.Config, repos *Repositories) middleware.RateLimitStore {
+	switch cfg.RateLimitStore {
+	case "firestore":
+		log.Println("🚦 Rate limit counters shared in Firestore")
+		return middleware.NewCounterRateLimitStore(repos.RateLimitRepo)
+	case "fake":
+		log.Println("🚦 Rate limit counters in the local fake counter backend")
+		return middleware.NewCounterRateLimitStore(middleware.NewFakeRateLimitCounter())
+	default:
+		if cfg.IsProduction() {
+			log.Println("⚠️  RATE_LIMIT_STORE=memory - limits are counted per instance")
+		}
+		return middleware.NewMemoryRateLimitStore()
+	}
+}
+
+// initializePaymentGateway selects the gateway that charges the tenant invoices (PAYMENT_GATEWAY)
+// Production gateways plug in here by implementing services.PaymentGateway
+func initializePaymentGateway(cfg *config.Config) services.PaymentGateway {
+	switch cfg.PaymentGateway {
+	case "fake":
+		if cfg.IsProduction() {
+			log.Println("⚠️  PAYMENT_GATEWAY=fake - invoices are not really charged")
+		}
+		return services.NewFakePaymentGateway()
+	default:
+		log.Printf("⚠️  Unknown PAYMENT_GATEWAY %q - using the fake gateway", cfg.PaymentGateway)
+		return services.NewFakePaymentGateway()
+	}
 }
 
 // initializeRepositories initializes all repositories
-func initializeRepositories(client *firestore.Client) *Repositories {
-	return &Repositories{
+func initializeRepositories(client *firestore.Client, piiEncryptor *pii.Encryptor) *Repositories {
+	repos := &Repositories{
 		TenantRepo:                 repositories.NewTenantRepository(client),
 		BrokerRepo:                 repositories.NewBrokerRepository(client),
 		UserRepo:                   repositories.NewUserRepository(client),                    // PROMPT 10
@@ -157,7 +246,41 @@ func initializeRepositories(client *firestore.Client) *Repositories {
 		ActivityLogRepo:            repositories.NewActivityLogRepository(client),
 		OwnerConfirmationTokenRepo: repositories.NewOwnerConfirmationTokenRepository(client), // PROMPT 08
 		ScheduledConfirmationRepo:  repositories.NewScheduledConfirmationRepository(client),  // Monthly confirmations
+		MarketSnapshotRepo:         repositories.NewMarketSnapshotRepository(client),         // Market analytics
+		MetricsRepo:                repositories.NewMetricsRepository(client),                // View tracking
+		OwnerReportRepo:            repositories.NewOwnerReportRepository(client),            // Monthly owner reports
+		OwnerPortalTokenRepo:       repositories.NewOwnerPortalTokenRepository(client),       // Owner portal
+		PropertyDocumentRepo:       repositories.NewPropertyDocumentRepository(client),       // Owner portal documents
+		SaleAuthorizationRepo:      repositories.NewSaleAuthorizationRepository(client),      // Sale authorizations
+		DataSubjectRequestRepo:     repositories.NewDataSubjectRequestRepository(client),     // LGPD data subject requests
+		RetentionRunRepo:           repositories.NewRetentionRunRepository(client),           // LGPD retention sweeps
+		ConsentRepo:                repositories.NewConsentRepository(client),                // LGPD consent ledger
+		AuditCheckpointRepo:        repositories.NewAuditCheckpointRepository(client),        // Activity log hash chain checkpoints
+		WebhookRepo:                repositories.NewWebhookRepository(client),                // Outbound webhooks
+		OutboxRepo:                 repositories.NewOutboxRepository(client),                 // Transactional outbox (domain events)
+		APIKeyRepo:                 repositories.NewAPIKeyRepository(client),                 // Partner API keys
+		RateLimitRepo:              repositories.NewRateLimitRepository(client),              // Shared rate limit counters
+		UsageRepo:                  repositories.NewUsageRepository(client),                  // Billing usage metering
+		InvoiceRepo:                repositories.NewInvoiceRepository(client),                // Billing invoices
+		TenantDomainRepo:           repositories.NewTenantDomainRepository(client),           // Tenant custom domains
+		WhatsAppLinkRepo:           repositories.NewWhatsAppLinkRepository(client),           // WhatsApp click-tracking short links
+		ConversationRepo:           repositories.NewConversationRepository(client),           // Lead conversation history (messages)
+		InboundEmailRepo:           repositories.NewInboundEmailRepository(client),           // Portal lead notification e-mails
+		ContactRepo:                repositories.NewContactRepository(client),                // Contacts (leads deduplicated by person)
+		PIIEncryptor:               piiEncryptor,
+	}
+
+	// Field-level encryption of owner/lead/user PII
+	if piiEncryptor != nil {
+		repos.OwnerRepo.SetEncryptor(piiEncryptor)
+		repos.LeadRepo.SetEncryptor(piiEncryptor)
+		repos.ContactRepo.SetEncryptor(piiEncryptor)
+		repos.UserRepo.SetEncryptor(piiEncryptor)
+		repos.DataSubjectRequestRepo.SetEncryptor(piiEncryptor)
+		repos.ConversationRepo.SetEncryptor(piiEncryptor)
 	}
+
+	return repos
 }
 
 // Services holds all service instances
@@ -176,6 +299,29 @@ type Services struct {
 	ImportService                 *services.ImportService
 	OwnerConfirmationService      *services.OwnerConfirmationService      // PROMPT 08
 	MonthlyConfirmationScheduler  *services.MonthlyConfirmationScheduler  // Monthly confirmations
+	MarketAnalyticsService        *services.MarketAnalyticsService        // Market analytics
+	ListingMetricsService         *services.ListingMetricsService         // View tracking
+	OwnerReportService            *services.OwnerReportService            // Monthly owner reports
+	OwnerPortalService            *services.OwnerPortalService            // Owner portal
+	SaleAuthorizationService      *services.SaleAuthorizationService      // Sale authorizations
+	PropertyDocumentService       *services.PropertyDocumentService       // Document vault
+	DataSubjectRequestService     *services.DataSubjectRequestService     // LGPD data subject requests
+	RetentionService              *services.RetentionService              // LGPD retention policy
+	ConsentService                *services.ConsentService                // LGPD consent ledger
+	AuditChainService             *services.AuditChainService             // Activity log hash chain
+	WebhookService                *services.WebhookService                // Outbound webhooks
+	OutboxRelay                   *services.OutboxRelay                   // Transactional outbox (domain events)
+	APIKeyService                 *services.APIKeyService                 // Partner API keys
+	PlanService                   *services.PlanService                   // Subscription plans and quotas
+	UsageService                  *services.UsageService                  // Billing usage metering
+	BillingService                *services.BillingService                // Billing invoices and dunning
+	DomainService                 *services.DomainService                 // Tenant custom domains and site URLs
+	WhatsAppLinkService           *services.WhatsAppLinkService           // WhatsApp click-tracking short links
+	WhatsAppInboundService        *services.WhatsAppInboundService        // WhatsApp inbound webhook (conversations → leads)
+	ConversationService           *services.ConversationService           // Unified lead conversation inbox
+	EmailInboundService           *services.EmailInboundService           // Portal lead e-mails (ZAP, VivaReal, OLX → leads)
+	ContactService                *services.ContactService                // Contacts: lead deduplication, merge and timelines
+	LeadScoringService            *services.LeadScoringService            // Rule-based lead scoring
 }
 
 // initializeServices initializes all services
@@ -207,6 +353,36 @@ func initializeServices(ctx context.Context, cfg *config.Config, repos *Reposito
 		importService = services.NewImportService(client)
 		log.Println("⚠️  ImportService initialized WITHOUT photo processing")
 	}
+	importService.SetEncryptor(repos.PIIEncryptor)
+
+	// Subscription plans: quotas and features of the tenant's plan, trial expiry
+	planService := services.NewPlanService(
+		repos.TenantRepo,
+		repos.PropertyRepo,
+		repos.BrokerRepo,
+		repos.ActivityLogRepo,
+	)
+	planService.SetImportService(importService)
+	importService.SetPlanService(planService)
+
+	// Billing: daily usage metering, monthly invoices charged through the payment gateway, dunning
+	usageService := services.NewUsageService(
+		repos.UsageRepo,
+		repos.TenantRepo,
+		repos.ListingRepo,
+	)
+	if storageService != nil {
+		usageService.SetStorageUsageReader(storageService)
+	}
+	importService.SetUsageService(usageService)
+
+	billingService := services.NewBillingService(
+		repos.InvoiceRepo,
+		repos.TenantRepo,
+		repos.ActivityLogRepo,
+		usageService,
+		initializePaymentGateway(cfg),
+	)
 
 	// PROMPT 08: Initialize OwnerConfirmationService
 	ownerConfirmationService := services.NewOwnerConfirmationService(
@@ -217,6 +393,7 @@ func initializeServices(ctx context.Context, cfg *config.Config, repos *Reposito
 		repos.ListingRepo,
 		repos.ActivityLogRepo,
 	)
+	ownerConfirmationService.SetTenantRepository(repos.TenantRepo)
 
 	// Initialize MonthlyConfirmationScheduler
 	monthlyConfirmationScheduler := services.NewMonthlyConfirmationScheduler(
@@ -226,6 +403,18 @@ func initializeServices(ctx context.Context, cfg *config.Config, repos *Reposito
 		ownerConfirmationService,
 	)
 
+	// Transactional outbox: writes and domain events committed together, then published to each consumer
+	// Consumer order matters: webhooks read the activity log entry written by the first consumer
+	webhookService := services.NewWebhookService(repos.WebhookRepo, repos.ActivityLogRepo)
+	webhookService.SetAllowInsecureURLs(cfg.WebhookAllowInsecureURLs)
+	outboxRelay := services.NewOutboxRelay(repos.OutboxRepo)
+	outboxRelay.Register(services.NewActivityLogConsumer(repos.ActivityLogRepo))
+	outboxRelay.Register(services.NewWebhookConsumer(webhookService))
+
+	// Lead scoring: rule-based 0-100 score recalculated on lead events and conversation messages
+	leadScoringService := services.NewLeadScoringService(repos.LeadRepo, repos.PropertyRepo, repos.ConversationRepo)
+	outboxRelay.Register(services.NewLeadScoringConsumer(leadScoringService))
+
 	// Initialize PropertyService
 	propertyService := services.NewPropertyService(
 		repos.PropertyRepo,
@@ -237,61 +426,306 @@ func initializeServices(ctx context.Context, cfg *config.Config, repos *Reposito
 	)
 	// PROMPT 08: Inject OwnerConfirmationService into PropertyService
 	propertyService.SetOwnerConfirmationService(ownerConfirmationService)
+	propertyService.SetOutboxRelay(outboxRelay)
+	propertyService.SetPlanService(planService)
+
+	// Property document vault (private objects, signed URLs, due-diligence checklist)
+	propertyDocumentService := services.NewPropertyDocumentService(
+		repos.PropertyDocumentRepo,
+		repos.PropertyRepo,
+		repos.ActivityLogRepo,
+		storageService,
+	)
+
+	// Sale authorizations: public/marketplace visibility requires a valid one (tenant setting)
+	emailService := services.NewEmailService()
+	saleAuthorizationService := services.NewSaleAuthorizationService(
+		repos.SaleAuthorizationRepo,
+		repos.PropertyRepo,
+		repos.BrokerRepo,
+		repos.PropertyBrokerRoleRepo,
+		repos.TenantRepo,
+		repos.ActivityLogRepo,
+		emailService,
+		propertyDocumentService,
+	)
+	propertyService.SetSaleAuthorizationService(saleAuthorizationService)
+
+	// View tracking: daily rollups per property/listing/broker
+	listingMetricsService := services.NewListingMetricsService(
+		repos.MetricsRepo,
+		repos.PropertyRepo,
+		repos.ListingRepo,
+	)
+
+	// Monthly owner report: delivered with scheduled confirmations and shown on the owner page
+	ownerReportService := services.NewOwnerReportService(
+		repos.OwnerReportRepo,
+		repos.PropertyRepo,
+		repos.LeadRepo,
+		repos.MarketSnapshotRepo,
+		repos.ActivityLogRepo,
+		listingMetricsService,
+	)
+	ownerConfirmationService.SetOwnerReportService(ownerReportService)
+	monthlyConfirmationScheduler.SetOwnerReportService(ownerReportService)
+
+	leadService := services.NewLeadService(
+		repos.LeadRepo,
+		repos.PropertyRepo,
+		repos.PropertyBrokerRoleRepo,
+		repos.TenantRepo,
+		repos.ActivityLogRepo,
+	)
+	// Leads count as conversions in the listing performance metrics
+	leadService.SetListingMetricsService(listingMetricsService)
+	leadService.SetOutboxRelay(outboxRelay)
+
+	// Public sites: custom domains, {slug}.PLATFORM_DOMAIN subdomains and canonical property URLs
+	domainService := services.NewDomainService(
+		repos.TenantDomainRepo,
+		repos.TenantRepo,
+		repos.ActivityLogRepo,
+		cfg.PlatformDomain,
+		cfg.PublicSiteURL,
+	)
+	leadService.SetDomainService(domainService)
+
+	// WhatsApp deep links: routed broker's number, tenant templates and click-tracking short links
+	whatsAppLinkService := services.NewWhatsAppLinkService(
+		repos.WhatsAppLinkRepo,
+		repos.ActivityLogRepo,
+		cfg.ShortLinkBaseURL,
+	)
+	whatsAppLinkService.SetListingMetricsService(listingMetricsService)
+	leadService.SetBrokerRepository(repos.BrokerRepo)
+	leadService.SetWhatsAppLinkService(whatsAppLinkService)
+	leadService.SetConversationRepository(repos.ConversationRepo)
+
+	// Lead notifications: brokers are e-mailed about their new and assigned leads (outbox consumer)
+	leadNotificationService := services.NewLeadNotificationService(
+		leadService,
+		repos.LeadRepo,
+		repos.BrokerRepo,
+		repos.PropertyRepo,
+		repos.TenantRepo,
+		emailService,
+	)
+	outboxRelay.Register(services.NewNotificationConsumer(leadNotificationService))
+
+	// Unified conversation inbox: replies by e-mail always, by WhatsApp when the Cloud API token is set
+	conversationService := services.NewConversationService(
+		repos.ConversationRepo,
+		repos.LeadRepo,
+		repos.BrokerRepo,
+		repos.TenantRepo,
+		repos.ActivityLogRepo,
+	)
+	conversationService.SetLeadService(leadService)
+	conversationService.SetUsageService(usageService)
+	conversationService.SetLeadScoringService(leadScoringService)
+	conversationService.RegisterProvider(services.NewEmailMessagingProvider(emailService))
+	if cfg.WhatsAppAccessToken != "" {
+		conversationService.RegisterProvider(services.NewWhatsAppCloudMessagingProvider(cfg.WhatsAppAccessToken, cfg.WhatsAppAPIBaseURL))
+	}
+	leadService.SetConversationService(conversationService)
+
+	// Contacts: leads of the same person (phone or e-mail) are interests of one contact
+	activityLogService := services.NewActivityLogService(
+		repos.ActivityLogRepo,
+		repos.TenantRepo,
+	)
+	contactService := services.NewContactService(
+		repos.ContactRepo,
+		repos.LeadRepo,
+		repos.UserRepo,
+		repos.ActivityLogRepo,
+		activityLogService,
+	)
+	leadService.SetContactService(contactService)
+
+	// WhatsApp inbound webhook: messages matched to a property become leads and conversation history
+	whatsAppInboundService := services.NewWhatsAppInboundService(
+		leadService,
+		conversationService,
+		repos.LeadRepo,
+		repos.PropertyRepo,
+		repos.TenantRepo,
+		repos.BrokerRepo,
+		repos.ConversationRepo,
+		repos.WhatsAppLinkRepo,
+		repos.ActivityLogRepo,
+		cfg.WhatsAppVerifyToken,
+		cfg.WhatsAppAppSecret,
+	)
+	whatsAppInboundService.SetEmailService(emailService)
+	if cfg.WhatsAppAppSecret == "" {
+		log.Println("⚠️  WHATSAPP_APP_SECRET not set - WhatsApp webhook deliveries will be rejected")
+	}
+
+	// Portal lead e-mails: notifications of ZAP, VivaReal and OLX become leads with the portal as UTM source
+	emailInboundService := services.NewEmailInboundService(
+		leadService,
+		repos.LeadRepo,
+		repos.PropertyRepo,
+		repos.TenantRepo,
+		repos.InboundEmailRepo,
+		repos.ActivityLogRepo,
+		cfg.InboundEmailSecret,
+	)
+	emailInboundService.SetConversationService(conversationService)
+
+	ownerService := services.NewOwnerService(
+		repos.OwnerRepo,
+		repos.TenantRepo,
+		repos.ActivityLogRepo,
+	)
+
+	// LGPD consent ledger: versioned texts, grants/withdrawals per purpose (contact, marketing, data_sharing)
+	consentService := services.NewConsentService(
+		repos.ConsentRepo,
+		repos.LeadRepo,
+		repos.OwnerRepo,
+		repos.ActivityLogRepo,
+	)
+	leadService.SetConsentService(consentService)
+	ownerService.SetConsentService(consentService)
+
+	// Owner portal: owner price drops alert interested leads (marketing consent required)
+	ownerPortalService := services.NewOwnerPortalService(
+		repos.OwnerPortalTokenRepo,
+		repos.OwnerRepo,
+		repos.PropertyRepo,
+		repos.ListingRepo,
+		repos.LeadRepo,
+		repos.TenantRepo,
+		repos.ActivityLogRepo,
+		emailService,
+		propertyDocumentService,
+	)
+	ownerPortalService.SetConsentService(consentService)
+	marketingAlertService := services.NewMarketingAlertService(
+		repos.LeadRepo,
+		repos.PropertyRepo,
+		repos.TenantRepo,
+		repos.ActivityLogRepo,
+		consentService,
+		emailService,
+	)
+	marketingAlertService.SetUsageService(usageService)
+	marketingAlertService.SetDomainService(domainService)
+	ownerPortalService.SetMarketingAlertService(marketingAlertService)
+	if cfg.WhatsAppAccessToken != "" {
+		ownerPortalService.SetWhatsAppProvider(services.NewWhatsAppCloudMessagingProvider(cfg.WhatsAppAccessToken, cfg.WhatsAppAPIBaseURL))
+	}
+
+	// LGPD data subject requests: intake with identity verification, export, erasure and audit
+	dataSubjectRequestService := services.NewDataSubjectRequestService(
+		repos.DataSubjectRequestRepo,
+		repos.OwnerRepo,
+		repos.LeadRepo,
+		repos.ContactRepo,
+		repos.ConversationRepo,
+		repos.ActivityLogRepo,
+		repos.ScheduledConfirmationRepo,
+		repos.TenantRepo,
+		ownerService,
+		leadService,
+		emailService,
+	)
+	if cfg.WhatsAppAccessToken != "" {
+		dataSubjectRequestService.SetWhatsAppProvider(services.NewWhatsAppCloudMessagingProvider(cfg.WhatsAppAccessToken, cfg.WhatsAppAPIBaseURL))
+	}
+
+	brokerService := services.NewBrokerService(
+		repos.BrokerRepo,
+		repos.TenantRepo,
+		repos.ActivityLogRepo,
+		repos.PropertyBrokerRoleRepo,
+		repos.PropertyRepo,
+		repos.ListingRepo,
+	)
+	brokerService.SetPlanService(planService)
+
+	listingService := services.NewListingService(
+		repos.ListingRepo,
+		repos.PropertyRepo,
+		repos.BrokerRepo,
+		repos.TenantRepo,
+		repos.ActivityLogRepo,
+	)
+	listingService.SetPlanService(planService)
+
+	apiKeyService := services.NewAPIKeyService(repos.APIKeyRepo, repos.ActivityLogRepo)
+	apiKeyService.SetPlanService(planService)
 
 	return &Services{
 		TenantService: services.NewTenantService(
 			repos.TenantRepo,
 			repos.ActivityLogRepo,
 		),
-		BrokerService: services.NewBrokerService(
-			repos.BrokerRepo,
-			repos.TenantRepo,
-			repos.ActivityLogRepo,
-			repos.PropertyBrokerRoleRepo,
-			repos.PropertyRepo,
-			repos.ListingRepo,
-		),
+		BrokerService: brokerService,
 		UserService: services.NewUserService( // PROMPT 10
 			repos.UserRepo,
 			repos.TenantRepo,
 			repos.ActivityLogRepo,
 		),
-		OwnerService: services.NewOwnerService(
-			repos.OwnerRepo,
-			repos.TenantRepo,
-			repos.ActivityLogRepo,
-		),
+		OwnerService:    ownerService,
 		PropertyService: propertyService, // Use the pre-configured instance
-		ListingService: services.NewListingService(
-			repos.ListingRepo,
+		ListingService:  listingService,
+		PropertyBrokerRoleService: services.NewPropertyBrokerRoleService(
+			repos.PropertyBrokerRoleRepo,
 			repos.PropertyRepo,
 			repos.BrokerRepo,
 			repos.TenantRepo,
 			repos.ActivityLogRepo,
 		),
-		PropertyBrokerRoleService: services.NewPropertyBrokerRoleService(
-			repos.PropertyBrokerRoleRepo,
+		LeadService: leadService,
+		ActivityLogService: activityLogService,
+		StorageService:              storageService,
+		PhotoProcessor:              photoProcessor,
+		ImportService:               importService,
+		OwnerConfirmationService:    ownerConfirmationService,    // PROMPT 08
+		MonthlyConfirmationScheduler: monthlyConfirmationScheduler, // Monthly confirmations
+		MarketAnalyticsService: services.NewMarketAnalyticsService(
+			repos.MarketSnapshotRepo,
 			repos.PropertyRepo,
-			repos.BrokerRepo,
 			repos.TenantRepo,
 			repos.ActivityLogRepo,
 		),
-		LeadService: services.NewLeadService(
+		ListingMetricsService: listingMetricsService, // View tracking
+		OwnerReportService:    ownerReportService,    // Monthly owner reports
+		OwnerPortalService:    ownerPortalService,    // Owner portal
+		SaleAuthorizationService: saleAuthorizationService, // Sale authorizations
+		PropertyDocumentService:  propertyDocumentService,  // Document vault
+		DataSubjectRequestService: dataSubjectRequestService, // LGPD data subject requests
+		RetentionService: services.NewRetentionService( // LGPD retention policy
+			repos.RetentionRunRepo,
 			repos.LeadRepo,
-			repos.PropertyRepo,
-			repos.PropertyBrokerRoleRepo,
-			repos.TenantRepo,
 			repos.ActivityLogRepo,
+			repos.TenantRepo,
+			leadService,
 		),
-		ActivityLogService: services.NewActivityLogService(
+		ConsentService: consentService, // LGPD consent ledger
+		AuditChainService: services.NewAuditChainService( // Activity log hash chain
 			repos.ActivityLogRepo,
+			repos.AuditCheckpointRepo,
 			repos.TenantRepo,
+			cfg.AuditCheckpointKey,
 		),
-		StorageService:              storageService,
-		PhotoProcessor:              photoProcessor,
-		ImportService:               importService,
-		OwnerConfirmationService:    ownerConfirmationService,    // PROMPT 08
-		MonthlyConfirmationScheduler: monthlyConfirmationScheduler, // Monthly confirmations
+		WebhookService:         webhookService,         // Outbound webhooks
+		OutboxRelay:            outboxRelay,            // Transactional outbox (domain events)
+		APIKeyService:          apiKeyService,          // Partner API keys
+		PlanService:            planService,            // Subscription plans and quotas
+		UsageService:           usageService,           // Billing usage metering
+		BillingService:         billingService,         // Billing invoices and dunning
+		DomainService:          domainService,          // Tenant custom domains and site URLs
+		WhatsAppLinkService:    whatsAppLinkService,    // WhatsApp click-tracking short links
+		WhatsAppInboundService: whatsAppInboundService, // WhatsApp inbound webhook (conversations → leads)
+		ConversationService:    conversationService,    // Unified lead conversation inbox
+		EmailInboundService:    emailInboundService,    // Portal lead e-mails (ZAP, VivaReal, OLX → leads)
+		ContactService:         contactService,         // Contacts: lead deduplication, merge and timelines
+		LeadScoringService:     leadScoringService,     // Rule-based lead scoring
 	}
 }
 
@@ -312,6 +746,28 @@ type Handlers struct {
 	ImportHandler                *handlers.ImportHandler
 	OwnerConfirmationHandler     *handlers.OwnerConfirmationHandler     // PROMPT 08
 	ScheduledConfirmationHandler *handlers.ScheduledConfirmationHandler // Monthly confirmations
+	MarketAnalyticsHandler       *handlers.MarketAnalyticsHandler       // Market analytics
+	ListingMetricsHandler        *handlers.ListingMetricsHandler        // View tracking
+	OwnerReportHandler           *handlers.OwnerReportHandler           // Monthly owner reports
+	OwnerPortalHandler           *handlers.OwnerPortalHandler           // Owner portal
+	SaleAuthorizationHandler     *handlers.SaleAuthorizationHandler     // Sale authorizations
+	PropertyDocumentHandler      *handlers.PropertyDocumentHandler      // Document vault
+	DataSubjectRequestHandler    *handlers.DataSubjectRequestHandler    // LGPD data subject requests
+	RetentionHandler             *handlers.RetentionHandler             // LGPD retention policy
+	ConsentHandler               *handlers.ConsentHandler               // LGPD consent ledger
+	AuditChainHandler            *handlers.AuditChainHandler            // Activity log hash chain
+	WebhookHandler               *handlers.WebhookHandler               // Outbound webhooks
+	OutboxHandler                *handlers.OutboxHandler                // Transactional outbox (domain events)
+	APIKeyHandler                *handlers.APIKeyHandler                // Partner API keys
+	PlanHandler                  *handlers.PlanHandler                  // Subscription plans and quotas
+	BillingHandler               *handlers.BillingHandler               // Usage metering and invoices
+	DomainHandler                *handlers.DomainHandler                // Tenant custom domains and public site
+	WhatsAppLinkHandler          *handlers.WhatsAppLinkHandler          // WhatsApp short link redirect
+	WhatsAppWebhookHandler       *handlers.WhatsAppWebhookHandler       // WhatsApp inbound webhook
+	ConversationHandler          *handlers.ConversationHandler          // Unified lead conversation inbox
+	InboundEmailHandler          *handlers.InboundEmailHandler          // Portal lead e-mails
+	ContactHandler               *handlers.ContactHandler               // Contacts (deduplicated leads)
+	LeadScoringHandler           *handlers.LeadScoringHandler           // Lead score recalculation
 	// Public handlers (cross-tenant, no tenant_id required)
 	PublicPropertyHandler *handlers.PublicPropertyHandler // Portal agregador property endpoints
 	PublicLeadHandler     *handlers.PublicLeadHandler     // Portal agregador lead endpoints
@@ -341,6 +797,28 @@ func initializeHandlers(authClient *auth.Client, firestoreClient *firestore.Clie
 		ImportHandler:                handlers.NewImportHandler(services.ImportService),
 		OwnerConfirmationHandler:     handlers.NewOwnerConfirmationHandler(services.OwnerConfirmationService),          // PROMPT 08
 		ScheduledConfirmationHandler: handlers.NewScheduledConfirmationHandler(services.MonthlyConfirmationScheduler),  // Monthly confirmations
+		MarketAnalyticsHandler:       handlers.NewMarketAnalyticsHandler(services.MarketAnalyticsService),              // Market analytics
+		ListingMetricsHandler:        handlers.NewListingMetricsHandler(services.ListingMetricsService),                // View tracking
+		OwnerReportHandler:           handlers.NewOwnerReportHandler(services.OwnerReportService),                      // Monthly owner reports
+		OwnerPortalHandler:           handlers.NewOwnerPortalHandler(services.OwnerPortalService),                      // Owner portal
+		SaleAuthorizationHandler:     handlers.NewSaleAuthorizationHandler(services.SaleAuthorizationService),          // Sale authorizations
+		PropertyDocumentHandler:      handlers.NewPropertyDocumentHandler(services.PropertyDocumentService),            // Document vault
+		DataSubjectRequestHandler:    handlers.NewDataSubjectRequestHandler(services.DataSubjectRequestService),        // LGPD data subject requests
+		RetentionHandler:             handlers.NewRetentionHandler(services.RetentionService),                          // LGPD retention policy
+		ConsentHandler:               handlers.NewConsentHandler(services.ConsentService),                              // LGPD consent ledger
+		AuditChainHandler:            handlers.NewAuditChainHandler(services.AuditChainService),                        // Activity log hash chain
+		WebhookHandler:               handlers.NewWebhookHandler(services.WebhookService),                              // Outbound webhooks
+		OutboxHandler:                handlers.NewOutboxHandler(services.OutboxRelay),                                  // Transactional outbox (domain events)
+		APIKeyHandler:                handlers.NewAPIKeyHandler(services.APIKeyService),                                // Partner API keys
+		PlanHandler:                  handlers.NewPlanHandler(services.PlanService),                                    // Subscription plans and quotas
+		BillingHandler:               handlers.NewBillingHandler(services.UsageService, services.BillingService),       // Usage metering and invoices
+		DomainHandler:                handlers.NewDomainHandler(services.DomainService),                                // Tenant custom domains and public site
+		WhatsAppLinkHandler:          handlers.NewWhatsAppLinkHandler(services.WhatsAppLinkService),                    // WhatsApp short link redirect
+		WhatsAppWebhookHandler:       handlers.NewWhatsAppWebhookHandler(services.WhatsAppInboundService),              // WhatsApp inbound webhook
+		ConversationHandler:          handlers.NewConversationHandler(services.ConversationService),                    // Unified lead conversation inbox
+		InboundEmailHandler:          handlers.NewInboundEmailHandler(services.EmailInboundService),                    // Portal lead e-mails
+		ContactHandler:               handlers.NewContactHandler(services.ContactService),                              // Contacts (deduplicated leads)
+		LeadScoringHandler:           handlers.NewLeadScoringHandler(services.LeadScoringService),                      // Lead score recalculation
 		// Public handlers (cross-tenant, no tenant_id required)
 		PublicPropertyHandler: handlers.NewPublicPropertyHandler(services.PropertyService),
 		PublicLeadHandler:     handlers.NewPublicLeadHandler(services.LeadService, services.PropertyService),
@@ -349,8 +827,21 @@ func initializeHandlers(authClient *auth.Client, firestoreClient *firestore.Clie
 }
 
 // setupRouter sets up the Gin router with middleware and routes
-func setupRouter(cfg *config.Config, handlers *Handlers, authMiddleware *middleware.AuthMiddleware, tenantMiddleware *middleware.TenantMiddleware) *gin.Engine {
+func setupRouter(cfg *config.Config, handlers *Handlers, authMiddleware *middleware.AuthMiddleware, apiKeyMiddleware *middleware.APIKeyMiddleware, tenantMiddleware *middleware.TenantMiddleware, hostTenantMiddleware *middleware.HostTenantMiddleware, piiAccessMiddleware *middleware.PIIAccessMiddleware, rateLimitStore middleware.RateLimitStore) *gin.Engine {
 	router := gin.New()
+	startedAt := time.Now()
+
+	// Behind a load balancer, only its X-Forwarded-For is used for the client IP (rate limits, consent IP)
+	if len(cfg.TrustedProxies) > 0 {
+		if err := router.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+			log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+		}
+	}
+
+	// Rate limiters share the configured store (counters are namespaced per limiter)
+	strictRateLimit := middleware.NewRateLimiter(middleware.StrictRateLimiterConfig(), rateLimitStore).Limit()
+	defaultRateLimit := middleware.NewRateLimiter(middleware.DefaultRateLimiterConfig(), rateLimitStore).Limit()
+	planRateLimiter := middleware.NewPlanRateLimiter(rateLimitStore)
 
 	// Global middleware
 	router.Use(middleware.ErrorRecovery())
@@ -377,7 +868,9 @@ func setupRouter(cfg *config.Config, handlers *Handlers, authMiddleware *middlew
 		c.JSON(http.StatusOK, gin.H{
 			"success": true,
 			"metrics": gin.H{
-				"uptime": time.Since(time.Now()).String(),
+				"uptime":                       time.Since(startedAt).String(),
+				"rate_limit_store_errors":      middleware.RateLimitStoreErrors(),
+				"activity_log_append_failures": repositories.ActivityLogAppendFailures(),
 			},
 		})
 	})
@@ -387,9 +880,20 @@ func setupRouter(cfg *config.Config, handlers *Handlers, authMiddleware *middlew
 		handlers.OwnerConfirmationHandler.RegisterPublicRoutes(router)
 	}
 
+	// WhatsApp short links (public, at the root to keep links short)
+	shortLinks := router.Group("/")
+	shortLinks.Use(strictRateLimit)
+	handlers.WhatsAppLinkHandler.RegisterRoutes(shortLinks)
+
 	// API routes
 	api := router.Group("/api/v1")
 
+	// WhatsApp Cloud API inbound webhook (PUBLIC - verify token and payload signature)
+	handlers.WhatsAppWebhookHandler.RegisterRoutes(api)
+
+	// Portal lead e-mails forwarded by the mail provider (PUBLIC - shared secret)
+	handlers.InboundEmailHandler.RegisterWebhookRoutes(api)
+
 	// Authentication routes (PUBLIC - no auth required)
 	auth := api.Group("/auth")
 	{
@@ -411,7 +915,7 @@ func setupRouter(cfg *config.Config, handlers *Handlers, authMiddleware *middlew
 	// Public routes FIRST (no authentication) - frontend público
 	// Apply strict rate limiting to public endpoints to prevent abuse
 	public := api.Group("/:tenant_id")
-	public.Use(middleware.StrictRateLimit())
+	public.Use(strictRateLimit)
 	{
 		// Public property endpoints
 		public.GET("/properties", handlers.PropertyHandler.ListProperties)
@@ -431,12 +935,47 @@ func setupRouter(cfg *config.Config, handlers *Handlers, authMiddleware *middlew
 		// Public images
 		public.GET("/property-images/:property_id", handlers.StorageHandler.ListImages)
 		public.GET("/property-images/:property_id/:image_id", handlers.StorageHandler.GetImageURL)
+
+		// Engagement tracking (views, impressions, WhatsApp clicks)
+		public.POST("/events", handlers.ListingMetricsHandler.TrackEvent)
+
+		// LGPD data subject requests (identity verified by code)
+		handlers.DataSubjectRequestHandler.RegisterPublicRoutes(public)
+		handlers.ConsentHandler.RegisterPublicRoutes(public)
+	}
+
+	// Public site routes by host (tenant resolved from the Host header: {slug}.PLATFORM_DOMAIN or a
+	// verified custom domain) - same endpoints as /:tenant_id for sites served on their own domain
+	site := api.Group("/site")
+	site.Use(strictRateLimit)
+	site.Use(hostTenantMiddleware.ResolveTenant())
+	{
+		handlers.DomainHandler.RegisterSiteRoutes(site)
+
+		site.GET("/properties", handlers.PropertyHandler.ListProperties)
+		site.GET("/properties/:id", handlers.PropertyHandler.GetProperty)
+		site.GET("/properties/slug/:slug", handlers.PropertyHandler.GetPropertyBySlug)
+
+		site.GET("/brokers/:id/public", handlers.BrokerHandler.GetBrokerPublicProfile)
+		site.GET("/brokers/:id/properties", handlers.BrokerHandler.GetBrokerProperties)
+
+		site.POST("/leads", handlers.LeadHandler.CreateLead)
+		site.POST("/properties/:property_id/leads/whatsapp", handlers.LeadHandler.CreateWhatsAppLead)
+		site.POST("/properties/:property_id/leads/form", handlers.LeadHandler.CreateFormLead)
+
+		site.GET("/property-images/:property_id", handlers.StorageHandler.ListImages)
+		site.GET("/property-images/:property_id/:image_id", handlers.StorageHandler.GetImageURL)
+
+		site.POST("/events", handlers.ListingMetricsHandler.TrackEvent)
+
+		handlers.DataSubjectRequestHandler.RegisterPublicRoutes(site)
+		handlers.ConsentHandler.RegisterPublicRoutes(site)
 	}
 
 	// Public routes for portal agregador (NO tenant_id required)
 	// List properties/brokers/leads across ALL tenants
 	publicPortal := api.Group("/public")
-	publicPortal.Use(middleware.StrictRateLimit())
+	publicPortal.Use(strictRateLimit)
 	{
 		// Public property endpoints (cross-tenant)
 		publicPortal.GET("/properties", handlers.PublicPropertyHandler.ListPublicProperties)
@@ -451,15 +990,36 @@ func setupRouter(cfg *config.Config, handlers *Handlers, authMiddleware *middlew
 		// Public broker endpoints (cross-tenant)
 		publicPortal.GET("/brokers/:id/profile", handlers.PublicBrokerHandler.GetPublicBrokerProfile)
 		publicPortal.GET("/brokers/:id/properties", handlers.PublicBrokerHandler.GetPublicBrokerProperties)
+
+		// Engagement tracking (cross-tenant, tenant resolved from property_id)
+		publicPortal.POST("/events", handlers.ListingMetricsHandler.TrackPublicEvent)
+
+		// White-label branding of a tenant (by slug)
+		handlers.TenantHandler.RegisterPublicRoutes(publicPortal)
 	}
 
+	// Owner self-service portal (magic link sessions, NO Firebase auth)
+	ownerPortal := api.Group("/owner-portal/:tenant_id")
+	ownerPortal.Use(strictRateLimit)
+	handlers.OwnerPortalHandler.RegisterRoutes(ownerPortal)
+
 	// Protected routes (require authentication) - admin dashboard
 	protected := api.Group("/admin")
-	protected.Use(authMiddleware.AuthRequired())
-	protected.Use(middleware.RateLimit()) // Less strict for authenticated users
+	protected.Use(apiKeyMiddleware.AuthRequired()) // Firebase ID token or tenant API key
+	protected.Use(defaultRateLimit) // Less strict for authenticated users (per IP)
 	{
+		// Subscription and billing jobs (cron, all tenants) - platform admins only
+		platformJobs := protected.Group("")
+		platformJobs.Use(tenantMiddleware.PlatformAdminRequired())
+		{
+			handlers.PlanHandler.RegisterJobRoutes(platformJobs)
+			handlers.BillingHandler.RegisterJobRoutes(platformJobs)
+		}
+
 		tenantScoped := protected.Group("/:tenant_id")
 		tenantScoped.Use(tenantMiddleware.ValidateTenant())
+		tenantScoped.Use(planRateLimiter.Limit())                 // per tenant and per user, quotas of the subscription plan
+		tenantScoped.Use(piiAccessMiddleware.ResolvePIIAccess()) // masks PII for roles without pii.view
 		{
 			// Admin-only routes
 			handlers.PropertyHandler.RegisterRoutes(tenantScoped)
@@ -469,7 +1029,27 @@ func setupRouter(cfg *config.Config, handlers *Handlers, authMiddleware *middlew
 			handlers.ListingHandler.RegisterRoutes(tenantScoped)
 			handlers.PropertyBrokerRoleHandler.RegisterRoutes(tenantScoped)
 			handlers.LeadHandler.RegisterRoutes(tenantScoped)
+			handlers.ConversationHandler.RegisterRoutes(tenantScoped)
+			handlers.InboundEmailHandler.RegisterRoutes(tenantScoped)
+			handlers.ContactHandler.RegisterRoutes(tenantScoped)
+			handlers.LeadScoringHandler.RegisterRoutes(tenantScoped)
 			handlers.ActivityLogHandler.RegisterRoutes(tenantScoped)
+			handlers.MarketAnalyticsHandler.RegisterRoutes(tenantScoped)
+			handlers.ListingMetricsHandler.RegisterRoutes(tenantScoped)
+			handlers.OwnerReportHandler.RegisterRoutes(tenantScoped)
+			handlers.SaleAuthorizationHandler.RegisterRoutes(tenantScoped)
+			handlers.PropertyDocumentHandler.RegisterRoutes(tenantScoped)
+			handlers.DataSubjectRequestHandler.RegisterRoutes(tenantScoped)
+			handlers.RetentionHandler.RegisterRoutes(tenantScoped)
+			handlers.ConsentHandler.RegisterRoutes(tenantScoped)
+			handlers.AuditChainHandler.RegisterRoutes(tenantScoped)
+			handlers.WebhookHandler.RegisterRoutes(tenantScoped)
+			handlers.OutboxHandler.RegisterRoutes(tenantScoped)
+			handlers.APIKeyHandler.RegisterRoutes(tenantScoped)
+			handlers.PlanHandler.RegisterRoutes(tenantScoped)
+			handlers.BillingHandler.RegisterRoutes(tenantScoped)
+			handlers.TenantHandler.RegisterBrandingRoutes(tenantScoped)
+			handlers.DomainHandler.RegisterRoutes(tenantScoped)
 			if handlers.StorageHandler != nil {
 				handlers.StorageHandler.RegisterRoutes(tenantScoped)
 			}
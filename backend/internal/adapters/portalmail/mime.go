@@ -0,0 +1,302 @@
+package portalmail
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+	"time"
+	"unicode"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+)
+
+// maxMIMEDepth limits nested multiparts and attached messages
+const maxMIMEDepth = 8
+
+// Email is an inbound e-mail reduced to the parts used by the lead intake
+type Email struct {
+	MessageID  string
+	From       string   // address only
+	ReplyTo    string   // address only
+	Recipients []string // To, Cc, Delivered-To, X-Original-To (addresses only)
+	Subject    string
+	Date       time.Time
+	Text       string   // text/plain body, else the text of the text/html body
+	Links      []string // link targets of the text/html body
+}
+
+var headerDecoder = &mime.WordDecoder{CharsetReader: charset.NewReaderLabel}
+
+// ParseEmail parses a raw RFC 5322 message (as uploaded or forwarded by the mail provider)
+// A portal e-mail forwarded as attachment (message/rfc822) replaces the envelope message
+func ParseEmail(raw []byte) (*Email, error) {
+	return parseEmail(raw, 0)
+}
+
+func parseEmail(raw []byte, depth int) (*Email, error) {
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("invalid e-mail: %w", err)
+	}
+
+	email := &Email{
+		MessageID: strings.Trim(strings.TrimSpace(msg.Header.Get("Message-Id")), "<>"),
+		From:      firstAddress(msg.Header.Get("From")),
+		ReplyTo:   firstAddress(msg.Header.Get("Reply-To")),
+		Subject:   decodeHeader(msg.Header.Get("Subject")),
+	}
+	if date, err := msg.Header.Date(); err == nil {
+		email.Date = date
+	}
+	for _, key := range []string{"To", "Cc", "Delivered-To", "X-Original-To", "X-Forwarded-To"} {
+		for _, value := range msg.Header[key] {
+			email.Recipients = append(email.Recipients, addresses(value)...)
+		}
+	}
+
+	body := &bodyParts{}
+	if err := body.walk(msg.Header, msg.Body, depth); err != nil {
+		return nil, err
+	}
+
+	if body.attached != nil {
+		if email.MessageID != "" && body.attached.MessageID == "" {
+			body.attached.MessageID = email.MessageID
+		}
+		body.attached.Recipients = append(body.attached.Recipients, email.Recipients...)
+		return body.attached, nil
+	}
+
+	email.Text = body.plain
+	text, links := htmlToText(body.html)
+	if strings.TrimSpace(email.Text) == "" {
+		email.Text = text
+	}
+	email.Links = links
+	return email, nil
+}
+
+// partHeader is the subset of MIME headers used to decode a part
+type partHeader interface {
+	Get(key string) string
+}
+
+// bodyParts collects the first text/plain and text/html parts and the first attached message
+type bodyParts struct {
+	plain    string
+	html     string
+	attached *Email
+}
+
+func (b *bodyParts) walk(header partHeader, body io.Reader, depth int) error {
+	if depth > maxMIMEDepth {
+		return nil
+	}
+
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		mediaType, params = "text/plain", map[string]string{}
+	}
+
+	switch {
+	case strings.HasPrefix(mediaType, "multipart/"):
+		reader := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := reader.NextRawPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("invalid multipart body: %w", err)
+			}
+			if err := b.walk(part.Header, part, depth+1); err != nil {
+				return err
+			}
+		}
+
+	case mediaType == "message/rfc822":
+		if b.attached != nil {
+			return nil
+		}
+		raw, err := io.ReadAll(decodeTransfer(header.Get("Content-Transfer-Encoding"), body))
+		if err != nil {
+			return fmt.Errorf("failed to read attached message: %w", err)
+		}
+		if attached, err := parseEmail(raw, depth+1); err == nil {
+			b.attached = attached
+		}
+		return nil
+
+	case mediaType == "text/plain" || mediaType == "text/html":
+		if strings.HasPrefix(strings.ToLower(header.Get("Content-Disposition")), "attachment") {
+			return nil
+		}
+		if (mediaType == "text/plain" && b.plain != "") || (mediaType == "text/html" && b.html != "") {
+			return nil
+		}
+
+		reader := decodeTransfer(header.Get("Content-Transfer-Encoding"), body)
+		if cs := params["charset"]; cs != "" {
+			if converted, err := charset.NewReaderLabel(cs, reader); err == nil {
+				reader = converted
+			}
+		}
+		content, err := io.ReadAll(reader)
+		if err != nil {
+			return fmt.Errorf("failed to decode %s part: %w", mediaType, err)
+		}
+
+		if mediaType == "text/plain" {
+			b.plain = string(content)
+		} else {
+			b.html = string(content)
+		}
+	}
+
+	return nil
+}
+
+// decodeTransfer decodes the Content-Transfer-Encoding of a part
+func decodeTransfer(encoding string, body io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "quoted-printable":
+		return quotedprintable.NewReader(body)
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, &newlineSkipper{r: body})
+	}
+	return body
+}
+
+// newlineSkipper drops the line breaks of base64 bodies
+type newlineSkipper struct {
+	r io.Reader
+}
+
+func (n *newlineSkipper) Read(p []byte) (int, error) {
+	count, err := n.r.Read(p)
+	kept := 0
+	for _, c := range p[:count] {
+		if c != '\r' && c != '\n' && c != ' ' && c != '\t' {
+			p[kept] = c
+			kept++
+		}
+	}
+	return kept, err
+}
+
+// decodeHeader decodes RFC 2047 encoded words ("=?UTF-8?Q?...?=")
+func decodeHeader(value string) string {
+	decoded, err := headerDecoder.DecodeHeader(value)
+	if err != nil {
+		return value
+	}
+	return decoded
+}
+
+// addresses returns the lowercase addresses of an address list header
+func addresses(value string) []string {
+	parser := mail.AddressParser{WordDecoder: headerDecoder}
+	list, err := parser.ParseList(value)
+	if err != nil {
+		return nil
+	}
+
+	result := make([]string, 0, len(list))
+	for _, address := range list {
+		result = append(result, strings.ToLower(address.Address))
+	}
+	return result
+}
+
+// firstAddress returns the first address of a header ("" when none)
+func firstAddress(value string) string {
+	if list := addresses(value); len(list) > 0 {
+		return list[0]
+	}
+	return ""
+}
+
+// PlusTag returns the tag of a plus-addressed recipient ("leads+imob-silva@..." → "imob-silva")
+func PlusTag(address string) string {
+	local, _, found := strings.Cut(address, "@")
+	if !found {
+		return ""
+	}
+	_, tag, found := strings.Cut(local, "+")
+	if !found {
+		return ""
+	}
+	return strings.ToLower(tag)
+}
+
+// blockElements end a line when converting HTML to text (table cells included: portals lay out
+// "label | value" in cells)
+var blockElements = map[string]bool{
+	"br": true, "p": true, "div": true, "tr": true, "td": true, "th": true, "li": true, "table": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+}
+
+// htmlToText converts an HTML e-mail body to text, one block per line, and returns the link targets
+// (the listing URL of portal e-mails is usually only in an href)
+func htmlToText(body string) (string, []string) {
+	var text strings.Builder
+	var links []string
+	tokenizer := html.NewTokenizer(strings.NewReader(body))
+	skip := 0
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return text.String(), links
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := tokenizer.TagName()
+			switch tag := string(name); {
+			case tag == "style" || tag == "script" || tag == "head":
+				skip++
+			case tag == "a":
+				for hasAttr {
+					var key, value []byte
+					key, value, hasAttr = tokenizer.TagAttr()
+					if string(key) == "href" {
+						links = append(links, string(value))
+					}
+				}
+			case blockElements[tag]:
+				text.WriteString("\n")
+			}
+		case html.EndTagToken:
+			name, _ := tokenizer.TagName()
+			switch tag := string(name); {
+			case tag == "style" || tag == "script" || tag == "head":
+				if skip > 0 {
+					skip--
+				}
+			case blockElements[tag]:
+				text.WriteString("\n")
+			}
+		case html.TextToken:
+			if skip > 0 {
+				continue
+			}
+			raw := string(tokenizer.Text())
+			words := strings.Fields(raw)
+			if len(words) == 0 {
+				continue
+			}
+			// Keep the space between inline elements ("<b>Nome:</b> Ana")
+			if strings.TrimLeftFunc(raw, unicode.IsSpace) != raw {
+				text.WriteString(" ")
+			}
+			text.WriteString(strings.Join(words, " "))
+			if strings.TrimRightFunc(raw, unicode.IsSpace) != raw {
+				text.WriteString(" ")
+			}
+		}
+	}
+}
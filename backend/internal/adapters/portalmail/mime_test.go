@@ -0,0 +1,33 @@
+package portalmail
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEmailForwardedAsAttachment(t *testing.T) {
+	email := loadFixture(t, "forwarded_olx.eml")
+
+	// The attached portal e-mail replaces the envelope, keeping the envelope recipients
+	assert.Equal(t, "mensagens@olx.com.br", email.From)
+	assert.Equal(t, "olx-0001@olx.com.br", email.MessageID)
+	assert.Contains(t, email.Recipients, "leads+imob-sul@mail.imob.com.br")
+
+	lead, err := Parse(email)
+	require.NoError(t, err)
+	assert.Equal(t, PortalOLX, lead.Portal)
+	assert.Equal(t, "Sérgio Araújo", lead.Name)
+}
+
+func TestHTMLToText(t *testing.T) {
+	text, links := htmlToText(`<html><head><style>p { color: red; }</style></head><body>` +
+		`<p><b>Nome:</b> Ana</p><table><tr><td>Telefone:</td><td>(41) 3333-2222</td></tr></table>` +
+		`<a href="https://www.olx.com.br/imoveis/casa-1187654321">Ver</a></body></html>`)
+
+	assert.Contains(t, text, "Nome: Ana")
+	assert.Contains(t, text, "Telefone:\n")
+	assert.NotContains(t, text, "color")
+	assert.Equal(t, []string{"https://www.olx.com.br/imoveis/casa-1187654321"}, links)
+}
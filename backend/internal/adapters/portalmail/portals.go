@@ -0,0 +1,360 @@
+package portalmail
+
+import (
+	"errors"
+	"net/mail"
+	"regexp"
+	"strings"
+)
+
+// Portal identifies the listing portal that sent a lead notification
+// The value is the lead UTMSource and the property external_source of the portal listing IDs
+type Portal string
+
+const (
+	PortalZap      Portal = "zap"
+	PortalVivaReal Portal = "vivareal"
+	PortalOLX      Portal = "olx"
+)
+
+var (
+	// ErrUnknownPortal is returned for e-mails that are not lead notifications of a supported portal
+	ErrUnknownPortal = errors.New("e-mail is not a lead notification of a supported portal (ZAP, VivaReal, OLX)")
+
+	// ErrNoContact is returned when the notification has neither the e-mail nor the phone of the lead
+	ErrNoContact = errors.New("lead notification has no e-mail or phone")
+)
+
+// maxMessageLines limits the lines read for the message of the lead
+const maxMessageLines = 30
+
+// Lead is the contact extracted from a portal lead notification
+type Lead struct {
+	Portal    Portal
+	Name      string
+	Email     string
+	Phone     string
+	Message   string
+	ListingID string // listing ID on the portal
+	Reference string // advertiser code of the property (ex: AP00335)
+}
+
+// field is a lead attribute found after a label ("Nome: Ana")
+type field int
+
+const (
+	fieldName field = iota
+	fieldEmail
+	fieldPhone
+	fieldMessage
+	fieldListingID
+	fieldReference
+)
+
+// portalFormat describes the notification e-mails of a portal
+type portalFormat struct {
+	portal      Portal
+	displayName string
+	domains     []string // sender domains
+	keywords    []string // names that identify a forwarded notification
+	labels      map[field][]string
+	listingURL  *regexp.Regexp // listing ID in the listing link
+}
+
+// commonLabels are the labels shared by the portals (folded: lowercase, no accents)
+var commonLabels = map[field][]string{
+	fieldName:      {"nome", "nome do interessado", "nome do contato", "interessado", "cliente"},
+	fieldEmail:     {"e-mail", "email", "e-mail do interessado", "email do interessado"},
+	fieldPhone:     {"telefone", "tel", "celular", "telefone do interessado", "whatsapp", "fone"},
+	fieldMessage:   {"mensagem", "mensagem do interessado", "comentario", "comentarios"},
+	fieldListingID: {"codigo do anuncio", "cod. do anuncio", "id do anuncio", "numero do anuncio"},
+	fieldReference: {"codigo do imovel", "cod. do imovel", "referencia", "ref", "ref.", "codigo do anunciante", "seu codigo", "codigo de referencia"},
+}
+
+var portalFormats = []portalFormat{
+	{
+		portal:      PortalZap,
+		displayName: "ZAP Imóveis",
+		domains:     []string{"zapimoveis.com.br", "zap.com.br"},
+		keywords:    []string{"zapimoveis", "zap imoveis"},
+		labels: map[field][]string{
+			fieldListingID: {"codigo zap", "id zap", "codigo do anuncio no zap"},
+		},
+		listingURL: regexp.MustCompile(`zapimoveis\.com\.br/imovel/[^\s"'<>]*?-id-(\d+)`),
+	},
+	{
+		portal:      PortalVivaReal,
+		displayName: "VivaReal",
+		domains:     []string{"vivareal.com.br", "vivareal.com"},
+		keywords:    []string{"vivareal", "viva real"},
+		labels: map[field][]string{
+			fieldListingID: {"codigo vivareal", "codigo viva real", "id vivareal", "codigo do anuncio no vivareal"},
+		},
+		listingURL: regexp.MustCompile(`vivareal\.com(?:\.br)?/imovel/[^\s"'<>]*?-id-(\d+)`),
+	},
+	{
+		portal:      PortalOLX,
+		displayName: "OLX",
+		domains:     []string{"olx.com.br"},
+		keywords:    []string{"olx"},
+		labels: map[field][]string{
+			fieldName:      {"nome do comprador", "comprador"},
+			fieldListingID: {"anuncio", "anuncio n", "anuncio no", "codigo olx", "id"},
+		},
+		listingURL: regexp.MustCompile(`olx\.com\.br/[^\s"'<>]*?-(\d{8,})\b`),
+	},
+}
+
+// Grupo ZAP sends the notifications of ZAP and VivaReal from the same domain: the body tells them apart
+var sharedSenderDomains = []string{"grupozap.com", "grupozap.com.br", "olxbrasil.com"}
+
+var (
+	phonePattern = regexp.MustCompile(`\+?\(?\d[\d\s().-]{7,}\d`)
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	codePattern  = regexp.MustCompile(`[A-Za-z0-9][A-Za-z0-9-]*`)
+)
+
+// messageStopWords end the message of the lead (portal footers)
+var messageStopWords = []string{"responda", "para responder", "atenciosamente", "este e-mail", "este email", "ver anuncio", "clique", "acesse"}
+
+// DisplayName returns the name of the portal shown to brokers
+func (p Portal) DisplayName() string {
+	for _, format := range portalFormats {
+		if format.portal == p {
+			return format.displayName
+		}
+	}
+	return string(p)
+}
+
+// DetectPortal identifies the portal of a lead notification, sent by the portal or forwarded by a broker
+func DetectPortal(email *Email) (Portal, bool) {
+	if format := detectFormat(email); format != nil {
+		return format.portal, true
+	}
+	return "", false
+}
+
+func detectFormat(email *Email) *portalFormat {
+	domain := addressDomain(email.From)
+	for i := range portalFormats {
+		for _, candidate := range portalFormats[i].domains {
+			if domain == candidate || strings.HasSuffix(domain, "."+candidate) {
+				return &portalFormats[i]
+			}
+		}
+	}
+
+	// Shared sender or forwarded notification: the first portal named in the e-mail
+	haystack := fold(email.Subject + "\n" + email.Text + "\n" + strings.Join(email.Links, "\n"))
+	var found *portalFormat
+	first := -1
+	for i := range portalFormats {
+		for _, keyword := range append(portalFormats[i].domains, portalFormats[i].keywords...) {
+			if index := strings.Index(haystack, keyword); index >= 0 && (first < 0 || index < first) {
+				first = index
+				found = &portalFormats[i]
+			}
+		}
+	}
+	return found
+}
+
+// Parse extracts the lead of a portal notification e-mail
+func Parse(email *Email) (*Lead, error) {
+	format := detectFormat(email)
+	if format == nil {
+		return nil, ErrUnknownPortal
+	}
+
+	values := extractFields(email.Text, format.labelsFor())
+	lead := &Lead{
+		Portal:    format.portal,
+		Name:      values[fieldName],
+		Message:   values[fieldMessage],
+		ListingID: firstCode(values[fieldListingID]),
+		Reference: strings.ToUpper(firstCode(values[fieldReference])),
+	}
+
+	if match := emailPattern.FindString(values[fieldEmail]); match != "" {
+		lead.Email = strings.ToLower(match)
+	} else if email.ReplyTo != "" && isPortalSender(email.From) && !isPortalSender(email.ReplyTo) && !isNoReply(email.ReplyTo) {
+		// Portals set the lead as Reply-To of the notification
+		lead.Email = email.ReplyTo
+	}
+
+	if match := phonePattern.FindString(values[fieldPhone]); match != "" {
+		lead.Phone = strings.TrimSpace(match)
+	}
+
+	if lead.ListingID == "" {
+		links := email.Text + "\n" + strings.Join(email.Links, "\n")
+		if match := format.listingURL.FindStringSubmatch(links); match != nil {
+			lead.ListingID = match[1]
+		}
+	}
+
+	if lead.Email == "" && lead.Phone == "" {
+		return nil, ErrNoContact
+	}
+	if lead.Name == "" {
+		lead.Name = "Lead via " + format.displayName
+	}
+
+	return lead, nil
+}
+
+// labelsFor returns the common labels followed by the portal specific ones
+func (f *portalFormat) labelsFor() map[field][]string {
+	labels := make(map[field][]string, len(commonLabels))
+	for key, values := range commonLabels {
+		labels[key] = append(append([]string{}, values...), f.labels[key]...)
+	}
+	return labels
+}
+
+// extractFields reads "label: value" lines; the value may be on the next line (HTML tables) and the
+// message spans the following lines until the next label
+func extractFields(text string, labels map[field][]string) map[field]string {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	for i := range lines {
+		lines[i] = cleanLine(lines[i])
+	}
+
+	values := make(map[field]string)
+	for i := 0; i < len(lines); i++ {
+		key, value, ok := matchLabel(lines[i], labels)
+		if !ok || values[key] != "" {
+			continue
+		}
+
+		if key == fieldMessage {
+			var message []string
+			if value != "" {
+				message = append(message, value)
+			}
+			for j := i + 1; j < len(lines) && j <= i+maxMessageLines; j++ {
+				if _, _, isLabel := matchLabel(lines[j], labels); isLabel || isStopLine(lines[j]) {
+					break
+				}
+				message = append(message, lines[j])
+			}
+			values[key] = strings.TrimSpace(collapseBlankLines(strings.Join(message, "\n")))
+			continue
+		}
+
+		if value == "" {
+			for j := i + 1; j < len(lines); j++ {
+				if lines[j] == "" {
+					continue
+				}
+				if _, _, isLabel := matchLabel(lines[j], labels); !isLabel {
+					value = lines[j]
+				}
+				break
+			}
+		}
+		values[key] = value
+	}
+	return values
+}
+
+// matchLabel returns the field and the value of a "label: value" line
+func matchLabel(line string, labels map[field][]string) (field, string, bool) {
+	folded := fold(line)
+	bestKey, bestLength := field(-1), 0
+	for key, candidates := range labels {
+		for _, label := range candidates {
+			if len(label) <= bestLength || !strings.HasPrefix(folded, label) {
+				continue
+			}
+			if strings.HasPrefix(strings.TrimLeft(folded[len(label):], " "), ":") {
+				bestKey, bestLength = key, len(label)
+			}
+		}
+	}
+	if bestLength == 0 {
+		return 0, "", false
+	}
+
+	// fold changes the byte length of accented letters: cut the original line at the separator
+	_, value, _ := strings.Cut(line, ":")
+	return bestKey, strings.TrimSpace(value), true
+}
+
+// cleanLine trims a line and the quote and bullet marks of forwarded notifications
+func cleanLine(line string) string {
+	line = strings.TrimSpace(line)
+	for {
+		trimmed := strings.TrimSpace(strings.TrimLeft(line, ">*•-|"))
+		if trimmed == line {
+			break
+		}
+		line = trimmed
+	}
+	return strings.Join(strings.Fields(line), " ")
+}
+
+func isStopLine(line string) bool {
+	folded := fold(line)
+	for _, stop := range messageStopWords {
+		if strings.HasPrefix(folded, stop) {
+			return true
+		}
+	}
+	return false
+}
+
+func collapseBlankLines(text string) string {
+	for strings.Contains(text, "\n\n\n") {
+		text = strings.ReplaceAll(text, "\n\n\n", "\n\n")
+	}
+	return text
+}
+
+// firstCode returns the first code-like word of a value ("AP00335 (seu código)" → "AP00335")
+func firstCode(value string) string {
+	return codePattern.FindString(strings.TrimLeft(value, "#nº° "))
+}
+
+func isPortalSender(address string) bool {
+	domain := addressDomain(address)
+	for _, format := range portalFormats {
+		for _, candidate := range format.domains {
+			if domain == candidate || strings.HasSuffix(domain, "."+candidate) {
+				return true
+			}
+		}
+	}
+	for _, candidate := range sharedSenderDomains {
+		if domain == candidate || strings.HasSuffix(domain, "."+candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+func isNoReply(address string) bool {
+	local, _, _ := strings.Cut(address, "@")
+	local = strings.ReplaceAll(strings.ReplaceAll(local, "-", ""), "_", "")
+	return strings.HasPrefix(local, "noreply") || strings.HasPrefix(local, "naoresponda")
+}
+
+func addressDomain(address string) string {
+	if parsed, err := mail.ParseAddress(address); err == nil {
+		address = parsed.Address
+	}
+	_, domain, _ := strings.Cut(strings.ToLower(address), "@")
+	return domain
+}
+
+// accentFolding removes the accents of Portuguese labels
+var accentFolding = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "é", "e", "ê", "e", "í", "i",
+	"ó", "o", "ô", "o", "õ", "o", "ú", "u", "ü", "u", "ç", "c", "º", "o",
+)
+
+// fold lowercases and removes accents (label matching)
+func fold(text string) string {
+	return accentFolding.Replace(strings.ToLower(text))
+}
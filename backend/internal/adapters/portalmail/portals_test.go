@@ -0,0 +1,126 @@
+package portalmail
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// loadFixture parses a raw e-mail of testdata/
+func loadFixture(t *testing.T, name string) *Email {
+	t.Helper()
+
+	raw, err := os.ReadFile(filepath.Join("testdata", name))
+	require.NoError(t, err)
+
+	email, err := ParseEmail(raw)
+	require.NoError(t, err)
+	return email
+}
+
+func TestParsePortalFixtures(t *testing.T) {
+	tests := []struct {
+		fixture string
+		subject string
+		lead    Lead
+	}{
+		{
+			// multipart/alternative, quoted-printable UTF-8; lead e-mail only in Reply-To, listing ID only in the HTML link
+			fixture: "zap_quoted_printable.eml",
+			subject: "Novo contato no seu anúncio",
+			lead: Lead{
+				Portal:    PortalZap,
+				Name:      "João Conceição",
+				Email:     "joao.conceicao@example.com",
+				Phone:     "(11) 98765-4321",
+				Message:   "Olá, gostaria de agendar uma visita ao apartamento.\nAinda está disponível?",
+				ListingID: "2598761234",
+				Reference: "AP00335",
+			},
+		},
+		{
+			// base64 UTF-8 from the shared Grupo ZAP sender: the body names the portal
+			fixture: "vivareal_base64.eml",
+			subject: "Nova mensagem no VivaReal",
+			lead: Lead{
+				Portal:    PortalVivaReal,
+				Name:      "Márcia Simões",
+				Email:     "marcia.simoes@example.com",
+				Phone:     "+55 41 99876-5432",
+				Message:   "Tenho interesse na casa, aceita financiamento?",
+				ListingID: "2601234567",
+				Reference: "CA0042",
+			},
+		},
+		{
+			// quoted-printable ISO-8859-1 body and header
+			fixture: "olx_iso_8859_1.eml",
+			subject: "Nova mensagem no seu anúncio",
+			lead: Lead{
+				Portal:    PortalOLX,
+				Name:      "Sérgio Araújo",
+				Phone:     "(21) 3456-7890",
+				Message:   "Qual o valor do condomínio?",
+				ListingID: "1187654321",
+			},
+		},
+		{
+			// text/html only, "label | value" table cells
+			fixture: "zap_html_only.eml",
+			subject: "Novo contato",
+			lead: Lead{
+				Portal:    PortalZap,
+				Name:      "Ana Beatriz",
+				Email:     "ana.beatriz@example.com",
+				Phone:     "(41) 3333-2222",
+				Message:   "Gostaria de mais fotos da cozinha.",
+				ListingID: "2587654321",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			email := loadFixture(t, tt.fixture)
+			assert.Equal(t, tt.subject, email.Subject)
+			assert.Contains(t, email.Recipients, "leads+imob-sul@mail.imob.com.br")
+
+			portal, ok := DetectPortal(email)
+			require.True(t, ok)
+			assert.Equal(t, tt.lead.Portal, portal)
+
+			lead, err := Parse(email)
+			require.NoError(t, err)
+			assert.Equal(t, tt.lead, *lead)
+		})
+	}
+}
+
+func TestParseUnknownSender(t *testing.T) {
+	email := loadFixture(t, "unknown_sender.eml")
+
+	_, ok := DetectPortal(email)
+	assert.False(t, ok)
+
+	_, err := Parse(email)
+	assert.ErrorIs(t, err, ErrUnknownPortal)
+}
+
+func TestParseNoContact(t *testing.T) {
+	email := &Email{
+		From: "noreply@zapimoveis.com.br",
+		Text: "Nome: Ana\nMensagem: Tenho interesse",
+	}
+
+	_, err := Parse(email)
+	assert.ErrorIs(t, err, ErrNoContact)
+}
+
+func TestPlusTag(t *testing.T) {
+	assert.Equal(t, "imob-sul", PlusTag("leads+Imob-Sul@mail.imob.com.br"))
+	assert.Equal(t, "", PlusTag("leads@mail.imob.com.br"))
+	assert.Equal(t, "", PlusTag("invalid"))
+}
@@ -25,8 +25,51 @@ type Config struct {
 	// CORS configuration
 	AllowedOrigins []string
 
+	// IPs/CIDRs of the load balancers allowed to set X-Forwarded-* headers (empty = none:
+	// the site host is read from Host and gin keeps its default client IP resolution)
+	TrustedProxies []string
+
 	// Logging configuration
 	LogLevel string
+
+	// PII encryption (field-level, see internal/pii)
+	// Local JSON key file for development; empty disables encryption
+	PIIKeyFile string
+
+	// Audit chain checkpoints (HMAC-SHA256 signing secret, see models.AuditCheckpoint)
+	// Empty disables checkpoint creation and signature verification
+	AuditCheckpointKey string
+
+	// Rate limit counters: "memory" (per process), "firestore" (shared by every replica)
+	// or "fake" (in-memory fake of the shared counter backend, for local development)
+	RateLimitStore string
+
+	// Payment gateway of the tenant invoices (card/PIX charges)
+	// Only "fake" (in-memory, local development) is built in; production gateways implement services.PaymentGateway
+	PaymentGateway string
+
+	// Public sites: tenants are served at {slug}.{PlatformDomain} (empty disables subdomains) and at their
+	// verified custom domains; PublicSiteURL is the fallback base URL of tenants without a domain
+	PlatformDomain string
+	PublicSiteURL  string
+
+	// Base URL of the WhatsApp click-tracking short links ({ShortLinkBaseURL}/w/{code}, served by this API)
+	ShortLinkBaseURL string
+
+	// WhatsApp Cloud API inbound webhook: token of the subscription verification and app secret that
+	// signs the deliveries (X-Hub-Signature-256; empty rejects every delivery)
+	WhatsAppVerifyToken string
+	WhatsAppAppSecret   string
+
+	// WhatsApp Cloud API replies from the inbox: access token (empty disables WhatsApp replies) and Graph API base URL
+	WhatsAppAccessToken string
+	WhatsAppAPIBaseURL  string
+
+	// Inbound portal lead e-mails: shared secret of the mail provider webhook (empty disables the webhook)
+	InboundEmailSecret string
+
+	// Outbound webhooks: allow plain HTTP and private/loopback addresses (local development receivers only)
+	WebhookAllowInsecureURLs bool
 }
 
 // Load loads configuration from environment variables
@@ -51,8 +94,44 @@ func Load() (*Config, error) {
 		// CORS
 		AllowedOrigins: parseCSV(getEnv("ALLOWED_ORIGINS", "http://localhost:3000,http://localhost:3001,http://localhost:3002")),
 
+		// Reverse proxies
+		TrustedProxies: parseCSV(getEnv("TRUSTED_PROXIES", "")),
+
 		// Logging
 		LogLevel: getEnv("LOG_LEVEL", "info"),
+
+		// PII encryption
+		PIIKeyFile: getEnv("PII_KEY_FILE", ""),
+
+		// Audit chain
+		AuditCheckpointKey: getEnv("AUDIT_CHECKPOINT_KEY", ""),
+
+		// Rate limiting
+		RateLimitStore: getEnv("RATE_LIMIT_STORE", "memory"),
+
+		// Billing
+		PaymentGateway: getEnv("PAYMENT_GATEWAY", "fake"),
+
+		// Public sites
+		PlatformDomain: getEnv("PLATFORM_DOMAIN", ""),
+		PublicSiteURL:  getEnv("PUBLIC_SITE_URL", "http://localhost:3001"),
+
+		// WhatsApp short links
+		ShortLinkBaseURL: getEnv("SHORT_LINK_BASE_URL", "http://localhost:8080"),
+
+		// WhatsApp inbound webhook
+		WhatsAppVerifyToken: getEnv("WHATSAPP_VERIFY_TOKEN", ""),
+		WhatsAppAppSecret:   getEnv("WHATSAPP_APP_SECRET", ""),
+
+		// WhatsApp replies
+		WhatsAppAccessToken: getEnv("WHATSAPP_ACCESS_TOKEN", ""),
+		WhatsAppAPIBaseURL:  getEnv("WHATSAPP_API_BASE_URL", "https://graph.facebook.com/v21.0"),
+
+		// Inbound portal lead e-mails
+		InboundEmailSecret: getEnv("INBOUND_EMAIL_SECRET", ""),
+
+		// Outbound webhooks
+		WebhookAllowInsecureURLs: getEnv("WEBHOOK_ALLOW_INSECURE_URLS", "false") == "true",
 	}
 
 	// Validate required configuration
@@ -0,0 +1,244 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyHandler handles the management of partner API keys
+type APIKeyHandler struct {
+	apiKeyService *services.APIKeyService
+}
+
+// NewAPIKeyHandler creates a new API key handler
+func NewAPIKeyHandler(apiKeyService *services.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+	}
+}
+
+// RegisterRoutes registers API key routes (tenant-scoped, admin; not reachable with API keys)
+func (h *APIKeyHandler) RegisterRoutes(router *gin.RouterGroup) {
+	apiKeys := router.Group("/api-keys")
+	{
+		apiKeys.GET("/scopes", h.ListScopes)
+		apiKeys.GET("", h.ListKeys)
+		apiKeys.POST("", h.CreateKey)
+		apiKeys.GET("/:key_id", h.GetKey)
+		apiKeys.PUT("/:key_id", h.UpdateKey)
+		apiKeys.POST("/:key_id/revoke", h.RevokeKey)
+	}
+}
+
+// CreateAPIKeyRequest represents the request body for creating an API key
+type CreateAPIKeyRequest struct {
+	Name               string     `json:"name" binding:"required"`
+	Scopes             []string   `json:"scopes" binding:"required"`
+	ExpiresAt          *time.Time `json:"expires_at,omitempty"`
+	RateLimitPerMinute int        `json:"rate_limit_per_minute,omitempty"` // default: 60
+}
+
+// UpdateAPIKeyRequest represents the request body for updating an API key
+type UpdateAPIKeyRequest struct {
+	Name               *string    `json:"name,omitempty"`
+	Scopes             []string   `json:"scopes,omitempty"`
+	ExpiresAt          *time.Time `json:"expires_at,omitempty"`
+	RateLimitPerMinute *int       `json:"rate_limit_per_minute,omitempty"`
+}
+
+// ListScopes lists the scopes that can be granted to API keys
+// @Summary List API key scopes
+// @Tags api-keys
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Success 200 {array} string
+// @Router /api/v1/admin/{tenant_id}/api-keys/scopes [get]
+func (h *APIKeyHandler) ListScopes(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    models.APIKeyScopes,
+		"count":   len(models.APIKeyScopes),
+	})
+}
+
+// CreateKey issues an API key
+// The key is only returned in this response (only its hash is stored)
+// @Summary Create API key
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param body body CreateAPIKeyRequest true "API key"
+// @Success 201 {object} models.APIKey
+// @Router /api/v1/admin/{tenant_id}/api-keys [post]
+func (h *APIKeyHandler) CreateKey(c *gin.Context) {
+	var req CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	actorID, exists := c.Get("user_id")
+	if !exists {
+		actorID = "system"
+	}
+
+	key, token, err := h.apiKeyService.CreateKey(c.Request.Context(), services.CreateAPIKeyInput{
+		TenantID:           c.Param("tenant_id"),
+		Name:               req.Name,
+		Scopes:             req.Scopes,
+		ExpiresAt:          req.ExpiresAt,
+		RateLimitPerMinute: req.RateLimitPerMinute,
+		CreatedBy:          actorID.(string),
+	})
+	if err != nil {
+		respondAPIKeyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    key,
+		"key":     token,
+	})
+}
+
+// ListKeys lists the API keys of the tenant
+// @Summary List API keys
+// @Tags api-keys
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Success 200 {array} models.APIKey
+// @Router /api/v1/admin/{tenant_id}/api-keys [get]
+func (h *APIKeyHandler) ListKeys(c *gin.Context) {
+	keys, err := h.apiKeyService.ListKeys(c.Request.Context(), c.Param("tenant_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    keys,
+		"count":   len(keys),
+	})
+}
+
+// GetKey retrieves an API key (prefix, scopes and usage; never the key itself)
+// @Summary Get API key
+// @Tags api-keys
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param key_id path string true "API key ID"
+// @Success 200 {object} models.APIKey
+// @Router /api/v1/admin/{tenant_id}/api-keys/{key_id} [get]
+func (h *APIKeyHandler) GetKey(c *gin.Context) {
+	key, err := h.apiKeyService.GetKey(c.Request.Context(), c.Param("tenant_id"), c.Param("key_id"))
+	if err != nil {
+		respondAPIKeyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    key,
+	})
+}
+
+// UpdateKey updates an API key (name, scopes, expiry, rate limit)
+// @Summary Update API key
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param key_id path string true "API key ID"
+// @Param body body UpdateAPIKeyRequest true "Fields to update"
+// @Success 200 {object} models.APIKey
+// @Router /api/v1/admin/{tenant_id}/api-keys/{key_id} [put]
+func (h *APIKeyHandler) UpdateKey(c *gin.Context) {
+	var req UpdateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	actorID, exists := c.Get("user_id")
+	if !exists {
+		actorID = "system"
+	}
+
+	key, err := h.apiKeyService.UpdateKey(c.Request.Context(), c.Param("tenant_id"), c.Param("key_id"), services.UpdateAPIKeyInput{
+		Name:               req.Name,
+		Scopes:             req.Scopes,
+		ExpiresAt:          req.ExpiresAt,
+		RateLimitPerMinute: req.RateLimitPerMinute,
+	}, actorID.(string))
+	if err != nil {
+		respondAPIKeyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    key,
+	})
+}
+
+// RevokeKey revokes an API key
+// @Summary Revoke API key
+// @Tags api-keys
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param key_id path string true "API key ID"
+// @Success 200 {object} models.APIKey
+// @Router /api/v1/admin/{tenant_id}/api-keys/{key_id}/revoke [post]
+func (h *APIKeyHandler) RevokeKey(c *gin.Context) {
+	actorID, exists := c.Get("user_id")
+	if !exists {
+		actorID = "system"
+	}
+
+	key, err := h.apiKeyService.RevokeKey(c.Request.Context(), c.Param("tenant_id"), c.Param("key_id"), actorID.(string))
+	if err != nil {
+		respondAPIKeyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    key,
+	})
+}
+
+// respondAPIKeyError maps API key service errors to HTTP responses
+func respondAPIKeyError(c *gin.Context, err error) {
+	if respondPlanLimitError(c, err) {
+		return
+	}
+	if err == repositories.ErrNotFound {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "api key not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusBadRequest, gin.H{
+		"success": false,
+		"error":   err.Error(),
+	})
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// AuditChainHandler handles the activity log hash chain endpoints (verification and signed checkpoints)
+type AuditChainHandler struct {
+	auditChainService *services.AuditChainService
+}
+
+// NewAuditChainHandler creates a new audit chain handler
+func NewAuditChainHandler(auditChainService *services.AuditChainService) *AuditChainHandler {
+	return &AuditChainHandler{
+		auditChainService: auditChainService,
+	}
+}
+
+// RegisterRoutes registers audit chain routes (tenant-scoped, admin)
+func (h *AuditChainHandler) RegisterRoutes(router *gin.RouterGroup) {
+	audit := router.Group("/audit")
+	{
+		audit.GET("/head", h.GetHead)
+		audit.GET("/verify", h.VerifyChain)
+		audit.GET("/checkpoints", h.ListCheckpoints)
+		audit.POST("/checkpoints", h.CreateCheckpoint)
+	}
+}
+
+// GetHead returns the current head of the activity log chain
+// @Summary Get audit chain head
+// @Tags audit
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Success 200 {object} models.AuditChainHead
+// @Router /api/v1/admin/{tenant_id}/audit/head [get]
+func (h *AuditChainHandler) GetHead(c *gin.Context) {
+	head, err := h.auditChainService.GetHead(c.Request.Context(), c.Param("tenant_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    head,
+	})
+}
+
+// VerifyChain verifies the activity log chain and reports the first broken link
+// @Summary Verify audit chain
+// @Description Detects removed (gaps) or modified entries and checkpoints that do not match
+// @Tags audit
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param from_checkpoint query bool false "Start after the latest signed checkpoint"
+// @Param max_entries query int false "Maximum entries verified (default 50000)"
+// @Success 200 {object} models.AuditVerificationReport
+// @Router /api/v1/admin/{tenant_id}/audit/verify [get]
+func (h *AuditChainHandler) VerifyChain(c *gin.Context) {
+	maxEntries, _ := strconv.Atoi(c.Query("max_entries"))
+
+	report, err := h.auditChainService.VerifyChain(c.Request.Context(), services.VerifyAuditChainInput{
+		TenantID:             c.Param("tenant_id"),
+		FromLatestCheckpoint: c.Query("from_checkpoint") == "true",
+		MaxEntries:           maxEntries,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    report,
+	})
+}
+
+// CreateCheckpoint verifies the chain and signs its current head
+// This endpoint should be called by a cron job daily
+// @Summary Create audit checkpoint
+// @Tags audit
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Success 201 {object} models.AuditCheckpoint
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/v1/admin/{tenant_id}/audit/checkpoints [post]
+func (h *AuditChainHandler) CreateCheckpoint(c *gin.Context) {
+	actorID, exists := c.Get("user_id")
+	if !exists {
+		actorID = "system"
+	}
+
+	checkpoint, err := h.auditChainService.CreateCheckpoint(c.Request.Context(), c.Param("tenant_id"), actorID.(string))
+	if err != nil {
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    checkpoint,
+	})
+}
+
+// ListCheckpoints lists the most recent signed checkpoints
+// @Summary List audit checkpoints
+// @Tags audit
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param limit query int false "Maximum checkpoints (default 30)"
+// @Success 200 {array} models.AuditCheckpoint
+// @Router /api/v1/admin/{tenant_id}/audit/checkpoints [get]
+func (h *AuditChainHandler) ListCheckpoints(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	checkpoints, err := h.auditChainService.ListCheckpoints(c.Request.Context(), c.Param("tenant_id"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    checkpoints,
+		"count":   len(checkpoints),
+	})
+}
@@ -10,6 +10,7 @@ import (
 
 	"firebase.google.com/go/v4/auth"
 	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
 	"github.com/altatech/ecosistema-imob/backend/internal/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -17,14 +18,16 @@ import (
 )
 
 type AuthHandler struct {
-	firebaseAuth *auth.Client
-	firestoreDB  *firestore.Client
+	firebaseAuth    *auth.Client
+	firestoreDB     *firestore.Client
+	activityLogRepo *repositories.ActivityLogRepository
 }
 
 func NewAuthHandler(firebaseAuth *auth.Client, firestoreDB *firestore.Client) *AuthHandler {
 	return &AuthHandler{
-		firebaseAuth: firebaseAuth,
-		firestoreDB:  firestoreDB,
+		firebaseAuth:    firebaseAuth,
+		firestoreDB:     firestoreDB,
+		activityLogRepo: repositories.NewActivityLogRepository(firestoreDB),
 	}
 }
 
@@ -562,16 +565,17 @@ func generateSlug(name string) string {
 	return slug
 }
 
-// Helper: Log activity
+// Helper: Log activity (through the repository, so the entry joins the tenant hash chain)
 func (h *AuthHandler) logActivity(ctx context.Context, tenantID, eventType string, metadata map[string]interface{}) {
-	activityLog := map[string]interface{}{
-		"tenant_id":  tenantID,
-		"event_type": eventType,
-		"metadata":   metadata,
-		"timestamp":  time.Now(),
+	activityLog := &models.ActivityLog{
+		TenantID:  tenantID,
+		EventType: eventType,
+		ActorType: models.ActorTypeSystem,
+		Metadata:  metadata,
+		Timestamp: time.Now(),
 	}
 
-	_, err := h.firestoreDB.Collection("tenants").Doc(tenantID).Collection("activity_logs").NewDoc().Set(ctx, activityLog)
+	err := h.activityLogRepo.Create(ctx, activityLog)
 	if err != nil {
 		log.Printf("Error logging activity: %v", err)
 	}
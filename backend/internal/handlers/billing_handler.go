@@ -0,0 +1,290 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// BillingHandler handles usage metering, invoices and the billing jobs
+type BillingHandler struct {
+	usageService   *services.UsageService
+	billingService *services.BillingService
+}
+
+// NewBillingHandler creates a new billing handler
+func NewBillingHandler(usageService *services.UsageService, billingService *services.BillingService) *BillingHandler {
+	return &BillingHandler{
+		usageService:   usageService,
+		billingService: billingService,
+	}
+}
+
+// PayInvoiceRequest is the request to pay an invoice
+type PayInvoiceRequest struct {
+	Method    models.PaymentMethod `json:"method" binding:"required"` // pix or card
+	CardToken string               `json:"card_token,omitempty"`      // required for card
+}
+
+// RegisterRoutes registers billing routes (tenant-scoped, admin)
+func (h *BillingHandler) RegisterRoutes(router *gin.RouterGroup) {
+	billing := router.Group("/billing")
+	{
+		billing.GET("/usage", h.GetUsage)
+		billing.GET("/invoices", h.ListInvoices)
+		billing.GET("/invoices/:invoice_id", h.GetInvoice)
+		billing.POST("/invoices/:invoice_id/pay", h.PayInvoice)
+	}
+}
+
+// RegisterJobRoutes registers the billing jobs (not tenant-scoped, the router must require a platform admin)
+func (h *BillingHandler) RegisterJobRoutes(router *gin.RouterGroup) {
+	jobs := router.Group("/jobs/billing")
+	{
+		jobs.POST("/snapshot-usage", h.SnapshotUsage)
+		jobs.POST("/generate-invoices", h.GenerateInvoices)
+		jobs.POST("/dunning", h.RunDunning)
+	}
+}
+
+// GetUsage returns the metered usage of the tenant in a period
+// @Summary Get metered usage
+// @Tags billing
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param period query string false "Period (YYYY-MM), default current month"
+// @Success 200 {object} models.MonthlyUsage
+// @Router /api/v1/admin/{tenant_id}/billing/usage [get]
+func (h *BillingHandler) GetUsage(c *gin.Context) {
+	usage, err := h.usageService.GetMonthlyUsage(c.Request.Context(), c.Param("tenant_id"), c.Query("period"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    usage,
+	})
+}
+
+// ListInvoices lists the invoices of the tenant
+// @Summary List invoices
+// @Tags billing
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param limit query int false "Limit"
+// @Success 200 {array} models.Invoice
+// @Router /api/v1/admin/{tenant_id}/billing/invoices [get]
+func (h *BillingHandler) ListInvoices(c *gin.Context) {
+	invoices, err := h.billingService.ListInvoices(c.Request.Context(), c.Param("tenant_id"), parsePaginationOptions(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    invoices,
+		"count":   len(invoices),
+	})
+}
+
+// GetInvoice returns an invoice as JSON, PDF or CSV
+// @Summary Get invoice
+// @Tags billing
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param invoice_id path string true "Invoice ID (YYYYMM)"
+// @Param format query string false "json (default), pdf or csv"
+// @Success 200 {object} models.Invoice
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/admin/{tenant_id}/billing/invoices/{invoice_id} [get]
+func (h *BillingHandler) GetInvoice(c *gin.Context) {
+	invoice, err := h.billingService.GetInvoice(c.Request.Context(), c.Param("tenant_id"), c.Param("invoice_id"))
+	if err != nil {
+		if err == repositories.ErrNotFound {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   "invoice not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	writeInvoice(c, invoice, c.DefaultQuery("format", "json"))
+}
+
+// PayInvoice charges an open or overdue invoice by card or PIX
+// @Summary Pay invoice
+// @Tags billing
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param invoice_id path string true "Invoice ID (YYYYMM)"
+// @Param body body PayInvoiceRequest true "Payment method"
+// @Success 200 {object} models.Invoice
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/v1/admin/{tenant_id}/billing/invoices/{invoice_id}/pay [post]
+func (h *BillingHandler) PayInvoice(c *gin.Context) {
+	var req PayInvoiceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	actorID, exists := c.Get("user_id")
+	if !exists {
+		actorID = "system"
+	}
+
+	invoice, err := h.billingService.PayInvoice(c.Request.Context(), c.Param("tenant_id"), c.Param("invoice_id"), req.Method, req.CardToken, actorID.(string))
+	if err != nil {
+		status := http.StatusBadRequest
+		switch {
+		case err == repositories.ErrNotFound:
+			status = http.StatusNotFound
+		case errors.Is(err, services.ErrInvoiceNotPayable):
+			status = http.StatusConflict
+		}
+		c.JSON(status, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    invoice,
+	})
+}
+
+// SnapshotUsage records today's active listings and storage bytes of every tenant
+// This endpoint should be called by a cron job daily, authenticated as a platform admin
+// @Summary Snapshot daily usage
+// @Tags billing
+// @Produce json
+// @Success 200 {object} services.UsageSnapshotResult
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/v1/admin/jobs/billing/snapshot-usage [post]
+func (h *BillingHandler) SnapshotUsage(c *gin.Context) {
+	result, err := h.usageService.SnapshotDaily(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    result,
+	})
+}
+
+// GenerateInvoices issues the invoices of a period for every tenant
+// This endpoint should be called by a cron job monthly (on the 1st, for the previous month), authenticated as a platform admin
+// @Summary Generate monthly invoices
+// @Tags billing
+// @Produce json
+// @Param period query string false "Period (YYYY-MM), default previous month"
+// @Success 200 {object} services.InvoiceRunResult
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/v1/admin/jobs/billing/generate-invoices [post]
+func (h *BillingHandler) GenerateInvoices(c *gin.Context) {
+	result, err := h.billingService.GenerateInvoices(c.Request.Context(), c.Query("period"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    result,
+	})
+}
+
+// RunDunning confirms PIX payments, retries failed charges and expires the subscriptions of unpaid invoices
+// This endpoint should be called by a cron job daily, authenticated as a platform admin
+// @Summary Run invoice dunning
+// @Tags billing
+// @Produce json
+// @Success 200 {object} services.DunningResult
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/v1/admin/jobs/billing/dunning [post]
+func (h *BillingHandler) RunDunning(c *gin.Context) {
+	result, err := h.billingService.RunDunning(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    result,
+	})
+}
+
+// writeInvoice writes an invoice as JSON, PDF or CSV
+func writeInvoice(c *gin.Context, invoice *models.Invoice, format string) {
+	var buf bytes.Buffer
+
+	switch format {
+	case "pdf":
+		if err := services.WriteInvoicePDF(&buf, invoice); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", invoice.Number+".pdf"))
+		c.Data(http.StatusOK, "application/pdf", buf.Bytes())
+
+	case "csv":
+		if err := services.WriteInvoiceCSV(&buf, invoice); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", invoice.Number+".csv"))
+		c.Data(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+
+	default:
+		c.JSON(http.StatusOK, gin.H{
+			"success": true,
+			"data":    invoice,
+		})
+	}
+}
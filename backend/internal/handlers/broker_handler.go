@@ -68,6 +68,9 @@ func (h *BrokerHandler) CreateBroker(c *gin.Context) {
 	broker.TenantID = tenantID
 
 	if err := h.brokerService.CreateBroker(c.Request.Context(), &broker); err != nil {
+		if respondPlanLimitError(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
 			"error":   err.Error(),
@@ -0,0 +1,276 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// ConsentHandler handles the consent ledger endpoints (versioned texts and consent per purpose)
+type ConsentHandler struct {
+	consentService *services.ConsentService
+}
+
+// NewConsentHandler creates a new consent handler
+func NewConsentHandler(consentService *services.ConsentService) *ConsentHandler {
+	return &ConsentHandler{
+		consentService: consentService,
+	}
+}
+
+// RegisterRoutes registers consent routes (tenant-scoped, admin)
+func (h *ConsentHandler) RegisterRoutes(router *gin.RouterGroup) {
+	texts := router.Group("/consent-texts")
+	{
+		texts.GET("/:purpose", h.ListTexts)
+		texts.POST("/:purpose", h.PublishText)
+	}
+
+	consents := router.Group("/consents")
+	{
+		consents.GET("/:subject_type/:subject_id", h.GetConsentState)
+		consents.GET("/:subject_type/:subject_id/history", h.GetConsentHistory)
+		consents.POST("/:subject_type/:subject_id", h.RecordConsent)
+	}
+}
+
+// RegisterPublicRoutes registers public consent routes (current text shown on forms)
+func (h *ConsentHandler) RegisterPublicRoutes(router *gin.RouterGroup) {
+	router.GET("/consent-texts/:purpose", h.GetCurrentText)
+}
+
+// PublishConsentTextRequest represents a new version of a consent text
+type PublishConsentTextRequest struct {
+	Text string `json:"text" binding:"required"`
+}
+
+// RecordConsentRequest represents a grant or withdrawal registered by a broker (e.g. consent given by phone)
+type RecordConsentRequest struct {
+	Purpose     models.ConsentPurpose `json:"purpose" binding:"required"`
+	Granted     *bool                 `json:"granted" binding:"required"`
+	TextVersion int                   `json:"text_version,omitempty"` // Versão publicada (0 = atual)
+	Text        string                `json:"text,omitempty"`         // Texto exibido, quando não publicado
+}
+
+// GetCurrentText returns the current consent text of a purpose
+// @Summary Get current consent text
+// @Tags consents
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param purpose path string true "Purpose (contact, marketing, data_sharing)"
+// @Success 200 {object} models.ConsentTextVersion
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/{tenant_id}/consent-texts/{purpose} [get]
+func (h *ConsentHandler) GetCurrentText(c *gin.Context) {
+	text, err := h.consentService.GetCurrentText(c.Request.Context(), c.Param("tenant_id"), models.ConsentPurpose(c.Param("purpose")))
+	if err != nil {
+		respondConsentError(c, err, "consent text not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    text,
+	})
+}
+
+// ListTexts lists every published version of a consent text
+// @Summary List consent text versions
+// @Tags consents
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param purpose path string true "Purpose (contact, marketing, data_sharing)"
+// @Success 200 {array} models.ConsentTextVersion
+// @Router /api/v1/admin/{tenant_id}/consent-texts/{purpose} [get]
+func (h *ConsentHandler) ListTexts(c *gin.Context) {
+	texts, err := h.consentService.ListTexts(c.Request.Context(), c.Param("tenant_id"), models.ConsentPurpose(c.Param("purpose")))
+	if err != nil {
+		respondConsentError(c, err, "consent text not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    texts,
+		"count":   len(texts),
+	})
+}
+
+// PublishText publishes a new version of a consent text (previous versions remain valid as evidence)
+// @Summary Publish consent text version
+// @Tags consents
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param purpose path string true "Purpose (contact, marketing, data_sharing)"
+// @Param body body PublishConsentTextRequest true "Text"
+// @Success 201 {object} models.ConsentTextVersion
+// @Router /api/v1/admin/{tenant_id}/consent-texts/{purpose} [post]
+func (h *ConsentHandler) PublishText(c *gin.Context) {
+	var req PublishConsentTextRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	actorID, exists := c.Get("user_id")
+	if !exists {
+		actorID = "system"
+	}
+
+	text, err := h.consentService.PublishText(c.Request.Context(), c.Param("tenant_id"), models.ConsentPurpose(c.Param("purpose")), req.Text, actorID.(string))
+	if err != nil {
+		respondConsentError(c, err, "consent text not found")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    text,
+	})
+}
+
+// GetConsentState returns the current consent of a lead or owner for every purpose
+// @Summary Get consent state
+// @Tags consents
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param subject_type path string true "Subject type (lead, owner)"
+// @Param subject_id path string true "Lead or owner ID"
+// @Success 200 {array} models.ConsentState
+// @Router /api/v1/admin/{tenant_id}/consents/{subject_type}/{subject_id} [get]
+func (h *ConsentHandler) GetConsentState(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	subjectType := models.ConsentSubjectType(c.Param("subject_type"))
+	subjectID := c.Param("subject_id")
+
+	if err := h.consentService.SubjectExists(c.Request.Context(), tenantID, subjectType, subjectID); err != nil {
+		respondConsentError(c, err, "subject not found")
+		return
+	}
+
+	states, err := h.consentService.GetConsentState(c.Request.Context(), tenantID, subjectType, subjectID)
+	if err != nil {
+		respondConsentError(c, err, "subject not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    states,
+	})
+}
+
+// GetConsentHistory returns the consent ledger of a lead or owner (oldest first)
+// @Summary Get consent history
+// @Tags consents
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param subject_type path string true "Subject type (lead, owner)"
+// @Param subject_id path string true "Lead or owner ID"
+// @Success 200 {array} models.ConsentRecord
+// @Router /api/v1/admin/{tenant_id}/consents/{subject_type}/{subject_id}/history [get]
+func (h *ConsentHandler) GetConsentHistory(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	subjectType := models.ConsentSubjectType(c.Param("subject_type"))
+	subjectID := c.Param("subject_id")
+
+	if err := h.consentService.SubjectExists(c.Request.Context(), tenantID, subjectType, subjectID); err != nil {
+		respondConsentError(c, err, "subject not found")
+		return
+	}
+
+	records, err := h.consentService.GetConsentHistory(c.Request.Context(), tenantID, subjectType, subjectID)
+	if err != nil {
+		respondConsentError(c, err, "subject not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    records,
+		"count":   len(records),
+	})
+}
+
+// RecordConsent registers a grant or withdrawal collected by a broker
+// @Summary Record consent
+// @Tags consents
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param subject_type path string true "Subject type (lead, owner)"
+// @Param subject_id path string true "Lead or owner ID"
+// @Param body body RecordConsentRequest true "Consent"
+// @Success 201 {object} models.ConsentRecord
+// @Router /api/v1/admin/{tenant_id}/consents/{subject_type}/{subject_id} [post]
+func (h *ConsentHandler) RecordConsent(c *gin.Context) {
+	var req RecordConsentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	tenantID := c.Param("tenant_id")
+	subjectType := models.ConsentSubjectType(c.Param("subject_type"))
+	subjectID := c.Param("subject_id")
+
+	if err := h.consentService.SubjectExists(c.Request.Context(), tenantID, subjectType, subjectID); err != nil {
+		respondConsentError(c, err, "subject not found")
+		return
+	}
+
+	actorID, exists := c.Get("user_id")
+	if !exists {
+		actorID = "system"
+	}
+
+	record, err := h.consentService.RecordConsent(c.Request.Context(), services.RecordConsentInput{
+		TenantID:    tenantID,
+		SubjectType: subjectType,
+		SubjectID:   subjectID,
+		Purpose:     req.Purpose,
+		Granted:     *req.Granted,
+		TextVersion: req.TextVersion,
+		Text:        req.Text,
+		IP:          c.ClientIP(),
+		UserAgent:   c.Request.UserAgent(),
+		Origin:      services.ConsentOriginBroker,
+		ActorType:   models.ActorTypeUser,
+		ActorID:     actorID.(string),
+	})
+	if err != nil {
+		respondConsentError(c, err, "subject not found")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    record,
+	})
+}
+
+// respondConsentError maps consent errors to HTTP responses
+func respondConsentError(c *gin.Context, err error, notFoundMessage string) {
+	if err == repositories.ErrNotFound {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   notFoundMessage,
+		})
+		return
+	}
+
+	c.JSON(http.StatusBadRequest, gin.H{
+		"success": false,
+		"error":   err.Error(),
+	})
+}
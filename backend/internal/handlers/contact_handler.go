@@ -0,0 +1,286 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/middleware"
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/services"
+	"github.com/altatech/ecosistema-imob/backend/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// ContactHandler handles contacts: the person behind the leads of several properties
+type ContactHandler struct {
+	contactService *services.ContactService
+}
+
+// NewContactHandler creates a new contact handler
+func NewContactHandler(contactService *services.ContactService) *ContactHandler {
+	return &ContactHandler{
+		contactService: contactService,
+	}
+}
+
+// RegisterRoutes registers contact routes (tenant-scoped)
+func (h *ContactHandler) RegisterRoutes(router *gin.RouterGroup) {
+	contacts := router.Group("/contacts")
+	{
+		contacts.GET("", h.ListContacts)
+		contacts.GET("/:id", h.GetContact)
+		contacts.GET("/:id/leads", h.ListContactLeads)
+		contacts.GET("/:id/timeline", h.GetContactTimeline)
+		contacts.GET("/:id/merge", h.PreviewMerge)
+		contacts.POST("/:id/merge", h.MergeContacts)
+	}
+}
+
+// MergeContactsRequest is the body of POST /contacts/:id/merge
+type MergeContactsRequest struct {
+	SourceContactID string                                               `json:"source_contact_id" binding:"required"`
+	Resolution      map[models.ContactMergeField]models.ContactMergeSide `json:"resolution"` // field -> "target" | "source"
+}
+
+// ListContacts lists the contacts of the tenant
+// @Summary List contacts
+// @Description Deduplicated people (same phone or e-mail) with their interests, most recent lead first
+// @Tags contacts
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param limit query int false "Limit"
+// @Param offset query int false "Offset"
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/{tenant_id}/contacts [get]
+func (h *ContactHandler) ListContacts(c *gin.Context) {
+	// Most recent lead first unless another order is requested
+	opts := parsePaginationOptions(c)
+	if c.Query("order_by") == "" {
+		opts.OrderBy = ""
+	}
+
+	contacts, err := h.contactService.ListContacts(c.Request.Context(), c.Param("tenant_id"), opts)
+	if err != nil {
+		respondContactError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    contactsForResponse(c, contacts),
+		"count":   len(contacts),
+	})
+}
+
+// GetContact retrieves a contact
+// @Summary Get contact
+// @Tags contacts
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Contact ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/contacts/{id} [get]
+func (h *ContactHandler) GetContact(c *gin.Context) {
+	contact, err := h.contactService.GetContact(c.Request.Context(), c.Param("tenant_id"), c.Param("id"))
+	if err != nil {
+		respondContactError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    contactForResponse(c, contact),
+	})
+}
+
+// ListContactLeads lists the leads (interests) of a contact
+// @Summary List contact leads
+// @Description One lead per property the contact asked about, most recent first
+// @Tags contacts
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Contact ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/contacts/{id}/leads [get]
+func (h *ContactHandler) ListContactLeads(c *gin.Context) {
+	leads, err := h.contactService.ListContactLeads(c.Request.Context(), c.Param("tenant_id"), c.Param("id"))
+	if err != nil {
+		respondContactError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    leadsForResponse(c, leads),
+		"count":   len(leads),
+	})
+}
+
+// GetContactTimeline retrieves the activity timeline of a contact
+// @Summary Get contact timeline
+// @Description Lead timelines of all the contact's leads, most recent first
+// @Tags contacts
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Contact ID"
+// @Param limit query int false "Limit" default(50)
+// @Param offset query int false "Offset"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/contacts/{id}/timeline [get]
+func (h *ContactHandler) GetContactTimeline(c *gin.Context) {
+	logs, err := h.contactService.GetContactTimeline(c.Request.Context(), c.Param("tenant_id"), c.Param("id"), parsePaginationOptions(c))
+	if err != nil {
+		respondContactError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    logs,
+		"count":   len(logs),
+	})
+}
+
+// PreviewMerge returns the conflicting fields of a merge
+// @Summary Preview contact merge
+// @Description Fields with different values on both contacts; each needs a resolution ("target" or "source") in the merge
+// @Tags contacts
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Contact ID (kept)"
+// @Param source_contact_id query string true "Duplicate contact ID (merged into the contact)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/contacts/{id}/merge [get]
+func (h *ContactHandler) PreviewMerge(c *gin.Context) {
+	conflicts, err := h.contactService.PreviewMerge(c.Request.Context(), c.Param("tenant_id"), c.Param("id"), c.Query("source_contact_id"))
+	if err != nil {
+		respondContactError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    contactMergeConflictsForResponse(c, conflicts),
+	})
+}
+
+// MergeContacts merges a duplicate contact into the contact
+// @Summary Merge contacts
+// @Description Admins only: moves the leads of the duplicate contact to the contact, applies the field resolutions and deletes the duplicate
+// @Tags contacts
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Contact ID (kept)"
+// @Param request body MergeContactsRequest true "Duplicate contact and field resolutions"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/{tenant_id}/contacts/{id}/merge [post]
+func (h *ContactHandler) MergeContacts(c *gin.Context) {
+	var req MergeContactsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	actorID, exists := c.Get("user_id")
+	if !exists {
+		actorID = "system"
+	}
+
+	contact, conflicts, err := h.contactService.MergeContacts(c.Request.Context(), c.Param("tenant_id"), c.Param("id"), actorID.(string), services.MergeContactsInput{
+		SourceID:   req.SourceContactID,
+		Resolution: req.Resolution,
+	})
+	if errors.Is(err, services.ErrContactMergeConflict) {
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"data":    contactMergeConflictsForResponse(c, conflicts),
+		})
+		return
+	}
+	if err != nil {
+		respondContactError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    contactForResponse(c, contact),
+	})
+}
+
+// contactForResponse masks the contact's PII for callers without PII permission
+func contactForResponse(c *gin.Context, contact *models.Contact) *models.Contact {
+	if contact == nil || middleware.CanViewPII(c) {
+		return contact
+	}
+
+	masked := *contact
+	masked.Email = utils.MaskEmail(contact.Email)
+	masked.Phone = utils.MaskPhone(contact.Phone)
+	return &masked
+}
+
+// contactsForResponse masks a list of contacts (see contactForResponse)
+func contactsForResponse(c *gin.Context, contacts []*models.Contact) []*models.Contact {
+	if middleware.CanViewPII(c) {
+		return contacts
+	}
+
+	masked := make([]*models.Contact, 0, len(contacts))
+	for _, contact := range contacts {
+		masked = append(masked, contactForResponse(c, contact))
+	}
+	return masked
+}
+
+// contactMergeConflictsForResponse masks the e-mail and phone values of merge conflicts for callers without PII permission
+func contactMergeConflictsForResponse(c *gin.Context, conflicts []models.ContactMergeConflict) []models.ContactMergeConflict {
+	if middleware.CanViewPII(c) {
+		return conflicts
+	}
+
+	masked := make([]models.ContactMergeConflict, 0, len(conflicts))
+	for _, conflict := range conflicts {
+		switch conflict.Field {
+		case models.ContactMergeFieldEmail:
+			conflict.Target, conflict.Source = utils.MaskEmail(conflict.Target), utils.MaskEmail(conflict.Source)
+		case models.ContactMergeFieldPhone:
+			conflict.Target, conflict.Source = utils.MaskPhone(conflict.Target), utils.MaskPhone(conflict.Source)
+		}
+		masked = append(masked, conflict)
+	}
+	return masked
+}
+
+// respondContactError writes the error of a contact operation
+func respondContactError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, services.ErrContactMergeForbidden):
+		status = http.StatusForbidden
+	case errors.Is(err, services.ErrContactMergeSameContact),
+		errors.Is(err, repositories.ErrInvalidInput):
+		status = http.StatusBadRequest
+	}
+	c.JSON(status, gin.H{
+		"success": false,
+		"error":   err.Error(),
+	})
+}
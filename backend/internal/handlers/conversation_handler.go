@@ -0,0 +1,381 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// ConversationHandler handles the unified inbox of leads
+type ConversationHandler struct {
+	conversationService *services.ConversationService
+}
+
+// NewConversationHandler creates a new conversation handler
+func NewConversationHandler(conversationService *services.ConversationService) *ConversationHandler {
+	return &ConversationHandler{
+		conversationService: conversationService,
+	}
+}
+
+// RegisterRoutes registers conversation routes (tenant-scoped)
+func (h *ConversationHandler) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/conversations", h.ListConversations)
+
+	leads := router.Group("/leads/:id")
+	{
+		leads.GET("/conversation", h.GetConversation)
+		leads.POST("/conversation/read", h.MarkRead)
+		leads.POST("/conversation/unread", h.MarkUnread)
+		leads.POST("/conversation/assign", h.Assign)
+		leads.POST("/messages", h.SendReply)
+		leads.POST("/notes", h.AddNote)
+		leads.POST("/calls", h.LogCall)
+	}
+}
+
+// SendReplyRequest is the body of POST /leads/:id/messages
+type SendReplyRequest struct {
+	Channel models.LeadChannel `json:"channel" binding:"required"` // whatsapp, email
+	Subject string             `json:"subject"`                    // e-mail
+	Body    string             `json:"body" binding:"required"`
+}
+
+// AddNoteRequest is the body of POST /leads/:id/notes
+type AddNoteRequest struct {
+	Body string `json:"body" binding:"required"`
+}
+
+// LogCallRequest is the body of POST /leads/:id/calls
+type LogCallRequest struct {
+	Direction       models.MessageDirection `json:"direction"` // default: outbound
+	Outcome         models.CallOutcome      `json:"outcome" binding:"required"`
+	DurationSeconds int                     `json:"duration_seconds"`
+	Notes           string                  `json:"notes"`
+	CalledAt        *time.Time              `json:"called_at"`
+}
+
+// AssignConversationRequest is the body of POST /leads/:id/conversation/assign
+type AssignConversationRequest struct {
+	BrokerID string `json:"broker_id" binding:"required"`
+}
+
+// ListConversations lists the conversation inbox of the tenant
+// @Summary List conversations
+// @Description Unified inbox: one conversation per lead across channels, most recent activity first
+// @Tags conversations
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param assigned_broker_id query string false "Filter by assigned broker"
+// @Param property_id query string false "Filter by property"
+// @Param channel query string false "Filter by channel (whatsapp, email, phone, form)"
+// @Param unread query bool false "Only conversations with unread messages"
+// @Param limit query int false "Limit"
+// @Param offset query int false "Offset"
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/{tenant_id}/conversations [get]
+func (h *ConversationHandler) ListConversations(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+
+	filters := &repositories.ConversationFilters{
+		AssignedBrokerID: c.Query("assigned_broker_id"),
+		PropertyID:       c.Query("property_id"),
+		UnreadOnly:       c.Query("unread") == "true",
+	}
+	if channel := c.Query("channel"); channel != "" {
+		leadChannel := models.LeadChannel(channel)
+		filters.Channel = &leadChannel
+	}
+
+	// Most recent activity first unless another order is requested
+	opts := parsePaginationOptions(c)
+	if c.Query("order_by") == "" {
+		opts.OrderBy = ""
+	}
+
+	conversations, err := h.conversationService.ListConversations(c.Request.Context(), tenantID, filters, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    conversations,
+		"count":   len(conversations),
+	})
+}
+
+// GetConversation retrieves the conversation summary of a lead
+// @Summary Get lead conversation
+// @Description Read/unread state, assignment and last message of the conversation with a lead (messages in GET /leads/{id}/messages)
+// @Tags conversations
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Lead ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/leads/{id}/conversation [get]
+func (h *ConversationHandler) GetConversation(c *gin.Context) {
+	conversation, err := h.conversationService.GetConversation(c.Request.Context(), c.Param("tenant_id"), c.Param("id"))
+	if err != nil {
+		respondConversationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    conversation,
+	})
+}
+
+// MarkRead marks the conversation of a lead as read
+// @Summary Mark conversation as read
+// @Tags conversations
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Lead ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/leads/{id}/conversation/read [post]
+func (h *ConversationHandler) MarkRead(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		userID = "system"
+	}
+
+	conversation, err := h.conversationService.MarkRead(c.Request.Context(), c.Param("tenant_id"), c.Param("id"), userID.(string))
+	if err != nil {
+		respondConversationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    conversation,
+	})
+}
+
+// MarkUnread flags the conversation of a lead as unread
+// @Summary Mark conversation as unread
+// @Tags conversations
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Lead ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/leads/{id}/conversation/unread [post]
+func (h *ConversationHandler) MarkUnread(c *gin.Context) {
+	conversation, err := h.conversationService.MarkUnread(c.Request.Context(), c.Param("tenant_id"), c.Param("id"))
+	if err != nil {
+		respondConversationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    conversation,
+	})
+}
+
+// Assign assigns the conversation of a lead to a broker
+// @Summary Assign conversation
+// @Tags conversations
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Lead ID"
+// @Param request body AssignConversationRequest true "Broker"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/leads/{id}/conversation/assign [post]
+func (h *ConversationHandler) Assign(c *gin.Context) {
+	var req AssignConversationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	actorID, exists := c.Get("user_id")
+	if !exists {
+		actorID = "system"
+	}
+
+	conversation, err := h.conversationService.Assign(c.Request.Context(), c.Param("tenant_id"), c.Param("id"), req.BrokerID, actorID.(string))
+	if err != nil {
+		respondConversationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    conversation,
+	})
+}
+
+// SendReply sends a reply to a lead through the messaging provider of the channel
+// @Summary Reply to a lead
+// @Description Sends the reply (WhatsApp within the 24h service window, e-mail) and records it in the conversation
+// @Tags conversations
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Lead ID"
+// @Param request body SendReplyRequest true "Reply"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 422 {object} map[string]interface{}
+// @Router /api/{tenant_id}/leads/{id}/messages [post]
+func (h *ConversationHandler) SendReply(c *gin.Context) {
+	var req SendReplyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	authorID, exists := c.Get("user_id")
+	if !exists {
+		authorID = "system"
+	}
+
+	message, err := h.conversationService.SendReply(c.Request.Context(), c.Param("tenant_id"), c.Param("id"), authorID.(string), services.SendReplyInput{
+		Channel: req.Channel,
+		Subject: req.Subject,
+		Body:    req.Body,
+	})
+	if err != nil {
+		respondConversationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    conversationMessageForResponse(c, message),
+	})
+}
+
+// AddNote adds an internal note to the conversation of a lead
+// @Summary Add conversation note
+// @Description Internal broker note (never sent to the lead)
+// @Tags conversations
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Lead ID"
+// @Param request body AddNoteRequest true "Note"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/leads/{id}/notes [post]
+func (h *ConversationHandler) AddNote(c *gin.Context) {
+	var req AddNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	authorID, exists := c.Get("user_id")
+	if !exists {
+		authorID = "system"
+	}
+
+	message, err := h.conversationService.AddNote(c.Request.Context(), c.Param("tenant_id"), c.Param("id"), authorID.(string), req.Body)
+	if err != nil {
+		respondConversationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    message,
+	})
+}
+
+// LogCall logs a phone call with a lead in the conversation
+// @Summary Log call
+// @Tags conversations
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Lead ID"
+// @Param request body LogCallRequest true "Call"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/leads/{id}/calls [post]
+func (h *ConversationHandler) LogCall(c *gin.Context) {
+	var req LogCallRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	authorID, exists := c.Get("user_id")
+	if !exists {
+		authorID = "system"
+	}
+
+	input := services.LogCallInput{
+		Direction:       req.Direction,
+		Outcome:         req.Outcome,
+		DurationSeconds: req.DurationSeconds,
+		Notes:           req.Notes,
+	}
+	if req.CalledAt != nil {
+		input.CalledAt = *req.CalledAt
+	}
+
+	message, err := h.conversationService.LogCall(c.Request.Context(), c.Param("tenant_id"), c.Param("id"), authorID.(string), input)
+	if err != nil {
+		respondConversationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    message,
+	})
+}
+
+// respondConversationError writes the error of a conversation operation
+func respondConversationError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError // provider and storage failures
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, services.ErrWhatsAppWindowClosed),
+		errors.Is(err, services.ErrNoMessagingProvider),
+		errors.Is(err, services.ErrMessagingNotConfigured),
+		errors.Is(err, services.ErrContactConsentRevoked):
+		status = http.StatusUnprocessableEntity
+	case errors.Is(err, repositories.ErrInvalidInput):
+		status = http.StatusBadRequest
+	}
+	c.JSON(status, gin.H{
+		"success": false,
+		"error":   err.Error(),
+	})
+}
@@ -0,0 +1,472 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// DataSubjectRequestHandler handles LGPD data subject requests (titular dos dados)
+type DataSubjectRequestHandler struct {
+	dsrService *services.DataSubjectRequestService
+}
+
+// NewDataSubjectRequestHandler creates a new data subject request handler
+func NewDataSubjectRequestHandler(dsrService *services.DataSubjectRequestService) *DataSubjectRequestHandler {
+	return &DataSubjectRequestHandler{
+		dsrService: dsrService,
+	}
+}
+
+// RegisterPublicRoutes registers the intake routes used by the data subject (no auth)
+func (h *DataSubjectRequestHandler) RegisterPublicRoutes(router *gin.RouterGroup) {
+	privacy := router.Group("/privacy/requests")
+	{
+		privacy.POST("", h.CreateRequest)
+		privacy.GET("/:request_id/status", h.GetRequestStatus)
+		privacy.POST("/:request_id/verify", h.VerifyRequest)
+		privacy.POST("/:request_id/resend-code", h.ResendCode)
+	}
+}
+
+// RegisterRoutes registers data subject request routes (tenant-scoped, admin)
+func (h *DataSubjectRequestHandler) RegisterRoutes(router *gin.RouterGroup) {
+	privacy := router.Group("/privacy/requests")
+	{
+		privacy.GET("", h.ListRequests)
+		privacy.GET("/report", h.GetAuditReport)
+		privacy.GET("/:request_id", h.GetRequest)
+		privacy.POST("/:request_id/search", h.SearchSubjectData)
+		privacy.GET("/:request_id/export", h.ExportSubjectData)
+		privacy.POST("/:request_id/erase", h.EraseSubjectData)
+		privacy.POST("/:request_id/complete", h.CompleteRequest)
+		privacy.POST("/:request_id/reject", h.RejectRequest)
+	}
+}
+
+// CreateDataSubjectRequestRequest represents the request body submitted by the data subject
+type CreateDataSubjectRequestRequest struct {
+	Type     models.DSRType `json:"type" binding:"required"` // access, portability, erasure
+	Name     string         `json:"name" binding:"required"`
+	Email    string         `json:"email,omitempty"`
+	Phone    string         `json:"phone,omitempty"`
+	Document string         `json:"document,omitempty"` // CPF (opcional, amplia a busca)
+	Details  string         `json:"details,omitempty"`
+}
+
+// VerifyDataSubjectRequestRequest represents the verification code submission
+type VerifyDataSubjectRequestRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// CompleteDataSubjectRequestRequest represents the resolution notes of a completed request
+type CompleteDataSubjectRequestRequest struct {
+	Notes string `json:"notes,omitempty"`
+}
+
+// RejectDataSubjectRequestRequest represents the reason for rejecting a request
+type RejectDataSubjectRequestRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// CreateRequest registers a data subject request and sends the verification code
+// @Summary Submit LGPD data subject request
+// @Tags privacy
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param body body CreateDataSubjectRequestRequest true "Request data"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/v1/{tenant_id}/privacy/requests [post]
+func (h *DataSubjectRequestHandler) CreateRequest(c *gin.Context) {
+	var req CreateDataSubjectRequestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	request, err := h.dsrService.CreateRequest(c.Request.Context(), services.CreateDataSubjectRequestInput{
+		TenantID: c.Param("tenant_id"),
+		Type:     req.Type,
+		Name:     req.Name,
+		Email:    req.Email,
+		Phone:    req.Phone,
+		Document: req.Document,
+		Details:  req.Details,
+		ClientIP: c.ClientIP(),
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    dsrPublicStatus(request),
+		"message": fmt.Sprintf("Verification code sent via %s", request.VerificationChannel),
+	})
+}
+
+// GetRequestStatus returns the public status of a request (no personal data)
+// @Summary Get LGPD request status
+// @Tags privacy
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param request_id path string true "Request ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/{tenant_id}/privacy/requests/{request_id}/status [get]
+func (h *DataSubjectRequestHandler) GetRequestStatus(c *gin.Context) {
+	request, err := h.dsrService.GetRequest(c.Request.Context(), c.Param("tenant_id"), c.Param("request_id"))
+	if err != nil {
+		respondDataSubjectRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    dsrPublicStatus(request),
+	})
+}
+
+// VerifyRequest confirms the subject's identity with the code received
+// @Summary Verify LGPD request
+// @Tags privacy
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param request_id path string true "Request ID"
+// @Param body body VerifyDataSubjectRequestRequest true "Verification code"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/v1/{tenant_id}/privacy/requests/{request_id}/verify [post]
+func (h *DataSubjectRequestHandler) VerifyRequest(c *gin.Context) {
+	var req VerifyDataSubjectRequestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	request, err := h.dsrService.VerifyRequest(c.Request.Context(), c.Param("tenant_id"), c.Param("request_id"), req.Code)
+	if err != nil {
+		respondDataSubjectRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    dsrPublicStatus(request),
+	})
+}
+
+// ResendCode sends a new verification code
+// @Summary Resend LGPD verification code
+// @Tags privacy
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param request_id path string true "Request ID"
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/{tenant_id}/privacy/requests/{request_id}/resend-code [post]
+func (h *DataSubjectRequestHandler) ResendCode(c *gin.Context) {
+	if err := h.dsrService.ResendVerificationCode(c.Request.Context(), c.Param("tenant_id"), c.Param("request_id")); err != nil {
+		respondDataSubjectRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Verification code sent",
+	})
+}
+
+// ListRequests lists data subject requests
+// @Summary List LGPD requests
+// @Tags privacy
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param status query string false "pending_verification, verified, completed, rejected"
+// @Param overdue query bool false "Only verified requests past the 15-day deadline"
+// @Success 200 {array} models.DataSubjectRequest
+// @Router /api/v1/admin/{tenant_id}/privacy/requests [get]
+func (h *DataSubjectRequestHandler) ListRequests(c *gin.Context) {
+	requests, err := h.dsrService.ListRequests(c.Request.Context(), c.Param("tenant_id"), models.DSRStatus(c.Query("status")), c.Query("overdue") == "true")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    dataSubjectRequestsForResponse(c, requests),
+		"count":   len(requests),
+	})
+}
+
+// GetAuditReport summarizes requests of a period (default: last 12 months)
+// @Summary LGPD requests audit report
+// @Tags privacy
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param from query string false "Start date (YYYY-MM-DD)"
+// @Param to query string false "End date (YYYY-MM-DD, exclusive)"
+// @Success 200 {object} services.DSRAuditReport
+// @Router /api/v1/admin/{tenant_id}/privacy/requests/report [get]
+func (h *DataSubjectRequestHandler) GetAuditReport(c *gin.Context) {
+	to := time.Now()
+	from := to.AddDate(-1, 0, 0)
+
+	if value := c.Query("from"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "invalid from date (expected YYYY-MM-DD)",
+			})
+			return
+		}
+		from = parsed
+	}
+	if value := c.Query("to"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "invalid to date (expected YYYY-MM-DD)",
+			})
+			return
+		}
+		to = parsed
+	}
+
+	report, err := h.dsrService.GetAuditReport(c.Request.Context(), c.Param("tenant_id"), from, to)
+	if err != nil {
+		respondDataSubjectRequestError(c, err)
+		return
+	}
+	report.Overdue = dataSubjectRequestsForResponse(c, report.Overdue)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    report,
+	})
+}
+
+// GetRequest retrieves a data subject request
+// @Summary Get LGPD request
+// @Tags privacy
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param request_id path string true "Request ID"
+// @Success 200 {object} models.DataSubjectRequest
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/admin/{tenant_id}/privacy/requests/{request_id} [get]
+func (h *DataSubjectRequestHandler) GetRequest(c *gin.Context) {
+	request, err := h.dsrService.GetRequest(c.Request.Context(), c.Param("tenant_id"), c.Param("request_id"))
+	if err != nil {
+		respondDataSubjectRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    dataSubjectRequestForResponse(c, request),
+	})
+}
+
+// SearchSubjectData searches the subject's data across the tenant collections
+// @Summary Search data subject records
+// @Tags privacy
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param request_id path string true "Request ID"
+// @Success 200 {object} models.DataSubjectRequest
+// @Router /api/v1/admin/{tenant_id}/privacy/requests/{request_id}/search [post]
+func (h *DataSubjectRequestHandler) SearchSubjectData(c *gin.Context) {
+	actorID, exists := c.Get("user_id")
+	if !exists {
+		actorID = "system"
+	}
+
+	request, err := h.dsrService.SearchSubjectData(c.Request.Context(), c.Param("tenant_id"), c.Param("request_id"), actorID.(string))
+	if err != nil {
+		respondDataSubjectRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    dataSubjectRequestForResponse(c, request),
+	})
+}
+
+// ExportSubjectData downloads the subject's data as a JSON file (acesso/portabilidade)
+// @Summary Export data subject records
+// @Tags privacy
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param request_id path string true "Request ID"
+// @Success 200 {object} services.DataSubjectExport
+// @Router /api/v1/admin/{tenant_id}/privacy/requests/{request_id}/export [get]
+func (h *DataSubjectRequestHandler) ExportSubjectData(c *gin.Context) {
+	actorID, exists := c.Get("user_id")
+	if !exists {
+		actorID = "system"
+	}
+
+	export, err := h.dsrService.ExportSubjectData(c.Request.Context(), c.Param("tenant_id"), c.Param("request_id"), actorID.(string))
+	if err != nil {
+		respondDataSubjectRequestError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"dados-titular-%s.json\"", export.RequestID))
+	c.IndentedJSON(http.StatusOK, export)
+}
+
+// EraseSubjectData anonymizes the subject's records (erasure requests only)
+// @Summary Erase data subject records
+// @Tags privacy
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param request_id path string true "Request ID"
+// @Success 200 {object} models.DataSubjectRequest
+// @Router /api/v1/admin/{tenant_id}/privacy/requests/{request_id}/erase [post]
+func (h *DataSubjectRequestHandler) EraseSubjectData(c *gin.Context) {
+	actorID, exists := c.Get("user_id")
+	if !exists {
+		actorID = "system"
+	}
+
+	request, err := h.dsrService.EraseSubjectData(c.Request.Context(), c.Param("tenant_id"), c.Param("request_id"), actorID.(string))
+	if err != nil {
+		respondDataSubjectRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    dataSubjectRequestForResponse(c, request),
+	})
+}
+
+// CompleteRequest marks a request as answered
+// @Summary Complete LGPD request
+// @Tags privacy
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param request_id path string true "Request ID"
+// @Param body body CompleteDataSubjectRequestRequest false "Resolution notes"
+// @Success 200 {object} models.DataSubjectRequest
+// @Router /api/v1/admin/{tenant_id}/privacy/requests/{request_id}/complete [post]
+func (h *DataSubjectRequestHandler) CompleteRequest(c *gin.Context) {
+	var req CompleteDataSubjectRequestRequest
+	_ = c.ShouldBindJSON(&req)
+
+	actorID, exists := c.Get("user_id")
+	if !exists {
+		actorID = "system"
+	}
+
+	request, err := h.dsrService.CompleteRequest(c.Request.Context(), c.Param("tenant_id"), c.Param("request_id"), actorID.(string), req.Notes)
+	if err != nil {
+		respondDataSubjectRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    dataSubjectRequestForResponse(c, request),
+	})
+}
+
+// RejectRequest closes a request without fulfilling it
+// @Summary Reject LGPD request
+// @Tags privacy
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param request_id path string true "Request ID"
+// @Param body body RejectDataSubjectRequestRequest true "Reason"
+// @Success 200 {object} models.DataSubjectRequest
+// @Router /api/v1/admin/{tenant_id}/privacy/requests/{request_id}/reject [post]
+func (h *DataSubjectRequestHandler) RejectRequest(c *gin.Context) {
+	var req RejectDataSubjectRequestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	actorID, exists := c.Get("user_id")
+	if !exists {
+		actorID = "system"
+	}
+
+	request, err := h.dsrService.RejectRequest(c.Request.Context(), c.Param("tenant_id"), c.Param("request_id"), actorID.(string), req.Reason)
+	if err != nil {
+		respondDataSubjectRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    dataSubjectRequestForResponse(c, request),
+	})
+}
+
+// dsrPublicStatus is what the data subject sees about the request (no personal data)
+func dsrPublicStatus(request *models.DataSubjectRequest) gin.H {
+	return gin.H{
+		"id":                   request.ID,
+		"type":                 request.Type,
+		"status":               request.Status,
+		"verification_channel": request.VerificationChannel,
+		"due_at":               request.DueAt,
+		"completed_at":         request.CompletedAt,
+	}
+}
+
+// respondDataSubjectRequestError maps data subject request errors to HTTP responses
+func respondDataSubjectRequestError(c *gin.Context, err error) {
+	if err == repositories.ErrNotFound {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "data subject request not found",
+		})
+		return
+	}
+	if errors.Is(err, services.ErrDSRResendThrottled) {
+		c.JSON(http.StatusTooManyRequests, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusBadRequest, gin.H{
+		"success": false,
+		"error":   err.Error(),
+	})
+}
@@ -0,0 +1,278 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/middleware"
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// DomainHandler handles tenant custom domains and the host-based public site info
+type DomainHandler struct {
+	domainService *services.DomainService
+}
+
+// NewDomainHandler creates a new domain handler
+func NewDomainHandler(domainService *services.DomainService) *DomainHandler {
+	return &DomainHandler{
+		domainService: domainService,
+	}
+}
+
+// AddDomainRequest is the request to register a custom domain
+type AddDomainRequest struct {
+	Hostname string `json:"hostname" binding:"required"` // ex: www.imobiliaria.com.br
+}
+
+// DomainResponse is a custom domain with the DNS record that proves its ownership
+type DomainResponse struct {
+	*models.TenantDomain
+	VerificationRecord DomainVerificationRecord `json:"verification_record"`
+}
+
+// DomainVerificationRecord is the TXT record the tenant must create to verify a domain
+type DomainVerificationRecord struct {
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// SiteInfoResponse is the public site resolved from the request host
+type SiteInfoResponse struct {
+	BaseURL  string                       `json:"base_url"`
+	Branding *models.PublicTenantBranding `json:"branding"`
+}
+
+// RegisterRoutes registers custom domain routes (tenant-scoped, admin)
+func (h *DomainHandler) RegisterRoutes(router *gin.RouterGroup) {
+	domains := router.Group("/domains")
+	{
+		domains.GET("", h.ListDomains)
+		domains.POST("", h.AddDomain)
+		domains.POST("/:hostname/verify", h.VerifyDomain)
+		domains.POST("/:hostname/primary", h.SetPrimaryDomain)
+		domains.DELETE("/:hostname", h.RemoveDomain)
+	}
+}
+
+// RegisterSiteRoutes registers the site info route (host-based public routes)
+func (h *DomainHandler) RegisterSiteRoutes(router *gin.RouterGroup) {
+	router.GET("", h.GetSite)
+}
+
+// ListDomains lists the custom domains of the tenant
+// @Summary List custom domains
+// @Tags domains
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Success 200 {array} DomainResponse
+// @Router /api/v1/admin/{tenant_id}/domains [get]
+func (h *DomainHandler) ListDomains(c *gin.Context) {
+	domains, err := h.domainService.ListDomains(c.Request.Context(), c.Param("tenant_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	response := make([]DomainResponse, 0, len(domains))
+	for _, domain := range domains {
+		response = append(response, newDomainResponse(domain))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    response,
+		"count":   len(response),
+	})
+}
+
+// AddDomain registers a custom domain; the response has the TXT record to create in the domain's DNS
+// @Summary Add custom domain
+// @Tags domains
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param body body AddDomainRequest true "Domain"
+// @Success 201 {object} DomainResponse
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/v1/admin/{tenant_id}/domains [post]
+func (h *DomainHandler) AddDomain(c *gin.Context) {
+	var req AddDomainRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	actorID, exists := c.Get("user_id")
+	if !exists {
+		actorID = "system"
+	}
+
+	domain, err := h.domainService.AddDomain(c.Request.Context(), c.Param("tenant_id"), req.Hostname, actorID.(string))
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, services.ErrDomainTaken) {
+			status = http.StatusConflict
+		}
+		c.JSON(status, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    newDomainResponse(domain),
+	})
+}
+
+// VerifyDomain checks the TXT record of a domain
+// @Summary Verify custom domain
+// @Tags domains
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param hostname path string true "Hostname"
+// @Success 200 {object} DomainResponse
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 410 {object} map[string]interface{}
+// @Router /api/v1/admin/{tenant_id}/domains/{hostname}/verify [post]
+func (h *DomainHandler) VerifyDomain(c *gin.Context) {
+	actorID, exists := c.Get("user_id")
+	if !exists {
+		actorID = "system"
+	}
+
+	domain, err := h.domainService.VerifyDomain(c.Request.Context(), c.Param("tenant_id"), c.Param("hostname"), actorID.(string))
+	if err != nil {
+		respondDomainError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"data":     newDomainResponse(domain),
+		"verified": domain.Status == models.DomainStatusVerified,
+	})
+}
+
+// SetPrimaryDomain sets a verified domain as the canonical domain of the tenant's site
+// @Summary Set primary domain
+// @Tags domains
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param hostname path string true "Hostname"
+// @Success 200 {object} DomainResponse
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/v1/admin/{tenant_id}/domains/{hostname}/primary [post]
+func (h *DomainHandler) SetPrimaryDomain(c *gin.Context) {
+	actorID, exists := c.Get("user_id")
+	if !exists {
+		actorID = "system"
+	}
+
+	domain, err := h.domainService.SetPrimaryDomain(c.Request.Context(), c.Param("tenant_id"), c.Param("hostname"), actorID.(string))
+	if err != nil {
+		respondDomainError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    newDomainResponse(domain),
+	})
+}
+
+// RemoveDomain removes a custom domain
+// @Summary Remove custom domain
+// @Tags domains
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param hostname path string true "Hostname"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/admin/{tenant_id}/domains/{hostname} [delete]
+func (h *DomainHandler) RemoveDomain(c *gin.Context) {
+	actorID, exists := c.Get("user_id")
+	if !exists {
+		actorID = "system"
+	}
+
+	if err := h.domainService.RemoveDomain(c.Request.Context(), c.Param("tenant_id"), c.Param("hostname"), actorID.(string)); err != nil {
+		respondDomainError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "domain removed",
+	})
+}
+
+// GetSite returns the canonical base URL and the branding of the site served at the request host
+// @Summary Get public site by host
+// @Tags domains
+// @Produce json
+// @Success 200 {object} SiteInfoResponse
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/site [get]
+func (h *DomainHandler) GetSite(c *gin.Context) {
+	tenant := middleware.GetTenant(c)
+	if tenant == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "no site is configured for this domain",
+		})
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": SiteInfoResponse{
+			BaseURL:  h.domainService.SiteBaseURL(tenant),
+			Branding: tenant.PublicBranding(),
+		},
+	})
+}
+
+// newDomainResponse adds the verification record to a domain
+func newDomainResponse(domain *models.TenantDomain) DomainResponse {
+	return DomainResponse{
+		TenantDomain: domain,
+		VerificationRecord: DomainVerificationRecord{
+			Type:  "TXT",
+			Name:  domain.VerificationRecordName(),
+			Value: domain.VerificationRecordValue(),
+		},
+	}
+}
+
+// respondDomainError writes the error of a domain operation
+func respondDomainError(c *gin.Context, err error) {
+	status := http.StatusBadRequest
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, services.ErrDomainNotVerified), errors.Is(err, services.ErrDomainTaken):
+		status = http.StatusConflict
+	case errors.Is(err, services.ErrDomainClaimExpired):
+		status = http.StatusGone
+	}
+	c.JSON(status, gin.H{
+		"success": false,
+		"error":   err.Error(),
+	})
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -129,6 +130,9 @@ func (h *ImportHandler) ImportFromFiles(c *gin.Context) {
 	ctx := context.Background()
 	batch, err := h.importService.CreateBatch(ctx, tenantID.(string), source, createdBy)
 	if err != nil {
+		if respondPlanLimitError(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create import batch", "details": err.Error()})
 		return
 	}
@@ -274,7 +278,11 @@ func (h *ImportHandler) processImport(ctx context.Context, batch *models.ImportB
 				// Import property
 				if err := h.importService.ImportProperty(ctx, batch, payload); err != nil {
 					log.Printf("❌ Error importing property %s: %v", imovel.Referencia, err)
-					_ = h.importService.LogError(ctx, batch, "import_failed", err.Error(), map[string]interface{}{
+					errorType := "import_failed"
+					if errors.Is(err, services.ErrPlanLimitReached) {
+						errorType = "plan_limit_reached"
+					}
+					_ = h.importService.LogError(ctx, batch, errorType, err.Error(), map[string]interface{}{
 						"reference":    imovel.Referencia,
 						"external_id":  imovel.Codigoimovel,
 						"property_idx": propertyIdx,
@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// maxInboundEmailSize limits the size of a received e-mail (portal notifications are small; forwards may
+// carry the original as attachment)
+const maxInboundEmailSize = 10 << 20
+
+// InboundEmailHandler handles the lead notification e-mails of listing portals
+type InboundEmailHandler struct {
+	inboundService *services.EmailInboundService
+}
+
+// NewInboundEmailHandler creates a new inbound e-mail handler
+func NewInboundEmailHandler(inboundService *services.EmailInboundService) *InboundEmailHandler {
+	return &InboundEmailHandler{
+		inboundService: inboundService,
+	}
+}
+
+// RegisterWebhookRoutes registers the mail provider webhook (public, authenticated by the shared secret)
+func (h *InboundEmailHandler) RegisterWebhookRoutes(router *gin.RouterGroup) {
+	router.POST("/webhooks/email", h.ReceiveEmail)
+}
+
+// RegisterRoutes registers inbound e-mail routes (tenant-scoped)
+func (h *InboundEmailHandler) RegisterRoutes(router *gin.RouterGroup) {
+	router.POST("/leads/email", h.UploadEmail)
+	router.GET("/inbound-emails", h.ListInboundEmails)
+}
+
+// ReceiveEmail receives an e-mail forwarded by the mail provider (raw MIME)
+// @Summary Receive portal lead e-mail
+// @Description Mail provider webhook: parses ZAP, VivaReal and OLX lead notifications sent to leads+{tenant-slug}@... and creates the leads
+// @Tags inbound-emails
+// @Accept plain
+// @Produce json
+// @Param X-Inbound-Email-Secret header string true "Shared secret"
+// @Param recipient query string false "Envelope recipient (leads+{tenant-slug}@...)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/webhooks/email [post]
+func (h *InboundEmailHandler) ReceiveEmail(c *gin.Context) {
+	if err := h.inboundService.VerifySecret(c.GetHeader("X-Inbound-Email-Secret")); err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	raw, err := readRawEmail(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	recipient := c.Query("recipient")
+	if recipient == "" {
+		recipient = c.PostForm("recipient")
+	}
+
+	result, err := h.inboundService.ReceiveEmail(c.Request.Context(), raw, recipient)
+	if err != nil {
+		respondInboundEmailError(c, err)
+		return
+	}
+
+	// Unparsed and unmatched e-mails are acknowledged too (recorded for the tenant to review)
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    inboundEmailResultForResponse(c, result),
+	})
+}
+
+// UploadEmail ingests a portal lead e-mail uploaded by a broker (.eml file or raw MIME body)
+// @Summary Upload portal lead e-mail
+// @Description Creates the lead of a ZAP, VivaReal or OLX notification e-mail (.eml), or threads the message into the contact's existing lead
+// @Tags inbound-emails
+// @Accept multipart/form-data
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param file formData file false "E-mail (.eml)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 422 {object} map[string]interface{}
+// @Router /api/{tenant_id}/leads/email [post]
+func (h *InboundEmailHandler) UploadEmail(c *gin.Context) {
+	raw, err := readRawEmail(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	actorID, exists := c.Get("user_id")
+	if !exists {
+		actorID = "system"
+	}
+
+	result, err := h.inboundService.IngestEmail(c.Request.Context(), c.Param("tenant_id"), raw, actorID.(string))
+	if err != nil {
+		respondInboundEmailError(c, err)
+		return
+	}
+
+	if result.Email.Status == models.InboundEmailStatusUnparsed || result.Email.Status == models.InboundEmailStatusUnmatched {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"success": false,
+			"error":   result.Email.Error,
+			"data":    inboundEmailResultForResponse(c, result),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    inboundEmailResultForResponse(c, result),
+	})
+}
+
+// ListInboundEmails lists the portal e-mails received by the tenant
+// @Summary List inbound portal e-mails
+// @Description Received lead notification e-mails and their outcome (unmatched e-mails need the listing code on a property)
+// @Tags inbound-emails
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param status query string false "lead_created, lead_updated, unmatched, unparsed"
+// @Param limit query int false "Limit"
+// @Param offset query int false "Offset"
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/{tenant_id}/inbound-emails [get]
+func (h *InboundEmailHandler) ListInboundEmails(c *gin.Context) {
+	var status *models.InboundEmailStatus
+	if value := c.Query("status"); value != "" {
+		s := models.InboundEmailStatus(value)
+		status = &s
+	}
+
+	// Most recent first unless another order is requested
+	opts := parsePaginationOptions(c)
+	if c.Query("order_by") == "" {
+		opts.OrderBy = ""
+	}
+
+	emails, err := h.inboundService.ListInboundEmails(c.Request.Context(), c.Param("tenant_id"), status, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    emails,
+		"count":   len(emails),
+	})
+}
+
+// readRawEmail reads the raw MIME e-mail: the "file" (upload), "email" or "body-mime" (mail providers)
+// form field, else the request body
+func readRawEmail(c *gin.Context) ([]byte, error) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxInboundEmailSize)
+
+	if strings.HasPrefix(c.ContentType(), "multipart/form-data") {
+		if file, err := c.FormFile("file"); err == nil {
+			opened, err := file.Open()
+			if err != nil {
+				return nil, errors.New("failed to read file")
+			}
+			defer opened.Close()
+			return io.ReadAll(opened)
+		}
+		for _, key := range []string{"email", "body-mime"} {
+			if value := c.PostForm(key); value != "" {
+				return []byte(value), nil
+			}
+		}
+		return nil, errors.New("file is required")
+	}
+
+	raw, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return nil, errors.New("failed to read body")
+	}
+	if len(raw) == 0 {
+		return nil, errors.New("e-mail is required")
+	}
+	return raw, nil
+}
+
+// respondInboundEmailError writes the error of an inbound e-mail operation
+func respondInboundEmailError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrInboundEmailUnknownTenant):
+		status = http.StatusNotFound
+	case errors.Is(err, repositories.ErrInvalidInput):
+		status = http.StatusBadRequest
+	}
+	c.JSON(status, gin.H{
+		"success": false,
+		"error":   err.Error(),
+	})
+}
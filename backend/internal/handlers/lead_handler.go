@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/altatech/ecosistema-imob/backend/internal/models"
@@ -34,6 +35,7 @@ func (h *LeadHandler) RegisterRoutes(router *gin.RouterGroup) {
 		leads.POST("/:id/assign", h.AssignToBroker)
 		leads.POST("/:id/revoke-consent", h.RevokeConsent)
 		leads.POST("/:id/anonymize", h.AnonymizeLead)
+		leads.GET("/:id/messages", h.ListLeadMessages)
 	}
 
 	// PROMPT 07: Public endpoints for WhatsApp and Form leads
@@ -84,7 +86,7 @@ func (h *LeadHandler) CreateLead(c *gin.Context) {
 
 	c.JSON(http.StatusCreated, gin.H{
 		"success": true,
-		"data":    lead,
+		"data":    leadForResponse(c, &lead),
 	})
 }
 
@@ -121,7 +123,53 @@ func (h *LeadHandler) GetLead(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
-		"data":    lead,
+		"data":    leadForResponse(c, lead),
+	})
+}
+
+// ListLeadMessages lists the conversation history of a lead
+// @Summary List lead messages
+// @Description List the messages exchanged with a lead (WhatsApp...), oldest first
+// @Tags leads
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Lead ID"
+// @Param limit query int false "Limit"
+// @Param offset query int false "Offset"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/{tenant_id}/leads/{id}/messages [get]
+func (h *LeadHandler) ListLeadMessages(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	id := c.Param("id")
+
+	// Chronological order unless another order is requested
+	opts := parsePaginationOptions(c)
+	if c.Query("order_by") == "" {
+		opts.OrderBy = ""
+	}
+
+	messages, err := h.leadService.ListLeadMessages(c.Request.Context(), tenantID, id, opts)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   "lead not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    conversationMessagesForResponse(c, messages),
+		"count":   len(messages),
 	})
 }
 
@@ -216,7 +264,7 @@ func (h *LeadHandler) DeleteLead(c *gin.Context) {
 // @Produce json
 // @Param tenant_id path string true "Tenant ID"
 // @Param limit query int false "Limit" default(50)
-// @Param order_by query string false "Order by field" default(created_at)
+// @Param order_by query string false "Order by field (created_at, score: highest first)" default(created_at)
 // @Param property_id query string false "Property ID filter"
 // @Param status query string false "Status filter"
 // @Param channel query string false "Channel filter"
@@ -257,7 +305,7 @@ func (h *LeadHandler) ListLeads(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
-		"data":    leads,
+		"data":    leadsForResponse(c, leads),
 		"count":   len(leads),
 	})
 }
@@ -478,6 +526,56 @@ type CreateFormLeadRequest struct {
 	UTMCampaign  string `json:"utm_campaign,omitempty"`
 	UTMMedium    string `json:"utm_medium,omitempty"`
 	Referrer     string `json:"referrer,omitempty"`
+
+	// Qualification (lead scoring)
+	Budget             float64 `json:"budget,omitempty"`                            // BRL
+	FinancingSimulated bool    `json:"financing_simulated,omitempty"`               // the financing simulator was used
+	PropertiesViewed   int     `json:"properties_viewed,omitempty" binding:"min=0"` // distinct properties viewed in the site session (counted by the site)
+
+	// Optional purposes (separate, unchecked-by-default checkboxes on the form)
+	// Each checkbox shows its own text: a published version (0 = current) or the text itself
+	MarketingConsent          bool   `json:"marketing_consent,omitempty"`
+	MarketingConsentVersion   int    `json:"marketing_consent_version,omitempty"`
+	MarketingConsentText      string `json:"marketing_consent_text,omitempty"`
+	DataSharingConsent        bool   `json:"data_sharing_consent,omitempty"`
+	DataSharingConsentVersion int    `json:"data_sharing_consent_version,omitempty"`
+	DataSharingConsentText    string `json:"data_sharing_consent_text,omitempty"`
+}
+
+// ConsentPurposes returns the optional purposes accepted on the form (contact is implied)
+func (r *CreateFormLeadRequest) ConsentPurposes() []models.ConsentPurpose {
+	purposes := make([]models.ConsentPurpose, 0, 2)
+	if r.MarketingConsent {
+		purposes = append(purposes, models.ConsentPurposeMarketing)
+	}
+	if r.DataSharingConsent {
+		purposes = append(purposes, models.ConsentPurposeDataSharing)
+	}
+	return purposes
+}
+
+// ConsentTextVersions returns the published text version shown for each accepted optional purpose
+func (r *CreateFormLeadRequest) ConsentTextVersions() map[models.ConsentPurpose]int {
+	versions := make(map[models.ConsentPurpose]int)
+	if r.MarketingConsent && r.MarketingConsentVersion > 0 {
+		versions[models.ConsentPurposeMarketing] = r.MarketingConsentVersion
+	}
+	if r.DataSharingConsent && r.DataSharingConsentVersion > 0 {
+		versions[models.ConsentPurposeDataSharing] = r.DataSharingConsentVersion
+	}
+	return versions
+}
+
+// ConsentPurposeTexts returns the text shown for each accepted optional purpose (when not a published version)
+func (r *CreateFormLeadRequest) ConsentPurposeTexts() map[models.ConsentPurpose]string {
+	texts := make(map[models.ConsentPurpose]string)
+	if r.MarketingConsent && r.MarketingConsentText != "" {
+		texts[models.ConsentPurposeMarketing] = r.MarketingConsentText
+	}
+	if r.DataSharingConsent && r.DataSharingConsentText != "" {
+		texts[models.ConsentPurposeDataSharing] = r.DataSharingConsentText
+	}
+	return texts
 }
 
 // CreateWhatsAppLead creates a new lead from WhatsApp button click
@@ -522,18 +620,19 @@ func (h *LeadHandler) CreateWhatsAppLead(c *gin.Context) {
 
 	// Create lead with WhatsApp channel
 	lead := &models.Lead{
-		TenantID:     tenantID,
-		PropertyID:   propertyID,
-		Channel:      models.LeadChannelWhatsApp,
-		Name:         leadName,
-		Phone:        leadPhone,
-		ConsentGiven: true, // Implícito ao clicar no botão WhatsApp
-		ConsentText:  "Concordo com a Política de Privacidade e autorizo o uso dos meus dados para contato sobre este imóvel.",
-		ConsentIP:    clientIP,
-		UTMSource:    req.UTMSource,
-		UTMCampaign:  req.UTMCampaign,
-		UTMMedium:    req.UTMMedium,
-		Referrer:     req.Referrer,
+		TenantID:         tenantID,
+		PropertyID:       propertyID,
+		Channel:          models.LeadChannelWhatsApp,
+		Name:             leadName,
+		Phone:            leadPhone,
+		ConsentGiven:     true, // Implícito ao clicar no botão WhatsApp
+		ConsentText:      "Concordo com a Política de Privacidade e autorizo o uso dos meus dados para contato sobre este imóvel.",
+		ConsentIP:        clientIP,
+		ConsentUserAgent: c.Request.UserAgent(),
+		UTMSource:        req.UTMSource,
+		UTMCampaign:      req.UTMCampaign,
+		UTMMedium:        req.UTMMedium,
+		Referrer:         req.Referrer,
 	}
 
 	// Create lead (validates property exists)
@@ -559,6 +658,7 @@ func (h *LeadHandler) CreateWhatsAppLead(c *gin.Context) {
 		"success":      true,
 		"lead_id":      lead.ID,
 		"whatsapp_url": whatsappData.URL,
+		"short_url":    whatsappData.ShortURL,
 		"message":      whatsappData.Message,
 	})
 }
@@ -603,20 +703,27 @@ func (h *LeadHandler) CreateFormLead(c *gin.Context) {
 
 	// Create lead with form channel
 	lead := &models.Lead{
-		TenantID:     tenantID,
-		PropertyID:   propertyID,
-		Name:         req.Name,
-		Email:        req.Email,
-		Phone:        req.Phone,
-		Message:      req.Message,
-		Channel:      models.LeadChannelForm,
-		ConsentGiven: req.ConsentGiven,
-		ConsentText:  req.ConsentText,
-		ConsentIP:    clientIP,
-		UTMSource:    req.UTMSource,
-		UTMCampaign:  req.UTMCampaign,
-		UTMMedium:    req.UTMMedium,
-		Referrer:     req.Referrer,
+		TenantID:            tenantID,
+		PropertyID:          propertyID,
+		Name:                req.Name,
+		Email:               req.Email,
+		Phone:               req.Phone,
+		Message:             req.Message,
+		Channel:             models.LeadChannelForm,
+		ConsentGiven:        req.ConsentGiven,
+		ConsentText:         req.ConsentText,
+		ConsentIP:           clientIP,
+		ConsentUserAgent:    c.Request.UserAgent(),
+		ConsentPurposes:     req.ConsentPurposes(),
+		ConsentTextVersions: req.ConsentTextVersions(),
+		ConsentPurposeTexts: req.ConsentPurposeTexts(),
+		UTMSource:           req.UTMSource,
+		UTMCampaign:         req.UTMCampaign,
+		UTMMedium:           req.UTMMedium,
+		Referrer:            req.Referrer,
+		Budget:              req.Budget,
+		FinancingSimulated:  req.FinancingSimulated,
+		PropertiesViewed:    req.PropertiesViewed,
 	}
 
 	// Create lead (validates property exists and contact methods)
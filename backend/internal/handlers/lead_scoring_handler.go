@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// LeadScoringHandler handles lead score recalculation
+type LeadScoringHandler struct {
+	scoringService *services.LeadScoringService
+}
+
+// NewLeadScoringHandler creates a new lead scoring handler
+func NewLeadScoringHandler(scoringService *services.LeadScoringService) *LeadScoringHandler {
+	return &LeadScoringHandler{
+		scoringService: scoringService,
+	}
+}
+
+// RegisterRoutes registers lead scoring routes (tenant-scoped)
+func (h *LeadScoringHandler) RegisterRoutes(router *gin.RouterGroup) {
+	leads := router.Group("/leads")
+	{
+		leads.POST("/score", h.RescoreLeads)
+		leads.POST("/:id/score", h.ScoreLead)
+	}
+}
+
+// ScoreLead recalculates the score of a lead
+// @Summary Recalculate lead score
+// @Description Recalculates the 0-100 score of a lead and returns the lead with the contributing factors
+// @Tags leads
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Lead ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/{tenant_id}/leads/{id}/score [post]
+func (h *LeadScoringHandler) ScoreLead(c *gin.Context) {
+	lead, err := h.scoringService.ScoreLead(c.Request.Context(), c.Param("tenant_id"), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   "lead not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    leadForResponse(c, lead),
+	})
+}
+
+// RescoreLeads recalculates the scores of every lead of the tenant
+// @Summary Recalculate lead scores
+// @Description Recalculates the scores of every lead, page by page (time-based signals, leads without score). Meant for a daily cron job
+// @Tags leads
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param batch_size query int false "Leads read per page" default(500)
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/{tenant_id}/leads/score [post]
+func (h *LeadScoringHandler) RescoreLeads(c *gin.Context) {
+	batchSize, _ := strconv.Atoi(c.Query("batch_size"))
+
+	scored, err := h.scoringService.RescoreLeads(c.Request.Context(), c.Param("tenant_id"), batchSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"data":    gin.H{"scored": scored},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    gin.H{"scored": scored},
+	})
+}
@@ -62,6 +62,9 @@ func (h *ListingHandler) CreateListing(c *gin.Context) {
 	listing.TenantID = tenantID
 
 	if err := h.listingService.CreateListing(c.Request.Context(), &listing); err != nil {
+		if respondPlanLimitError(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
 			"error":   err.Error(),
@@ -140,6 +143,9 @@ func (h *ListingHandler) UpdateListing(c *gin.Context) {
 	}
 
 	if err := h.listingService.UpdateListing(c.Request.Context(), tenantID, id, updates); err != nil {
+		if respondPlanLimitError(c, err) {
+			return
+		}
 		if err == repositories.ErrNotFound {
 			c.JSON(http.StatusNotFound, gin.H{
 				"success": false,
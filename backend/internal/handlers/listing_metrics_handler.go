@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// ListingMetricsHandler handles engagement tracking and listing performance requests
+type ListingMetricsHandler struct {
+	metricsService *services.ListingMetricsService
+}
+
+// NewListingMetricsHandler creates a new listing metrics handler
+func NewListingMetricsHandler(metricsService *services.ListingMetricsService) *ListingMetricsHandler {
+	return &ListingMetricsHandler{
+		metricsService: metricsService,
+	}
+}
+
+// RegisterRoutes registers performance routes (tenant-scoped, admin)
+func (h *ListingMetricsHandler) RegisterRoutes(router *gin.RouterGroup) {
+	metrics := router.Group("/metrics")
+	{
+		metrics.GET("/properties/:property_id", h.GetPropertyPerformance)
+		metrics.GET("/listings/:listing_id", h.GetListingPerformance)
+		metrics.GET("/brokers/:broker_id", h.GetBrokerPerformance)
+	}
+}
+
+// TrackEventRequest represents an engagement event sent by the public frontends
+type TrackEventRequest struct {
+	PropertyID string                   `json:"property_id" binding:"required"`
+	ListingID  string                   `json:"listing_id,omitempty"`
+	EventType  models.TrackingEventType `json:"event_type" binding:"required"` // view, impression, whatsapp_click
+}
+
+// TrackEvent ingests a view/impression/click event for a tenant property
+// @Summary Track engagement event
+// @Description Lightweight ingestion of view, impression and WhatsApp click events (bots are ignored)
+// @Tags metrics
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param body body TrackEventRequest true "Event"
+// @Success 202 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/v1/{tenant_id}/events [post]
+func (h *ListingMetricsHandler) TrackEvent(c *gin.Context) {
+	h.trackEvent(c, c.Param("tenant_id"))
+}
+
+// TrackPublicEvent ingests an engagement event from the portal agregador (tenant resolved from property)
+// @Summary Track engagement event (cross-tenant)
+// @Tags metrics
+// @Accept json
+// @Produce json
+// @Param body body TrackEventRequest true "Event"
+// @Success 202 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/v1/public/events [post]
+func (h *ListingMetricsHandler) TrackPublicEvent(c *gin.Context) {
+	h.trackEvent(c, "")
+}
+
+func (h *ListingMetricsHandler) trackEvent(c *gin.Context, tenantID string) {
+	var req TrackEventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	tracked, err := h.metricsService.TrackEvent(c.Request.Context(), services.TrackEventInput{
+		TenantID:   tenantID,
+		PropertyID: req.PropertyID,
+		ListingID:  req.ListingID,
+		EventType:  req.EventType,
+		UserAgent:  c.Request.UserAgent(),
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"success": true,
+		"tracked": tracked,
+	})
+}
+
+// GetPropertyPerformance returns engagement and conversion for a property
+// @Summary Get property performance
+// @Tags metrics
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param property_id path string true "Property ID"
+// @Param from query string false "Start date (YYYY-MM-DD), default 30 days ago"
+// @Param to query string false "End date (YYYY-MM-DD), default today"
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/admin/{tenant_id}/metrics/properties/{property_id} [get]
+func (h *ListingMetricsHandler) GetPropertyPerformance(c *gin.Context) {
+	h.getPerformance(c, models.MetricScopeProperty, c.Param("property_id"))
+}
+
+// GetListingPerformance returns engagement and conversion (views → leads) for a listing
+// @Summary Get listing performance
+// @Tags metrics
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param listing_id path string true "Listing ID"
+// @Param from query string false "Start date (YYYY-MM-DD), default 30 days ago"
+// @Param to query string false "End date (YYYY-MM-DD), default today"
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/admin/{tenant_id}/metrics/listings/{listing_id} [get]
+func (h *ListingMetricsHandler) GetListingPerformance(c *gin.Context) {
+	h.getPerformance(c, models.MetricScopeListing, c.Param("listing_id"))
+}
+
+// GetBrokerPerformance returns engagement and conversion across a broker's listings
+// @Summary Get broker performance
+// @Tags metrics
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param broker_id path string true "Broker ID"
+// @Param from query string false "Start date (YYYY-MM-DD), default 30 days ago"
+// @Param to query string false "End date (YYYY-MM-DD), default today"
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/admin/{tenant_id}/metrics/brokers/{broker_id} [get]
+func (h *ListingMetricsHandler) GetBrokerPerformance(c *gin.Context) {
+	h.getPerformance(c, models.MetricScopeBroker, c.Param("broker_id"))
+}
+
+func (h *ListingMetricsHandler) getPerformance(c *gin.Context, scope models.MetricScope, entityID string) {
+	tenantID := c.Param("tenant_id")
+
+	from, to, err := parseDateRange(c, 30)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	performance, err := h.metricsService.GetPerformance(c.Request.Context(), tenantID, scope, entityID, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    performance,
+	})
+}
+
+// parseDateRange parses ?from=YYYY-MM-DD&to=YYYY-MM-DD in the rollup timezone (default: last defaultDays days)
+func parseDateRange(c *gin.Context, defaultDays int) (time.Time, time.Time, error) {
+	to := time.Now()
+	from := to.AddDate(0, 0, -defaultDays)
+
+	if fromStr := c.Query("from"); fromStr != "" {
+		parsed, err := services.ParseMetricsDate(fromStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid from date format. Use YYYY-MM-DD")
+		}
+		from = parsed
+	}
+
+	if toStr := c.Query("to"); toStr != "" {
+		parsed, err := services.ParseMetricsDate(toStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid to date format. Use YYYY-MM-DD")
+		}
+		to = parsed
+	}
+
+	return from, to, nil
+}
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/services"
+)
+
+func TestParseDateRangeExplicitRange(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", "/performance?from=2026-10-01&to=2026-10-15", nil)
+
+	from, to, err := parseDateRange(c, 30)
+	require.NoError(t, err)
+
+	// The rollup day keys are the requested days (not the day before)
+	assert.Equal(t, "2026-10-01", services.MetricsDate(from))
+	assert.Equal(t, "2026-10-15", services.MetricsDate(to))
+}
+
+func TestParseDateRangeInvalidDate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", "/performance?from=01/10/2026", nil)
+
+	_, _, err := parseDateRange(c, 30)
+	assert.Error(t, err)
+}
@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// MarketAnalyticsHandler handles market analytics HTTP requests
+type MarketAnalyticsHandler struct {
+	analyticsService *services.MarketAnalyticsService
+}
+
+// NewMarketAnalyticsHandler creates a new market analytics handler
+func NewMarketAnalyticsHandler(analyticsService *services.MarketAnalyticsService) *MarketAnalyticsHandler {
+	return &MarketAnalyticsHandler{
+		analyticsService: analyticsService,
+	}
+}
+
+// RegisterRoutes registers market analytics routes (tenant-scoped)
+func (h *MarketAnalyticsHandler) RegisterRoutes(router *gin.RouterGroup) {
+	analytics := router.Group("/analytics")
+	{
+		analytics.GET("/market", h.GetTenantSnapshot)
+		analytics.GET("/market/export", h.ExportTenantSnapshot)
+		analytics.POST("/market/snapshots", h.GenerateTenantSnapshot)
+		analytics.GET("/marketplace", h.GetMarketplaceSnapshot)
+		analytics.GET("/marketplace/export", h.ExportMarketplaceSnapshot)
+	}
+}
+
+// GenerateSnapshotRequest represents the request body for snapshot generation
+type GenerateSnapshotRequest struct {
+	Period string `json:"period"` // YYYY-MM (default: previous month)
+}
+
+// GetTenantSnapshot returns the tenant market snapshot
+// @Summary Get market analytics
+// @Description Median price/m², absorption, days on market and rent yield by neighborhood and type
+// @Tags analytics
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param period query string false "Period (YYYY-MM), default latest"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/admin/{tenant_id}/analytics/market [get]
+func (h *MarketAnalyticsHandler) GetTenantSnapshot(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+
+	snapshot, err := h.analyticsService.GetTenantSnapshot(c.Request.Context(), tenantID, c.Query("period"))
+	if err != nil {
+		respondSnapshotError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    snapshot,
+	})
+}
+
+// ExportTenantSnapshot exports the tenant market snapshot as CSV
+// @Summary Export market analytics (CSV)
+// @Tags analytics
+// @Produce text/csv
+// @Param tenant_id path string true "Tenant ID"
+// @Param period query string false "Period (YYYY-MM), default latest"
+// @Router /api/v1/admin/{tenant_id}/analytics/market/export [get]
+func (h *MarketAnalyticsHandler) ExportTenantSnapshot(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+
+	snapshot, err := h.analyticsService.GetTenantSnapshot(c.Request.Context(), tenantID, c.Query("period"))
+	if err != nil {
+		respondSnapshotError(c, err)
+		return
+	}
+
+	writeSnapshotCSV(c, snapshot, fmt.Sprintf("mercado-%s.csv", snapshot.Period))
+}
+
+// GenerateTenantSnapshot (re)computes the tenant snapshot for a period
+// This can be called by a cron job monthly
+// @Summary Generate market snapshot
+// @Tags analytics
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param body body GenerateSnapshotRequest false "Period"
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/admin/{tenant_id}/analytics/market/snapshots [post]
+func (h *MarketAnalyticsHandler) GenerateTenantSnapshot(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+
+	var req GenerateSnapshotRequest
+	// Body is optional
+	_ = c.ShouldBindJSON(&req)
+
+	snapshot, err := h.analyticsService.GenerateTenantSnapshot(c.Request.Context(), tenantID, req.Period)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    snapshot,
+	})
+}
+
+// GetMarketplaceSnapshot returns the anonymized marketplace snapshot
+// @Summary Get marketplace analytics
+// @Description Anonymized cross-tenant indicators (segments with at least 3 agencies)
+// @Tags analytics
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param period query string false "Period (YYYY-MM), default latest"
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/admin/{tenant_id}/analytics/marketplace [get]
+func (h *MarketAnalyticsHandler) GetMarketplaceSnapshot(c *gin.Context) {
+	snapshot, err := h.analyticsService.GetMarketplaceSnapshot(c.Request.Context(), c.Query("period"))
+	if err != nil {
+		respondSnapshotError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    snapshot,
+	})
+}
+
+// ExportMarketplaceSnapshot exports the marketplace snapshot as CSV
+// @Summary Export marketplace analytics (CSV)
+// @Tags analytics
+// @Produce text/csv
+// @Param tenant_id path string true "Tenant ID"
+// @Param period query string false "Period (YYYY-MM), default latest"
+// @Router /api/v1/admin/{tenant_id}/analytics/marketplace/export [get]
+func (h *MarketAnalyticsHandler) ExportMarketplaceSnapshot(c *gin.Context) {
+	snapshot, err := h.analyticsService.GetMarketplaceSnapshot(c.Request.Context(), c.Query("period"))
+	if err != nil {
+		respondSnapshotError(c, err)
+		return
+	}
+
+	writeSnapshotCSV(c, snapshot, fmt.Sprintf("mercado-marketplace-%s.csv", snapshot.Period))
+}
+
+// respondSnapshotError maps snapshot lookup errors to HTTP responses
+func respondSnapshotError(c *gin.Context, err error) {
+	if err == repositories.ErrNotFound {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "market snapshot not found for period",
+		})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"success": false,
+		"error":   err.Error(),
+	})
+}
+
+// writeSnapshotCSV writes a snapshot as a CSV attachment
+func writeSnapshotCSV(c *gin.Context, snapshot *models.MarketSnapshot, filename string) {
+	var buf bytes.Buffer
+	if err := services.WriteSnapshotCSV(&buf, snapshot); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// OutboxHandler handles the transactional outbox endpoints (relay and failed events)
+type OutboxHandler struct {
+	outboxRelay *services.OutboxRelay
+}
+
+// NewOutboxHandler creates a new outbox handler
+func NewOutboxHandler(outboxRelay *services.OutboxRelay) *OutboxHandler {
+	return &OutboxHandler{
+		outboxRelay: outboxRelay,
+	}
+}
+
+// RegisterRoutes registers outbox routes (tenant-scoped, admin)
+func (h *OutboxHandler) RegisterRoutes(router *gin.RouterGroup) {
+	outbox := router.Group("/outbox")
+	{
+		outbox.POST("/relay", h.Relay)
+		outbox.GET("/events", h.ListEvents)
+		outbox.POST("/events/:event_id/retry", h.RetryEvent)
+	}
+}
+
+// Relay publishes the pending events whose next attempt is due
+// This endpoint should be called by a cron job every minute
+// @Summary Relay outbox events
+// @Tags outbox
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param limit query int false "Maximum events (default 200)"
+// @Success 200 {object} services.OutboxRelayResult
+// @Router /api/v1/admin/{tenant_id}/outbox/relay [post]
+func (h *OutboxHandler) Relay(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	result, err := h.outboxRelay.RelayPending(c.Request.Context(), c.Param("tenant_id"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    result,
+	})
+}
+
+// ListEvents lists outbox events by status
+// @Summary List outbox events
+// @Tags outbox
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param status query string false "pending, published or failed (default failed)"
+// @Param limit query int false "Maximum events (default 50)"
+// @Success 200 {array} models.OutboxEvent
+// @Router /api/v1/admin/{tenant_id}/outbox/events [get]
+func (h *OutboxHandler) ListEvents(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	events, err := h.outboxRelay.ListEvents(c.Request.Context(), c.Param("tenant_id"), models.OutboxEventStatus(c.Query("status")), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    events,
+		"count":   len(events),
+	})
+}
+
+// RetryEvent publishes a failed event again (consumers that already processed it are skipped)
+// @Summary Retry failed outbox event
+// @Tags outbox
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param event_id path string true "Event ID"
+// @Success 200 {object} models.OutboxEvent
+// @Router /api/v1/admin/{tenant_id}/outbox/events/{event_id}/retry [post]
+func (h *OutboxHandler) RetryEvent(c *gin.Context) {
+	event, err := h.outboxRelay.RetryEvent(c.Request.Context(), c.Param("tenant_id"), c.Param("event_id"))
+	if err != nil {
+		if err == repositories.ErrNotFound {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   "outbox event not found",
+			})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    event,
+	})
+}
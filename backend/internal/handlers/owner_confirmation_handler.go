@@ -29,6 +29,9 @@ func (h *OwnerConfirmationHandler) RegisterPublicRoutes(router *gin.Engine) {
 
 	// Public confirmation submission - POST /api/v1/owner-confirmations/{token}/submit
 	router.POST("/api/v1/owner-confirmations/:token/submit", h.SubmitConfirmation)
+
+	// Public monthly owner report - GET /api/v1/owner-confirmations/{token}/report
+	router.GET("/api/v1/owner-confirmations/:token/report", h.GetOwnerReport)
 }
 
 // GetConfirmationPage validates token and returns minimal property info
@@ -165,3 +168,48 @@ func (h *OwnerConfirmationHandler) SubmitConfirmation(c *gin.Context) {
 		"message": "Obrigado! Informação atualizada com sucesso.",
 	})
 }
+
+// GetOwnerReport returns the monthly performance report of the token's property
+// GET /api/v1/owner-confirmations/{token}/report
+// @Summary Get owner monthly report
+// @Description Returns the monthly report (views, leads by channel, visits, price vs. neighborhood median) as JSON, HTML or PDF
+// @Tags owner-confirmation
+// @Produce json,html,application/pdf
+// @Param token path string true "Confirmation Token"
+// @Param tenant_id query string true "Tenant ID"
+// @Param period query string false "Period (YYYY-MM), default latest"
+// @Param format query string false "json (default), html or pdf"
+// @Success 200 {object} models.OwnerReport
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/owner-confirmations/{token}/report [get]
+func (h *OwnerConfirmationHandler) GetOwnerReport(c *gin.Context) {
+	token := c.Param("token")
+	tenantID := c.Query("tenant_id")
+
+	if tenantID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "tenant_id is required",
+		})
+		return
+	}
+
+	report, err := h.ownerConfirmationService.GetOwnerReportByToken(c.Request.Context(), tenantID, token, c.Query("period"))
+	if err != nil {
+		if err == repositories.ErrNotFound {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   "report not found",
+			})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	writeOwnerReport(c, report, c.DefaultQuery("format", "json"))
+}
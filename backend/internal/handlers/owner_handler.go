@@ -72,7 +72,7 @@ func (h *OwnerHandler) CreateOwner(c *gin.Context) {
 
 	c.JSON(http.StatusCreated, gin.H{
 		"success": true,
-		"data":    owner,
+		"data":    ownerForResponse(c, &owner),
 	})
 }
 
@@ -109,7 +109,7 @@ func (h *OwnerHandler) GetOwner(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
-		"data":    owner,
+		"data":    ownerForResponse(c, owner),
 	})
 }
 
@@ -225,7 +225,7 @@ func (h *OwnerHandler) ListOwners(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
-		"data":    owners,
+		"data":    ownersForResponse(c, owners),
 		"count":   len(owners),
 	})
 }
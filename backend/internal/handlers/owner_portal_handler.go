@@ -0,0 +1,510 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/services"
+	"github.com/altatech/ecosistema-imob/backend/internal/storage"
+	"github.com/gin-gonic/gin"
+)
+
+// ownerPortalSessionKey is the gin context key of the authenticated owner portal session
+const ownerPortalSessionKey = "owner_portal_session"
+
+// OwnerPortalHandler handles owner self-service portal requests
+type OwnerPortalHandler struct {
+	ownerPortalService *services.OwnerPortalService
+}
+
+// NewOwnerPortalHandler creates a new owner portal handler
+func NewOwnerPortalHandler(ownerPortalService *services.OwnerPortalService) *OwnerPortalHandler {
+	return &OwnerPortalHandler{
+		ownerPortalService: ownerPortalService,
+	}
+}
+
+// RegisterRoutes registers owner portal routes
+// Router group must be /api/v1/owner-portal/:tenant_id (no Firebase auth: owners use magic link sessions)
+func (h *OwnerPortalHandler) RegisterRoutes(router *gin.RouterGroup) {
+	// Public - magic link flow
+	router.POST("/magic-link", h.RequestMagicLink)
+	router.POST("/sessions", h.CreateSession)
+
+	// Authenticated by owner session (Authorization: Bearer <session_token>)
+	authenticated := router.Group("")
+	authenticated.Use(h.RequireOwnerSession())
+	{
+		authenticated.GET("/me", h.GetProfile)
+		authenticated.POST("/logout", h.Logout)
+		authenticated.GET("/properties", h.ListProperties)
+		authenticated.PATCH("/properties/:id/price", h.UpdatePrice)
+		authenticated.PATCH("/properties/:id/availability", h.UpdateAvailability)
+		authenticated.GET("/properties/:id/documents", h.ListDocuments)
+		authenticated.POST("/properties/:id/documents", h.UploadDocument)
+		authenticated.GET("/documents/:document_id/url", h.GetDocumentURL)
+		authenticated.PUT("/consent", h.UpdateConsent)
+		authenticated.GET("/consents", h.GetConsents)
+		authenticated.POST("/consents/:purpose", h.UpdatePurposeConsent)
+	}
+}
+
+// RequestOwnerMagicLinkRequest represents a magic link request (email or phone)
+type RequestOwnerMagicLinkRequest struct {
+	Email string `json:"email,omitempty"`
+	Phone string `json:"phone,omitempty"` // Link enviado por WhatsApp
+}
+
+// CreateOwnerSessionRequest represents the exchange of a magic link for a session
+type CreateOwnerSessionRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// UpdateOwnerPriceRequest represents a price update by the owner
+type UpdateOwnerPriceRequest struct {
+	PriceAmount float64 `json:"price_amount" binding:"required"`
+}
+
+// UpdateOwnerAvailabilityRequest represents an availability update by the owner
+type UpdateOwnerAvailabilityRequest struct {
+	Available *bool `json:"available" binding:"required"`
+}
+
+// UpdateOwnerConsentRequest represents the owner giving or revoking LGPD consent
+type UpdateOwnerConsentRequest struct {
+	ConsentGiven *bool  `json:"consent_given" binding:"required"`
+	ConsentText  string `json:"consent_text,omitempty"` // Texto exibido ao proprietário
+}
+
+// UpdateOwnerPurposeConsentRequest represents a grant or withdrawal of one consent purpose
+type UpdateOwnerPurposeConsentRequest struct {
+	Granted     *bool `json:"granted" binding:"required"`
+	TextVersion int   `json:"text_version,omitempty"` // Versão publicada exibida (0 = atual)
+}
+
+// RequireOwnerSession validates the owner portal session token
+func (h *OwnerPortalHandler) RequireOwnerSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authHeader := c.GetHeader("Authorization")
+		token := strings.TrimPrefix(authHeader, "Bearer ")
+		if authHeader == "" || token == authHeader {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"success": false,
+				"error":   "owner session token is required",
+			})
+			c.Abort()
+			return
+		}
+
+		session, err := h.ownerPortalService.Authenticate(c.Request.Context(), c.Param("tenant_id"), token)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"success": false,
+				"error":   "invalid or expired session",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Set(ownerPortalSessionKey, session)
+		c.Set("owner_id", session.OwnerID)
+		c.Next()
+	}
+}
+
+// RequestMagicLink sends a single-use access link to the owner
+// Always returns 200 to avoid revealing which contacts are registered (400 for phone requests when the
+// tenant cannot send WhatsApp messages)
+// @Summary Request owner portal magic link
+// @Tags owner-portal
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param body body RequestOwnerMagicLinkRequest true "Email or phone"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/v1/owner-portal/{tenant_id}/magic-link [post]
+func (h *OwnerPortalHandler) RequestMagicLink(c *gin.Context) {
+	var req RequestOwnerMagicLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	err := h.ownerPortalService.RequestMagicLink(c.Request.Context(), services.RequestOwnerMagicLinkInput{
+		TenantID: c.Param("tenant_id"),
+		Email:    req.Email,
+		Phone:    req.Phone,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "If the contact is registered, an access link has been sent",
+	})
+}
+
+// CreateSession exchanges a magic link token for a portal session
+// @Summary Start owner portal session
+// @Tags owner-portal
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param body body CreateOwnerSessionRequest true "Magic link token"
+// @Success 201 {object} services.OwnerPortalSessionResult
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/v1/owner-portal/{tenant_id}/sessions [post]
+func (h *OwnerPortalHandler) CreateSession(c *gin.Context) {
+	var req CreateOwnerSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	result, err := h.ownerPortalService.ExchangeMagicLink(c.Request.Context(), c.Param("tenant_id"), req.Token, c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    result,
+	})
+}
+
+// GetProfile returns the authenticated owner profile
+// @Summary Get owner portal profile
+// @Tags owner-portal
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Success 200 {object} services.OwnerPortalProfile
+// @Router /api/v1/owner-portal/{tenant_id}/me [get]
+func (h *OwnerPortalHandler) GetProfile(c *gin.Context) {
+	profile, err := h.ownerPortalService.GetProfile(c.Request.Context(), ownerPortalSession(c))
+	if err != nil {
+		respondOwnerPortalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    profile,
+	})
+}
+
+// Logout ends the owner portal session
+// @Summary End owner portal session
+// @Tags owner-portal
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/owner-portal/{tenant_id}/logout [post]
+func (h *OwnerPortalHandler) Logout(c *gin.Context) {
+	if err := h.ownerPortalService.Logout(c.Request.Context(), ownerPortalSession(c)); err != nil {
+		respondOwnerPortalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Session ended successfully",
+	})
+}
+
+// ListProperties lists the owner's properties with lead and visit counts
+// @Summary List owner properties
+// @Tags owner-portal
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Success 200 {array} services.OwnerPortalProperty
+// @Router /api/v1/owner-portal/{tenant_id}/properties [get]
+func (h *OwnerPortalHandler) ListProperties(c *gin.Context) {
+	properties, err := h.ownerPortalService.ListProperties(c.Request.Context(), ownerPortalSession(c))
+	if err != nil {
+		respondOwnerPortalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    properties,
+		"count":   len(properties),
+	})
+}
+
+// UpdatePrice updates the price of an owned property
+// @Summary Update property price (owner)
+// @Tags owner-portal
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Property ID"
+// @Param body body UpdateOwnerPriceRequest true "New price"
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/owner-portal/{tenant_id}/properties/{id}/price [patch]
+func (h *OwnerPortalHandler) UpdatePrice(c *gin.Context) {
+	var req UpdateOwnerPriceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	if err := h.ownerPortalService.UpdatePrice(c.Request.Context(), ownerPortalSession(c), c.Param("id"), req.PriceAmount); err != nil {
+		respondOwnerPortalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Price updated successfully",
+	})
+}
+
+// UpdateAvailability marks an owned property as available or unavailable
+// @Summary Update property availability (owner)
+// @Tags owner-portal
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Property ID"
+// @Param body body UpdateOwnerAvailabilityRequest true "Availability"
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/owner-portal/{tenant_id}/properties/{id}/availability [patch]
+func (h *OwnerPortalHandler) UpdateAvailability(c *gin.Context) {
+	var req UpdateOwnerAvailabilityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	if err := h.ownerPortalService.UpdateAvailability(c.Request.Context(), ownerPortalSession(c), c.Param("id"), *req.Available); err != nil {
+		respondOwnerPortalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Availability updated successfully",
+	})
+}
+
+// ListDocuments lists the documents of an owned property
+// @Summary List property documents (owner)
+// @Tags owner-portal
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Property ID"
+// @Success 200 {array} models.PropertyDocument
+// @Router /api/v1/owner-portal/{tenant_id}/properties/{id}/documents [get]
+func (h *OwnerPortalHandler) ListDocuments(c *gin.Context) {
+	documents, err := h.ownerPortalService.ListDocuments(c.Request.Context(), ownerPortalSession(c), c.Param("id"))
+	if err != nil {
+		respondOwnerPortalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    documents,
+		"count":   len(documents),
+	})
+}
+
+// UploadDocument uploads a document (PDF/JPEG/PNG) for an owned property
+// @Summary Upload property document (owner)
+// @Tags owner-portal
+// @Accept multipart/form-data
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Property ID"
+// @Param file formData file true "Document file"
+// @Param category formData string false "matricula, iptu, habite_se, condominium_statement, owner_id, other"
+// @Success 201 {object} models.PropertyDocument
+// @Failure 413 {object} map[string]interface{}
+// @Failure 415 {object} map[string]interface{}
+// @Router /api/v1/owner-portal/{tenant_id}/properties/{id}/documents [post]
+func (h *OwnerPortalHandler) UploadDocument(c *gin.Context) {
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "file is required",
+		})
+		return
+	}
+	defer file.Close()
+
+	document, err := h.ownerPortalService.UploadDocument(c.Request.Context(), ownerPortalSession(c), c.Param("id"), models.DocumentCategory(c.PostForm("category")), file, header)
+	if err != nil {
+		switch err {
+		case storage.ErrFileTooLarge:
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+		case storage.ErrInvalidDocumentType:
+			c.JSON(http.StatusUnsupportedMediaType, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+		default:
+			respondOwnerPortalError(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    document,
+	})
+}
+
+// GetDocumentURL returns a short-lived download URL for a document of an owned property
+// @Summary Get document download URL (owner)
+// @Tags owner-portal
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param document_id path string true "Document ID"
+// @Success 200 {object} services.DocumentURLResponse
+// @Router /api/v1/owner-portal/{tenant_id}/documents/{document_id}/url [get]
+func (h *OwnerPortalHandler) GetDocumentURL(c *gin.Context) {
+	response, err := h.ownerPortalService.GetDocumentURL(c.Request.Context(), ownerPortalSession(c), c.Param("document_id"))
+	if err != nil {
+		respondOwnerPortalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    response,
+	})
+}
+
+// UpdateConsent gives or revokes the owner's LGPD consent
+// @Summary Give or revoke LGPD consent (owner)
+// @Tags owner-portal
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param body body UpdateOwnerConsentRequest true "Consent"
+// @Success 200 {object} services.OwnerPortalProfile
+// @Router /api/v1/owner-portal/{tenant_id}/consent [put]
+func (h *OwnerPortalHandler) UpdateConsent(c *gin.Context) {
+	var req UpdateOwnerConsentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	profile, err := h.ownerPortalService.UpdateConsent(c.Request.Context(), ownerPortalSession(c), *req.ConsentGiven, req.ConsentText)
+	if err != nil {
+		respondOwnerPortalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    profile,
+	})
+}
+
+// GetConsents returns the current consent of the owner for every purpose
+// @Summary Get owner consents per purpose
+// @Tags owner-portal
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Success 200 {array} models.ConsentState
+// @Router /api/v1/owner-portal/{tenant_id}/consents [get]
+func (h *OwnerPortalHandler) GetConsents(c *gin.Context) {
+	states, err := h.ownerPortalService.GetConsents(c.Request.Context(), ownerPortalSession(c))
+	if err != nil {
+		respondOwnerPortalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    states,
+	})
+}
+
+// UpdatePurposeConsent grants or withdraws one consent purpose (contact, marketing, data_sharing)
+// @Summary Update owner consent for a purpose
+// @Tags owner-portal
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param purpose path string true "Purpose (contact, marketing, data_sharing)"
+// @Param body body UpdateOwnerPurposeConsentRequest true "Consent"
+// @Success 200 {array} models.ConsentState
+// @Router /api/v1/owner-portal/{tenant_id}/consents/{purpose} [post]
+func (h *OwnerPortalHandler) UpdatePurposeConsent(c *gin.Context) {
+	var req UpdateOwnerPurposeConsentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	states, err := h.ownerPortalService.UpdatePurposeConsent(c.Request.Context(), ownerPortalSession(c), models.ConsentPurpose(c.Param("purpose")), *req.Granted, req.TextVersion)
+	if err != nil {
+		respondOwnerPortalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    states,
+	})
+}
+
+// ownerPortalSession returns the session set by RequireOwnerSession
+func ownerPortalSession(c *gin.Context) *models.OwnerPortalToken {
+	session, _ := c.MustGet(ownerPortalSessionKey).(*models.OwnerPortalToken)
+	return session
+}
+
+// respondOwnerPortalError maps owner portal errors to HTTP responses
+func respondOwnerPortalError(c *gin.Context, err error) {
+	if err == repositories.ErrNotFound {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusBadRequest, gin.H{
+		"success": false,
+		"error":   err.Error(),
+	})
+}
@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// OwnerReportHandler handles monthly owner report requests
+type OwnerReportHandler struct {
+	ownerReportService *services.OwnerReportService
+}
+
+// NewOwnerReportHandler creates a new owner report handler
+func NewOwnerReportHandler(ownerReportService *services.OwnerReportService) *OwnerReportHandler {
+	return &OwnerReportHandler{
+		ownerReportService: ownerReportService,
+	}
+}
+
+// RegisterRoutes registers owner report routes (tenant-scoped, admin)
+func (h *OwnerReportHandler) RegisterRoutes(router *gin.RouterGroup) {
+	router.POST("/owner-reports/generate", h.GenerateReports)
+
+	properties := router.Group("/properties")
+	{
+		properties.GET("/:id/owner-reports", h.GetReport)
+		properties.POST("/:id/owner-reports", h.GenerateReport)
+		properties.POST("/:id/visits", h.RecordVisit)
+	}
+}
+
+// GenerateOwnerReportRequest represents the request body for report generation
+type GenerateOwnerReportRequest struct {
+	Period string `json:"period"` // YYYY-MM (default: previous month)
+}
+
+// RecordVisitRequest represents a completed visit to a property
+type RecordVisitRequest struct {
+	LeadID    string    `json:"lead_id,omitempty"`
+	VisitedAt time.Time `json:"visited_at,omitempty"` // default: now
+}
+
+// GenerateReports generates the monthly reports of all owned properties
+// This endpoint should be called by a cron job monthly, before scheduled confirmations are sent
+// @Summary Generate monthly owner reports
+// @Tags owner-reports
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param body body GenerateOwnerReportRequest false "Period"
+// @Success 200 {object} services.GenerateOwnerReportsResponse
+// @Router /api/v1/admin/{tenant_id}/owner-reports/generate [post]
+func (h *OwnerReportHandler) GenerateReports(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+
+	var req GenerateOwnerReportRequest
+	_ = c.ShouldBindJSON(&req) // Body is optional
+
+	response, err := h.ownerReportService.GenerateForTenant(c.Request.Context(), tenantID, req.Period)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    response,
+	})
+}
+
+// GenerateReport generates (or regenerates) the monthly report of a property
+// @Summary Generate owner report for a property
+// @Tags owner-reports
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Property ID"
+// @Param body body GenerateOwnerReportRequest false "Period"
+// @Success 200 {object} models.OwnerReport
+// @Router /api/v1/admin/{tenant_id}/properties/{id}/owner-reports [post]
+func (h *OwnerReportHandler) GenerateReport(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	propertyID := c.Param("id")
+
+	var req GenerateOwnerReportRequest
+	_ = c.ShouldBindJSON(&req) // Body is optional
+
+	report, err := h.ownerReportService.GenerateReport(c.Request.Context(), tenantID, propertyID, req.Period)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    report,
+	})
+}
+
+// GetReport returns the monthly report of a property
+// @Summary Get owner report for a property
+// @Tags owner-reports
+// @Produce json,html,application/pdf
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Property ID"
+// @Param period query string false "Period (YYYY-MM), default latest"
+// @Param format query string false "json (default), html or pdf"
+// @Success 200 {object} models.OwnerReport
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/admin/{tenant_id}/properties/{id}/owner-reports [get]
+func (h *OwnerReportHandler) GetReport(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	propertyID := c.Param("id")
+
+	report, err := h.ownerReportService.GetReport(c.Request.Context(), tenantID, propertyID, c.Query("period"))
+	if err != nil {
+		if err == repositories.ErrNotFound {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   "owner report not found for period",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	writeOwnerReport(c, report, c.DefaultQuery("format", "json"))
+}
+
+// RecordVisit registers a completed visit (counted in the owner report)
+// @Summary Record property visit
+// @Tags owner-reports
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Property ID"
+// @Param body body RecordVisitRequest false "Visit data"
+// @Success 201 {object} map[string]interface{}
+// @Router /api/v1/admin/{tenant_id}/properties/{id}/visits [post]
+func (h *OwnerReportHandler) RecordVisit(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	propertyID := c.Param("id")
+
+	var req RecordVisitRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
+	}
+
+	actorID, exists := c.Get("user_id")
+	if !exists {
+		actorID = "system"
+	}
+
+	if err := h.ownerReportService.RecordVisit(c.Request.Context(), tenantID, propertyID, actorID.(string), req.LeadID, req.VisitedAt); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Visit recorded successfully",
+	})
+}
+
+// writeOwnerReport writes a report as JSON, HTML or PDF
+func writeOwnerReport(c *gin.Context, report *models.OwnerReport, format string) {
+	var buf bytes.Buffer
+
+	switch format {
+	case "html":
+		if err := services.RenderOwnerReportHTML(&buf, report); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
+		c.Data(http.StatusOK, "text/html; charset=utf-8", buf.Bytes())
+
+	case "pdf":
+		if err := services.WriteOwnerReportPDF(&buf, report); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
+		filename := fmt.Sprintf("relatorio_%s_%s.pdf", report.PropertyReference, report.Period)
+		if report.PropertyReference == "" {
+			filename = fmt.Sprintf("relatorio_%s.pdf", report.Period)
+		}
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+		c.Data(http.StatusOK, "application/pdf", buf.Bytes())
+
+	default:
+		c.JSON(http.StatusOK, gin.H{
+			"success": true,
+			"data":    report,
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/middleware"
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/services"
+	"github.com/altatech/ecosistema-imob/backend/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// ownerForResponse masks the owner's PII for callers without PII permission
+func ownerForResponse(c *gin.Context, owner *models.Owner) *models.Owner {
+	if owner == nil || middleware.CanViewPII(c) {
+		return owner
+	}
+
+	masked := *owner
+	masked.Email = utils.MaskEmail(owner.Email)
+	masked.Phone = utils.MaskPhone(owner.Phone)
+	masked.Document = utils.MaskDocument(owner.Document)
+	return &masked
+}
+
+// ownersForResponse masks a list of owners (see ownerForResponse)
+func ownersForResponse(c *gin.Context, owners []*models.Owner) []*models.Owner {
+	if middleware.CanViewPII(c) {
+		return owners
+	}
+
+	masked := make([]*models.Owner, 0, len(owners))
+	for _, owner := range owners {
+		masked = append(masked, ownerForResponse(c, owner))
+	}
+	return masked
+}
+
+// leadForResponse masks the lead's PII for callers without PII permission
+func leadForResponse(c *gin.Context, lead *models.Lead) *models.Lead {
+	if lead == nil || middleware.CanViewPII(c) {
+		return lead
+	}
+
+	masked := *lead
+	masked.Email = utils.MaskEmail(lead.Email)
+	masked.Phone = utils.MaskPhone(lead.Phone)
+	return &masked
+}
+
+// leadsForResponse masks a list of leads (see leadForResponse)
+func leadsForResponse(c *gin.Context, leads []*models.Lead) []*models.Lead {
+	if middleware.CanViewPII(c) {
+		return leads
+	}
+
+	masked := make([]*models.Lead, 0, len(leads))
+	for _, lead := range leads {
+		masked = append(masked, leadForResponse(c, lead))
+	}
+	return masked
+}
+
+// userForResponse masks the user's document for callers without PII permission
+func userForResponse(c *gin.Context, user *models.User) *models.User {
+	if user == nil || middleware.CanViewPII(c) {
+		return user
+	}
+
+	masked := *user
+	masked.Document = utils.MaskDocument(user.Document)
+	return &masked
+}
+
+// usersForResponse masks a list of users (see userForResponse)
+func usersForResponse(c *gin.Context, users []*models.User) []*models.User {
+	if middleware.CanViewPII(c) {
+		return users
+	}
+
+	masked := make([]*models.User, 0, len(users))
+	for _, user := range users {
+		masked = append(masked, userForResponse(c, user))
+	}
+	return masked
+}
+
+// dataSubjectRequestForResponse masks the subject's contact data and document for callers without PII permission
+func dataSubjectRequestForResponse(c *gin.Context, request *models.DataSubjectRequest) *models.DataSubjectRequest {
+	if request == nil || middleware.CanViewPII(c) {
+		return request
+	}
+
+	masked := *request
+	masked.SubjectEmail = utils.MaskEmail(request.SubjectEmail)
+	masked.SubjectPhone = utils.MaskPhone(request.SubjectPhone)
+	masked.SubjectDocument = utils.MaskDocument(request.SubjectDocument)
+	return &masked
+}
+
+// dataSubjectRequestsForResponse masks a list of data subject requests (see dataSubjectRequestForResponse)
+func dataSubjectRequestsForResponse(c *gin.Context, requests []*models.DataSubjectRequest) []*models.DataSubjectRequest {
+	if middleware.CanViewPII(c) {
+		return requests
+	}
+
+	masked := make([]*models.DataSubjectRequest, 0, len(requests))
+	for _, request := range requests {
+		masked = append(masked, dataSubjectRequestForResponse(c, request))
+	}
+	return masked
+}
+
+// conversationMessagesForResponse masks the sender and recipient of conversation messages for callers without PII permission
+func conversationMessagesForResponse(c *gin.Context, messages []*models.ConversationMessage) []*models.ConversationMessage {
+	if middleware.CanViewPII(c) {
+		return messages
+	}
+
+	masked := make([]*models.ConversationMessage, 0, len(messages))
+	for _, message := range messages {
+		copied := *message
+		copied.From = maskMessageAddress(message.From)
+		copied.To = maskMessageAddress(message.To)
+		masked = append(masked, &copied)
+	}
+	return masked
+}
+
+// conversationMessageForResponse masks a single conversation message (see conversationMessagesForResponse)
+func conversationMessageForResponse(c *gin.Context, message *models.ConversationMessage) *models.ConversationMessage {
+	return conversationMessagesForResponse(c, []*models.ConversationMessage{message})[0]
+}
+
+// maskMessageAddress masks the sender/recipient of a message: e-mail address or phone
+func maskMessageAddress(address string) string {
+	if strings.Contains(address, "@") {
+		return utils.MaskEmail(address)
+	}
+	return utils.MaskPhone(address)
+}
+
+// inboundEmailResultForResponse masks the lead of an inbound e-mail result (see leadForResponse)
+func inboundEmailResultForResponse(c *gin.Context, result *services.EmailIngestResult) *services.EmailIngestResult {
+	if result.Lead == nil || middleware.CanViewPII(c) {
+		return result
+	}
+
+	masked := *result
+	masked.Lead = leadForResponse(c, result.Lead)
+	return &masked
+}
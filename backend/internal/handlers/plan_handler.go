@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// PlanHandler handles subscription plans, usage and the trial lifecycle
+type PlanHandler struct {
+	planService *services.PlanService
+}
+
+// NewPlanHandler creates a new plan handler
+func NewPlanHandler(planService *services.PlanService) *PlanHandler {
+	return &PlanHandler{
+		planService: planService,
+	}
+}
+
+// RegisterRoutes registers plan routes (tenant-scoped, admin)
+func (h *PlanHandler) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/plan", h.GetPlanUsage)
+}
+
+// RegisterJobRoutes registers the subscription jobs (not tenant-scoped, the router must require a platform admin)
+func (h *PlanHandler) RegisterJobRoutes(router *gin.RouterGroup) {
+	router.POST("/jobs/expire-trials", h.ExpireTrials)
+}
+
+// GetPlanUsage returns the effective plan of the tenant with its quotas, features and current usage
+// @Summary Get plan and usage
+// @Tags plans
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Success 200 {object} services.PlanUsage
+// @Router /api/v1/admin/{tenant_id}/plan [get]
+func (h *PlanHandler) GetPlanUsage(c *gin.Context) {
+	usage, err := h.planService.GetUsage(c.Request.Context(), c.Param("tenant_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    usage,
+	})
+}
+
+// ExpireTrials moves the tenants whose trial ended to the expired status
+// This endpoint should be called by a cron job daily, authenticated as a platform admin
+// @Summary Expire ended trials
+// @Tags plans
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/v1/admin/jobs/expire-trials [post]
+func (h *PlanHandler) ExpireTrials(c *gin.Context) {
+	expired, err := h.planService.ExpireTrials(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    gin.H{"expired": expired},
+	})
+}
+
+// respondPlanLimitError responds 402 Payment Required when err was caused by the tenant's plan
+// Returns false (and responds nothing) for other errors
+func respondPlanLimitError(c *gin.Context, err error) bool {
+	var limitErr *services.PlanLimitError
+	if !errors.As(err, &limitErr) {
+		return false
+	}
+
+	body := gin.H{
+		"success": false,
+		"error":   limitErr.Error(),
+		"code":    "plan_limit_reached",
+		"plan":    limitErr.Plan,
+	}
+	if limitErr.Feature != "" {
+		body["code"] = "plan_feature_unavailable"
+		body["feature"] = limitErr.Feature
+	} else {
+		body["resource"] = limitErr.Resource
+		body["limit"] = limitErr.Limit
+	}
+	if limitErr.Plan == models.SubscriptionPlanFree {
+		body["upgrade_to"] = models.SubscriptionPlanFull
+	}
+
+	c.JSON(http.StatusPaymentRequired, body)
+	return true
+}
@@ -0,0 +1,325 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/services"
+	"github.com/altatech/ecosistema-imob/backend/internal/storage"
+	"github.com/gin-gonic/gin"
+)
+
+// PropertyDocumentHandler handles property document vault requests
+type PropertyDocumentHandler struct {
+	documentService *services.PropertyDocumentService
+}
+
+// NewPropertyDocumentHandler creates a new property document handler
+func NewPropertyDocumentHandler(documentService *services.PropertyDocumentService) *PropertyDocumentHandler {
+	return &PropertyDocumentHandler{
+		documentService: documentService,
+	}
+}
+
+// RegisterRoutes registers property document routes (tenant-scoped, admin)
+func (h *PropertyDocumentHandler) RegisterRoutes(router *gin.RouterGroup) {
+	properties := router.Group("/properties")
+	{
+		properties.POST("/:id/documents", h.UploadDocument)
+		properties.GET("/:id/documents", h.ListDocuments)
+		properties.GET("/:id/documents/checklist", h.GetChecklist)
+	}
+
+	documents := router.Group("/documents")
+	{
+		documents.GET("/expiring", h.ListExpiring)
+		documents.GET("/:document_id", h.GetDocument)
+		documents.GET("/:document_id/url", h.GetDocumentURL)
+		documents.PATCH("/:document_id", h.UpdateDocument)
+		documents.DELETE("/:document_id", h.DeleteDocument)
+	}
+}
+
+// UploadDocument uploads a document to the property vault
+// @Summary Upload property document
+// @Tags property-documents
+// @Accept multipart/form-data
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Property ID"
+// @Param file formData file true "Document file (PDF/JPEG/PNG)"
+// @Param category formData string true "matricula, iptu, habite_se, condominium_statement, owner_id, sale_authorization, other"
+// @Param expires_at formData string false "Expiry date (YYYY-MM-DD)"
+// @Param description formData string false "Description"
+// @Success 201 {object} models.PropertyDocument
+// @Failure 413 {object} map[string]interface{}
+// @Failure 415 {object} map[string]interface{}
+// @Router /api/v1/admin/{tenant_id}/properties/{id}/documents [post]
+func (h *PropertyDocumentHandler) UploadDocument(c *gin.Context) {
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "file is required",
+		})
+		return
+	}
+	defer file.Close()
+
+	expiresAt, err := services.ParseDocumentExpiry(c.PostForm("expires_at"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	actorID, exists := c.Get("user_id")
+	if !exists {
+		actorID = "system"
+	}
+
+	document, err := h.documentService.UploadDocument(c.Request.Context(), services.UploadPropertyDocumentInput{
+		TenantID:       c.Param("tenant_id"),
+		PropertyID:     c.Param("id"),
+		Category:       models.DocumentCategory(c.PostForm("category")),
+		Description:    c.PostForm("description"),
+		ExpiresAt:      expiresAt,
+		File:           file,
+		Header:         header,
+		UploadedByType: models.ActorTypeUser,
+		UploadedByID:   actorID.(string),
+	})
+	if err != nil {
+		respondDocumentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    document,
+	})
+}
+
+// ListDocuments lists the documents of a property
+// @Summary List property documents
+// @Tags property-documents
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Property ID"
+// @Param category query string false "Filter by category"
+// @Success 200 {array} models.PropertyDocument
+// @Router /api/v1/admin/{tenant_id}/properties/{id}/documents [get]
+func (h *PropertyDocumentHandler) ListDocuments(c *gin.Context) {
+	documents, err := h.documentService.ListDocuments(c.Request.Context(), c.Param("tenant_id"), c.Param("id"), models.DocumentCategory(c.Query("category")))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    documents,
+		"count":   len(documents),
+	})
+}
+
+// GetChecklist returns the due-diligence checklist of a property
+// @Summary Get property document checklist
+// @Description Shows which documents (per property type) are missing or expired before a sale can close
+// @Tags property-documents
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Property ID"
+// @Success 200 {object} services.DocumentChecklist
+// @Router /api/v1/admin/{tenant_id}/properties/{id}/documents/checklist [get]
+func (h *PropertyDocumentHandler) GetChecklist(c *gin.Context) {
+	checklist, err := h.documentService.GetChecklist(c.Request.Context(), c.Param("tenant_id"), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    checklist,
+	})
+}
+
+// ListExpiring lists documents expired or expiring within the given days
+// @Summary List expiring documents
+// @Tags property-documents
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param days query int false "Days ahead (default 30)"
+// @Success 200 {array} models.PropertyDocument
+// @Router /api/v1/admin/{tenant_id}/documents/expiring [get]
+func (h *PropertyDocumentHandler) ListExpiring(c *gin.Context) {
+	days, _ := strconv.Atoi(c.DefaultQuery("days", "30"))
+
+	documents, err := h.documentService.ListExpiring(c.Request.Context(), c.Param("tenant_id"), days)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    documents,
+		"count":   len(documents),
+	})
+}
+
+// GetDocument retrieves a document record
+// @Summary Get property document
+// @Tags property-documents
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param document_id path string true "Document ID"
+// @Success 200 {object} models.PropertyDocument
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/admin/{tenant_id}/documents/{document_id} [get]
+func (h *PropertyDocumentHandler) GetDocument(c *gin.Context) {
+	document, err := h.documentService.GetDocument(c.Request.Context(), c.Param("tenant_id"), c.Param("document_id"))
+	if err != nil {
+		respondDocumentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    document,
+	})
+}
+
+// GetDocumentURL returns a short-lived signed URL to download a document
+// @Summary Get document download URL
+// @Tags property-documents
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param document_id path string true "Document ID"
+// @Success 200 {object} services.DocumentURLResponse
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/admin/{tenant_id}/documents/{document_id}/url [get]
+func (h *PropertyDocumentHandler) GetDocumentURL(c *gin.Context) {
+	document, err := h.documentService.GetDocument(c.Request.Context(), c.Param("tenant_id"), c.Param("document_id"))
+	if err != nil {
+		respondDocumentError(c, err)
+		return
+	}
+
+	actorID, exists := c.Get("user_id")
+	if !exists {
+		actorID = "system"
+	}
+
+	response, err := h.documentService.GetDownloadURL(c.Request.Context(), document, models.ActorTypeUser, actorID.(string))
+	if err != nil {
+		respondDocumentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    response,
+	})
+}
+
+// UpdateDocument updates the category, description or expiry date of a document
+// @Summary Update property document
+// @Tags property-documents
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param document_id path string true "Document ID"
+// @Param updates body map[string]interface{} true "category, description, expires_at"
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/admin/{tenant_id}/documents/{document_id} [patch]
+func (h *PropertyDocumentHandler) UpdateDocument(c *gin.Context) {
+	var updates map[string]interface{}
+	if err := c.ShouldBindJSON(&updates); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	actorID, exists := c.Get("user_id")
+	if !exists {
+		actorID = "system"
+	}
+
+	if err := h.documentService.UpdateDocument(c.Request.Context(), c.Param("tenant_id"), c.Param("document_id"), updates, actorID.(string)); err != nil {
+		respondDocumentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Document updated successfully",
+	})
+}
+
+// DeleteDocument removes a document from the vault
+// @Summary Delete property document
+// @Tags property-documents
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param document_id path string true "Document ID"
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/admin/{tenant_id}/documents/{document_id} [delete]
+func (h *PropertyDocumentHandler) DeleteDocument(c *gin.Context) {
+	actorID, exists := c.Get("user_id")
+	if !exists {
+		actorID = "system"
+	}
+
+	if err := h.documentService.DeleteDocument(c.Request.Context(), c.Param("tenant_id"), c.Param("document_id"), actorID.(string)); err != nil {
+		respondDocumentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Document deleted successfully",
+	})
+}
+
+// respondDocumentError maps document vault errors to HTTP responses
+func respondDocumentError(c *gin.Context, err error) {
+	switch err {
+	case repositories.ErrNotFound, storage.ErrDocumentNotFound:
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "document not found",
+		})
+	case storage.ErrFileTooLarge:
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+	case storage.ErrInvalidDocumentType:
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+	}
+}
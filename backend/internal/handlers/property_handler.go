@@ -69,6 +69,9 @@ func (h *PropertyHandler) CreateProperty(c *gin.Context) {
 	property.TenantID = tenantID
 
 	if err := h.propertyService.CreateProperty(c.Request.Context(), &property); err != nil {
+		if respondPlanLimitError(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
 			"error":   err.Error(),
@@ -168,6 +171,8 @@ func (h *PropertyHandler) GetPropertyBySlug(c *gin.Context) {
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
+// @Failure 402 {object} map[string]interface{} "Plan limit reached"
+// @Failure 422 {object} map[string]interface{} "Sale authorization required"
 // @Failure 500 {object} map[string]interface{}
 // @Router /api/{tenant_id}/properties/{id} [put]
 func (h *PropertyHandler) UpdateProperty(c *gin.Context) {
@@ -184,6 +189,16 @@ func (h *PropertyHandler) UpdateProperty(c *gin.Context) {
 	}
 
 	if err := h.propertyService.UpdateProperty(c.Request.Context(), tenantID, id, updates); err != nil {
+		if respondPlanLimitError(c, err) {
+			return
+		}
+		if err == services.ErrSaleAuthorizationRequired {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
 		if err == repositories.ErrNotFound {
 			c.JSON(http.StatusNotFound, gin.H{
 				"success": false,
@@ -390,6 +405,8 @@ type UpdateVisibilityRequest struct {
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
+// @Failure 402 {object} map[string]interface{} "Plan limit reached"
+// @Failure 422 {object} map[string]interface{} "Sale authorization required"
 // @Failure 500 {object} map[string]interface{}
 // @Router /api/{tenant_id}/properties/{id}/visibility [post]
 func (h *PropertyHandler) UpdateVisibility(c *gin.Context) {
@@ -406,6 +423,16 @@ func (h *PropertyHandler) UpdateVisibility(c *gin.Context) {
 	}
 
 	if err := h.propertyService.UpdateVisibility(c.Request.Context(), tenantID, id, req.Visibility); err != nil {
+		if respondPlanLimitError(c, err) {
+			return
+		}
+		if err == services.ErrSaleAuthorizationRequired {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
 		if err == repositories.ErrNotFound {
 			c.JSON(http.StatusNotFound, gin.H{
 				"success": false,
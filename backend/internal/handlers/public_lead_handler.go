@@ -85,18 +85,19 @@ func (h *PublicLeadHandler) CreatePublicWhatsAppLead(c *gin.Context) {
 	// Create lead with WhatsApp channel
 	// Use tenant_id from property
 	lead := &models.Lead{
-		TenantID:     property.TenantID,
-		PropertyID:   propertyID,
-		Channel:      models.LeadChannelWhatsApp,
-		Name:         leadName,
-		Phone:        leadPhone,
-		ConsentGiven: true, // Implícito ao clicar no botão WhatsApp
-		ConsentText:  "Concordo com a Política de Privacidade e autorizo o uso dos meus dados para contato sobre este imóvel.",
-		ConsentIP:    clientIP,
-		UTMSource:    req.UTMSource,
-		UTMCampaign:  req.UTMCampaign,
-		UTMMedium:    req.UTMMedium,
-		Referrer:     req.Referrer,
+		TenantID:         property.TenantID,
+		PropertyID:       propertyID,
+		Channel:          models.LeadChannelWhatsApp,
+		Name:             leadName,
+		Phone:            leadPhone,
+		ConsentGiven:     true, // Implícito ao clicar no botão WhatsApp
+		ConsentText:      "Concordo com a Política de Privacidade e autorizo o uso dos meus dados para contato sobre este imóvel.",
+		ConsentIP:        clientIP,
+		ConsentUserAgent: c.Request.UserAgent(),
+		UTMSource:        req.UTMSource,
+		UTMCampaign:      req.UTMCampaign,
+		UTMMedium:        req.UTMMedium,
+		Referrer:         req.Referrer,
 	}
 
 	// Create lead (validates property exists)
@@ -122,6 +123,7 @@ func (h *PublicLeadHandler) CreatePublicWhatsAppLead(c *gin.Context) {
 		"success":      true,
 		"lead_id":      lead.ID,
 		"whatsapp_url": whatsappData.URL,
+		"short_url":    whatsappData.ShortURL,
 		"message":      whatsappData.Message,
 	})
 }
@@ -185,20 +187,24 @@ func (h *PublicLeadHandler) CreatePublicFormLead(c *gin.Context) {
 	// Create lead with form channel
 	// Use tenant_id from property
 	lead := &models.Lead{
-		TenantID:     property.TenantID,
-		PropertyID:   propertyID,
-		Name:         req.Name,
-		Email:        req.Email,
-		Phone:        req.Phone,
-		Message:      req.Message,
-		Channel:      models.LeadChannelForm,
-		ConsentGiven: req.ConsentGiven,
-		ConsentText:  req.ConsentText,
-		ConsentIP:    clientIP,
-		UTMSource:    req.UTMSource,
-		UTMCampaign:  req.UTMCampaign,
-		UTMMedium:    req.UTMMedium,
-		Referrer:     req.Referrer,
+		TenantID:            property.TenantID,
+		PropertyID:          propertyID,
+		Name:                req.Name,
+		Email:               req.Email,
+		Phone:               req.Phone,
+		Message:             req.Message,
+		Channel:             models.LeadChannelForm,
+		ConsentGiven:        req.ConsentGiven,
+		ConsentText:         req.ConsentText,
+		ConsentIP:           clientIP,
+		ConsentUserAgent:    c.Request.UserAgent(),
+		ConsentPurposes:     req.ConsentPurposes(),
+		ConsentTextVersions: req.ConsentTextVersions(),
+		ConsentPurposeTexts: req.ConsentPurposeTexts(),
+		UTMSource:           req.UTMSource,
+		UTMCampaign:         req.UTMCampaign,
+		UTMMedium:           req.UTMMedium,
+		Referrer:            req.Referrer,
 	}
 
 	// Create lead (validates property exists and contact methods)
@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// RetentionHandler handles LGPD retention policy endpoints
+type RetentionHandler struct {
+	retentionService *services.RetentionService
+}
+
+// NewRetentionHandler creates a new retention handler
+func NewRetentionHandler(retentionService *services.RetentionService) *RetentionHandler {
+	return &RetentionHandler{
+		retentionService: retentionService,
+	}
+}
+
+// RegisterRoutes registers retention routes (tenant-scoped, admin)
+func (h *RetentionHandler) RegisterRoutes(router *gin.RouterGroup) {
+	retention := router.Group("/retention")
+	{
+		retention.GET("/policy", h.GetPolicy)
+		retention.POST("/sweep", h.Sweep)
+		retention.GET("/runs", h.ListRuns)
+		retention.GET("/runs/:run_id", h.GetRun)
+	}
+}
+
+// RetentionSweepRequest represents the request body for a retention sweep
+type RetentionSweepRequest struct {
+	DryRun    bool `json:"dry_run"`              // If true, only reports what would change
+	BatchSize int  `json:"batch_size,omitempty"` // Records per rule (default 200, max 1000)
+}
+
+// GetPolicy returns the effective retention policy (tenant settings with defaults)
+// @Summary Get retention policy
+// @Tags retention
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Success 200 {object} models.RetentionPolicy
+// @Router /api/v1/admin/{tenant_id}/retention/policy [get]
+func (h *RetentionHandler) GetPolicy(c *gin.Context) {
+	policy, err := h.retentionService.GetPolicy(c.Request.Context(), c.Param("tenant_id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    policy,
+	})
+}
+
+// Sweep applies one batch of the retention rules (or reports it, in dry-run)
+// This endpoint should be called by a cron job daily
+// @Summary Run retention sweep
+// @Tags retention
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param body body RetentionSweepRequest false "Sweep options"
+// @Success 200 {object} models.RetentionRun
+// @Router /api/v1/admin/{tenant_id}/retention/sweep [post]
+func (h *RetentionHandler) Sweep(c *gin.Context) {
+	var req RetentionSweepRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "Invalid request body",
+			})
+			return
+		}
+	}
+
+	actorID, exists := c.Get("user_id")
+	if !exists {
+		actorID = "system"
+	}
+
+	run, err := h.retentionService.Sweep(c.Request.Context(), services.RetentionSweepInput{
+		TenantID:    c.Param("tenant_id"),
+		DryRun:      req.DryRun,
+		BatchSize:   req.BatchSize,
+		TriggeredBy: actorID.(string),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    run,
+	})
+}
+
+// ListRuns lists the most recent retention sweeps
+// @Summary List retention sweeps
+// @Tags retention
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param limit query int false "Maximum runs (default 20)"
+// @Success 200 {array} models.RetentionRun
+// @Router /api/v1/admin/{tenant_id}/retention/runs [get]
+func (h *RetentionHandler) ListRuns(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	runs, err := h.retentionService.ListRuns(c.Request.Context(), c.Param("tenant_id"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    runs,
+		"count":   len(runs),
+	})
+}
+
+// GetRun retrieves a retention sweep report
+// @Summary Get retention sweep
+// @Tags retention
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param run_id path string true "Run ID"
+// @Success 200 {object} models.RetentionRun
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/admin/{tenant_id}/retention/runs/{run_id} [get]
+func (h *RetentionHandler) GetRun(c *gin.Context) {
+	run, err := h.retentionService.GetRun(c.Request.Context(), c.Param("tenant_id"), c.Param("run_id"))
+	if err != nil {
+		if err == repositories.ErrNotFound {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   "retention run not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    run,
+	})
+}
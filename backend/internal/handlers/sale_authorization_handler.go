@@ -0,0 +1,281 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/services"
+	"github.com/altatech/ecosistema-imob/backend/internal/storage"
+	"github.com/gin-gonic/gin"
+)
+
+// SaleAuthorizationHandler handles sale authorization ("autorização de venda") requests
+type SaleAuthorizationHandler struct {
+	saleAuthorizationService *services.SaleAuthorizationService
+}
+
+// NewSaleAuthorizationHandler creates a new sale authorization handler
+func NewSaleAuthorizationHandler(saleAuthorizationService *services.SaleAuthorizationService) *SaleAuthorizationHandler {
+	return &SaleAuthorizationHandler{
+		saleAuthorizationService: saleAuthorizationService,
+	}
+}
+
+// RegisterRoutes registers sale authorization routes (tenant-scoped, admin)
+func (h *SaleAuthorizationHandler) RegisterRoutes(router *gin.RouterGroup) {
+	properties := router.Group("/properties")
+	{
+		properties.POST("/:id/sale-authorizations", h.CreateAuthorization)
+		properties.GET("/:id/sale-authorizations", h.ListAuthorizations)
+	}
+
+	authorizations := router.Group("/sale-authorizations")
+	{
+		authorizations.POST("/process-expirations", h.ProcessExpirations)
+		authorizations.GET("/:authorization_id", h.GetAuthorization)
+		authorizations.POST("/:authorization_id/document", h.AttachSignedDocument)
+		authorizations.POST("/:authorization_id/revoke", h.RevokeAuthorization)
+	}
+}
+
+// CreateSaleAuthorizationRequest represents the request body for a new sale authorization
+type CreateSaleAuthorizationRequest struct {
+	BrokerID             string    `json:"broker_id,omitempty"` // default: originating broker
+	IsExclusive          bool      `json:"is_exclusive"`
+	CommissionPercentage float64   `json:"commission_percentage"`
+	StartDate            time.Time `json:"start_date" binding:"required"`
+	EndDate              time.Time `json:"end_date" binding:"required"`
+	Notes                string    `json:"notes,omitempty"`
+}
+
+// RevokeSaleAuthorizationRequest represents the request body for revoking an authorization
+type RevokeSaleAuthorizationRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// CreateAuthorization registers a sale authorization for a property
+// @Summary Create sale authorization
+// @Tags sale-authorizations
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Property ID"
+// @Param body body CreateSaleAuthorizationRequest true "Authorization data"
+// @Success 201 {object} models.SaleAuthorization
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/v1/admin/{tenant_id}/properties/{id}/sale-authorizations [post]
+func (h *SaleAuthorizationHandler) CreateAuthorization(c *gin.Context) {
+	var req CreateSaleAuthorizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	actorID, exists := c.Get("user_id")
+	if !exists {
+		actorID = "system"
+	}
+
+	authorization, err := h.saleAuthorizationService.CreateAuthorization(c.Request.Context(), services.CreateSaleAuthorizationInput{
+		TenantID:             c.Param("tenant_id"),
+		PropertyID:           c.Param("id"),
+		BrokerID:             req.BrokerID,
+		IsExclusive:          req.IsExclusive,
+		CommissionPercentage: req.CommissionPercentage,
+		StartDate:            req.StartDate,
+		EndDate:              req.EndDate,
+		Notes:                req.Notes,
+	}, actorID.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    authorization,
+	})
+}
+
+// ListAuthorizations lists the sale authorizations of a property
+// @Summary List sale authorizations of a property
+// @Tags sale-authorizations
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Property ID"
+// @Success 200 {array} models.SaleAuthorization
+// @Router /api/v1/admin/{tenant_id}/properties/{id}/sale-authorizations [get]
+func (h *SaleAuthorizationHandler) ListAuthorizations(c *gin.Context) {
+	authorizations, err := h.saleAuthorizationService.ListByProperty(c.Request.Context(), c.Param("tenant_id"), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    authorizations,
+		"count":   len(authorizations),
+	})
+}
+
+// GetAuthorization retrieves a sale authorization
+// @Summary Get sale authorization
+// @Tags sale-authorizations
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param authorization_id path string true "Authorization ID"
+// @Success 200 {object} models.SaleAuthorization
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/admin/{tenant_id}/sale-authorizations/{authorization_id} [get]
+func (h *SaleAuthorizationHandler) GetAuthorization(c *gin.Context) {
+	authorization, err := h.saleAuthorizationService.GetAuthorization(c.Request.Context(), c.Param("tenant_id"), c.Param("authorization_id"))
+	if err != nil {
+		respondSaleAuthorizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    authorization,
+	})
+}
+
+// AttachSignedDocument uploads the signed authorization document
+// @Summary Upload signed sale authorization
+// @Tags sale-authorizations
+// @Accept multipart/form-data
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param authorization_id path string true "Authorization ID"
+// @Param file formData file true "Signed document (PDF/JPEG/PNG)"
+// @Success 200 {object} models.SaleAuthorization
+// @Failure 413 {object} map[string]interface{}
+// @Failure 415 {object} map[string]interface{}
+// @Router /api/v1/admin/{tenant_id}/sale-authorizations/{authorization_id}/document [post]
+func (h *SaleAuthorizationHandler) AttachSignedDocument(c *gin.Context) {
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "file is required",
+		})
+		return
+	}
+	defer file.Close()
+
+	actorID, exists := c.Get("user_id")
+	if !exists {
+		actorID = "system"
+	}
+
+	authorization, err := h.saleAuthorizationService.AttachSignedDocument(c.Request.Context(), c.Param("tenant_id"), c.Param("authorization_id"), file, header, actorID.(string))
+	if err != nil {
+		switch err {
+		case storage.ErrFileTooLarge:
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+		case storage.ErrInvalidDocumentType:
+			c.JSON(http.StatusUnsupportedMediaType, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+		default:
+			respondSaleAuthorizationError(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    authorization,
+	})
+}
+
+// RevokeAuthorization cancels a sale authorization before its end date
+// @Summary Revoke sale authorization
+// @Tags sale-authorizations
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param authorization_id path string true "Authorization ID"
+// @Param body body RevokeSaleAuthorizationRequest true "Reason"
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/admin/{tenant_id}/sale-authorizations/{authorization_id}/revoke [post]
+func (h *SaleAuthorizationHandler) RevokeAuthorization(c *gin.Context) {
+	var req RevokeSaleAuthorizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	actorID, exists := c.Get("user_id")
+	if !exists {
+		actorID = "system"
+	}
+
+	if err := h.saleAuthorizationService.RevokeAuthorization(c.Request.Context(), c.Param("tenant_id"), c.Param("authorization_id"), req.Reason, actorID.(string)); err != nil {
+		respondSaleAuthorizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Sale authorization revoked successfully",
+	})
+}
+
+// ProcessExpirations sends expiry alerts to brokers and expires lapsed authorizations
+// This endpoint should be called by a cron job daily
+// @Summary Process sale authorization expirations
+// @Tags sale-authorizations
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Success 200 {object} services.SaleAuthorizationExpiryResponse
+// @Router /api/v1/admin/{tenant_id}/sale-authorizations/process-expirations [post]
+func (h *SaleAuthorizationHandler) ProcessExpirations(c *gin.Context) {
+	response, err := h.saleAuthorizationService.ProcessExpirations(c.Request.Context(), c.Param("tenant_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    response,
+	})
+}
+
+// respondSaleAuthorizationError maps sale authorization errors to HTTP responses
+func respondSaleAuthorizationError(c *gin.Context, err error) {
+	if err == repositories.ErrNotFound {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "sale authorization not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusBadRequest, gin.H{
+		"success": false,
+		"error":   err.Error(),
+	})
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/altatech/ecosistema-imob/backend/internal/models"
@@ -35,6 +36,129 @@ func (h *TenantHandler) RegisterRoutes(router *gin.Engine) {
 	}
 }
 
+// RegisterBrandingRoutes registers the branding routes (tenant-scoped, admin)
+func (h *TenantHandler) RegisterBrandingRoutes(router *gin.RouterGroup) {
+	router.GET("/branding", h.GetBranding)
+	router.PUT("/branding", h.UpdateBranding)
+}
+
+// RegisterPublicRoutes registers the public tenant routes (no authentication)
+func (h *TenantHandler) RegisterPublicRoutes(router *gin.RouterGroup) {
+	router.GET("/tenants/:slug/branding", h.GetPublicBranding)
+}
+
+// GetBranding returns the stored branding of the tenant and the resolved branding with the fallbacks applied
+// @Summary Get tenant branding
+// @Tags tenants
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/admin/{tenant_id}/branding [get]
+func (h *TenantHandler) GetBranding(c *gin.Context) {
+	branding, resolved, err := h.tenantService.GetBranding(c.Request.Context(), c.Param("tenant_id"))
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   "tenant not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"branding": branding,
+			"resolved": resolved,
+		},
+	})
+}
+
+// UpdateBranding replaces the branding of the tenant
+// @Summary Update tenant branding
+// @Tags tenants
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param branding body models.TenantBranding true "Branding"
+// @Success 200 {object} models.PublicTenantBranding
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/v1/admin/{tenant_id}/branding [put]
+func (h *TenantHandler) UpdateBranding(c *gin.Context) {
+	var branding models.TenantBranding
+	if err := c.ShouldBindJSON(&branding); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	actorID, exists := c.Get("user_id")
+	if !exists {
+		actorID = "system"
+	}
+
+	resolved, err := h.tenantService.UpdateBranding(c.Request.Context(), c.Param("tenant_id"), &branding, actorID.(string))
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   "tenant not found",
+			})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    resolved,
+	})
+}
+
+// GetPublicBranding returns the resolved branding of a tenant by slug (logo, colors, fonts, contacts, legal footer)
+// @Summary Get public tenant branding
+// @Tags public
+// @Produce json
+// @Param slug path string true "Tenant slug"
+// @Success 200 {object} models.PublicTenantBranding
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/public/tenants/{slug}/branding [get]
+func (h *TenantHandler) GetPublicBranding(c *gin.Context) {
+	branding, err := h.tenantService.GetPublicBranding(c.Request.Context(), c.Param("slug"))
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   "tenant not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    branding,
+	})
+}
+
 // CreateTenant creates a new tenant
 // @Summary Create a new tenant
 // @Description Create a new tenant (real estate agency)
@@ -139,6 +263,13 @@ func (h *TenantHandler) UpdateTenant(c *gin.Context) {
 			})
 			return
 		}
+		if errors.Is(err, services.ErrTenantFieldReadOnly) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
 			"error":   err.Error(),
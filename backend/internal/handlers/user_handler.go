@@ -57,7 +57,7 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusCreated, user)
+	c.JSON(http.StatusCreated, userForResponse(c, &user))
 }
 
 // GetUser handles GET /api/v1/admin/:tenant_id/users/:userId
@@ -71,7 +71,7 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, user)
+	c.JSON(http.StatusOK, userForResponse(c, user))
 }
 
 // ListUsers handles GET /api/v1/admin/:tenant_id/users
@@ -96,7 +96,7 @@ func (h *UserHandler) ListUsers(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, users)
+	c.JSON(http.StatusOK, usersForResponse(c, users))
 }
 
 // UpdateUser handles PUT /api/v1/admin/:tenant_id/users/:userId
@@ -122,7 +122,7 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, user)
+	c.JSON(http.StatusOK, userForResponse(c, user))
 }
 
 // DeleteUser handles DELETE /api/v1/admin/:tenant_id/users/:userId
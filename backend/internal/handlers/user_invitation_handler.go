@@ -220,7 +220,7 @@ func (h *UserInvitationHandler) InviteUser(c *gin.Context) {
 		req.Name,
 		token,
 		tenantID,
-		tenant.Name,
+		tenant.ResolvedBranding(),
 		inviterName,
 		req.Role,
 		expiresAt,
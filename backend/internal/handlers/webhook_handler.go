@@ -0,0 +1,424 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookHandler handles outbound webhook endpoints (subscriptions, delivery logs and replay)
+type WebhookHandler struct {
+	webhookService *services.WebhookService
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler(webhookService *services.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+// RegisterRoutes registers webhook routes (tenant-scoped, admin)
+func (h *WebhookHandler) RegisterRoutes(router *gin.RouterGroup) {
+	webhooks := router.Group("/webhooks")
+	{
+		webhooks.GET("/events", h.ListEvents)
+		webhooks.POST("/run", h.Run)
+
+		webhooks.GET("/subscriptions", h.ListSubscriptions)
+		webhooks.POST("/subscriptions", h.CreateSubscription)
+		webhooks.GET("/subscriptions/:subscription_id", h.GetSubscription)
+		webhooks.PUT("/subscriptions/:subscription_id", h.UpdateSubscription)
+		webhooks.DELETE("/subscriptions/:subscription_id", h.DeleteSubscription)
+		webhooks.POST("/subscriptions/:subscription_id/rotate-secret", h.RotateSecret)
+		webhooks.POST("/subscriptions/:subscription_id/replay", h.ReplaySubscription)
+
+		webhooks.GET("/deliveries", h.ListDeliveries)
+		webhooks.GET("/deliveries/:delivery_id", h.GetDelivery)
+		webhooks.POST("/deliveries/:delivery_id/replay", h.ReplayDelivery)
+	}
+}
+
+// CreateWebhookSubscriptionRequest represents the request body for creating a subscription
+type CreateWebhookSubscriptionRequest struct {
+	Name        string   `json:"name" binding:"required"`
+	URL         string   `json:"url" binding:"required"`
+	Events      []string `json:"events" binding:"required"` // tipos de evento ou ["*"]
+	Description string   `json:"description,omitempty"`
+}
+
+// UpdateWebhookSubscriptionRequest represents the request body for updating a subscription
+type UpdateWebhookSubscriptionRequest struct {
+	Name        *string  `json:"name,omitempty"`
+	URL         *string  `json:"url,omitempty"`
+	Events      []string `json:"events,omitempty"`
+	Description *string  `json:"description,omitempty"`
+	Active      *bool    `json:"active,omitempty"`
+}
+
+// ReplayWebhookSubscriptionRequest represents the request body for replaying events to a subscription
+type ReplayWebhookSubscriptionRequest struct {
+	FromSequence int64 `json:"from_sequence" binding:"required"`
+	ToSequence   int64 `json:"to_sequence,omitempty"` // default: last dispatched event
+}
+
+// ListEvents lists the event types that can be subscribed to
+// @Summary List webhook events
+// @Tags webhooks
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Success 200 {array} string
+// @Router /api/v1/admin/{tenant_id}/webhooks/events [get]
+func (h *WebhookHandler) ListEvents(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    models.WebhookEventTypes,
+		"count":   len(models.WebhookEventTypes),
+	})
+}
+
+// CreateSubscription creates a webhook subscription
+// The signing secret is only returned in this response (and when rotated)
+// @Summary Create webhook subscription
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param body body CreateWebhookSubscriptionRequest true "Subscription"
+// @Success 201 {object} models.WebhookSubscription
+// @Router /api/v1/admin/{tenant_id}/webhooks/subscriptions [post]
+func (h *WebhookHandler) CreateSubscription(c *gin.Context) {
+	var req CreateWebhookSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	actorID, exists := c.Get("user_id")
+	if !exists {
+		actorID = "system"
+	}
+
+	subscription, err := h.webhookService.CreateSubscription(c.Request.Context(), services.CreateWebhookSubscriptionInput{
+		TenantID:    c.Param("tenant_id"),
+		Name:        req.Name,
+		URL:         req.URL,
+		Events:      req.Events,
+		Description: req.Description,
+		CreatedBy:   actorID.(string),
+	})
+	if err != nil {
+		respondWebhookError(c, err, "webhook subscription not found")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    subscription,
+		"secret":  subscription.Secret,
+	})
+}
+
+// ListSubscriptions lists the webhook subscriptions
+// @Summary List webhook subscriptions
+// @Tags webhooks
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Success 200 {array} models.WebhookSubscription
+// @Router /api/v1/admin/{tenant_id}/webhooks/subscriptions [get]
+func (h *WebhookHandler) ListSubscriptions(c *gin.Context) {
+	subscriptions, err := h.webhookService.ListSubscriptions(c.Request.Context(), c.Param("tenant_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    subscriptions,
+		"count":   len(subscriptions),
+	})
+}
+
+// GetSubscription retrieves a webhook subscription
+// @Summary Get webhook subscription
+// @Tags webhooks
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param subscription_id path string true "Subscription ID"
+// @Success 200 {object} models.WebhookSubscription
+// @Router /api/v1/admin/{tenant_id}/webhooks/subscriptions/{subscription_id} [get]
+func (h *WebhookHandler) GetSubscription(c *gin.Context) {
+	subscription, err := h.webhookService.GetSubscription(c.Request.Context(), c.Param("tenant_id"), c.Param("subscription_id"))
+	if err != nil {
+		respondWebhookError(c, err, "webhook subscription not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    subscription,
+	})
+}
+
+// UpdateSubscription updates a webhook subscription (name, url, events, description, active)
+// @Summary Update webhook subscription
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param subscription_id path string true "Subscription ID"
+// @Param body body UpdateWebhookSubscriptionRequest true "Fields to update"
+// @Success 200 {object} models.WebhookSubscription
+// @Router /api/v1/admin/{tenant_id}/webhooks/subscriptions/{subscription_id} [put]
+func (h *WebhookHandler) UpdateSubscription(c *gin.Context) {
+	var req UpdateWebhookSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	actorID, exists := c.Get("user_id")
+	if !exists {
+		actorID = "system"
+	}
+
+	subscription, err := h.webhookService.UpdateSubscription(c.Request.Context(), c.Param("tenant_id"), c.Param("subscription_id"), services.UpdateWebhookSubscriptionInput{
+		Name:        req.Name,
+		URL:         req.URL,
+		Events:      req.Events,
+		Description: req.Description,
+		Active:      req.Active,
+	}, actorID.(string))
+	if err != nil {
+		respondWebhookError(c, err, "webhook subscription not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    subscription,
+	})
+}
+
+// DeleteSubscription deletes a webhook subscription
+// @Summary Delete webhook subscription
+// @Tags webhooks
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param subscription_id path string true "Subscription ID"
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/admin/{tenant_id}/webhooks/subscriptions/{subscription_id} [delete]
+func (h *WebhookHandler) DeleteSubscription(c *gin.Context) {
+	actorID, exists := c.Get("user_id")
+	if !exists {
+		actorID = "system"
+	}
+
+	if err := h.webhookService.DeleteSubscription(c.Request.Context(), c.Param("tenant_id"), c.Param("subscription_id"), actorID.(string)); err != nil {
+		respondWebhookError(c, err, "webhook subscription not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Webhook subscription deleted successfully",
+	})
+}
+
+// RotateSecret replaces the signing secret of a subscription
+// @Summary Rotate webhook secret
+// @Tags webhooks
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param subscription_id path string true "Subscription ID"
+// @Success 200 {object} models.WebhookSubscription
+// @Router /api/v1/admin/{tenant_id}/webhooks/subscriptions/{subscription_id}/rotate-secret [post]
+func (h *WebhookHandler) RotateSecret(c *gin.Context) {
+	actorID, exists := c.Get("user_id")
+	if !exists {
+		actorID = "system"
+	}
+
+	subscription, err := h.webhookService.RotateSecret(c.Request.Context(), c.Param("tenant_id"), c.Param("subscription_id"), actorID.(string))
+	if err != nil {
+		respondWebhookError(c, err, "webhook subscription not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    subscription,
+		"secret":  subscription.Secret,
+	})
+}
+
+// ReplaySubscription queues the events of a sequence range again for a subscription
+// @Summary Replay events to a webhook subscription
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param subscription_id path string true "Subscription ID"
+// @Param body body ReplayWebhookSubscriptionRequest true "Sequence range"
+// @Success 202 {object} map[string]interface{}
+// @Router /api/v1/admin/{tenant_id}/webhooks/subscriptions/{subscription_id}/replay [post]
+func (h *WebhookHandler) ReplaySubscription(c *gin.Context) {
+	var req ReplayWebhookSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	actorID, exists := c.Get("user_id")
+	if !exists {
+		actorID = "system"
+	}
+
+	queued, err := h.webhookService.ReplaySubscription(c.Request.Context(), c.Param("tenant_id"), c.Param("subscription_id"), req.FromSequence, req.ToSequence, actorID.(string))
+	if err != nil {
+		respondWebhookError(c, err, "webhook subscription not found")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"success": true,
+		"data":    gin.H{"queued": queued},
+	})
+}
+
+// ListDeliveries lists the delivery log
+// @Summary List webhook deliveries
+// @Tags webhooks
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param subscription_id query string false "Filter by subscription"
+// @Param status query string false "Filter by status (pending, retrying, delivered, dead)"
+// @Param limit query int false "Maximum deliveries (default 50)"
+// @Success 200 {array} models.WebhookDelivery
+// @Router /api/v1/admin/{tenant_id}/webhooks/deliveries [get]
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	filters := &repositories.WebhookDeliveryFilters{
+		SubscriptionID: c.Query("subscription_id"),
+		Status:         models.WebhookDeliveryStatus(c.Query("status")),
+	}
+
+	deliveries, err := h.webhookService.ListDeliveries(c.Request.Context(), c.Param("tenant_id"), filters, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    deliveries,
+		"count":   len(deliveries),
+	})
+}
+
+// GetDelivery retrieves a delivery with its attempt log
+// @Summary Get webhook delivery
+// @Tags webhooks
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param delivery_id path string true "Delivery ID"
+// @Success 200 {object} models.WebhookDelivery
+// @Router /api/v1/admin/{tenant_id}/webhooks/deliveries/{delivery_id} [get]
+func (h *WebhookHandler) GetDelivery(c *gin.Context) {
+	delivery, err := h.webhookService.GetDelivery(c.Request.Context(), c.Param("tenant_id"), c.Param("delivery_id"))
+	if err != nil {
+		respondWebhookError(c, err, "webhook delivery not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    delivery,
+	})
+}
+
+// ReplayDelivery queues a new delivery of the same event
+// @Summary Replay webhook delivery
+// @Tags webhooks
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param delivery_id path string true "Delivery ID"
+// @Success 202 {object} models.WebhookDelivery
+// @Router /api/v1/admin/{tenant_id}/webhooks/deliveries/{delivery_id}/replay [post]
+func (h *WebhookHandler) ReplayDelivery(c *gin.Context) {
+	actorID, exists := c.Get("user_id")
+	if !exists {
+		actorID = "system"
+	}
+
+	delivery, err := h.webhookService.ReplayDelivery(c.Request.Context(), c.Param("tenant_id"), c.Param("delivery_id"), actorID.(string))
+	if err != nil {
+		respondWebhookError(c, err, "webhook delivery or subscription not found")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"success": true,
+		"data":    delivery,
+	})
+}
+
+// Run dispatches new events and sends the due deliveries
+// This endpoint should be called by a cron job every minute
+// @Summary Run webhook dispatcher
+// @Tags webhooks
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Success 200 {object} services.WebhookRunResult
+// @Router /api/v1/admin/{tenant_id}/webhooks/run [post]
+func (h *WebhookHandler) Run(c *gin.Context) {
+	result, err := h.webhookService.RunWebhooks(c.Request.Context(), c.Param("tenant_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    result,
+	})
+}
+
+// respondWebhookError maps webhook service errors to HTTP responses
+func respondWebhookError(c *gin.Context, err error, notFoundMessage string) {
+	if err == repositories.ErrNotFound {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   notFoundMessage,
+		})
+		return
+	}
+
+	c.JSON(http.StatusBadRequest, gin.H{
+		"success": false,
+		"error":   err.Error(),
+	})
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// WhatsAppLinkHandler handles the redirect of WhatsApp click-tracking short links
+type WhatsAppLinkHandler struct {
+	linkService *services.WhatsAppLinkService
+}
+
+// NewWhatsAppLinkHandler creates a new WhatsApp link handler
+func NewWhatsAppLinkHandler(linkService *services.WhatsAppLinkService) *WhatsAppLinkHandler {
+	return &WhatsAppLinkHandler{
+		linkService: linkService,
+	}
+}
+
+// RegisterRoutes registers the short link redirect (public, at the root to keep links short)
+func (h *WhatsAppLinkHandler) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/w/:code", h.Redirect)
+}
+
+// Redirect records the click of a short link and redirects to the WhatsApp deep link
+// @Summary Redirect WhatsApp short link
+// @Tags whatsapp
+// @Param code path string true "Short link code"
+// @Success 302
+// @Failure 404 {object} map[string]interface{}
+// @Router /w/{code} [get]
+func (h *WhatsAppLinkHandler) Redirect(c *gin.Context) {
+	link, err := h.linkService.Click(c.Request.Context(), c.Param("code"), c.Request.UserAgent())
+	if err != nil {
+		if err == repositories.ErrNotFound {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   "link not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	// Every click must reach the handler to be counted
+	c.Header("Cache-Control", "no-store")
+	c.Redirect(http.StatusFound, link.TargetURL)
+}
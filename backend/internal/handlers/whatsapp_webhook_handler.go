@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// maxWhatsAppWebhookBodySize limits the size of a webhook delivery
+const maxWhatsAppWebhookBodySize = 1 << 20
+
+// WhatsAppWebhookHandler handles the inbound webhook of the WhatsApp Cloud API
+type WhatsAppWebhookHandler struct {
+	inboundService *services.WhatsAppInboundService
+}
+
+// NewWhatsAppWebhookHandler creates a new WhatsApp webhook handler
+func NewWhatsAppWebhookHandler(inboundService *services.WhatsAppInboundService) *WhatsAppWebhookHandler {
+	return &WhatsAppWebhookHandler{
+		inboundService: inboundService,
+	}
+}
+
+// RegisterRoutes registers the webhook routes (public, authenticated by the verify token and the payload signature)
+func (h *WhatsAppWebhookHandler) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/webhooks/whatsapp", h.VerifySubscription)
+	router.POST("/webhooks/whatsapp", h.ReceiveMessages)
+}
+
+// VerifySubscription answers the subscription verification of the Meta app
+// @Summary Verify WhatsApp webhook subscription
+// @Tags whatsapp
+// @Produce plain
+// @Param hub.mode query string true "subscribe"
+// @Param hub.verify_token query string true "Verify token"
+// @Param hub.challenge query string true "Challenge"
+// @Success 200 {string} string "challenge"
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/v1/webhooks/whatsapp [get]
+func (h *WhatsAppWebhookHandler) VerifySubscription(c *gin.Context) {
+	challenge, err := h.inboundService.VerifySubscription(c.Query("hub.mode"), c.Query("hub.verify_token"), c.Query("hub.challenge"))
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.String(http.StatusOK, challenge)
+}
+
+// ReceiveMessages receives messages sent to the business number and threads them into lead conversations
+// @Summary Receive WhatsApp messages
+// @Description WhatsApp Cloud API webhook: matches messages to a property (protocol, reference or link), creates or updates the lead and notifies the routed broker
+// @Tags whatsapp
+// @Accept json
+// @Produce json
+// @Param X-Hub-Signature-256 header string false "sha256=<HMAC of the body with the app secret>"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/v1/webhooks/whatsapp [post]
+func (h *WhatsAppWebhookHandler) ReceiveMessages(c *gin.Context) {
+	// The signature is computed over the raw body
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWhatsAppWebhookBodySize))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "failed to read body",
+		})
+		return
+	}
+
+	if err := h.inboundService.VerifySignature(body, c.GetHeader("X-Hub-Signature-256")); err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	var payload models.WhatsAppWebhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "invalid payload",
+		})
+		return
+	}
+
+	// Always acknowledge processed deliveries (Meta retries non-2xx responses)
+	result := h.inboundService.HandleWebhook(c.Request.Context(), &payload)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    result,
+	})
+}
@@ -0,0 +1,195 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// APIKeyIDKey is the context key for the ID of the API key that authenticated the request
+	APIKeyIDKey ContextKey = "api_key_id"
+	// APIKeyScopesKey is the context key for the scopes of the API key
+	APIKeyScopesKey ContextKey = "api_key_scopes"
+
+	// APIKeyHeader carries an API key (alternative to "Authorization: Bearer eik_...")
+	APIKeyHeader = "X-API-Key"
+
+	// apiKeyUserIDPrefix marks the user_id of requests authenticated by API key (actor in activity logs)
+	apiKeyUserIDPrefix = "api_key:"
+
+	// adminTenantRoutePrefix is the route prefix of the tenant-scoped admin API
+	adminTenantRoutePrefix = "/api/v1/admin/:tenant_id"
+)
+
+// apiKeyRouteScopes maps the admin routes available to API keys ("METHOD path" relative to
+// /api/v1/admin/:tenant_id) to the scope they require; any other route is denied to API keys
+var apiKeyRouteScopes = map[string]string{
+	"GET /properties":                 models.APIKeyScopePropertiesRead,
+	"GET /properties/:id":             models.APIKeyScopePropertiesRead,
+	"GET /properties/slug/:slug":      models.APIKeyScopePropertiesRead,
+	"GET /properties/:id/duplicates":  models.APIKeyScopePropertiesRead,
+	"POST /properties":                models.APIKeyScopePropertiesWrite,
+	"PUT /properties/:id":             models.APIKeyScopePropertiesWrite,
+	"POST /properties/:id/status":     models.APIKeyScopePropertiesWrite,
+	"POST /properties/:id/visibility": models.APIKeyScopePropertiesWrite,
+
+	"GET /listings":     models.APIKeyScopeListingsRead,
+	"GET /listings/:id": models.APIKeyScopeListingsRead,
+	"POST /listings":    models.APIKeyScopeListingsWrite,
+	"PUT /listings/:id": models.APIKeyScopeListingsWrite,
+
+	"GET /leads":             models.APIKeyScopeLeadsRead,
+	"GET /leads/:id":         models.APIKeyScopeLeadsRead,
+	"POST /leads":            models.APIKeyScopeLeadsWrite,
+	"PUT /leads/:id":         models.APIKeyScopeLeadsWrite,
+	"POST /leads/:id/status": models.APIKeyScopeLeadsWrite,
+	"POST /leads/:id/assign": models.APIKeyScopeLeadsWrite,
+
+	"GET /owners":     models.APIKeyScopeOwnersRead,
+	"GET /owners/:id": models.APIKeyScopeOwnersRead,
+	"POST /owners":    models.APIKeyScopeOwnersWrite,
+	"PUT /owners/:id": models.APIKeyScopeOwnersWrite,
+
+	"GET /brokers":     models.APIKeyScopeBrokersRead,
+	"GET /brokers/:id": models.APIKeyScopeBrokersRead,
+
+	"POST /import/properties":             models.APIKeyScopeImportRun,
+	"GET /import/batches/:batchId":        models.APIKeyScopeImportRun,
+	"GET /import/batches/:batchId/errors": models.APIKeyScopeImportRun,
+}
+
+// RequiredAPIKeyScope returns the scope an API key needs for a route (empty when API keys are not accepted)
+func RequiredAPIKeyScope(method, fullPath string) string {
+	if !strings.HasPrefix(fullPath, adminTenantRoutePrefix) {
+		return ""
+	}
+	return apiKeyRouteScopes[method+" "+strings.TrimPrefix(fullPath, adminTenantRoutePrefix)]
+}
+
+// APIKeyAuthenticator verifies tenant API keys and records their usage (implemented by services.APIKeyService)
+type APIKeyAuthenticator interface {
+	Authenticate(ctx context.Context, tenantID, token string) (*models.APIKey, error)
+	RecordUsage(ctx context.Context, key *models.APIKey, method, path string, statusCode int, clientIP string)
+}
+
+// APIKeyMiddleware authenticates admin requests with either a tenant API key or a Firebase ID token
+type APIKeyMiddleware struct {
+	authMiddleware *AuthMiddleware
+	authenticator  APIKeyAuthenticator
+	rateLimitStore RateLimitStore
+}
+
+// NewAPIKeyMiddleware creates a new API key middleware (Firebase tokens are verified by authMiddleware)
+// Per-key rate limits are counted in rateLimitStore (nil uses a process-local memory store)
+func NewAPIKeyMiddleware(authMiddleware *AuthMiddleware, authenticator APIKeyAuthenticator, rateLimitStore RateLimitStore) *APIKeyMiddleware {
+	if rateLimitStore == nil {
+		rateLimitStore = NewMemoryRateLimitStore()
+	}
+
+	return &APIKeyMiddleware{
+		authMiddleware: authMiddleware,
+		authenticator:  authenticator,
+		rateLimitStore: rateLimitStore,
+	}
+}
+
+// AuthRequired returns a middleware that requires an API key or a Firebase ID token
+// API keys (X-API-Key header or "Authorization: Bearer eik_...") are only accepted on tenant routes of
+// their own tenant listed in apiKeyRouteScopes, with the required scope, within the key's rate limit
+func (m *APIKeyMiddleware) AuthRequired() gin.HandlerFunc {
+	firebaseAuth := m.authMiddleware.AuthRequired()
+
+	return func(c *gin.Context) {
+		token := extractAPIKey(c)
+		if token == "" {
+			firebaseAuth(c)
+			return
+		}
+
+		tenantID := c.Param("tenant_id")
+		if tenantID == "" {
+			c.JSON(http.StatusForbidden, gin.H{
+				"success": false,
+				"error":   "api keys are only accepted on tenant routes",
+			})
+			c.Abort()
+			return
+		}
+
+		key, err := m.authenticator.Authenticate(c.Request.Context(), tenantID, token)
+		if err != nil {
+			log.Printf("API key rejected for tenant %s: %v", tenantID, err)
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"success": false,
+				"error":   "invalid or expired api key",
+			})
+			c.Abort()
+			return
+		}
+
+		if !takeRateLimit(c, m.rateLimitStore, "api_key:"+key.ID, key.RateLimit(), time.Minute) {
+			abortRateLimited(c, "API key rate limit exceeded. Please try again later.")
+			return
+		}
+
+		scope := RequiredAPIKeyScope(c.Request.Method, c.FullPath())
+		if scope == "" || !key.HasScope(scope) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"success": false,
+				"error":   "api key is not allowed to access this resource",
+				"scope":   scope,
+			})
+			c.Abort()
+			m.authenticator.RecordUsage(c.Request.Context(), key, c.Request.Method, c.FullPath(), http.StatusForbidden, c.ClientIP())
+			return
+		}
+
+		// Set API key information in context (user_id identifies the key as actor)
+		userID := apiKeyUserIDPrefix + key.ID
+		c.Set(string(UserIDKey), userID)
+		c.Set(string(APIKeyIDKey), key.ID)
+		c.Set(string(APIKeyScopesKey), key.Scopes)
+
+		ctx := context.WithValue(c.Request.Context(), UserIDKey, userID)
+		ctx = context.WithValue(ctx, APIKeyIDKey, key.ID)
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		m.authenticator.RecordUsage(c.Request.Context(), key, c.Request.Method, c.FullPath(), c.Writer.Status(), c.ClientIP())
+	}
+}
+
+// extractAPIKey returns the API key of the request (empty when the request carries no API key)
+func extractAPIKey(c *gin.Context) string {
+	if key := strings.TrimSpace(c.GetHeader(APIKeyHeader)); key != "" {
+		return key
+	}
+
+	parts := strings.SplitN(c.GetHeader("Authorization"), " ", 2)
+	if len(parts) == 2 && parts[0] == "Bearer" && models.IsAPIKeyToken(parts[1]) {
+		return parts[1]
+	}
+	return ""
+}
+
+// GetAPIKeyID retrieves the ID of the API key that authenticated the request (empty for Firebase users)
+func GetAPIKeyID(c *gin.Context) string {
+	if keyID, exists := c.Get(string(APIKeyIDKey)); exists {
+		if id, ok := keyID.(string); ok {
+			return id
+		}
+	}
+	return ""
+}
+
+// IsAPIKeyRequest checks if the request was authenticated by an API key
+func IsAPIKeyRequest(c *gin.Context) bool {
+	return GetAPIKeyID(c) != ""
+}
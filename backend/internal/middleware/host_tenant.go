@@ -0,0 +1,138 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/gin-gonic/gin"
+)
+
+// HostTenantResolver resolves the tenant served at a host (implemented by services.DomainService)
+type HostTenantResolver interface {
+	ResolveHost(ctx context.Context, host string) (*models.Tenant, error)
+}
+
+// HostTenantMiddleware resolves the tenant of public site requests from the Host header
+// ({slug}.{platform domain} subdomains and verified custom domains)
+type HostTenantMiddleware struct {
+	resolver       HostTenantResolver
+	trustedProxies []*net.IPNet // peers allowed to set X-Forwarded-Host
+}
+
+// NewHostTenantMiddleware creates a new host tenant middleware
+// trustedProxies are the IPs or CIDRs of the proxies whose X-Forwarded-Host is honored (empty = Host only)
+func NewHostTenantMiddleware(resolver HostTenantResolver, trustedProxies []string) (*HostTenantMiddleware, error) {
+	networks, err := parseTrustedProxies(trustedProxies)
+	if err != nil {
+		return nil, err
+	}
+
+	return &HostTenantMiddleware{
+		resolver:       resolver,
+		trustedProxies: networks,
+	}, nil
+}
+
+// ResolveTenant returns a middleware that resolves the tenant from the request host and sets it in context
+// The tenant ID is also exposed as the "tenant_id" path parameter, so handlers of /:tenant_id routes can be
+// mounted unchanged on host-based routes
+func (m *HostTenantMiddleware) ResolveTenant() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		host := m.RequestHost(c)
+
+		tenant, err := m.resolver.ResolveHost(c.Request.Context(), host)
+		if err != nil {
+			if err == repositories.ErrNotFound {
+				c.JSON(http.StatusNotFound, gin.H{
+					"success": false,
+					"error":   "no site is configured for this domain",
+				})
+				c.Abort()
+				return
+			}
+
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"error":   "failed to resolve tenant",
+			})
+			c.Abort()
+			return
+		}
+
+		// Set tenant in context
+		c.Set(string(TenantIDKey), tenant.ID)
+		c.Set(string(TenantKey), tenant)
+		c.Params = append(c.Params, gin.Param{Key: "tenant_id", Value: tenant.ID})
+
+		// Set in request context as well for use in repositories/services
+		ctx := context.WithValue(c.Request.Context(), TenantIDKey, tenant.ID)
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
+
+// RequestHost returns the host requested by the client
+// X-Forwarded-Host is only honored when the request comes from a trusted proxy (the load balancer):
+// any client can send the header, and a spoofed host would serve another tenant's site
+func (m *HostTenantMiddleware) RequestHost(c *gin.Context) string {
+	if forwarded := c.GetHeader("X-Forwarded-Host"); forwarded != "" && m.fromTrustedProxy(c) {
+		// Proxies may append hosts: the first one is the client's
+		return models.NormalizeHostname(strings.Split(forwarded, ",")[0])
+	}
+	return models.NormalizeHostname(c.Request.Host)
+}
+
+// fromTrustedProxy checks whether the direct peer of the request is a trusted proxy
+func (m *HostTenantMiddleware) fromTrustedProxy(c *gin.Context) bool {
+	if len(m.trustedProxies) == 0 {
+		return false
+	}
+
+	host, _, err := net.SplitHostPort(strings.TrimSpace(c.Request.RemoteAddr))
+	if err != nil {
+		host = strings.TrimSpace(c.Request.RemoteAddr)
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+
+	for _, network := range m.trustedProxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTrustedProxies parses proxy IPs and CIDRs (a single IP is a /32 or /128 network)
+func parseTrustedProxies(proxies []string) ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(proxies))
+	for _, proxy := range proxies {
+		if !strings.Contains(proxy, "/") {
+			ip := net.ParseIP(proxy)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q", proxy)
+			}
+			bits := 128
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 32
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
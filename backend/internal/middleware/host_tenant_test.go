@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hostTestContext creates a request to Host from the peer, with the X-Forwarded-Host header when set
+func hostTestContext(remoteAddr, forwardedHost string) *gin.Context {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", "/api/v1/public/site", nil)
+	c.Request.Host = "imobiliaria-sul.example.com"
+	c.Request.RemoteAddr = remoteAddr
+	if forwardedHost != "" {
+		c.Request.Header.Set("X-Forwarded-Host", forwardedHost)
+	}
+	return c
+}
+
+func TestRequestHostIgnoresForwardedHostFromUntrustedPeers(t *testing.T) {
+	m, err := NewHostTenantMiddleware(nil, []string{"10.0.0.0/8"})
+	require.NoError(t, err)
+
+	c := hostTestContext("203.0.113.7:51234", "outra-imobiliaria.example.com")
+	assert.Equal(t, "imobiliaria-sul.example.com", m.RequestHost(c))
+
+	// Without trusted proxies the header is never honored
+	m, err = NewHostTenantMiddleware(nil, nil)
+	require.NoError(t, err)
+
+	c = hostTestContext("10.1.2.3:51234", "outra-imobiliaria.example.com")
+	assert.Equal(t, "imobiliaria-sul.example.com", m.RequestHost(c))
+}
+
+func TestRequestHostHonorsForwardedHostFromTrustedProxies(t *testing.T) {
+	m, err := NewHostTenantMiddleware(nil, []string{"10.0.0.0/8", "192.0.2.10", "2001:db8::1"})
+	require.NoError(t, err)
+
+	c := hostTestContext("10.1.2.3:51234", "Casa-Nova.example.com, 10.1.2.3")
+	assert.Equal(t, "casa-nova.example.com", m.RequestHost(c))
+
+	c = hostTestContext("192.0.2.10:443", "casa-nova.example.com")
+	assert.Equal(t, "casa-nova.example.com", m.RequestHost(c))
+
+	c = hostTestContext("[2001:db8::1]:443", "casa-nova.example.com")
+	assert.Equal(t, "casa-nova.example.com", m.RequestHost(c))
+
+	// Another IP of the same /32 network is not trusted
+	c = hostTestContext("192.0.2.11:443", "casa-nova.example.com")
+	assert.Equal(t, "imobiliaria-sul.example.com", m.RequestHost(c))
+}
+
+func TestNewHostTenantMiddlewareRejectsInvalidProxies(t *testing.T) {
+	_, err := NewHostTenantMiddleware(nil, []string{"load-balancer"})
+	assert.Error(t, err)
+
+	_, err = NewHostTenantMiddleware(nil, []string{"10.0.0.0/33"})
+	assert.Error(t, err)
+}
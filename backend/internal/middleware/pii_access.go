@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// PIIAccessKey is the context key telling whether the caller may see unmasked PII
+	PIIAccessKey ContextKey = "pii_access"
+)
+
+// PIIAccessMiddleware resolves whether the authenticated user may see unmasked personal data
+type PIIAccessMiddleware struct {
+	userRepo *repositories.UserRepository
+}
+
+// NewPIIAccessMiddleware creates a new PII access middleware
+func NewPIIAccessMiddleware(userRepo *repositories.UserRepository) *PIIAccessMiddleware {
+	return &PIIAccessMiddleware{
+		userRepo: userRepo,
+	}
+}
+
+// ResolvePIIAccess sets PIIAccessKey in the context
+// Must run after AuthRequired and ValidateTenant
+// The user's role/permissions come from the tenant's users collection; when the
+// user is not found there (e.g. legacy brokers), the "role" custom claim is used
+func (m *PIIAccessMiddleware) ResolvePIIAccess() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		allowed := false
+
+		firebaseUID := GetUserID(c)
+		if firebaseUID != "" {
+			user, err := m.userRepo.GetByFirebaseUID(c.Request.Context(), c.Param("tenant_id"), firebaseUID)
+			if err == nil {
+				allowed = user.IsActive && user.CanViewPII()
+			} else if token := GetFirebaseToken(c); token != nil {
+				if role, ok := token.Claims["role"].(string); ok {
+					allowed = models.RoleCanViewPII(role)
+				}
+			}
+		}
+
+		c.Set(string(PIIAccessKey), allowed)
+		c.Next()
+	}
+}
+
+// CanViewPII checks whether the caller may see unmasked PII
+// Defaults to false (masked) when the access was not resolved
+func CanViewPII(c *gin.Context) bool {
+	if allowed, exists := c.Get(string(PIIAccessKey)); exists {
+		if ok, isBool := allowed.(bool); isBool {
+			return ok
+		}
+	}
+	return false
+}
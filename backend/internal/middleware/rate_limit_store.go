@@ -0,0 +1,153 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+)
+
+// RateLimitStore counts requests per key in fixed windows
+// Implementations shared by every replica (CounterRateLimitStore) make limits global instead of per process
+type RateLimitStore interface {
+	Take(ctx context.Context, key string, limit int, window time.Duration) (*models.RateLimitResult, error)
+}
+
+// memoryWindow holds the request count of a key in the current window
+type memoryWindow struct {
+	count   int64
+	resetAt time.Time
+}
+
+// MemoryRateLimitStore is a process-local RateLimitStore (single instance and development)
+type MemoryRateLimitStore struct {
+	windows map[string]*memoryWindow
+	mu      sync.Mutex
+}
+
+// NewMemoryRateLimitStore creates a process-local rate limit store
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	s := &MemoryRateLimitStore{
+		windows: make(map[string]*memoryWindow),
+	}
+
+	// Start cleanup goroutine
+	go s.cleanupWindows()
+
+	return s
+}
+
+// Take counts a request for the key in the current window
+func (s *MemoryRateLimitStore) Take(ctx context.Context, key string, limit int, window time.Duration) (*models.RateLimitResult, error) {
+	now := time.Now()
+	resetAt := models.RateLimitWindowStart(now, window).Add(window)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w, exists := s.windows[key]
+	if !exists || !w.resetAt.Equal(resetAt) {
+		w = &memoryWindow{resetAt: resetAt}
+		s.windows[key] = w
+	}
+	w.count++
+
+	return models.NewRateLimitResult(limit, w.count, resetAt, now), nil
+}
+
+// cleanupWindows removes expired windows periodically
+func (s *MemoryRateLimitStore) cleanupWindows() {
+	ticker := time.NewTicker(5 * time.Minute)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		now := time.Now()
+		s.mu.Lock()
+		for key, w := range s.windows {
+			if !w.resetAt.After(now) {
+				delete(s.windows, key)
+			}
+		}
+		s.mu.Unlock()
+	}
+}
+
+// RateLimitCounter is a shared atomic counter backend with Redis semantics (INCR, then PEXPIRE on the
+// first increment): Increment returns the new value and the key expires ttl after it was created
+// Implemented by repositories.RateLimitRepository (Firestore, sharded) and FakeRateLimitCounter (local fake)
+// Keys are unique per window, so backends never need to reset a counter in place
+type RateLimitCounter interface {
+	Increment(ctx context.Context, key string, ttl time.Duration) (int64, error)
+}
+
+// CounterRateLimitStore is a RateLimitStore backed by a shared counter (one counter per key and window)
+type CounterRateLimitStore struct {
+	counter RateLimitCounter
+}
+
+// NewCounterRateLimitStore creates a rate limit store backed by a shared counter
+func NewCounterRateLimitStore(counter RateLimitCounter) *CounterRateLimitStore {
+	return &CounterRateLimitStore{
+		counter: counter,
+	}
+}
+
+// Take counts a request for the key in the current window
+func (s *CounterRateLimitStore) Take(ctx context.Context, key string, limit int, window time.Duration) (*models.RateLimitResult, error) {
+	now := time.Now()
+	windowStart := models.RateLimitWindowStart(now, window)
+	resetAt := windowStart.Add(window)
+
+	count, err := s.counter.Increment(ctx, fmt.Sprintf("%s:%d", key, windowStart.Unix()), resetAt.Sub(now)+time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	return models.NewRateLimitResult(limit, count, resetAt, now), nil
+}
+
+// fakeCounter holds a FakeRateLimitCounter value
+type fakeCounter struct {
+	value     int64
+	expiresAt time.Time
+}
+
+// FakeRateLimitCounter is an in-memory RateLimitCounter with the semantics of the shared backends
+// Used for local development and tests of CounterRateLimitStore without Firestore or Redis
+type FakeRateLimitCounter struct {
+	counters map[string]*fakeCounter
+	mu       sync.Mutex
+}
+
+// NewFakeRateLimitCounter creates an in-memory counter backend
+func NewFakeRateLimitCounter() *FakeRateLimitCounter {
+	return &FakeRateLimitCounter{
+		counters: make(map[string]*fakeCounter),
+	}
+}
+
+// Increment increments a counter, creating it with the ttl when missing or expired
+func (f *FakeRateLimitCounter) Increment(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	now := time.Now()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	// Expired counters are dropped on write (the real backends expire them on their own)
+	for k, c := range f.counters {
+		if !c.expiresAt.After(now) {
+			delete(f.counters, k)
+		}
+	}
+
+	c, exists := f.counters[key]
+	if !exists {
+		c = &fakeCounter{expiresAt: now.Add(ttl)}
+		f.counters[key] = c
+	}
+	c.value++
+
+	return c.value, nil
+}
@@ -1,130 +1,203 @@
 package middleware
 
 import (
+	"log"
 	"net/http"
-	"sync"
+	"strconv"
+	"sync/atomic"
 	"time"
 
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
 	"github.com/gin-gonic/gin"
-	"golang.org/x/time/rate"
+)
+
+const (
+	// rateLimitResultKey holds the most restrictive rate limit result of the request (response headers)
+	rateLimitResultKey ContextKey = "rate_limit_result"
 )
 
 // RateLimiterConfig holds the rate limiter configuration
 type RateLimiterConfig struct {
-	RequestsPerSecond float64       // Requests allowed per second
-	Burst             int           // Maximum burst size
-	CleanupInterval   time.Duration // Interval to cleanup old limiters
+	Name     string        // Key namespace (limiters with different limits must not share counters)
+	Requests int           // Requests allowed per window
+	Window   time.Duration // Window length
 }
 
 // DefaultRateLimiterConfig returns default rate limiter configuration
 func DefaultRateLimiterConfig() RateLimiterConfig {
 	return RateLimiterConfig{
-		RequestsPerSecond: 10.0,            // 10 requests per second
-		Burst:             20,               // Burst of up to 20 requests
-		CleanupInterval:   5 * time.Minute, // Cleanup every 5 minutes
+		Name:     "default",
+		Requests: 600,         // 10 requests per second on average
+		Window:   time.Minute, // per minute
 	}
 }
 
 // StrictRateLimiterConfig returns strict rate limiter for public endpoints
 func StrictRateLimiterConfig() RateLimiterConfig {
 	return RateLimiterConfig{
-		RequestsPerSecond: 2.0,             // 2 requests per second
-		Burst:             5,                // Burst of up to 5 requests
-		CleanupInterval:   5 * time.Minute, // Cleanup every 5 minutes
+		Name:     "strict",
+		Requests: 120,         // 2 requests per second on average
+		Window:   time.Minute, // per minute
 	}
 }
 
-// visitor holds rate limiter and last seen time
-type visitor struct {
-	limiter  *rate.Limiter
-	lastSeen time.Time
-}
-
 // RateLimiter is a middleware that limits requests per IP
 type RateLimiter struct {
-	visitors map[string]*visitor
-	mu       sync.RWMutex
-	config   RateLimiterConfig
+	store  RateLimitStore
+	config RateLimiterConfig
 }
 
 // NewRateLimiter creates a new rate limiter with the given configuration
-func NewRateLimiter(config RateLimiterConfig) *RateLimiter {
-	rl := &RateLimiter{
-		visitors: make(map[string]*visitor),
-		config:   config,
+// A nil store uses a process-local memory store
+func NewRateLimiter(config RateLimiterConfig, store RateLimitStore) *RateLimiter {
+	if store == nil {
+		store = NewMemoryRateLimitStore()
 	}
 
-	// Start cleanup goroutine
-	go rl.cleanupVisitors()
-
-	return rl
+	return &RateLimiter{
+		store:  store,
+		config: config,
+	}
 }
 
-// getVisitor retrieves or creates a rate limiter for the given IP
-func (rl *RateLimiter) getVisitor(ip string) *rate.Limiter {
-	rl.mu.Lock()
-	defer rl.mu.Unlock()
+// Limit returns a Gin middleware that limits requests per IP
+func (rl *RateLimiter) Limit() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := rl.config.Name + ":ip:" + c.ClientIP()
 
-	v, exists := rl.visitors[ip]
-	if !exists {
-		limiter := rate.NewLimiter(rate.Limit(rl.config.RequestsPerSecond), rl.config.Burst)
-		rl.visitors[ip] = &visitor{
-			limiter:  limiter,
-			lastSeen: time.Now(),
+		if !takeRateLimit(c, rl.store, key, rl.config.Requests, rl.config.Window) {
+			abortRateLimited(c, "Rate limit exceeded. Please try again later.")
+			return
 		}
-		return limiter
+
+		c.Next()
 	}
+}
 
-	v.lastSeen = time.Now()
-	return v.limiter
+// RateLimit is a convenience function to create a rate limiter with default config
+func RateLimit() gin.HandlerFunc {
+	return NewRateLimiter(DefaultRateLimiterConfig(), nil).Limit()
 }
 
-// cleanupVisitors removes old visitors periodically
-func (rl *RateLimiter) cleanupVisitors() {
-	ticker := time.NewTicker(rl.config.CleanupInterval)
-	defer ticker.Stop()
+// StrictRateLimit is a convenience function for strict rate limiting (public endpoints)
+func StrictRateLimit() gin.HandlerFunc {
+	return NewRateLimiter(StrictRateLimiterConfig(), nil).Limit()
+}
 
-	for range ticker.C {
-		rl.mu.Lock()
-		for ip, v := range rl.visitors {
-			// Remove visitors not seen in the last 3 minutes
-			if time.Since(v.lastSeen) > 3*time.Minute {
-				delete(rl.visitors, ip)
-			}
-		}
-		rl.mu.Unlock()
+// PlanRateLimiter limits authenticated requests per tenant and per user with the quotas of the
+// tenant's effective subscription plan (models.Tenant.EffectivePlan)
+type PlanRateLimiter struct {
+	store RateLimitStore
+}
+
+// NewPlanRateLimiter creates a new plan rate limiter (a nil store uses a process-local memory store)
+func NewPlanRateLimiter(store RateLimitStore) *PlanRateLimiter {
+	if store == nil {
+		store = NewMemoryRateLimitStore()
+	}
+
+	return &PlanRateLimiter{
+		store: store,
 	}
 }
 
-// Limit returns a Gin middleware that limits requests per IP
-func (rl *RateLimiter) Limit() gin.HandlerFunc {
+// Limit returns a Gin middleware that applies the plan quotas
+// Must run after ValidateTenant; requests made with an API key are only counted for the tenant
+// (the key has its own limit, see APIKeyMiddleware)
+func (pl *PlanRateLimiter) Limit() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Get client IP
-		ip := c.ClientIP()
-
-		// Get limiter for this IP
-		limiter := rl.getVisitor(ip)
-
-		// Check if request is allowed
-		if !limiter.Allow() {
-			c.JSON(http.StatusTooManyRequests, gin.H{
-				"success": false,
-				"error":   "Rate limit exceeded. Please try again later.",
-			})
-			c.Abort()
+		tenant := GetTenant(c)
+		if tenant == nil {
+			c.Next()
+			return
+		}
+
+		limits := tenant.EffectivePlan(time.Now()).RateLimits
+
+		if !takeRateLimit(c, pl.store, "tenant:"+tenant.ID, limits.Tenant.Limit, limits.Tenant.Window) {
+			abortRateLimited(c, "Tenant rate limit exceeded for the current plan. Please try again later.")
 			return
 		}
 
+		if userID := GetUserID(c); userID != "" && !IsAPIKeyRequest(c) {
+			if !takeRateLimit(c, pl.store, "user:"+tenant.ID+":"+userID, limits.User.Limit, limits.User.Window) {
+				abortRateLimited(c, "Rate limit exceeded. Please try again later.")
+				return
+			}
+		}
+
 		c.Next()
 	}
 }
 
-// RateLimit is a convenience function to create a rate limiter with default config
-func RateLimit() gin.HandlerFunc {
-	return NewRateLimiter(DefaultRateLimiterConfig()).Limit()
+// rateLimitStoreErrorLogInterval is the minimum time between two logs of store failures
+const rateLimitStoreErrorLogInterval = time.Minute
+
+var (
+	// rateLimitStoreErrors counts every store failure since the process started (GET /metrics)
+	rateLimitStoreErrors atomic.Int64
+
+	// rateLimitStoreErrorLoggedAt is the UnixNano of the last logged store failure
+	rateLimitStoreErrorLoggedAt atomic.Int64
+)
+
+// RateLimitStoreErrors returns how many rate limit store failures let requests through unlimited
+func RateLimitStoreErrors() int64 {
+	return rateLimitStoreErrors.Load()
 }
 
-// StrictRateLimit is a convenience function for strict rate limiting (public endpoints)
-func StrictRateLimit() gin.HandlerFunc {
-	return NewRateLimiter(StrictRateLimiterConfig()).Limit()
+// takeRateLimit counts the request against a key and sets the RateLimit-* headers
+// Store failures do not block requests (fail open): they are counted (RateLimitStoreErrors) and
+// logged at most once per rateLimitStoreErrorLogInterval so an outage does not flood the logs
+func takeRateLimit(c *gin.Context, store RateLimitStore, key string, limit int, window time.Duration) bool {
+	result, err := store.Take(c.Request.Context(), key, limit, window)
+	if err != nil {
+		logRateLimitStoreError(key, err, time.Now())
+		return true
+	}
+
+	setRateLimitHeaders(c, result)
+	return result.Allowed
+}
+
+// setRateLimitHeaders sets the RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset headers
+// When several limits apply to a request, the headers describe the one closest to being exhausted
+func setRateLimitHeaders(c *gin.Context, result *models.RateLimitResult) {
+	if previous, exists := c.Get(string(rateLimitResultKey)); exists {
+		if prev, ok := previous.(*models.RateLimitResult); ok && prev.Remaining <= result.Remaining && result.Allowed {
+			return
+		}
+	}
+	c.Set(string(rateLimitResultKey), result)
+
+	c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+	c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	c.Header("RateLimit-Reset", strconv.Itoa(result.ResetSeconds()))
+	if !result.Allowed {
+		c.Header("Retry-After", strconv.Itoa(result.ResetSeconds()))
+	}
+}
+
+// abortRateLimited responds 429 Too Many Requests
+func abortRateLimited(c *gin.Context, message string) {
+	c.JSON(http.StatusTooManyRequests, gin.H{
+		"success": false,
+		"error":   message,
+	})
+	c.Abort()
+}
+
+// logRateLimitStoreError meters a store failure and logs it unless another one was logged recently
+func logRateLimitStoreError(key string, err error, now time.Time) {
+	total := rateLimitStoreErrors.Add(1)
+
+	last := rateLimitStoreErrorLoggedAt.Load()
+	if now.UnixNano()-last < int64(rateLimitStoreErrorLogInterval) {
+		return
+	}
+	if !rateLimitStoreErrorLoggedAt.CompareAndSwap(last, now.UnixNano()) {
+		return
+	}
+
+	log.Printf("⚠️  Rate limit store error for %s (request not limited, %d store errors so far): %v", key, total, err)
 }
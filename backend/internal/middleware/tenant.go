@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
 	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
 	"github.com/gin-gonic/gin"
 )
@@ -14,6 +15,8 @@ type TenantContextKey string
 const (
 	// TenantIDKey is the context key for tenant ID
 	TenantIDKey TenantContextKey = "tenant_id"
+	// TenantKey is the context key for the validated tenant (*models.Tenant)
+	TenantKey TenantContextKey = "tenant"
 )
 
 // TenantMiddleware provides tenant validation middleware
@@ -75,6 +78,7 @@ func (m *TenantMiddleware) ValidateTenant() gin.HandlerFunc {
 
 		// Set tenant ID in context
 		c.Set(string(TenantIDKey), tenantID)
+		c.Set(string(TenantKey), tenant)
 
 		// Set in request context as well for use in repositories/services
 		ctx := context.WithValue(c.Request.Context(), TenantIDKey, tenantID)
@@ -84,6 +88,54 @@ func (m *TenantMiddleware) ValidateTenant() gin.HandlerFunc {
 	}
 }
 
+// PlatformAdminRequired returns a middleware that only admits admins of a platform admin tenant
+// (tenant_id and role claims of the Firebase token), for cross-tenant routes such as the subscription jobs
+// Must run after AuthRequired; API keys never reach these routes (they are only accepted on tenant routes)
+func (m *TenantMiddleware) PlatformAdminRequired() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := GetFirebaseToken(c)
+		if token == nil {
+			c.JSON(http.StatusForbidden, gin.H{
+				"success": false,
+				"error":   "platform admin access required",
+			})
+			c.Abort()
+			return
+		}
+
+		tenantID, _ := token.Claims["tenant_id"].(string)
+		role, _ := token.Claims["role"].(string)
+		if tenantID == "" || role != "admin" {
+			c.JSON(http.StatusForbidden, gin.H{
+				"success": false,
+				"error":   "platform admin access required",
+			})
+			c.Abort()
+			return
+		}
+
+		tenant, err := m.tenantRepo.Get(c.Request.Context(), tenantID)
+		if err != nil && err != repositories.ErrNotFound {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"error":   "failed to validate tenant",
+			})
+			c.Abort()
+			return
+		}
+		if err != nil || !tenant.IsActive || !tenant.IsPlatformAdmin {
+			c.JSON(http.StatusForbidden, gin.H{
+				"success": false,
+				"error":   "platform admin access required",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
 // RequireTenant returns a middleware that ensures tenant_id is present in context
 // This is useful for routes that need tenant context but don't have it in the path
 func (m *TenantMiddleware) RequireTenant() gin.HandlerFunc {
@@ -113,6 +165,16 @@ func GetTenantID(c *gin.Context) string {
 	return ""
 }
 
+// GetTenant retrieves the tenant validated by ValidateTenant (nil outside tenant routes)
+func GetTenant(c *gin.Context) *models.Tenant {
+	if tenant, exists := c.Get(string(TenantKey)); exists {
+		if t, ok := tenant.(*models.Tenant); ok {
+			return t
+		}
+	}
+	return nil
+}
+
 // GetTenantIDFromContext retrieves the tenant ID from a standard context
 func GetTenantIDFromContext(ctx context.Context) string {
 	if tenantID := ctx.Value(TenantIDKey); tenantID != nil {
@@ -46,4 +46,12 @@ type ActivityLog struct {
 
 	// Timestamp
 	Timestamp time.Time `firestore:"timestamp" json:"timestamp"`
+
+	// Cadeia de hashes por tenant (append-only, ver audit_chain.go)
+	// ChainHash = SHA256(entrada canônica + PrevHash); PII do metadata é coberta por PIIHash
+	Sequence    int64      `firestore:"sequence,omitempty" json:"sequence,omitempty"` // 1, 2, 3... sem lacunas
+	PrevHash    string     `firestore:"prev_hash,omitempty" json:"prev_hash,omitempty"`
+	ChainHash   string     `firestore:"chain_hash,omitempty" json:"chain_hash,omitempty"`
+	PIIHash     string     `firestore:"pii_hash,omitempty" json:"pii_hash,omitempty"`
+	PIIPrunedAt *time.Time `firestore:"pii_pruned_at,omitempty" json:"pii_pruned_at,omitempty"` // PII removida pela política de retenção
 }
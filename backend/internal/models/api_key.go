@@ -0,0 +1,133 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+// API key scopes granted to partner integrations
+const (
+	APIKeyScopePropertiesRead  = "properties:read"
+	APIKeyScopePropertiesWrite = "properties:write"
+	APIKeyScopeListingsRead    = "listings:read"
+	APIKeyScopeListingsWrite   = "listings:write"
+	APIKeyScopeLeadsRead       = "leads:read"
+	APIKeyScopeLeadsWrite      = "leads:write"
+	APIKeyScopeOwnersRead      = "owners:read"
+	APIKeyScopeOwnersWrite     = "owners:write"
+	APIKeyScopeBrokersRead     = "brokers:read"
+	APIKeyScopeImportRun       = "import:run"
+)
+
+// APIKeyScopes are the scopes a tenant can grant to an API key
+var APIKeyScopes = []string{
+	APIKeyScopePropertiesRead,
+	APIKeyScopePropertiesWrite,
+	APIKeyScopeListingsRead,
+	APIKeyScopeListingsWrite,
+	APIKeyScopeLeadsRead,
+	APIKeyScopeLeadsWrite,
+	APIKeyScopeOwnersRead,
+	APIKeyScopeOwnersWrite,
+	APIKeyScopeBrokersRead,
+	APIKeyScopeImportRun,
+}
+
+// IsValidAPIKeyScope checks if a scope can be granted to an API key
+func IsValidAPIKeyScope(scope string) bool {
+	for _, s := range APIKeyScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// API key format: eik_<prefix>_<secret>
+// The prefix identifies the key (lookup, listings, logs); only the SHA-256 of the whole key is stored
+const (
+	APIKeyTokenPrefix  = "eik_"
+	APIKeyPrefixLength = 8
+)
+
+// Rate limit applied when the key does not define one
+const DefaultAPIKeyRateLimitPerMinute = 60
+
+// APIKey is a tenant-issued credential for server-to-server integrations
+// Collection: /tenants/{tenantId}/api_keys/{keyId}
+type APIKey struct {
+	ID       string   `firestore:"-" json:"id"`
+	TenantID string   `firestore:"tenant_id" json:"tenant_id"`
+	Name     string   `firestore:"name" json:"name"` // ex: "Site builder", "CRM"
+	Prefix   string   `firestore:"prefix" json:"prefix"`
+	Scopes   []string `firestore:"scopes" json:"scopes"`
+
+	// KeyHash é o SHA-256 da chave completa; a chave é exibida apenas na criação
+	KeyHash string `firestore:"key_hash" json:"-"`
+
+	RateLimitPerMinute int        `firestore:"rate_limit_per_minute" json:"rate_limit_per_minute"`
+	ExpiresAt          *time.Time `firestore:"expires_at,omitempty" json:"expires_at,omitempty"`
+
+	// Uso
+	LastUsedAt *time.Time `firestore:"last_used_at,omitempty" json:"last_used_at,omitempty"`
+	LastUsedIP string     `firestore:"last_used_ip,omitempty" json:"last_used_ip,omitempty"`
+
+	// Revogação
+	RevokedAt *time.Time `firestore:"revoked_at,omitempty" json:"revoked_at,omitempty"`
+	RevokedBy string     `firestore:"revoked_by,omitempty" json:"revoked_by,omitempty"`
+
+	CreatedBy string    `firestore:"created_by,omitempty" json:"created_by,omitempty"`
+	CreatedAt time.Time `firestore:"created_at" json:"created_at"`
+	UpdatedAt time.Time `firestore:"updated_at" json:"updated_at"`
+}
+
+// HasScope checks if the key grants a scope
+func (k *APIKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// IsActive checks if the key can authenticate requests (not revoked nor expired)
+func (k *APIKey) IsActive(now time.Time) bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || now.Before(*k.ExpiresAt)
+}
+
+// RateLimit returns the requests per minute allowed for the key
+func (k *APIKey) RateLimit() int {
+	if k.RateLimitPerMinute <= 0 {
+		return DefaultAPIKeyRateLimitPerMinute
+	}
+	return k.RateLimitPerMinute
+}
+
+// IsAPIKeyToken checks if a credential has the API key format (as opposed to a Firebase ID token)
+func IsAPIKeyToken(token string) bool {
+	return strings.HasPrefix(token, APIKeyTokenPrefix)
+}
+
+// ParseAPIKeyPrefix extracts the identifying prefix of an API key
+func ParseAPIKeyPrefix(token string) (string, bool) {
+	if !IsAPIKeyToken(token) {
+		return "", false
+	}
+	parts := strings.SplitN(strings.TrimPrefix(token, APIKeyTokenPrefix), "_", 2)
+	if len(parts) != 2 || len(parts[0]) != APIKeyPrefixLength || parts[1] == "" {
+		return "", false
+	}
+	return parts[0], true
+}
+
+// HashAPIKey returns the SHA-256 hex hash stored instead of the key
+func HashAPIKey(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,94 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+// Test ParseAPIKeyPrefix
+func TestParseAPIKeyPrefix(t *testing.T) {
+	tests := []struct {
+		token    string
+		expected string
+		ok       bool
+	}{
+		{"eik_1a2b3c4d_c2VjcmV0", "1a2b3c4d", true},
+		{"eik_1a2b3c4d_sec_ret", "1a2b3c4d", true}, // o segredo pode conter "_"
+		{"eik_1a2b3c4d_", "", false},
+		{"eik_1a2b_c2VjcmV0", "", false},
+		{"eik_1a2b3c4dc2VjcmV0", "", false},
+		{"eyJhbGciOiJSUzI1NiJ9.payload.sig", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		prefix, ok := ParseAPIKeyPrefix(tt.token)
+		if prefix != tt.expected || ok != tt.ok {
+			t.Errorf("ParseAPIKeyPrefix(%q) = (%q, %v), expected (%q, %v)", tt.token, prefix, ok, tt.expected, tt.ok)
+		}
+	}
+}
+
+// Test HashAPIKey is deterministic and does not contain the key
+func TestHashAPIKey(t *testing.T) {
+	token := "eik_1a2b3c4d_c2VjcmV0"
+	hash := HashAPIKey(token)
+
+	if len(hash) != 64 {
+		t.Errorf("hash length = %d, expected 64", len(hash))
+	}
+	if hash != HashAPIKey(token) {
+		t.Error("hash should be deterministic")
+	}
+	if hash == HashAPIKey("eik_1a2b3c4d_b3V0cm8") {
+		t.Error("different keys should have different hashes")
+	}
+}
+
+// Test APIKey.IsActive with revocation and expiry
+func TestAPIKeyIsActive(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	tests := []struct {
+		name     string
+		key      APIKey
+		expected bool
+	}{
+		{"no expiry", APIKey{}, true},
+		{"not expired", APIKey{ExpiresAt: &future}, true},
+		{"expired", APIKey{ExpiresAt: &past}, false},
+		{"revoked", APIKey{RevokedAt: &past}, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.key.IsActive(now); got != tt.expected {
+			t.Errorf("%s: IsActive = %v, expected %v", tt.name, got, tt.expected)
+		}
+	}
+}
+
+// Test APIKey.HasScope and RateLimit default
+func TestAPIKeyScopesAndRateLimit(t *testing.T) {
+	key := APIKey{Scopes: []string{APIKeyScopePropertiesRead, APIKeyScopeLeadsWrite}}
+
+	if !key.HasScope(APIKeyScopePropertiesRead) || !key.HasScope(APIKeyScopeLeadsWrite) {
+		t.Error("granted scopes should be present")
+	}
+	if key.HasScope(APIKeyScopePropertiesWrite) || key.HasScope(APIKeyScopeImportRun) {
+		t.Error("scopes not granted should be absent")
+	}
+
+	if key.RateLimit() != DefaultAPIKeyRateLimitPerMinute {
+		t.Errorf("RateLimit = %d, expected default %d", key.RateLimit(), DefaultAPIKeyRateLimitPerMinute)
+	}
+	key.RateLimitPerMinute = 600
+	if key.RateLimit() != 600 {
+		t.Errorf("RateLimit = %d, expected 600", key.RateLimit())
+	}
+
+	if !IsValidAPIKeyScope("import:run") || IsValidAPIKeyScope("tenants:write") {
+		t.Error("IsValidAPIKeyScope mismatch")
+	}
+}
@@ -0,0 +1,336 @@
+package models
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// AuditGenesisHash is the previous hash of the first entry of a tenant chain
+const AuditGenesisHash = "0000000000000000000000000000000000000000000000000000000000000000"
+
+// Audit chain break reasons
+const (
+	AuditBreakGap                 = "gap"                  // sequência ausente (entrada removida)
+	AuditBreakPrevHashMismatch    = "prev_hash_mismatch"   // entrada não aponta para a anterior
+	AuditBreakHashMismatch        = "hash_mismatch"        // conteúdo da entrada foi alterado
+	AuditBreakPIIMismatch         = "pii_mismatch"         // dados pessoais alterados (antes da poda de retenção)
+	AuditBreakCheckpointMismatch  = "checkpoint_mismatch"  // hash diverge do checkpoint assinado
+	AuditBreakCheckpointSignature = "checkpoint_signature" // assinatura do checkpoint inválida
+	AuditBreakHeadMismatch        = "head_mismatch"        // entradas removidas do fim da cadeia
+)
+
+// AuditChainHead is the tip of a tenant activity log hash chain
+// Document: /tenants/{tenantId}/audit_chain/head
+// Atualizado na mesma transação que grava cada ActivityLog (ver ActivityLogRepository.Create)
+type AuditChainHead struct {
+	Sequence  int64     `firestore:"sequence" json:"sequence"`
+	LastHash  string    `firestore:"last_hash" json:"last_hash"`
+	LastLogID string    `firestore:"last_log_id" json:"last_log_id"`
+	UpdatedAt time.Time `firestore:"updated_at" json:"updated_at"`
+}
+
+// AuditCheckpoint is a signed snapshot of the chain head (CRECI/COFECI audits)
+// Collection: /tenants/{tenantId}/audit_checkpoints/{sequence}
+// A assinatura (HMAC-SHA256 com a chave do servidor) impede reescrever a cadeia inteira até o checkpoint
+type AuditCheckpoint struct {
+	ID        string    `firestore:"-" json:"id"`
+	TenantID  string    `firestore:"tenant_id" json:"tenant_id"`
+	Sequence  int64     `firestore:"sequence" json:"sequence"`
+	ChainHash string    `firestore:"chain_hash" json:"chain_hash"`
+	LogID     string    `firestore:"log_id" json:"log_id"`
+	KeyID     string    `firestore:"key_id" json:"key_id"`
+	Signature string    `firestore:"signature" json:"signature"`
+	CreatedBy string    `firestore:"created_by,omitempty" json:"created_by,omitempty"` // user ID ou "system"
+	CreatedAt time.Time `firestore:"created_at" json:"created_at"`
+}
+
+// AuditChainBreak describes the first broken link found by the verification
+type AuditChainBreak struct {
+	Sequence int64  `json:"sequence"`
+	LogID    string `json:"log_id,omitempty"`
+	Reason   string `json:"reason"`
+	Expected string `json:"expected,omitempty"`
+	Actual   string `json:"actual,omitempty"`
+}
+
+// AuditVerificationReport is the result of a chain verification
+type AuditVerificationReport struct {
+	TenantID            string           `json:"tenant_id"`
+	FromSequence        int64            `json:"from_sequence"`
+	LastSequence        int64            `json:"last_sequence"` // última entrada verificada
+	HeadSequence        int64            `json:"head_sequence"`
+	EntriesVerified     int              `json:"entries_verified"`
+	CheckpointsVerified int              `json:"checkpoints_verified"`
+	SignaturesVerified  bool             `json:"signatures_verified"` // false quando a chave de assinatura não está configurada
+	Complete            bool             `json:"complete"`            // verificou até o head
+	Valid               bool             `json:"valid"`
+	FirstBreak          *AuditChainBreak `json:"first_break,omitempty"`
+	VerifiedAt          time.Time        `json:"verified_at"`
+}
+
+// auditChainPayload is the canonical content hashed for each entry (field order is fixed)
+type auditChainPayload struct {
+	TenantID  string                 `json:"tenant_id"`
+	Sequence  int64                  `json:"sequence"`
+	PrevHash  string                 `json:"prev_hash"`
+	EventID   string                 `json:"event_id"`
+	EventHash string                 `json:"event_hash"`
+	RequestID string                 `json:"request_id"`
+	EventType string                 `json:"event_type"`
+	ActorType string                 `json:"actor_type"`
+	ActorID   string                 `json:"actor_id"`
+	Metadata  map[string]interface{} `json:"metadata"`
+	PIIHash   string                 `json:"pii_hash"`
+	Timestamp string                 `json:"timestamp"`
+}
+
+// ComputeChainHash returns the hash of the entry linked to PrevHash
+// PII metadata keys (ActivityLogPIIMetadataKeys) are covered by PIIHash instead, so the
+// retention policy can prune them without breaking the chain
+func (l *ActivityLog) ComputeChainHash() string {
+	metadata := make(map[string]interface{}, len(l.Metadata))
+	for key, value := range NormalizeAuditMetadata(l.Metadata) {
+		if !isActivityLogPIIKey(key) {
+			metadata[key] = value
+		}
+	}
+
+	return auditHash(auditChainPayload{
+		TenantID:  l.TenantID,
+		Sequence:  l.Sequence,
+		PrevHash:  l.PrevHash,
+		EventID:   l.EventID,
+		EventHash: l.EventHash,
+		RequestID: l.RequestID,
+		EventType: l.EventType,
+		ActorType: string(l.ActorType),
+		ActorID:   l.ActorID,
+		Metadata:  metadata,
+		PIIHash:   l.PIIHash,
+		Timestamp: l.Timestamp.UTC().Truncate(time.Microsecond).Format(time.RFC3339Nano),
+	})
+}
+
+// ActivityLogPIIHash returns the hash of the PII metadata keys present ("" when there is none)
+func ActivityLogPIIHash(metadata map[string]interface{}) string {
+	normalized := NormalizeAuditMetadata(metadata)
+	pii := make(map[string]interface{})
+	for _, key := range ActivityLogPIIMetadataKeys {
+		if value, ok := normalized[key]; ok {
+			pii[key] = value
+		}
+	}
+	if len(pii) == 0 {
+		return ""
+	}
+	return auditHash(pii)
+}
+
+// HasActivityLogPII checks if any PII metadata key is present
+func HasActivityLogPII(metadata map[string]interface{}) bool {
+	for _, key := range ActivityLogPIIMetadataKeys {
+		if _, ok := metadata[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// PrepareChainEntry links a new entry to the chain (called inside the repository transaction)
+// Metadata and timestamp are normalized to what Firestore returns, so the hash verifies after a read
+func (l *ActivityLog) PrepareChainEntry(sequence int64, prevHash string) {
+	l.Metadata = NormalizeAuditMetadata(l.Metadata)
+	l.Timestamp = l.Timestamp.UTC().Truncate(time.Microsecond)
+	l.Sequence = sequence
+	l.PrevHash = prevHash
+	l.PIIHash = ActivityLogPIIHash(l.Metadata)
+	l.ChainHash = l.ComputeChainHash()
+}
+
+// AuditChainVerifier checks entries in sequence order and reports the first broken link
+type AuditChainVerifier struct {
+	nextSequence int64
+	prevHash     string
+}
+
+// NewAuditChainVerifier starts a verification after a trusted entry (0 and AuditGenesisHash for the whole chain)
+func NewAuditChainVerifier(afterSequence int64, prevHash string) *AuditChainVerifier {
+	return &AuditChainVerifier{
+		nextSequence: afterSequence + 1,
+		prevHash:     prevHash,
+	}
+}
+
+// NextSequence returns the sequence expected for the next entry
+func (v *AuditChainVerifier) NextSequence() int64 {
+	return v.nextSequence
+}
+
+// Check verifies the next entry of the chain (nil when the link is valid)
+func (v *AuditChainVerifier) Check(l *ActivityLog) *AuditChainBreak {
+	if l.Sequence != v.nextSequence {
+		return &AuditChainBreak{
+			Sequence: v.nextSequence,
+			LogID:    l.ID,
+			Reason:   AuditBreakGap,
+			Expected: fmt.Sprintf("%d", v.nextSequence),
+			Actual:   fmt.Sprintf("%d", l.Sequence),
+		}
+	}
+	if l.PrevHash != v.prevHash {
+		return &AuditChainBreak{Sequence: l.Sequence, LogID: l.ID, Reason: AuditBreakPrevHashMismatch, Expected: v.prevHash, Actual: l.PrevHash}
+	}
+	if hash := l.ComputeChainHash(); hash != l.ChainHash {
+		return &AuditChainBreak{Sequence: l.Sequence, LogID: l.ID, Reason: AuditBreakHashMismatch, Expected: l.ChainHash, Actual: hash}
+	}
+
+	// PII can only be removed (retention pruning), never changed
+	if l.PIIPrunedAt == nil || HasActivityLogPII(l.Metadata) {
+		if hash := ActivityLogPIIHash(l.Metadata); hash != l.PIIHash {
+			return &AuditChainBreak{Sequence: l.Sequence, LogID: l.ID, Reason: AuditBreakPIIMismatch, Expected: l.PIIHash, Actual: hash}
+		}
+	}
+
+	v.nextSequence++
+	v.prevHash = l.ChainHash
+	return nil
+}
+
+// SigningPayload returns the content signed by a checkpoint
+func (c *AuditCheckpoint) SigningPayload() string {
+	return fmt.Sprintf("%s|%d|%s|%s|%s", c.TenantID, c.Sequence, c.ChainHash, c.LogID, c.CreatedAt.UTC().Truncate(time.Microsecond).Format(time.RFC3339Nano))
+}
+
+// Sign signs the checkpoint with the server key (HMAC-SHA256)
+func (c *AuditCheckpoint) Sign(key []byte) {
+	c.KeyID = AuditKeyID(key)
+	c.Signature = auditHMAC(key, c.SigningPayload())
+}
+
+// VerifySignature checks the checkpoint signature with the server key
+func (c *AuditCheckpoint) VerifySignature(key []byte) bool {
+	if c.KeyID != AuditKeyID(key) {
+		return false
+	}
+	return hmac.Equal([]byte(c.Signature), []byte(auditHMAC(key, c.SigningPayload())))
+}
+
+// AuditKeyID identifies a signing key without revealing it
+func AuditKeyID(key []byte) string {
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// NormalizeAuditMetadata converts metadata values to the types Firestore returns when reading them back
+// (int64, float64, UTC time with microsecond precision, []interface{}, map[string]interface{})
+func NormalizeAuditMetadata(metadata map[string]interface{}) map[string]interface{} {
+	if metadata == nil {
+		return nil
+	}
+	normalized := make(map[string]interface{}, len(metadata))
+	for key, value := range metadata {
+		normalized[key] = normalizeAuditValue(value)
+	}
+	return normalized
+}
+
+// normalizeAuditValue normalizes a single metadata value
+func normalizeAuditValue(value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+
+	switch v := value.(type) {
+	case time.Time:
+		return v.UTC().Truncate(time.Microsecond)
+	case *time.Time:
+		if v == nil {
+			return nil
+		}
+		return v.UTC().Truncate(time.Microsecond)
+	case []byte:
+		return v
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.String:
+		return rv.String()
+	case reflect.Bool:
+		return rv.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			return nil
+		}
+		return normalizeAuditValue(rv.Elem().Interface())
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return nil
+		}
+		items := make([]interface{}, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			items[i] = normalizeAuditValue(rv.Index(i).Interface())
+		}
+		return items
+	case reflect.Map:
+		if rv.IsNil() {
+			return nil
+		}
+		m := make(map[string]interface{}, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			m[fmt.Sprint(iter.Key().Interface())] = normalizeAuditValue(iter.Value().Interface())
+		}
+		return m
+	}
+
+	// Structs and other types: stored through their JSON representation
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return fmt.Sprint(value)
+	}
+	return normalizeAuditValue(decoded)
+}
+
+// isActivityLogPIIKey checks if a metadata key holds personal data
+func isActivityLogPIIKey(key string) bool {
+	for _, piiKey := range ActivityLogPIIMetadataKeys {
+		if key == piiKey {
+			return true
+		}
+	}
+	return false
+}
+
+// auditHash returns the SHA-256 of the canonical JSON of a value (map keys are sorted by encoding/json)
+func auditHash(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		// e.g. NaN: fall back to the textual form (fmt prints map keys sorted)
+		data = []byte(fmt.Sprintf("%v", value))
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// auditHMAC returns the hex HMAC-SHA256 of a payload
+func auditHMAC(key []byte, payload string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
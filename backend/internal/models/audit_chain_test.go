@@ -0,0 +1,206 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+// buildTestChain creates n linked entries (as written by ActivityLogRepository.Create)
+func buildTestChain(n int) []*ActivityLog {
+	base := time.Date(2025, 3, 10, 14, 30, 0, 123456789, time.FixedZone("BRT", -3*3600))
+	logs := make([]*ActivityLog, 0, n)
+	prevHash := AuditGenesisHash
+	for i := 0; i < n; i++ {
+		log := &ActivityLog{
+			ID:        "log-" + string(rune('a'+i)),
+			TenantID:  "tenant-1",
+			EventType: "lead_created_form",
+			ActorType: ActorTypeSystem,
+			Metadata: map[string]interface{}{
+				"lead_id":       "lead-1",
+				"consent_given": true,
+				"attempt":       i,
+				"email":         "maria@example.com",
+				"expires_at":    base.Add(time.Hour),
+			},
+			Timestamp: base.Add(time.Duration(i) * time.Minute),
+		}
+		log.PrepareChainEntry(int64(i+1), prevHash)
+		prevHash = log.ChainHash
+		logs = append(logs, log)
+	}
+	return logs
+}
+
+// verifyTestChain returns the first break of a chain (nil when valid)
+func verifyTestChain(logs []*ActivityLog) *AuditChainBreak {
+	verifier := NewAuditChainVerifier(0, AuditGenesisHash)
+	for _, log := range logs {
+		if brk := verifier.Check(log); brk != nil {
+			return brk
+		}
+	}
+	return nil
+}
+
+// Test that a chain survives the Firestore round trip (int64 numbers, UTC microsecond times)
+func TestAuditChainRoundTrip(t *testing.T) {
+	logs := buildTestChain(3)
+
+	for _, log := range logs {
+		log.Metadata["expires_at"] = log.Metadata["expires_at"].(time.Time).In(time.UTC)
+		log.Timestamp = log.Timestamp.In(time.UTC)
+	}
+
+	if brk := verifyTestChain(logs); brk != nil {
+		t.Fatalf("valid chain reported broken: %+v", brk)
+	}
+	if logs[0].PrevHash != AuditGenesisHash {
+		t.Errorf("first entry PrevHash = %s, expected genesis", logs[0].PrevHash)
+	}
+	if logs[1].PrevHash != logs[0].ChainHash {
+		t.Errorf("entries are not linked")
+	}
+}
+
+// Test AuditChainVerifier break detection
+func TestAuditChainVerifier(t *testing.T) {
+	tests := []struct {
+		name           string
+		tamper         func(logs []*ActivityLog) []*ActivityLog
+		expectedReason string
+		expectedSeq    int64
+	}{
+		{
+			name:   "Valid chain",
+			tamper: func(logs []*ActivityLog) []*ActivityLog { return logs },
+		},
+		{
+			name: "Removed entry",
+			tamper: func(logs []*ActivityLog) []*ActivityLog {
+				return append(logs[:1], logs[2:]...)
+			},
+			expectedReason: AuditBreakGap,
+			expectedSeq:    2,
+		},
+		{
+			name: "Modified metadata",
+			tamper: func(logs []*ActivityLog) []*ActivityLog {
+				logs[2].Metadata["lead_id"] = "lead-2"
+				return logs
+			},
+			expectedReason: AuditBreakHashMismatch,
+			expectedSeq:    3,
+		},
+		{
+			name: "Modified entry with recomputed hash",
+			tamper: func(logs []*ActivityLog) []*ActivityLog {
+				logs[1].EventType = "lead_deleted"
+				logs[1].ChainHash = logs[1].ComputeChainHash()
+				return logs
+			},
+			expectedReason: AuditBreakPrevHashMismatch,
+			expectedSeq:    3,
+		},
+		{
+			name: "Modified PII",
+			tamper: func(logs []*ActivityLog) []*ActivityLog {
+				logs[0].Metadata["email"] = "other@example.com"
+				return logs
+			},
+			expectedReason: AuditBreakPIIMismatch,
+			expectedSeq:    1,
+		},
+		{
+			name: "PII pruned by the retention policy",
+			tamper: func(logs []*ActivityLog) []*ActivityLog {
+				prunedAt := time.Now()
+				delete(logs[1].Metadata, "email")
+				logs[1].PIIPrunedAt = &prunedAt
+				return logs
+			},
+		},
+		{
+			name: "PII removed without pruning mark",
+			tamper: func(logs []*ActivityLog) []*ActivityLog {
+				delete(logs[1].Metadata, "email")
+				return logs
+			},
+			expectedReason: AuditBreakPIIMismatch,
+			expectedSeq:    2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			brk := verifyTestChain(tt.tamper(buildTestChain(4)))
+			if tt.expectedReason == "" {
+				if brk != nil {
+					t.Fatalf("expected valid chain, got %+v", brk)
+				}
+				return
+			}
+			if brk == nil {
+				t.Fatalf("expected break %s, chain reported valid", tt.expectedReason)
+			}
+			if brk.Reason != tt.expectedReason || brk.Sequence != tt.expectedSeq {
+				t.Errorf("break = %s at %d, expected %s at %d", brk.Reason, brk.Sequence, tt.expectedReason, tt.expectedSeq)
+			}
+		})
+	}
+}
+
+// Test AuditCheckpoint signature
+func TestAuditCheckpointSignature(t *testing.T) {
+	key := []byte("test-signing-key")
+	checkpoint := &AuditCheckpoint{
+		TenantID:  "tenant-1",
+		Sequence:  42,
+		ChainHash: "abc123",
+		LogID:     "log-42",
+		CreatedAt: time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC),
+	}
+	checkpoint.Sign(key)
+
+	if !checkpoint.VerifySignature(key) {
+		t.Fatal("signature should verify with the signing key")
+	}
+	if checkpoint.VerifySignature([]byte("other-key")) {
+		t.Error("signature should not verify with another key")
+	}
+
+	checkpoint.ChainHash = "def456"
+	if checkpoint.VerifySignature(key) {
+		t.Error("signature should not verify after the checkpoint is modified")
+	}
+}
+
+// Test NormalizeAuditMetadata
+func TestNormalizeAuditMetadata(t *testing.T) {
+	type status string
+	when := time.Date(2025, 3, 10, 14, 30, 0, 123456789, time.FixedZone("BRT", -3*3600))
+
+	normalized := NormalizeAuditMetadata(map[string]interface{}{
+		"count":  3,
+		"status": status("active"),
+		"ids":    []string{"a", "b"},
+		"when":   when,
+		"price":  float32(1.5),
+	})
+
+	if v, ok := normalized["count"].(int64); !ok || v != 3 {
+		t.Errorf("count = %#v, expected int64(3)", normalized["count"])
+	}
+	if v, ok := normalized["status"].(string); !ok || v != "active" {
+		t.Errorf("status = %#v, expected string", normalized["status"])
+	}
+	if v, ok := normalized["ids"].([]interface{}); !ok || len(v) != 2 {
+		t.Errorf("ids = %#v, expected []interface{}", normalized["ids"])
+	}
+	if v, ok := normalized["when"].(time.Time); !ok || v.Location() != time.UTC || v.Nanosecond() != 123456000 {
+		t.Errorf("when = %#v, expected UTC with microsecond precision", normalized["when"])
+	}
+	if v, ok := normalized["price"].(float64); !ok || v != 1.5 {
+		t.Errorf("price = %#v, expected float64(1.5)", normalized["price"])
+	}
+}
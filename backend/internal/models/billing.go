@@ -0,0 +1,254 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Usage metrics (UsageDaily fields)
+const (
+	UsageMetricActiveListings = "active_listings" // gauge: active listings on the day (billed per listing-day)
+	UsageMetricImports        = "imports"         // counter: import batches started
+	UsageMetricMessagesSent   = "messages_sent"   // counter: messages sent to leads and owners
+	UsageMetricStorageBytes   = "storage_bytes"   // gauge: bytes stored (billed per GB-month)
+)
+
+// UsageDaily is the daily usage rollup of a tenant
+// Collection: /tenants/{tenantId}/usage_daily/{YYYYMMDD}
+// Counters are incremented atomically when the event happens; gauges are set by the daily snapshot job
+type UsageDaily struct {
+	ID       string `firestore:"-" json:"id"`
+	TenantID string `firestore:"tenant_id" json:"tenant_id"`
+	Date     string `firestore:"date" json:"date"` // YYYY-MM-DD (America/Sao_Paulo)
+
+	ActiveListings int64 `firestore:"active_listings" json:"active_listings"`
+	Imports        int64 `firestore:"imports" json:"imports"`
+	MessagesSent   int64 `firestore:"messages_sent" json:"messages_sent"`
+	StorageBytes   int64 `firestore:"storage_bytes" json:"storage_bytes"`
+
+	UpdatedAt time.Time `firestore:"updated_at" json:"updated_at"`
+}
+
+// MonthlyUsage is the usage of a tenant aggregated over a billing period
+type MonthlyUsage struct {
+	Period          string `firestore:"period" json:"period"`                       // YYYY-MM
+	Days            int    `firestore:"days" json:"days"`                           // days with a usage rollup
+	ListingDays     int64  `firestore:"listing_days" json:"listing_days"`           // sum of the daily active listings
+	Imports         int64  `firestore:"imports" json:"imports"`                     // import batches
+	MessagesSent    int64  `firestore:"messages_sent" json:"messages_sent"`         // messages sent
+	AvgStorageBytes int64  `firestore:"avg_storage_bytes" json:"avg_storage_bytes"` // average of the daily storage snapshots
+}
+
+// AggregateMonthlyUsage sums the daily rollups of a period
+func AggregateMonthlyUsage(period string, days []*UsageDaily) *MonthlyUsage {
+	usage := &MonthlyUsage{Period: period, Days: len(days)}
+
+	var storageBytes int64
+	for _, day := range days {
+		usage.ListingDays += day.ActiveListings
+		usage.Imports += day.Imports
+		usage.MessagesSent += day.MessagesSent
+		storageBytes += day.StorageBytes
+	}
+	if len(days) > 0 {
+		usage.AvgStorageBytes = storageBytes / int64(len(days))
+	}
+
+	return usage
+}
+
+// Value returns the billable amount of a usage metric
+func (u *MonthlyUsage) Value(metric string) int64 {
+	switch metric {
+	case UsageMetricActiveListings:
+		return u.ListingDays
+	case UsageMetricImports:
+		return u.Imports
+	case UsageMetricMessagesSent:
+		return u.MessagesSent
+	case UsageMetricStorageBytes:
+		return u.AvgStorageBytes
+	}
+	return 0
+}
+
+// UsagePrice is the price of a metered usage metric in a plan
+// Usage above Included is billed in blocks of UnitSize (rounded up) at UnitPriceCents each
+type UsagePrice struct {
+	Metric         string `json:"metric"`
+	Description    string `json:"description"`
+	Included       int64  `json:"included"`  // units included in the base fee
+	UnitSize       int64  `json:"unit_size"` // units per billed block (ex: 1 GB of storage bytes)
+	UnitPriceCents int64  `json:"unit_price_cents"`
+}
+
+// BillableQuantity returns the billed blocks for a usage amount
+func (p UsagePrice) BillableQuantity(used int64) int64 {
+	excess := used - p.Included
+	if excess <= 0 {
+		return 0
+	}
+
+	unitSize := p.UnitSize
+	if unitSize <= 0 {
+		unitSize = 1
+	}
+	return (excess + unitSize - 1) / unitSize
+}
+
+// InvoiceStatus defines the payment status of an invoice
+type InvoiceStatus string
+
+const (
+	InvoiceStatusOpen    InvoiceStatus = "open"    // aguardando pagamento
+	InvoiceStatusPaid    InvoiceStatus = "paid"    // paga (ou sem valor a cobrar)
+	InvoiceStatusOverdue InvoiceStatus = "overdue" // cobrança esgotada, assinatura expirada
+	InvoiceStatusVoid    InvoiceStatus = "void"    // cancelada
+)
+
+// PaymentMethod defines how an invoice is charged
+type PaymentMethod string
+
+const (
+	PaymentMethodPix  PaymentMethod = "pix"
+	PaymentMethodCard PaymentMethod = "card"
+)
+
+// IsValidPaymentMethod checks if a payment method is supported
+func IsValidPaymentMethod(method PaymentMethod) bool {
+	return method == PaymentMethodPix || method == PaymentMethodCard
+}
+
+// DunningRetryDays are the days after the due date when an unpaid invoice is charged again
+// After the last retry fails the invoice becomes overdue and the subscription expires
+var DunningRetryDays = []int{1, 3, 7}
+
+// InvoicePaymentDays is how many days after issue an invoice is due
+const InvoicePaymentDays = 5
+
+// InvoiceLineItem is a line of an invoice (base fee or metered usage)
+type InvoiceLineItem struct {
+	Metric         string `firestore:"metric,omitempty" json:"metric,omitempty"` // empty for the base fee
+	Description    string `firestore:"description" json:"description"`
+	Used           int64  `firestore:"used,omitempty" json:"used,omitempty"` // metered usage in the period
+	Included       int64  `firestore:"included,omitempty" json:"included,omitempty"`
+	Quantity       int64  `firestore:"quantity" json:"quantity"` // billed units
+	UnitPriceCents int64  `firestore:"unit_price_cents" json:"unit_price_cents"`
+	AmountCents    int64  `firestore:"amount_cents" json:"amount_cents"`
+}
+
+// Invoice is the monthly bill of a tenant (base fee of the plan + metered usage)
+// Collection: /tenants/{tenantId}/invoices/{YYYYMM}
+// O ID determinístico por período torna a geração mensal idempotente
+type Invoice struct {
+	ID       string `firestore:"-" json:"id"`
+	TenantID string `firestore:"tenant_id" json:"tenant_id"`
+	Number   string `firestore:"number" json:"number"` // FAT-YYYYMM-<tenant>
+
+	// Período de referência
+	Period      string    `firestore:"period" json:"period"` // YYYY-MM
+	PeriodStart time.Time `firestore:"period_start" json:"period_start"`
+	PeriodEnd   time.Time `firestore:"period_end" json:"period_end"`
+
+	// Valores
+	Plan       string            `firestore:"plan" json:"plan"`
+	Currency   string            `firestore:"currency" json:"currency"` // BRL
+	LineItems  []InvoiceLineItem `firestore:"line_items" json:"line_items"`
+	TotalCents int64             `firestore:"total_cents" json:"total_cents"`
+	Usage      *MonthlyUsage     `firestore:"usage,omitempty" json:"usage,omitempty"`
+
+	// Pagamento
+	Status        InvoiceStatus `firestore:"status" json:"status"`
+	DueAt         time.Time     `firestore:"due_at" json:"due_at"`
+	PaidAt        *time.Time    `firestore:"paid_at,omitempty" json:"paid_at,omitempty"`
+	PaymentMethod PaymentMethod `firestore:"payment_method,omitempty" json:"payment_method,omitempty"`
+	ChargeID      string        `firestore:"charge_id,omitempty" json:"charge_id,omitempty"`           // gateway charge of the last attempt
+	PixCopyPaste  string        `firestore:"pix_copy_paste,omitempty" json:"pix_copy_paste,omitempty"` // PIX "copia e cola"
+	PixExpiresAt  *time.Time    `firestore:"pix_expires_at,omitempty" json:"pix_expires_at,omitempty"`
+
+	// Dunning (tentativas de cobrança)
+	Attempts      int        `firestore:"attempts" json:"attempts"`
+	LastAttemptAt *time.Time `firestore:"last_attempt_at,omitempty" json:"last_attempt_at,omitempty"`
+	NextAttemptAt *time.Time `firestore:"next_attempt_at,omitempty" json:"next_attempt_at,omitempty"`
+	LastError     string     `firestore:"last_error,omitempty" json:"last_error,omitempty"`
+
+	// Metadata
+	CreatedAt time.Time `firestore:"created_at" json:"created_at"`
+	UpdatedAt time.Time `firestore:"updated_at" json:"updated_at"`
+}
+
+// InvoiceID returns the deterministic invoice ID of a period (YYYY-MM -> YYYYMM)
+func InvoiceID(period string) string {
+	if len(period) == 7 {
+		return period[:4] + period[5:]
+	}
+	return period
+}
+
+// InvoiceNumber returns the human-readable invoice number (FAT-YYYYMM-<first 6 chars of the tenant ID>)
+func InvoiceNumber(tenantID, period string) string {
+	short := tenantID
+	if len(short) > 6 {
+		short = short[:6]
+	}
+	return fmt.Sprintf("FAT-%s-%s", InvoiceID(period), short)
+}
+
+// BuildInvoiceLineItems prices the base fee and the metered usage of a plan
+// Usage lines are only added when there is usage; they are billed only above the included units
+func BuildInvoiceLineItems(plan Plan, usage *MonthlyUsage) []InvoiceLineItem {
+	items := make([]InvoiceLineItem, 0, len(plan.UsagePrices)+1)
+
+	if plan.MonthlyPriceCents > 0 {
+		items = append(items, InvoiceLineItem{
+			Description:    fmt.Sprintf("Plano %s (mensalidade)", plan.Name),
+			Quantity:       1,
+			UnitPriceCents: plan.MonthlyPriceCents,
+			AmountCents:    plan.MonthlyPriceCents,
+		})
+	}
+
+	for _, price := range plan.UsagePrices {
+		used := usage.Value(price.Metric)
+		if used == 0 {
+			continue
+		}
+
+		quantity := price.BillableQuantity(used)
+		items = append(items, InvoiceLineItem{
+			Metric:         price.Metric,
+			Description:    price.Description,
+			Used:           used,
+			Included:       price.Included,
+			Quantity:       quantity,
+			UnitPriceCents: price.UnitPriceCents,
+			AmountCents:    quantity * price.UnitPriceCents,
+		})
+	}
+
+	return items
+}
+
+// InvoiceTotal sums the amounts of the line items
+func InvoiceTotal(items []InvoiceLineItem) int64 {
+	var total int64
+	for _, item := range items {
+		total += item.AmountCents
+	}
+	return total
+}
+
+// NextDunningAttempt returns when the invoice should be charged again, or nil when the retries are exhausted
+// Attempts counts the charges already made (the first one when the invoice is issued)
+func (i *Invoice) NextDunningAttempt() *time.Time {
+	retry := i.Attempts - 1
+	if retry < 0 {
+		retry = 0
+	}
+	if retry >= len(DunningRetryDays) {
+		return nil
+	}
+
+	next := i.DueAt.AddDate(0, 0, DunningRetryDays[retry])
+	return &next
+}
@@ -0,0 +1,122 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+// Test AggregateMonthlyUsage sums counters and averages storage
+func TestAggregateMonthlyUsage(t *testing.T) {
+	days := []*UsageDaily{
+		{ActiveListings: 10, Imports: 1, MessagesSent: 5, StorageBytes: 100},
+		{ActiveListings: 12, MessagesSent: 7, StorageBytes: 300},
+	}
+
+	usage := AggregateMonthlyUsage("2026-09", days)
+	if usage.Days != 2 || usage.ListingDays != 22 || usage.Imports != 1 || usage.MessagesSent != 12 || usage.AvgStorageBytes != 200 {
+		t.Errorf("unexpected usage: %+v", usage)
+	}
+
+	if empty := AggregateMonthlyUsage("2026-09", nil); empty.Days != 0 || empty.AvgStorageBytes != 0 {
+		t.Errorf("unexpected empty usage: %+v", empty)
+	}
+}
+
+// Test UsagePrice.BillableQuantity (included units and rounding up to blocks)
+func TestUsagePriceBillableQuantity(t *testing.T) {
+	storage := UsagePrice{Included: 10 << 30, UnitSize: 1 << 30}
+	messages := UsagePrice{Included: 100}
+
+	tests := []struct {
+		price    UsagePrice
+		used     int64
+		expected int64
+	}{
+		{storage, 5 << 30, 0},
+		{storage, 10 << 30, 0},
+		{storage, 10<<30 + 1, 1},
+		{storage, 12 << 30, 2},
+		{messages, 99, 0},
+		{messages, 150, 50},
+	}
+
+	for _, tt := range tests {
+		if got := tt.price.BillableQuantity(tt.used); got != tt.expected {
+			t.Errorf("BillableQuantity(%d) = %d, expected %d", tt.used, got, tt.expected)
+		}
+	}
+}
+
+// Test BuildInvoiceLineItems prices the base fee and the usage above the included units
+func TestBuildInvoiceLineItems(t *testing.T) {
+	plan := Plan{
+		Name:              "Full",
+		MonthlyPriceCents: 10000,
+		UsagePrices: []UsagePrice{
+			{Metric: UsageMetricMessagesSent, Description: "Mensagens", Included: 100, UnitSize: 1, UnitPriceCents: 5},
+			{Metric: UsageMetricImports, Description: "Importações", Included: 2, UnitSize: 1, UnitPriceCents: 500},
+		},
+	}
+	usage := &MonthlyUsage{MessagesSent: 150, Imports: 0}
+
+	items := BuildInvoiceLineItems(plan, usage)
+	if len(items) != 2 {
+		t.Fatalf("expected base fee and messages lines, got %+v", items)
+	}
+	if items[0].AmountCents != 10000 || items[0].Metric != "" {
+		t.Errorf("unexpected base fee line: %+v", items[0])
+	}
+	if items[1].Metric != UsageMetricMessagesSent || items[1].Quantity != 50 || items[1].AmountCents != 250 {
+		t.Errorf("unexpected messages line: %+v", items[1])
+	}
+	if total := InvoiceTotal(items); total != 10250 {
+		t.Errorf("InvoiceTotal = %d, expected 10250", total)
+	}
+
+	if free := BuildInvoiceLineItems(GetPlan(SubscriptionPlanFree), usage); InvoiceTotal(free) != 0 {
+		t.Errorf("free plan should not be billed, got %+v", free)
+	}
+}
+
+// Test InvoiceID and InvoiceNumber
+func TestInvoiceNumber(t *testing.T) {
+	if got := InvoiceID("2026-09"); got != "202609" {
+		t.Errorf("InvoiceID = %s", got)
+	}
+	if got := InvoiceNumber("abcdefghij", "2026-09"); got != "FAT-202609-abcdef" {
+		t.Errorf("InvoiceNumber = %s", got)
+	}
+	if got := InvoiceNumber("abc", "2026-09"); got != "FAT-202609-abc" {
+		t.Errorf("InvoiceNumber = %s", got)
+	}
+}
+
+// Test Invoice.NextDunningAttempt follows the retry schedule until exhausted
+func TestInvoiceNextDunningAttempt(t *testing.T) {
+	due := time.Date(2026, 10, 6, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		attempts int
+		expected int // days after due, -1 = exhausted
+	}{
+		{0, 1},
+		{1, 1},
+		{2, 3},
+		{3, 7},
+		{4, -1},
+	}
+
+	for _, tt := range tests {
+		invoice := &Invoice{DueAt: due, Attempts: tt.attempts}
+		next := invoice.NextDunningAttempt()
+		if tt.expected < 0 {
+			if next != nil {
+				t.Errorf("attempts=%d: expected retries exhausted, got %v", tt.attempts, next)
+			}
+			continue
+		}
+		if next == nil || !next.Equal(due.AddDate(0, 0, tt.expected)) {
+			t.Errorf("attempts=%d: expected due+%dd, got %v", tt.attempts, tt.expected, next)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package models
+
+import "time"
+
+// ConsentPurpose defines what the personal data may be used for (LGPD Art. 8, §4 - finalidades determinadas)
+type ConsentPurpose string
+
+const (
+	ConsentPurposeContact     ConsentPurpose = "contact"      // contato sobre o imóvel / intermediação
+	ConsentPurposeMarketing   ConsentPurpose = "marketing"    // alertas e comunicações de marketing
+	ConsentPurposeDataSharing ConsentPurpose = "data_sharing" // compartilhamento com corretores parceiros (co-corretagem)
+)
+
+// ConsentPurposes lists every purpose, in display order
+var ConsentPurposes = []ConsentPurpose{
+	ConsentPurposeContact,
+	ConsentPurposeMarketing,
+	ConsentPurposeDataSharing,
+}
+
+// IsValidConsentPurpose checks if a purpose is known
+func IsValidConsentPurpose(purpose ConsentPurpose) bool {
+	for _, p := range ConsentPurposes {
+		if p == purpose {
+			return true
+		}
+	}
+	return false
+}
+
+// ConsentSubjectType identifies who gave the consent
+type ConsentSubjectType string
+
+const (
+	ConsentSubjectLead  ConsentSubjectType = "lead"
+	ConsentSubjectOwner ConsentSubjectType = "owner"
+)
+
+// ConsentAction is the ledger operation
+type ConsentAction string
+
+const (
+	ConsentActionGranted   ConsentAction = "granted"
+	ConsentActionWithdrawn ConsentAction = "withdrawn"
+)
+
+// ConsentTextVersion is a published consent text for a purpose
+// Collection: /tenants/{tenantId}/consent_texts/{textId}
+// IMUTÁVEL: alterar o texto publica uma nova versão; versões antigas continuam válidas como prova
+type ConsentTextVersion struct {
+	ID        string         `firestore:"-" json:"id"`
+	TenantID  string         `firestore:"tenant_id" json:"tenant_id"`
+	Purpose   ConsentPurpose `firestore:"purpose" json:"purpose"`
+	Version   int            `firestore:"version" json:"version"` // 1, 2, 3... por finalidade
+	Text      string         `firestore:"text" json:"text"`
+	CreatedBy string         `firestore:"created_by,omitempty" json:"created_by,omitempty"` // user ID
+	CreatedAt time.Time      `firestore:"created_at" json:"created_at"`
+}
+
+// ConsentRecord is an immutable consent ledger entry (one grant or withdrawal)
+// Collection: /tenants/{tenantId}/consent_records/{recordId}
+// APPEND-ONLY: registros nunca são alterados ou removidos; o estado atual é o último registro por finalidade
+type ConsentRecord struct {
+	ID          string             `firestore:"-" json:"id"`
+	TenantID    string             `firestore:"tenant_id" json:"tenant_id"`
+	SubjectType ConsentSubjectType `firestore:"subject_type" json:"subject_type"` // lead, owner
+	SubjectID   string             `firestore:"subject_id" json:"subject_id"`
+	Purpose     ConsentPurpose     `firestore:"purpose" json:"purpose"`
+	Action      ConsentAction      `firestore:"action" json:"action"` // granted, withdrawn
+
+	// Texto exibido ao titular (versão publicada, ou 0 quando o texto veio do formulário)
+	TextID      string `firestore:"text_id,omitempty" json:"text_id,omitempty"`
+	TextVersion int    `firestore:"text_version" json:"text_version"`
+	Text        string `firestore:"text,omitempty" json:"text,omitempty"`
+
+	// Evidência
+	IP        string    `firestore:"ip,omitempty" json:"ip,omitempty"`
+	UserAgent string    `firestore:"user_agent,omitempty" json:"user_agent,omitempty"`
+	Origin    string    `firestore:"origin" json:"origin"` // form, whatsapp, owner_portal, broker, xls_import, api
+	ActorType ActorType `firestore:"actor_type" json:"actor_type"`
+	ActorID   string    `firestore:"actor_id,omitempty" json:"actor_id,omitempty"`
+
+	RecordedAt time.Time `firestore:"recorded_at" json:"recorded_at"`
+}
+
+// ConsentState is the current consent of a subject for one purpose (derived from the ledger)
+type ConsentState struct {
+	Purpose     ConsentPurpose `json:"purpose"`
+	Granted     bool           `json:"granted"`
+	TextVersion int            `json:"text_version,omitempty"`
+	RecordID    string         `json:"record_id,omitempty"`
+	UpdatedAt   *time.Time     `json:"updated_at,omitempty"`
+}
+
+// CurrentConsentStates folds ledger records (any order) into the current state per purpose
+// Purposes without records are reported as not granted
+func CurrentConsentStates(records []*ConsentRecord) []ConsentState {
+	latest := make(map[ConsentPurpose]*ConsentRecord)
+	for _, record := range records {
+		current, ok := latest[record.Purpose]
+		if !ok || record.RecordedAt.After(current.RecordedAt) {
+			latest[record.Purpose] = record
+		}
+	}
+
+	states := make([]ConsentState, 0, len(ConsentPurposes))
+	for _, purpose := range ConsentPurposes {
+		state := ConsentState{Purpose: purpose}
+		if record, ok := latest[purpose]; ok {
+			recordedAt := record.RecordedAt
+			state.Granted = record.Action == ConsentActionGranted
+			state.TextVersion = record.TextVersion
+			state.RecordID = record.ID
+			state.UpdatedAt = &recordedAt
+		}
+		states = append(states, state)
+	}
+	return states
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+// Test CurrentConsentStates
+func TestCurrentConsentStates(t *testing.T) {
+	base := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	records := []*ConsentRecord{
+		// Out of order on purpose: the latest record per purpose wins
+		{ID: "r3", Purpose: ConsentPurposeMarketing, Action: ConsentActionWithdrawn, RecordedAt: base.Add(2 * time.Hour)},
+		{ID: "r1", Purpose: ConsentPurposeContact, Action: ConsentActionGranted, TextVersion: 2, RecordedAt: base},
+		{ID: "r2", Purpose: ConsentPurposeMarketing, Action: ConsentActionGranted, TextVersion: 1, RecordedAt: base.Add(time.Hour)},
+	}
+
+	states := CurrentConsentStates(records)
+	if len(states) != len(ConsentPurposes) {
+		t.Fatalf("CurrentConsentStates() returned %d states, expected %d", len(states), len(ConsentPurposes))
+	}
+
+	expected := map[ConsentPurpose]struct {
+		granted  bool
+		recordID string
+	}{
+		ConsentPurposeContact:     {true, "r1"},
+		ConsentPurposeMarketing:   {false, "r3"},
+		ConsentPurposeDataSharing: {false, ""},
+	}
+
+	for _, state := range states {
+		want := expected[state.Purpose]
+		if state.Granted != want.granted {
+			t.Errorf("%s: Granted = %v, expected %v", state.Purpose, state.Granted, want.granted)
+		}
+		if state.RecordID != want.recordID {
+			t.Errorf("%s: RecordID = %q, expected %q", state.Purpose, state.RecordID, want.recordID)
+		}
+	}
+}
+
+// Test IsValidConsentPurpose
+func TestIsValidConsentPurpose(t *testing.T) {
+	if !IsValidConsentPurpose(ConsentPurposeDataSharing) {
+		t.Error("data_sharing should be a valid purpose")
+	}
+	if IsValidConsentPurpose("newsletter") {
+		t.Error("newsletter should not be a valid purpose")
+	}
+}
@@ -0,0 +1,164 @@
+package models
+
+import "time"
+
+// Contact is a person interested in the tenant's properties: the leads of the same person (same phone or
+// e-mail) are the interests of one contact, one lead per property
+// Collection: /tenants/{tenantId}/contacts/{contactId}
+type Contact struct {
+	ID       string `firestore:"-" json:"id"`
+	TenantID string `firestore:"tenant_id" json:"tenant_id"`
+
+	// Dados da pessoa (normalizados: e-mail em minúsculas, telefone NormalizePhoneBR)
+	Name  string `firestore:"name,omitempty" json:"name,omitempty"`
+	Email string `firestore:"email,omitempty" json:"email,omitempty"`
+	Phone string `firestore:"phone,omitempty" json:"phone,omitempty"`
+
+	// Blind indexes (HMAC) para deduplicação quando email/phone estão criptografados (internal/pii)
+	EmailIndex string `firestore:"email_bidx,omitempty" json:"-"`
+	PhoneIndex string `firestore:"phone_bidx,omitempty" json:"-"`
+
+	// Interesses (leads)
+	LeadCount   int       `firestore:"lead_count" json:"lead_count"`
+	PropertyIDs []string  `firestore:"property_ids,omitempty" json:"property_ids,omitempty"`
+	FirstLeadAt time.Time `firestore:"first_lead_at" json:"first_lead_at"`
+	LastLeadAt  time.Time `firestore:"last_lead_at" json:"last_lead_at"`
+
+	// Contatos incorporados por merge (IDs antigos)
+	MergedContactIDs []string `firestore:"merged_contact_ids,omitempty" json:"merged_contact_ids,omitempty"`
+
+	// Metadata
+	CreatedAt time.Time `firestore:"created_at" json:"created_at"`
+	UpdatedAt time.Time `firestore:"updated_at" json:"updated_at"`
+}
+
+// PermissionMergeContacts allows merging duplicate contacts (admins have every permission)
+const PermissionMergeContacts = "contacts.merge"
+
+// ContactMergeField is a contact field whose value is chosen when two contacts are merged
+type ContactMergeField string
+
+const (
+	ContactMergeFieldName  ContactMergeField = "name"
+	ContactMergeFieldEmail ContactMergeField = "email"
+	ContactMergeFieldPhone ContactMergeField = "phone"
+)
+
+// contactMergeFields are the fields compared in a merge, in response order
+var contactMergeFields = []ContactMergeField{ContactMergeFieldName, ContactMergeFieldEmail, ContactMergeFieldPhone}
+
+// ContactMergeSide tells which contact's value is kept for a conflicting field
+type ContactMergeSide string
+
+const (
+	ContactMergeKeepTarget ContactMergeSide = "target" // contato que permanece
+	ContactMergeKeepSource ContactMergeSide = "source" // contato incorporado (removido após o merge)
+)
+
+// ContactMergeConflict is a field with different values on both contacts
+type ContactMergeConflict struct {
+	Field  ContactMergeField `json:"field"`
+	Target string            `json:"target"`
+	Source string            `json:"source"`
+}
+
+// AddLead counts a lead as an interest of the contact and completes the contact data missing on it
+func (c *Contact) AddLead(lead *Lead) {
+	c.LeadCount++
+	if !containsString(c.PropertyIDs, lead.PropertyID) {
+		c.PropertyIDs = append(c.PropertyIDs, lead.PropertyID)
+	}
+
+	at := lead.CreatedAt
+	if at.IsZero() {
+		at = time.Now()
+	}
+	if c.FirstLeadAt.IsZero() || at.Before(c.FirstLeadAt) {
+		c.FirstLeadAt = at
+	}
+	if at.After(c.LastLeadAt) {
+		c.LastLeadAt = at
+	}
+
+	if c.Name == "" {
+		c.Name = lead.Name
+	}
+	if c.Email == "" {
+		c.Email = lead.Email
+	}
+	if c.Phone == "" && lead.Phone != "WhatsApp" {
+		c.Phone = lead.Phone
+	}
+}
+
+// MergeConflicts returns the fields with different non-empty values on the contact and on source
+func (c *Contact) MergeConflicts(source *Contact) []ContactMergeConflict {
+	conflicts := make([]ContactMergeConflict, 0)
+	for _, field := range contactMergeFields {
+		target, other := *c.mergeField(field), *source.mergeField(field)
+		if target != "" && other != "" && target != other {
+			conflicts = append(conflicts, ContactMergeConflict{Field: field, Target: target, Source: other})
+		}
+	}
+	return conflicts
+}
+
+// Merge incorporates source into the contact: fields empty on the contact take the source value and
+// conflicting fields take the value of the side chosen in resolution. Returns the conflicting fields
+// without a valid resolution; the contact is unchanged when any is left
+func (c *Contact) Merge(source *Contact, resolution map[ContactMergeField]ContactMergeSide) []ContactMergeField {
+	unresolved := make([]ContactMergeField, 0)
+	for _, conflict := range c.MergeConflicts(source) {
+		side := resolution[conflict.Field]
+		if side != ContactMergeKeepTarget && side != ContactMergeKeepSource {
+			unresolved = append(unresolved, conflict.Field)
+		}
+	}
+	if len(unresolved) > 0 {
+		return unresolved
+	}
+
+	for _, field := range contactMergeFields {
+		target, other := c.mergeField(field), *source.mergeField(field)
+		if *target == "" || (other != "" && resolution[field] == ContactMergeKeepSource) {
+			*target = other
+		}
+	}
+
+	c.LeadCount += source.LeadCount
+	for _, propertyID := range source.PropertyIDs {
+		if !containsString(c.PropertyIDs, propertyID) {
+			c.PropertyIDs = append(c.PropertyIDs, propertyID)
+		}
+	}
+	if !source.FirstLeadAt.IsZero() && (c.FirstLeadAt.IsZero() || source.FirstLeadAt.Before(c.FirstLeadAt)) {
+		c.FirstLeadAt = source.FirstLeadAt
+	}
+	if source.LastLeadAt.After(c.LastLeadAt) {
+		c.LastLeadAt = source.LastLeadAt
+	}
+	c.MergedContactIDs = append(append(c.MergedContactIDs, source.ID), source.MergedContactIDs...)
+
+	return nil
+}
+
+// mergeField returns the value of a merge field
+func (c *Contact) mergeField(field ContactMergeField) *string {
+	switch field {
+	case ContactMergeFieldEmail:
+		return &c.Email
+	case ContactMergeFieldPhone:
+		return &c.Phone
+	}
+	return &c.Name
+}
+
+// containsString reports whether values contains value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+// Test AddLead counts the interest once per property and completes the missing contact data
+func TestContactAddLead(t *testing.T) {
+	first := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	contact := &Contact{Phone: "+5511987654321"}
+
+	contact.AddLead(&Lead{PropertyID: "p1", Name: "Ana", Email: "ana@example.com", Phone: "+5511987654321", CreatedAt: first})
+	contact.AddLead(&Lead{PropertyID: "p1", Name: "Ana Souza", Phone: "WhatsApp", CreatedAt: first.Add(-time.Hour)})
+	contact.AddLead(&Lead{PropertyID: "p2", Name: "Ana", CreatedAt: first.Add(time.Hour)})
+
+	if contact.LeadCount != 3 {
+		t.Errorf("expected 3 leads, got %d", contact.LeadCount)
+	}
+	if len(contact.PropertyIDs) != 2 {
+		t.Errorf("expected 2 properties, got %v", contact.PropertyIDs)
+	}
+	if contact.Name != "Ana" || contact.Email != "ana@example.com" || contact.Phone != "+5511987654321" {
+		t.Errorf("unexpected contact data: %q %q %q", contact.Name, contact.Email, contact.Phone)
+	}
+	if !contact.FirstLeadAt.Equal(first.Add(-time.Hour)) || !contact.LastLeadAt.Equal(first.Add(time.Hour)) {
+		t.Errorf("unexpected lead dates: %v - %v", contact.FirstLeadAt, contact.LastLeadAt)
+	}
+}
+
+// Test MergeConflicts reports only fields with different values on both contacts
+func TestContactMergeConflicts(t *testing.T) {
+	target := &Contact{Name: "Ana", Email: "ana@example.com"}
+	source := &Contact{Name: "Ana Souza", Email: "ana@example.com", Phone: "+5511987654321"}
+
+	conflicts := target.MergeConflicts(source)
+	if len(conflicts) != 1 {
+		t.Fatalf("expected 1 conflict, got %v", conflicts)
+	}
+	if conflicts[0].Field != ContactMergeFieldName || conflicts[0].Target != "Ana" || conflicts[0].Source != "Ana Souza" {
+		t.Errorf("unexpected conflict: %+v", conflicts[0])
+	}
+}
+
+// Test Merge refuses unresolved conflicts and applies the chosen side of each field
+func TestContactMerge(t *testing.T) {
+	early := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	late := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	newTarget := func() *Contact {
+		return &Contact{ID: "c1", Name: "Ana", Email: "ana@example.com", LeadCount: 1, PropertyIDs: []string{"p1"}, FirstLeadAt: late, LastLeadAt: late}
+	}
+	source := &Contact{ID: "c2", Name: "Ana Souza", Email: "ana.souza@example.com", Phone: "+5511987654321", LeadCount: 2,
+		PropertyIDs: []string{"p1", "p2"}, FirstLeadAt: early, LastLeadAt: early, MergedContactIDs: []string{"c0"}}
+
+	target := newTarget()
+	unresolved := target.Merge(source, map[ContactMergeField]ContactMergeSide{ContactMergeFieldName: ContactMergeKeepSource})
+	if len(unresolved) != 1 || unresolved[0] != ContactMergeFieldEmail {
+		t.Fatalf("expected the e-mail conflict unresolved, got %v", unresolved)
+	}
+	if target.Name != "Ana" || target.LeadCount != 1 {
+		t.Error("expected the contact unchanged when conflicts are unresolved")
+	}
+
+	unresolved = target.Merge(source, map[ContactMergeField]ContactMergeSide{
+		ContactMergeFieldName:  ContactMergeKeepSource,
+		ContactMergeFieldEmail: "both",
+	})
+	if len(unresolved) != 1 {
+		t.Errorf("expected an invalid side to be unresolved, got %v", unresolved)
+	}
+
+	unresolved = target.Merge(source, map[ContactMergeField]ContactMergeSide{
+		ContactMergeFieldName:  ContactMergeKeepSource,
+		ContactMergeFieldEmail: ContactMergeKeepTarget,
+	})
+	if len(unresolved) != 0 {
+		t.Fatalf("expected no unresolved conflicts, got %v", unresolved)
+	}
+	if target.Name != "Ana Souza" || target.Email != "ana@example.com" || target.Phone != "+5511987654321" {
+		t.Errorf("unexpected merged data: %q %q %q", target.Name, target.Email, target.Phone)
+	}
+	if target.LeadCount != 3 || len(target.PropertyIDs) != 2 {
+		t.Errorf("unexpected merged interests: %d %v", target.LeadCount, target.PropertyIDs)
+	}
+	if !target.FirstLeadAt.Equal(early) || !target.LastLeadAt.Equal(late) {
+		t.Errorf("unexpected merged dates: %v - %v", target.FirstLeadAt, target.LastLeadAt)
+	}
+	if len(target.MergedContactIDs) != 2 || target.MergedContactIDs[0] != "c2" || target.MergedContactIDs[1] != "c0" {
+		t.Errorf("unexpected merged contact IDs: %v", target.MergedContactIDs)
+	}
+}
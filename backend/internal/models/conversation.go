@@ -0,0 +1,226 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// WhatsAppServiceWindow is how long after the lead's last WhatsApp message free-form replies can be sent
+// (WhatsApp customer service window; afterwards only approved templates are delivered)
+const WhatsAppServiceWindow = 24 * time.Hour
+
+// maxMessagePreviewLength is the length (in characters) of the last message preview of a conversation
+const maxMessagePreviewLength = 120
+
+// MessageDirection defines whether a message was received from or sent to the lead
+type MessageDirection string
+
+const (
+	MessageDirectionInbound  MessageDirection = "inbound"  // enviada pelo interessado
+	MessageDirectionOutbound MessageDirection = "outbound" // enviada pela imobiliária
+)
+
+// MessageKind defines the kind of a conversation entry
+type MessageKind string
+
+const (
+	MessageKindMessage MessageKind = "message" // mensagem trocada com o interessado
+	MessageKindNote    MessageKind = "note"    // nota interna do corretor (não enviada ao interessado)
+	MessageKindCall    MessageKind = "call"    // registro de ligação
+)
+
+// CallOutcome defines the outcome of a logged call
+type CallOutcome string
+
+const (
+	CallOutcomeAnswered  CallOutcome = "answered"
+	CallOutcomeNoAnswer  CallOutcome = "no_answer"
+	CallOutcomeBusy      CallOutcome = "busy"
+	CallOutcomeVoicemail CallOutcome = "voicemail"
+)
+
+// IsValidCallOutcome reports whether the call outcome is known
+func IsValidCallOutcome(outcome CallOutcome) bool {
+	switch outcome {
+	case CallOutcomeAnswered, CallOutcomeNoAnswer, CallOutcomeBusy, CallOutcomeVoicemail:
+		return true
+	}
+	return false
+}
+
+// ConversationMessage is an entry of the conversation history of a lead: a message (any channel),
+// a broker note or a call log
+// Collection: /tenants/{tenantId}/leads/{leadId}/messages/{messageId}
+// Mensagens de provedores usam o ID externo como ID do documento (entregas repetidas do webhook não duplicam)
+type ConversationMessage struct {
+	ID        string           `firestore:"-" json:"id"`
+	TenantID  string           `firestore:"tenant_id" json:"tenant_id"`
+	LeadID    string           `firestore:"lead_id" json:"lead_id"`
+	Kind      MessageKind      `firestore:"kind" json:"kind"`
+	Channel   LeadChannel      `firestore:"channel,omitempty" json:"channel,omitempty"` // vazio em notas
+	Direction MessageDirection `firestore:"direction" json:"direction"`
+	AuthorID  string           `firestore:"author_id,omitempty" json:"author_id,omitempty"` // usuário que respondeu, anotou ou ligou
+
+	// Conteúdo
+	Subject     string `firestore:"subject,omitempty" json:"subject,omitempty"` // e-mail
+	Body        string `firestore:"body,omitempty" json:"body,omitempty"`
+	ContentType string `firestore:"content_type,omitempty" json:"content_type,omitempty"` // text, image, audio, document, location...
+
+	// Ligação (kind = call)
+	CallOutcome         CallOutcome `firestore:"call_outcome,omitempty" json:"call_outcome,omitempty"`
+	CallDurationSeconds int         `firestore:"call_duration_seconds,omitempty" json:"call_duration_seconds,omitempty"`
+
+	// Remetente/destinatário (telefone E.164 ou e-mail) - criptografados quando PII_KEY_FILE está configurado
+	From        string `firestore:"from,omitempty" json:"from,omitempty"`
+	To          string `firestore:"to,omitempty" json:"to,omitempty"`
+	ContactName string `firestore:"contact_name,omitempty" json:"contact_name,omitempty"` // nome do perfil do remetente
+
+	// Provedor
+	Provider   string `firestore:"provider,omitempty" json:"provider,omitempty"`       // ex: whatsapp_cloud
+	ExternalID string `firestore:"external_id,omitempty" json:"external_id,omitempty"` // ID da mensagem no provedor
+
+	SentAt    time.Time `firestore:"sent_at" json:"sent_at"`
+	CreatedAt time.Time `firestore:"created_at" json:"created_at"`
+}
+
+// Conversation is the inbox entry of a lead: summary of its messages, notes and calls across channels,
+// with read/unread state and the broker it is assigned to
+// Collection: /tenants/{tenantId}/conversations/{leadId} (uma conversa por lead)
+type Conversation struct {
+	LeadID      string `firestore:"-" json:"lead_id"`
+	TenantID    string `firestore:"tenant_id" json:"tenant_id"`
+	PropertyID  string `firestore:"property_id" json:"property_id"`
+	ContactName string `firestore:"contact_name,omitempty" json:"contact_name,omitempty"`
+
+	// Atribuição
+	AssignedBrokerID string     `firestore:"assigned_broker_id,omitempty" json:"assigned_broker_id,omitempty"`
+	AssignedAt       *time.Time `firestore:"assigned_at,omitempty" json:"assigned_at,omitempty"`
+
+	// Resumo
+	Channels             []LeadChannel    `firestore:"channels,omitempty" json:"channels,omitempty"` // canais com mensagens
+	MessageCount         int              `firestore:"message_count" json:"message_count"`
+	LastMessageAt        time.Time        `firestore:"last_message_at" json:"last_message_at"`
+	LastMessagePreview   string           `firestore:"last_message_preview,omitempty" json:"last_message_preview,omitempty"`
+	LastMessageKind      MessageKind      `firestore:"last_message_kind,omitempty" json:"last_message_kind,omitempty"`
+	LastMessageChannel   LeadChannel      `firestore:"last_message_channel,omitempty" json:"last_message_channel,omitempty"`
+	LastMessageDirection MessageDirection `firestore:"last_message_direction,omitempty" json:"last_message_direction,omitempty"`
+
+	// Última mensagem recebida (janela de atendimento do WhatsApp)
+	LastInboundAt         *time.Time `firestore:"last_inbound_at,omitempty" json:"last_inbound_at,omitempty"`
+	LastWhatsAppInboundAt *time.Time `firestore:"last_whatsapp_inbound_at,omitempty" json:"last_whatsapp_inbound_at,omitempty"`
+
+	// Tempo de resposta do interessado (lead scoring): primeiro contato do corretor e primeira resposta após ele
+	FirstOutreachAt *time.Time `firestore:"first_outreach_at,omitempty" json:"first_outreach_at,omitempty"`
+	FirstReplyAt    *time.Time `firestore:"first_reply_at,omitempty" json:"first_reply_at,omitempty"`
+
+	// Leitura: mensagens recebidas desde a última leitura (has_unread permite filtrar a caixa de entrada)
+	UnreadCount int        `firestore:"unread_count" json:"unread_count"`
+	HasUnread   bool       `firestore:"has_unread" json:"has_unread"`
+	LastReadAt  *time.Time `firestore:"last_read_at,omitempty" json:"last_read_at,omitempty"`
+	LastReadBy  string     `firestore:"last_read_by,omitempty" json:"last_read_by,omitempty"`
+
+	CreatedAt time.Time `firestore:"created_at" json:"created_at"`
+	UpdatedAt time.Time `firestore:"updated_at" json:"updated_at"`
+}
+
+// ApplyMessage updates the conversation summary with a new entry
+// Inbound messages are unread until the conversation is read; an outbound reply marks it read
+func (c *Conversation) ApplyMessage(message *ConversationMessage) {
+	c.MessageCount++
+
+	if !message.SentAt.Before(c.LastMessageAt) {
+		c.LastMessageAt = message.SentAt
+		c.LastMessagePreview = MessagePreview(message.Body)
+		c.LastMessageKind = message.Kind
+		c.LastMessageChannel = message.Channel
+		c.LastMessageDirection = message.Direction
+	}
+
+	// Calls count as outreach and reply too; notes are internal
+	if message.Kind != MessageKindNote {
+		sentAt := message.SentAt
+		switch {
+		case message.Direction == MessageDirectionOutbound && (c.FirstOutreachAt == nil || sentAt.Before(*c.FirstOutreachAt)):
+			c.FirstOutreachAt = &sentAt
+		case message.Direction == MessageDirectionInbound && c.FirstOutreachAt != nil && c.FirstReplyAt == nil && sentAt.After(*c.FirstOutreachAt):
+			c.FirstReplyAt = &sentAt
+		}
+	}
+
+	if message.Kind != MessageKindMessage {
+		return
+	}
+
+	if !containsChannel(c.Channels, message.Channel) {
+		c.Channels = append(c.Channels, message.Channel)
+	}
+
+	switch message.Direction {
+	case MessageDirectionInbound:
+		c.UnreadCount++
+		c.HasUnread = true
+		sentAt := message.SentAt
+		if c.LastInboundAt == nil || sentAt.After(*c.LastInboundAt) {
+			c.LastInboundAt = &sentAt
+		}
+		if message.Channel == LeadChannelWhatsApp && (c.LastWhatsAppInboundAt == nil || sentAt.After(*c.LastWhatsAppInboundAt)) {
+			c.LastWhatsAppInboundAt = &sentAt
+		}
+	case MessageDirectionOutbound:
+		c.MarkRead(message.AuthorID, message.SentAt)
+	}
+}
+
+// MarkRead marks the inbound messages of the conversation as read
+func (c *Conversation) MarkRead(userID string, at time.Time) {
+	c.UnreadCount = 0
+	c.HasUnread = false
+	c.LastReadAt = &at
+	c.LastReadBy = userID
+}
+
+// MarkUnread flags the conversation as unread (at least one unread message)
+func (c *Conversation) MarkUnread() {
+	if c.UnreadCount == 0 {
+		c.UnreadCount = 1
+	}
+	c.HasUnread = true
+}
+
+// WhatsAppWindowOpen reports whether free-form WhatsApp replies can be sent (lead wrote in the last 24 hours)
+func (c *Conversation) WhatsAppWindowOpen(now time.Time) bool {
+	return c.LastWhatsAppInboundAt != nil && now.Sub(*c.LastWhatsAppInboundAt) < WhatsAppServiceWindow
+}
+
+// MessagePreview returns the first characters of a message body on a single line
+func MessagePreview(body string) string {
+	preview := strings.Join(strings.Fields(body), " ")
+	if runes := []rune(preview); len(runes) > maxMessagePreviewLength {
+		return strings.TrimSpace(string(runes[:maxMessagePreviewLength-1])) + "…"
+	}
+	return preview
+}
+
+// containsChannel reports whether a channel is in the list
+func containsChannel(channels []LeadChannel, channel LeadChannel) bool {
+	for _, existing := range channels {
+		if existing == channel {
+			return true
+		}
+	}
+	return false
+}
+
+// WhatsAppThread maps a WhatsApp contact to the lead its messages are threaded into
+// Mensagens sem protocolo/referência continuam na conversa do último lead do contato
+// Collection: /tenants/{tenantId}/whatsapp_threads/{threadId}
+// threadId = blind index do telefone (wa_id sem criptografia de PII); o wa_id não é gravado no documento
+type WhatsAppThread struct {
+	WaID             string     `firestore:"-" json:"wa_id"`
+	LeadID           string     `firestore:"lead_id" json:"lead_id"`
+	PropertyID       string     `firestore:"property_id" json:"property_id"`
+	ContactName      string     `firestore:"contact_name,omitempty" json:"contact_name,omitempty"`
+	LastInboundAt    time.Time  `firestore:"last_inbound_at" json:"last_inbound_at"`
+	BrokerNotifiedAt *time.Time `firestore:"broker_notified_at,omitempty" json:"broker_notified_at,omitempty"`
+	UpdatedAt        time.Time  `firestore:"updated_at" json:"updated_at"`
+}
@@ -0,0 +1,117 @@
+package models
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// Test ApplyMessage keeps the summary, channels and unread count of the conversation
+func TestConversationApplyMessage(t *testing.T) {
+	start := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	conversation := &Conversation{}
+
+	conversation.ApplyMessage(&ConversationMessage{
+		Kind: MessageKindMessage, Channel: LeadChannelForm, Direction: MessageDirectionInbound,
+		Body: "Gostaria de agendar uma visita", SentAt: start,
+	})
+	conversation.ApplyMessage(&ConversationMessage{
+		Kind: MessageKindMessage, Channel: LeadChannelWhatsApp, Direction: MessageDirectionInbound,
+		Body: "Oi, ainda está disponível?", SentAt: start.Add(time.Hour),
+	})
+
+	if conversation.MessageCount != 2 || conversation.UnreadCount != 2 || !conversation.HasUnread {
+		t.Fatalf("MessageCount=%d UnreadCount=%d HasUnread=%v, expected 2, 2 and true", conversation.MessageCount, conversation.UnreadCount, conversation.HasUnread)
+	}
+	if len(conversation.Channels) != 2 || conversation.Channels[0] != LeadChannelForm || conversation.Channels[1] != LeadChannelWhatsApp {
+		t.Errorf("Channels = %v, expected [form whatsapp]", conversation.Channels)
+	}
+	if conversation.LastMessagePreview != "Oi, ainda está disponível?" || conversation.LastMessageChannel != LeadChannelWhatsApp {
+		t.Errorf("unexpected last message: %q via %s", conversation.LastMessagePreview, conversation.LastMessageChannel)
+	}
+	if conversation.LastWhatsAppInboundAt == nil || !conversation.LastWhatsAppInboundAt.Equal(start.Add(time.Hour)) {
+		t.Errorf("LastWhatsAppInboundAt = %v, expected %v", conversation.LastWhatsAppInboundAt, start.Add(time.Hour))
+	}
+
+	// A note does not change the unread state nor the channels
+	conversation.ApplyMessage(&ConversationMessage{
+		Kind: MessageKindNote, Direction: MessageDirectionOutbound, AuthorID: "user-1",
+		Body: "Cliente prefere contato à tarde", SentAt: start.Add(2 * time.Hour),
+	})
+	if conversation.UnreadCount != 2 || len(conversation.Channels) != 2 {
+		t.Errorf("note changed UnreadCount=%d Channels=%v", conversation.UnreadCount, conversation.Channels)
+	}
+	if conversation.LastMessageKind != MessageKindNote {
+		t.Errorf("LastMessageKind = %s, expected note", conversation.LastMessageKind)
+	}
+
+	// An older entry (late webhook delivery) counts but does not replace the last message
+	conversation.ApplyMessage(&ConversationMessage{
+		Kind: MessageKindMessage, Channel: LeadChannelWhatsApp, Direction: MessageDirectionInbound,
+		Body: "Mensagem atrasada", SentAt: start.Add(30 * time.Minute),
+	})
+	if conversation.LastMessageKind != MessageKindNote || conversation.UnreadCount != 3 || conversation.MessageCount != 4 {
+		t.Errorf("late message: kind=%s unread=%d count=%d", conversation.LastMessageKind, conversation.UnreadCount, conversation.MessageCount)
+	}
+
+	// A reply marks the conversation read
+	replyAt := start.Add(3 * time.Hour)
+	conversation.ApplyMessage(&ConversationMessage{
+		Kind: MessageKindMessage, Channel: LeadChannelWhatsApp, Direction: MessageDirectionOutbound, AuthorID: "user-1",
+		Body: "Está sim! Podemos agendar para amanhã?", SentAt: replyAt,
+	})
+	if conversation.UnreadCount != 0 || conversation.HasUnread || conversation.LastReadBy != "user-1" || conversation.LastReadAt == nil || !conversation.LastReadAt.Equal(replyAt) {
+		t.Errorf("reply did not mark read: unread=%d by=%q at=%v", conversation.UnreadCount, conversation.LastReadBy, conversation.LastReadAt)
+	}
+	if conversation.FirstOutreachAt == nil || !conversation.FirstOutreachAt.Equal(replyAt) || conversation.FirstReplyAt != nil {
+		t.Errorf("FirstOutreachAt = %v FirstReplyAt = %v, expected %v and nil (the note is not an outreach)", conversation.FirstOutreachAt, conversation.FirstReplyAt, replyAt)
+	}
+
+	conversation.MarkUnread()
+	if conversation.UnreadCount != 1 || !conversation.HasUnread {
+		t.Errorf("MarkUnread() UnreadCount = %d HasUnread = %v, expected 1 and true", conversation.UnreadCount, conversation.HasUnread)
+	}
+
+	// The first answer after the outreach measures the response latency of the lead
+	answerAt := replyAt.Add(20 * time.Minute)
+	conversation.ApplyMessage(&ConversationMessage{
+		Kind: MessageKindMessage, Channel: LeadChannelWhatsApp, Direction: MessageDirectionInbound,
+		Body: "Pode ser às 10h", SentAt: answerAt,
+	})
+	conversation.ApplyMessage(&ConversationMessage{
+		Kind: MessageKindMessage, Channel: LeadChannelWhatsApp, Direction: MessageDirectionInbound,
+		Body: "Obrigado", SentAt: answerAt.Add(time.Minute),
+	})
+	if conversation.FirstReplyAt == nil || !conversation.FirstReplyAt.Equal(answerAt) {
+		t.Errorf("FirstReplyAt = %v, expected %v", conversation.FirstReplyAt, answerAt)
+	}
+}
+
+// Test WhatsAppWindowOpen allows free-form replies only within 24 hours of the lead's last WhatsApp message
+func TestConversationWhatsAppWindowOpen(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	recent := now.Add(-23 * time.Hour)
+	expired := now.Add(-25 * time.Hour)
+
+	if (&Conversation{}).WhatsAppWindowOpen(now) {
+		t.Error("expected window closed without WhatsApp messages")
+	}
+	if !(&Conversation{LastWhatsAppInboundAt: &recent}).WhatsAppWindowOpen(now) {
+		t.Error("expected window open 23 hours after the last message")
+	}
+	if (&Conversation{LastWhatsAppInboundAt: &expired}).WhatsAppWindowOpen(now) {
+		t.Error("expected window closed 25 hours after the last message")
+	}
+}
+
+// Test MessagePreview collapses whitespace and truncates long messages
+func TestMessagePreview(t *testing.T) {
+	if preview := MessagePreview("  Olá!\n\nTenho   interesse "); preview != "Olá! Tenho interesse" {
+		t.Errorf("MessagePreview() = %q", preview)
+	}
+
+	long := MessagePreview(strings.Repeat("á", 200))
+	if runes := []rune(long); len(runes) != maxMessagePreviewLength || !strings.HasSuffix(long, "…") {
+		t.Errorf("MessagePreview() of long body has %d characters: %q", len(runes), long)
+	}
+}
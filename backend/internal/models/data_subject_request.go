@@ -0,0 +1,137 @@
+package models
+
+import "time"
+
+// DSRType defines what the data subject (titular) is asking for (LGPD Art. 18)
+type DSRType string
+
+const (
+	DSRTypeAccess      DSRType = "access"      // confirmação e acesso aos dados
+	DSRTypePortability DSRType = "portability" // cópia em formato legível por máquina
+	DSRTypeErasure     DSRType = "erasure"     // eliminação/anonimização
+)
+
+// DSRStatus defines the lifecycle of a data subject request
+type DSRStatus string
+
+const (
+	DSRStatusPendingVerification DSRStatus = "pending_verification" // aguardando código de verificação
+	DSRStatusVerified            DSRStatus = "verified"             // identidade confirmada, prazo correndo
+	DSRStatusCompleted           DSRStatus = "completed"
+	DSRStatusRejected            DSRStatus = "rejected"
+)
+
+// DSR verification channels (where the identity verification code is sent)
+const (
+	DSRVerificationChannelEmail    = "email"
+	DSRVerificationChannelWhatsApp = "whatsapp"
+)
+
+// DSR subject identifiers (data informed in the request and used to find the subject's records)
+const (
+	DSRIdentifierEmail    = "email"
+	DSRIdentifierPhone    = "phone"
+	DSRIdentifierDocument = "document" // CPF
+)
+
+// DSRIdentifier is an identifier informed by the subject
+type DSRIdentifier struct {
+	Kind     string // email, phone, document
+	Value    string
+	Verified bool // received the verification code
+}
+
+// DSRResponseDeadlineDays is the legal deadline to answer a verified request (LGPD Art. 19, II)
+const DSRResponseDeadlineDays = 15
+
+// DSRMatch is a record found for the data subject in one of the tenant collections
+type DSRMatch struct {
+	Collection  string   `firestore:"collection" json:"collection"` // owners, leads, activity_logs, scheduled_confirmations
+	DocumentID  string   `firestore:"document_id" json:"document_id"`
+	Description string   `firestore:"description,omitempty" json:"description,omitempty"`
+	Action      string   `firestore:"action,omitempty" json:"action,omitempty"`         // anonymized, cancelled, deleted, pii_removed, retained (preenchido na eliminação)
+	MatchedBy   []string `firestore:"matched_by,omitempty" json:"matched_by,omitempty"` // identificadores que encontraram o registro (email, phone, document)
+}
+
+// DataSubjectRequest represents an LGPD data subject request (acesso, portabilidade, eliminação)
+// Collection: /tenants/{tenantId}/data_subject_requests/{requestId}
+// IMPORTANTE: o código de verificação nunca é armazenado, apenas seu hash SHA-256
+// O prazo de 15 dias começa quando a identidade do titular é verificada
+type DataSubjectRequest struct {
+	ID       string    `firestore:"-" json:"id"`
+	TenantID string    `firestore:"tenant_id" json:"tenant_id"`
+	Type     DSRType   `firestore:"type" json:"type"`     // access, portability, erasure
+	Status   DSRStatus `firestore:"status" json:"status"` // pending_verification, verified, completed, rejected
+
+	// Titular (dados informados na solicitação)
+	SubjectName     string `firestore:"subject_name,omitempty" json:"subject_name,omitempty"`
+	SubjectEmail    string `firestore:"subject_email,omitempty" json:"subject_email,omitempty"`
+	SubjectPhone    string `firestore:"subject_phone,omitempty" json:"subject_phone,omitempty"`
+	SubjectDocument string `firestore:"subject_document,omitempty" json:"subject_document,omitempty"` // CPF (opcional, usado na busca de proprietários)
+	Details         string `firestore:"details,omitempty" json:"details,omitempty"`
+
+	// Verificação de identidade
+	VerificationChannel  string     `firestore:"verification_channel" json:"verification_channel"` // email, whatsapp
+	VerificationCodeHash string     `firestore:"verification_code_hash" json:"-"`
+	VerificationExpiry   time.Time  `firestore:"verification_expiry" json:"-"`
+	VerificationAttempts int        `firestore:"verification_attempts" json:"verification_attempts"` // todas as tentativas, inclusive de códigos reenviados
+	VerificationSentAt   time.Time  `firestore:"verification_sent_at" json:"-"`
+	VerificationResends  int        `firestore:"verification_resends" json:"-"`
+	VerifiedAt           *time.Time `firestore:"verified_at,omitempty" json:"verified_at,omitempty"`
+
+	// Prazo
+	DueAt       *time.Time `firestore:"due_at,omitempty" json:"due_at,omitempty"` // verified_at + 15 dias
+	CompletedAt *time.Time `firestore:"completed_at,omitempty" json:"completed_at,omitempty"`
+
+	// Processamento
+	Matches            []DSRMatch `firestore:"matches,omitempty" json:"matches,omitempty"`
+	MatchedIdentifiers []string   `firestore:"matched_identifiers,omitempty" json:"matched_identifiers,omitempty"` // identificadores informados que encontraram registros
+	SearchedAt         *time.Time `firestore:"searched_at,omitempty" json:"searched_at,omitempty"`
+	ExportGeneratedAt  *time.Time `firestore:"export_generated_at,omitempty" json:"export_generated_at,omitempty"`
+	ErasedAt           *time.Time `firestore:"erased_at,omitempty" json:"erased_at,omitempty"`
+	HandledBy          string     `firestore:"handled_by,omitempty" json:"handled_by,omitempty"` // user ID
+	ResolutionNotes    string     `firestore:"resolution_notes,omitempty" json:"resolution_notes,omitempty"`
+	RejectionReason    string     `firestore:"rejection_reason,omitempty" json:"rejection_reason,omitempty"`
+
+	// Origem da solicitação
+	RequestIP string `firestore:"request_ip,omitempty" json:"request_ip,omitempty"`
+
+	// Metadata
+	CreatedAt time.Time `firestore:"created_at" json:"created_at"`
+	UpdatedAt time.Time `firestore:"updated_at" json:"updated_at"`
+}
+
+// SubjectIdentifiers returns the identifiers informed by the subject, once the request is verified (nil before)
+// All of them are searched; Verified marks the one that received the verification code
+func (r *DataSubjectRequest) SubjectIdentifiers() []DSRIdentifier {
+	if r.VerifiedAt == nil {
+		return nil
+	}
+
+	identifiers := make([]DSRIdentifier, 0, 3)
+	if r.SubjectEmail != "" {
+		identifiers = append(identifiers, DSRIdentifier{Kind: DSRIdentifierEmail, Value: r.SubjectEmail, Verified: r.VerificationChannel == DSRVerificationChannelEmail})
+	}
+	if r.SubjectPhone != "" {
+		identifiers = append(identifiers, DSRIdentifier{Kind: DSRIdentifierPhone, Value: r.SubjectPhone, Verified: r.VerificationChannel == DSRVerificationChannelWhatsApp})
+	}
+	if r.SubjectDocument != "" {
+		identifiers = append(identifiers, DSRIdentifier{Kind: DSRIdentifierDocument, Value: r.SubjectDocument})
+	}
+	return identifiers
+}
+
+// IsOpen checks whether the request still needs an answer
+func (r *DataSubjectRequest) IsOpen() bool {
+	return r.Status == DSRStatusVerified
+}
+
+// IsOverdue checks whether a verified request passed its legal deadline without an answer
+func (r *DataSubjectRequest) IsOverdue(now time.Time) bool {
+	return r.IsOpen() && r.DueAt != nil && now.After(*r.DueAt)
+}
+
+// AnsweredOnTime checks whether a completed/rejected request was answered within the deadline
+func (r *DataSubjectRequest) AnsweredOnTime() bool {
+	return r.CompletedAt != nil && r.DueAt != nil && !r.CompletedAt.After(*r.DueAt)
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDataSubjectRequestSubjectIdentifiers(t *testing.T) {
+	now := time.Now()
+
+	request := &DataSubjectRequest{
+		SubjectEmail:        "titular@example.com",
+		SubjectPhone:        "+5541999998888",
+		SubjectDocument:     "12345678909",
+		VerificationChannel: DSRVerificationChannelEmail,
+	}
+	if identifiers := request.SubjectIdentifiers(); identifiers != nil {
+		t.Fatalf("unverified request must not expose identifiers, got %+v", identifiers)
+	}
+
+	request.VerifiedAt = &now
+	expected := []DSRIdentifier{
+		{Kind: DSRIdentifierEmail, Value: "titular@example.com", Verified: true},
+		{Kind: DSRIdentifierPhone, Value: "+5541999998888"},
+		{Kind: DSRIdentifierDocument, Value: "12345678909"},
+	}
+	if got := request.SubjectIdentifiers(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("SubjectIdentifiers() = %+v, expected %+v", got, expected)
+	}
+
+	request.SubjectEmail = ""
+	request.SubjectDocument = ""
+	request.VerificationChannel = DSRVerificationChannelWhatsApp
+	expected = []DSRIdentifier{{Kind: DSRIdentifierPhone, Value: "+5541999998888", Verified: true}}
+	if got := request.SubjectIdentifiers(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("SubjectIdentifiers() = %+v, expected %+v", got, expected)
+	}
+}
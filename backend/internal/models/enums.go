@@ -136,7 +136,8 @@ const (
 type ActorType string
 
 const (
-	ActorTypeUser   ActorType = "user"   // Broker autenticado
-	ActorTypeSystem ActorType = "system" // Job automático
-	ActorTypeOwner  ActorType = "owner"  // Owner confirmando via link
+	ActorTypeUser   ActorType = "user"    // Broker autenticado
+	ActorTypeSystem ActorType = "system"  // Job automático
+	ActorTypeOwner  ActorType = "owner"   // Owner confirmando via link
+	ActorTypeAPIKey ActorType = "api_key" // Integração autenticada por chave de API
 )
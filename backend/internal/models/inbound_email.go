@@ -0,0 +1,47 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+// InboundEmailStatus defines the outcome of an inbound lead notification e-mail
+type InboundEmailStatus string
+
+const (
+	InboundEmailStatusLeadCreated InboundEmailStatus = "lead_created" // novo lead criado
+	InboundEmailStatusLeadUpdated InboundEmailStatus = "lead_updated" // mensagem adicionada à conversa de um lead existente
+	InboundEmailStatusUnmatched   InboundEmailStatus = "unmatched"    // imóvel do anúncio não encontrado
+	InboundEmailStatusUnparsed    InboundEmailStatus = "unparsed"     // não é uma notificação de portal suportada ou sem contato
+)
+
+// InboundEmail records a lead notification e-mail received from a listing portal (ZAP, VivaReal, OLX)
+// Collection: /tenants/{tenantId}/inbound_emails/{id}
+// O ID é derivado do Message-ID: o mesmo e-mail recebido de novo não duplica o lead
+// O conteúdo do e-mail (dados do interessado) não é armazenado, somente o resultado
+type InboundEmail struct {
+	ID         string             `firestore:"-" json:"id"`
+	TenantID   string             `firestore:"tenant_id" json:"tenant_id"`
+	MessageID  string             `firestore:"message_id,omitempty" json:"message_id,omitempty"`
+	Sender     string             `firestore:"sender,omitempty" json:"sender,omitempty"` // remetente (portal ou corretor que encaminhou)
+	Portal     string             `firestore:"portal,omitempty" json:"portal,omitempty"` // zap, vivareal, olx
+	Status     InboundEmailStatus `firestore:"status" json:"status"`
+	Error      string             `firestore:"error,omitempty" json:"error,omitempty"`
+	ListingID  string             `firestore:"listing_id,omitempty" json:"listing_id,omitempty"` // código do anúncio no portal
+	Reference  string             `firestore:"reference,omitempty" json:"reference,omitempty"`   // código do imóvel do anunciante
+	PropertyID string             `firestore:"property_id,omitempty" json:"property_id,omitempty"`
+	LeadID     string             `firestore:"lead_id,omitempty" json:"lead_id,omitempty"`
+	ReceivedAt time.Time          `firestore:"received_at" json:"received_at"`
+	CreatedAt  time.Time          `firestore:"created_at" json:"created_at"`
+}
+
+// InboundEmailID returns the document ID of an inbound e-mail: hash of the Message-ID, else of the raw content
+func InboundEmailID(messageID string, raw []byte) string {
+	sum := sha256.Sum256(raw)
+	if messageID = strings.TrimSpace(messageID); messageID != "" {
+		sum = sha256.Sum256([]byte(strings.ToLower(messageID)))
+	}
+	return hex.EncodeToString(sum[:16])
+}
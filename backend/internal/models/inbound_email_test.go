@@ -0,0 +1,22 @@
+package models
+
+import "testing"
+
+// Test InboundEmailID is stable per Message-ID and falls back to the raw content
+func TestInboundEmailID(t *testing.T) {
+	first := InboundEmailID("<ABC123@zapimoveis.com.br>", []byte("raw 1"))
+	again := InboundEmailID("<abc123@zapimoveis.com.br>", []byte("raw 2"))
+	if first != again {
+		t.Errorf("expected the same ID for the same Message-ID, got %s and %s", first, again)
+	}
+	if len(first) != 32 {
+		t.Errorf("expected a 32-character ID, got %q", first)
+	}
+
+	if InboundEmailID("", []byte("raw 1")) == InboundEmailID("", []byte("raw 2")) {
+		t.Error("expected different IDs for different contents without Message-ID")
+	}
+	if InboundEmailID("", []byte("raw 1")) != InboundEmailID("  ", []byte("raw 1")) {
+		t.Error("expected a blank Message-ID to fall back to the content")
+	}
+}
@@ -8,7 +8,8 @@ import "time"
 type Lead struct {
 	ID         string `firestore:"-" json:"id"`
 	TenantID   string `firestore:"tenant_id" json:"tenant_id"`
-	PropertyID string `firestore:"property_id" json:"property_id"` // ref Property (OBRIGATÓRIO)
+	PropertyID string `firestore:"property_id" json:"property_id"`                   // ref Property (OBRIGATÓRIO)
+	ContactID  string `firestore:"contact_id,omitempty" json:"contact_id,omitempty"` // ref Contact (mesma pessoa em vários imóveis)
 
 	// Dados do interessado (MÍNIMOS)
 	Name    string `firestore:"name,omitempty" json:"name,omitempty"`
@@ -16,6 +17,10 @@ type Lead struct {
 	Phone   string `firestore:"phone,omitempty" json:"phone,omitempty"`
 	Message string `firestore:"message,omitempty" json:"message,omitempty"`
 
+	// Blind indexes (HMAC) para busca quando email/phone estão criptografados (internal/pii)
+	EmailIndex string `firestore:"email_bidx,omitempty" json:"-"`
+	PhoneIndex string `firestore:"phone_bidx,omitempty" json:"-"`
+
 	// Origem
 	Channel     LeadChannel `firestore:"channel" json:"channel"` // whatsapp, form, phone, email
 	UTMSource   string      `firestore:"utm_source,omitempty" json:"utm_source,omitempty"`
@@ -26,6 +31,16 @@ type Lead struct {
 	// Status
 	Status LeadStatus `firestore:"status" json:"status"` // new, contacted, qualified, lost
 
+	// Qualificação (informada no formulário do site)
+	Budget             float64 `firestore:"budget,omitempty" json:"budget,omitempty"`                           // orçamento do interessado (BRL)
+	FinancingSimulated bool    `firestore:"financing_simulated,omitempty" json:"financing_simulated,omitempty"` // usou o simulador de financiamento
+	PropertiesViewed   int     `firestore:"properties_viewed,omitempty" json:"properties_viewed,omitempty"`     // imóveis distintos vistos no site antes do contato
+
+	// Lead scoring (0-100, recalculado a cada evento do lead - LeadScoringService)
+	Score        int               `firestore:"score" json:"score"`
+	ScoreFactors []LeadScoreFactor `firestore:"score_factors,omitempty" json:"score_factors,omitempty"`
+	ScoredAt     *time.Time        `firestore:"scored_at,omitempty" json:"scored_at,omitempty"`
+
 	// LGPD - Consentimento (AI_DEV_DIRECTIVE Seção 21)
 	// OBRIGATÓRIO: consent_given DEVE ser true para criar lead
 	ConsentGiven   bool       `firestore:"consent_given" json:"consent_given"`               // OBRIGATÓRIO para criar lead
@@ -35,6 +50,16 @@ type Lead struct {
 	ConsentRevoked bool       `firestore:"consent_revoked" json:"consent_revoked"`           // default: false
 	RevokedAt      *time.Time `firestore:"revoked_at,omitempty" json:"revoked_at,omitempty"` // Timestamp da revogação
 
+	// LGPD - Consent ledger (finalidades aceitas na captura; contact sempre incluída)
+	// O estado atual por finalidade está no consent ledger (ConsentService)
+	ConsentUserAgent string           `firestore:"consent_user_agent,omitempty" json:"consent_user_agent,omitempty"`
+	ConsentPurposes  []ConsentPurpose `firestore:"consent_purposes,omitempty" json:"consent_purposes,omitempty"`
+
+	// Texto aceito por finalidade opcional (marketing, data_sharing): versão publicada exibida (0 = atual)
+	// ou o próprio texto exibido pelo formulário. ConsentText é o texto da finalidade contact
+	ConsentTextVersions map[ConsentPurpose]int    `firestore:"consent_text_versions,omitempty" json:"consent_text_versions,omitempty"`
+	ConsentPurposeTexts map[ConsentPurpose]string `firestore:"consent_purpose_texts,omitempty" json:"consent_purpose_texts,omitempty"`
+
 	// LGPD - Anonimização
 	IsAnonymized        bool       `firestore:"is_anonymized" json:"is_anonymized"` // default: false
 	AnonymizedAt        *time.Time `firestore:"anonymized_at,omitempty" json:"anonymized_at,omitempty"`
@@ -0,0 +1,185 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Lead score (0-100): a base score plus the points of explainable signals (see ScoreLead)
+const (
+	LeadScoreBase = 30
+	LeadScoreMin  = 0
+	LeadScoreMax  = 100
+)
+
+// Lead score factor codes
+const (
+	LeadScoreFactorBase                = "base"
+	LeadScoreFactorChannel             = "channel"
+	LeadScoreFactorMessageIntent       = "message_intent"
+	LeadScoreFactorMessageLowIntent    = "message_low_intent"
+	LeadScoreFactorPhoneValid          = "phone_valid"
+	LeadScoreFactorFinancingSimulation = "financing_simulation"
+	LeadScoreFactorPropertiesInquired  = "properties_inquired"
+	LeadScoreFactorPropertiesViewed    = "properties_viewed"
+	LeadScoreFactorResponseLatency     = "response_latency"
+	LeadScoreFactorNoResponse          = "no_response"
+	LeadScoreFactorBudgetFit           = "budget_fit"
+)
+
+// LeadScoreFactor is a signal that contributed to the lead score (explains the score to brokers)
+// The points of the factors add up to the score before clamping to 0-100
+type LeadScoreFactor struct {
+	Code   string `firestore:"code" json:"code"`
+	Points int    `firestore:"points" json:"points"`
+	Detail string `firestore:"detail,omitempty" json:"detail,omitempty"` // ex: "whatsapp", "3 properties"
+}
+
+// LeadScoreSignals are the inputs of the lead score
+type LeadScoreSignals struct {
+	Channel            LeadChannel
+	Message            string
+	PhoneValid         bool
+	FinancingSimulated bool
+	PropertiesInquired int        // properties the person asked about (this lead included)
+	PropertiesViewed   int        // distinct properties viewed on the site before contacting (reported by the site)
+	OutreachAt         *time.Time // first broker message or call
+	RepliedAt          *time.Time // first answer of the lead after the outreach
+	Budget             float64    // informed by the lead (0 = unknown)
+	PropertyPrice      float64
+	Now                time.Time
+}
+
+// channelScores are the points of each channel (direct conversations convert more than portal e-mails)
+var channelScores = map[LeadChannel]int{
+	LeadChannelWhatsApp: 15,
+	LeadChannelPhone:    15,
+	LeadChannelForm:     10,
+	LeadChannelEmail:    5,
+}
+
+// intentKeywords show buying intent in the message (folded: lowercase, no accents)
+var intentKeywords = []string{
+	"visita", "visitar", "agendar", "comprar", "proposta", "financiamento", "financiar",
+	"a vista", "entrada", "fgts", "urgente", "mudar", "documentacao",
+}
+
+// lowIntentKeywords show the lead is only browsing
+var lowIntentKeywords = []string{
+	"so curiosidade", "apenas curiosidade", "so pesquisando", "apenas pesquisando", "so olhando",
+	"apenas olhando", "sem pressa", "futuramente",
+}
+
+const (
+	intentKeywordPoints = 5
+	maxIntentPoints     = 15
+
+	// noResponseAfter is how long after the outreach an unanswered lead loses points
+	noResponseAfter = 72 * time.Hour
+)
+
+// ScoreLead computes the lead score and the factors that explain it
+func ScoreLead(signals LeadScoreSignals) (int, []LeadScoreFactor) {
+	factors := []LeadScoreFactor{{Code: LeadScoreFactorBase, Points: LeadScoreBase}}
+	add := func(code string, points int, detail string) {
+		factors = append(factors, LeadScoreFactor{Code: code, Points: points, Detail: detail})
+	}
+
+	if points, ok := channelScores[signals.Channel]; ok {
+		add(LeadScoreFactorChannel, points, string(signals.Channel))
+	}
+
+	message := foldScoreText(signals.Message)
+	if found := matchKeywords(message, intentKeywords); len(found) > 0 {
+		add(LeadScoreFactorMessageIntent, min(len(found)*intentKeywordPoints, maxIntentPoints), strings.Join(found, ", "))
+	}
+	if found := matchKeywords(message, lowIntentKeywords); len(found) > 0 {
+		add(LeadScoreFactorMessageLowIntent, -10, strings.Join(found, ", "))
+	}
+
+	if signals.PhoneValid {
+		add(LeadScoreFactorPhoneValid, 10, "")
+	}
+	if signals.FinancingSimulated {
+		add(LeadScoreFactorFinancingSimulation, 10, "")
+	}
+
+	switch {
+	case signals.PropertiesInquired >= 3:
+		add(LeadScoreFactorPropertiesInquired, 10, fmt.Sprintf("%d properties", signals.PropertiesInquired))
+	case signals.PropertiesInquired == 2:
+		add(LeadScoreFactorPropertiesInquired, 5, "2 properties")
+	}
+
+	switch {
+	case signals.PropertiesViewed >= 5:
+		add(LeadScoreFactorPropertiesViewed, 10, fmt.Sprintf("%d properties viewed", signals.PropertiesViewed))
+	case signals.PropertiesViewed >= 3:
+		add(LeadScoreFactorPropertiesViewed, 5, fmt.Sprintf("%d properties viewed", signals.PropertiesViewed))
+	}
+
+	if signals.OutreachAt != nil {
+		if signals.RepliedAt != nil {
+			latency := signals.RepliedAt.Sub(*signals.OutreachAt)
+			switch {
+			case latency <= time.Hour:
+				add(LeadScoreFactorResponseLatency, 10, "replied within 1 hour")
+			case latency <= 24*time.Hour:
+				add(LeadScoreFactorResponseLatency, 5, "replied within 24 hours")
+			}
+		} else if signals.Now.Sub(*signals.OutreachAt) > noResponseAfter {
+			add(LeadScoreFactorNoResponse, -10, "no reply in 72 hours")
+		}
+	}
+
+	if signals.Budget > 0 && signals.PropertyPrice > 0 {
+		ratio := signals.Budget / signals.PropertyPrice
+		detail := fmt.Sprintf("budget %.0f%% of price", ratio*100)
+		switch {
+		case ratio >= 1:
+			add(LeadScoreFactorBudgetFit, 15, detail)
+		case ratio >= 0.8:
+			add(LeadScoreFactorBudgetFit, 8, detail)
+		case ratio < 0.5:
+			add(LeadScoreFactorBudgetFit, -15, detail)
+		}
+	}
+
+	score := 0
+	for _, factor := range factors {
+		score += factor.Points
+	}
+	return max(LeadScoreMin, min(score, LeadScoreMax)), factors
+}
+
+// matchKeywords returns the keywords found as whole words in the folded text
+func matchKeywords(text string, keywords []string) []string {
+	found := make([]string, 0)
+	padded := " " + text + " "
+	for _, keyword := range keywords {
+		if strings.Contains(padded, " "+keyword+" ") {
+			found = append(found, keyword)
+		}
+	}
+	return found
+}
+
+// foldScoreText lowercases, removes accents and turns punctuation into spaces
+func foldScoreText(text string) string {
+	t := transform.Chain(norm.NFD, transform.RemoveFunc(func(r rune) bool {
+		return unicode.Is(unicode.Mn, r)
+	}), norm.NFC)
+	folded, _, err := transform.String(t, strings.ToLower(text))
+	if err != nil {
+		folded = strings.ToLower(text)
+	}
+
+	return strings.Join(strings.FieldsFunc(folded, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
@@ -0,0 +1,130 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+// Test ScoreLead adds the points of each signal to the base score and explains them
+func TestScoreLead(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	outreach := now.Add(-2 * time.Hour)
+	replied := outreach.Add(30 * time.Minute)
+
+	score, factors := ScoreLead(LeadScoreSignals{
+		Channel:            LeadChannelForm,
+		Message:            "Olá! Quero agendar uma VISITA, tenho FGTS para a entrada.",
+		PhoneValid:         true,
+		PropertiesInquired: 2,
+		OutreachAt:         &outreach,
+		RepliedAt:          &replied,
+		Now:                now,
+	})
+
+	// base 30 + form 10 + intent 15 (4 keywords, capped) + phone 10 + 2 properties 5 + reply within 1h 10
+	if score != 80 {
+		t.Errorf("expected score 80, got %d (%+v)", score, factors)
+	}
+
+	points := make(map[string]int)
+	total := 0
+	for _, factor := range factors {
+		points[factor.Code] = factor.Points
+		total += factor.Points
+	}
+	if total != score {
+		t.Errorf("expected the factors to add up to the score, got %d", total)
+	}
+	if points[LeadScoreFactorMessageIntent] != 15 || points[LeadScoreFactorResponseLatency] != 10 {
+		t.Errorf("unexpected factors: %+v", factors)
+	}
+	if _, ok := points[LeadScoreFactorBudgetFit]; ok {
+		t.Error("expected no budget factor without a budget")
+	}
+}
+
+// Test ScoreLead penalizes low intent, unanswered outreach and a budget far below the price
+func TestScoreLeadLowIntent(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	outreach := now.Add(-4 * 24 * time.Hour)
+
+	score, factors := ScoreLead(LeadScoreSignals{
+		Channel:       LeadChannelEmail,
+		Message:       "Só curiosidade mesmo, sem pressa",
+		OutreachAt:    &outreach,
+		Budget:        200000,
+		PropertyPrice: 800000,
+		Now:           now,
+	})
+
+	// base 30 + e-mail 5 - low intent 10 - no reply 10 - budget 15 → clamped to 0
+	if score != 0 {
+		t.Errorf("expected score 0, got %d (%+v)", score, factors)
+	}
+
+	found := make(map[string]bool)
+	for _, factor := range factors {
+		found[factor.Code] = true
+	}
+	for _, code := range []string{LeadScoreFactorMessageLowIntent, LeadScoreFactorNoResponse, LeadScoreFactorBudgetFit} {
+		if !found[code] {
+			t.Errorf("expected factor %s, got %+v", code, factors)
+		}
+	}
+}
+
+// Test ScoreLead rewards leads that browsed several properties before contacting
+func TestScoreLeadPropertiesViewed(t *testing.T) {
+	tests := []struct {
+		viewed int
+		points int
+	}{
+		{0, 0},
+		{2, 0},
+		{3, 5},
+		{5, 10},
+		{12, 10},
+	}
+
+	for _, tt := range tests {
+		_, factors := ScoreLead(LeadScoreSignals{Channel: LeadChannelForm, PropertiesViewed: tt.viewed, Now: time.Now()})
+
+		points := 0
+		for _, factor := range factors {
+			if factor.Code == LeadScoreFactorPropertiesViewed {
+				points = factor.Points
+			}
+		}
+		if points != tt.points {
+			t.Errorf("%d properties viewed: expected %d points, got %d", tt.viewed, tt.points, points)
+		}
+	}
+}
+
+// Test ScoreLead caps the score at 100
+func TestScoreLeadMax(t *testing.T) {
+	score, _ := ScoreLead(LeadScoreSignals{
+		Channel:            LeadChannelWhatsApp,
+		Message:            "Quero comprar à vista, posso fazer proposta hoje",
+		PhoneValid:         true,
+		FinancingSimulated: true,
+		PropertiesInquired: 4,
+		Budget:             900000,
+		PropertyPrice:      850000,
+		Now:                time.Now(),
+	})
+	if score != LeadScoreMax {
+		t.Errorf("expected score %d, got %d", LeadScoreMax, score)
+	}
+}
+
+// Test keywords match whole words only, without accents or case
+func TestMatchKeywords(t *testing.T) {
+	found := matchKeywords(foldScoreText("Financiamento? Só à VISTA!"), []string{"financiamento", "a vista", "visita"})
+	if len(found) != 2 || found[0] != "financiamento" || found[1] != "a vista" {
+		t.Errorf("unexpected keywords: %v", found)
+	}
+	if found := matchKeywords(foldScoreText("revisitar"), []string{"visitar"}); len(found) != 0 {
+		t.Errorf("expected no match inside a word, got %v", found)
+	}
+}
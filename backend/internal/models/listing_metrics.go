@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+// TrackingEventType defines the type of engagement event sent by the public frontends
+type TrackingEventType string
+
+const (
+	TrackingEventView          TrackingEventType = "view"           // página do imóvel aberta
+	TrackingEventImpression    TrackingEventType = "impression"     // card exibido em listagem/busca
+	TrackingEventWhatsAppClick TrackingEventType = "whatsapp_click" // clique no botão WhatsApp
+)
+
+// MetricScope defines the entity a daily rollup is aggregated by
+type MetricScope string
+
+const (
+	MetricScopeProperty MetricScope = "property"
+	MetricScopeListing  MetricScope = "listing"
+	MetricScopeBroker   MetricScope = "broker"
+)
+
+// DailyMetrics is a daily rollup of engagement counters for a property, listing or broker
+// Collection: /tenants/{tenantId}/metrics_daily/{scope}_{entityId}_{YYYYMMDD}
+// IMPORTANTE: contadores são incrementados atomicamente (firestore.Increment), sem eventos brutos
+type DailyMetrics struct {
+	ID       string      `firestore:"-" json:"id"`
+	TenantID string      `firestore:"tenant_id" json:"tenant_id"`
+	Scope    MetricScope `firestore:"scope" json:"scope"`         // property, listing, broker
+	EntityID string      `firestore:"entity_id" json:"entity_id"` // ID da entidade do scope
+	Date     string      `firestore:"date" json:"date"`           // YYYY-MM-DD (America/Sao_Paulo)
+
+	// Contadores
+	Views          int64 `firestore:"views" json:"views"`
+	Impressions    int64 `firestore:"impressions" json:"impressions"`
+	WhatsAppClicks int64 `firestore:"whatsapp_clicks" json:"whatsapp_clicks"`
+	Leads          int64 `firestore:"leads" json:"leads"`
+
+	// Metadata
+	UpdatedAt time.Time `firestore:"updated_at" json:"updated_at"`
+}
+
+// MetricsCounterField maps a tracking event to its counter field in DailyMetrics
+func MetricsCounterField(eventType TrackingEventType) string {
+	switch eventType {
+	case TrackingEventView:
+		return "views"
+	case TrackingEventImpression:
+		return "impressions"
+	case TrackingEventWhatsAppClick:
+		return "whatsapp_clicks"
+	}
+	return ""
+}
@@ -0,0 +1,66 @@
+package models
+
+import "time"
+
+// MarketSnapshotScope defines the aggregation scope of a market snapshot
+type MarketSnapshotScope string
+
+const (
+	MarketSnapshotScopeTenant      MarketSnapshotScope = "tenant"      // apenas imóveis da imobiliária
+	MarketSnapshotScopeMarketplace MarketSnapshotScope = "marketplace" // todos os tenants (anonimizado)
+)
+
+// MarketSnapshot represents a periodic aggregation of market indicators (price/m², estoque, absorção)
+// Collection: /tenants/{tenantId}/market_snapshots/{period} (scope=tenant)
+// Collection: /market_snapshots/{period} (scope=marketplace, sem identificação de tenants)
+// IMPORTANTE: snapshots são gerados por job periódico, nunca calculados por request
+type MarketSnapshot struct {
+	ID       string              `firestore:"-" json:"id"`
+	TenantID string              `firestore:"tenant_id,omitempty" json:"tenant_id,omitempty"` // vazio para scope=marketplace
+	Scope    MarketSnapshotScope `firestore:"scope" json:"scope"`
+
+	// Período de referência (mensal, ex: "2026-10")
+	Period      string    `firestore:"period" json:"period"`
+	PeriodStart time.Time `firestore:"period_start" json:"period_start"`
+	PeriodEnd   time.Time `firestore:"period_end" json:"period_end"`
+
+	// Totais
+	TotalProperties int `firestore:"total_properties" json:"total_properties"`
+	TotalInventory  int `firestore:"total_inventory" json:"total_inventory"` // disponíveis no fim do período
+
+	// Indicadores por bairro + tipo
+	Segments []MarketSegmentStats `firestore:"segments" json:"segments"`
+
+	// Metadata
+	GeneratedAt time.Time `firestore:"generated_at" json:"generated_at"`
+}
+
+// MarketSegmentStats contains the indicators for a city/neighborhood/property type segment
+type MarketSegmentStats struct {
+	City         string       `firestore:"city" json:"city"`
+	State        string       `firestore:"state" json:"state"`
+	Neighborhood string       `firestore:"neighborhood" json:"neighborhood"`
+	PropertyType PropertyType `firestore:"property_type" json:"property_type"`
+
+	// Estoque
+	InventoryCount int `firestore:"inventory_count" json:"inventory_count"` // disponíveis no fim do período
+	AbsorbedCount  int `firestore:"absorbed_count" json:"absorbed_count"`   // saíram do mercado no período (indisponíveis)
+
+	// Absorção = absorvidos / (estoque + absorvidos) * 100
+	AbsorptionRate float64 `firestore:"absorption_rate" json:"absorption_rate"`
+
+	// Preço por m² (apenas venda, área útil ou total)
+	PriceSampleSize  int     `firestore:"price_sample_size" json:"price_sample_size"`
+	MedianPricePerM2 float64 `firestore:"median_price_per_m2" json:"median_price_per_m2"`
+	MedianPrice      float64 `firestore:"median_price" json:"median_price"`
+
+	// Tempo médio de mercado (derivado do histórico de status)
+	AvgDaysOnMarket float64 `firestore:"avg_days_on_market" json:"avg_days_on_market"`
+
+	// Rentabilidade bruta anual (aluguel * 12 / preço de venda * 100)
+	RentYieldSampleSize int     `firestore:"rent_yield_sample_size" json:"rent_yield_sample_size"`
+	MedianRentYield     float64 `firestore:"median_rent_yield" json:"median_rent_yield"`
+
+	// Apenas scope=marketplace: número de tenants distintos no segmento (k-anonimato)
+	TenantCount int `firestore:"tenant_count,omitempty" json:"tenant_count,omitempty"`
+}
@@ -0,0 +1,103 @@
+package models
+
+import "time"
+
+// OutboxEventStatus represents the relay status of an outbox event
+type OutboxEventStatus string
+
+const (
+	OutboxEventPending   OutboxEventStatus = "pending"   // aguardando consumidores
+	OutboxEventPublished OutboxEventStatus = "published" // todos os consumidores processaram
+	OutboxEventFailed    OutboxEventStatus = "failed"    // tentativas esgotadas (reprocessar manualmente)
+)
+
+// Outbox relay retry policy (exponential backoff)
+const (
+	OutboxMaxAttempts    = 10
+	OutboxBaseRetryDelay = 10 * time.Second
+	OutboxMaxRetryDelay  = time.Hour
+)
+
+// OutboxRetryDelay returns the delay before relaying an event again after a failed attempt (attempts >= 1)
+// 10s, 20s, 40s... capped at 1h
+func OutboxRetryDelay(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	delay := OutboxBaseRetryDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= OutboxMaxRetryDelay {
+			return OutboxMaxRetryDelay
+		}
+	}
+	return delay
+}
+
+// OutboxEvent is a domain event committed in the same Firestore transaction as the write that produced it
+// The relay publishes it to every in-process consumer; the event ID is the idempotency key of each consumer
+// (the activity log entry has the same ID, so webhooks receive it as X-Webhook-ID)
+// Collection: /tenants/{tenantId}/outbox/{eventId}
+type OutboxEvent struct {
+	ID        string    `firestore:"-" json:"id"`
+	TenantID  string    `firestore:"tenant_id" json:"tenant_id"`
+	EventType string    `firestore:"event_type" json:"event_type"` // ex: lead_created_form, property_updated
+	ActorType ActorType `firestore:"actor_type" json:"actor_type"`
+	ActorID   string    `firestore:"actor_id,omitempty" json:"actor_id,omitempty"`
+
+	// Entidade alterada pela escrita
+	AggregateType string `firestore:"aggregate_type" json:"aggregate_type"` // lead, property
+	AggregateID   string `firestore:"aggregate_id" json:"aggregate_id"`
+
+	Metadata   map[string]interface{} `firestore:"metadata" json:"metadata"`
+	OccurredAt time.Time              `firestore:"occurred_at" json:"occurred_at"`
+
+	// Relay
+	Status        OutboxEventStatus `firestore:"status" json:"status"`
+	Consumers     []string          `firestore:"consumers" json:"consumers"` // consumidores que já processaram o evento
+	Attempts      int               `firestore:"attempts" json:"attempts"`
+	LastError     string            `firestore:"last_error,omitempty" json:"last_error,omitempty"`
+	NextAttemptAt time.Time         `firestore:"next_attempt_at" json:"next_attempt_at"`
+	PublishedAt   *time.Time        `firestore:"published_at,omitempty" json:"published_at,omitempty"`
+}
+
+// NewOutboxEvent creates a pending domain event
+func NewOutboxEvent(tenantID, eventType, aggregateType, aggregateID string, actorType ActorType, actorID string, metadata map[string]interface{}) *OutboxEvent {
+	now := time.Now()
+	return &OutboxEvent{
+		TenantID:      tenantID,
+		EventType:     eventType,
+		ActorType:     actorType,
+		ActorID:       actorID,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		Metadata:      metadata,
+		OccurredAt:    now,
+		Status:        OutboxEventPending,
+		Consumers:     []string{},
+		NextAttemptAt: now,
+	}
+}
+
+// HandledBy checks if a consumer already processed the event
+func (e *OutboxEvent) HandledBy(consumer string) bool {
+	for _, c := range e.Consumers {
+		if c == consumer {
+			return true
+		}
+	}
+	return false
+}
+
+// ActivityLog returns the activity log entry of the event (same ID: re-publishing never duplicates it)
+func (e *OutboxEvent) ActivityLog() *ActivityLog {
+	return &ActivityLog{
+		ID:        e.ID,
+		TenantID:  e.TenantID,
+		EventType: e.EventType,
+		ActorType: e.ActorType,
+		ActorID:   e.ActorID,
+		Metadata:  e.Metadata,
+		Timestamp: e.OccurredAt,
+	}
+}
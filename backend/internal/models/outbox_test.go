@@ -0,0 +1,70 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+// Test OutboxRetryDelay exponential backoff
+func TestOutboxRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempts int
+		expected time.Duration
+	}{
+		{0, 10 * time.Second},
+		{1, 10 * time.Second},
+		{2, 20 * time.Second},
+		{5, 160 * time.Second},
+		{9, 2560 * time.Second},
+		{10, OutboxMaxRetryDelay},
+	}
+
+	for _, tt := range tests {
+		if got := OutboxRetryDelay(tt.attempts); got != tt.expected {
+			t.Errorf("OutboxRetryDelay(%d) = %s, expected %s", tt.attempts, got, tt.expected)
+		}
+	}
+}
+
+// Test that an outbox event maps to the activity log entry with the same ID (consumer idempotency key)
+func TestOutboxEventActivityLog(t *testing.T) {
+	event := NewOutboxEvent("tenant-1", "lead_status_changed", "lead", "lead-1", ActorTypeSystem, "", map[string]interface{}{
+		"lead_id":    "lead-1",
+		"new_status": "contacted",
+	})
+	event.ID = "evt-1"
+
+	if event.Status != OutboxEventPending {
+		t.Errorf("new event status = %s, expected pending", event.Status)
+	}
+	if event.NextAttemptAt.After(time.Now()) {
+		t.Error("new event should be due immediately")
+	}
+
+	log := event.ActivityLog()
+	if log.ID != "evt-1" || log.TenantID != "tenant-1" || log.EventType != "lead_status_changed" {
+		t.Errorf("unexpected activity log: %+v", log)
+	}
+	if !log.Timestamp.Equal(event.OccurredAt) {
+		t.Errorf("activity log timestamp = %s, expected the event time %s", log.Timestamp, event.OccurredAt)
+	}
+	if log.Metadata["new_status"] != "contacted" {
+		t.Errorf("metadata not carried to the activity log")
+	}
+}
+
+// Test OutboxEvent.HandledBy
+func TestOutboxEventHandledBy(t *testing.T) {
+	event := NewOutboxEvent("tenant-1", "property_created", "property", "prop-1", ActorTypeSystem, "", nil)
+	if event.HandledBy("activity_log") {
+		t.Error("new event should not be handled by any consumer")
+	}
+
+	event.Consumers = append(event.Consumers, "activity_log")
+	if !event.HandledBy("activity_log") {
+		t.Error("activity_log should be recorded as handled")
+	}
+	if event.HandledBy("webhooks") {
+		t.Error("webhooks should not be recorded as handled")
+	}
+}
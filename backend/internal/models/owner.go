@@ -16,6 +16,11 @@ type Owner struct {
 	Document     string `firestore:"document,omitempty" json:"document,omitempty"`           // CPF/CNPJ
 	DocumentType string `firestore:"document_type,omitempty" json:"document_type,omitempty"` // "cpf", "cnpj"
 
+	// Blind indexes (HMAC) para busca quando email/phone/document estão criptografados (internal/pii)
+	EmailIndex    string `firestore:"email_bidx,omitempty" json:"-"`
+	PhoneIndex    string `firestore:"phone_bidx,omitempty" json:"-"`
+	DocumentIndex string `firestore:"document_bidx,omitempty" json:"-"`
+
 	// Completude dos dados
 	OwnerStatus OwnerStatus `firestore:"owner_status" json:"owner_status"` // incomplete, partial, verified
 
@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// OwnerPortalTokenType defines the kind of owner portal token
+type OwnerPortalTokenType string
+
+const (
+	OwnerPortalTokenMagicLink OwnerPortalTokenType = "magic_link" // link de acesso (uso único, curta duração)
+	OwnerPortalTokenSession   OwnerPortalTokenType = "session"    // sessão do portal (criada a partir do magic link)
+)
+
+// OwnerPortalToken represents a magic link or an authenticated session of the owner self-service portal
+// Collection: /tenants/{tenantId}/owner_portal_tokens/{tokenId}
+// IMPORTANTE: armazenar apenas o HASH do token (igual OwnerConfirmationToken)
+type OwnerPortalToken struct {
+	ID       string `firestore:"-" json:"id"`
+	TenantID string `firestore:"tenant_id" json:"tenant_id"`
+	OwnerID  string `firestore:"owner_id" json:"owner_id"` // ref Owner
+
+	Type      OwnerPortalTokenType `firestore:"type" json:"type"`             // magic_link, session
+	TokenHash string               `firestore:"token_hash" json:"token_hash"` // SHA-256 hash do token
+
+	// Magic link
+	Channel string `firestore:"channel,omitempty" json:"channel,omitempty"` // email, whatsapp
+
+	// Sessão
+	MagicLinkID string `firestore:"magic_link_id,omitempty" json:"magic_link_id,omitempty"` // magic link que originou a sessão
+	ClientIP    string `firestore:"client_ip,omitempty" json:"client_ip,omitempty"`
+	UserAgent   string `firestore:"user_agent,omitempty" json:"user_agent,omitempty"`
+
+	// Validade
+	ExpiresAt  time.Time  `firestore:"expires_at" json:"expires_at"`
+	UsedAt     *time.Time `firestore:"used_at,omitempty" json:"used_at,omitempty"` // magic link consumido
+	RevokedAt  *time.Time `firestore:"revoked_at,omitempty" json:"revoked_at,omitempty"`
+	LastSeenAt *time.Time `firestore:"last_seen_at,omitempty" json:"last_seen_at,omitempty"`
+
+	// Metadata
+	CreatedAt time.Time `firestore:"created_at" json:"created_at"`
+}
+
+// IsActive checks whether the token can still be used
+func (t *OwnerPortalToken) IsActive(now time.Time) bool {
+	if t.RevokedAt != nil || now.After(t.ExpiresAt) {
+		return false
+	}
+	if t.Type == OwnerPortalTokenMagicLink && t.UsedAt != nil {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+// Test OwnerPortalToken.IsActive: magic links are single use, sessions expire and can be revoked
+func TestOwnerPortalTokenIsActive(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	used := now.Add(-time.Minute)
+
+	tests := []struct {
+		name     string
+		token    OwnerPortalToken
+		expected bool
+	}{
+		{"magic link valid", OwnerPortalToken{Type: OwnerPortalTokenMagicLink, ExpiresAt: now.Add(time.Minute)}, true},
+		{"magic link expired", OwnerPortalToken{Type: OwnerPortalTokenMagicLink, ExpiresAt: now.Add(-time.Second)}, false},
+		{"magic link used", OwnerPortalToken{Type: OwnerPortalTokenMagicLink, ExpiresAt: now.Add(time.Minute), UsedAt: &used}, false},
+		{"session reused", OwnerPortalToken{Type: OwnerPortalTokenSession, ExpiresAt: now.Add(time.Hour), UsedAt: &used}, true},
+		{"session revoked", OwnerPortalToken{Type: OwnerPortalTokenSession, ExpiresAt: now.Add(time.Hour), RevokedAt: &used}, false},
+		{"session expired", OwnerPortalToken{Type: OwnerPortalTokenSession, ExpiresAt: now.Add(-time.Hour)}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.token.IsActive(now); got != tt.expected {
+				t.Errorf("IsActive() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// OwnerReport is the monthly performance report of a property sent to its owner
+// Collection: /tenants/{tenantId}/owner_reports/{propertyId}_{YYYYMM}
+// Entregue junto com a ScheduledConfirmation e exibido na página do link tokenizado
+// IMPORTANTE: contém apenas agregados (sem dados pessoais de leads)
+type OwnerReport struct {
+	ID         string `firestore:"-" json:"id"`
+	TenantID   string `firestore:"tenant_id" json:"tenant_id"`
+	PropertyID string `firestore:"property_id" json:"property_id"` // ref Property
+	OwnerID    string `firestore:"owner_id,omitempty" json:"owner_id,omitempty"`
+
+	// Período (mês de referência)
+	Period      string    `firestore:"period" json:"period"` // YYYY-MM
+	PeriodStart time.Time `firestore:"period_start" json:"period_start"`
+	PeriodEnd   time.Time `firestore:"period_end" json:"period_end"`
+
+	// Snapshot do imóvel
+	PropertyReference string       `firestore:"property_reference,omitempty" json:"property_reference,omitempty"`
+	PropertyType      PropertyType `firestore:"property_type" json:"property_type"`
+	Neighborhood      string       `firestore:"neighborhood" json:"neighborhood"`
+	City              string       `firestore:"city" json:"city"`
+	PriceAmount       float64      `firestore:"price_amount" json:"price_amount"`
+	PricePerM2        float64      `firestore:"price_per_m2,omitempty" json:"price_per_m2,omitempty"`
+
+	// Engajamento (rollups diários de metrics_daily)
+	Views          int64 `firestore:"views" json:"views"`
+	Impressions    int64 `firestore:"impressions" json:"impressions"`
+	WhatsAppClicks int64 `firestore:"whatsapp_clicks" json:"whatsapp_clicks"`
+
+	// Leads e visitas
+	LeadsTotal     int            `firestore:"leads_total" json:"leads_total"`
+	LeadsByChannel map[string]int `firestore:"leads_by_channel" json:"leads_by_channel"` // whatsapp, form, phone, email
+	Visits         int            `firestore:"visits" json:"visits"`
+
+	// Comparação com o mercado (MarketSnapshot do bairro + tipo)
+	NeighborhoodMedianPricePerM2 float64  `firestore:"neighborhood_median_price_per_m2,omitempty" json:"neighborhood_median_price_per_m2,omitempty"`
+	NeighborhoodSampleSize       int      `firestore:"neighborhood_sample_size,omitempty" json:"neighborhood_sample_size,omitempty"`
+	PriceVsMedianPercent         *float64 `firestore:"price_vs_median_percent,omitempty" json:"price_vs_median_percent,omitempty"` // +10 = 10% acima da mediana
+	MarketSnapshotPeriod         string   `firestore:"market_snapshot_period,omitempty" json:"market_snapshot_period,omitempty"`
+
+	// Tempo de mercado
+	DaysOnMarket int `firestore:"days_on_market" json:"days_on_market"`
+
+	// Metadata
+	GeneratedAt time.Time `firestore:"generated_at" json:"generated_at"`
+}
@@ -0,0 +1,143 @@
+package models
+
+import "time"
+
+// Subscription plans (Tenant.SubscriptionPlan)
+const (
+	SubscriptionPlanFree = "free"
+	SubscriptionPlanFull = "full"
+)
+
+// Subscription statuses (Tenant.SubscriptionStatus)
+const (
+	SubscriptionStatusActive    = "active"
+	SubscriptionStatusTrial     = "trial"
+	SubscriptionStatusExpired   = "expired"
+	SubscriptionStatusCancelled = "cancelled"
+)
+
+// DefaultTrialDays is the length of the trial of new tenants
+const DefaultTrialDays = 14
+
+// Plan features (Plan.Features)
+const (
+	PlanFeatureMarketplaceVisibility = "marketplace_visibility" // publish properties on the marketplace
+	PlanFeatureAPIAccess             = "api_access"             // create partner API keys
+)
+
+// Plan quota resources (Plan.Limit)
+const (
+	PlanResourceProperties       = "properties"
+	PlanResourceBrokers          = "brokers"
+	PlanResourcePhotosPerListing = "photos_per_listing"
+	PlanResourceImportsPerMonth  = "imports_per_month"
+)
+
+// Plan is a subscription plan with its quotas and features
+// Quotas of 0 are unlimited
+type Plan struct {
+	ID                  string         `json:"id"`
+	Name                string         `json:"name"`
+	MaxProperties       int            `json:"max_properties"`
+	MaxBrokers          int            `json:"max_brokers"` // active brokers
+	MaxPhotosPerListing int            `json:"max_photos_per_listing"`
+	MaxImportsPerMonth  int            `json:"max_imports_per_month"` // import batches per calendar month
+	Features            []string       `json:"features"`
+	RateLimits          PlanRateLimits `json:"rate_limits"`
+	MonthlyPriceCents   int64          `json:"monthly_price_cents"`    // base fee, BRL cents
+	UsagePrices         []UsagePrice   `json:"usage_prices,omitempty"` // metered usage billed above the included units
+}
+
+// plans are the subscription plans by ID (unknown or empty plans get the free plan)
+var plans = map[string]Plan{
+	SubscriptionPlanFree: {
+		ID:                  SubscriptionPlanFree,
+		Name:                "Free",
+		MaxProperties:       30,
+		MaxBrokers:          2,
+		MaxPhotosPerListing: 10,
+		MaxImportsPerMonth:  1,
+		Features:            []string{},
+		RateLimits: PlanRateLimits{
+			Tenant: RateLimitPolicy{Limit: 600, Window: time.Minute},
+			User:   RateLimitPolicy{Limit: 120, Window: time.Minute},
+		},
+	},
+	SubscriptionPlanFull: {
+		ID:                  SubscriptionPlanFull,
+		Name:                "Full",
+		MaxProperties:       0,
+		MaxBrokers:          0,
+		MaxPhotosPerListing: 50,
+		MaxImportsPerMonth:  0,
+		Features:            []string{PlanFeatureMarketplaceVisibility, PlanFeatureAPIAccess},
+		RateLimits: PlanRateLimits{
+			Tenant: RateLimitPolicy{Limit: 6000, Window: time.Minute},
+			User:   RateLimitPolicy{Limit: 600, Window: time.Minute},
+		},
+		MonthlyPriceCents: 29900,
+		UsagePrices: []UsagePrice{
+			{Metric: UsageMetricActiveListings, Description: "Anúncios ativos (anúncio-dia)", Included: 6000, UnitSize: 1, UnitPriceCents: 5},
+			{Metric: UsageMetricImports, Description: "Importações", Included: 20, UnitSize: 1, UnitPriceCents: 500},
+			{Metric: UsageMetricMessagesSent, Description: "Mensagens enviadas", Included: 2000, UnitSize: 1, UnitPriceCents: 5},
+			{Metric: UsageMetricStorageBytes, Description: "Armazenamento (GB-mês)", Included: 10 << 30, UnitSize: 1 << 30, UnitPriceCents: 100},
+		},
+	},
+}
+
+// GetPlan returns a subscription plan by ID (unknown or empty plans get the free plan)
+func GetPlan(id string) Plan {
+	if plan, ok := plans[id]; ok {
+		return plan
+	}
+	return plans[SubscriptionPlanFree]
+}
+
+// HasFeature checks if the plan includes a feature
+func (p Plan) HasFeature(feature string) bool {
+	for _, f := range p.Features {
+		if f == feature {
+			return true
+		}
+	}
+	return false
+}
+
+// Limit returns the quota of a resource (0 = unlimited)
+func (p Plan) Limit(resource string) int {
+	switch resource {
+	case PlanResourceProperties:
+		return p.MaxProperties
+	case PlanResourceBrokers:
+		return p.MaxBrokers
+	case PlanResourcePhotosPerListing:
+		return p.MaxPhotosPerListing
+	case PlanResourceImportsPerMonth:
+		return p.MaxImportsPerMonth
+	}
+	return 0
+}
+
+// Allows checks if adding resources on top of the used ones stays within the quota
+func (p Plan) Allows(resource string, used, adding int) bool {
+	limit := p.Limit(resource)
+	return limit == 0 || used+adding <= limit
+}
+
+// IsTrialExpired checks if the tenant is in a trial that ended
+func (t *Tenant) IsTrialExpired(now time.Time) bool {
+	return t.SubscriptionStatus == SubscriptionStatusTrial && t.TrialEndsAt != nil && !now.Before(*t.TrialEndsAt)
+}
+
+// EffectivePlan returns the plan whose quotas apply to the tenant
+// Expired and cancelled subscriptions, and trials past their end date (before the expiry job runs),
+// fall back to the free plan
+func (t *Tenant) EffectivePlan(now time.Time) Plan {
+	switch {
+	case t.SubscriptionStatus == SubscriptionStatusExpired, t.SubscriptionStatus == SubscriptionStatusCancelled:
+		return GetPlan(SubscriptionPlanFree)
+	case t.IsTrialExpired(now):
+		return GetPlan(SubscriptionPlanFree)
+	}
+	return GetPlan(t.SubscriptionPlan)
+}
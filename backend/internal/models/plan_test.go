@@ -0,0 +1,78 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+// Test GetPlan (unknown plans get the free plan)
+func TestGetPlan(t *testing.T) {
+	if GetPlan(SubscriptionPlanFull).ID != SubscriptionPlanFull {
+		t.Error("expected the full plan")
+	}
+	if GetPlan("").ID != SubscriptionPlanFree || GetPlan("enterprise").ID != SubscriptionPlanFree {
+		t.Error("empty and unknown plans should get the free plan")
+	}
+}
+
+// Test Plan.HasFeature
+func TestPlanHasFeature(t *testing.T) {
+	if GetPlan(SubscriptionPlanFree).HasFeature(PlanFeatureMarketplaceVisibility) {
+		t.Error("free plan should not include marketplace visibility")
+	}
+	if !GetPlan(SubscriptionPlanFull).HasFeature(PlanFeatureMarketplaceVisibility) {
+		t.Error("full plan should include marketplace visibility")
+	}
+}
+
+// Test Plan.Allows (0 is unlimited)
+func TestPlanAllows(t *testing.T) {
+	plan := Plan{MaxProperties: 10, MaxBrokers: 0}
+
+	tests := []struct {
+		resource string
+		used     int
+		adding   int
+		expected bool
+	}{
+		{PlanResourceProperties, 0, 1, true},
+		{PlanResourceProperties, 9, 1, true},
+		{PlanResourceProperties, 10, 1, false},
+		{PlanResourceProperties, 5, 6, false},
+		{PlanResourceBrokers, 1000, 1, true},
+		{"unknown", 1000, 1, true},
+	}
+
+	for _, tt := range tests {
+		if got := plan.Allows(tt.resource, tt.used, tt.adding); got != tt.expected {
+			t.Errorf("Allows(%s, %d, %d) = %v, expected %v", tt.resource, tt.used, tt.adding, got, tt.expected)
+		}
+	}
+}
+
+// Test Tenant.EffectivePlan for each subscription status
+func TestTenantEffectivePlan(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	future := now.Add(24 * time.Hour)
+	past := now.Add(-time.Second)
+
+	tests := []struct {
+		name     string
+		tenant   Tenant
+		expected string
+	}{
+		{"active full", Tenant{SubscriptionPlan: SubscriptionPlanFull, SubscriptionStatus: SubscriptionStatusActive}, SubscriptionPlanFull},
+		{"running trial", Tenant{SubscriptionPlan: SubscriptionPlanFull, SubscriptionStatus: SubscriptionStatusTrial, TrialEndsAt: &future}, SubscriptionPlanFull},
+		{"ended trial", Tenant{SubscriptionPlan: SubscriptionPlanFull, SubscriptionStatus: SubscriptionStatusTrial, TrialEndsAt: &past}, SubscriptionPlanFree},
+		{"trial ending now", Tenant{SubscriptionPlan: SubscriptionPlanFull, SubscriptionStatus: SubscriptionStatusTrial, TrialEndsAt: &now}, SubscriptionPlanFree},
+		{"expired", Tenant{SubscriptionPlan: SubscriptionPlanFull, SubscriptionStatus: SubscriptionStatusExpired}, SubscriptionPlanFree},
+		{"cancelled", Tenant{SubscriptionPlan: SubscriptionPlanFull, SubscriptionStatus: SubscriptionStatusCancelled}, SubscriptionPlanFree},
+		{"no subscription", Tenant{}, SubscriptionPlanFree},
+	}
+
+	for _, tt := range tests {
+		if got := tt.tenant.EffectivePlan(now).ID; got != tt.expected {
+			t.Errorf("%s: EffectivePlan = %s, expected %s", tt.name, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// DocumentCategory defines the type of a property document
+type DocumentCategory string
+
+const (
+	DocumentCategoryMatricula            DocumentCategory = "matricula"             // Matrícula atualizada do imóvel
+	DocumentCategoryIPTU                 DocumentCategory = "iptu"                  // Carnê de IPTU
+	DocumentCategoryHabiteSe             DocumentCategory = "habite_se"             // Habite-se / auto de conclusão
+	DocumentCategoryCondominiumStatement DocumentCategory = "condominium_statement" // Declaração de quitação condominial
+	DocumentCategoryOwnerID              DocumentCategory = "owner_id"              // Documento de identidade do proprietário (RG/CNH/CPF)
+	DocumentCategorySaleAuthorization    DocumentCategory = "sale_authorization"    // Autorização de venda assinada
+	DocumentCategoryOther                DocumentCategory = "other"
+)
+
+// PropertyDocument represents a document attached to a property (matrícula, IPTU, contratos...)
+// Collection: /tenants/{tenantId}/property_documents/{documentId}
+// IMPORTANTE: o arquivo fica em objeto PRIVADO no Storage (StoragePath), nunca com URL pública
+// Acesso somente via signed URL de curta duração
+type PropertyDocument struct {
+	ID         string `firestore:"-" json:"id"`
+	TenantID   string `firestore:"tenant_id" json:"tenant_id"`
+	PropertyID string `firestore:"property_id" json:"property_id"`               // ref Property
+	OwnerID    string `firestore:"owner_id,omitempty" json:"owner_id,omitempty"` // ref Owner (quando enviado pelo proprietário)
+
+	// Classificação
+	Category    DocumentCategory `firestore:"category" json:"category"` // matricula, iptu, habite_se, condominium_statement, owner_id, sale_authorization, other
+	Description string           `firestore:"description,omitempty" json:"description,omitempty"`
+
+	// Validade (certidões, declarações e matrícula vencem)
+	ExpiresAt *time.Time `firestore:"expires_at,omitempty" json:"expires_at,omitempty"`
+
+	// Arquivo
+	FileName    string `firestore:"file_name" json:"file_name"`
+	ContentType string `firestore:"content_type" json:"content_type"`
+	Size        int64  `firestore:"size" json:"size"`
+	StoragePath string `firestore:"storage_path" json:"-"` // caminho do objeto privado (não expor)
+
+	// Auditoria
+	UploadedByType ActorType `firestore:"uploaded_by_type" json:"uploaded_by_type"` // user, owner
+	UploadedByID   string    `firestore:"uploaded_by_id,omitempty" json:"uploaded_by_id,omitempty"`
+
+	// Metadata
+	CreatedAt time.Time `firestore:"created_at" json:"created_at"`
+	UpdatedAt time.Time `firestore:"updated_at" json:"updated_at"`
+}
+
+// IsExpired checks whether the document is past its expiry date
+func (d *PropertyDocument) IsExpired(now time.Time) bool {
+	return d.ExpiresAt != nil && now.After(*d.ExpiresAt)
+}
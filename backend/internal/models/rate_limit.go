@@ -0,0 +1,68 @@
+package models
+
+import "time"
+
+// RateLimitPolicy allows Limit requests per fixed Window
+type RateLimitPolicy struct {
+	Limit  int           `json:"limit"`
+	Window time.Duration `json:"window"`
+}
+
+// PlanRateLimits are the request quotas of a subscription plan (Plan.RateLimits)
+// Tenant is shared by every user and API key of the tenant; User applies to each authenticated user
+// (API keys have their own limit, see APIKey.RateLimitPerMinute)
+type PlanRateLimits struct {
+	Tenant RateLimitPolicy `json:"tenant"`
+	User   RateLimitPolicy `json:"user"`
+}
+
+// RateLimitsForPlan returns the request quotas of a subscription plan (unknown or empty plans get the free quotas)
+func RateLimitsForPlan(plan string) PlanRateLimits {
+	return GetPlan(plan).RateLimits
+}
+
+// RateLimitResult is the outcome of counting a request in a fixed window
+// Its fields map to the RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset response headers
+type RateLimitResult struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	ResetAfter time.Duration // time until the window resets
+}
+
+// NewRateLimitResult builds the result for the count-th request of a window ending at resetAt
+func NewRateLimitResult(limit int, count int64, resetAt, now time.Time) *RateLimitResult {
+	remaining := int64(limit) - count
+	if remaining < 0 {
+		remaining = 0
+	}
+	resetAfter := resetAt.Sub(now)
+	if resetAfter < 0 {
+		resetAfter = 0
+	}
+
+	return &RateLimitResult{
+		Allowed:    count <= int64(limit),
+		Limit:      limit,
+		Remaining:  int(remaining),
+		ResetAfter: resetAfter,
+	}
+}
+
+// ResetSeconds returns the seconds until the window resets, rounded up (RateLimit-Reset)
+func (r *RateLimitResult) ResetSeconds() int {
+	seconds := int(r.ResetAfter / time.Second)
+	if r.ResetAfter%time.Second != 0 {
+		seconds++
+	}
+	return seconds
+}
+
+// RateLimitWindowStart returns the start of the fixed window containing now
+// Windows are aligned to absolute time so every replica counts in the same window
+func RateLimitWindowStart(now time.Time, window time.Duration) time.Time {
+	if window <= 0 {
+		return now
+	}
+	return now.Truncate(window)
+}
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+// Test NewRateLimitResult remaining, allowance and reset
+func TestNewRateLimitResult(t *testing.T) {
+	now := time.Date(2026, 1, 1, 10, 0, 30, 0, time.UTC)
+	resetAt := now.Add(30 * time.Second)
+
+	tests := []struct {
+		count     int64
+		allowed   bool
+		remaining int
+	}{
+		{1, true, 9},
+		{9, true, 1},
+		{10, true, 0},
+		{11, false, 0},
+		{50, false, 0},
+	}
+
+	for _, tt := range tests {
+		result := NewRateLimitResult(10, tt.count, resetAt, now)
+		if result.Allowed != tt.allowed || result.Remaining != tt.remaining || result.Limit != 10 {
+			t.Errorf("count %d: got allowed=%v remaining=%d limit=%d, expected allowed=%v remaining=%d",
+				tt.count, result.Allowed, result.Remaining, result.Limit, tt.allowed, tt.remaining)
+		}
+		if result.ResetSeconds() != 30 {
+			t.Errorf("count %d: ResetSeconds = %d, expected 30", tt.count, result.ResetSeconds())
+		}
+	}
+}
+
+// Test ResetSeconds rounds up partial seconds
+func TestRateLimitResultResetSeconds(t *testing.T) {
+	tests := []struct {
+		resetAfter time.Duration
+		expected   int
+	}{
+		{0, 0},
+		{500 * time.Millisecond, 1},
+		{time.Second, 1},
+		{59*time.Second + time.Millisecond, 60},
+	}
+
+	for _, tt := range tests {
+		result := &RateLimitResult{ResetAfter: tt.resetAfter}
+		if got := result.ResetSeconds(); got != tt.expected {
+			t.Errorf("ResetSeconds(%s) = %d, expected %d", tt.resetAfter, got, tt.expected)
+		}
+	}
+}
+
+// Test RateLimitWindowStart aligns windows
+func TestRateLimitWindowStart(t *testing.T) {
+	now := time.Date(2026, 1, 1, 10, 7, 42, 123, time.UTC)
+
+	if got := RateLimitWindowStart(now, time.Minute); !got.Equal(time.Date(2026, 1, 1, 10, 7, 0, 0, time.UTC)) {
+		t.Errorf("minute window start = %s", got)
+	}
+	if got := RateLimitWindowStart(now, time.Hour); !got.Equal(time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("hour window start = %s", got)
+	}
+}
+
+// Test RateLimitsForPlan (unknown plans get the free quotas)
+func TestRateLimitsForPlan(t *testing.T) {
+	free := RateLimitsForPlan(SubscriptionPlanFree)
+	full := RateLimitsForPlan(SubscriptionPlanFull)
+
+	if full.Tenant.Limit <= free.Tenant.Limit || full.User.Limit <= free.User.Limit {
+		t.Error("full plan should have higher quotas than the free plan")
+	}
+	if RateLimitsForPlan("") != free || RateLimitsForPlan("enterprise") != free {
+		t.Error("empty and unknown plans should get the free quotas")
+	}
+}
@@ -0,0 +1,96 @@
+package models
+
+import "time"
+
+// Default retention periods (LGPD Art. 15/16 - dados eliminados ao fim do tratamento)
+const (
+	DefaultLeadInactivityRetentionMonths = 24
+	DefaultRevokedConsentRetentionDays   = 30
+	DefaultActivityLogPIIRetentionYears  = 5
+)
+
+// Retention rules applied by the sweeper
+const (
+	RetentionRuleLeadInactivity = "lead_inactivity"      // leads sem atividade
+	RetentionRuleRevokedConsent = "lead_revoked_consent" // leads com consentimento revogado
+	RetentionRuleActivityLogPII = "activity_log_pii"     // PII no metadata dos activity logs
+)
+
+// ActivityLogPIIMetadataKeys are the activity log metadata keys holding personal data
+// Removed by the activity_log_pii rule; IDs and event data are kept for the audit trail
+var ActivityLogPIIMetadataKeys = []string{
+	"name",
+	"email",
+	"phone",
+	"document",
+	"user_email",
+	"client_ip",
+	"consent_ip",
+	"user_agent",
+}
+
+// RetentionPolicy holds the retention periods of a tenant (0 disables a rule)
+type RetentionPolicy struct {
+	LeadInactivityMonths int `firestore:"lead_inactivity_months" json:"lead_inactivity_months"`
+	RevokedConsentDays   int `firestore:"revoked_consent_days" json:"revoked_consent_days"`
+	ActivityLogPIIYears  int `firestore:"activity_log_pii_years" json:"activity_log_pii_years"`
+}
+
+// RetentionPolicyFor returns the tenant retention policy (settings with defaults)
+func RetentionPolicyFor(tenant *Tenant) RetentionPolicy {
+	return RetentionPolicy{
+		LeadInactivityMonths: tenant.IntSetting(TenantSettingLeadInactivityRetentionMonths, DefaultLeadInactivityRetentionMonths),
+		RevokedConsentDays:   tenant.IntSetting(TenantSettingRevokedConsentRetentionDays, DefaultRevokedConsentRetentionDays),
+		ActivityLogPIIYears:  tenant.IntSetting(TenantSettingActivityLogPIIRetentionYears, DefaultActivityLogPIIRetentionYears),
+	}
+}
+
+// Cutoff returns the date before which records are subject to a rule (false when the rule is disabled)
+func (p RetentionPolicy) Cutoff(rule string, now time.Time) (time.Time, bool) {
+	switch rule {
+	case RetentionRuleLeadInactivity:
+		if p.LeadInactivityMonths > 0 {
+			return now.AddDate(0, -p.LeadInactivityMonths, 0), true
+		}
+	case RetentionRuleRevokedConsent:
+		if p.RevokedConsentDays > 0 {
+			return now.AddDate(0, 0, -p.RevokedConsentDays), true
+		}
+	case RetentionRuleActivityLogPII:
+		if p.ActivityLogPIIYears > 0 {
+			return now.AddDate(-p.ActivityLogPIIYears, 0, 0), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// RetentionRuleResult is the outcome of one rule in a sweep
+type RetentionRuleResult struct {
+	Rule      string    `firestore:"rule" json:"rule"`
+	Enabled   bool      `firestore:"enabled" json:"enabled"`
+	Cutoff    time.Time `firestore:"cutoff,omitempty" json:"cutoff,omitempty"`
+	Matched   int       `firestore:"matched" json:"matched"` // registros elegíveis neste lote
+	Applied   int       `firestore:"applied" json:"applied"` // 0 em dry-run
+	Failed    int       `firestore:"failed" json:"failed"`
+	HasMore   bool      `firestore:"has_more" json:"has_more"`                         // lote cheio: há mais registros elegíveis
+	RecordIDs []string  `firestore:"record_ids,omitempty" json:"record_ids,omitempty"` // IDs afetados (ou que seriam, em dry-run)
+	Errors    []string  `firestore:"errors,omitempty" json:"errors,omitempty"`
+}
+
+// RetentionRun records a retention sweep (dry-run report or applied)
+// Collection: /tenants/{tenantId}/retention_runs/{runId}
+type RetentionRun struct {
+	ID          string                `firestore:"-" json:"id"`
+	TenantID    string                `firestore:"tenant_id" json:"tenant_id"`
+	DryRun      bool                  `firestore:"dry_run" json:"dry_run"`
+	BatchSize   int                   `firestore:"batch_size" json:"batch_size"`
+	Policy      RetentionPolicy       `firestore:"policy" json:"policy"`
+	Rules       []RetentionRuleResult `firestore:"rules" json:"rules"`
+	TriggeredBy string                `firestore:"triggered_by,omitempty" json:"triggered_by,omitempty"` // user ID ou "system"
+
+	// ActivityLogCursor is the timestamp of the last activity log pruned (next sweep resumes after it)
+	ActivityLogCursor *time.Time `firestore:"activity_log_cursor,omitempty" json:"activity_log_cursor,omitempty"`
+
+	StartedAt  time.Time `firestore:"started_at" json:"started_at"`
+	FinishedAt time.Time `firestore:"finished_at" json:"finished_at"`
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+// Test RetentionPolicyFor
+func TestRetentionPolicyFor(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings map[string]interface{}
+		expected RetentionPolicy
+	}{
+		{
+			name:     "Defaults when not configured",
+			settings: nil,
+			expected: RetentionPolicy{LeadInactivityMonths: 24, RevokedConsentDays: 30, ActivityLogPIIYears: 5},
+		},
+		{
+			name: "Tenant overrides (Firestore numbers)",
+			settings: map[string]interface{}{
+				TenantSettingLeadInactivityRetentionMonths: int64(12),
+				TenantSettingRevokedConsentRetentionDays:   float64(7),
+				TenantSettingActivityLogPIIRetentionYears:  int64(0),
+			},
+			expected: RetentionPolicy{LeadInactivityMonths: 12, RevokedConsentDays: 7, ActivityLogPIIYears: 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := RetentionPolicyFor(&Tenant{Settings: tt.settings})
+			if result != tt.expected {
+				t.Errorf("RetentionPolicyFor() = %+v, expected %+v", result, tt.expected)
+			}
+		})
+	}
+}
+
+// Test RetentionPolicy.Cutoff
+func TestRetentionPolicy_Cutoff(t *testing.T) {
+	now := time.Date(2026, 3, 15, 10, 0, 0, 0, time.UTC)
+	policy := RetentionPolicy{LeadInactivityMonths: 24, RevokedConsentDays: 30, ActivityLogPIIYears: 0}
+
+	tests := []struct {
+		rule          string
+		expected      time.Time
+		expectEnabled bool
+	}{
+		{RetentionRuleLeadInactivity, time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC), true},
+		{RetentionRuleRevokedConsent, time.Date(2026, 2, 13, 10, 0, 0, 0, time.UTC), true},
+		{RetentionRuleActivityLogPII, time.Time{}, false},
+		{"unknown", time.Time{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			cutoff, enabled := policy.Cutoff(tt.rule, now)
+			if enabled != tt.expectEnabled {
+				t.Fatalf("Cutoff() enabled = %v, expected %v", enabled, tt.expectEnabled)
+			}
+			if !cutoff.Equal(tt.expected) {
+				t.Errorf("Cutoff() = %v, expected %v", cutoff, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package models
+
+import "time"
+
+// SaleAuthorizationStatus defines the lifecycle status of a sale authorization
+type SaleAuthorizationStatus string
+
+const (
+	SaleAuthorizationStatusActive  SaleAuthorizationStatus = "active"  // vigente (ou aguardando início)
+	SaleAuthorizationStatusExpired SaleAuthorizationStatus = "expired" // prazo encerrado
+	SaleAuthorizationStatusRevoked SaleAuthorizationStatus = "revoked" // cancelada antes do prazo
+)
+
+// SaleAuthorization represents the "autorização de venda" signed by the owner (exclusive or not)
+// Collection: /tenants/{tenantId}/sale_authorizations/{authorizationId}
+// IMPORTANTE: exigida pelo CRECI para anunciar o imóvel
+// Com a configuração do tenant "require_sale_authorization", Visibility public/marketplace exige autorização válida
+type SaleAuthorization struct {
+	ID         string `firestore:"-" json:"id"`
+	TenantID   string `firestore:"tenant_id" json:"tenant_id"`
+	PropertyID string `firestore:"property_id" json:"property_id"` // ref Property
+	OwnerID    string `firestore:"owner_id" json:"owner_id"`       // ref Owner (quem assina)
+	BrokerID   string `firestore:"broker_id" json:"broker_id"`     // ref Broker (captador, recebe os alertas de vencimento)
+
+	// Condições
+	IsExclusive          bool    `firestore:"is_exclusive" json:"is_exclusive"`
+	CommissionPercentage float64 `firestore:"commission_percentage" json:"commission_percentage"` // ex: 6.0 = 6%
+
+	// Vigência
+	StartDate time.Time `firestore:"start_date" json:"start_date"`
+	EndDate   time.Time `firestore:"end_date" json:"end_date"`
+
+	// Documento assinado (PropertyDocument privado)
+	SignedDocumentID string     `firestore:"signed_document_id,omitempty" json:"signed_document_id,omitempty"` // ref PropertyDocument
+	SignedAt         *time.Time `firestore:"signed_at,omitempty" json:"signed_at,omitempty"`
+
+	// Status
+	Status        SaleAuthorizationStatus `firestore:"status" json:"status"` // active, expired, revoked
+	RevokedAt     *time.Time              `firestore:"revoked_at,omitempty" json:"revoked_at,omitempty"`
+	RevokedReason string                  `firestore:"revoked_reason,omitempty" json:"revoked_reason,omitempty"`
+
+	// Alerta de vencimento enviado ao captador (evita reenvio)
+	ExpiryAlertSentAt *time.Time `firestore:"expiry_alert_sent_at,omitempty" json:"expiry_alert_sent_at,omitempty"`
+
+	Notes string `firestore:"notes,omitempty" json:"notes,omitempty"`
+
+	// Metadata
+	CreatedBy string    `firestore:"created_by,omitempty" json:"created_by,omitempty"`
+	CreatedAt time.Time `firestore:"created_at" json:"created_at"`
+	UpdatedAt time.Time `firestore:"updated_at" json:"updated_at"`
+}
+
+// IsValid checks whether the authorization allows advertising the property at the given time
+// Valid = ativa, assinada e dentro da vigência
+func (a *SaleAuthorization) IsValid(now time.Time) bool {
+	if a.Status != SaleAuthorizationStatusActive || a.SignedDocumentID == "" {
+		return false
+	}
+	return !now.Before(a.StartDate) && now.Before(a.EndDate)
+}
@@ -18,6 +18,10 @@ type ScheduledConfirmation struct {
 	ConfirmationURL  string `firestore:"confirmation_url" json:"confirmation_url"`     // Full URL with token
 	ConfirmationLink string `firestore:"confirmation_link" json:"confirmation_link"`   // Short link (if applicable)
 
+	// Monthly owner report delivered with the confirmation
+	ReportID     string `firestore:"report_id,omitempty" json:"report_id,omitempty"`         // ref OwnerReport
+	ReportPeriod string `firestore:"report_period,omitempty" json:"report_period,omitempty"` // YYYY-MM
+
 	// Scheduling info
 	ScheduledFor time.Time                  `firestore:"scheduled_for" json:"scheduled_for"` // When it should be sent
 	SentAt       *time.Time                 `firestore:"sent_at,omitempty" json:"sent_at,omitempty"`
@@ -35,6 +35,12 @@ type Tenant struct {
 	IsActive        bool                   `firestore:"is_active" json:"is_active"`
 	IsPlatformAdmin bool                   `firestore:"is_platform_admin,omitempty" json:"is_platform_admin,omitempty"`
 
+	// White-label branding (nil = tenant data + platform defaults, see ResolvedBranding)
+	Branding *TenantBranding `firestore:"branding,omitempty" json:"branding,omitempty"`
+
+	// Public site domain (verified TenantDomain used in canonical URLs, empty = {slug}.platform subdomain)
+	PrimaryDomain string `firestore:"primary_domain,omitempty" json:"primary_domain,omitempty"`
+
 	// Subscription
 	SubscriptionPlan      string     `firestore:"subscription_plan,omitempty" json:"subscription_plan,omitempty"`           // "free", "full"
 	SubscriptionStatus    string     `firestore:"subscription_status,omitempty" json:"subscription_status,omitempty"`       // "active", "trial", "expired", "cancelled"
@@ -45,3 +51,53 @@ type Tenant struct {
 	CreatedAt time.Time `firestore:"created_at" json:"created_at"`
 	UpdatedAt time.Time `firestore:"updated_at" json:"updated_at"`
 }
+
+// Tenant settings keys (Settings map)
+const (
+	// TenantSettingRequireSaleAuthorization requires a valid SaleAuthorization for public/marketplace visibility
+	TenantSettingRequireSaleAuthorization = "require_sale_authorization"
+
+	// TenantSettingSaleAuthorizationAlertDays is how many days before expiry the broker is alerted (default 15)
+	TenantSettingSaleAuthorizationAlertDays = "sale_authorization_alert_days"
+
+	// TenantSettingLeadInactivityRetentionMonths anonymizes leads without activity after N months (default 24, 0 disables)
+	TenantSettingLeadInactivityRetentionMonths = "lead_inactivity_retention_months"
+
+	// TenantSettingRevokedConsentRetentionDays anonymizes leads N days after consent revocation (default 30, 0 disables)
+	TenantSettingRevokedConsentRetentionDays = "revoked_consent_retention_days"
+
+	// TenantSettingActivityLogPIIRetentionYears prunes PII from activity log metadata after N years (default 5, 0 disables)
+	TenantSettingActivityLogPIIRetentionYears = "activity_log_pii_retention_years"
+
+	// TenantSettingBillingPaymentMethod is how monthly invoices are charged: "pix" (default) or "card"
+	TenantSettingBillingPaymentMethod = "billing_payment_method"
+
+	// TenantSettingBillingCardToken is the gateway token of the card charged when the payment method is "card"
+	TenantSettingBillingCardToken = "billing_card_token"
+)
+
+// BoolSetting returns a boolean setting (false when missing)
+func (t *Tenant) BoolSetting(key string) bool {
+	value, _ := t.Settings[key].(bool)
+	return value
+}
+
+// StringSetting returns a string setting (empty when missing)
+func (t *Tenant) StringSetting(key string) string {
+	value, _ := t.Settings[key].(string)
+	return value
+}
+
+// IntSetting returns a numeric setting, or the fallback when missing
+// Firestore/JSON numbers may be decoded as int64 or float64
+func (t *Tenant) IntSetting(key string, fallback int) int {
+	switch value := t.Settings[key].(type) {
+	case int:
+		return value
+	case int64:
+		return int(value)
+	case float64:
+		return int(value)
+	}
+	return fallback
+}
@@ -0,0 +1,178 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Platform branding (used when the tenant did not configure its own)
+const (
+	PlatformBrandName          = "Ecosistema Imob"
+	DefaultBrandPrimaryColor   = "#667eea"
+	DefaultBrandSecondaryColor = "#764ba2"
+	DefaultBrandFontFamily     = "Arial, sans-serif"
+)
+
+// brandColorPattern matches #RGB and #RRGGBB colors
+var brandColorPattern = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// BrandingSocialLinks are the social profiles shown in the public site, e-mails and pages
+type BrandingSocialLinks struct {
+	Website   string `firestore:"website,omitempty" json:"website,omitempty"`
+	Instagram string `firestore:"instagram,omitempty" json:"instagram,omitempty"`
+	Facebook  string `firestore:"facebook,omitempty" json:"facebook,omitempty"`
+	LinkedIn  string `firestore:"linkedin,omitempty" json:"linkedin,omitempty"`
+	YouTube   string `firestore:"youtube,omitempty" json:"youtube,omitempty"`
+	TikTok    string `firestore:"tiktok,omitempty" json:"tiktok,omitempty"`
+}
+
+// urls returns the social links by field name (validation)
+func (l BrandingSocialLinks) urls() map[string]string {
+	return map[string]string{
+		"social_links.website":   l.Website,
+		"social_links.instagram": l.Instagram,
+		"social_links.facebook":  l.Facebook,
+		"social_links.linkedin":  l.LinkedIn,
+		"social_links.youtube":   l.YouTube,
+		"social_links.tiktok":    l.TikTok,
+	}
+}
+
+// TenantBranding is the white-label identity of a tenant (public site, e-mails, owner pages, WhatsApp messages)
+// Stored in Tenant.Branding; empty fields fall back to the tenant data and the platform defaults (see Tenant.ResolvedBranding)
+type TenantBranding struct {
+	// Visual identity
+	LogoURL           string `firestore:"logo_url,omitempty" json:"logo_url,omitempty"`
+	FaviconURL        string `firestore:"favicon_url,omitempty" json:"favicon_url,omitempty"`
+	PrimaryColor      string `firestore:"primary_color,omitempty" json:"primary_color,omitempty"`     // #RRGGBB
+	SecondaryColor    string `firestore:"secondary_color,omitempty" json:"secondary_color,omitempty"` // #RRGGBB
+	FontFamily        string `firestore:"font_family,omitempty" json:"font_family,omitempty"`
+	HeadingFontFamily string `firestore:"heading_font_family,omitempty" json:"heading_font_family,omitempty"`
+
+	// Communication
+	SenderName     string `firestore:"sender_name,omitempty" json:"sender_name,omitempty"`         // nome do remetente dos e-mails e assinatura do WhatsApp
+	ReplyTo        string `firestore:"reply_to,omitempty" json:"reply_to,omitempty"`               // Reply-To dos e-mails
+	WhatsAppNumber string `firestore:"whatsapp_number,omitempty" json:"whatsapp_number,omitempty"` // somente dígitos, com DDI (ex: 5535999999999)
+
+	SocialLinks BrandingSocialLinks `firestore:"social_links,omitempty" json:"social_links,omitempty"`
+
+	// Legal
+	CRECI       string `firestore:"creci,omitempty" json:"creci,omitempty"`
+	LegalFooter string `firestore:"legal_footer,omitempty" json:"legal_footer,omitempty"` // rodapé legal (razão social, CRECI, CNPJ)
+}
+
+// Validate checks the colors and URLs of the branding (empty fields are allowed)
+func (b *TenantBranding) Validate() error {
+	colors := map[string]string{
+		"primary_color":   b.PrimaryColor,
+		"secondary_color": b.SecondaryColor,
+	}
+	for field, color := range colors {
+		if color != "" && !brandColorPattern.MatchString(color) {
+			return fmt.Errorf("%s must be a hex color (#RRGGBB)", field)
+		}
+	}
+
+	urls := b.SocialLinks.urls()
+	urls["logo_url"] = b.LogoURL
+	urls["favicon_url"] = b.FaviconURL
+	for field, url := range urls {
+		if url != "" && !strings.HasPrefix(url, "https://") && !strings.HasPrefix(url, "http://") {
+			return fmt.Errorf("%s must be an http(s) URL", field)
+		}
+	}
+
+	for _, digit := range b.WhatsAppNumber {
+		if digit < '0' || digit > '9' {
+			return fmt.Errorf("whatsapp_number must contain only digits")
+		}
+	}
+
+	return nil
+}
+
+// ResolvedBranding returns the branding of the tenant with every empty field filled:
+// sender name, CRECI and WhatsApp number come from the tenant data, visual identity from the platform defaults
+func (t *Tenant) ResolvedBranding() TenantBranding {
+	var branding TenantBranding
+	if t.Branding != nil {
+		branding = *t.Branding
+	}
+
+	if branding.SenderName == "" {
+		branding.SenderName = t.Name
+	}
+	if branding.SenderName == "" {
+		branding.SenderName = PlatformBrandName
+	}
+	if branding.ReplyTo == "" {
+		branding.ReplyTo = t.Email
+	}
+	if branding.CRECI == "" {
+		branding.CRECI = t.CRECI
+	}
+	if branding.WhatsAppNumber == "" {
+		branding.WhatsAppNumber = brazilianWhatsAppNumber(t.Phone)
+	}
+	if branding.PrimaryColor == "" {
+		branding.PrimaryColor = DefaultBrandPrimaryColor
+	}
+	if branding.SecondaryColor == "" {
+		branding.SecondaryColor = DefaultBrandSecondaryColor
+	}
+	if branding.FontFamily == "" {
+		branding.FontFamily = DefaultBrandFontFamily
+	}
+	if branding.HeadingFontFamily == "" {
+		branding.HeadingFontFamily = branding.FontFamily
+	}
+	if branding.LegalFooter == "" {
+		branding.LegalFooter = branding.SenderName
+		if branding.CRECI != "" {
+			branding.LegalFooter = fmt.Sprintf("%s - CRECI %s", branding.SenderName, branding.CRECI)
+		}
+	}
+
+	return branding
+}
+
+// PlatformBranding returns the platform identity (e-mails not tied to a tenant)
+func PlatformBranding() TenantBranding {
+	tenant := &Tenant{Name: PlatformBrandName}
+	return tenant.ResolvedBranding()
+}
+
+// PublicTenantBranding is the public view of a tenant's branding (served by slug to the public sites)
+type PublicTenantBranding struct {
+	TenantID string `json:"tenant_id"`
+	Slug     string `json:"slug"`
+	Name     string `json:"name"`
+	TenantBranding
+}
+
+// PublicBranding returns the resolved branding of the tenant for the public branding endpoint
+func (t *Tenant) PublicBranding() *PublicTenantBranding {
+	return &PublicTenantBranding{
+		TenantID:       t.ID,
+		Slug:           t.Slug,
+		Name:           t.Name,
+		TenantBranding: t.ResolvedBranding(),
+	}
+}
+
+// brazilianWhatsAppNumber returns the digits of a phone with the Brazilian DDI (ex: (35) 99999-9999 -> 5535999999999)
+func brazilianWhatsAppNumber(phone string) string {
+	var sb strings.Builder
+	for _, char := range phone {
+		if char >= '0' && char <= '9' {
+			sb.WriteRune(char)
+		}
+	}
+
+	digits := sb.String()
+	if len(digits) == 10 || len(digits) == 11 {
+		return "55" + digits
+	}
+	return digits
+}
@@ -0,0 +1,60 @@
+package models
+
+import "testing"
+
+// Test Tenant.ResolvedBranding falls back to the tenant data and the platform defaults
+func TestTenantResolvedBranding(t *testing.T) {
+	tenant := &Tenant{Name: "Imobiliária Sul", Email: "contato@sul.com.br", Phone: "(35) 99867-1079", CRECI: "12345-J"}
+
+	branding := tenant.ResolvedBranding()
+	if branding.SenderName != "Imobiliária Sul" || branding.ReplyTo != "contato@sul.com.br" {
+		t.Errorf("unexpected sender: %+v", branding)
+	}
+	if branding.WhatsAppNumber != "5535998671079" {
+		t.Errorf("WhatsAppNumber = %s, expected 5535998671079", branding.WhatsAppNumber)
+	}
+	if branding.PrimaryColor != DefaultBrandPrimaryColor || branding.HeadingFontFamily != DefaultBrandFontFamily {
+		t.Errorf("expected platform visual defaults, got %+v", branding)
+	}
+	if branding.LegalFooter != "Imobiliária Sul - CRECI 12345-J" {
+		t.Errorf("LegalFooter = %s", branding.LegalFooter)
+	}
+
+	tenant.Branding = &TenantBranding{SenderName: "Sul Imóveis", PrimaryColor: "#112233", WhatsAppNumber: "5511999990000", LegalFooter: "Sul Imóveis Ltda"}
+	branding = tenant.ResolvedBranding()
+	if branding.SenderName != "Sul Imóveis" || branding.PrimaryColor != "#112233" || branding.WhatsAppNumber != "5511999990000" || branding.LegalFooter != "Sul Imóveis Ltda" {
+		t.Errorf("tenant overrides not kept: %+v", branding)
+	}
+	if branding.CRECI != "12345-J" {
+		t.Errorf("CRECI should fall back to the tenant, got %s", branding.CRECI)
+	}
+
+	if platform := PlatformBranding(); platform.SenderName != PlatformBrandName || platform.LegalFooter != PlatformBrandName {
+		t.Errorf("unexpected platform branding: %+v", platform)
+	}
+}
+
+// Test TenantBranding.Validate
+func TestTenantBrandingValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		branding TenantBranding
+		valid    bool
+	}{
+		{"empty", TenantBranding{}, true},
+		{"complete", TenantBranding{PrimaryColor: "#fff", SecondaryColor: "#1a2B3c", LogoURL: "https://cdn.example.com/logo.png", WhatsAppNumber: "5535999999999", SocialLinks: BrandingSocialLinks{Instagram: "https://instagram.com/sul"}}, true},
+		{"invalid color", TenantBranding{PrimaryColor: "blue"}, false},
+		{"invalid logo URL", TenantBranding{LogoURL: "javascript:alert(1)"}, false},
+		{"invalid social link", TenantBranding{SocialLinks: BrandingSocialLinks{Facebook: "facebook.com/sul"}}, false},
+		{"formatted whatsapp", TenantBranding{WhatsAppNumber: "+55 (35) 99999-9999"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.branding.Validate()
+			if (err == nil) != tt.valid {
+				t.Errorf("Validate() error = %v, expected valid = %v", err, tt.valid)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package models
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DomainVerificationRecordPrefix is the DNS label of the TXT record that proves the ownership of a custom domain
+// (ex: _imob-verification.www.imobiliaria.com.br)
+const DomainVerificationRecordPrefix = "_imob-verification"
+
+// DomainStatus defines the verification status of a custom domain
+type DomainStatus string
+
+const (
+	DomainStatusPending  DomainStatus = "pending"  // aguardando o registro TXT
+	DomainStatusVerified DomainStatus = "verified" // propriedade confirmada, resolve o tenant
+)
+
+// DomainClaimTTL is how long a pending domain claim can be verified; expired claims no longer verify
+// and are pruned when the hostname is claimed again
+const DomainClaimTTL = 7 * 24 * time.Hour
+
+// hostnamePattern matches a fully qualified hostname (at least two labels)
+var hostnamePattern = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z]{2,63}$`)
+
+// TenantDomain is a custom domain claimed by a tenant for its public site
+// Collection: /tenant_domains/{tenant_id}_{hostname}
+// Vários tenants podem ter claims pendentes do mesmo hostname; só o que comprova o registro TXT fica verificado
+// (um único claim verificado por hostname) e os demais claims pendentes são removidos
+type TenantDomain struct {
+	Hostname string       `firestore:"hostname" json:"hostname"`
+	TenantID string       `firestore:"tenant_id" json:"tenant_id"`
+	Status   DomainStatus `firestore:"status" json:"status"`
+
+	// Ownership verification (DNS TXT)
+	VerificationToken string     `firestore:"verification_token" json:"verification_token"`
+	ExpiresAt         *time.Time `firestore:"expires_at,omitempty" json:"expires_at,omitempty"` // pending claims only
+	VerifiedAt        *time.Time `firestore:"verified_at,omitempty" json:"verified_at,omitempty"`
+	LastCheckedAt     *time.Time `firestore:"last_checked_at,omitempty" json:"last_checked_at,omitempty"`
+	LastError         string     `firestore:"last_error,omitempty" json:"last_error,omitempty"`
+
+	// Metadata
+	CreatedBy string    `firestore:"created_by,omitempty" json:"created_by,omitempty"`
+	CreatedAt time.Time `firestore:"created_at" json:"created_at"`
+	UpdatedAt time.Time `firestore:"updated_at" json:"updated_at"`
+}
+
+// TenantDomainDocID returns the document ID of a tenant's claim of a hostname
+func TenantDomainDocID(tenantID, hostname string) string {
+	return tenantID + "_" + hostname
+}
+
+// IsExpired reports whether a pending claim can no longer be verified
+func (d *TenantDomain) IsExpired(now time.Time) bool {
+	return d.Status == DomainStatusPending && d.ExpiresAt != nil && now.After(*d.ExpiresAt)
+}
+
+// VerificationRecordName returns the name of the TXT record checked for the domain
+func (d *TenantDomain) VerificationRecordName() string {
+	return DomainVerificationRecordPrefix + "." + d.Hostname
+}
+
+// VerificationRecordValue returns the expected value of the TXT record
+func (d *TenantDomain) VerificationRecordValue() string {
+	return "imob-verification=" + d.VerificationToken
+}
+
+// NormalizeHostname lowercases a host and removes the port, the scheme and the trailing dot
+// (ex: "HTTPS://WWW.Imob.com.br:443/" -> "www.imob.com.br")
+func NormalizeHostname(host string) string {
+	host = strings.ToLower(strings.TrimSpace(host))
+	host = strings.TrimPrefix(host, "https://")
+	host = strings.TrimPrefix(host, "http://")
+	if i := strings.IndexByte(host, '/'); i >= 0 {
+		host = host[:i]
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.TrimSuffix(host, ".")
+}
+
+// ValidateHostname checks that a normalized host is a fully qualified domain name
+func ValidateHostname(host string) error {
+	if len(host) > 253 || !hostnamePattern.MatchString(host) {
+		return fmt.Errorf("invalid hostname: %s", host)
+	}
+	return nil
+}
+
+// PlatformSubdomainSlug returns the tenant slug of a {slug}.{platformDomain} host
+func PlatformSubdomainSlug(host, platformDomain string) (string, bool) {
+	if platformDomain == "" {
+		return "", false
+	}
+
+	slug, found := strings.CutSuffix(host, "."+platformDomain)
+	if !found || slug == "" || strings.Contains(slug, ".") || slug == "www" {
+		return "", false
+	}
+	return slug, true
+}
+
+// SiteBaseURL returns the canonical base URL of the tenant's public site:
+// the primary custom domain, else the {slug}.{platformDomain} subdomain, else the fallback URL
+func (t *Tenant) SiteBaseURL(platformDomain, fallbackURL string) string {
+	switch {
+	case t.PrimaryDomain != "":
+		return "https://" + t.PrimaryDomain
+	case platformDomain != "" && t.Slug != "":
+		return fmt.Sprintf("https://%s.%s", t.Slug, platformDomain)
+	default:
+		return strings.TrimSuffix(fallbackURL, "/")
+	}
+}
+
+// PropertyCanonicalURL returns the canonical URL of a property on a site (by slug, else by ID)
+func PropertyCanonicalURL(baseURL string, property *Property) string {
+	path := property.Slug
+	if path == "" {
+		path = property.ID
+	}
+	return fmt.Sprintf("%s/imoveis/%s", baseURL, path)
+}
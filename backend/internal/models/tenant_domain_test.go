@@ -0,0 +1,107 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+// Test NormalizeHostname and ValidateHostname
+func TestNormalizeAndValidateHostname(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		valid    bool
+	}{
+		{"www.Imobiliaria.com.br", "www.imobiliaria.com.br", true},
+		{"HTTPS://imob.com.br:443/imoveis", "imob.com.br", true},
+		{"imob.com.br.", "imob.com.br", true},
+		{"localhost:3001", "localhost", false},
+		{"imob_sul.com", "imob_sul.com", false},
+		{"-imob.com", "-imob.com", false},
+	}
+
+	for _, tt := range tests {
+		got := NormalizeHostname(tt.input)
+		if got != tt.expected {
+			t.Errorf("NormalizeHostname(%q) = %q, expected %q", tt.input, got, tt.expected)
+		}
+		if err := ValidateHostname(got); (err == nil) != tt.valid {
+			t.Errorf("ValidateHostname(%q) error = %v, expected valid = %v", got, err, tt.valid)
+		}
+	}
+}
+
+// Test PlatformSubdomainSlug only accepts a single label under the platform domain
+func TestPlatformSubdomainSlug(t *testing.T) {
+	tests := []struct {
+		host string
+		slug string
+		ok   bool
+	}{
+		{"imob-sul.ecosistema.com.br", "imob-sul", true},
+		{"ecosistema.com.br", "", false},
+		{"www.ecosistema.com.br", "", false},
+		{"a.b.ecosistema.com.br", "", false},
+		{"imob-sul.outro.com.br", "", false},
+	}
+
+	for _, tt := range tests {
+		slug, ok := PlatformSubdomainSlug(tt.host, "ecosistema.com.br")
+		if slug != tt.slug || ok != tt.ok {
+			t.Errorf("PlatformSubdomainSlug(%q) = (%q, %v), expected (%q, %v)", tt.host, slug, ok, tt.slug, tt.ok)
+		}
+	}
+
+	if _, ok := PlatformSubdomainSlug("imob.ecosistema.com.br", ""); ok {
+		t.Error("expected no slug without a platform domain")
+	}
+}
+
+// Test Tenant.SiteBaseURL and PropertyCanonicalURL
+func TestTenantSiteBaseURL(t *testing.T) {
+	tenant := &Tenant{Slug: "imob-sul"}
+
+	if got := tenant.SiteBaseURL("", "http://localhost:3001/"); got != "http://localhost:3001" {
+		t.Errorf("fallback URL = %s", got)
+	}
+	if got := tenant.SiteBaseURL("ecosistema.com.br", "http://localhost:3001"); got != "https://imob-sul.ecosistema.com.br" {
+		t.Errorf("subdomain URL = %s", got)
+	}
+
+	tenant.PrimaryDomain = "www.imobsul.com.br"
+	base := tenant.SiteBaseURL("ecosistema.com.br", "http://localhost:3001")
+	if base != "https://www.imobsul.com.br" {
+		t.Errorf("primary domain URL = %s", base)
+	}
+
+	if got := PropertyCanonicalURL(base, &Property{ID: "p1", Slug: "casa-centro"}); got != "https://www.imobsul.com.br/imoveis/casa-centro" {
+		t.Errorf("PropertyCanonicalURL = %s", got)
+	}
+	if got := PropertyCanonicalURL(base, &Property{ID: "p1"}); got != "https://www.imobsul.com.br/imoveis/p1" {
+		t.Errorf("PropertyCanonicalURL without slug = %s", got)
+	}
+}
+
+// Test TenantDomain.IsExpired only expires pending claims
+func TestTenantDomainIsExpired(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	tests := []struct {
+		name     string
+		domain   TenantDomain
+		expected bool
+	}{
+		{"pending within TTL", TenantDomain{Status: DomainStatusPending, ExpiresAt: &future}, false},
+		{"pending past TTL", TenantDomain{Status: DomainStatusPending, ExpiresAt: &past}, true},
+		{"pending without expiry", TenantDomain{Status: DomainStatusPending}, false},
+		{"verified", TenantDomain{Status: DomainStatusVerified, ExpiresAt: &past}, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.domain.IsExpired(now); got != tt.expected {
+			t.Errorf("%s: IsExpired() = %v, expected %v", tt.name, got, tt.expected)
+		}
+	}
+}
@@ -25,8 +25,9 @@ type User struct {
 	Phone string `firestore:"phone,omitempty" json:"phone,omitempty"`
 
 	// Document information (optional for admin users)
-	Document     string `firestore:"document,omitempty" json:"document,omitempty"`           // CPF ou CNPJ
-	DocumentType string `firestore:"document_type,omitempty" json:"document_type,omitempty"` // "cpf" ou "cnpj"
+	Document      string `firestore:"document,omitempty" json:"document,omitempty"`           // CPF ou CNPJ
+	DocumentType  string `firestore:"document_type,omitempty" json:"document_type,omitempty"` // "cpf" ou "cnpj"
+	DocumentIndex string `firestore:"document_bidx,omitempty" json:"-"`                       // blind index (HMAC) quando Document está criptografado
 
 	// CRECI (optional - only for users who are also brokers)
 	CRECI string `firestore:"creci,omitempty" json:"creci,omitempty"` // Format: "XXXXX-F/UF"
@@ -111,6 +112,27 @@ func (u *User) HasPermission(permission string) bool {
 	return false
 }
 
+// PermissionViewPII allows seeing unmasked CPF/CNPJ, phone and email of owners, leads and users
+const PermissionViewPII = "pii.view"
+
+// piiRoles are the roles that see unmasked PII without an explicit permission
+// Brokers attend their own leads and owners, so they need contact data
+var piiRoles = map[string]bool{
+	"admin":        true,
+	"broker_admin": true,
+	"broker":       true,
+}
+
+// RoleCanViewPII checks whether a role sees unmasked PII by default
+func RoleCanViewPII(role string) bool {
+	return piiRoles[role]
+}
+
+// CanViewPII checks if the user may see unmasked PII in API responses
+func (u *User) CanViewPII() bool {
+	return RoleCanViewPII(u.Role) || u.HasPermission(PermissionViewPII)
+}
+
 // AddPermission adds a permission to the user
 func (u *User) AddPermission(permission string) {
 	if !u.HasPermission(permission) {
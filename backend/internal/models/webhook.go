@@ -0,0 +1,190 @@
+package models
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// WebhookEventTypes are the ActivityLog events integrators can subscribe to
+var WebhookEventTypes = []string{
+	"property_created",
+	"property_updated",
+	"property_deleted",
+	"property_status_changed",
+	"property_price_confirmed",
+	"property_visibility_changed",
+	"listing_created",
+	"listing_updated",
+	"listing_activated",
+	"listing_deactivated",
+	"listing_deleted",
+	"canonical_listing_assigned",
+	"canonical_listing_changed",
+	"lead_created_whatsapp",
+	"lead_created_form",
+	"lead_status_changed",
+	"lead_assigned_to_broker",
+	"owner_confirmed_status", // confirmação do proprietário (link ou portal)
+	"owner_confirmed_price",
+	"sale_authorization_signed",
+	"sale_authorization_expired",
+}
+
+// WebhookAllEvents subscribes to every event in WebhookEventTypes
+const WebhookAllEvents = "*"
+
+// IsValidWebhookEvent checks if an event can be subscribed to
+func IsValidWebhookEvent(eventType string) bool {
+	if eventType == WebhookAllEvents {
+		return true
+	}
+	for _, e := range WebhookEventTypes {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookSubscription is a tenant-configured endpoint that receives signed event deliveries
+// Collection: /tenants/{tenantId}/webhook_subscriptions/{subscriptionId}
+type WebhookSubscription struct {
+	ID          string   `firestore:"-" json:"id"`
+	TenantID    string   `firestore:"tenant_id" json:"tenant_id"`
+	Name        string   `firestore:"name" json:"name"` // ex: "Site builder", "BI"
+	URL         string   `firestore:"url" json:"url"`
+	Events      []string `firestore:"events" json:"events"` // tipos de evento ou "*"
+	Description string   `firestore:"description,omitempty" json:"description,omitempty"`
+	Active      bool     `firestore:"active" json:"active"`
+
+	// Secret assina as entregas (HMAC-SHA256); exibido apenas na criação e na rotação
+	Secret string `firestore:"secret" json:"-"`
+
+	CreatedBy string    `firestore:"created_by,omitempty" json:"created_by,omitempty"`
+	CreatedAt time.Time `firestore:"created_at" json:"created_at"`
+	UpdatedAt time.Time `firestore:"updated_at" json:"updated_at"`
+}
+
+// Matches checks if the subscription receives an event type
+func (s *WebhookSubscription) Matches(eventType string) bool {
+	if !s.Active {
+		return false
+	}
+	for _, e := range s.Events {
+		if e == eventType || (e == WebhookAllEvents && IsValidWebhookEvent(eventType)) {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDeliveryStatus represents the status of a delivery
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"   // aguardando primeira tentativa
+	WebhookDeliveryRetrying  WebhookDeliveryStatus = "retrying"  // falhou, nova tentativa agendada
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "delivered" // resposta 2xx
+	WebhookDeliveryDead      WebhookDeliveryStatus = "dead"      // dead-letter: tentativas esgotadas
+)
+
+// Webhook retry policy (exponential backoff)
+const (
+	WebhookMaxAttempts    = 8
+	WebhookBaseRetryDelay = 30 * time.Second
+	WebhookMaxRetryDelay  = 6 * time.Hour
+)
+
+// WebhookRetryDelay returns the delay before the next attempt after a failed one (attempts >= 1)
+// 30s, 1m, 2m, 4m... capped at 6h
+func WebhookRetryDelay(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	delay := WebhookBaseRetryDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= WebhookMaxRetryDelay {
+			return WebhookMaxRetryDelay
+		}
+	}
+	return delay
+}
+
+// WebhookAttempt is one HTTP attempt of a delivery (delivery log)
+// O corpo da resposta não é guardado: o log da entrega não pode expor respostas do endpoint
+type WebhookAttempt struct {
+	AttemptedAt time.Time `firestore:"attempted_at" json:"attempted_at"`
+	StatusCode  int       `firestore:"status_code,omitempty" json:"status_code,omitempty"`
+	Error       string    `firestore:"error,omitempty" json:"error,omitempty"`
+	DurationMs  int64     `firestore:"duration_ms" json:"duration_ms"`
+}
+
+// WebhookDelivery is one event queued for one subscription
+// Collection: /tenants/{tenantId}/webhook_deliveries/{deliveryId}
+// ID determinístico ({subscriptionId}_{activityLogId}) garante no máximo uma entrega por evento e assinatura
+type WebhookDelivery struct {
+	ID             string                `firestore:"-" json:"id"`
+	TenantID       string                `firestore:"tenant_id" json:"tenant_id"`
+	SubscriptionID string                `firestore:"subscription_id" json:"subscription_id"`
+	EventType      string                `firestore:"event_type" json:"event_type"`
+	ActivityLogID  string                `firestore:"activity_log_id" json:"activity_log_id"`
+	Sequence       int64                 `firestore:"sequence" json:"sequence"` // posição do evento na cadeia do ActivityLog
+	Payload        string                `firestore:"payload" json:"payload"`   // JSON assinado (corpo do POST)
+	Status         WebhookDeliveryStatus `firestore:"status" json:"status"`
+	Attempts       int                   `firestore:"attempts" json:"attempts"`
+	AttemptLog     []WebhookAttempt      `firestore:"attempt_log,omitempty" json:"attempt_log,omitempty"`
+	NextAttemptAt  time.Time             `firestore:"next_attempt_at" json:"next_attempt_at"`
+	DeliveredAt    *time.Time            `firestore:"delivered_at,omitempty" json:"delivered_at,omitempty"`
+	ReplayOf       string                `firestore:"replay_of,omitempty" json:"replay_of,omitempty"` // entrega original (replay)
+	CreatedAt      time.Time             `firestore:"created_at" json:"created_at"`
+	UpdatedAt      time.Time             `firestore:"updated_at" json:"updated_at"`
+}
+
+// WebhookEventPayload is the body posted to subscribers
+// Dados pessoais (ActivityLogPIIMetadataKeys) não são enviados; integradores consultam a API
+type WebhookEventPayload struct {
+	ID        string                 `json:"id"` // ActivityLog ID (idempotency key do integrador)
+	Type      string                 `json:"type"`
+	TenantID  string                 `json:"tenant_id"`
+	Sequence  int64                  `json:"sequence"`
+	CreatedAt time.Time              `json:"created_at"`
+	Data      map[string]interface{} `json:"data"`
+}
+
+// NewWebhookEventPayload builds the payload of an activity log event (PII removed)
+func NewWebhookEventPayload(log *ActivityLog) WebhookEventPayload {
+	data := make(map[string]interface{}, len(log.Metadata))
+	for key, value := range log.Metadata {
+		if !isActivityLogPIIKey(key) {
+			data[key] = value
+		}
+	}
+	return WebhookEventPayload{
+		ID:        log.ID,
+		Type:      log.EventType,
+		TenantID:  log.TenantID,
+		Sequence:  log.Sequence,
+		CreatedAt: log.Timestamp,
+		Data:      data,
+	}
+}
+
+// WebhookDispatchState tracks the last activity log sequence turned into deliveries
+// Document: /tenants/{tenantId}/webhook_state/dispatcher
+type WebhookDispatchState struct {
+	LastSequence int64     `firestore:"last_sequence" json:"last_sequence"`
+	UpdatedAt    time.Time `firestore:"updated_at" json:"updated_at"`
+}
+
+// SignWebhookPayload returns the signature header value: t=<unix>,v1=<hex HMAC-SHA256(secret, "<unix>.<body>")>
+// Integrators recompute v1 and reject old timestamps (replay protection)
+func SignWebhookPayload(secret string, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp.Unix())
+	mac.Write(body)
+	return fmt.Sprintf("t=%d,v1=%s", timestamp.Unix(), hex.EncodeToString(mac.Sum(nil)))
+}
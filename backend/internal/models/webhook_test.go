@@ -0,0 +1,115 @@
+package models
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Test SignWebhookPayload header format and HMAC
+func TestSignWebhookPayload(t *testing.T) {
+	secret := "whsec_test"
+	timestamp := time.Unix(1741617000, 0)
+	body := []byte(`{"id":"log-1","type":"lead_created_form"}`)
+
+	signature := SignWebhookPayload(secret, timestamp, body)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("1741617000." + string(body)))
+	expected := "t=1741617000,v1=" + hex.EncodeToString(mac.Sum(nil))
+	if signature != expected {
+		t.Errorf("signature = %s, expected %s", signature, expected)
+	}
+
+	if SignWebhookPayload("other", timestamp, body) == signature {
+		t.Error("signature should depend on the secret")
+	}
+	if SignWebhookPayload(secret, timestamp.Add(time.Second), body) == signature {
+		t.Error("signature should depend on the timestamp")
+	}
+}
+
+// Test WebhookRetryDelay exponential backoff
+func TestWebhookRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempts int
+		expected time.Duration
+	}{
+		{0, 30 * time.Second},
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{7, 32 * time.Minute},
+		{10, 256 * time.Minute},
+		{11, WebhookMaxRetryDelay},
+		{50, WebhookMaxRetryDelay},
+	}
+
+	for _, tt := range tests {
+		if got := WebhookRetryDelay(tt.attempts); got != tt.expected {
+			t.Errorf("WebhookRetryDelay(%d) = %s, expected %s", tt.attempts, got, tt.expected)
+		}
+	}
+}
+
+// Test WebhookSubscription.Matches
+func TestWebhookSubscriptionMatches(t *testing.T) {
+	tests := []struct {
+		name      string
+		events    []string
+		active    bool
+		eventType string
+		expected  bool
+	}{
+		{"Subscribed event", []string{"lead_created_form"}, true, "lead_created_form", true},
+		{"Other event", []string{"lead_created_form"}, true, "property_created", false},
+		{"Wildcard", []string{"*"}, true, "property_created", true},
+		{"Wildcard ignores internal events", []string{"*"}, true, "webhook_delivery_dead", false},
+		{"Inactive subscription", []string{"*"}, false, "property_created", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subscription := &WebhookSubscription{Events: tt.events, Active: tt.active}
+			if got := subscription.Matches(tt.eventType); got != tt.expected {
+				t.Errorf("Matches(%s) = %v, expected %v", tt.eventType, got, tt.expected)
+			}
+		})
+	}
+}
+
+// Test that NewWebhookEventPayload does not send PII
+func TestNewWebhookEventPayloadRemovesPII(t *testing.T) {
+	log := &ActivityLog{
+		ID:        "log-1",
+		TenantID:  "tenant-1",
+		EventType: "lead_created_form",
+		Sequence:  7,
+		Metadata: map[string]interface{}{
+			"lead_id": "lead-1",
+			"email":   "maria@example.com",
+		},
+	}
+	for _, key := range ActivityLogPIIMetadataKeys {
+		log.Metadata[key] = "pii"
+	}
+
+	payload := NewWebhookEventPayload(log)
+
+	if payload.ID != "log-1" || payload.Sequence != 7 || payload.Type != "lead_created_form" {
+		t.Errorf("unexpected payload envelope: %+v", payload)
+	}
+	if payload.Data["lead_id"] != "lead-1" {
+		t.Errorf("lead_id should be kept")
+	}
+	for key := range payload.Data {
+		for _, piiKey := range ActivityLogPIIMetadataKeys {
+			if strings.EqualFold(key, piiKey) {
+				t.Errorf("PII key %s sent in payload", key)
+			}
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package models
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/utils"
+)
+
+// WhatsAppPhoneSource identifies where the number of a WhatsApp deep link came from
+type WhatsAppPhoneSource string
+
+const (
+	WhatsAppPhoneSourceBroker   WhatsAppPhoneSource = "broker"          // telefone do corretor responsável (roteado)
+	WhatsAppPhoneSourceBranding WhatsAppPhoneSource = "tenant_branding" // WhatsApp configurado no branding do tenant
+	WhatsAppPhoneSourceTenant   WhatsAppPhoneSource = "tenant_phone"    // telefone cadastral do tenant
+	WhatsAppPhoneSourceNone     WhatsAppPhoneSource = "none"            // sem número: o usuário escolhe o contato no WhatsApp
+)
+
+// TenantSettingWhatsAppMessageTemplate is the tenant's template of the WhatsApp lead message (see WhatsAppMessagePlaceholders)
+const TenantSettingWhatsAppMessageTemplate = "whatsapp_message_template"
+
+// MaxWhatsAppMessageTemplateLength bounds the template size (the rendered message goes in the wa.me URL)
+const MaxWhatsAppMessageTemplateLength = 1000
+
+// DefaultWhatsAppMessageTemplate is the lead message used when the tenant has no template
+const DefaultWhatsAppMessageTemplate = "Olá! Tenho interesse no imóvel:\n\n" +
+	"📍 {address}\n" +
+	"💰 {price}\n" +
+	"🏠 {property_type}\n\n" +
+	"🔗 Link: {link}\n\n" +
+	"Protocolo: #{protocol}\n" +
+	"Via: {sender}"
+
+// WhatsAppMessagePlaceholders are the placeholders accepted in WhatsApp message templates
+var WhatsAppMessagePlaceholders = []string{
+	"{reference}",     // referência do imóvel
+	"{price}",         // preço formatado (ex: R$ 450.000,00)
+	"{link}",          // URL canônica do imóvel
+	"{protocol}",      // protocolo do lead (ID)
+	"{address}",       // rua - bairro, cidade
+	"{property_type}", // tipo do imóvel
+	"{sender}",        // nome do remetente do branding
+}
+
+// whatsAppPlaceholderPattern matches any {placeholder} of a template
+var whatsAppPlaceholderPattern = regexp.MustCompile(`\{[a-z_]+\}`)
+
+// WhatsAppMessageData are the values of the placeholders of a WhatsApp message
+type WhatsAppMessageData struct {
+	Reference    string
+	Price        string
+	Link         string
+	Protocol     string
+	Address      string
+	PropertyType string
+	Sender       string
+}
+
+// ValidateWhatsAppMessageTemplate checks the size and the placeholders of a template
+func ValidateWhatsAppMessageTemplate(template string) error {
+	if strings.TrimSpace(template) == "" {
+		return fmt.Errorf("whatsapp message template cannot be empty")
+	}
+	if len(template) > MaxWhatsAppMessageTemplateLength {
+		return fmt.Errorf("whatsapp message template must have at most %d characters", MaxWhatsAppMessageTemplateLength)
+	}
+
+	for _, placeholder := range whatsAppPlaceholderPattern.FindAllString(template, -1) {
+		known := false
+		for _, accepted := range WhatsAppMessagePlaceholders {
+			if placeholder == accepted {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("unknown placeholder %s (accepted: %s)", placeholder, strings.Join(WhatsAppMessagePlaceholders, ", "))
+		}
+	}
+
+	return nil
+}
+
+// RenderWhatsAppMessage replaces the placeholders of a template (empty template = default)
+func RenderWhatsAppMessage(template string, data WhatsAppMessageData) string {
+	if template == "" {
+		template = DefaultWhatsAppMessageTemplate
+	}
+
+	replacer := strings.NewReplacer(
+		"{reference}", data.Reference,
+		"{price}", data.Price,
+		"{link}", data.Link,
+		"{protocol}", data.Protocol,
+		"{address}", data.Address,
+		"{property_type}", data.PropertyType,
+		"{sender}", data.Sender,
+	)
+	return replacer.Replace(template)
+}
+
+// WhatsAppMessageTemplate returns the tenant's WhatsApp message template (default when not configured)
+func (t *Tenant) WhatsAppMessageTemplate() string {
+	if template := t.StringSetting(TenantSettingWhatsAppMessageTemplate); template != "" {
+		return template
+	}
+	return DefaultWhatsAppMessageTemplate
+}
+
+// ResolveWhatsAppPhone returns the number (digits with DDI, as wa.me expects) that receives the WhatsApp lead:
+// the routed broker's phone, else the WhatsApp number of the tenant branding, else the tenant phone
+// Inactive brokers and numbers that are not valid E.164 after normalization are skipped
+func ResolveWhatsAppPhone(broker *Broker, tenant *Tenant) (string, WhatsAppPhoneSource) {
+	if broker != nil && broker.IsActive {
+		if phone := whatsAppPhoneDigits(broker.Phone); phone != "" {
+			return phone, WhatsAppPhoneSourceBroker
+		}
+	}
+
+	if tenant != nil {
+		if tenant.Branding != nil {
+			if phone := whatsAppPhoneDigits(tenant.Branding.WhatsAppNumber); phone != "" {
+				return phone, WhatsAppPhoneSourceBranding
+			}
+		}
+		if phone := whatsAppPhoneDigits(tenant.Phone); phone != "" {
+			return phone, WhatsAppPhoneSourceTenant
+		}
+	}
+
+	return "", WhatsAppPhoneSourceNone
+}
+
+// whatsAppPhoneDigits normalizes a phone to E.164 (Brazil by default) and returns its digits, or "" when invalid
+func whatsAppPhoneDigits(phone string) string {
+	if strings.TrimSpace(phone) == "" {
+		return ""
+	}
+
+	normalized := utils.NormalizePhoneE164(phone, "55")
+	if err := utils.ValidatePhoneE164(normalized); err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(normalized, "+")
+}
+
+// WhatsAppURL returns the wa.me deep link of a message (without phone the user picks the contact)
+func WhatsAppURL(phone, message string) string {
+	// WhatsApp does not decode "+" as space: spaces must be %20
+	text := strings.ReplaceAll(url.QueryEscape(message), "+", "%20")
+	return fmt.Sprintf("https://wa.me/%s?text=%s", phone, text)
+}
+
+// WhatsAppLink is a click-tracking short link of a WhatsApp deep link: the click is recorded
+// (lead, channel, property metrics) before redirecting to wa.me
+// Collection: /whatsapp_links/{code}
+// Coleção raiz: o redirect resolve o código sem saber o tenant
+type WhatsAppLink struct {
+	Code       string `firestore:"-" json:"code"`
+	TenantID   string `firestore:"tenant_id" json:"tenant_id"`
+	LeadID     string `firestore:"lead_id" json:"lead_id"`
+	PropertyID string `firestore:"property_id" json:"property_id"`
+	BrokerID   string `firestore:"broker_id,omitempty" json:"broker_id,omitempty"`
+
+	// Origem do lead (registrada em cada clique)
+	Channel   LeadChannel `firestore:"channel" json:"channel"`
+	UTMSource string      `firestore:"utm_source,omitempty" json:"utm_source,omitempty"`
+
+	// Destino
+	TargetURL   string              `firestore:"target_url" json:"target_url"`
+	PhoneSource WhatsAppPhoneSource `firestore:"phone_source" json:"phone_source"`
+
+	// Cliques (bots não contam)
+	Clicks        int64      `firestore:"clicks" json:"clicks"`
+	LastClickedAt *time.Time `firestore:"last_clicked_at,omitempty" json:"last_clicked_at,omitempty"`
+
+	CreatedAt time.Time `firestore:"created_at" json:"created_at"`
+}
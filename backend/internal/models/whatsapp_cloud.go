@@ -0,0 +1,217 @@
+package models
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/utils"
+)
+
+// WhatsAppCloudProvider identifies the WhatsApp Cloud API (Meta) as message provider
+const WhatsAppCloudProvider = "whatsapp_cloud"
+
+// TenantSettingWhatsAppPhoneNumberID is the WhatsApp Cloud API phone number ID of the tenant's business number
+// (metadata.phone_number_id of the webhook payload): inbound messages to this number belong to the tenant
+const TenantSettingWhatsAppPhoneNumberID = "whatsapp_phone_number_id"
+
+// WhatsAppWebhookPayload is the payload of the WhatsApp Cloud API webhook (object = whatsapp_business_account)
+type WhatsAppWebhookPayload struct {
+	Object string                 `json:"object"`
+	Entry  []WhatsAppWebhookEntry `json:"entry"`
+}
+
+// WhatsAppWebhookEntry is a business account entry of the webhook payload
+type WhatsAppWebhookEntry struct {
+	ID      string                  `json:"id"`
+	Changes []WhatsAppWebhookChange `json:"changes"`
+}
+
+// WhatsAppWebhookChange is a change notification (field = messages)
+type WhatsAppWebhookChange struct {
+	Field string               `json:"field"`
+	Value WhatsAppWebhookValue `json:"value"`
+}
+
+// WhatsAppWebhookValue holds the messages received by a business number
+type WhatsAppWebhookValue struct {
+	MessagingProduct string `json:"messaging_product"`
+	Metadata         struct {
+		DisplayPhoneNumber string `json:"display_phone_number"`
+		PhoneNumberID      string `json:"phone_number_id"`
+	} `json:"metadata"`
+	Contacts []struct {
+		Profile struct {
+			Name string `json:"name"`
+		} `json:"profile"`
+		WaID string `json:"wa_id"`
+	} `json:"contacts"`
+	Messages []WhatsAppWebhookMessage `json:"messages"`
+}
+
+// WhatsAppWebhookMessage is a message of the webhook payload (only the fields used by the lead intake)
+type WhatsAppWebhookMessage struct {
+	From      string `json:"from"`
+	ID        string `json:"id"`
+	Timestamp string `json:"timestamp"` // unix seconds
+	Type      string `json:"type"`      // text, button, interactive, image, audio, video, document, location...
+	Text      *struct {
+		Body string `json:"body"`
+	} `json:"text,omitempty"`
+	Button *struct {
+		Text string `json:"text"`
+	} `json:"button,omitempty"`
+	Interactive *struct {
+		ButtonReply *struct {
+			Title string `json:"title"`
+		} `json:"button_reply,omitempty"`
+		ListReply *struct {
+			Title string `json:"title"`
+		} `json:"list_reply,omitempty"`
+	} `json:"interactive,omitempty"`
+	Image    *WhatsAppWebhookMedia `json:"image,omitempty"`
+	Video    *WhatsAppWebhookMedia `json:"video,omitempty"`
+	Document *WhatsAppWebhookMedia `json:"document,omitempty"`
+}
+
+// WhatsAppWebhookMedia is a media attachment (only the caption is kept in the conversation)
+type WhatsAppWebhookMedia struct {
+	Caption string `json:"caption,omitempty"`
+}
+
+// WhatsAppInboundMessage is a received message flattened from the webhook payload
+type WhatsAppInboundMessage struct {
+	PhoneNumberID string // business number that received the message
+	WaID          string // WhatsApp ID (phone digits with DDI) of the sender
+	ContactName   string
+	MessageID     string
+	ContentType   string
+	Body          string
+	SentAt        time.Time
+}
+
+// InboundMessages returns the messages of the payload (status notifications are ignored)
+func (p *WhatsAppWebhookPayload) InboundMessages() []WhatsAppInboundMessage {
+	var messages []WhatsAppInboundMessage
+	for _, entry := range p.Entry {
+		for _, change := range entry.Changes {
+			if change.Field != "" && change.Field != "messages" {
+				continue
+			}
+
+			names := make(map[string]string, len(change.Value.Contacts))
+			for _, contact := range change.Value.Contacts {
+				names[contact.WaID] = contact.Profile.Name
+			}
+
+			for _, message := range change.Value.Messages {
+				sentAt := time.Now()
+				if seconds, err := strconv.ParseInt(message.Timestamp, 10, 64); err == nil {
+					sentAt = time.Unix(seconds, 0)
+				}
+
+				messages = append(messages, WhatsAppInboundMessage{
+					PhoneNumberID: change.Value.Metadata.PhoneNumberID,
+					WaID:          message.From,
+					ContactName:   names[message.From],
+					MessageID:     message.ID,
+					ContentType:   message.Type,
+					Body:          message.body(),
+					SentAt:        sentAt,
+				})
+			}
+		}
+	}
+	return messages
+}
+
+// body returns the text of a message (text, button and list replies, media captions)
+func (m *WhatsAppWebhookMessage) body() string {
+	switch {
+	case m.Text != nil:
+		return m.Text.Body
+	case m.Button != nil:
+		return m.Button.Text
+	case m.Interactive != nil && m.Interactive.ButtonReply != nil:
+		return m.Interactive.ButtonReply.Title
+	case m.Interactive != nil && m.Interactive.ListReply != nil:
+		return m.Interactive.ListReply.Title
+	case m.Image != nil:
+		return m.Image.Caption
+	case m.Video != nil:
+		return m.Video.Caption
+	case m.Document != nil:
+		return m.Document.Caption
+	}
+	return ""
+}
+
+// VerifyWhatsAppSignature checks the X-Hub-Signature-256 header (sha256=<hex HMAC-SHA256(appSecret, body)>)
+func VerifyWhatsAppSignature(appSecret string, body []byte, header string) bool {
+	signature, found := strings.CutPrefix(header, "sha256=")
+	if !found {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(appSecret))
+	mac.Write(body)
+	return hmac.Equal([]byte(signature), []byte(hex.EncodeToString(mac.Sum(nil))))
+}
+
+// WhatsAppMessageRefs are the identifiers found in the text of an inbound message
+// (the deep link message carries the protocol, the property link and/or the reference)
+type WhatsAppMessageRefs struct {
+	ShortLinkCode string // .../w/{code}
+	Protocol      string // "Protocolo: #{leadId}"
+	PropertySlug  string // .../imoveis/{slug}
+	Reference     string // "Ref: AP00335" / "Referência AP00335"
+}
+
+var (
+	whatsAppShortLinkPattern = regexp.MustCompile(`/w/([A-Za-z0-9_-]{8})\b`)
+	whatsAppProtocolPattern  = regexp.MustCompile(`(?i)protocolo:?\s*#?\s*([A-Za-z0-9]{6,})`)
+	whatsAppSlugPattern      = regexp.MustCompile(`/imoveis/([a-z0-9]+(?:-[a-z0-9]+)*)`)
+	whatsAppReferencePattern = regexp.MustCompile(`(?i)\b(?:ref|refer[êe]ncia|c[óo]digo)\b\.?:?\s*#?\s*([A-Za-z0-9][A-Za-z0-9-]{1,})`)
+)
+
+// ParseWhatsAppMessageRefs extracts the short link code, protocol, property slug and reference of a message
+func ParseWhatsAppMessageRefs(text string) WhatsAppMessageRefs {
+	var refs WhatsAppMessageRefs
+	if match := whatsAppShortLinkPattern.FindStringSubmatch(text); match != nil {
+		refs.ShortLinkCode = match[1]
+	}
+	if match := whatsAppProtocolPattern.FindStringSubmatch(text); match != nil {
+		refs.Protocol = match[1]
+	}
+	if match := whatsAppSlugPattern.FindStringSubmatch(text); match != nil {
+		refs.PropertySlug = match[1]
+	}
+	if match := whatsAppReferencePattern.FindStringSubmatch(text); match != nil {
+		refs.Reference = strings.ToUpper(match[1])
+	}
+	return refs
+}
+
+// HasPropertyRef reports whether the message identifies a lead or a property
+func (r WhatsAppMessageRefs) HasPropertyRef() bool {
+	return r.ShortLinkCode != "" || r.Protocol != "" || r.PropertySlug != "" || r.Reference != ""
+}
+
+// LeadPhoneFromWhatsAppID converts a WhatsApp ID to the phone format of leads:
+// Brazilian numbers as (XX) XXXXX-XXXX, other countries in E.164
+func LeadPhoneFromWhatsAppID(waID string) string {
+	digits := strings.TrimPrefix(utils.NormalizePhoneE164(waID, ""), "+")
+	if national, found := strings.CutPrefix(digits, "55"); found && (len(national) == 10 || len(national) == 11) {
+		return utils.NormalizePhoneBR(national)
+	}
+	return "+" + digits
+}
+
+// WhatsAppRecipient returns the Cloud API recipient of a lead phone: digits with DDI ("" when invalid)
+func WhatsAppRecipient(phone string) string {
+	return whatsAppPhoneDigits(phone)
+}
@@ -0,0 +1,173 @@
+package models
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+)
+
+// Test InboundMessages flattens the Cloud API payload with the contact names and message texts
+func TestWhatsAppWebhookPayloadInboundMessages(t *testing.T) {
+	raw := `{
+		"object": "whatsapp_business_account",
+		"entry": [{
+			"id": "102290129340398",
+			"changes": [{
+				"field": "messages",
+				"value": {
+					"messaging_product": "whatsapp",
+					"metadata": {"display_phone_number": "15550783881", "phone_number_id": "106540352242922"},
+					"contacts": [{"profile": {"name": "Maria Silva"}, "wa_id": "5535998671079"}],
+					"messages": [
+						{"from": "5535998671079", "id": "wamid.A", "timestamp": "1700000000", "type": "text", "text": {"body": "Olá! Protocolo: #abc123XYZ"}},
+						{"from": "5535998671079", "id": "wamid.B", "timestamp": "1700000060", "type": "image", "image": {"caption": "Foto da fachada"}},
+						{"from": "5535998671079", "id": "wamid.C", "timestamp": "1700000120", "type": "interactive", "interactive": {"button_reply": {"id": "yes", "title": "Quero visitar"}}}
+					]
+				}
+			}, {
+				"field": "message_template_status_update",
+				"value": {"messages": [{"from": "x", "id": "wamid.D", "type": "text", "text": {"body": "ignored"}}]}
+			}]
+		}]
+	}`
+
+	var payload WhatsAppWebhookPayload
+	if err := json.Unmarshal([]byte(raw), &payload); err != nil {
+		t.Fatalf("failed to decode payload: %v", err)
+	}
+
+	messages := payload.InboundMessages()
+	if len(messages) != 3 {
+		t.Fatalf("InboundMessages() returned %d messages, expected 3", len(messages))
+	}
+
+	first := messages[0]
+	if first.PhoneNumberID != "106540352242922" || first.WaID != "5535998671079" || first.ContactName != "Maria Silva" {
+		t.Errorf("unexpected sender of first message: %+v", first)
+	}
+	if first.MessageID != "wamid.A" || first.ContentType != "text" || first.Body != "Olá! Protocolo: #abc123XYZ" {
+		t.Errorf("unexpected content of first message: %+v", first)
+	}
+	if first.SentAt.Unix() != 1700000000 {
+		t.Errorf("SentAt = %v, expected unix 1700000000", first.SentAt)
+	}
+	if messages[1].Body != "Foto da fachada" {
+		t.Errorf("image caption = %q, expected %q", messages[1].Body, "Foto da fachada")
+	}
+	if messages[2].Body != "Quero visitar" {
+		t.Errorf("button reply = %q, expected %q", messages[2].Body, "Quero visitar")
+	}
+}
+
+// Test ParseWhatsAppMessageRefs finds the identifiers of the deep link message
+func TestParseWhatsAppMessageRefs(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected WhatsAppMessageRefs
+	}{
+		{
+			name: "default deep link message",
+			text: "Olá! Tenho interesse no imóvel Apartamento - Rua A, 10 - R$ 450.000,00\nhttps://api.imob.com.br/w/Ab3_x-9Z\nProtocolo: #lead42XYZ\nVia: Site",
+			expected: WhatsAppMessageRefs{
+				ShortLinkCode: "Ab3_x-9Z",
+				Protocol:      "lead42XYZ",
+			},
+		},
+		{
+			name: "canonical property link and reference",
+			text: "Vi o anúncio https://www.imobiliaria.com.br/imoveis/casa-3-quartos-centro Ref: ap00335",
+			expected: WhatsAppMessageRefs{
+				PropertySlug: "casa-3-quartos-centro",
+				Reference:    "AP00335",
+			},
+		},
+		{
+			name:     "reference written out",
+			text:     "Gostaria de informações do imóvel código CA-120",
+			expected: WhatsAppMessageRefs{Reference: "CA-120"},
+		},
+		{
+			name:     "plain text",
+			text:     "Bom dia, ainda está disponível?",
+			expected: WhatsAppMessageRefs{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			refs := ParseWhatsAppMessageRefs(tt.text)
+			if refs != tt.expected {
+				t.Errorf("ParseWhatsAppMessageRefs() = %+v, expected %+v", refs, tt.expected)
+			}
+			if refs.HasPropertyRef() != (tt.expected != WhatsAppMessageRefs{}) {
+				t.Errorf("HasPropertyRef() = %v", refs.HasPropertyRef())
+			}
+		})
+	}
+}
+
+// Test VerifyWhatsAppSignature accepts only the HMAC-SHA256 of the body with the app secret
+func TestVerifyWhatsAppSignature(t *testing.T) {
+	body := []byte(`{"object":"whatsapp_business_account"}`)
+	mac := hmac.New(sha256.New, []byte("app-secret"))
+	mac.Write(body)
+	valid := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	if !VerifyWhatsAppSignature("app-secret", body, valid) {
+		t.Error("expected valid signature to be accepted")
+	}
+	if VerifyWhatsAppSignature("other-secret", body, valid) {
+		t.Error("expected signature with another secret to be rejected")
+	}
+	if VerifyWhatsAppSignature("app-secret", []byte(`{}`), valid) {
+		t.Error("expected signature of another body to be rejected")
+	}
+	if VerifyWhatsAppSignature("app-secret", body, hex.EncodeToString(mac.Sum(nil))) {
+		t.Error("expected signature without sha256= prefix to be rejected")
+	}
+}
+
+// Test LeadPhoneFromWhatsAppID formats Brazilian numbers like CreateLead and keeps foreign numbers in E.164
+func TestLeadPhoneFromWhatsAppID(t *testing.T) {
+	tests := []struct {
+		waID     string
+		expected string
+	}{
+		{"5535998671079", "(35) 99867-1079"},
+		{"551133334444", "(11) 3333-4444"},
+		{"14155552671", "+14155552671"},
+		{"351912345678", "+351912345678"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.waID, func(t *testing.T) {
+			if phone := LeadPhoneFromWhatsAppID(tt.waID); phone != tt.expected {
+				t.Errorf("LeadPhoneFromWhatsAppID(%q) = %q, expected %q", tt.waID, phone, tt.expected)
+			}
+		})
+	}
+}
+
+// Test WhatsAppRecipient converts lead phones back to the digits the Cloud API expects
+func TestWhatsAppRecipient(t *testing.T) {
+	tests := []struct {
+		phone    string
+		expected string
+	}{
+		{"(35) 99867-1079", "5535998671079"},
+		{"+14155552671", "14155552671"},
+		{"WhatsApp", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.phone, func(t *testing.T) {
+			if recipient := WhatsAppRecipient(tt.phone); recipient != tt.expected {
+				t.Errorf("WhatsAppRecipient(%q) = %q, expected %q", tt.phone, recipient, tt.expected)
+			}
+		})
+	}
+}
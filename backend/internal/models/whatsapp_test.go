@@ -0,0 +1,113 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+// Test ResolveWhatsAppPhone falls back from the routed broker to the tenant branding and the tenant phone
+func TestResolveWhatsAppPhone(t *testing.T) {
+	activeBroker := &Broker{Phone: "(35) 99867-1079", IsActive: true}
+	tenant := &Tenant{Phone: "(11) 3333-4444", Branding: &TenantBranding{WhatsAppNumber: "5511999990000"}}
+
+	tests := []struct {
+		name           string
+		broker         *Broker
+		tenant         *Tenant
+		expectedPhone  string
+		expectedSource WhatsAppPhoneSource
+	}{
+		{"routed broker", activeBroker, tenant, "5535998671079", WhatsAppPhoneSourceBroker},
+		{"broker in E.164", &Broker{Phone: "+55 35 99867-1079", IsActive: true}, tenant, "5535998671079", WhatsAppPhoneSourceBroker},
+		{"no broker falls back to branding", nil, tenant, "5511999990000", WhatsAppPhoneSourceBranding},
+		{"inactive broker falls back to branding", &Broker{Phone: "(35) 99867-1079"}, tenant, "5511999990000", WhatsAppPhoneSourceBranding},
+		{"broker without phone falls back to branding", &Broker{IsActive: true}, tenant, "5511999990000", WhatsAppPhoneSourceBranding},
+		{"invalid broker phone falls back to branding", &Broker{Phone: "99867", IsActive: true}, tenant, "5511999990000", WhatsAppPhoneSourceBranding},
+		{"no branding number falls back to tenant phone", nil, &Tenant{Phone: "(11) 3333-4444", Branding: &TenantBranding{}}, "551133334444", WhatsAppPhoneSourceTenant},
+		{"no branding falls back to tenant phone", &Broker{IsActive: true}, &Tenant{Phone: "11 3333-4444"}, "551133334444", WhatsAppPhoneSourceTenant},
+		{"no number at all", nil, &Tenant{Phone: "WhatsApp"}, "", WhatsAppPhoneSourceNone},
+		{"no tenant", nil, nil, "", WhatsAppPhoneSourceNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			phone, source := ResolveWhatsAppPhone(tt.broker, tt.tenant)
+			if phone != tt.expectedPhone || source != tt.expectedSource {
+				t.Errorf("ResolveWhatsAppPhone() = (%s, %s), expected (%s, %s)", phone, source, tt.expectedPhone, tt.expectedSource)
+			}
+		})
+	}
+}
+
+// Test RenderWhatsAppMessage with the default and a tenant template
+func TestRenderWhatsAppMessage(t *testing.T) {
+	data := WhatsAppMessageData{
+		Reference:    "AP-102",
+		Price:        "R$ 450.000,00",
+		Link:         "https://www.sul.com.br/imoveis/ap-102",
+		Protocol:     "lead123",
+		Address:      "Rua A - Centro, Pouso Alegre",
+		PropertyType: "apartment",
+		Sender:       "Sul Imóveis",
+	}
+
+	message := RenderWhatsAppMessage("", data)
+	for _, expected := range []string{"R$ 450.000,00", "https://www.sul.com.br/imoveis/ap-102", "Protocolo: #lead123", "Via: Sul Imóveis"} {
+		if !strings.Contains(message, expected) {
+			t.Errorf("default message missing %q: %s", expected, message)
+		}
+	}
+
+	message = RenderWhatsAppMessage("Olá, quero o {reference} por {price}: {link} ({protocol}) {reference}", data)
+	expected := "Olá, quero o AP-102 por R$ 450.000,00: https://www.sul.com.br/imoveis/ap-102 (lead123) AP-102"
+	if message != expected {
+		t.Errorf("RenderWhatsAppMessage() = %s, expected %s", message, expected)
+	}
+
+	tenant := &Tenant{}
+	if tenant.WhatsAppMessageTemplate() != DefaultWhatsAppMessageTemplate {
+		t.Error("tenant without template should use the default")
+	}
+	tenant.Settings = map[string]interface{}{TenantSettingWhatsAppMessageTemplate: "Ref {reference}"}
+	if tenant.WhatsAppMessageTemplate() != "Ref {reference}" {
+		t.Errorf("WhatsAppMessageTemplate() = %s", tenant.WhatsAppMessageTemplate())
+	}
+}
+
+// Test ValidateWhatsAppMessageTemplate
+func TestValidateWhatsAppMessageTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		valid    bool
+	}{
+		{"default", DefaultWhatsAppMessageTemplate, true},
+		{"all placeholders", "{reference} {price} {link} {protocol} {address} {property_type} {sender}", true},
+		{"no placeholders", "Olá!", true},
+		{"empty", "  ", false},
+		{"unknown placeholder", "Olá {name}", false},
+		{"too long", strings.Repeat("a", MaxWhatsAppMessageTemplateLength+1), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateWhatsAppMessageTemplate(tt.template)
+			if (err == nil) != tt.valid {
+				t.Errorf("ValidateWhatsAppMessageTemplate() error = %v, expected valid = %v", err, tt.valid)
+			}
+		})
+	}
+}
+
+// Test WhatsAppURL escapes the message for wa.me
+func TestWhatsAppURL(t *testing.T) {
+	url := WhatsAppURL("5535998671079", "Olá! Ref #1 & R$ 10,00\nhttps://x.com/a?b=c")
+	expected := "https://wa.me/5535998671079?text=Ol%C3%A1%21%20Ref%20%231%20%26%20R%24%2010%2C00%0Ahttps%3A%2F%2Fx.com%2Fa%3Fb%3Dc"
+	if url != expected {
+		t.Errorf("WhatsAppURL() = %s, expected %s", url, expected)
+	}
+
+	if url := WhatsAppURL("", "Olá"); url != "https://wa.me/?text=Ol%C3%A1" {
+		t.Errorf("WhatsAppURL() without phone = %s", url)
+	}
+}
@@ -0,0 +1,257 @@
+package pii
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/utils"
+)
+
+// ciphertextPrefix marks encrypted values: enc:v1:{keyID}:{wrappedDEK}:{nonce||ciphertext}
+const ciphertextPrefix = "enc:v1:"
+
+// ErrMalformedCiphertext is returned when an encrypted value cannot be parsed
+var ErrMalformedCiphertext = errors.New("malformed ciphertext")
+
+// FieldKind identifies how a value is normalized before computing its blind index
+type FieldKind string
+
+const (
+	FieldPhone    FieldKind = "phone"    // only digits, without the 55 country code
+	FieldEmail    FieldKind = "email"    // lowercase, trimmed
+	FieldDocument FieldKind = "document" // only digits (CPF/CNPJ)
+	FieldName     FieldKind = "name"     // trimmed (encrypted only, never looked up)
+)
+
+// Encryptor encrypts/decrypts PII values and computes blind indexes
+// Safe for concurrent use; DEKs are cached in memory
+type Encryptor struct {
+	provider KeyProvider
+
+	mu         sync.Mutex
+	currentKey *dataKey          // DEK used for new values (wrapped with the current KEK)
+	unwrapped  map[string][]byte // wrapped DEK (keyID:base64) -> DEK
+}
+
+// dataKey is a DEK together with its wrapped form
+type dataKey struct {
+	keyID   string
+	dek     []byte
+	wrapped string // base64
+}
+
+// NewEncryptor creates an encryptor backed by the given key provider
+func NewEncryptor(provider KeyProvider) *Encryptor {
+	return &Encryptor{
+		provider:  provider,
+		unwrapped: make(map[string][]byte),
+	}
+}
+
+// CurrentKeyID returns the key ID new values are wrapped with
+func (e *Encryptor) CurrentKeyID() string {
+	return e.provider.CurrentKeyID()
+}
+
+// Encrypt encrypts a value; empty strings are stored as-is
+// Already encrypted values are returned unchanged
+func (e *Encryptor) Encrypt(ctx context.Context, plaintext string) (string, error) {
+	if plaintext == "" || IsEncrypted(plaintext) {
+		return plaintext, nil
+	}
+
+	key, err := e.dataKey(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := sealGCM(key.dek, []byte(plaintext))
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt value: %w", err)
+	}
+
+	return ciphertextPrefix + key.keyID + ":" + key.wrapped + ":" + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt decrypts a value produced by Encrypt
+// Plaintext values (records written before encryption was enabled) are returned unchanged
+func (e *Encryptor) Decrypt(ctx context.Context, value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+
+	keyID, wrapped, sealed, err := parseCiphertext(value)
+	if err != nil {
+		return "", err
+	}
+
+	dek, err := e.unwrap(ctx, keyID, wrapped)
+	if err != nil {
+		return "", err
+	}
+
+	ciphertext, err := base64.RawStdEncoding.DecodeString(sealed)
+	if err != nil {
+		return "", ErrMalformedCiphertext
+	}
+
+	plaintext, err := openGCM(dek, ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt value: %w", err)
+	}
+
+	return string(plaintext), nil
+}
+
+// NeedsRotation reports whether a stored value is plaintext or wrapped with a non-current key
+func (e *Encryptor) NeedsRotation(value string) bool {
+	if value == "" {
+		return false
+	}
+	if !IsEncrypted(value) {
+		return true
+	}
+
+	keyID, _, _, err := parseCiphertext(value)
+	if err != nil {
+		return true
+	}
+	return keyID != e.provider.CurrentKeyID()
+}
+
+// Rotate re-encrypts a value with the current key (plaintext values are encrypted)
+func (e *Encryptor) Rotate(ctx context.Context, value string) (string, error) {
+	if !e.NeedsRotation(value) {
+		return value, nil
+	}
+
+	plaintext, err := e.Decrypt(ctx, value)
+	if err != nil {
+		return "", err
+	}
+	return e.Encrypt(ctx, plaintext)
+}
+
+// BlindIndex computes the deterministic lookup token for a value
+// Values are normalized per kind, so "(11) 98765-4321" and "+5511987654321" match
+func (e *Encryptor) BlindIndex(kind FieldKind, value string) string {
+	normalized := Normalize(kind, value)
+	if normalized == "" {
+		return ""
+	}
+
+	mac := hmac.New(sha256.New, e.provider.BlindIndexKey())
+	mac.Write([]byte(kind))
+	mac.Write([]byte{0})
+	mac.Write([]byte(normalized))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Normalize returns the canonical form of a value used for blind indexes
+func Normalize(kind FieldKind, value string) string {
+	switch kind {
+	case FieldPhone:
+		digits := onlyDigits(value)
+		// Strip Brazilian country code (+55) from E.164 numbers
+		if (len(digits) == 12 || len(digits) == 13) && strings.HasPrefix(digits, "55") {
+			digits = digits[2:]
+		}
+		return digits
+	case FieldEmail:
+		return utils.NormalizeEmail(value)
+	case FieldDocument:
+		return onlyDigits(value)
+	default:
+		return strings.TrimSpace(value)
+	}
+}
+
+// IsEncrypted reports whether a stored value was produced by Encrypt
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, ciphertextPrefix)
+}
+
+// dataKey returns the DEK for new values, generating one when the current KEK changes
+func (e *Encryptor) dataKey(ctx context.Context) (*dataKey, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	keyID := e.provider.CurrentKeyID()
+	if e.currentKey != nil && e.currentKey.keyID == keyID {
+		return e.currentKey, nil
+	}
+
+	dek := make([]byte, 32)
+	if _, err := rand.Read(dek); err != nil {
+		return nil, fmt.Errorf("failed to generate data key: %w", err)
+	}
+
+	wrapped, err := e.provider.WrapKey(ctx, keyID, dek)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wrap data key: %w", err)
+	}
+
+	key := &dataKey{
+		keyID:   keyID,
+		dek:     dek,
+		wrapped: base64.RawURLEncoding.EncodeToString(wrapped),
+	}
+	e.currentKey = key
+	e.unwrapped[keyID+":"+key.wrapped] = dek
+	return key, nil
+}
+
+// unwrap returns the DEK for a wrapped key, calling the provider only once per DEK
+func (e *Encryptor) unwrap(ctx context.Context, keyID, wrapped string) ([]byte, error) {
+	cacheKey := keyID + ":" + wrapped
+
+	e.mu.Lock()
+	dek, ok := e.unwrapped[cacheKey]
+	e.mu.Unlock()
+	if ok {
+		return dek, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(wrapped)
+	if err != nil {
+		return nil, ErrMalformedCiphertext
+	}
+
+	dek, err = e.provider.UnwrapKey(ctx, keyID, raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key: %w", err)
+	}
+
+	e.mu.Lock()
+	e.unwrapped[cacheKey] = dek
+	e.mu.Unlock()
+	return dek, nil
+}
+
+// parseCiphertext splits enc:v1:{keyID}:{wrappedDEK}:{sealed}
+func parseCiphertext(value string) (keyID, wrapped, sealed string, err error) {
+	parts := strings.Split(strings.TrimPrefix(value, ciphertextPrefix), ":")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", "", "", ErrMalformedCiphertext
+	}
+	return parts[0], parts[1], parts[2], nil
+}
+
+// onlyDigits removes every non-digit character
+func onlyDigits(value string) string {
+	var b strings.Builder
+	for _, r := range value {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,185 @@
+package pii
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func newTestProvider(t *testing.T, currentKeyID string) *LocalKeyProvider {
+	t.Helper()
+	provider, err := NewLocalKeyProvider(currentKeyID, map[string][]byte{
+		"k1": bytes.Repeat([]byte{1}, 32),
+		"k2": bytes.Repeat([]byte{2}, 32),
+	}, bytes.Repeat([]byte{9}, 32))
+	if err != nil {
+		t.Fatalf("NewLocalKeyProvider() error = %v", err)
+	}
+	return provider
+}
+
+func TestEncryptor_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	enc := NewEncryptor(newTestProvider(t, "k1"))
+
+	ciphertext, err := enc.Encrypt(ctx, "123.456.789-09")
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	if !IsEncrypted(ciphertext) || strings.Contains(ciphertext, "456") {
+		t.Fatalf("Encrypt() = %q, want opaque ciphertext", ciphertext)
+	}
+
+	plaintext, err := enc.Decrypt(ctx, ciphertext)
+	if err != nil {
+		t.Fatalf("Decrypt() error = %v", err)
+	}
+	if plaintext != "123.456.789-09" {
+		t.Errorf("Decrypt() = %q, want original value", plaintext)
+	}
+
+	// Another encryptor (fresh cache) with the same keys must decrypt it too
+	other := NewEncryptor(newTestProvider(t, "k1"))
+	if got, err := other.Decrypt(ctx, ciphertext); err != nil || got != "123.456.789-09" {
+		t.Errorf("Decrypt() with new encryptor = %q, %v", got, err)
+	}
+}
+
+func TestEncryptor_PassThrough(t *testing.T) {
+	ctx := context.Background()
+	enc := NewEncryptor(newTestProvider(t, "k1"))
+
+	if got, _ := enc.Encrypt(ctx, ""); got != "" {
+		t.Errorf("Encrypt(\"\") = %q, want empty", got)
+	}
+	if got, _ := enc.Decrypt(ctx, "(11) 98765-4321"); got != "(11) 98765-4321" {
+		t.Errorf("Decrypt(plaintext) = %q, want unchanged", got)
+	}
+	if _, err := enc.Decrypt(ctx, "enc:v1:k1:broken"); !errors.Is(err, ErrMalformedCiphertext) {
+		t.Errorf("Decrypt(malformed) error = %v, want ErrMalformedCiphertext", err)
+	}
+}
+
+func TestEncryptor_Rotation(t *testing.T) {
+	ctx := context.Background()
+	old := NewEncryptor(newTestProvider(t, "k1"))
+	ciphertext, err := old.Encrypt(ctx, "joao@example.com")
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+
+	enc := NewEncryptor(newTestProvider(t, "k2"))
+	if !enc.NeedsRotation(ciphertext) {
+		t.Fatalf("NeedsRotation() = false for value wrapped with old key")
+	}
+	if !enc.NeedsRotation("plain@example.com") {
+		t.Errorf("NeedsRotation() = false for plaintext value")
+	}
+
+	rotated, err := enc.Rotate(ctx, ciphertext)
+	if err != nil {
+		t.Fatalf("Rotate() error = %v", err)
+	}
+	if !strings.HasPrefix(rotated, "enc:v1:k2:") || enc.NeedsRotation(rotated) {
+		t.Errorf("Rotate() = %q, want value wrapped with k2", rotated)
+	}
+	if got, _ := enc.Decrypt(ctx, rotated); got != "joao@example.com" {
+		t.Errorf("Decrypt(rotated) = %q", got)
+	}
+}
+
+func TestEncryptor_BlindIndex(t *testing.T) {
+	enc := NewEncryptor(newTestProvider(t, "k1"))
+
+	tests := []struct {
+		name string
+		kind FieldKind
+		a, b string
+	}{
+		{"phone formats", FieldPhone, "(11) 98765-4321", "+55 11 98765-4321"},
+		{"email case", FieldEmail, " Joao@Example.com", "joao@example.com"},
+		{"document formats", FieldDocument, "123.456.789-09", "12345678909"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if enc.BlindIndex(tt.kind, tt.a) != enc.BlindIndex(tt.kind, tt.b) {
+				t.Errorf("BlindIndex(%q) != BlindIndex(%q)", tt.a, tt.b)
+			}
+		})
+	}
+
+	if enc.BlindIndex(FieldPhone, "11987654321") == enc.BlindIndex(FieldDocument, "11987654321") {
+		t.Errorf("blind indexes of different kinds must differ")
+	}
+	if enc.BlindIndex(FieldEmail, "") != "" {
+		t.Errorf("BlindIndex of empty value must be empty")
+	}
+
+	// Rotating the KEK must not change blind indexes
+	rotated := NewEncryptor(newTestProvider(t, "k2"))
+	if enc.BlindIndex(FieldPhone, "11987654321") != rotated.BlindIndex(FieldPhone, "11987654321") {
+		t.Errorf("blind index changed after KEK rotation")
+	}
+}
+
+type fakeKMS struct {
+	calls int
+}
+
+func (f *fakeKMS) Encrypt(ctx context.Context, keyName string, plaintext []byte) ([]byte, error) {
+	f.calls++
+	return append([]byte(keyName+"|"), plaintext...), nil
+}
+
+func (f *fakeKMS) Decrypt(ctx context.Context, keyName string, ciphertext []byte) ([]byte, error) {
+	f.calls++
+	prefix := []byte(keyName + "|")
+	if !bytes.HasPrefix(ciphertext, prefix) {
+		return nil, errors.New("wrong key")
+	}
+	return ciphertext[len(prefix):], nil
+}
+
+func TestKMSKeyProvider(t *testing.T) {
+	ctx := context.Background()
+	kms := &fakeKMS{}
+	provider, err := NewKMSKeyProvider(kms, "prod-1", map[string]string{
+		"prod-1": "projects/p/locations/l/keyRings/r/cryptoKeys/pii",
+	}, bytes.Repeat([]byte{7}, 32))
+	if err != nil {
+		t.Fatalf("NewKMSKeyProvider() error = %v", err)
+	}
+
+	enc := NewEncryptor(provider)
+	first, _ := enc.Encrypt(ctx, "11987654321")
+	second, _ := enc.Encrypt(ctx, "21987654321")
+	if kms.calls != 1 {
+		t.Errorf("KMS calls = %d, want 1 (DEK cached)", kms.calls)
+	}
+
+	fresh := NewEncryptor(provider)
+	for _, value := range []string{first, second} {
+		if _, err := fresh.Decrypt(ctx, value); err != nil {
+			t.Fatalf("Decrypt() error = %v", err)
+		}
+	}
+	if kms.calls != 2 {
+		t.Errorf("KMS calls = %d, want 2 (unwrapped DEK cached)", kms.calls)
+	}
+}
+
+func TestNewLocalKeyProvider_Validation(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, 32)
+	if _, err := NewLocalKeyProvider("missing", map[string][]byte{"k1": key}, key); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("missing current key error = %v", err)
+	}
+	if _, err := NewLocalKeyProvider("k1", map[string][]byte{"k1": key[:16]}, key); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("short key error = %v", err)
+	}
+	if _, err := NewLocalKeyProvider("a:b", map[string][]byte{"a:b": key}, key); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("key id with ':' error = %v", err)
+	}
+}
@@ -0,0 +1,264 @@
+// Package pii implements field-level envelope encryption for personal data
+// (CPF/CNPJ, phone, email) stored in Firestore.
+//
+// Each value is encrypted with AES-256-GCM using a data encryption key (DEK).
+// The DEK is wrapped by a key encryption key (KEK) held by a KeyProvider and
+// stored next to the ciphertext, so rotating the KEK only requires re-wrapping.
+// Deterministic blind indexes (HMAC-SHA256 over the normalized value) allow
+// equality lookups (GetByPhone, GetByDocument...) without storing plaintext.
+package pii
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+var (
+	// ErrUnknownKey is returned when a ciphertext references a key the provider does not hold
+	ErrUnknownKey = errors.New("unknown encryption key")
+	// ErrInvalidKey is returned when key material has the wrong size or format
+	ErrInvalidKey = errors.New("invalid encryption key")
+)
+
+// KeyProvider wraps and unwraps data encryption keys with a key encryption key
+type KeyProvider interface {
+	// CurrentKeyID returns the key used to wrap new DEKs
+	CurrentKeyID() string
+	// WrapKey encrypts a DEK with the given key
+	WrapKey(ctx context.Context, keyID string, dek []byte) ([]byte, error)
+	// UnwrapKey decrypts a DEK previously wrapped with the given key
+	UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error)
+	// BlindIndexKey returns the HMAC key used for deterministic blind indexes
+	BlindIndexKey() []byte
+}
+
+// ============================================================================
+// Local key file (development)
+// ============================================================================
+
+// LocalKeyFile is the JSON layout of a local key file
+//
+//	{
+//	  "current_key_id": "2026-01",
+//	  "keys": {"2025-06": "<base64 32 bytes>", "2026-01": "<base64 32 bytes>"},
+//	  "blind_index_key": "<base64 32 bytes>"
+//	}
+//
+// Old keys must stay in the file until `pii-rotate` re-wrapped every record.
+type LocalKeyFile struct {
+	CurrentKeyID  string            `json:"current_key_id"`
+	Keys          map[string]string `json:"keys"`
+	BlindIndexKey string            `json:"blind_index_key"`
+}
+
+// LocalKeyProvider keeps KEKs in memory, loaded from a local key file (dev only)
+type LocalKeyProvider struct {
+	currentKeyID  string
+	keys          map[string][]byte
+	blindIndexKey []byte
+}
+
+// NewLocalKeyProvider creates a provider from raw 32-byte keys
+func NewLocalKeyProvider(currentKeyID string, keys map[string][]byte, blindIndexKey []byte) (*LocalKeyProvider, error) {
+	if _, ok := keys[currentKeyID]; !ok {
+		return nil, fmt.Errorf("%w: current key %q not found", ErrInvalidKey, currentKeyID)
+	}
+	for keyID, key := range keys {
+		if keyID == "" || strings.Contains(keyID, ":") {
+			return nil, fmt.Errorf("%w: key id %q must be non-empty and must not contain ':'", ErrInvalidKey, keyID)
+		}
+		if len(key) != 32 {
+			return nil, fmt.Errorf("%w: key %q must have 32 bytes", ErrInvalidKey, keyID)
+		}
+	}
+	if len(blindIndexKey) < 32 {
+		return nil, fmt.Errorf("%w: blind index key must have at least 32 bytes", ErrInvalidKey)
+	}
+
+	return &LocalKeyProvider{
+		currentKeyID:  currentKeyID,
+		keys:          keys,
+		blindIndexKey: blindIndexKey,
+	}, nil
+}
+
+// LoadLocalKeyProvider reads a local key file (see LocalKeyFile)
+func LoadLocalKeyProvider(path string) (*LocalKeyProvider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+
+	var file LocalKeyFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse key file: %w", err)
+	}
+
+	keys := make(map[string][]byte, len(file.Keys))
+	for keyID, encoded := range file.Keys {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("%w: key %q is not valid base64", ErrInvalidKey, keyID)
+		}
+		keys[keyID] = key
+	}
+
+	blindIndexKey, err := base64.StdEncoding.DecodeString(file.BlindIndexKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: blind_index_key is not valid base64", ErrInvalidKey)
+	}
+
+	return NewLocalKeyProvider(file.CurrentKeyID, keys, blindIndexKey)
+}
+
+// CurrentKeyID returns the key used to wrap new DEKs
+func (p *LocalKeyProvider) CurrentKeyID() string {
+	return p.currentKeyID
+}
+
+// WrapKey encrypts a DEK with the given local key (AES-256-GCM)
+func (p *LocalKeyProvider) WrapKey(ctx context.Context, keyID string, dek []byte) ([]byte, error) {
+	key, ok := p.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKey, keyID)
+	}
+	return sealGCM(key, dek)
+}
+
+// UnwrapKey decrypts a DEK wrapped with the given local key
+func (p *LocalKeyProvider) UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	key, ok := p.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKey, keyID)
+	}
+	return openGCM(key, wrapped)
+}
+
+// BlindIndexKey returns the HMAC key used for blind indexes
+func (p *LocalKeyProvider) BlindIndexKey() []byte {
+	return p.blindIndexKey
+}
+
+// ============================================================================
+// KMS (production)
+// ============================================================================
+
+// KMSClient is the subset of a cloud KMS API used to wrap DEKs
+// (e.g. Cloud KMS Encrypt/Decrypt on a symmetric key)
+type KMSClient interface {
+	Encrypt(ctx context.Context, keyName string, plaintext []byte) ([]byte, error)
+	Decrypt(ctx context.Context, keyName string, ciphertext []byte) ([]byte, error)
+}
+
+// KMSKeyProvider wraps DEKs through a KMS; KEKs never leave the KMS
+// Key IDs are short aliases mapped to KMS key names, so ciphertexts stay compact
+type KMSKeyProvider struct {
+	client        KMSClient
+	currentKeyID  string
+	keyNames      map[string]string // alias -> KMS key resource name
+	blindIndexKey []byte
+}
+
+// NewKMSKeyProvider creates a KMS-backed provider
+// blindIndexKey should come from a secret manager (it is not wrapped by the KMS)
+func NewKMSKeyProvider(client KMSClient, currentKeyID string, keyNames map[string]string, blindIndexKey []byte) (*KMSKeyProvider, error) {
+	if client == nil {
+		return nil, fmt.Errorf("%w: KMS client is required", ErrInvalidKey)
+	}
+	if _, ok := keyNames[currentKeyID]; !ok {
+		return nil, fmt.Errorf("%w: current key %q not found", ErrInvalidKey, currentKeyID)
+	}
+	for keyID := range keyNames {
+		if keyID == "" || strings.Contains(keyID, ":") {
+			return nil, fmt.Errorf("%w: key id %q must be non-empty and must not contain ':'", ErrInvalidKey, keyID)
+		}
+	}
+	if len(blindIndexKey) < 32 {
+		return nil, fmt.Errorf("%w: blind index key must have at least 32 bytes", ErrInvalidKey)
+	}
+
+	return &KMSKeyProvider{
+		client:        client,
+		currentKeyID:  currentKeyID,
+		keyNames:      keyNames,
+		blindIndexKey: blindIndexKey,
+	}, nil
+}
+
+// CurrentKeyID returns the key used to wrap new DEKs
+func (p *KMSKeyProvider) CurrentKeyID() string {
+	return p.currentKeyID
+}
+
+// WrapKey encrypts a DEK with the KMS key mapped to keyID
+func (p *KMSKeyProvider) WrapKey(ctx context.Context, keyID string, dek []byte) ([]byte, error) {
+	keyName, ok := p.keyNames[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKey, keyID)
+	}
+	return p.client.Encrypt(ctx, keyName, dek)
+}
+
+// UnwrapKey decrypts a DEK with the KMS key mapped to keyID
+func (p *KMSKeyProvider) UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	keyName, ok := p.keyNames[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKey, keyID)
+	}
+	return p.client.Decrypt(ctx, keyName, wrapped)
+}
+
+// BlindIndexKey returns the HMAC key used for blind indexes
+func (p *KMSKeyProvider) BlindIndexKey() []byte {
+	return p.blindIndexKey
+}
+
+// ============================================================================
+// AES-GCM helpers
+// ============================================================================
+
+// sealGCM encrypts plaintext with AES-GCM, returning nonce || ciphertext
+func sealGCM(key, plaintext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidKey, err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// openGCM decrypts nonce || ciphertext produced by sealGCM
+func openGCM(key, sealed []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidKey, err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
@@ -2,16 +2,45 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math/rand"
+	"sync/atomic"
 	"time"
 
 	"cloud.google.com/go/firestore"
+	"cloud.google.com/go/firestore/apiv1/firestorepb"
 	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/altatech/ecosistema-imob/backend/internal/models"
 )
 
+const (
+	// activityLogAppendRounds is how many times Create runs the chain transaction when it is aborted
+	// by contention (each run already retries aborted commits inside the Firestore client)
+	activityLogAppendRounds = 4
+
+	// activityLogAppendBaseBackoff is the wait before the first retry round (doubles every round)
+	activityLogAppendBaseBackoff = 100 * time.Millisecond
+)
+
+// ErrAuditChainContention is returned when an entry could not be appended because the tenant chain
+// head stayed contended through every retry
+var ErrAuditChainContention = errors.New("audit chain head contended")
+
+// activityLogAppendFailures counts the entries Create could not append since the process started
+var activityLogAppendFailures atomic.Int64
+
+// ActivityLogAppendFailures returns how many activity log entries were lost (GET /metrics)
+func ActivityLogAppendFailures() int64 {
+	return activityLogAppendFailures.Load()
+}
+
 // ActivityLogRepository handles Firestore operations for activity logs
+// Logs are append-only (no Update/Delete): the only change allowed after creation is the
+// PII pruning of the retention policy (RemoveMetadataKeys), which the hash chain tolerates
 type ActivityLogRepository struct {
 	*BaseRepository
 }
@@ -37,7 +66,12 @@ type ActivityLogFilters struct {
 	EndDate   *time.Time
 }
 
-// Create creates a new activity log entry
+// Create appends an entry to the tenant hash chain
+// Activity logs are APPEND-ONLY: the entry is linked to the chain head in a transaction
+// (sequence, prev_hash, chain_hash) and never overwritten - an existing ID returns ErrAlreadyExists
+// Note: the head document serializes the writes of a tenant (one chain per tenant). Transactions
+// aborted by contention on the head are retried with backoff (activityLogAppendRounds rounds of the
+// client's own attempts); when they are exhausted the error wraps ErrAuditChainContention
 func (r *ActivityLogRepository) Create(ctx context.Context, log *models.ActivityLog) error {
 	if log.TenantID == "" {
 		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
@@ -55,17 +89,91 @@ func (r *ActivityLogRepository) Create(ctx context.Context, log *models.Activity
 		log.Timestamp = time.Now()
 	}
 
-	collectionPath := r.getActivityLogsCollection(log.TenantID)
+	logRef := r.Client().Collection(r.getActivityLogsCollection(log.TenantID)).Doc(log.ID)
+	headRef := r.getChainHeadRef(log.TenantID)
+
+	var entry models.ActivityLog
+	appendEntry := func(ctx context.Context, tx *firestore.Transaction) error {
+		var head models.AuditChainHead
+		doc, err := tx.Get(headRef)
+		if err != nil && status.Code(err) != codes.NotFound {
+			return fmt.Errorf("failed to get audit chain head: %w", err)
+		}
+		if err == nil {
+			if err := doc.DataTo(&head); err != nil {
+				return fmt.Errorf("failed to decode audit chain head: %w", err)
+			}
+		}
+
+		prevHash := head.LastHash
+		if prevHash == "" {
+			prevHash = models.AuditGenesisHash
+		}
+
+		// Recomputed on every attempt (transactions may be retried)
+		entry = *log
+		entry.PrepareChainEntry(head.Sequence+1, prevHash)
 
-	// Use Set instead of Create to allow idempotent operations
-	// This is important for event deduplication based on event_id
-	if err := r.SetDocument(ctx, collectionPath, log.ID, log); err != nil {
+		if err := tx.Create(logRef, &entry); err != nil {
+			return err
+		}
+		return tx.Set(headRef, models.AuditChainHead{
+			Sequence:  entry.Sequence,
+			LastHash:  entry.ChainHash,
+			LastLogID: entry.ID,
+			UpdatedAt: time.Now(),
+		})
+	}
+
+	var err error
+	for round := 0; round < activityLogAppendRounds; round++ {
+		if round > 0 {
+			if err = sleepContext(ctx, activityLogAppendBackoff(round)); err != nil {
+				break
+			}
+		}
+
+		err = r.Client().RunTransaction(ctx, appendEntry)
+		if status.Code(err) != codes.Aborted {
+			break
+		}
+	}
+	if err != nil {
+		if status.Code(err) == codes.AlreadyExists {
+			return ErrAlreadyExists
+		}
+
+		activityLogAppendFailures.Add(1)
+		if status.Code(err) == codes.Aborted {
+			return fmt.Errorf("failed to create activity log: %w: %v", ErrAuditChainContention, err)
+		}
 		return fmt.Errorf("failed to create activity log: %w", err)
 	}
 
+	*log = entry
 	return nil
 }
 
+// activityLogAppendBackoff returns the wait before a retry round of Create (exponential with jitter,
+// so the writers of a busy tenant do not retry in lockstep)
+func activityLogAppendBackoff(round int) time.Duration {
+	base := activityLogAppendBaseBackoff << (round - 1)
+	return base/2 + time.Duration(rand.Int63n(int64(base)))
+}
+
+// sleepContext waits for d or until the context is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
 // Get retrieves an activity log by ID
 func (r *ActivityLogRepository) Get(ctx context.Context, tenantID, id string) (*models.ActivityLog, error) {
 	if tenantID == "" {
@@ -352,6 +460,36 @@ func (r *ActivityLogRepository) ListPropertyLogs(ctx context.Context, tenantID,
 	return logs, nil
 }
 
+// CountPropertyEvents counts the events of a type recorded for a property (since the given time, when set)
+// Runs a count aggregation, so the logs are not read (requires the metadata.property_id + event_type + timestamp index)
+func (r *ActivityLogRepository) CountPropertyEvents(ctx context.Context, tenantID, propertyID, eventType string, since *time.Time) (int, error) {
+	if tenantID == "" {
+		return 0, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if propertyID == "" || eventType == "" {
+		return 0, fmt.Errorf("%w: property_id and event_type are required", ErrInvalidInput)
+	}
+
+	query := r.Client().Collection(r.getActivityLogsCollection(tenantID)).
+		Where("metadata.property_id", "==", propertyID).
+		Where("event_type", "==", eventType)
+	if since != nil {
+		query = query.Where("timestamp", ">", *since)
+	}
+
+	result, err := query.NewAggregationQuery().WithCount("count").Get(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count property events: %w", err)
+	}
+
+	count, ok := result["count"].(*firestorepb.Value)
+	if !ok {
+		return 0, fmt.Errorf("failed to count property events: missing count")
+	}
+
+	return int(count.GetIntegerValue()), nil
+}
+
 // ListLeadLogs retrieves activity logs for a specific lead
 func (r *ActivityLogRepository) ListLeadLogs(ctx context.Context, tenantID, leadID string, opts PaginationOptions) ([]*models.ActivityLog, error) {
 	if tenantID == "" {
@@ -397,15 +535,247 @@ func (r *ActivityLogRepository) ListLeadLogs(ctx context.Context, tenantID, lead
 	return logs, nil
 }
 
-// Delete deletes an activity log (should be rare - logs are typically immutable)
-func (r *ActivityLogRepository) Delete(ctx context.Context, tenantID, id string) error {
+// ListOwnerLogs retrieves activity logs for a specific owner
+func (r *ActivityLogRepository) ListOwnerLogs(ctx context.Context, tenantID, ownerID string, opts PaginationOptions) ([]*models.ActivityLog, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if ownerID == "" {
+		return nil, fmt.Errorf("%w: owner_id is required", ErrInvalidInput)
+	}
+
+	if opts.Limit == 0 {
+		opts = DefaultPaginationOptions()
+	}
+
+	// Query logs where metadata.owner_id == ownerID
+	collectionPath := r.getActivityLogsCollection(tenantID)
+	query := r.Client().Collection(collectionPath).
+		Where("metadata.owner_id", "==", ownerID)
+	query = r.ApplyPagination(query, opts)
+
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	logs := make([]*models.ActivityLog, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate owner logs: %w", err)
+		}
+
+		var log models.ActivityLog
+		if err := doc.DataTo(&log); err != nil {
+			return nil, fmt.Errorf("failed to decode activity log: %w", err)
+		}
+
+		log.ID = doc.Ref.ID
+		logs = append(logs, &log)
+	}
+
+	return logs, nil
+}
+
+// ListDataSubjectRequestLogs retrieves activity logs of a data subject request (metadata.request_id)
+func (r *ActivityLogRepository) ListDataSubjectRequestLogs(ctx context.Context, tenantID, requestID string, opts PaginationOptions) ([]*models.ActivityLog, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if requestID == "" {
+		return nil, fmt.Errorf("%w: request_id is required", ErrInvalidInput)
+	}
+
+	if opts.Limit == 0 {
+		opts = DefaultPaginationOptions()
+	}
+
+	collectionPath := r.getActivityLogsCollection(tenantID)
+	query := r.Client().Collection(collectionPath).
+		Where("metadata.request_id", "==", requestID)
+	query = r.ApplyPagination(query, opts)
+
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	logs := make([]*models.ActivityLog, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate data subject request logs: %w", err)
+		}
+
+		var log models.ActivityLog
+		if err := doc.DataTo(&log); err != nil {
+			return nil, fmt.Errorf("failed to decode activity log: %w", err)
+		}
+
+		log.ID = doc.Ref.ID
+		logs = append(logs, &log)
+	}
+
+	return logs, nil
+}
+
+// ListOlderThan retrieves logs with after < timestamp < before, oldest first (retention sweeper)
+// A zero after starts from the oldest log
+func (r *ActivityLogRepository) ListOlderThan(ctx context.Context, tenantID string, after, before time.Time, limit int) ([]*models.ActivityLog, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	collectionPath := r.getActivityLogsCollection(tenantID)
+	query := r.Client().Collection(collectionPath).
+		Where("timestamp", "<", before)
+	if !after.IsZero() {
+		query = query.Where("timestamp", ">", after)
+	}
+	query = query.OrderBy("timestamp", firestore.Asc).Limit(limit)
+
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	logs := make([]*models.ActivityLog, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate activity logs: %w", err)
+		}
+
+		var log models.ActivityLog
+		if err := doc.DataTo(&log); err != nil {
+			return nil, fmt.Errorf("failed to decode activity log: %w", err)
+		}
+
+		log.ID = doc.Ref.ID
+		logs = append(logs, &log)
+	}
+
+	return logs, nil
+}
+
+// RemoveMetadataKeys deletes metadata entries of an activity log (PII pruning by the retention policy and
+// data subject erasure)
+func (r *ActivityLogRepository) RemoveMetadataKeys(ctx context.Context, tenantID, id string, keys []string) error {
 	if tenantID == "" {
 		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
 	}
+	if id == "" {
+		return fmt.Errorf("%w: activity log ID is required", ErrInvalidInput)
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	for _, key := range keys {
+		if !isActivityLogPIIKey(key) {
+			return fmt.Errorf("%w: only PII metadata keys can be removed (%s)", ErrInvalidInput, key)
+		}
+	}
+
+	// pii_pruned_at tells the chain verification that PII was removed (see models.AuditChainVerifier)
+	updates := make([]firestore.Update, 0, len(keys)+1)
+	for _, key := range keys {
+		updates = append(updates, firestore.Update{Path: "metadata." + key, Value: firestore.Delete})
+	}
+	updates = append(updates, firestore.Update{Path: "pii_pruned_at", Value: time.Now()})
 
 	collectionPath := r.getActivityLogsCollection(tenantID)
-	if err := r.DeleteDocument(ctx, collectionPath, id); err != nil {
-		return fmt.Errorf("failed to delete activity log: %w", err)
+	if err := r.UpdateDocument(ctx, collectionPath, id, updates); err != nil {
+		return fmt.Errorf("failed to prune activity log metadata: %w", err)
 	}
 	return nil
 }
+
+// GetChainHead returns the head of the tenant hash chain (zero head when the chain is empty)
+func (r *ActivityLogRepository) GetChainHead(ctx context.Context, tenantID string) (*models.AuditChainHead, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	doc, err := r.getChainHeadRef(tenantID).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return &models.AuditChainHead{}, nil
+		}
+		return nil, fmt.Errorf("failed to get audit chain head: %w", err)
+	}
+
+	var head models.AuditChainHead
+	if err := doc.DataTo(&head); err != nil {
+		return nil, fmt.Errorf("failed to decode audit chain head: %w", err)
+	}
+	return &head, nil
+}
+
+// GetBySequence retrieves the chain entry with a sequence number
+func (r *ActivityLogRepository) GetBySequence(ctx context.Context, tenantID string, sequence int64) (*models.ActivityLog, error) {
+	logs, err := r.ListChain(ctx, tenantID, sequence-1, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(logs) == 0 || logs[0].Sequence != sequence {
+		return nil, ErrNotFound
+	}
+	return logs[0], nil
+}
+
+// ListChain retrieves chain entries with sequence > afterSequence, in chain order
+// Entries written before the hash chain (no sequence) are not returned
+func (r *ActivityLogRepository) ListChain(ctx context.Context, tenantID string, afterSequence int64, limit int) ([]*models.ActivityLog, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	collectionPath := r.getActivityLogsCollection(tenantID)
+	query := r.Client().Collection(collectionPath).
+		Where("sequence", ">", afterSequence).
+		OrderBy("sequence", firestore.Asc).
+		Limit(limit)
+
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	logs := make([]*models.ActivityLog, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate activity log chain: %w", err)
+		}
+
+		var log models.ActivityLog
+		if err := doc.DataTo(&log); err != nil {
+			return nil, fmt.Errorf("failed to decode activity log: %w", err)
+		}
+
+		log.ID = doc.Ref.ID
+		logs = append(logs, &log)
+	}
+
+	return logs, nil
+}
+
+// getChainHeadRef returns the head document of the tenant hash chain
+func (r *ActivityLogRepository) getChainHeadRef(tenantID string) *firestore.DocumentRef {
+	return r.Client().Collection(fmt.Sprintf("tenants/%s/audit_chain", tenantID)).Doc("head")
+}
+
+// isActivityLogPIIKey checks if a metadata key is listed in models.ActivityLogPIIMetadataKeys
+func isActivityLogPIIKey(key string) bool {
+	for _, piiKey := range models.ActivityLogPIIMetadataKeys {
+		if key == piiKey {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,166 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+)
+
+// APIKeyRepository handles Firestore operations for partner API keys
+type APIKeyRepository struct {
+	*BaseRepository
+}
+
+// NewAPIKeyRepository creates a new API key repository
+func NewAPIKeyRepository(client *firestore.Client) *APIKeyRepository {
+	return &APIKeyRepository{
+		BaseRepository: NewBaseRepository(client),
+	}
+}
+
+// getAPIKeysCollection returns the collection path for API keys within a tenant
+func (r *APIKeyRepository) getAPIKeysCollection(tenantID string) string {
+	return fmt.Sprintf("tenants/%s/api_keys", tenantID)
+}
+
+// Create creates a new API key
+func (r *APIKeyRepository) Create(ctx context.Context, key *models.APIKey) error {
+	if key.TenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if key.Prefix == "" || key.KeyHash == "" {
+		return fmt.Errorf("%w: prefix and key_hash are required", ErrInvalidInput)
+	}
+
+	collectionPath := r.getAPIKeysCollection(key.TenantID)
+	if key.ID == "" {
+		key.ID = r.GenerateID(collectionPath)
+	}
+
+	now := time.Now()
+	key.CreatedAt = now
+	key.UpdatedAt = now
+
+	if err := r.CreateDocument(ctx, collectionPath, key.ID, key); err != nil {
+		return fmt.Errorf("failed to create api key: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves an API key by ID
+func (r *APIKeyRepository) Get(ctx context.Context, tenantID, id string) (*models.APIKey, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	var key models.APIKey
+	if err := r.GetDocument(ctx, r.getAPIKeysCollection(tenantID), id, &key); err != nil {
+		return nil, err
+	}
+
+	key.ID = id
+	return &key, nil
+}
+
+// GetByPrefix retrieves an API key by its identifying prefix
+func (r *APIKeyRepository) GetByPrefix(ctx context.Context, tenantID, prefix string) (*models.APIKey, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	query := r.Client().Collection(r.getAPIKeysCollection(tenantID)).
+		Where("prefix", "==", prefix).
+		Limit(1)
+
+	keys, err := r.list(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, ErrNotFound
+	}
+
+	return keys[0], nil
+}
+
+// Update updates an API key
+func (r *APIKeyRepository) Update(ctx context.Context, tenantID, id string, updates []firestore.Update) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	updates = append(updates, firestore.Update{Path: "updated_at", Value: time.Now()})
+
+	if err := r.UpdateDocument(ctx, r.getAPIKeysCollection(tenantID), id, updates); err != nil {
+		if err == ErrNotFound {
+			return err
+		}
+		return fmt.Errorf("failed to update api key: %w", err)
+	}
+
+	return nil
+}
+
+// RecordUsage stores the last use of an API key (updated_at is left untouched)
+func (r *APIKeyRepository) RecordUsage(ctx context.Context, tenantID, id string, usedAt time.Time, ip string) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	if err := r.UpdateDocument(ctx, r.getAPIKeysCollection(tenantID), id, []firestore.Update{
+		{Path: "last_used_at", Value: usedAt},
+		{Path: "last_used_ip", Value: ip},
+	}); err != nil {
+		if err == ErrNotFound {
+			return err
+		}
+		return fmt.Errorf("failed to record api key usage: %w", err)
+	}
+
+	return nil
+}
+
+// List lists the API keys of a tenant (newest first)
+func (r *APIKeyRepository) List(ctx context.Context, tenantID string) ([]*models.APIKey, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	query := r.Client().Collection(r.getAPIKeysCollection(tenantID)).
+		OrderBy("created_at", firestore.Desc)
+
+	return r.list(ctx, query)
+}
+
+// list runs a query and decodes the keys
+func (r *APIKeyRepository) list(ctx context.Context, query firestore.Query) ([]*models.APIKey, error) {
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	keys := make([]*models.APIKey, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate api keys: %w", err)
+		}
+
+		var key models.APIKey
+		if err := doc.DataTo(&key); err != nil {
+			return nil, fmt.Errorf("failed to decode api key: %w", err)
+		}
+
+		key.ID = doc.Ref.ID
+		keys = append(keys, &key)
+	}
+
+	return keys, nil
+}
@@ -0,0 +1,124 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+)
+
+// AuditCheckpointRepository handles Firestore operations for signed audit chain checkpoints
+// Checkpoints are immutable (no update/delete)
+type AuditCheckpointRepository struct {
+	*BaseRepository
+}
+
+// NewAuditCheckpointRepository creates a new audit checkpoint repository
+func NewAuditCheckpointRepository(client *firestore.Client) *AuditCheckpointRepository {
+	return &AuditCheckpointRepository{
+		BaseRepository: NewBaseRepository(client),
+	}
+}
+
+// getCheckpointsCollection returns the collection path for audit checkpoints within a tenant
+func (r *AuditCheckpointRepository) getCheckpointsCollection(tenantID string) string {
+	return fmt.Sprintf("tenants/%s/audit_checkpoints", tenantID)
+}
+
+// Create records a checkpoint (ID = zero-padded sequence; ErrAlreadyExists if the sequence was already checkpointed)
+func (r *AuditCheckpointRepository) Create(ctx context.Context, checkpoint *models.AuditCheckpoint) error {
+	if checkpoint.TenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if checkpoint.Sequence <= 0 {
+		return fmt.Errorf("%w: sequence is required", ErrInvalidInput)
+	}
+
+	checkpoint.ID = fmt.Sprintf("%012d", checkpoint.Sequence)
+
+	if err := r.CreateDocument(ctx, r.getCheckpointsCollection(checkpoint.TenantID), checkpoint.ID, checkpoint); err != nil {
+		if err == ErrAlreadyExists {
+			return err
+		}
+		return fmt.Errorf("failed to create audit checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+// GetLatest retrieves the most recent checkpoint (nil when none)
+func (r *AuditCheckpointRepository) GetLatest(ctx context.Context, tenantID string) (*models.AuditCheckpoint, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	query := r.Client().Collection(r.getCheckpointsCollection(tenantID)).
+		OrderBy("sequence", firestore.Desc).
+		Limit(1)
+
+	checkpoints, err := r.list(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(checkpoints) == 0 {
+		return nil, nil
+	}
+	return checkpoints[0], nil
+}
+
+// List retrieves the most recent checkpoints (newest first)
+func (r *AuditCheckpointRepository) List(ctx context.Context, tenantID string, limit int) ([]*models.AuditCheckpoint, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	query := r.Client().Collection(r.getCheckpointsCollection(tenantID)).
+		OrderBy("sequence", firestore.Desc).
+		Limit(limit)
+
+	return r.list(ctx, query)
+}
+
+// ListRange retrieves the checkpoints with fromSequence <= sequence <= toSequence (oldest first)
+func (r *AuditCheckpointRepository) ListRange(ctx context.Context, tenantID string, fromSequence, toSequence int64) ([]*models.AuditCheckpoint, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	query := r.Client().Collection(r.getCheckpointsCollection(tenantID)).
+		Where("sequence", ">=", fromSequence).
+		Where("sequence", "<=", toSequence).
+		OrderBy("sequence", firestore.Asc)
+
+	return r.list(ctx, query)
+}
+
+// list runs a query and decodes the checkpoints
+func (r *AuditCheckpointRepository) list(ctx context.Context, query firestore.Query) ([]*models.AuditCheckpoint, error) {
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	checkpoints := make([]*models.AuditCheckpoint, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate audit checkpoints: %w", err)
+		}
+
+		var checkpoint models.AuditCheckpoint
+		if err := doc.DataTo(&checkpoint); err != nil {
+			return nil, fmt.Errorf("failed to decode audit checkpoint: %w", err)
+		}
+
+		checkpoint.ID = doc.Ref.ID
+		checkpoints = append(checkpoints, &checkpoint)
+	}
+
+	return checkpoints, nil
+}
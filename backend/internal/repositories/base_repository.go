@@ -39,6 +39,36 @@ func (r *BaseRepository) Client() *firestore.Client {
 	return r.client
 }
 
+// TxWrite is a write applied inside a Firestore transaction (see OutboxRepository.Commit)
+// Firestore transactions require all reads before writes: validate before, only write inside
+type TxWrite func(ctx context.Context, tx *firestore.Transaction) error
+
+// RunTransaction runs writes in a single Firestore transaction
+// gRPC NotFound/AlreadyExists (tx.Update/tx.Create) are returned as ErrNotFound/ErrAlreadyExists
+func (r *BaseRepository) RunTransaction(ctx context.Context, writes ...TxWrite) error {
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		for _, write := range writes {
+			if write == nil {
+				continue
+			}
+			if err := write(ctx, tx); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	switch {
+	case err == nil:
+		return nil
+	case status.Code(err) == codes.NotFound:
+		return ErrNotFound
+	case status.Code(err) == codes.AlreadyExists:
+		return ErrAlreadyExists
+	default:
+		return err
+	}
+}
+
 // PaginationOptions contains options for pagination
 type PaginationOptions struct {
 	Limit      int
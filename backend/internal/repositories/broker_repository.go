@@ -255,6 +255,25 @@ func (r *BrokerRepository) ListActive(ctx context.Context, tenantID string, opts
 	return brokers, nil
 }
 
+// CountActive returns the number of active brokers for a tenant
+func (r *BrokerRepository) CountActive(ctx context.Context, tenantID string) (int, error) {
+	if tenantID == "" {
+		return 0, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	collectionPath := r.getBrokersCollection(tenantID)
+	query := r.Client().Collection(collectionPath).
+		Where("is_active", "==", true)
+
+	// Use Select() to only fetch document IDs for counting (more efficient)
+	docs, err := query.Select().Documents(ctx).GetAll()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count active brokers: %w", err)
+	}
+
+	return len(docs), nil
+}
+
 // ListByRole retrieves brokers by role for a tenant
 func (r *BrokerRepository) ListByRole(ctx context.Context, tenantID, role string, opts PaginationOptions) ([]*models.Broker, error) {
 	if tenantID == "" {
@@ -0,0 +1,180 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+)
+
+// ConsentRepository handles Firestore operations for the consent ledger and versioned consent texts
+// The ledger is append-only: there are no Update/Delete methods for consent records or texts
+type ConsentRepository struct {
+	*BaseRepository
+}
+
+// NewConsentRepository creates a new consent repository
+func NewConsentRepository(client *firestore.Client) *ConsentRepository {
+	return &ConsentRepository{
+		BaseRepository: NewBaseRepository(client),
+	}
+}
+
+// getRecordsCollection returns the collection path for consent records within a tenant
+func (r *ConsentRepository) getRecordsCollection(tenantID string) string {
+	return fmt.Sprintf("tenants/%s/consent_records", tenantID)
+}
+
+// getTextsCollection returns the collection path for consent texts within a tenant
+func (r *ConsentRepository) getTextsCollection(tenantID string) string {
+	return fmt.Sprintf("tenants/%s/consent_texts", tenantID)
+}
+
+// CreateRecord appends a consent record to the ledger
+func (r *ConsentRepository) CreateRecord(ctx context.Context, record *models.ConsentRecord) error {
+	if record.TenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if record.SubjectID == "" {
+		return fmt.Errorf("%w: subject_id is required", ErrInvalidInput)
+	}
+
+	if record.ID == "" {
+		record.ID = r.GenerateID(r.getRecordsCollection(record.TenantID))
+	}
+	if record.RecordedAt.IsZero() {
+		record.RecordedAt = time.Now()
+	}
+
+	if err := r.CreateDocument(ctx, r.getRecordsCollection(record.TenantID), record.ID, record); err != nil {
+		return fmt.Errorf("failed to create consent record: %w", err)
+	}
+
+	return nil
+}
+
+// ListRecordsBySubject retrieves the ledger of a subject (oldest first)
+func (r *ConsentRepository) ListRecordsBySubject(ctx context.Context, tenantID string, subjectType models.ConsentSubjectType, subjectID string) ([]*models.ConsentRecord, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if subjectID == "" {
+		return nil, fmt.Errorf("%w: subject_id is required", ErrInvalidInput)
+	}
+
+	query := r.Client().Collection(r.getRecordsCollection(tenantID)).
+		Where("subject_type", "==", string(subjectType)).
+		Where("subject_id", "==", subjectID).
+		OrderBy("recorded_at", firestore.Asc)
+
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	records := make([]*models.ConsentRecord, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate consent records: %w", err)
+		}
+
+		var record models.ConsentRecord
+		if err := doc.DataTo(&record); err != nil {
+			return nil, fmt.Errorf("failed to decode consent record: %w", err)
+		}
+
+		record.ID = doc.Ref.ID
+		records = append(records, &record)
+	}
+
+	return records, nil
+}
+
+// CreateText publishes a consent text version
+func (r *ConsentRepository) CreateText(ctx context.Context, text *models.ConsentTextVersion) error {
+	if text.TenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if text.Text == "" {
+		return fmt.Errorf("%w: text is required", ErrInvalidInput)
+	}
+
+	// Deterministic ID: one document per purpose/version (concurrent publications collide)
+	text.ID = fmt.Sprintf("%s_v%d", text.Purpose, text.Version)
+	text.CreatedAt = time.Now()
+
+	if err := r.CreateDocument(ctx, r.getTextsCollection(text.TenantID), text.ID, text); err != nil {
+		return fmt.Errorf("failed to create consent text: %w", err)
+	}
+
+	return nil
+}
+
+// GetLatestText retrieves the current (highest version) text of a purpose
+func (r *ConsentRepository) GetLatestText(ctx context.Context, tenantID string, purpose models.ConsentPurpose) (*models.ConsentTextVersion, error) {
+	texts, err := r.ListTexts(ctx, tenantID, purpose)
+	if err != nil {
+		return nil, err
+	}
+	if len(texts) == 0 {
+		return nil, ErrNotFound
+	}
+	return texts[0], nil
+}
+
+// GetText retrieves a specific version of a purpose text
+func (r *ConsentRepository) GetText(ctx context.Context, tenantID string, purpose models.ConsentPurpose, version int) (*models.ConsentTextVersion, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	id := fmt.Sprintf("%s_v%d", purpose, version)
+	var text models.ConsentTextVersion
+	if err := r.GetDocument(ctx, r.getTextsCollection(tenantID), id, &text); err != nil {
+		return nil, err
+	}
+
+	text.ID = id
+	return &text, nil
+}
+
+// ListTexts retrieves the text versions of a purpose (newest first)
+func (r *ConsentRepository) ListTexts(ctx context.Context, tenantID string, purpose models.ConsentPurpose) ([]*models.ConsentTextVersion, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	query := r.Client().Collection(r.getTextsCollection(tenantID)).
+		Where("purpose", "==", string(purpose)).
+		OrderBy("version", firestore.Desc)
+
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	texts := make([]*models.ConsentTextVersion, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate consent texts: %w", err)
+		}
+
+		var text models.ConsentTextVersion
+		if err := doc.DataTo(&text); err != nil {
+			return nil, fmt.Errorf("failed to decode consent text: %w", err)
+		}
+
+		text.ID = doc.Ref.ID
+		texts = append(texts, &text)
+	}
+
+	return texts, nil
+}
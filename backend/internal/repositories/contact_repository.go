@@ -0,0 +1,494 @@
+package repositories
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/pii"
+)
+
+// ContactKey is a phone or an e-mail identifying a contact
+// Collection: /tenants/{tenantId}/contact_keys/{keyId} (keyId = blind index of the value -> contact_id)
+// A key document per phone/e-mail serializes concurrent leads of the same person (see AssignLead)
+type ContactKey struct {
+	Kind  pii.FieldKind
+	Value string
+}
+
+// contactKeyDoc is the stored contact key
+type contactKeyDoc struct {
+	ContactID string    `firestore:"contact_id"`
+	UpdatedAt time.Time `firestore:"updated_at"`
+}
+
+// ContactRepository handles Firestore operations for contacts (the person behind the leads)
+type ContactRepository struct {
+	*BaseRepository
+	encryptor *pii.Encryptor // optional: field-level encryption of PII
+}
+
+// NewContactRepository creates a new contact repository
+func NewContactRepository(client *firestore.Client) *ContactRepository {
+	return &ContactRepository{
+		BaseRepository: NewBaseRepository(client),
+	}
+}
+
+// SetEncryptor enables field-level encryption of email and phone
+// Deduplication lookups then use blind indexes (run cmd/pii-rotate to backfill existing contacts)
+func (r *ContactRepository) SetEncryptor(encryptor *pii.Encryptor) {
+	r.encryptor = encryptor
+}
+
+// getContactsCollection returns the collection path for contacts within a tenant
+func (r *ContactRepository) getContactsCollection(tenantID string) string {
+	return fmt.Sprintf("tenants/%s/contacts", tenantID)
+}
+
+// getContactKeysCollection returns the collection path for contact keys within a tenant
+func (r *ContactRepository) getContactKeysCollection(tenantID string) string {
+	return fmt.Sprintf("tenants/%s/contact_keys", tenantID)
+}
+
+// contactKeyID returns the document ID of a contact key (the blind index, or a plain hash without an encryptor)
+func (r *ContactRepository) contactKeyID(key ContactKey) string {
+	if r.encryptor != nil {
+		return r.encryptor.BlindIndex(key.Kind, key.Value)
+	}
+	normalized := pii.Normalize(key.Kind, key.Value)
+	if normalized == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(string(key.Kind) + "\x00" + normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+// NewID returns a new contact ID (the contact is written by UpdateContact once its first lead exists)
+func (r *ContactRepository) NewID(tenantID string) string {
+	return r.GenerateID(r.getContactsCollection(tenantID))
+}
+
+// Get retrieves a contact by ID
+func (r *ContactRepository) Get(ctx context.Context, tenantID, id string) (*models.Contact, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if id == "" {
+		return nil, fmt.Errorf("%w: contact ID is required", ErrInvalidInput)
+	}
+
+	var contact models.Contact
+	if err := r.GetDocument(ctx, r.getContactsCollection(tenantID), id, &contact); err != nil {
+		return nil, err
+	}
+	if err := DecryptContact(ctx, r.encryptor, &contact); err != nil {
+		return nil, err
+	}
+
+	contact.ID = id
+	return &contact, nil
+}
+
+// GetByEmail retrieves the contact with the given (normalized) email
+func (r *ContactRepository) GetByEmail(ctx context.Context, tenantID, email string) (*models.Contact, error) {
+	if email == "" {
+		return nil, fmt.Errorf("%w: email is required", ErrInvalidInput)
+	}
+	return r.getBy(ctx, tenantID, "email", pii.FieldEmail, email)
+}
+
+// GetByPhone retrieves the contact with the given (normalized) phone
+func (r *ContactRepository) GetByPhone(ctx context.Context, tenantID, phone string) (*models.Contact, error) {
+	if phone == "" {
+		return nil, fmt.Errorf("%w: phone is required", ErrInvalidInput)
+	}
+	return r.getBy(ctx, tenantID, "phone", pii.FieldPhone, phone)
+}
+
+// getBy retrieves the oldest contact with a PII field value
+func (r *ContactRepository) getBy(ctx context.Context, tenantID, field string, kind pii.FieldKind, value string) (*models.Contact, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	lookupField, lookupValue := piiLookup(r.encryptor, field, kind, value)
+	iter := r.Client().Collection(r.getContactsCollection(tenantID)).
+		Where(lookupField, "==", lookupValue).
+		Limit(1).
+		Documents(ctx)
+	defer iter.Stop()
+
+	doc, err := iter.Next()
+	if err == iterator.Done {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query contact by %s: %w", field, err)
+	}
+
+	var contact models.Contact
+	if err := doc.DataTo(&contact); err != nil {
+		return nil, fmt.Errorf("failed to decode contact: %w", err)
+	}
+	if err := DecryptContact(ctx, r.encryptor, &contact); err != nil {
+		return nil, err
+	}
+
+	contact.ID = doc.Ref.ID
+	return &contact, nil
+}
+
+// UpdateContact atomically reads, changes and writes a contact
+// update receives the stored contact (a new one with exists = false when missing) and may return an
+// error to abort without writing
+func (r *ContactRepository) UpdateContact(ctx context.Context, tenantID, id string, update func(contact *models.Contact, exists bool) error) (*models.Contact, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if id == "" {
+		return nil, fmt.Errorf("%w: contact ID is required", ErrInvalidInput)
+	}
+
+	ref := r.Client().Collection(r.getContactsCollection(tenantID)).Doc(id)
+	var result *models.Contact
+	err := r.Client().RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		now := time.Now()
+		contact := &models.Contact{TenantID: tenantID, CreatedAt: now}
+
+		doc, err := tx.Get(ref)
+		if err != nil && status.Code(err) != codes.NotFound {
+			return err
+		}
+		exists := err == nil
+		if exists {
+			if err := doc.DataTo(contact); err != nil {
+				return err
+			}
+			if err := DecryptContact(ctx, r.encryptor, contact); err != nil {
+				return err
+			}
+		}
+		contact.ID = id
+
+		if err := update(contact, exists); err != nil {
+			return err
+		}
+
+		contact.UpdatedAt = now
+		stored, err := EncryptContact(ctx, r.encryptor, contact)
+		if err != nil {
+			return err
+		}
+		result = contact
+		return tx.Set(ref, stored)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// AssignLead atomically finds the contact of a lead by its keys (in order, phone first), changes it and claims
+// the keys for it, so that concurrent leads of the same person end up in a single contact
+// Keys pointing to a deleted contact are ignored; contacts written before the keys existed are found by their
+// phone/e-mail. update receives the contact (a new one with exists = false when none matches); writes run in
+// the same transaction (ex: the contact_id of the lead)
+func (r *ContactRepository) AssignLead(ctx context.Context, tenantID string, keys []ContactKey, update func(contact *models.Contact, exists bool) error, writes func(contact *models.Contact) ([]TxWrite, error)) (*models.Contact, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%w: phone or email is required", ErrInvalidInput)
+	}
+
+	contacts := r.Client().Collection(r.getContactsCollection(tenantID))
+	keyRefs := make([]*firestore.DocumentRef, 0, len(keys))
+	for _, key := range keys {
+		if id := r.contactKeyID(key); id != "" {
+			keyRefs = append(keyRefs, r.Client().Collection(r.getContactKeysCollection(tenantID)).Doc(id))
+		}
+	}
+
+	var result *models.Contact
+	err := r.Client().RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		now := time.Now()
+		var contactDoc *firestore.DocumentSnapshot
+
+		for _, ref := range keyRefs {
+			doc, err := tx.Get(ref)
+			if status.Code(err) == codes.NotFound {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			var key contactKeyDoc
+			if err := doc.DataTo(&key); err != nil {
+				return err
+			}
+			if contactDoc != nil || key.ContactID == "" {
+				continue
+			}
+			snapshot, err := tx.Get(contacts.Doc(key.ContactID))
+			if status.Code(err) == codes.NotFound {
+				continue // Contact deleted (merge, LGPD): the key is claimed again
+			}
+			if err != nil {
+				return err
+			}
+			contactDoc = snapshot
+		}
+
+		// Contacts written before the keys existed
+		if contactDoc == nil {
+			for _, key := range keys {
+				field := "email"
+				if key.Kind == pii.FieldPhone {
+					field = "phone"
+				}
+				existing, err := r.getBy(ctx, tenantID, field, key.Kind, key.Value)
+				if err == ErrNotFound {
+					continue
+				}
+				if err != nil {
+					return err
+				}
+				if contactDoc, err = tx.Get(contacts.Doc(existing.ID)); err != nil {
+					return err
+				}
+				break
+			}
+		}
+
+		contact := &models.Contact{TenantID: tenantID, CreatedAt: now}
+		exists := contactDoc != nil
+		ref := contacts.NewDoc()
+		if exists {
+			ref = contactDoc.Ref
+			if err := contactDoc.DataTo(contact); err != nil {
+				return err
+			}
+			if err := DecryptContact(ctx, r.encryptor, contact); err != nil {
+				return err
+			}
+		}
+		contact.ID = ref.ID
+
+		if err := update(contact, exists); err != nil {
+			return err
+		}
+
+		contact.UpdatedAt = now
+		stored, err := EncryptContact(ctx, r.encryptor, contact)
+		if err != nil {
+			return err
+		}
+		var extra []TxWrite
+		if writes != nil {
+			if extra, err = writes(contact); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Set(ref, stored); err != nil {
+			return err
+		}
+		for _, keyRef := range keyRefs {
+			if err := tx.Set(keyRef, contactKeyDoc{ContactID: contact.ID, UpdatedAt: now}); err != nil {
+				return err
+			}
+		}
+		for _, write := range extra {
+			if err := write(ctx, tx); err != nil {
+				return err
+			}
+		}
+
+		result = contact
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ReleaseKeys deletes the keys that still point to a contact (phone/e-mail no longer used by its leads)
+func (r *ContactRepository) ReleaseKeys(ctx context.Context, tenantID, contactID string, keys []ContactKey) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	for _, key := range keys {
+		id := r.contactKeyID(key)
+		if id == "" {
+			continue
+		}
+		ref := r.Client().Collection(r.getContactKeysCollection(tenantID)).Doc(id)
+		err := r.Client().RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+			doc, err := tx.Get(ref)
+			if status.Code(err) == codes.NotFound {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			var stored contactKeyDoc
+			if err := doc.DataTo(&stored); err != nil {
+				return err
+			}
+			if stored.ContactID != contactID {
+				return nil
+			}
+			return tx.Delete(ref)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to release contact key: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// MoveKeysTx returns the write pointing the keys of a contact to another one (merge)
+func (r *ContactRepository) MoveKeysTx(ctx context.Context, tenantID, fromID, toID string) (TxWrite, error) {
+	refs, err := r.keyRefs(ctx, tenantID, fromID)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context, tx *firestore.Transaction) error {
+		for _, ref := range refs {
+			if err := tx.Set(ref, contactKeyDoc{ContactID: toID, UpdatedAt: time.Now()}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, nil
+}
+
+// keyRefs returns the keys pointing to a contact
+func (r *ContactRepository) keyRefs(ctx context.Context, tenantID, contactID string) ([]*firestore.DocumentRef, error) {
+	docs, err := r.Client().Collection(r.getContactKeysCollection(tenantID)).
+		Where("contact_id", "==", contactID).
+		Documents(ctx).GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query contact keys: %w", err)
+	}
+
+	refs := make([]*firestore.DocumentRef, 0, len(docs))
+	for _, doc := range docs {
+		refs = append(refs, doc.Ref)
+	}
+	return refs, nil
+}
+
+// SetTx returns the write replacing a contact for a transaction
+func (r *ContactRepository) SetTx(ctx context.Context, contact *models.Contact) (TxWrite, error) {
+	if contact.TenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if contact.ID == "" {
+		return nil, fmt.Errorf("%w: contact ID is required", ErrInvalidInput)
+	}
+
+	contact.UpdatedAt = time.Now()
+	stored, err := EncryptContact(ctx, r.encryptor, contact)
+	if err != nil {
+		return nil, err
+	}
+
+	docRef := r.Client().Collection(r.getContactsCollection(contact.TenantID)).Doc(contact.ID)
+	return func(ctx context.Context, tx *firestore.Transaction) error {
+		return tx.Set(docRef, stored)
+	}, nil
+}
+
+// Delete deletes a contact and its keys
+func (r *ContactRepository) Delete(ctx context.Context, tenantID, id string) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	refs, err := r.keyRefs(ctx, tenantID, id)
+	if err != nil {
+		return err
+	}
+	for _, ref := range refs {
+		if _, err := ref.Delete(ctx); err != nil {
+			return fmt.Errorf("failed to delete contact key: %w", err)
+		}
+	}
+
+	if err := r.DeleteDocument(ctx, r.getContactsCollection(tenantID), id); err != nil {
+		return fmt.Errorf("failed to delete contact: %w", err)
+	}
+	return nil
+}
+
+// DeleteTx returns the write of Delete for a transaction (ErrNotFound when the contact does not exist)
+func (r *ContactRepository) DeleteTx(tenantID, id string) (TxWrite, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if id == "" {
+		return nil, fmt.Errorf("%w: contact ID is required", ErrInvalidInput)
+	}
+
+	docRef := r.Client().Collection(r.getContactsCollection(tenantID)).Doc(id)
+	return func(ctx context.Context, tx *firestore.Transaction) error {
+		return tx.Delete(docRef, firestore.Exists)
+	}, nil
+}
+
+// List retrieves the contacts of a tenant (most recent lead first)
+func (r *ContactRepository) List(ctx context.Context, tenantID string, opts PaginationOptions) ([]*models.Contact, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	query := r.Client().Collection(r.getContactsCollection(tenantID)).Query
+	if opts.OrderBy == "" {
+		opts.OrderBy = "last_lead_at"
+		opts.Direction = firestore.Desc
+	}
+	query = r.ApplyPagination(query, opts)
+
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	contacts := make([]*models.Contact, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate contacts: %w", err)
+		}
+
+		var contact models.Contact
+		if err := doc.DataTo(&contact); err != nil {
+			return nil, fmt.Errorf("failed to decode contact: %w", err)
+		}
+		if err := DecryptContact(ctx, r.encryptor, &contact); err != nil {
+			return nil, err
+		}
+
+		contact.ID = doc.Ref.ID
+		contacts = append(contacts, &contact)
+	}
+
+	return contacts, nil
+}
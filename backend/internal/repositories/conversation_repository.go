@@ -0,0 +1,356 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/pii"
+)
+
+// ConversationRepository handles Firestore operations for the conversation history of leads
+type ConversationRepository struct {
+	*BaseRepository
+	encryptor *pii.Encryptor // optional: field-level encryption of PII
+}
+
+// ConversationFilters contains filters for the conversation inbox
+type ConversationFilters struct {
+	AssignedBrokerID string
+	PropertyID       string
+	Channel          *models.LeadChannel
+	UnreadOnly       bool
+}
+
+// NewConversationRepository creates a new conversation repository
+func NewConversationRepository(client *firestore.Client) *ConversationRepository {
+	return &ConversationRepository{
+		BaseRepository: NewBaseRepository(client),
+	}
+}
+
+// SetEncryptor enables field-level encryption of the message senders/recipients and of the WhatsApp threads
+// WhatsApp threads are then keyed by the phone blind index instead of the WhatsApp ID (run cmd/pii-rotate to
+// encrypt and re-key existing messages and threads)
+func (r *ConversationRepository) SetEncryptor(encryptor *pii.Encryptor) {
+	r.encryptor = encryptor
+}
+
+// getMessagesCollection returns the collection path for the messages of a lead
+func (r *ConversationRepository) getMessagesCollection(tenantID, leadID string) string {
+	return fmt.Sprintf("tenants/%s/leads/%s/messages", tenantID, leadID)
+}
+
+// getConversationsCollection returns the collection path for conversations (one per lead) within a tenant
+func (r *ConversationRepository) getConversationsCollection(tenantID string) string {
+	return fmt.Sprintf("tenants/%s/conversations", tenantID)
+}
+
+// getWhatsAppThreadsCollection returns the collection path for WhatsApp threads within a tenant
+func (r *ConversationRepository) getWhatsAppThreadsCollection(tenantID string) string {
+	return fmt.Sprintf("tenants/%s/whatsapp_threads", tenantID)
+}
+
+// whatsAppThreadID returns the document ID of the thread of a WhatsApp contact
+// (the phone blind index, or the WhatsApp ID itself without an encryptor)
+func (r *ConversationRepository) whatsAppThreadID(waID string) string {
+	if r.encryptor == nil {
+		return waID
+	}
+	return r.encryptor.BlindIndex(pii.FieldPhone, waID)
+}
+
+// CreateMessage stores a message in the conversation of a lead
+// Messages with an external ID use it as document ID: a repeated delivery fails with ErrAlreadyExists
+func (r *ConversationRepository) CreateMessage(ctx context.Context, message *models.ConversationMessage) error {
+	if message.TenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if message.LeadID == "" {
+		return fmt.Errorf("%w: lead_id is required", ErrInvalidInput)
+	}
+
+	collectionPath := r.getMessagesCollection(message.TenantID, message.LeadID)
+	message.ID = message.ExternalID
+	if message.ID == "" {
+		message.ID = r.GenerateID(collectionPath)
+	}
+
+	message.CreatedAt = time.Now()
+	if message.SentAt.IsZero() {
+		message.SentAt = message.CreatedAt
+	}
+
+	stored, err := EncryptConversationMessage(ctx, r.encryptor, message)
+	if err != nil {
+		return err
+	}
+
+	if err := r.CreateDocument(ctx, collectionPath, message.ID, stored); err != nil {
+		if err == ErrAlreadyExists {
+			return err
+		}
+		return fmt.Errorf("failed to create conversation message: %w", err)
+	}
+
+	return nil
+}
+
+// ListMessages retrieves the messages of a lead in chronological order
+func (r *ConversationRepository) ListMessages(ctx context.Context, tenantID, leadID string, opts PaginationOptions) ([]*models.ConversationMessage, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if leadID == "" {
+		return nil, fmt.Errorf("%w: lead_id is required", ErrInvalidInput)
+	}
+
+	query := r.Client().Collection(r.getMessagesCollection(tenantID, leadID)).Query
+	if opts.OrderBy == "" {
+		opts.OrderBy = "sent_at"
+		opts.Direction = firestore.Asc
+	}
+	query = r.ApplyPagination(query, opts)
+
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	messages := make([]*models.ConversationMessage, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate conversation messages: %w", err)
+		}
+
+		var message models.ConversationMessage
+		if err := doc.DataTo(&message); err != nil {
+			return nil, fmt.Errorf("failed to decode conversation message: %w", err)
+		}
+		if err := DecryptConversationMessage(ctx, r.encryptor, &message); err != nil {
+			return nil, err
+		}
+
+		message.ID = doc.Ref.ID
+		messages = append(messages, &message)
+	}
+
+	return messages, nil
+}
+
+// GetConversation retrieves the conversation of a lead
+func (r *ConversationRepository) GetConversation(ctx context.Context, tenantID, leadID string) (*models.Conversation, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	var conversation models.Conversation
+	if err := r.GetDocument(ctx, r.getConversationsCollection(tenantID), leadID, &conversation); err != nil {
+		return nil, err
+	}
+
+	conversation.LeadID = leadID
+	return &conversation, nil
+}
+
+// UpdateConversation atomically reads, changes and writes the conversation of a lead
+// update receives the stored conversation (a new one with exists = false when missing) and may return an
+// error to abort without writing
+func (r *ConversationRepository) UpdateConversation(ctx context.Context, tenantID, leadID string, update func(conversation *models.Conversation, exists bool) error) (*models.Conversation, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if leadID == "" {
+		return nil, fmt.Errorf("%w: lead_id is required", ErrInvalidInput)
+	}
+
+	ref := r.Client().Collection(r.getConversationsCollection(tenantID)).Doc(leadID)
+	var result *models.Conversation
+	err := r.Client().RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		now := time.Now()
+		conversation := &models.Conversation{TenantID: tenantID, CreatedAt: now}
+
+		doc, err := tx.Get(ref)
+		if err != nil && status.Code(err) != codes.NotFound {
+			return err
+		}
+		exists := err == nil
+		if exists {
+			if err := doc.DataTo(conversation); err != nil {
+				return err
+			}
+		}
+		conversation.LeadID = leadID
+
+		if err := update(conversation, exists); err != nil {
+			return err
+		}
+
+		conversation.UpdatedAt = now
+		result = conversation
+		return tx.Set(ref, conversation)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ListConversations retrieves the conversation inbox of a tenant (most recent activity first)
+func (r *ConversationRepository) ListConversations(ctx context.Context, tenantID string, filters *ConversationFilters, opts PaginationOptions) ([]*models.Conversation, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	query := r.Client().Collection(r.getConversationsCollection(tenantID)).Query
+	if filters != nil {
+		if filters.AssignedBrokerID != "" {
+			query = query.Where("assigned_broker_id", "==", filters.AssignedBrokerID)
+		}
+		if filters.PropertyID != "" {
+			query = query.Where("property_id", "==", filters.PropertyID)
+		}
+		if filters.Channel != nil {
+			query = query.Where("channels", "array-contains", string(*filters.Channel))
+		}
+		if filters.UnreadOnly {
+			query = query.Where("has_unread", "==", true)
+		}
+	}
+
+	if opts.OrderBy == "" {
+		opts.OrderBy = "last_message_at"
+		opts.Direction = firestore.Desc
+	}
+	query = r.ApplyPagination(query, opts)
+
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	conversations := make([]*models.Conversation, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate conversations: %w", err)
+		}
+
+		var conversation models.Conversation
+		if err := doc.DataTo(&conversation); err != nil {
+			return nil, fmt.Errorf("failed to decode conversation: %w", err)
+		}
+
+		conversation.LeadID = doc.Ref.ID
+		conversations = append(conversations, &conversation)
+	}
+
+	return conversations, nil
+}
+
+// GetWhatsAppThread retrieves the thread of a WhatsApp contact
+func (r *ConversationRepository) GetWhatsAppThread(ctx context.Context, tenantID, waID string) (*models.WhatsAppThread, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	var thread models.WhatsAppThread
+	if err := r.GetDocument(ctx, r.getWhatsAppThreadsCollection(tenantID), r.whatsAppThreadID(waID), &thread); err != nil {
+		return nil, err
+	}
+	if err := DecryptWhatsAppThread(ctx, r.encryptor, &thread); err != nil {
+		return nil, err
+	}
+
+	thread.WaID = waID
+	return &thread, nil
+}
+
+// SetWhatsAppThread creates or replaces the thread of a WhatsApp contact
+func (r *ConversationRepository) SetWhatsAppThread(ctx context.Context, tenantID string, thread *models.WhatsAppThread) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if thread.WaID == "" {
+		return fmt.Errorf("%w: wa_id is required", ErrInvalidInput)
+	}
+
+	thread.UpdatedAt = time.Now()
+	stored, err := EncryptWhatsAppThread(ctx, r.encryptor, thread)
+	if err != nil {
+		return err
+	}
+
+	if err := r.SetDocument(ctx, r.getWhatsAppThreadsCollection(tenantID), r.whatsAppThreadID(thread.WaID), stored); err != nil {
+		return fmt.Errorf("failed to set whatsapp thread: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteWhatsAppThread deletes the thread of a WhatsApp contact (LGPD erasure); missing threads are ignored
+func (r *ConversationRepository) DeleteWhatsAppThread(ctx context.Context, tenantID, waID string) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if waID == "" {
+		return fmt.Errorf("%w: wa_id is required", ErrInvalidInput)
+	}
+
+	if _, err := r.Client().Collection(r.getWhatsAppThreadsCollection(tenantID)).Doc(r.whatsAppThreadID(waID)).Delete(ctx); err != nil {
+		return fmt.Errorf("failed to delete whatsapp thread: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteLeadConversation deletes the conversation of a lead, its messages and the WhatsApp threads pointing to it
+// (LGPD anonymization)
+func (r *ConversationRepository) DeleteLeadConversation(ctx context.Context, tenantID, leadID string) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if leadID == "" {
+		return fmt.Errorf("%w: lead_id is required", ErrInvalidInput)
+	}
+
+	queries := []firestore.Query{
+		r.Client().Collection(r.getMessagesCollection(tenantID, leadID)).Query,
+		r.Client().Collection(r.getWhatsAppThreadsCollection(tenantID)).Where("lead_id", "==", leadID),
+	}
+
+	for _, query := range queries {
+		iter := query.Documents(ctx)
+		for {
+			doc, err := iter.Next()
+			if err == iterator.Done {
+				break
+			}
+			if err != nil {
+				iter.Stop()
+				return fmt.Errorf("failed to iterate lead conversation: %w", err)
+			}
+			if _, err := doc.Ref.Delete(ctx); err != nil {
+				iter.Stop()
+				return fmt.Errorf("failed to delete lead conversation: %w", err)
+			}
+		}
+		iter.Stop()
+	}
+
+	if _, err := r.Client().Collection(r.getConversationsCollection(tenantID)).Doc(leadID).Delete(ctx); err != nil {
+		return fmt.Errorf("failed to delete conversation: %w", err)
+	}
+
+	return nil
+}
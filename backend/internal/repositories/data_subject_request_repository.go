@@ -0,0 +1,214 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/pii"
+)
+
+// DataSubjectRequestRepository handles Firestore operations for LGPD data subject requests
+type DataSubjectRequestRepository struct {
+	*BaseRepository
+	encryptor *pii.Encryptor // optional: field-level encryption of the subject's contact data
+}
+
+// NewDataSubjectRequestRepository creates a new data subject request repository
+func NewDataSubjectRequestRepository(client *firestore.Client) *DataSubjectRequestRepository {
+	return &DataSubjectRequestRepository{
+		BaseRepository: NewBaseRepository(client),
+	}
+}
+
+// SetEncryptor enables field-level encryption of the subject's email, phone and document
+func (r *DataSubjectRequestRepository) SetEncryptor(encryptor *pii.Encryptor) {
+	r.encryptor = encryptor
+}
+
+// getRequestsCollection returns the collection path for data subject requests within a tenant
+func (r *DataSubjectRequestRepository) getRequestsCollection(tenantID string) string {
+	return fmt.Sprintf("tenants/%s/data_subject_requests", tenantID)
+}
+
+// Create creates a new data subject request
+func (r *DataSubjectRequestRepository) Create(ctx context.Context, request *models.DataSubjectRequest) error {
+	if request.TenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	if request.ID == "" {
+		request.ID = r.GenerateID(r.getRequestsCollection(request.TenantID))
+	}
+
+	now := time.Now()
+	request.CreatedAt = now
+	request.UpdatedAt = now
+
+	stored, err := EncryptDataSubjectRequest(ctx, r.encryptor, request)
+	if err != nil {
+		return err
+	}
+
+	if err := r.CreateDocument(ctx, r.getRequestsCollection(request.TenantID), request.ID, stored); err != nil {
+		return fmt.Errorf("failed to create data subject request: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves a data subject request by ID
+func (r *DataSubjectRequestRepository) Get(ctx context.Context, tenantID, id string) (*models.DataSubjectRequest, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if id == "" {
+		return nil, fmt.Errorf("%w: request ID is required", ErrInvalidInput)
+	}
+
+	var request models.DataSubjectRequest
+	if err := r.GetDocument(ctx, r.getRequestsCollection(tenantID), id, &request); err != nil {
+		return nil, err
+	}
+	if err := DecryptDataSubjectRequest(ctx, r.encryptor, &request); err != nil {
+		return nil, err
+	}
+
+	request.ID = id
+	return &request, nil
+}
+
+// Update updates a data subject request
+func (r *DataSubjectRequestRepository) Update(ctx context.Context, tenantID, id string, updates map[string]interface{}) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if id == "" {
+		return fmt.Errorf("%w: request ID is required", ErrInvalidInput)
+	}
+
+	if err := EncryptPIIUpdates(ctx, r.encryptor, DataSubjectRequestPIIFields, updates); err != nil {
+		return err
+	}
+
+	updates["updated_at"] = time.Now()
+
+	if err := r.UpdateDocument(ctx, r.getRequestsCollection(tenantID), id, mapToFirestoreUpdates(updates)); err != nil {
+		return fmt.Errorf("failed to update data subject request: %w", err)
+	}
+
+	return nil
+}
+
+// TakeVerificationAttempt counts a verification attempt in a transaction, after check accepts the stored request
+// Counting before the code is compared means parallel guesses cannot go past the attempt limit; an error of
+// check is returned as is and nothing is written
+func (r *DataSubjectRequestRepository) TakeVerificationAttempt(ctx context.Context, tenantID, id string, check func(request *models.DataSubjectRequest) error) (*models.DataSubjectRequest, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if id == "" {
+		return nil, fmt.Errorf("%w: request ID is required", ErrInvalidInput)
+	}
+
+	ref := r.Client().Collection(r.getRequestsCollection(tenantID)).Doc(id)
+	var request models.DataSubjectRequest
+	err := r.Client().RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(ref)
+		if err != nil {
+			if status.Code(err) == codes.NotFound {
+				return ErrNotFound
+			}
+			return fmt.Errorf("failed to get data subject request: %w", err)
+		}
+
+		request = models.DataSubjectRequest{}
+		if err := doc.DataTo(&request); err != nil {
+			return fmt.Errorf("failed to decode data subject request: %w", err)
+		}
+		if err := check(&request); err != nil {
+			return err
+		}
+
+		request.VerificationAttempts++
+		return tx.Update(ref, []firestore.Update{
+			{Path: "verification_attempts", Value: firestore.Increment(1)},
+			{Path: "updated_at", Value: time.Now()},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := DecryptDataSubjectRequest(ctx, r.encryptor, &request); err != nil {
+		return nil, err
+	}
+
+	request.ID = id
+	return &request, nil
+}
+
+// List retrieves data subject requests (most recent first), optionally filtered by status
+func (r *DataSubjectRequestRepository) List(ctx context.Context, tenantID string, status models.DSRStatus) ([]*models.DataSubjectRequest, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	query := r.Client().Collection(r.getRequestsCollection(tenantID)).Query
+	if status != "" {
+		query = query.Where("status", "==", string(status))
+	}
+	query = query.OrderBy("created_at", firestore.Desc)
+
+	return r.list(ctx, query)
+}
+
+// ListCreatedBetween retrieves requests created in [from, to) (oldest first), used by the audit report
+func (r *DataSubjectRequestRepository) ListCreatedBetween(ctx context.Context, tenantID string, from, to time.Time) ([]*models.DataSubjectRequest, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	query := r.Client().Collection(r.getRequestsCollection(tenantID)).
+		Where("created_at", ">=", from).
+		Where("created_at", "<", to).
+		OrderBy("created_at", firestore.Asc)
+
+	return r.list(ctx, query)
+}
+
+// list runs a query and decodes the data subject requests
+func (r *DataSubjectRequestRepository) list(ctx context.Context, query firestore.Query) ([]*models.DataSubjectRequest, error) {
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	requests := make([]*models.DataSubjectRequest, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate data subject requests: %w", err)
+		}
+
+		var request models.DataSubjectRequest
+		if err := doc.DataTo(&request); err != nil {
+			return nil, fmt.Errorf("failed to decode data subject request: %w", err)
+		}
+		if err := DecryptDataSubjectRequest(ctx, r.encryptor, &request); err != nil {
+			return nil, err
+		}
+
+		request.ID = doc.Ref.ID
+		requests = append(requests, &request)
+	}
+
+	return requests, nil
+}
@@ -0,0 +1,113 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+)
+
+// InboundEmailRepository handles Firestore operations for the lead notification e-mails received from portals
+type InboundEmailRepository struct {
+	*BaseRepository
+}
+
+// NewInboundEmailRepository creates a new inbound e-mail repository
+func NewInboundEmailRepository(client *firestore.Client) *InboundEmailRepository {
+	return &InboundEmailRepository{
+		BaseRepository: NewBaseRepository(client),
+	}
+}
+
+// getInboundEmailsCollection returns the collection path for inbound e-mails within a tenant
+func (r *InboundEmailRepository) getInboundEmailsCollection(tenantID string) string {
+	return fmt.Sprintf("tenants/%s/inbound_emails", tenantID)
+}
+
+// Create stores an inbound e-mail; fails with ErrAlreadyExists when the e-mail was already received
+func (r *InboundEmailRepository) Create(ctx context.Context, email *models.InboundEmail) error {
+	if email.TenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if email.ID == "" {
+		return fmt.Errorf("%w: id is required", ErrInvalidInput)
+	}
+
+	email.CreatedAt = time.Now()
+	if email.ReceivedAt.IsZero() {
+		email.ReceivedAt = email.CreatedAt
+	}
+
+	if err := r.CreateDocument(ctx, r.getInboundEmailsCollection(email.TenantID), email.ID, email); err != nil {
+		if err == ErrAlreadyExists {
+			return err
+		}
+		return fmt.Errorf("failed to create inbound email: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves an inbound e-mail by ID
+func (r *InboundEmailRepository) Get(ctx context.Context, tenantID, id string) (*models.InboundEmail, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if id == "" {
+		return nil, fmt.Errorf("%w: id is required", ErrInvalidInput)
+	}
+
+	var email models.InboundEmail
+	if err := r.GetDocument(ctx, r.getInboundEmailsCollection(tenantID), id, &email); err != nil {
+		return nil, err
+	}
+
+	email.ID = id
+	return &email, nil
+}
+
+// List retrieves the inbound e-mails of a tenant (most recent first), optionally by status
+func (r *InboundEmailRepository) List(ctx context.Context, tenantID string, status *models.InboundEmailStatus, opts PaginationOptions) ([]*models.InboundEmail, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	query := r.Client().Collection(r.getInboundEmailsCollection(tenantID)).Query
+	if status != nil {
+		query = query.Where("status", "==", string(*status))
+	}
+
+	if opts.OrderBy == "" {
+		opts.OrderBy = "received_at"
+		opts.Direction = firestore.Desc
+	}
+	query = r.ApplyPagination(query, opts)
+
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	emails := make([]*models.InboundEmail, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate inbound emails: %w", err)
+		}
+
+		var email models.InboundEmail
+		if err := doc.DataTo(&email); err != nil {
+			return nil, fmt.Errorf("failed to decode inbound email: %w", err)
+		}
+
+		email.ID = doc.Ref.ID
+		emails = append(emails, &email)
+	}
+
+	return emails, nil
+}
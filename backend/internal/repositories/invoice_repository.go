@@ -0,0 +1,140 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+)
+
+// InvoiceRepository handles Firestore operations for tenant invoices
+type InvoiceRepository struct {
+	*BaseRepository
+}
+
+// NewInvoiceRepository creates a new invoice repository
+func NewInvoiceRepository(client *firestore.Client) *InvoiceRepository {
+	return &InvoiceRepository{
+		BaseRepository: NewBaseRepository(client),
+	}
+}
+
+// getInvoicesCollection returns the collection path for invoices within a tenant
+func (r *InvoiceRepository) getInvoicesCollection(tenantID string) string {
+	return fmt.Sprintf("tenants/%s/invoices", tenantID)
+}
+
+// Create creates the invoice of a period (ID YYYYMM); fails if it already exists
+func (r *InvoiceRepository) Create(ctx context.Context, invoice *models.Invoice) error {
+	if invoice.TenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if invoice.Period == "" {
+		return fmt.Errorf("%w: period is required", ErrInvalidInput)
+	}
+
+	now := time.Now()
+	invoice.ID = models.InvoiceID(invoice.Period)
+	invoice.CreatedAt = now
+	invoice.UpdatedAt = now
+
+	if err := r.CreateDocument(ctx, r.getInvoicesCollection(invoice.TenantID), invoice.ID, invoice); err != nil {
+		return fmt.Errorf("failed to create invoice: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves an invoice by ID
+func (r *InvoiceRepository) Get(ctx context.Context, tenantID, id string) (*models.Invoice, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if id == "" {
+		return nil, fmt.Errorf("%w: invoice ID is required", ErrInvalidInput)
+	}
+
+	var invoice models.Invoice
+	if err := r.GetDocument(ctx, r.getInvoicesCollection(tenantID), id, &invoice); err != nil {
+		return nil, err
+	}
+
+	invoice.ID = id
+	return &invoice, nil
+}
+
+// Update updates an invoice
+func (r *InvoiceRepository) Update(ctx context.Context, tenantID, id string, updates []firestore.Update) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if id == "" {
+		return fmt.Errorf("%w: invoice ID is required", ErrInvalidInput)
+	}
+
+	updates = append(updates, firestore.Update{Path: "updated_at", Value: time.Now()})
+
+	if err := r.UpdateDocument(ctx, r.getInvoicesCollection(tenantID), id, updates); err != nil {
+		return fmt.Errorf("failed to update invoice: %w", err)
+	}
+
+	return nil
+}
+
+// List retrieves the invoices of a tenant (most recent period first)
+func (r *InvoiceRepository) List(ctx context.Context, tenantID string, opts PaginationOptions) ([]*models.Invoice, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	if opts.Limit == 0 {
+		opts = DefaultPaginationOptions()
+	}
+	opts.OrderBy = "period"
+	opts.Direction = firestore.Desc
+
+	query := r.ApplyPagination(r.Client().Collection(r.getInvoicesCollection(tenantID)).Query, opts)
+	return r.list(ctx, query)
+}
+
+// ListOpen retrieves the unpaid invoices of a tenant (dunning)
+func (r *InvoiceRepository) ListOpen(ctx context.Context, tenantID string) ([]*models.Invoice, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	query := r.Client().Collection(r.getInvoicesCollection(tenantID)).
+		Where("status", "==", string(models.InvoiceStatusOpen))
+	return r.list(ctx, query)
+}
+
+// list runs an invoice query
+func (r *InvoiceRepository) list(ctx context.Context, query firestore.Query) ([]*models.Invoice, error) {
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	invoices := make([]*models.Invoice, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate invoices: %w", err)
+		}
+
+		var invoice models.Invoice
+		if err := doc.DataTo(&invoice); err != nil {
+			return nil, fmt.Errorf("failed to decode invoice: %w", err)
+		}
+
+		invoice.ID = doc.Ref.ID
+		invoices = append(invoices, &invoice)
+	}
+
+	return invoices, nil
+}
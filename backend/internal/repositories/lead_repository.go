@@ -9,11 +9,13 @@ import (
 	"google.golang.org/api/iterator"
 
 	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/pii"
 )
 
 // LeadRepository handles Firestore operations for leads
 type LeadRepository struct {
 	*BaseRepository
+	encryptor *pii.Encryptor // optional: field-level encryption of PII
 }
 
 // NewLeadRepository creates a new lead repository
@@ -23,6 +25,12 @@ func NewLeadRepository(client *firestore.Client) *LeadRepository {
 	}
 }
 
+// SetEncryptor enables field-level encryption of email and phone
+// Lookups by these fields then use blind indexes (run cmd/pii-rotate to backfill existing leads)
+func (r *LeadRepository) SetEncryptor(encryptor *pii.Encryptor) {
+	r.encryptor = encryptor
+}
+
 // getLeadsCollection returns the collection path for leads within a tenant
 func (r *LeadRepository) getLeadsCollection(tenantID string) string {
 	return fmt.Sprintf("tenants/%s/leads", tenantID)
@@ -37,6 +45,43 @@ type LeadFilters struct {
 
 // Create creates a new lead
 func (r *LeadRepository) Create(ctx context.Context, lead *models.Lead) error {
+	if err := r.prepareCreate(lead); err != nil {
+		return err
+	}
+
+	stored, err := EncryptLead(ctx, r.encryptor, lead)
+	if err != nil {
+		return err
+	}
+
+	collectionPath := r.getLeadsCollection(lead.TenantID)
+	if err := r.CreateDocument(ctx, collectionPath, lead.ID, stored); err != nil {
+		return fmt.Errorf("failed to create lead: %w", err)
+	}
+
+	return nil
+}
+
+// CreateTx returns the write of Create for a transaction (transactional outbox)
+// The lead ID is assigned immediately so the domain event can reference it
+func (r *LeadRepository) CreateTx(ctx context.Context, lead *models.Lead) (TxWrite, error) {
+	if err := r.prepareCreate(lead); err != nil {
+		return nil, err
+	}
+
+	stored, err := EncryptLead(ctx, r.encryptor, lead)
+	if err != nil {
+		return nil, err
+	}
+
+	docRef := r.Client().Collection(r.getLeadsCollection(lead.TenantID)).Doc(lead.ID)
+	return func(ctx context.Context, tx *firestore.Transaction) error {
+		return tx.Create(docRef, stored)
+	}, nil
+}
+
+// prepareCreate validates a new lead and sets its ID and timestamps
+func (r *LeadRepository) prepareCreate(lead *models.Lead) error {
 	if lead.TenantID == "" {
 		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
 	}
@@ -60,11 +105,6 @@ func (r *LeadRepository) Create(ctx context.Context, lead *models.Lead) error {
 		lead.ConsentDate = now
 	}
 
-	collectionPath := r.getLeadsCollection(lead.TenantID)
-	if err := r.CreateDocument(ctx, collectionPath, lead.ID, lead); err != nil {
-		return fmt.Errorf("failed to create lead: %w", err)
-	}
-
 	return nil
 }
 
@@ -79,6 +119,9 @@ func (r *LeadRepository) Get(ctx context.Context, tenantID, id string) (*models.
 	if err := r.GetDocument(ctx, collectionPath, id, &lead); err != nil {
 		return nil, err
 	}
+	if err := DecryptLead(ctx, r.encryptor, &lead); err != nil {
+		return nil, err
+	}
 
 	lead.ID = id
 	return &lead, nil
@@ -86,11 +129,43 @@ func (r *LeadRepository) Get(ctx context.Context, tenantID, id string) (*models.
 
 // Update updates a lead
 func (r *LeadRepository) Update(ctx context.Context, tenantID, id string, updates map[string]interface{}) error {
+	firestoreUpdates, err := r.prepareUpdate(ctx, tenantID, id, updates)
+	if err != nil {
+		return err
+	}
+
+	collectionPath := r.getLeadsCollection(tenantID)
+	if err := r.UpdateDocument(ctx, collectionPath, id, firestoreUpdates); err != nil {
+		return fmt.Errorf("failed to update lead: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateTx returns the write of Update for a transaction (ErrNotFound when the lead does not exist)
+func (r *LeadRepository) UpdateTx(ctx context.Context, tenantID, id string, updates map[string]interface{}) (TxWrite, error) {
+	firestoreUpdates, err := r.prepareUpdate(ctx, tenantID, id, updates)
+	if err != nil {
+		return nil, err
+	}
+
+	docRef := r.Client().Collection(r.getLeadsCollection(tenantID)).Doc(id)
+	return func(ctx context.Context, tx *firestore.Transaction) error {
+		return tx.Update(docRef, firestoreUpdates)
+	}, nil
+}
+
+// prepareUpdate encrypts PII in the updates and converts them to Firestore updates
+func (r *LeadRepository) prepareUpdate(ctx context.Context, tenantID, id string, updates map[string]interface{}) ([]firestore.Update, error) {
 	if tenantID == "" {
-		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
 	}
 	if id == "" {
-		return fmt.Errorf("%w: lead ID is required", ErrInvalidInput)
+		return nil, fmt.Errorf("%w: lead ID is required", ErrInvalidInput)
+	}
+
+	if err := EncryptPIIUpdates(ctx, r.encryptor, LeadPIIFields, updates); err != nil {
+		return nil, err
 	}
 
 	// Add updated_at timestamp
@@ -105,12 +180,7 @@ func (r *LeadRepository) Update(ctx context.Context, tenantID, id string, update
 		})
 	}
 
-	collectionPath := r.getLeadsCollection(tenantID)
-	if err := r.UpdateDocument(ctx, collectionPath, id, firestoreUpdates); err != nil {
-		return fmt.Errorf("failed to update lead: %w", err)
-	}
-
-	return nil
+	return firestoreUpdates, nil
 }
 
 // Delete deletes a lead (should be rare - prefer anonymization)
@@ -126,6 +196,21 @@ func (r *LeadRepository) Delete(ctx context.Context, tenantID, id string) error
 	return nil
 }
 
+// DeleteTx returns the write of Delete for a transaction (ErrNotFound when the lead does not exist)
+func (r *LeadRepository) DeleteTx(tenantID, id string) (TxWrite, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if id == "" {
+		return nil, fmt.Errorf("%w: lead ID is required", ErrInvalidInput)
+	}
+
+	docRef := r.Client().Collection(r.getLeadsCollection(tenantID)).Doc(id)
+	return func(ctx context.Context, tx *firestore.Transaction) error {
+		return tx.Delete(docRef, firestore.Exists)
+	}, nil
+}
+
 // List retrieves leads for a tenant with optional filters and pagination
 func (r *LeadRepository) List(ctx context.Context, tenantID string, filters *LeadFilters, opts PaginationOptions) ([]*models.Lead, error) {
 	if tenantID == "" {
@@ -171,6 +256,9 @@ func (r *LeadRepository) List(ctx context.Context, tenantID string, filters *Lea
 		if err := doc.DataTo(&lead); err != nil {
 			return nil, fmt.Errorf("failed to decode lead: %w", err)
 		}
+		if err := DecryptLead(ctx, r.encryptor, &lead); err != nil {
+			return nil, err
+		}
 
 		lead.ID = doc.Ref.ID
 		leads = append(leads, &lead)
@@ -224,10 +312,11 @@ func (r *LeadRepository) GetByEmail(ctx context.Context, tenantID, propertyID, e
 		return nil, fmt.Errorf("%w: email is required", ErrInvalidInput)
 	}
 
+	lookupField, lookupValue := piiLookup(r.encryptor, "email", pii.FieldEmail, email)
 	collectionPath := r.getLeadsCollection(tenantID)
 	query := r.Client().Collection(collectionPath).
 		Where("property_id", "==", propertyID).
-		Where("email", "==", email).
+		Where(lookupField, "==", lookupValue).
 		Limit(1)
 
 	iter := query.Documents(ctx)
@@ -245,6 +334,9 @@ func (r *LeadRepository) GetByEmail(ctx context.Context, tenantID, propertyID, e
 	if err := doc.DataTo(&lead); err != nil {
 		return nil, fmt.Errorf("failed to decode lead: %w", err)
 	}
+	if err := DecryptLead(ctx, r.encryptor, &lead); err != nil {
+		return nil, err
+	}
 
 	lead.ID = doc.Ref.ID
 	return &lead, nil
@@ -262,10 +354,11 @@ func (r *LeadRepository) GetByPhone(ctx context.Context, tenantID, propertyID, p
 		return nil, fmt.Errorf("%w: phone is required", ErrInvalidInput)
 	}
 
+	lookupField, lookupValue := piiLookup(r.encryptor, "phone", pii.FieldPhone, phone)
 	collectionPath := r.getLeadsCollection(tenantID)
 	query := r.Client().Collection(collectionPath).
 		Where("property_id", "==", propertyID).
-		Where("phone", "==", phone).
+		Where(lookupField, "==", lookupValue).
 		Limit(1)
 
 	iter := query.Documents(ctx)
@@ -283,11 +376,95 @@ func (r *LeadRepository) GetByPhone(ctx context.Context, tenantID, propertyID, p
 	if err := doc.DataTo(&lead); err != nil {
 		return nil, fmt.Errorf("failed to decode lead: %w", err)
 	}
+	if err := DecryptLead(ctx, r.encryptor, &lead); err != nil {
+		return nil, err
+	}
 
 	lead.ID = doc.Ref.ID
 	return &lead, nil
 }
 
+// ListByContact retrieves every lead of the tenant with the given email or phone
+// (across properties - used by LGPD data subject requests)
+func (r *LeadRepository) ListByContact(ctx context.Context, tenantID, email, phone string) ([]*models.Lead, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if email == "" && phone == "" {
+		return nil, fmt.Errorf("%w: email or phone is required", ErrInvalidInput)
+	}
+
+	collectionPath := r.getLeadsCollection(tenantID)
+	seen := make(map[string]bool)
+	leads := make([]*models.Lead, 0)
+
+	lookups := []struct {
+		field string
+		kind  pii.FieldKind
+		value string
+	}{
+		{"email", pii.FieldEmail, email},
+		{"phone", pii.FieldPhone, phone},
+	}
+
+	for _, lookup := range lookups {
+		if lookup.value == "" {
+			continue
+		}
+
+		lookupField, lookupValue := piiLookup(r.encryptor, lookup.field, lookup.kind, lookup.value)
+		iter := r.Client().Collection(collectionPath).
+			Where(lookupField, "==", lookupValue).
+			Documents(ctx)
+
+		for {
+			doc, err := iter.Next()
+			if err == iterator.Done {
+				break
+			}
+			if err != nil {
+				iter.Stop()
+				return nil, fmt.Errorf("failed to query leads by %s: %w", lookup.field, err)
+			}
+			if seen[doc.Ref.ID] {
+				continue
+			}
+
+			var lead models.Lead
+			if err := doc.DataTo(&lead); err != nil {
+				iter.Stop()
+				return nil, fmt.Errorf("failed to decode lead: %w", err)
+			}
+			if err := DecryptLead(ctx, r.encryptor, &lead); err != nil {
+				iter.Stop()
+				return nil, err
+			}
+
+			lead.ID = doc.Ref.ID
+			seen[lead.ID] = true
+			leads = append(leads, &lead)
+		}
+		iter.Stop()
+	}
+
+	return leads, nil
+}
+
+// ListByContactID retrieves the leads (interests) of a contact
+func (r *LeadRepository) ListByContactID(ctx context.Context, tenantID, contactID string) ([]*models.Lead, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if contactID == "" {
+		return nil, fmt.Errorf("%w: contact_id is required", ErrInvalidInput)
+	}
+
+	query := r.Client().Collection(r.getLeadsCollection(tenantID)).
+		Where("contact_id", "==", contactID)
+
+	return r.queryLeads(ctx, query)
+}
+
 // ListWithRevokedConsent retrieves leads with revoked consent
 func (r *LeadRepository) ListWithRevokedConsent(ctx context.Context, tenantID string, opts PaginationOptions) ([]*models.Lead, error) {
 	if tenantID == "" {
@@ -321,6 +498,70 @@ func (r *LeadRepository) ListWithRevokedConsent(ctx context.Context, tenantID st
 		if err := doc.DataTo(&lead); err != nil {
 			return nil, fmt.Errorf("failed to decode lead: %w", err)
 		}
+		if err := DecryptLead(ctx, r.encryptor, &lead); err != nil {
+			return nil, err
+		}
+
+		lead.ID = doc.Ref.ID
+		leads = append(leads, &lead)
+	}
+
+	return leads, nil
+}
+
+// ListInactiveBefore retrieves non-anonymized leads not updated since the cutoff, oldest first
+func (r *LeadRepository) ListInactiveBefore(ctx context.Context, tenantID string, cutoff time.Time, limit int) ([]*models.Lead, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	query := r.Client().Collection(r.getLeadsCollection(tenantID)).
+		Where("is_anonymized", "==", false).
+		Where("updated_at", "<", cutoff).
+		OrderBy("updated_at", firestore.Asc).
+		Limit(limit)
+
+	return r.queryLeads(ctx, query)
+}
+
+// ListRevokedConsentBefore retrieves non-anonymized leads whose consent was revoked before the cutoff
+func (r *LeadRepository) ListRevokedConsentBefore(ctx context.Context, tenantID string, cutoff time.Time, limit int) ([]*models.Lead, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	query := r.Client().Collection(r.getLeadsCollection(tenantID)).
+		Where("consent_revoked", "==", true).
+		Where("is_anonymized", "==", false).
+		Where("revoked_at", "<", cutoff).
+		OrderBy("revoked_at", firestore.Asc).
+		Limit(limit)
+
+	return r.queryLeads(ctx, query)
+}
+
+// queryLeads runs a query and decodes the leads
+func (r *LeadRepository) queryLeads(ctx context.Context, query firestore.Query) ([]*models.Lead, error) {
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	leads := make([]*models.Lead, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate leads: %w", err)
+		}
+
+		var lead models.Lead
+		if err := doc.DataTo(&lead); err != nil {
+			return nil, fmt.Errorf("failed to decode lead: %w", err)
+		}
+		if err := DecryptLead(ctx, r.encryptor, &lead); err != nil {
+			return nil, err
+		}
 
 		lead.ID = doc.Ref.ID
 		leads = append(leads, &lead)
@@ -329,6 +570,23 @@ func (r *LeadRepository) ListWithRevokedConsent(ctx context.Context, tenantID st
 	return leads, nil
 }
 
+// UpdateScore stores the lead score and its factors
+// updated_at is kept: rescoring is not lead activity (retention counts inactivity from updated_at)
+func (r *LeadRepository) UpdateScore(ctx context.Context, tenantID, id string, score int, factors []models.LeadScoreFactor, scoredAt time.Time) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if id == "" {
+		return fmt.Errorf("%w: lead ID is required", ErrInvalidInput)
+	}
+
+	return r.UpdateDocument(ctx, r.getLeadsCollection(tenantID), id, []firestore.Update{
+		{Path: "score", Value: score},
+		{Path: "score_factors", Value: factors},
+		{Path: "scored_at", Value: scoredAt},
+	})
+}
+
 // RevokeConsent marks a lead's consent as revoked
 func (r *LeadRepository) RevokeConsent(ctx context.Context, tenantID, id string) error {
 	if tenantID == "" {
@@ -284,6 +284,26 @@ func (r *ListingRepository) ListActive(ctx context.Context, tenantID string, opt
 	return listings, nil
 }
 
+// CountActive returns the number of active listings for a tenant
+func (r *ListingRepository) CountActive(ctx context.Context, tenantID string) (int, error) {
+	if tenantID == "" {
+		return 0, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	collectionPath := r.getListingsCollection(tenantID)
+	query := r.Client().Collection(collectionPath).
+		Where("tenant_id", "==", tenantID).
+		Where("is_active", "==", true)
+
+	// Use Select() to only fetch document IDs for counting (more efficient)
+	docs, err := query.Select().Documents(ctx).GetAll()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count active listings: %w", err)
+	}
+
+	return len(docs), nil
+}
+
 // GetCanonicalForProperty retrieves the canonical listing for a property
 func (r *ListingRepository) GetCanonicalForProperty(ctx context.Context, tenantID, propertyID string) (*models.Listing, error) {
 	if tenantID == "" {
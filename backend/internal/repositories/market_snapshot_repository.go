@@ -0,0 +1,117 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+)
+
+const (
+	marketplaceSnapshotsCollection = "market_snapshots"
+)
+
+// MarketSnapshotRepository handles Firestore operations for market analytics snapshots
+type MarketSnapshotRepository struct {
+	*BaseRepository
+}
+
+// NewMarketSnapshotRepository creates a new market snapshot repository
+func NewMarketSnapshotRepository(client *firestore.Client) *MarketSnapshotRepository {
+	return &MarketSnapshotRepository{
+		BaseRepository: NewBaseRepository(client),
+	}
+}
+
+// getSnapshotsCollection returns the collection path for snapshots of the given scope
+func (r *MarketSnapshotRepository) getSnapshotsCollection(scope models.MarketSnapshotScope, tenantID string) string {
+	if scope == models.MarketSnapshotScopeMarketplace {
+		return marketplaceSnapshotsCollection
+	}
+	return fmt.Sprintf("tenants/%s/market_snapshots", tenantID)
+}
+
+// Save creates or replaces a snapshot (document ID = period, so re-running a job is idempotent)
+func (r *MarketSnapshotRepository) Save(ctx context.Context, snapshot *models.MarketSnapshot) error {
+	if snapshot.Period == "" {
+		return fmt.Errorf("%w: period is required", ErrInvalidInput)
+	}
+	if snapshot.Scope == models.MarketSnapshotScopeTenant && snapshot.TenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required for tenant snapshots", ErrInvalidInput)
+	}
+
+	snapshot.ID = snapshot.Period
+
+	collectionPath := r.getSnapshotsCollection(snapshot.Scope, snapshot.TenantID)
+	if err := r.SetDocument(ctx, collectionPath, snapshot.ID, snapshot); err != nil {
+		return fmt.Errorf("failed to save market snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves a tenant snapshot by period (ex: "2026-10")
+func (r *MarketSnapshotRepository) Get(ctx context.Context, tenantID, period string) (*models.MarketSnapshot, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	return r.get(ctx, r.getSnapshotsCollection(models.MarketSnapshotScopeTenant, tenantID), period)
+}
+
+// GetMarketplace retrieves the anonymized marketplace snapshot by period
+func (r *MarketSnapshotRepository) GetMarketplace(ctx context.Context, period string) (*models.MarketSnapshot, error) {
+	return r.get(ctx, marketplaceSnapshotsCollection, period)
+}
+
+// GetLatest retrieves the most recent tenant snapshot
+func (r *MarketSnapshotRepository) GetLatest(ctx context.Context, tenantID string) (*models.MarketSnapshot, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	return r.getLatest(ctx, r.getSnapshotsCollection(models.MarketSnapshotScopeTenant, tenantID))
+}
+
+// GetLatestMarketplace retrieves the most recent marketplace snapshot
+func (r *MarketSnapshotRepository) GetLatestMarketplace(ctx context.Context) (*models.MarketSnapshot, error) {
+	return r.getLatest(ctx, marketplaceSnapshotsCollection)
+}
+
+func (r *MarketSnapshotRepository) get(ctx context.Context, collectionPath, period string) (*models.MarketSnapshot, error) {
+	var snapshot models.MarketSnapshot
+	if err := r.GetDocument(ctx, collectionPath, period, &snapshot); err != nil {
+		return nil, err
+	}
+
+	snapshot.ID = period
+	return &snapshot, nil
+}
+
+func (r *MarketSnapshotRepository) getLatest(ctx context.Context, collectionPath string) (*models.MarketSnapshot, error) {
+	query := r.Client().Collection(collectionPath).
+		OrderBy("period_start", firestore.Desc).
+		Limit(1)
+
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	doc, err := iter.Next()
+	if err == iterator.Done {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query latest market snapshot: %w", err)
+	}
+
+	var snapshot models.MarketSnapshot
+	if err := doc.DataTo(&snapshot); err != nil {
+		return nil, fmt.Errorf("failed to decode market snapshot: %w", err)
+	}
+
+	snapshot.ID = doc.Ref.ID
+	return &snapshot, nil
+}
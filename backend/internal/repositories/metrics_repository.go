@@ -0,0 +1,123 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+)
+
+// MetricTarget identifies a rollup document to increment
+type MetricTarget struct {
+	Scope    models.MetricScope
+	EntityID string
+}
+
+// MetricsRepository handles Firestore operations for daily engagement rollups
+type MetricsRepository struct {
+	*BaseRepository
+}
+
+// NewMetricsRepository creates a new metrics repository
+func NewMetricsRepository(client *firestore.Client) *MetricsRepository {
+	return &MetricsRepository{
+		BaseRepository: NewBaseRepository(client),
+	}
+}
+
+// getMetricsCollection returns the collection path for daily metrics within a tenant
+func (r *MetricsRepository) getMetricsCollection(tenantID string) string {
+	return fmt.Sprintf("tenants/%s/metrics_daily", tenantID)
+}
+
+// dailyMetricsDocID builds the deterministic rollup document ID
+func dailyMetricsDocID(scope models.MetricScope, entityID, date string) string {
+	return fmt.Sprintf("%s_%s_%s", scope, entityID, strings.ReplaceAll(date, "-", ""))
+}
+
+// Increment atomically increments a counter field on the daily rollup of every target
+// All targets are written in a single batch so property/listing/broker rollups stay consistent
+func (r *MetricsRepository) Increment(ctx context.Context, tenantID, date string, targets []MetricTarget, field string, delta int64) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if date == "" || field == "" {
+		return fmt.Errorf("%w: date and field are required", ErrInvalidInput)
+	}
+
+	collection := r.Client().Collection(r.getMetricsCollection(tenantID))
+	batch := r.Client().Batch()
+	writes := 0
+	for _, target := range targets {
+		if target.EntityID == "" {
+			continue
+		}
+
+		docRef := collection.Doc(dailyMetricsDocID(target.Scope, target.EntityID, date))
+		batch.Set(docRef, map[string]interface{}{
+			"tenant_id":  tenantID,
+			"scope":      string(target.Scope),
+			"entity_id":  target.EntityID,
+			"date":       date,
+			field:        firestore.Increment(delta),
+			"updated_at": time.Now(),
+		}, firestore.MergeAll)
+		writes++
+	}
+
+	if writes == 0 {
+		return nil
+	}
+
+	if _, err := batch.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to increment daily metrics: %w", err)
+	}
+
+	return nil
+}
+
+// ListRange retrieves the daily rollups of an entity between two dates (inclusive, YYYY-MM-DD)
+func (r *MetricsRepository) ListRange(ctx context.Context, tenantID string, scope models.MetricScope, entityID, fromDate, toDate string) ([]*models.DailyMetrics, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if entityID == "" {
+		return nil, fmt.Errorf("%w: entity_id is required", ErrInvalidInput)
+	}
+
+	query := r.Client().Collection(r.getMetricsCollection(tenantID)).
+		Where("scope", "==", string(scope)).
+		Where("entity_id", "==", entityID).
+		Where("date", ">=", fromDate).
+		Where("date", "<=", toDate).
+		OrderBy("date", firestore.Asc)
+
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	results := make([]*models.DailyMetrics, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate daily metrics: %w", err)
+		}
+
+		var metrics models.DailyMetrics
+		if err := doc.DataTo(&metrics); err != nil {
+			return nil, fmt.Errorf("failed to decode daily metrics: %w", err)
+		}
+
+		metrics.ID = doc.Ref.ID
+		results = append(results, &metrics)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,226 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+)
+
+// OutboxRepository handles Firestore operations for the transactional outbox (domain events)
+type OutboxRepository struct {
+	*BaseRepository
+}
+
+// NewOutboxRepository creates a new outbox repository
+func NewOutboxRepository(client *firestore.Client) *OutboxRepository {
+	return &OutboxRepository{
+		BaseRepository: NewBaseRepository(client),
+	}
+}
+
+// getOutboxCollection returns the collection path for outbox events within a tenant
+func (r *OutboxRepository) getOutboxCollection(tenantID string) string {
+	return fmt.Sprintf("tenants/%s/outbox", tenantID)
+}
+
+// Commit applies the domain writes and creates their events in a single Firestore transaction
+// Either everything is committed or nothing is: an event is never lost nor emitted for a failed write
+func (r *OutboxRepository) Commit(ctx context.Context, events []*models.OutboxEvent, writes ...TxWrite) error {
+	for _, event := range events {
+		if event.TenantID == "" {
+			return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+		}
+		if event.EventType == "" {
+			return fmt.Errorf("%w: event_type is required", ErrInvalidInput)
+		}
+		if event.ID == "" {
+			event.ID = r.GenerateID(r.getOutboxCollection(event.TenantID))
+		}
+
+		docRef := r.Client().Collection(r.getOutboxCollection(event.TenantID)).Doc(event.ID)
+		stored := event
+		writes = append(writes, func(ctx context.Context, tx *firestore.Transaction) error {
+			return tx.Create(docRef, stored)
+		})
+	}
+
+	if err := r.RunTransaction(ctx, writes...); err != nil {
+		if err == ErrNotFound || err == ErrAlreadyExists {
+			return err
+		}
+		return fmt.Errorf("failed to commit outbox transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves an outbox event by ID
+func (r *OutboxRepository) Get(ctx context.Context, tenantID, id string) (*models.OutboxEvent, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	var event models.OutboxEvent
+	if err := r.GetDocument(ctx, r.getOutboxCollection(tenantID), id, &event); err != nil {
+		return nil, err
+	}
+
+	event.ID = id
+	return &event, nil
+}
+
+// Claim reserves a pending event for one relay by pushing next_attempt_at forward (lease)
+// Returns nil when the event is not due (claimed by a concurrent relay, published or failed)
+func (r *OutboxRepository) Claim(ctx context.Context, tenantID, id string, now time.Time, lease time.Duration) (*models.OutboxEvent, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	ref := r.Client().Collection(r.getOutboxCollection(tenantID)).Doc(id)
+	var claimed *models.OutboxEvent
+	err := r.Client().RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		claimed = nil
+		doc, err := tx.Get(ref)
+		if err != nil {
+			if status.Code(err) == codes.NotFound {
+				return ErrNotFound
+			}
+			return fmt.Errorf("failed to get outbox event: %w", err)
+		}
+
+		var event models.OutboxEvent
+		if err := doc.DataTo(&event); err != nil {
+			return fmt.Errorf("failed to decode outbox event: %w", err)
+		}
+		if event.Status != models.OutboxEventPending || event.NextAttemptAt.After(now) {
+			return nil
+		}
+
+		event.ID = doc.Ref.ID
+		claimed = &event
+		return tx.Update(ref, []firestore.Update{
+			{Path: "next_attempt_at", Value: now.Add(lease)},
+		})
+	})
+	if err != nil {
+		if err == ErrNotFound {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to claim outbox event: %w", err)
+	}
+
+	return claimed, nil
+}
+
+// MarkConsumed records that a consumer processed an event
+func (r *OutboxRepository) MarkConsumed(ctx context.Context, tenantID, id, consumer string) error {
+	return r.update(ctx, tenantID, id, []firestore.Update{
+		{Path: "consumers", Value: firestore.ArrayUnion(consumer)},
+	})
+}
+
+// MarkPublished marks an event as processed by every consumer
+func (r *OutboxRepository) MarkPublished(ctx context.Context, tenantID, id string) error {
+	return r.update(ctx, tenantID, id, []firestore.Update{
+		{Path: "status", Value: string(models.OutboxEventPublished)},
+		{Path: "published_at", Value: time.Now()},
+		{Path: "last_error", Value: firestore.Delete},
+	})
+}
+
+// RecordFailure records a failed relay attempt (status stays pending until attempts are exhausted)
+func (r *OutboxRepository) RecordFailure(ctx context.Context, tenantID, id string, attempts int, eventStatus models.OutboxEventStatus, nextAttemptAt time.Time, lastError string) error {
+	return r.update(ctx, tenantID, id, []firestore.Update{
+		{Path: "attempts", Value: attempts},
+		{Path: "status", Value: string(eventStatus)},
+		{Path: "next_attempt_at", Value: nextAttemptAt},
+		{Path: "last_error", Value: lastError},
+	})
+}
+
+// Reset makes a failed event pending again (manual reprocessing; consumers already done are skipped)
+func (r *OutboxRepository) Reset(ctx context.Context, tenantID, id string) error {
+	return r.update(ctx, tenantID, id, []firestore.Update{
+		{Path: "status", Value: string(models.OutboxEventPending)},
+		{Path: "attempts", Value: 0},
+		{Path: "next_attempt_at", Value: time.Now()},
+	})
+}
+
+// ListDue lists pending events whose next relay attempt is due (oldest first)
+func (r *OutboxRepository) ListDue(ctx context.Context, tenantID string, now time.Time, limit int) ([]*models.OutboxEvent, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	query := r.Client().Collection(r.getOutboxCollection(tenantID)).
+		Where("status", "==", string(models.OutboxEventPending)).
+		Where("next_attempt_at", "<=", now).
+		OrderBy("next_attempt_at", firestore.Asc).
+		Limit(limit)
+
+	return r.list(ctx, query)
+}
+
+// ListByStatus lists events with a status (newest first)
+func (r *OutboxRepository) ListByStatus(ctx context.Context, tenantID string, eventStatus models.OutboxEventStatus, limit int) ([]*models.OutboxEvent, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	query := r.Client().Collection(r.getOutboxCollection(tenantID)).
+		Where("status", "==", string(eventStatus)).
+		OrderBy("occurred_at", firestore.Desc).
+		Limit(limit)
+
+	return r.list(ctx, query)
+}
+
+// update applies updates to an outbox event
+func (r *OutboxRepository) update(ctx context.Context, tenantID, id string, updates []firestore.Update) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	if err := r.UpdateDocument(ctx, r.getOutboxCollection(tenantID), id, updates); err != nil {
+		if err == ErrNotFound {
+			return err
+		}
+		return fmt.Errorf("failed to update outbox event: %w", err)
+	}
+	return nil
+}
+
+// list runs a query and decodes the events
+func (r *OutboxRepository) list(ctx context.Context, query firestore.Query) ([]*models.OutboxEvent, error) {
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	events := make([]*models.OutboxEvent, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate outbox events: %w", err)
+		}
+
+		var event models.OutboxEvent
+		if err := doc.DataTo(&event); err != nil {
+			return nil, fmt.Errorf("failed to decode outbox event: %w", err)
+		}
+
+		event.ID = doc.Ref.ID
+		events = append(events, &event)
+	}
+
+	return events, nil
+}
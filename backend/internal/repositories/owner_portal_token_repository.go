@@ -0,0 +1,128 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+)
+
+// OwnerPortalTokenRepository handles Firestore operations for owner portal magic links and sessions
+type OwnerPortalTokenRepository struct {
+	*BaseRepository
+}
+
+// NewOwnerPortalTokenRepository creates a new owner portal token repository
+func NewOwnerPortalTokenRepository(client *firestore.Client) *OwnerPortalTokenRepository {
+	return &OwnerPortalTokenRepository{
+		BaseRepository: NewBaseRepository(client),
+	}
+}
+
+// getTokensCollection returns the collection path for owner portal tokens within a tenant
+func (r *OwnerPortalTokenRepository) getTokensCollection(tenantID string) string {
+	return fmt.Sprintf("tenants/%s/owner_portal_tokens", tenantID)
+}
+
+// Create creates a new magic link or session token
+func (r *OwnerPortalTokenRepository) Create(ctx context.Context, token *models.OwnerPortalToken) error {
+	if token.TenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if token.OwnerID == "" {
+		return fmt.Errorf("%w: owner_id is required", ErrInvalidInput)
+	}
+	if token.TokenHash == "" {
+		return fmt.Errorf("%w: token_hash is required", ErrInvalidInput)
+	}
+
+	if token.ID == "" {
+		token.ID = r.GenerateID(r.getTokensCollection(token.TenantID))
+	}
+	token.CreatedAt = time.Now()
+
+	if err := r.CreateDocument(ctx, r.getTokensCollection(token.TenantID), token.ID, token); err != nil {
+		return fmt.Errorf("failed to create owner portal token: %w", err)
+	}
+
+	return nil
+}
+
+// GetByTokenHash retrieves a token of the given type by its hash
+func (r *OwnerPortalTokenRepository) GetByTokenHash(ctx context.Context, tenantID string, tokenType models.OwnerPortalTokenType, tokenHash string) (*models.OwnerPortalToken, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if tokenHash == "" {
+		return nil, fmt.Errorf("%w: token_hash is required", ErrInvalidInput)
+	}
+
+	query := r.Client().Collection(r.getTokensCollection(tenantID)).
+		Where("token_hash", "==", tokenHash).
+		Where("type", "==", string(tokenType)).
+		Limit(1)
+
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	doc, err := iter.Next()
+	if err == iterator.Done {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query owner portal token: %w", err)
+	}
+
+	var token models.OwnerPortalToken
+	if err := doc.DataTo(&token); err != nil {
+		return nil, fmt.Errorf("failed to decode owner portal token: %w", err)
+	}
+
+	token.ID = doc.Ref.ID
+	return &token, nil
+}
+
+// Update updates an owner portal token
+func (r *OwnerPortalTokenRepository) Update(ctx context.Context, tenantID, id string, updates map[string]interface{}) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if id == "" {
+		return fmt.Errorf("%w: token ID is required", ErrInvalidInput)
+	}
+
+	if err := r.UpdateDocument(ctx, r.getTokensCollection(tenantID), id, mapToFirestoreUpdates(updates)); err != nil {
+		return fmt.Errorf("failed to update owner portal token: %w", err)
+	}
+
+	return nil
+}
+
+// MarkUsed atomically consumes a magic link (fails if it was already used)
+func (r *OwnerPortalTokenRepository) MarkUsed(ctx context.Context, tenantID, id string) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	ref := r.Client().Collection(r.getTokensCollection(tenantID)).Doc(id)
+	return r.Client().RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(ref)
+		if err != nil {
+			return fmt.Errorf("failed to get owner portal token: %w", err)
+		}
+
+		var token models.OwnerPortalToken
+		if err := doc.DataTo(&token); err != nil {
+			return fmt.Errorf("failed to decode owner portal token: %w", err)
+		}
+		if token.UsedAt != nil {
+			return fmt.Errorf("%w: magic link already used", ErrInvalidInput)
+		}
+
+		return tx.Update(ref, []firestore.Update{{Path: "used_at", Value: time.Now()}})
+	})
+}
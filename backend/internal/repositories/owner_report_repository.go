@@ -0,0 +1,106 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+)
+
+// OwnerReportRepository handles Firestore operations for monthly owner reports
+type OwnerReportRepository struct {
+	*BaseRepository
+}
+
+// NewOwnerReportRepository creates a new owner report repository
+func NewOwnerReportRepository(client *firestore.Client) *OwnerReportRepository {
+	return &OwnerReportRepository{
+		BaseRepository: NewBaseRepository(client),
+	}
+}
+
+// getOwnerReportsCollection returns the collection path for owner reports within a tenant
+func (r *OwnerReportRepository) getOwnerReportsCollection(tenantID string) string {
+	return fmt.Sprintf("tenants/%s/owner_reports", tenantID)
+}
+
+// ownerReportDocID builds the deterministic report ID ({propertyId}_{YYYYMM})
+func ownerReportDocID(propertyID, period string) string {
+	return fmt.Sprintf("%s_%s", propertyID, strings.ReplaceAll(period, "-", ""))
+}
+
+// Save creates or replaces a report (one per property and period, so regenerating is idempotent)
+func (r *OwnerReportRepository) Save(ctx context.Context, report *models.OwnerReport) error {
+	if report.TenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if report.PropertyID == "" || report.Period == "" {
+		return fmt.Errorf("%w: property_id and period are required", ErrInvalidInput)
+	}
+
+	report.ID = ownerReportDocID(report.PropertyID, report.Period)
+
+	if err := r.SetDocument(ctx, r.getOwnerReportsCollection(report.TenantID), report.ID, report); err != nil {
+		return fmt.Errorf("failed to save owner report: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves the report of a property for a period (ex: "2026-10")
+func (r *OwnerReportRepository) Get(ctx context.Context, tenantID, propertyID, period string) (*models.OwnerReport, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if propertyID == "" || period == "" {
+		return nil, fmt.Errorf("%w: property_id and period are required", ErrInvalidInput)
+	}
+
+	id := ownerReportDocID(propertyID, period)
+
+	var report models.OwnerReport
+	if err := r.GetDocument(ctx, r.getOwnerReportsCollection(tenantID), id, &report); err != nil {
+		return nil, err
+	}
+
+	report.ID = id
+	return &report, nil
+}
+
+// GetLatestForProperty retrieves the most recent report of a property
+func (r *OwnerReportRepository) GetLatestForProperty(ctx context.Context, tenantID, propertyID string) (*models.OwnerReport, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if propertyID == "" {
+		return nil, fmt.Errorf("%w: property_id is required", ErrInvalidInput)
+	}
+
+	query := r.Client().Collection(r.getOwnerReportsCollection(tenantID)).
+		Where("property_id", "==", propertyID).
+		OrderBy("period_start", firestore.Desc).
+		Limit(1)
+
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	doc, err := iter.Next()
+	if err == iterator.Done {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query latest owner report: %w", err)
+	}
+
+	var report models.OwnerReport
+	if err := doc.DataTo(&report); err != nil {
+		return nil, fmt.Errorf("failed to decode owner report: %w", err)
+	}
+
+	report.ID = doc.Ref.ID
+	return &report, nil
+}
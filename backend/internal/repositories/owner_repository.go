@@ -9,11 +9,13 @@ import (
 	"google.golang.org/api/iterator"
 
 	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/pii"
 )
 
 // OwnerRepository handles Firestore operations for property owners
 type OwnerRepository struct {
 	*BaseRepository
+	encryptor *pii.Encryptor // optional: field-level encryption of PII
 }
 
 // NewOwnerRepository creates a new owner repository
@@ -23,6 +25,12 @@ func NewOwnerRepository(client *firestore.Client) *OwnerRepository {
 	}
 }
 
+// SetEncryptor enables field-level encryption of email, phone and document
+// Lookups by these fields then use blind indexes (run cmd/pii-rotate to backfill existing owners)
+func (r *OwnerRepository) SetEncryptor(encryptor *pii.Encryptor) {
+	r.encryptor = encryptor
+}
+
 // getOwnersCollection returns the collection path for owners within a tenant
 func (r *OwnerRepository) getOwnersCollection(tenantID string) string {
 	return fmt.Sprintf("tenants/%s/owners", tenantID)
@@ -42,8 +50,13 @@ func (r *OwnerRepository) Create(ctx context.Context, owner *models.Owner) error
 	owner.CreatedAt = now
 	owner.UpdatedAt = now
 
+	stored, err := EncryptOwner(ctx, r.encryptor, owner)
+	if err != nil {
+		return err
+	}
+
 	collectionPath := r.getOwnersCollection(owner.TenantID)
-	if err := r.CreateDocument(ctx, collectionPath, owner.ID, owner); err != nil {
+	if err := r.CreateDocument(ctx, collectionPath, owner.ID, stored); err != nil {
 		return fmt.Errorf("failed to create owner: %w", err)
 	}
 
@@ -61,6 +74,9 @@ func (r *OwnerRepository) Get(ctx context.Context, tenantID, id string) (*models
 	if err := r.GetDocument(ctx, collectionPath, id, &owner); err != nil {
 		return nil, err
 	}
+	if err := DecryptOwner(ctx, r.encryptor, &owner); err != nil {
+		return nil, err
+	}
 
 	owner.ID = id
 	return &owner, nil
@@ -75,9 +91,10 @@ func (r *OwnerRepository) GetByEmail(ctx context.Context, tenantID, email string
 		return nil, fmt.Errorf("%w: email is required", ErrInvalidInput)
 	}
 
+	lookupField, lookupValue := piiLookup(r.encryptor, "email", pii.FieldEmail, email)
 	collectionPath := r.getOwnersCollection(tenantID)
 	query := r.Client().Collection(collectionPath).
-		Where("email", "==", email).
+		Where(lookupField, "==", lookupValue).
 		Limit(1)
 
 	iter := query.Documents(ctx)
@@ -95,6 +112,47 @@ func (r *OwnerRepository) GetByEmail(ctx context.Context, tenantID, email string
 	if err := doc.DataTo(&owner); err != nil {
 		return nil, fmt.Errorf("failed to decode owner: %w", err)
 	}
+	if err := DecryptOwner(ctx, r.encryptor, &owner); err != nil {
+		return nil, err
+	}
+
+	owner.ID = doc.Ref.ID
+	return &owner, nil
+}
+
+// GetByPhone retrieves an owner by phone (normalized with utils.NormalizePhoneBR)
+func (r *OwnerRepository) GetByPhone(ctx context.Context, tenantID, phone string) (*models.Owner, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if phone == "" {
+		return nil, fmt.Errorf("%w: phone is required", ErrInvalidInput)
+	}
+
+	lookupField, lookupValue := piiLookup(r.encryptor, "phone", pii.FieldPhone, phone)
+	collectionPath := r.getOwnersCollection(tenantID)
+	query := r.Client().Collection(collectionPath).
+		Where(lookupField, "==", lookupValue).
+		Limit(1)
+
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	doc, err := iter.Next()
+	if err == iterator.Done {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query owner by phone: %w", err)
+	}
+
+	var owner models.Owner
+	if err := doc.DataTo(&owner); err != nil {
+		return nil, fmt.Errorf("failed to decode owner: %w", err)
+	}
+	if err := DecryptOwner(ctx, r.encryptor, &owner); err != nil {
+		return nil, err
+	}
 
 	owner.ID = doc.Ref.ID
 	return &owner, nil
@@ -109,9 +167,10 @@ func (r *OwnerRepository) GetByDocument(ctx context.Context, tenantID, document
 		return nil, fmt.Errorf("%w: document is required", ErrInvalidInput)
 	}
 
+	lookupField, lookupValue := piiLookup(r.encryptor, "document", pii.FieldDocument, document)
 	collectionPath := r.getOwnersCollection(tenantID)
 	query := r.Client().Collection(collectionPath).
-		Where("document", "==", document).
+		Where(lookupField, "==", lookupValue).
 		Limit(1)
 
 	iter := query.Documents(ctx)
@@ -129,6 +188,9 @@ func (r *OwnerRepository) GetByDocument(ctx context.Context, tenantID, document
 	if err := doc.DataTo(&owner); err != nil {
 		return nil, fmt.Errorf("failed to decode owner: %w", err)
 	}
+	if err := DecryptOwner(ctx, r.encryptor, &owner); err != nil {
+		return nil, err
+	}
 
 	owner.ID = doc.Ref.ID
 	return &owner, nil
@@ -143,6 +205,10 @@ func (r *OwnerRepository) Update(ctx context.Context, tenantID, id string, updat
 		return fmt.Errorf("%w: owner ID is required", ErrInvalidInput)
 	}
 
+	if err := EncryptPIIUpdates(ctx, r.encryptor, OwnerPIIFields, updates); err != nil {
+		return err
+	}
+
 	// Add updated_at timestamp
 	updates["updated_at"] = time.Now()
 
@@ -207,6 +273,9 @@ func (r *OwnerRepository) List(ctx context.Context, tenantID string, opts Pagina
 		if err := doc.DataTo(&owner); err != nil {
 			return nil, fmt.Errorf("failed to decode owner: %w", err)
 		}
+		if err := DecryptOwner(ctx, r.encryptor, &owner); err != nil {
+			return nil, err
+		}
 
 		owner.ID = doc.Ref.ID
 		owners = append(owners, &owner)
@@ -247,6 +316,9 @@ func (r *OwnerRepository) ListByStatus(ctx context.Context, tenantID string, sta
 		if err := doc.DataTo(&owner); err != nil {
 			return nil, fmt.Errorf("failed to decode owner: %w", err)
 		}
+		if err := DecryptOwner(ctx, r.encryptor, &owner); err != nil {
+			return nil, err
+		}
 
 		owner.ID = doc.Ref.ID
 		owners = append(owners, &owner)
@@ -288,6 +360,9 @@ func (r *OwnerRepository) ListWithoutConsent(ctx context.Context, tenantID strin
 		if err := doc.DataTo(&owner); err != nil {
 			return nil, fmt.Errorf("failed to decode owner: %w", err)
 		}
+		if err := DecryptOwner(ctx, r.encryptor, &owner); err != nil {
+			return nil, err
+		}
 
 		owner.ID = doc.Ref.ID
 		owners = append(owners, &owner)
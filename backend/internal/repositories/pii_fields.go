@@ -0,0 +1,405 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"cloud.google.com/go/firestore"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/pii"
+)
+
+// Encrypted PII fields per collection (Firestore field -> blind index normalization)
+// Each field has a companion "{field}_bidx" holding its blind index
+var (
+	OwnerPIIFields = map[string]pii.FieldKind{
+		"email":    pii.FieldEmail,
+		"phone":    pii.FieldPhone,
+		"document": pii.FieldDocument,
+	}
+	LeadPIIFields = map[string]pii.FieldKind{
+		"email": pii.FieldEmail,
+		"phone": pii.FieldPhone,
+	}
+	ContactPIIFields = map[string]pii.FieldKind{
+		"email": pii.FieldEmail,
+		"phone": pii.FieldPhone,
+	}
+	UserPIIFields = map[string]pii.FieldKind{
+		"document": pii.FieldDocument,
+	}
+	DataSubjectRequestPIIFields = map[string]pii.FieldKind{
+		"subject_email":    pii.FieldEmail,
+		"subject_phone":    pii.FieldPhone,
+		"subject_document": pii.FieldDocument,
+	}
+	// from/to hold a phone or an e-mail depending on the channel
+	ConversationMessagePIIFields = map[string]pii.FieldKind{
+		"from":         pii.FieldName,
+		"to":           pii.FieldName,
+		"contact_name": pii.FieldName,
+	}
+	WhatsAppThreadPIIFields = map[string]pii.FieldKind{
+		"contact_name": pii.FieldName,
+	}
+)
+
+// blindIndexField returns the Firestore field holding the blind index of a PII field
+func blindIndexField(field string) string {
+	return field + "_bidx"
+}
+
+// piiLookup returns the field/value pair used to query a PII field
+// Without an encryptor the plaintext field is queried, as before
+func piiLookup(encryptor *pii.Encryptor, field string, kind pii.FieldKind, value string) (string, string) {
+	if encryptor == nil {
+		return field, value
+	}
+	return blindIndexField(field), encryptor.BlindIndex(kind, value)
+}
+
+// encryptPIIField encrypts a single value and returns it with its blind index
+func encryptPIIField(ctx context.Context, encryptor *pii.Encryptor, kind pii.FieldKind, value string) (string, string, error) {
+	plaintext, err := encryptor.Decrypt(ctx, value)
+	if err != nil {
+		return "", "", err
+	}
+	ciphertext, err := encryptor.Encrypt(ctx, plaintext)
+	if err != nil {
+		return "", "", err
+	}
+	return ciphertext, encryptor.BlindIndex(kind, plaintext), nil
+}
+
+// EncryptPIIUpdates encrypts PII fields present in an update map and adds their blind indexes
+// Non-string values (e.g. firestore.Delete) are left untouched
+func EncryptPIIUpdates(ctx context.Context, encryptor *pii.Encryptor, fields map[string]pii.FieldKind, updates map[string]interface{}) error {
+	if encryptor == nil {
+		return nil
+	}
+
+	for field, kind := range fields {
+		value, ok := updates[field].(string)
+		if !ok {
+			continue
+		}
+
+		ciphertext, index, err := encryptPIIField(ctx, encryptor, kind, value)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt %s: %w", field, err)
+		}
+		updates[field] = ciphertext
+		updates[blindIndexField(field)] = index
+	}
+
+	return nil
+}
+
+// EncryptPIIFirestoreUpdates is EncryptPIIUpdates for code building []firestore.Update directly
+func EncryptPIIFirestoreUpdates(ctx context.Context, encryptor *pii.Encryptor, fields map[string]pii.FieldKind, updates []firestore.Update) ([]firestore.Update, error) {
+	if encryptor == nil {
+		return updates, nil
+	}
+
+	result := make([]firestore.Update, 0, len(updates))
+	for _, update := range updates {
+		kind, isPII := fields[update.Path]
+		value, isString := update.Value.(string)
+		if !isPII || !isString {
+			result = append(result, update)
+			continue
+		}
+
+		ciphertext, index, err := encryptPIIField(ctx, encryptor, kind, value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt %s: %w", update.Path, err)
+		}
+		result = append(result,
+			firestore.Update{Path: update.Path, Value: ciphertext},
+			firestore.Update{Path: blindIndexField(update.Path), Value: index},
+		)
+	}
+
+	return result, nil
+}
+
+// RotatePIIFields computes the updates needed to bring a stored document up to date:
+// plaintext values get encrypted, values wrapped with an old key are re-wrapped with the
+// current key and missing/stale blind indexes are recomputed. Returns nil when nothing changes.
+func RotatePIIFields(ctx context.Context, encryptor *pii.Encryptor, fields map[string]pii.FieldKind, data map[string]interface{}) (map[string]interface{}, error) {
+	updates := make(map[string]interface{})
+
+	for field, kind := range fields {
+		value, ok := data[field].(string)
+		if !ok || value == "" {
+			continue
+		}
+
+		plaintext, err := encryptor.Decrypt(ctx, value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt %s: %w", field, err)
+		}
+
+		if encryptor.NeedsRotation(value) {
+			ciphertext, err := encryptor.Encrypt(ctx, plaintext)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encrypt %s: %w", field, err)
+			}
+			updates[field] = ciphertext
+		}
+		if kind == pii.FieldName {
+			continue // Never looked up: no blind index
+		}
+
+		index := encryptor.BlindIndex(kind, plaintext)
+		if current, _ := data[blindIndexField(field)].(string); current != index {
+			updates[blindIndexField(field)] = index
+		}
+	}
+
+	if len(updates) == 0 {
+		return nil, nil
+	}
+	return updates, nil
+}
+
+// EncryptOwner returns a copy of the owner with PII fields encrypted and blind indexes set
+func EncryptOwner(ctx context.Context, encryptor *pii.Encryptor, owner *models.Owner) (*models.Owner, error) {
+	stored := *owner
+	if encryptor == nil {
+		return &stored, nil
+	}
+
+	var err error
+	if stored.Email, stored.EmailIndex, err = encryptPIIField(ctx, encryptor, pii.FieldEmail, owner.Email); err != nil {
+		return nil, fmt.Errorf("failed to encrypt owner email: %w", err)
+	}
+	if stored.Phone, stored.PhoneIndex, err = encryptPIIField(ctx, encryptor, pii.FieldPhone, owner.Phone); err != nil {
+		return nil, fmt.Errorf("failed to encrypt owner phone: %w", err)
+	}
+	if stored.Document, stored.DocumentIndex, err = encryptPIIField(ctx, encryptor, pii.FieldDocument, owner.Document); err != nil {
+		return nil, fmt.Errorf("failed to encrypt owner document: %w", err)
+	}
+	return &stored, nil
+}
+
+// DecryptOwner decrypts the owner's PII fields in place
+func DecryptOwner(ctx context.Context, encryptor *pii.Encryptor, owner *models.Owner) error {
+	if encryptor == nil {
+		return nil
+	}
+
+	var err error
+	if owner.Email, err = encryptor.Decrypt(ctx, owner.Email); err != nil {
+		return fmt.Errorf("failed to decrypt owner email: %w", err)
+	}
+	if owner.Phone, err = encryptor.Decrypt(ctx, owner.Phone); err != nil {
+		return fmt.Errorf("failed to decrypt owner phone: %w", err)
+	}
+	if owner.Document, err = encryptor.Decrypt(ctx, owner.Document); err != nil {
+		return fmt.Errorf("failed to decrypt owner document: %w", err)
+	}
+	return nil
+}
+
+// EncryptLead returns a copy of the lead with PII fields encrypted and blind indexes set
+func EncryptLead(ctx context.Context, encryptor *pii.Encryptor, lead *models.Lead) (*models.Lead, error) {
+	stored := *lead
+	if encryptor == nil {
+		return &stored, nil
+	}
+
+	var err error
+	if stored.Email, stored.EmailIndex, err = encryptPIIField(ctx, encryptor, pii.FieldEmail, lead.Email); err != nil {
+		return nil, fmt.Errorf("failed to encrypt lead email: %w", err)
+	}
+	if stored.Phone, stored.PhoneIndex, err = encryptPIIField(ctx, encryptor, pii.FieldPhone, lead.Phone); err != nil {
+		return nil, fmt.Errorf("failed to encrypt lead phone: %w", err)
+	}
+	return &stored, nil
+}
+
+// DecryptLead decrypts the lead's PII fields in place
+func DecryptLead(ctx context.Context, encryptor *pii.Encryptor, lead *models.Lead) error {
+	if encryptor == nil {
+		return nil
+	}
+
+	var err error
+	if lead.Email, err = encryptor.Decrypt(ctx, lead.Email); err != nil {
+		return fmt.Errorf("failed to decrypt lead email: %w", err)
+	}
+	if lead.Phone, err = encryptor.Decrypt(ctx, lead.Phone); err != nil {
+		return fmt.Errorf("failed to decrypt lead phone: %w", err)
+	}
+	return nil
+}
+
+// EncryptContact returns a copy of the contact with PII fields encrypted and blind indexes set
+func EncryptContact(ctx context.Context, encryptor *pii.Encryptor, contact *models.Contact) (*models.Contact, error) {
+	stored := *contact
+	if encryptor == nil {
+		return &stored, nil
+	}
+
+	var err error
+	if stored.Email, stored.EmailIndex, err = encryptPIIField(ctx, encryptor, pii.FieldEmail, contact.Email); err != nil {
+		return nil, fmt.Errorf("failed to encrypt contact email: %w", err)
+	}
+	if stored.Phone, stored.PhoneIndex, err = encryptPIIField(ctx, encryptor, pii.FieldPhone, contact.Phone); err != nil {
+		return nil, fmt.Errorf("failed to encrypt contact phone: %w", err)
+	}
+	return &stored, nil
+}
+
+// DecryptContact decrypts the contact's PII fields in place
+func DecryptContact(ctx context.Context, encryptor *pii.Encryptor, contact *models.Contact) error {
+	if encryptor == nil {
+		return nil
+	}
+
+	var err error
+	if contact.Email, err = encryptor.Decrypt(ctx, contact.Email); err != nil {
+		return fmt.Errorf("failed to decrypt contact email: %w", err)
+	}
+	if contact.Phone, err = encryptor.Decrypt(ctx, contact.Phone); err != nil {
+		return fmt.Errorf("failed to decrypt contact phone: %w", err)
+	}
+	return nil
+}
+
+// EncryptUser returns a copy of the user with PII fields encrypted and blind indexes set
+func EncryptUser(ctx context.Context, encryptor *pii.Encryptor, user *models.User) (*models.User, error) {
+	stored := *user
+	if encryptor == nil {
+		return &stored, nil
+	}
+
+	var err error
+	if stored.Document, stored.DocumentIndex, err = encryptPIIField(ctx, encryptor, pii.FieldDocument, user.Document); err != nil {
+		return nil, fmt.Errorf("failed to encrypt user document: %w", err)
+	}
+	return &stored, nil
+}
+
+// DecryptUser decrypts the user's PII fields in place
+func DecryptUser(ctx context.Context, encryptor *pii.Encryptor, user *models.User) error {
+	if encryptor == nil {
+		return nil
+	}
+
+	var err error
+	if user.Document, err = encryptor.Decrypt(ctx, user.Document); err != nil {
+		return fmt.Errorf("failed to decrypt user document: %w", err)
+	}
+	return nil
+}
+
+// EncryptDataSubjectRequest returns a copy of the request with the subject's contact data encrypted
+// Requests are never looked up by these fields, so no blind index is kept on the model
+func EncryptDataSubjectRequest(ctx context.Context, encryptor *pii.Encryptor, request *models.DataSubjectRequest) (*models.DataSubjectRequest, error) {
+	stored := *request
+	if encryptor == nil {
+		return &stored, nil
+	}
+
+	var err error
+	if stored.SubjectEmail, err = encryptor.Encrypt(ctx, request.SubjectEmail); err != nil {
+		return nil, fmt.Errorf("failed to encrypt subject email: %w", err)
+	}
+	if stored.SubjectPhone, err = encryptor.Encrypt(ctx, request.SubjectPhone); err != nil {
+		return nil, fmt.Errorf("failed to encrypt subject phone: %w", err)
+	}
+	if stored.SubjectDocument, err = encryptor.Encrypt(ctx, request.SubjectDocument); err != nil {
+		return nil, fmt.Errorf("failed to encrypt subject document: %w", err)
+	}
+	return &stored, nil
+}
+
+// DecryptDataSubjectRequest decrypts the subject's contact data in place
+func DecryptDataSubjectRequest(ctx context.Context, encryptor *pii.Encryptor, request *models.DataSubjectRequest) error {
+	if encryptor == nil {
+		return nil
+	}
+
+	var err error
+	if request.SubjectEmail, err = encryptor.Decrypt(ctx, request.SubjectEmail); err != nil {
+		return fmt.Errorf("failed to decrypt subject email: %w", err)
+	}
+	if request.SubjectPhone, err = encryptor.Decrypt(ctx, request.SubjectPhone); err != nil {
+		return fmt.Errorf("failed to decrypt subject phone: %w", err)
+	}
+	if request.SubjectDocument, err = encryptor.Decrypt(ctx, request.SubjectDocument); err != nil {
+		return fmt.Errorf("failed to decrypt subject document: %w", err)
+	}
+	return nil
+}
+
+// EncryptConversationMessage returns a copy of the message with the sender/recipient encrypted
+// Messages are never looked up by these fields, so no blind index is kept on the model
+func EncryptConversationMessage(ctx context.Context, encryptor *pii.Encryptor, message *models.ConversationMessage) (*models.ConversationMessage, error) {
+	stored := *message
+	if encryptor == nil {
+		return &stored, nil
+	}
+
+	var err error
+	if stored.From, err = encryptor.Encrypt(ctx, message.From); err != nil {
+		return nil, fmt.Errorf("failed to encrypt message sender: %w", err)
+	}
+	if stored.To, err = encryptor.Encrypt(ctx, message.To); err != nil {
+		return nil, fmt.Errorf("failed to encrypt message recipient: %w", err)
+	}
+	if stored.ContactName, err = encryptor.Encrypt(ctx, message.ContactName); err != nil {
+		return nil, fmt.Errorf("failed to encrypt message contact name: %w", err)
+	}
+	return &stored, nil
+}
+
+// DecryptConversationMessage decrypts the sender/recipient of a message in place
+func DecryptConversationMessage(ctx context.Context, encryptor *pii.Encryptor, message *models.ConversationMessage) error {
+	if encryptor == nil {
+		return nil
+	}
+
+	var err error
+	if message.From, err = encryptor.Decrypt(ctx, message.From); err != nil {
+		return fmt.Errorf("failed to decrypt message sender: %w", err)
+	}
+	if message.To, err = encryptor.Decrypt(ctx, message.To); err != nil {
+		return fmt.Errorf("failed to decrypt message recipient: %w", err)
+	}
+	if message.ContactName, err = encryptor.Decrypt(ctx, message.ContactName); err != nil {
+		return fmt.Errorf("failed to decrypt message contact name: %w", err)
+	}
+	return nil
+}
+
+// EncryptWhatsAppThread returns a copy of the thread with the contact name encrypted
+// (the WhatsApp ID is not stored: the document ID is its phone blind index)
+func EncryptWhatsAppThread(ctx context.Context, encryptor *pii.Encryptor, thread *models.WhatsAppThread) (*models.WhatsAppThread, error) {
+	stored := *thread
+	if encryptor == nil {
+		return &stored, nil
+	}
+
+	var err error
+	if stored.ContactName, err = encryptor.Encrypt(ctx, thread.ContactName); err != nil {
+		return nil, fmt.Errorf("failed to encrypt thread contact name: %w", err)
+	}
+	return &stored, nil
+}
+
+// DecryptWhatsAppThread decrypts the thread's contact name in place
+func DecryptWhatsAppThread(ctx context.Context, encryptor *pii.Encryptor, thread *models.WhatsAppThread) error {
+	if encryptor == nil {
+		return nil
+	}
+
+	var err error
+	if thread.ContactName, err = encryptor.Decrypt(ctx, thread.ContactName); err != nil {
+		return fmt.Errorf("failed to decrypt thread contact name: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+)
+
+// PropertyDocumentRepository handles Firestore operations for property documents
+type PropertyDocumentRepository struct {
+	*BaseRepository
+}
+
+// NewPropertyDocumentRepository creates a new property document repository
+func NewPropertyDocumentRepository(client *firestore.Client) *PropertyDocumentRepository {
+	return &PropertyDocumentRepository{
+		BaseRepository: NewBaseRepository(client),
+	}
+}
+
+// getDocumentsCollection returns the collection path for property documents within a tenant
+func (r *PropertyDocumentRepository) getDocumentsCollection(tenantID string) string {
+	return fmt.Sprintf("tenants/%s/property_documents", tenantID)
+}
+
+// Create creates a new property document record
+func (r *PropertyDocumentRepository) Create(ctx context.Context, document *models.PropertyDocument) error {
+	if document.TenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if document.PropertyID == "" {
+		return fmt.Errorf("%w: property_id is required", ErrInvalidInput)
+	}
+
+	if document.ID == "" {
+		document.ID = r.GenerateID(r.getDocumentsCollection(document.TenantID))
+	}
+
+	now := time.Now()
+	document.CreatedAt = now
+	document.UpdatedAt = now
+
+	if err := r.CreateDocument(ctx, r.getDocumentsCollection(document.TenantID), document.ID, document); err != nil {
+		return fmt.Errorf("failed to create property document: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves a property document by ID
+func (r *PropertyDocumentRepository) Get(ctx context.Context, tenantID, id string) (*models.PropertyDocument, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	var document models.PropertyDocument
+	if err := r.GetDocument(ctx, r.getDocumentsCollection(tenantID), id, &document); err != nil {
+		return nil, err
+	}
+
+	document.ID = id
+	return &document, nil
+}
+
+// ListByProperty retrieves all documents of a property (newest first)
+func (r *PropertyDocumentRepository) ListByProperty(ctx context.Context, tenantID, propertyID string) ([]*models.PropertyDocument, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if propertyID == "" {
+		return nil, fmt.Errorf("%w: property_id is required", ErrInvalidInput)
+	}
+
+	query := r.Client().Collection(r.getDocumentsCollection(tenantID)).
+		Where("property_id", "==", propertyID).
+		OrderBy("created_at", firestore.Desc)
+
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	documents := make([]*models.PropertyDocument, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate property documents: %w", err)
+		}
+
+		var document models.PropertyDocument
+		if err := doc.DataTo(&document); err != nil {
+			return nil, fmt.Errorf("failed to decode property document: %w", err)
+		}
+
+		document.ID = doc.Ref.ID
+		documents = append(documents, &document)
+	}
+
+	return documents, nil
+}
+
+// Update updates a property document record
+func (r *PropertyDocumentRepository) Update(ctx context.Context, tenantID, id string, updates map[string]interface{}) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if id == "" {
+		return fmt.Errorf("%w: document ID is required", ErrInvalidInput)
+	}
+
+	updates["updated_at"] = time.Now()
+
+	if err := r.UpdateDocument(ctx, r.getDocumentsCollection(tenantID), id, mapToFirestoreUpdates(updates)); err != nil {
+		return fmt.Errorf("failed to update property document: %w", err)
+	}
+
+	return nil
+}
+
+// Delete deletes a property document record
+func (r *PropertyDocumentRepository) Delete(ctx context.Context, tenantID, id string) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	if err := r.DeleteDocument(ctx, r.getDocumentsCollection(tenantID), id); err != nil {
+		return fmt.Errorf("failed to delete property document: %w", err)
+	}
+
+	return nil
+}
+
+// ListExpiringBefore retrieves documents whose expiry date is before the given time (soonest first)
+func (r *PropertyDocumentRepository) ListExpiringBefore(ctx context.Context, tenantID string, before time.Time) ([]*models.PropertyDocument, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	query := r.Client().Collection(r.getDocumentsCollection(tenantID)).
+		Where("expires_at", "<", before).
+		OrderBy("expires_at", firestore.Asc)
+
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	documents := make([]*models.PropertyDocument, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate property documents: %w", err)
+		}
+
+		var document models.PropertyDocument
+		if err := doc.DataTo(&document); err != nil {
+			return nil, fmt.Errorf("failed to decode property document: %w", err)
+		}
+
+		document.ID = doc.Ref.ID
+		documents = append(documents, &document)
+	}
+
+	return documents, nil
+}
@@ -46,6 +46,33 @@ type PropertyFilters struct {
 
 // Create creates a new property
 func (r *PropertyRepository) Create(ctx context.Context, property *models.Property) error {
+	if err := r.prepareCreate(property); err != nil {
+		return err
+	}
+
+	// Store in root collection with tenant_id field
+	if err := r.CreateDocument(ctx, "properties", property.ID, property); err != nil {
+		return fmt.Errorf("failed to create property: %w", err)
+	}
+
+	return nil
+}
+
+// CreateTx returns the write of Create for a transaction (transactional outbox)
+// The property ID is assigned immediately so the domain event can reference it
+func (r *PropertyRepository) CreateTx(property *models.Property) (TxWrite, error) {
+	if err := r.prepareCreate(property); err != nil {
+		return nil, err
+	}
+
+	docRef := r.Client().Collection("properties").Doc(property.ID)
+	return func(ctx context.Context, tx *firestore.Transaction) error {
+		return tx.Create(docRef, property)
+	}, nil
+}
+
+// prepareCreate validates a new property and sets its ID and timestamps
+func (r *PropertyRepository) prepareCreate(property *models.Property) error {
 	if property.TenantID == "" {
 		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
 	}
@@ -58,11 +85,6 @@ func (r *PropertyRepository) Create(ctx context.Context, property *models.Proper
 	property.CreatedAt = now
 	property.UpdatedAt = now
 
-	// Store in root collection with tenant_id field
-	if err := r.CreateDocument(ctx, "properties", property.ID, property); err != nil {
-		return fmt.Errorf("failed to create property: %w", err)
-	}
-
 	return nil
 }
 
@@ -185,13 +207,74 @@ func (r *PropertyRepository) GetByExternalID(ctx context.Context, tenantID, exte
 	return &property, nil
 }
 
+// GetByReference retrieves a property by its reference code (ex: "AP00335")
+func (r *PropertyRepository) GetByReference(ctx context.Context, tenantID, reference string) (*models.Property, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if reference == "" {
+		return nil, fmt.Errorf("%w: reference is required", ErrInvalidInput)
+	}
+
+	query := r.Client().Collection("properties").
+		Where("tenant_id", "==", tenantID).
+		Where("reference", "==", reference).
+		Limit(1)
+
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	doc, err := iter.Next()
+	if err == iterator.Done {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query property by reference: %w", err)
+	}
+
+	var property models.Property
+	if err := doc.DataTo(&property); err != nil {
+		return nil, fmt.Errorf("failed to decode property: %w", err)
+	}
+
+	property.ID = doc.Ref.ID
+	return &property, nil
+}
+
 // Update updates a property
 func (r *PropertyRepository) Update(ctx context.Context, tenantID, id string, updates map[string]interface{}) error {
+	firestoreUpdates, err := r.prepareUpdate(tenantID, id, updates)
+	if err != nil {
+		return err
+	}
+
+	if err := r.UpdateDocument(ctx, "properties", id, firestoreUpdates); err != nil {
+		return fmt.Errorf("failed to update property: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateTx returns the write of Update for a transaction (ErrNotFound when the property does not exist)
+func (r *PropertyRepository) UpdateTx(tenantID, id string, updates map[string]interface{}) (TxWrite, error) {
+	firestoreUpdates, err := r.prepareUpdate(tenantID, id, updates)
+	if err != nil {
+		return nil, err
+	}
+
+	docRef := r.Client().Collection("properties").Doc(id)
+	return func(ctx context.Context, tx *firestore.Transaction) error {
+		return tx.Update(docRef, firestoreUpdates)
+	}, nil
+}
+
+// prepareUpdate converts the updates to Firestore updates
+func (r *PropertyRepository) prepareUpdate(tenantID, id string, updates map[string]interface{}) ([]firestore.Update, error) {
 	if tenantID == "" {
-		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
 	}
 	if id == "" {
-		return fmt.Errorf("%w: property ID is required", ErrInvalidInput)
+		return nil, fmt.Errorf("%w: property ID is required", ErrInvalidInput)
 	}
 
 	// Add updated_at timestamp
@@ -206,11 +289,7 @@ func (r *PropertyRepository) Update(ctx context.Context, tenantID, id string, up
 		})
 	}
 
-	if err := r.UpdateDocument(ctx, "properties", id, firestoreUpdates); err != nil {
-		return fmt.Errorf("failed to update property: %w", err)
-	}
-
-	return nil
+	return firestoreUpdates, nil
 }
 
 // Delete deletes a property
@@ -225,6 +304,21 @@ func (r *PropertyRepository) Delete(ctx context.Context, tenantID, id string) er
 	return nil
 }
 
+// DeleteTx returns the write of Delete for a transaction (ErrNotFound when the property does not exist)
+func (r *PropertyRepository) DeleteTx(tenantID, id string) (TxWrite, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if id == "" {
+		return nil, fmt.Errorf("%w: property ID is required", ErrInvalidInput)
+	}
+
+	docRef := r.Client().Collection("properties").Doc(id)
+	return func(ctx context.Context, tx *firestore.Transaction) error {
+		return tx.Delete(docRef, firestore.Exists)
+	}, nil
+}
+
 // List retrieves properties for a tenant with optional filters and pagination
 func (r *PropertyRepository) List(ctx context.Context, tenantID string, filters *PropertyFilters, opts PaginationOptions) ([]*models.Property, error) {
 	if tenantID == "" {
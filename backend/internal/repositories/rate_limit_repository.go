@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+)
+
+// rateLimitShards is how many documents share the writes of a rate limit counter
+// A single Firestore document sustains about one write per second, so the hot keys of a window
+// (one tenant or IP) are spread over several shard documents and summed on read
+const rateLimitShards = 16
+
+// rateLimitCounter is a rate limit counter shard document
+type rateLimitCounter struct {
+	Count     int64     `firestore:"count"`
+	ExpiresAt time.Time `firestore:"expires_at"` // configure a Firestore TTL policy on this field
+}
+
+// RateLimitRepository is a Firestore counter backend shared by every replica for rate limiting
+// Collection: /rate_limits/{key}_{shard}
+type RateLimitRepository struct {
+	*BaseRepository
+	shards int
+}
+
+// NewRateLimitRepository creates a new rate limit repository
+func NewRateLimitRepository(client *firestore.Client) *RateLimitRepository {
+	return &RateLimitRepository{
+		BaseRepository: NewBaseRepository(client),
+		shards:         rateLimitShards,
+	}
+}
+
+// Increment increments a counter and returns its new value
+// The increment is a blind write (no transaction) on a random shard; the value is the sum of the
+// unexpired shards read right after, so concurrent requests may observe the same value
+// Counters are not reset in place: keys must be unique per window (CounterRateLimitStore appends the
+// window start) and expire ttl after the first increment
+func (r *RateLimitRepository) Increment(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	if key == "" {
+		return 0, fmt.Errorf("%w: key is required", ErrInvalidInput)
+	}
+
+	now := time.Now()
+	refs := r.shardRefs(key)
+	shard := refs[rand.Intn(len(refs))]
+
+	// The window-keyed callers pass the same expiry on every increment, so merging it is idempotent
+	_, err := shard.Set(ctx, map[string]interface{}{
+		"count":      firestore.Increment(1),
+		"expires_at": now.Add(ttl),
+	}, firestore.MergeAll)
+	if err != nil {
+		return 0, fmt.Errorf("failed to increment rate limit counter: %w", err)
+	}
+
+	docs, err := r.Client().GetAll(ctx, refs)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read rate limit counter: %w", err)
+	}
+
+	var count int64
+	for _, doc := range docs {
+		if !doc.Exists() {
+			continue
+		}
+		var stored rateLimitCounter
+		if err := doc.DataTo(&stored); err != nil {
+			return 0, fmt.Errorf("failed to decode rate limit counter: %w", err)
+		}
+		// TTL deletion is lazy, so expired shards are ignored on read
+		if stored.ExpiresAt.After(now) {
+			count += stored.Count
+		}
+	}
+
+	return count, nil
+}
+
+// shardRefs returns the shard documents of a counter
+func (r *RateLimitRepository) shardRefs(key string) []*firestore.DocumentRef {
+	id := strings.ReplaceAll(key, "/", "_")
+	refs := make([]*firestore.DocumentRef, r.shards)
+	for i := range refs {
+		refs[i] = r.Client().Collection("rate_limits").Doc(fmt.Sprintf("%s_%d", id, i))
+	}
+	return refs
+}
@@ -0,0 +1,124 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+)
+
+// RetentionRunRepository handles Firestore operations for retention sweeps
+type RetentionRunRepository struct {
+	*BaseRepository
+}
+
+// NewRetentionRunRepository creates a new retention run repository
+func NewRetentionRunRepository(client *firestore.Client) *RetentionRunRepository {
+	return &RetentionRunRepository{
+		BaseRepository: NewBaseRepository(client),
+	}
+}
+
+// getRunsCollection returns the collection path for retention runs within a tenant
+func (r *RetentionRunRepository) getRunsCollection(tenantID string) string {
+	return fmt.Sprintf("tenants/%s/retention_runs", tenantID)
+}
+
+// Create records a retention run
+func (r *RetentionRunRepository) Create(ctx context.Context, run *models.RetentionRun) error {
+	if run.TenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	if run.ID == "" {
+		run.ID = r.GenerateID(r.getRunsCollection(run.TenantID))
+	}
+
+	if err := r.CreateDocument(ctx, r.getRunsCollection(run.TenantID), run.ID, run); err != nil {
+		return fmt.Errorf("failed to create retention run: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves a retention run by ID
+func (r *RetentionRunRepository) Get(ctx context.Context, tenantID, id string) (*models.RetentionRun, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if id == "" {
+		return nil, fmt.Errorf("%w: run ID is required", ErrInvalidInput)
+	}
+
+	var run models.RetentionRun
+	if err := r.GetDocument(ctx, r.getRunsCollection(tenantID), id, &run); err != nil {
+		return nil, err
+	}
+
+	run.ID = id
+	return &run, nil
+}
+
+// List retrieves the most recent retention runs
+func (r *RetentionRunRepository) List(ctx context.Context, tenantID string, limit int) ([]*models.RetentionRun, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	query := r.Client().Collection(r.getRunsCollection(tenantID)).
+		OrderBy("started_at", firestore.Desc).
+		Limit(limit)
+
+	return r.list(ctx, query)
+}
+
+// GetLastApplied retrieves the most recent non dry-run sweep (nil when none)
+func (r *RetentionRunRepository) GetLastApplied(ctx context.Context, tenantID string) (*models.RetentionRun, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	query := r.Client().Collection(r.getRunsCollection(tenantID)).
+		Where("dry_run", "==", false).
+		OrderBy("started_at", firestore.Desc).
+		Limit(1)
+
+	runs, err := r.list(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(runs) == 0 {
+		return nil, nil
+	}
+	return runs[0], nil
+}
+
+// list runs a query and decodes the retention runs
+func (r *RetentionRunRepository) list(ctx context.Context, query firestore.Query) ([]*models.RetentionRun, error) {
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	runs := make([]*models.RetentionRun, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate retention runs: %w", err)
+		}
+
+		var run models.RetentionRun
+		if err := doc.DataTo(&run); err != nil {
+			return nil, fmt.Errorf("failed to decode retention run: %w", err)
+		}
+
+		run.ID = doc.Ref.ID
+		runs = append(runs, &run)
+	}
+
+	return runs, nil
+}
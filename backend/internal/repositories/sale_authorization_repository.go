@@ -0,0 +1,147 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+)
+
+// SaleAuthorizationRepository handles Firestore operations for sale authorizations
+type SaleAuthorizationRepository struct {
+	*BaseRepository
+}
+
+// NewSaleAuthorizationRepository creates a new sale authorization repository
+func NewSaleAuthorizationRepository(client *firestore.Client) *SaleAuthorizationRepository {
+	return &SaleAuthorizationRepository{
+		BaseRepository: NewBaseRepository(client),
+	}
+}
+
+// getAuthorizationsCollection returns the collection path for sale authorizations within a tenant
+func (r *SaleAuthorizationRepository) getAuthorizationsCollection(tenantID string) string {
+	return fmt.Sprintf("tenants/%s/sale_authorizations", tenantID)
+}
+
+// Create creates a new sale authorization
+func (r *SaleAuthorizationRepository) Create(ctx context.Context, authorization *models.SaleAuthorization) error {
+	if authorization.TenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if authorization.PropertyID == "" {
+		return fmt.Errorf("%w: property_id is required", ErrInvalidInput)
+	}
+
+	if authorization.ID == "" {
+		authorization.ID = r.GenerateID(r.getAuthorizationsCollection(authorization.TenantID))
+	}
+
+	now := time.Now()
+	authorization.CreatedAt = now
+	authorization.UpdatedAt = now
+
+	if err := r.CreateDocument(ctx, r.getAuthorizationsCollection(authorization.TenantID), authorization.ID, authorization); err != nil {
+		return fmt.Errorf("failed to create sale authorization: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves a sale authorization by ID
+func (r *SaleAuthorizationRepository) Get(ctx context.Context, tenantID, id string) (*models.SaleAuthorization, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if id == "" {
+		return nil, fmt.Errorf("%w: authorization ID is required", ErrInvalidInput)
+	}
+
+	var authorization models.SaleAuthorization
+	if err := r.GetDocument(ctx, r.getAuthorizationsCollection(tenantID), id, &authorization); err != nil {
+		return nil, err
+	}
+
+	authorization.ID = id
+	return &authorization, nil
+}
+
+// Update updates a sale authorization
+func (r *SaleAuthorizationRepository) Update(ctx context.Context, tenantID, id string, updates map[string]interface{}) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if id == "" {
+		return fmt.Errorf("%w: authorization ID is required", ErrInvalidInput)
+	}
+
+	updates["updated_at"] = time.Now()
+
+	if err := r.UpdateDocument(ctx, r.getAuthorizationsCollection(tenantID), id, mapToFirestoreUpdates(updates)); err != nil {
+		return fmt.Errorf("failed to update sale authorization: %w", err)
+	}
+
+	return nil
+}
+
+// ListByProperty retrieves the authorizations of a property (most recent end date first)
+func (r *SaleAuthorizationRepository) ListByProperty(ctx context.Context, tenantID, propertyID string) ([]*models.SaleAuthorization, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if propertyID == "" {
+		return nil, fmt.Errorf("%w: property_id is required", ErrInvalidInput)
+	}
+
+	query := r.Client().Collection(r.getAuthorizationsCollection(tenantID)).
+		Where("property_id", "==", propertyID).
+		OrderBy("end_date", firestore.Desc)
+
+	return r.list(ctx, query)
+}
+
+// ListActiveEndingBefore retrieves active authorizations whose end date is before the given time
+// Used by the expiry job (alerts and expiration)
+func (r *SaleAuthorizationRepository) ListActiveEndingBefore(ctx context.Context, tenantID string, before time.Time) ([]*models.SaleAuthorization, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	query := r.Client().Collection(r.getAuthorizationsCollection(tenantID)).
+		Where("status", "==", string(models.SaleAuthorizationStatusActive)).
+		Where("end_date", "<", before).
+		OrderBy("end_date", firestore.Asc)
+
+	return r.list(ctx, query)
+}
+
+// list runs a query and decodes the sale authorizations
+func (r *SaleAuthorizationRepository) list(ctx context.Context, query firestore.Query) ([]*models.SaleAuthorization, error) {
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	authorizations := make([]*models.SaleAuthorization, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate sale authorizations: %w", err)
+		}
+
+		var authorization models.SaleAuthorization
+		if err := doc.DataTo(&authorization); err != nil {
+			return nil, fmt.Errorf("failed to decode sale authorization: %w", err)
+		}
+
+		authorization.ID = doc.Ref.ID
+		authorizations = append(authorizations, &authorization)
+	}
+
+	return authorizations, nil
+}
@@ -216,3 +216,41 @@ func (r *ScheduledConfirmationRepository) ListByTenant(ctx context.Context, tena
 
 	return results, nil
 }
+
+// ListByOwner retrieves all scheduled confirmations sent to an owner (LGPD data subject requests)
+func (r *ScheduledConfirmationRepository) ListByOwner(ctx context.Context, tenantID, ownerID string) ([]*models.ScheduledConfirmation, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if ownerID == "" {
+		return nil, fmt.Errorf("%w: owner_id is required", ErrInvalidInput)
+	}
+
+	query := r.Client().Collection("scheduled_confirmations").
+		Where("tenant_id", "==", tenantID).
+		Where("owner_id", "==", ownerID)
+
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	var results []*models.ScheduledConfirmation
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate scheduled confirmations by owner: %w", err)
+		}
+
+		var sc models.ScheduledConfirmation
+		if err := doc.DataTo(&sc); err != nil {
+			return nil, fmt.Errorf("failed to decode scheduled confirmation: %w", err)
+		}
+
+		sc.ID = doc.Ref.ID
+		results = append(results, &sc)
+	}
+
+	return results, nil
+}
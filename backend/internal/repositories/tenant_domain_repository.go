@@ -0,0 +1,239 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+)
+
+// tenantDomainsCollection is the root collection of custom domain claims (document ID = {tenant_id}_{hostname})
+const tenantDomainsCollection = "tenant_domains"
+
+// TenantDomainRepository handles Firestore operations for tenant custom domains
+type TenantDomainRepository struct {
+	*BaseRepository
+}
+
+// NewTenantDomainRepository creates a new tenant domain repository
+func NewTenantDomainRepository(client *firestore.Client) *TenantDomainRepository {
+	return &TenantDomainRepository{
+		BaseRepository: NewBaseRepository(client),
+	}
+}
+
+// Create registers a tenant's claim of a domain; fails with ErrAlreadyExists when the tenant already claimed the hostname
+// Other tenants may hold pending claims of the same hostname (see Verify)
+func (r *TenantDomainRepository) Create(ctx context.Context, domain *models.TenantDomain) error {
+	if domain.TenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if domain.Hostname == "" {
+		return fmt.Errorf("%w: hostname is required", ErrInvalidInput)
+	}
+
+	now := time.Now()
+	domain.CreatedAt = now
+	domain.UpdatedAt = now
+
+	if err := r.CreateDocument(ctx, tenantDomainsCollection, models.TenantDomainDocID(domain.TenantID, domain.Hostname), domain); err != nil {
+		return fmt.Errorf("failed to create tenant domain: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves a tenant's claim of a hostname
+func (r *TenantDomainRepository) Get(ctx context.Context, tenantID, hostname string) (*models.TenantDomain, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if hostname == "" {
+		return nil, fmt.Errorf("%w: hostname is required", ErrInvalidInput)
+	}
+
+	var domain models.TenantDomain
+	if err := r.GetDocument(ctx, tenantDomainsCollection, models.TenantDomainDocID(tenantID, hostname), &domain); err != nil {
+		return nil, err
+	}
+
+	return &domain, nil
+}
+
+// GetVerified retrieves the verified claim of a hostname (ErrNotFound when no tenant verified it)
+func (r *TenantDomainRepository) GetVerified(ctx context.Context, hostname string) (*models.TenantDomain, error) {
+	if hostname == "" {
+		return nil, fmt.Errorf("%w: hostname is required", ErrInvalidInput)
+	}
+
+	iter := r.Client().Collection(tenantDomainsCollection).
+		Where("hostname", "==", hostname).
+		Where("status", "==", models.DomainStatusVerified).
+		Limit(1).
+		Documents(ctx)
+	defer iter.Stop()
+
+	doc, err := iter.Next()
+	if err == iterator.Done {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tenant domains: %w", err)
+	}
+
+	var domain models.TenantDomain
+	if err := doc.DataTo(&domain); err != nil {
+		return nil, fmt.Errorf("failed to decode tenant domain: %w", err)
+	}
+
+	return &domain, nil
+}
+
+// Update updates a tenant's claim of a hostname
+func (r *TenantDomainRepository) Update(ctx context.Context, tenantID, hostname string, updates []firestore.Update) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if hostname == "" {
+		return fmt.Errorf("%w: hostname is required", ErrInvalidInput)
+	}
+
+	updates = append(updates, firestore.Update{Path: "updated_at", Value: time.Now()})
+
+	if err := r.UpdateDocument(ctx, tenantDomainsCollection, models.TenantDomainDocID(tenantID, hostname), updates); err != nil {
+		return fmt.Errorf("failed to update tenant domain: %w", err)
+	}
+
+	return nil
+}
+
+// Verify marks a tenant's claim as verified and removes the other tenants' pending claims of the hostname,
+// in a single transaction so that a hostname has at most one verified claim
+// Fails with ErrAlreadyExists when another tenant holds the verified claim
+func (r *TenantDomainRepository) Verify(ctx context.Context, tenantID, hostname string, verifiedAt time.Time) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if hostname == "" {
+		return fmt.Errorf("%w: hostname is required", ErrInvalidInput)
+	}
+
+	collection := r.Client().Collection(tenantDomainsCollection)
+	ownRef := collection.Doc(models.TenantDomainDocID(tenantID, hostname))
+
+	err := r.Client().RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		docs, err := tx.Documents(collection.Where("hostname", "==", hostname)).GetAll()
+		if err != nil {
+			return fmt.Errorf("failed to query tenant domains: %w", err)
+		}
+
+		others := make([]*firestore.DocumentRef, 0, len(docs))
+		for _, doc := range docs {
+			if doc.Ref.ID == ownRef.ID {
+				continue
+			}
+			if status, _ := doc.Data()["status"].(string); status == string(models.DomainStatusVerified) {
+				return ErrAlreadyExists
+			}
+			others = append(others, doc.Ref)
+		}
+
+		if err := tx.Update(ownRef, []firestore.Update{
+			{Path: "status", Value: models.DomainStatusVerified},
+			{Path: "verified_at", Value: verifiedAt},
+			{Path: "expires_at", Value: firestore.Delete},
+			{Path: "updated_at", Value: time.Now()},
+		}); err != nil {
+			return err
+		}
+		for _, ref := range others {
+			if err := tx.Delete(ref); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, ErrAlreadyExists) {
+			return ErrAlreadyExists
+		}
+		return fmt.Errorf("failed to verify tenant domain: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a tenant's claim of a hostname
+func (r *TenantDomainRepository) Delete(ctx context.Context, tenantID, hostname string) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if hostname == "" {
+		return fmt.Errorf("%w: hostname is required", ErrInvalidInput)
+	}
+
+	return r.DeleteDocument(ctx, tenantDomainsCollection, models.TenantDomainDocID(tenantID, hostname))
+}
+
+// DeleteExpiredClaims removes the expired pending claims of a hostname (by any tenant)
+func (r *TenantDomainRepository) DeleteExpiredClaims(ctx context.Context, hostname string, now time.Time) (int, error) {
+	domains, err := r.listWhere(ctx, "hostname", hostname)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	for _, domain := range domains {
+		if !domain.IsExpired(now) {
+			continue
+		}
+		if err := r.Delete(ctx, domain.TenantID, domain.Hostname); err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+
+	return deleted, nil
+}
+
+// ListByTenant retrieves the domain claims of a tenant
+func (r *TenantDomainRepository) ListByTenant(ctx context.Context, tenantID string) ([]*models.TenantDomain, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	return r.listWhere(ctx, "tenant_id", tenantID)
+}
+
+// listWhere retrieves the domain claims matching an equality filter
+func (r *TenantDomainRepository) listWhere(ctx context.Context, field, value string) ([]*models.TenantDomain, error) {
+	iter := r.Client().Collection(tenantDomainsCollection).
+		Where(field, "==", value).
+		Documents(ctx)
+	defer iter.Stop()
+
+	domains := make([]*models.TenantDomain, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate tenant domains: %w", err)
+		}
+
+		var domain models.TenantDomain
+		if err := doc.DataTo(&domain); err != nil {
+			return nil, fmt.Errorf("failed to decode tenant domain: %w", err)
+		}
+
+		domains = append(domains, &domain)
+	}
+
+	return domains, nil
+}
@@ -85,6 +85,36 @@ func (r *TenantRepository) GetBySlug(ctx context.Context, slug string) (*models.
 	return &tenant, nil
 }
 
+// GetByWhatsAppPhoneNumberID retrieves the tenant whose WhatsApp Cloud API business number has the given phone number ID
+func (r *TenantRepository) GetByWhatsAppPhoneNumberID(ctx context.Context, phoneNumberID string) (*models.Tenant, error) {
+	if phoneNumberID == "" {
+		return nil, fmt.Errorf("%w: phone_number_id is required", ErrInvalidInput)
+	}
+
+	query := r.Client().Collection(tenantsCollection).
+		Where("settings."+models.TenantSettingWhatsAppPhoneNumberID, "==", phoneNumberID).
+		Limit(1)
+
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	doc, err := iter.Next()
+	if err == iterator.Done {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tenant by whatsapp phone number id: %w", err)
+	}
+
+	var tenant models.Tenant
+	if err := doc.DataTo(&tenant); err != nil {
+		return nil, fmt.Errorf("failed to decode tenant: %w", err)
+	}
+
+	tenant.ID = doc.Ref.ID
+	return &tenant, nil
+}
+
 // Update updates a tenant
 func (r *TenantRepository) Update(ctx context.Context, id string, updates map[string]interface{}) error {
 	if id == "" {
@@ -186,3 +216,34 @@ func (r *TenantRepository) ListActive(ctx context.Context, opts PaginationOption
 
 	return tenants, nil
 }
+
+// ListTrialsEndingBefore retrieves the tenants in trial whose trial ended at or before the given time
+func (r *TenantRepository) ListTrialsEndingBefore(ctx context.Context, before time.Time) ([]*models.Tenant, error) {
+	query := r.Client().Collection(tenantsCollection).
+		Where("subscription_status", "==", models.SubscriptionStatusTrial).
+		Where("trial_ends_at", "<=", before)
+
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	tenants := make([]*models.Tenant, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate trial tenants: %w", err)
+		}
+
+		var tenant models.Tenant
+		if err := doc.DataTo(&tenant); err != nil {
+			return nil, fmt.Errorf("failed to decode tenant: %w", err)
+		}
+
+		tenant.ID = doc.Ref.ID
+		tenants = append(tenants, &tenant)
+	}
+
+	return tenants, nil
+}
@@ -0,0 +1,103 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+)
+
+// UsageRepository handles Firestore operations for the daily usage rollups of billing
+type UsageRepository struct {
+	*BaseRepository
+}
+
+// NewUsageRepository creates a new usage repository
+func NewUsageRepository(client *firestore.Client) *UsageRepository {
+	return &UsageRepository{
+		BaseRepository: NewBaseRepository(client),
+	}
+}
+
+// getUsageCollection returns the collection path for daily usage within a tenant
+func (r *UsageRepository) getUsageCollection(tenantID string) string {
+	return fmt.Sprintf("tenants/%s/usage_daily", tenantID)
+}
+
+// Increment atomically increments a usage counter on the rollup of a day (YYYY-MM-DD)
+func (r *UsageRepository) Increment(ctx context.Context, tenantID, date, metric string, delta int64) error {
+	return r.write(ctx, tenantID, date, map[string]interface{}{
+		metric: firestore.Increment(delta),
+	})
+}
+
+// SetGauges sets usage gauges (ex: active listings, storage bytes) on the rollup of a day (YYYY-MM-DD)
+func (r *UsageRepository) SetGauges(ctx context.Context, tenantID, date string, gauges map[string]int64) error {
+	fields := make(map[string]interface{}, len(gauges))
+	for metric, value := range gauges {
+		fields[metric] = value
+	}
+	return r.write(ctx, tenantID, date, fields)
+}
+
+// write merges fields into the rollup of a day
+func (r *UsageRepository) write(ctx context.Context, tenantID, date string, fields map[string]interface{}) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if date == "" || len(fields) == 0 {
+		return fmt.Errorf("%w: date and metrics are required", ErrInvalidInput)
+	}
+
+	fields["tenant_id"] = tenantID
+	fields["date"] = date
+	fields["updated_at"] = time.Now()
+
+	docRef := r.Client().Collection(r.getUsageCollection(tenantID)).Doc(strings.ReplaceAll(date, "-", ""))
+	if _, err := docRef.Set(ctx, fields, firestore.MergeAll); err != nil {
+		return fmt.Errorf("failed to record usage: %w", err)
+	}
+
+	return nil
+}
+
+// ListRange retrieves the daily usage rollups between two dates (inclusive, YYYY-MM-DD)
+func (r *UsageRepository) ListRange(ctx context.Context, tenantID, fromDate, toDate string) ([]*models.UsageDaily, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	query := r.Client().Collection(r.getUsageCollection(tenantID)).
+		Where("date", ">=", fromDate).
+		Where("date", "<=", toDate).
+		OrderBy("date", firestore.Asc)
+
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	results := make([]*models.UsageDaily, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate daily usage: %w", err)
+		}
+
+		var usage models.UsageDaily
+		if err := doc.DataTo(&usage); err != nil {
+			return nil, fmt.Errorf("failed to decode daily usage: %w", err)
+		}
+
+		usage.ID = doc.Ref.ID
+		results = append(results, &usage)
+	}
+
+	return results, nil
+}
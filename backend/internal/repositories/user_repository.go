@@ -7,6 +7,7 @@ import (
 
 	"cloud.google.com/go/firestore"
 	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/pii"
 	"google.golang.org/api/iterator"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -14,7 +15,8 @@ import (
 
 // UserRepository handles database operations for administrative users
 type UserRepository struct {
-	client *firestore.Client
+	client    *firestore.Client
+	encryptor *pii.Encryptor // optional: field-level encryption of PII
 }
 
 // NewUserRepository creates a new user repository
@@ -24,6 +26,11 @@ func NewUserRepository(client *firestore.Client) *UserRepository {
 	}
 }
 
+// SetEncryptor enables field-level encryption of the user's document (CPF/CNPJ)
+func (r *UserRepository) SetEncryptor(encryptor *pii.Encryptor) {
+	r.encryptor = encryptor
+}
+
 // Create creates a new user
 func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
 	if user.ID == "" {
@@ -36,8 +43,13 @@ func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
 	user.CreatedAt = time.Now()
 	user.UpdatedAt = time.Now()
 
+	stored, err := EncryptUser(ctx, r.encryptor, user)
+	if err != nil {
+		return err
+	}
+
 	collection := r.getUsersCollection(user.TenantID)
-	_, err := collection.Doc(user.ID).Set(ctx, user)
+	_, err = collection.Doc(user.ID).Set(ctx, stored)
 	return err
 }
 
@@ -63,6 +75,9 @@ func (r *UserRepository) Get(ctx context.Context, tenantID, userID string) (*mod
 	if err := doc.DataTo(&user); err != nil {
 		return nil, fmt.Errorf("failed to parse user: %w", err)
 	}
+	if err := DecryptUser(ctx, r.encryptor, &user); err != nil {
+		return nil, err
+	}
 
 	user.ID = doc.Ref.ID
 	return &user, nil
@@ -95,6 +110,9 @@ func (r *UserRepository) GetByEmail(ctx context.Context, tenantID, email string)
 	if err := doc.DataTo(&user); err != nil {
 		return nil, fmt.Errorf("failed to parse user: %w", err)
 	}
+	if err := DecryptUser(ctx, r.encryptor, &user); err != nil {
+		return nil, err
+	}
 
 	user.ID = doc.Ref.ID
 	return &user, nil
@@ -127,6 +145,9 @@ func (r *UserRepository) GetByFirebaseUID(ctx context.Context, tenantID, firebas
 	if err := doc.DataTo(&user); err != nil {
 		return nil, fmt.Errorf("failed to parse user: %w", err)
 	}
+	if err := DecryptUser(ctx, r.encryptor, &user); err != nil {
+		return nil, err
+	}
 
 	user.ID = doc.Ref.ID
 	return &user, nil
@@ -156,6 +177,9 @@ func (r *UserRepository) List(ctx context.Context, tenantID string) ([]*models.U
 		if err := doc.DataTo(&user); err != nil {
 			return nil, fmt.Errorf("failed to parse user: %w", err)
 		}
+		if err := DecryptUser(ctx, r.encryptor, &user); err != nil {
+			return nil, err
+		}
 
 		user.ID = doc.Ref.ID
 		users = append(users, &user)
@@ -173,6 +197,10 @@ func (r *UserRepository) Update(ctx context.Context, tenantID, userID string, up
 		return fmt.Errorf("user ID is required")
 	}
 
+	if err := EncryptPIIUpdates(ctx, r.encryptor, UserPIIFields, updates); err != nil {
+		return err
+	}
+
 	updates["updated_at"] = time.Now()
 
 	collection := r.getUsersCollection(tenantID)
@@ -231,6 +259,9 @@ func (r *UserRepository) ListByRole(ctx context.Context, tenantID, role string)
 		if err := doc.DataTo(&user); err != nil {
 			return nil, fmt.Errorf("failed to parse user: %w", err)
 		}
+		if err := DecryptUser(ctx, r.encryptor, &user); err != nil {
+			return nil, err
+		}
 
 		user.ID = doc.Ref.ID
 		users = append(users, &user)
@@ -265,6 +296,9 @@ func (r *UserRepository) ListActive(ctx context.Context, tenantID string) ([]*mo
 		if err := doc.DataTo(&user); err != nil {
 			return nil, fmt.Errorf("failed to parse user: %w", err)
 		}
+		if err := DecryptUser(ctx, r.encryptor, &user); err != nil {
+			return nil, err
+		}
 
 		user.ID = doc.Ref.ID
 		users = append(users, &user)
@@ -0,0 +1,366 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+)
+
+// WebhookRepository handles Firestore operations for webhook subscriptions, the delivery queue and the dispatcher cursor
+type WebhookRepository struct {
+	*BaseRepository
+}
+
+// NewWebhookRepository creates a new webhook repository
+func NewWebhookRepository(client *firestore.Client) *WebhookRepository {
+	return &WebhookRepository{
+		BaseRepository: NewBaseRepository(client),
+	}
+}
+
+// getSubscriptionsCollection returns the collection path for webhook subscriptions within a tenant
+func (r *WebhookRepository) getSubscriptionsCollection(tenantID string) string {
+	return fmt.Sprintf("tenants/%s/webhook_subscriptions", tenantID)
+}
+
+// getDeliveriesCollection returns the collection path for webhook deliveries within a tenant
+func (r *WebhookRepository) getDeliveriesCollection(tenantID string) string {
+	return fmt.Sprintf("tenants/%s/webhook_deliveries", tenantID)
+}
+
+// getDispatchStateRef returns the dispatcher cursor document of a tenant
+func (r *WebhookRepository) getDispatchStateRef(tenantID string) *firestore.DocumentRef {
+	return r.Client().Collection(fmt.Sprintf("tenants/%s/webhook_state", tenantID)).Doc("dispatcher")
+}
+
+// CreateSubscription creates a new webhook subscription
+func (r *WebhookRepository) CreateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error {
+	if subscription.TenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	collectionPath := r.getSubscriptionsCollection(subscription.TenantID)
+	if subscription.ID == "" {
+		subscription.ID = r.GenerateID(collectionPath)
+	}
+
+	now := time.Now()
+	subscription.CreatedAt = now
+	subscription.UpdatedAt = now
+
+	if err := r.CreateDocument(ctx, collectionPath, subscription.ID, subscription); err != nil {
+		return fmt.Errorf("failed to create webhook subscription: %w", err)
+	}
+
+	return nil
+}
+
+// GetSubscription retrieves a webhook subscription by ID
+func (r *WebhookRepository) GetSubscription(ctx context.Context, tenantID, id string) (*models.WebhookSubscription, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	var subscription models.WebhookSubscription
+	if err := r.GetDocument(ctx, r.getSubscriptionsCollection(tenantID), id, &subscription); err != nil {
+		return nil, err
+	}
+
+	subscription.ID = id
+	return &subscription, nil
+}
+
+// UpdateSubscription updates a webhook subscription
+func (r *WebhookRepository) UpdateSubscription(ctx context.Context, tenantID, id string, updates []firestore.Update) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	updates = append(updates, firestore.Update{Path: "updated_at", Value: time.Now()})
+
+	if err := r.UpdateDocument(ctx, r.getSubscriptionsCollection(tenantID), id, updates); err != nil {
+		if err == ErrNotFound {
+			return err
+		}
+		return fmt.Errorf("failed to update webhook subscription: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteSubscription deletes a webhook subscription (its delivery logs are kept)
+func (r *WebhookRepository) DeleteSubscription(ctx context.Context, tenantID, id string) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	if err := r.DeleteDocument(ctx, r.getSubscriptionsCollection(tenantID), id); err != nil {
+		if err == ErrNotFound {
+			return err
+		}
+		return fmt.Errorf("failed to delete webhook subscription: %w", err)
+	}
+
+	return nil
+}
+
+// ListSubscriptions lists the webhook subscriptions of a tenant
+// activeOnly restricts the list to subscriptions that receive events
+func (r *WebhookRepository) ListSubscriptions(ctx context.Context, tenantID string, activeOnly bool) ([]*models.WebhookSubscription, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	query := r.Client().Collection(r.getSubscriptionsCollection(tenantID)).Query
+	if activeOnly {
+		query = query.Where("active", "==", true)
+	}
+
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	subscriptions := make([]*models.WebhookSubscription, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate webhook subscriptions: %w", err)
+		}
+
+		var subscription models.WebhookSubscription
+		if err := doc.DataTo(&subscription); err != nil {
+			return nil, fmt.Errorf("failed to decode webhook subscription: %w", err)
+		}
+
+		subscription.ID = doc.Ref.ID
+		subscriptions = append(subscriptions, &subscription)
+	}
+
+	return subscriptions, nil
+}
+
+// CreateDelivery queues a delivery (ErrAlreadyExists if the event was already queued for the subscription)
+func (r *WebhookRepository) CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	if delivery.TenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if delivery.SubscriptionID == "" {
+		return fmt.Errorf("%w: subscription_id is required", ErrInvalidInput)
+	}
+
+	collectionPath := r.getDeliveriesCollection(delivery.TenantID)
+	if delivery.ID == "" {
+		delivery.ID = r.GenerateID(collectionPath)
+	}
+
+	now := time.Now()
+	delivery.CreatedAt = now
+	delivery.UpdatedAt = now
+
+	if err := r.CreateDocument(ctx, collectionPath, delivery.ID, delivery); err != nil {
+		if err == ErrAlreadyExists {
+			return err
+		}
+		return fmt.Errorf("failed to create webhook delivery: %w", err)
+	}
+
+	return nil
+}
+
+// GetDelivery retrieves a webhook delivery by ID
+func (r *WebhookRepository) GetDelivery(ctx context.Context, tenantID, id string) (*models.WebhookDelivery, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	var delivery models.WebhookDelivery
+	if err := r.GetDocument(ctx, r.getDeliveriesCollection(tenantID), id, &delivery); err != nil {
+		return nil, err
+	}
+
+	delivery.ID = id
+	return &delivery, nil
+}
+
+// UpdateDelivery updates a webhook delivery (attempt results)
+func (r *WebhookRepository) UpdateDelivery(ctx context.Context, tenantID, id string, updates []firestore.Update) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	updates = append(updates, firestore.Update{Path: "updated_at", Value: time.Now()})
+
+	if err := r.UpdateDocument(ctx, r.getDeliveriesCollection(tenantID), id, updates); err != nil {
+		if err == ErrNotFound {
+			return err
+		}
+		return fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+
+	return nil
+}
+
+// ClaimDelivery reserves a due delivery for one sender by pushing next_attempt_at forward (lease)
+// Returns false when the delivery is no longer due (already claimed, delivered or dead-lettered)
+func (r *WebhookRepository) ClaimDelivery(ctx context.Context, tenantID, id string, now time.Time, lease time.Duration) (bool, error) {
+	if tenantID == "" {
+		return false, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	ref := r.Client().Collection(r.getDeliveriesCollection(tenantID)).Doc(id)
+	claimed := false
+	err := r.Client().RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		claimed = false
+		doc, err := tx.Get(ref)
+		if err != nil {
+			if status.Code(err) == codes.NotFound {
+				return ErrNotFound
+			}
+			return fmt.Errorf("failed to get webhook delivery: %w", err)
+		}
+
+		var delivery models.WebhookDelivery
+		if err := doc.DataTo(&delivery); err != nil {
+			return fmt.Errorf("failed to decode webhook delivery: %w", err)
+		}
+		if delivery.Status != models.WebhookDeliveryPending && delivery.Status != models.WebhookDeliveryRetrying {
+			return nil
+		}
+		if delivery.NextAttemptAt.After(now) {
+			return nil
+		}
+
+		claimed = true
+		return tx.Update(ref, []firestore.Update{
+			{Path: "next_attempt_at", Value: now.Add(lease)},
+			{Path: "updated_at", Value: now},
+		})
+	})
+	if err != nil {
+		if err == ErrNotFound {
+			return false, err
+		}
+		return false, fmt.Errorf("failed to claim webhook delivery: %w", err)
+	}
+
+	return claimed, nil
+}
+
+// WebhookDeliveryFilters contains filters for listing deliveries
+type WebhookDeliveryFilters struct {
+	SubscriptionID string
+	Status         models.WebhookDeliveryStatus
+}
+
+// ListDeliveries lists webhook deliveries (newest first)
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, tenantID string, filters *WebhookDeliveryFilters, opts PaginationOptions) ([]*models.WebhookDelivery, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	query := r.Client().Collection(r.getDeliveriesCollection(tenantID)).Query
+	if filters != nil {
+		if filters.SubscriptionID != "" {
+			query = query.Where("subscription_id", "==", filters.SubscriptionID)
+		}
+		if filters.Status != "" {
+			query = query.Where("status", "==", string(filters.Status))
+		}
+	}
+
+	if opts.OrderBy == "" {
+		opts.OrderBy = "created_at"
+		opts.Direction = firestore.Desc
+	}
+	query = r.ApplyPagination(query, opts)
+
+	return r.listDeliveries(ctx, query)
+}
+
+// ListDueDeliveries lists pending and retrying deliveries whose next attempt is due
+func (r *WebhookRepository) ListDueDeliveries(ctx context.Context, tenantID string, now time.Time, limit int) ([]*models.WebhookDelivery, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	query := r.Client().Collection(r.getDeliveriesCollection(tenantID)).
+		Where("status", "in", []string{string(models.WebhookDeliveryPending), string(models.WebhookDeliveryRetrying)}).
+		Where("next_attempt_at", "<=", now).
+		OrderBy("next_attempt_at", firestore.Asc).
+		Limit(limit)
+
+	return r.listDeliveries(ctx, query)
+}
+
+// GetDispatchState returns the dispatcher cursor (nil when the dispatcher never ran)
+func (r *WebhookRepository) GetDispatchState(ctx context.Context, tenantID string) (*models.WebhookDispatchState, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	doc, err := r.getDispatchStateRef(tenantID).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get webhook dispatch state: %w", err)
+	}
+
+	var state models.WebhookDispatchState
+	if err := doc.DataTo(&state); err != nil {
+		return nil, fmt.Errorf("failed to decode webhook dispatch state: %w", err)
+	}
+	return &state, nil
+}
+
+// SetDispatchState saves the dispatcher cursor
+func (r *WebhookRepository) SetDispatchState(ctx context.Context, tenantID string, lastSequence int64) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	state := &models.WebhookDispatchState{
+		LastSequence: lastSequence,
+		UpdatedAt:    time.Now(),
+	}
+	if _, err := r.getDispatchStateRef(tenantID).Set(ctx, state); err != nil {
+		return fmt.Errorf("failed to save webhook dispatch state: %w", err)
+	}
+	return nil
+}
+
+// listDeliveries runs a query and decodes the deliveries
+func (r *WebhookRepository) listDeliveries(ctx context.Context, query firestore.Query) ([]*models.WebhookDelivery, error) {
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	deliveries := make([]*models.WebhookDelivery, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate webhook deliveries: %w", err)
+		}
+
+		var delivery models.WebhookDelivery
+		if err := doc.DataTo(&delivery); err != nil {
+			return nil, fmt.Errorf("failed to decode webhook delivery: %w", err)
+		}
+
+		delivery.ID = doc.Ref.ID
+		deliveries = append(deliveries, &delivery)
+	}
+
+	return deliveries, nil
+}
@@ -0,0 +1,75 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+)
+
+// whatsAppLinksCollection is the root collection of WhatsApp short links (document ID = code)
+const whatsAppLinksCollection = "whatsapp_links"
+
+// WhatsAppLinkRepository handles Firestore operations for WhatsApp click-tracking short links
+type WhatsAppLinkRepository struct {
+	*BaseRepository
+}
+
+// NewWhatsAppLinkRepository creates a new WhatsApp link repository
+func NewWhatsAppLinkRepository(client *firestore.Client) *WhatsAppLinkRepository {
+	return &WhatsAppLinkRepository{
+		BaseRepository: NewBaseRepository(client),
+	}
+}
+
+// Create stores a short link; fails with ErrAlreadyExists when the code is taken
+func (r *WhatsAppLinkRepository) Create(ctx context.Context, link *models.WhatsAppLink) error {
+	if link.Code == "" {
+		return fmt.Errorf("%w: code is required", ErrInvalidInput)
+	}
+	if link.TenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	link.CreatedAt = time.Now()
+
+	if err := r.CreateDocument(ctx, whatsAppLinksCollection, link.Code, link); err != nil {
+		return fmt.Errorf("failed to create whatsapp link: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves a short link by code
+func (r *WhatsAppLinkRepository) Get(ctx context.Context, code string) (*models.WhatsAppLink, error) {
+	if code == "" {
+		return nil, fmt.Errorf("%w: code is required", ErrInvalidInput)
+	}
+
+	var link models.WhatsAppLink
+	if err := r.GetDocument(ctx, whatsAppLinksCollection, code, &link); err != nil {
+		return nil, err
+	}
+
+	link.Code = code
+	return &link, nil
+}
+
+// RecordClick atomically increments the clicks of a short link
+func (r *WhatsAppLinkRepository) RecordClick(ctx context.Context, code string) error {
+	if code == "" {
+		return fmt.Errorf("%w: code is required", ErrInvalidInput)
+	}
+
+	if err := r.UpdateDocument(ctx, whatsAppLinksCollection, code, []firestore.Update{
+		{Path: "clicks", Value: firestore.Increment(1)},
+		{Path: "last_clicked_at", Value: time.Now()},
+	}); err != nil {
+		return fmt.Errorf("failed to record whatsapp link click: %w", err)
+	}
+
+	return nil
+}
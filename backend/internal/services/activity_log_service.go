@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/sha256"
 	"fmt"
+	"log"
 	"time"
 
 	"github.com/altatech/ecosistema-imob/backend/internal/models"
@@ -362,3 +363,14 @@ func (s *ActivityLogService) generateEventHash(log *models.ActivityLog) string {
 	hash := sha256.Sum256([]byte(combined))
 	return fmt.Sprintf("%x", hash)
 }
+
+// appendActivityLog appends an entry to the activity log and logs the failure
+// Most callers do not fail the operation they audit when the entry is lost, so the failure is reported
+// here (and metered by repositories.ActivityLogAppendFailures) instead of being silently dropped
+func appendActivityLog(ctx context.Context, writer ActivityLogWriter, entry *models.ActivityLog) error {
+	err := writer.Create(ctx, entry)
+	if err != nil && err != repositories.ErrAlreadyExists {
+		log.Printf("⚠️  Activity log %s not appended for tenant %s: %v", entry.EventType, entry.TenantID, err)
+	}
+	return err
+}
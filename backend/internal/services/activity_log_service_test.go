@@ -0,0 +1,36 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// failingActivityLogWriter fails every append with err
+type failingActivityLogWriter struct {
+	err error
+}
+
+func (w *failingActivityLogWriter) Create(ctx context.Context, log *models.ActivityLog) error {
+	return w.err
+}
+
+func TestAppendActivityLogReturnsTheFailure(t *testing.T) {
+	entry := &models.ActivityLog{TenantID: "tenant-1", EventType: "lead_status_changed"}
+
+	contended := fmt.Errorf("failed to create activity log: %w", repositories.ErrAuditChainContention)
+	err := appendActivityLog(context.Background(), &failingActivityLogWriter{err: contended}, entry)
+	assert.ErrorIs(t, err, repositories.ErrAuditChainContention)
+
+	err = appendActivityLog(context.Background(), &failingActivityLogWriter{err: repositories.ErrAlreadyExists}, entry)
+	assert.ErrorIs(t, err, repositories.ErrAlreadyExists)
+
+	writer := NewMockActivityLogRepository()
+	assert.NoError(t, appendActivityLog(context.Background(), writer, entry))
+	assert.Len(t, writer.logs, 1)
+}
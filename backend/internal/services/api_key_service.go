@@ -0,0 +1,362 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// ErrInvalidAPIKey is returned when an API key is unknown, malformed, revoked or expired
+var ErrInvalidAPIKey = errors.New("invalid or expired api key")
+
+const (
+	// apiKeyUsageInterval throttles the last-used writes of a key (one write per interval)
+	apiKeyUsageInterval = time.Minute
+
+	// maxAPIKeyRateLimitPerMinute bounds the per-key rate limit a tenant can configure
+	maxAPIKeyRateLimitPerMinute = 6000
+)
+
+// APIKeyService manages tenant-issued API keys for server-to-server integrations
+// Keys are shown once on creation; only their SHA-256 is stored, and the prefix identifies them
+// Key lifecycle, mutating requests and denied requests are recorded in the activity log
+type APIKeyService struct {
+	apiKeyRepo      *repositories.APIKeyRepository
+	activityLogRepo *repositories.ActivityLogRepository
+	planService     *PlanService // Optional: API access depends on the subscription plan
+}
+
+// NewAPIKeyService creates a new API key service
+func NewAPIKeyService(
+	apiKeyRepo *repositories.APIKeyRepository,
+	activityLogRepo *repositories.ActivityLogRepository,
+) *APIKeyService {
+	return &APIKeyService{
+		apiKeyRepo:      apiKeyRepo,
+		activityLogRepo: activityLogRepo,
+	}
+}
+
+// SetPlanService sets the plan service (for dependency injection)
+func (s *APIKeyService) SetPlanService(service *PlanService) {
+	s.planService = service
+}
+
+// CreateAPIKeyInput represents a new API key
+type CreateAPIKeyInput struct {
+	TenantID           string
+	Name               string
+	Scopes             []string
+	ExpiresAt          *time.Time
+	RateLimitPerMinute int
+	CreatedBy          string
+}
+
+// UpdateAPIKeyInput represents an API key update (nil fields are unchanged)
+type UpdateAPIKeyInput struct {
+	Name               *string
+	Scopes             []string
+	ExpiresAt          *time.Time
+	RateLimitPerMinute *int
+}
+
+// CreateKey issues a new API key and returns it with the plaintext key
+// The plaintext key is only returned here
+func (s *APIKeyService) CreateKey(ctx context.Context, input CreateAPIKeyInput) (*models.APIKey, string, error) {
+	if input.TenantID == "" {
+		return nil, "", fmt.Errorf("tenant_id is required")
+	}
+	if strings.TrimSpace(input.Name) == "" {
+		return nil, "", fmt.Errorf("name is required")
+	}
+	if err := validateAPIKeyScopes(input.Scopes); err != nil {
+		return nil, "", err
+	}
+	if input.ExpiresAt != nil && !input.ExpiresAt.After(time.Now()) {
+		return nil, "", fmt.Errorf("expires_at must be in the future")
+	}
+	if err := validateAPIKeyRateLimit(input.RateLimitPerMinute); err != nil {
+		return nil, "", err
+	}
+	if s.planService != nil {
+		if err := s.planService.CheckFeature(ctx, input.TenantID, models.PlanFeatureAPIAccess); err != nil {
+			return nil, "", err
+		}
+	}
+
+	prefix, token, err := s.generateUniqueKey(ctx, input.TenantID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	key := &models.APIKey{
+		TenantID:           input.TenantID,
+		Name:               strings.TrimSpace(input.Name),
+		Prefix:             prefix,
+		Scopes:             input.Scopes,
+		KeyHash:            models.HashAPIKey(token),
+		RateLimitPerMinute: input.RateLimitPerMinute,
+		ExpiresAt:          input.ExpiresAt,
+		CreatedBy:          input.CreatedBy,
+	}
+
+	if err := s.apiKeyRepo.Create(ctx, key); err != nil {
+		return nil, "", err
+	}
+
+	_ = s.logActivity(ctx, input.TenantID, "api_key_created", input.CreatedBy, map[string]interface{}{
+		"api_key_id": key.ID,
+		"prefix":     key.Prefix,
+		"name":       key.Name,
+		"scopes":     key.Scopes,
+	})
+
+	return key, token, nil
+}
+
+// GetKey retrieves an API key
+func (s *APIKeyService) GetKey(ctx context.Context, tenantID, id string) (*models.APIKey, error) {
+	return s.apiKeyRepo.Get(ctx, tenantID, id)
+}
+
+// ListKeys lists the API keys of a tenant (including revoked and expired keys)
+func (s *APIKeyService) ListKeys(ctx context.Context, tenantID string) ([]*models.APIKey, error) {
+	return s.apiKeyRepo.List(ctx, tenantID)
+}
+
+// UpdateKey updates the name, scopes, expiry or rate limit of an active API key
+func (s *APIKeyService) UpdateKey(ctx context.Context, tenantID, id string, input UpdateAPIKeyInput, actorID string) (*models.APIKey, error) {
+	key, err := s.apiKeyRepo.Get(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if key.RevokedAt != nil {
+		return nil, fmt.Errorf("api key is revoked")
+	}
+
+	updates := []firestore.Update{}
+	metadata := map[string]interface{}{
+		"api_key_id": id,
+		"prefix":     key.Prefix,
+	}
+
+	if input.Name != nil {
+		if strings.TrimSpace(*input.Name) == "" {
+			return nil, fmt.Errorf("name cannot be empty")
+		}
+		updates = append(updates, firestore.Update{Path: "name", Value: strings.TrimSpace(*input.Name)})
+	}
+	if input.Scopes != nil {
+		if err := validateAPIKeyScopes(input.Scopes); err != nil {
+			return nil, err
+		}
+		updates = append(updates, firestore.Update{Path: "scopes", Value: input.Scopes})
+		metadata["old_scopes"] = key.Scopes
+		metadata["new_scopes"] = input.Scopes
+	}
+	if input.ExpiresAt != nil {
+		if !input.ExpiresAt.After(time.Now()) {
+			return nil, fmt.Errorf("expires_at must be in the future")
+		}
+		updates = append(updates, firestore.Update{Path: "expires_at", Value: *input.ExpiresAt})
+		metadata["expires_at"] = *input.ExpiresAt
+	}
+	if input.RateLimitPerMinute != nil {
+		if err := validateAPIKeyRateLimit(*input.RateLimitPerMinute); err != nil {
+			return nil, err
+		}
+		updates = append(updates, firestore.Update{Path: "rate_limit_per_minute", Value: *input.RateLimitPerMinute})
+		metadata["rate_limit_per_minute"] = *input.RateLimitPerMinute
+	}
+
+	if len(updates) == 0 {
+		return key, nil
+	}
+
+	if err := s.apiKeyRepo.Update(ctx, tenantID, id, updates); err != nil {
+		return nil, err
+	}
+
+	_ = s.logActivity(ctx, tenantID, "api_key_updated", actorID, metadata)
+
+	return s.apiKeyRepo.Get(ctx, tenantID, id)
+}
+
+// RevokeKey revokes an API key (requests with it are rejected right away)
+func (s *APIKeyService) RevokeKey(ctx context.Context, tenantID, id, actorID string) (*models.APIKey, error) {
+	key, err := s.apiKeyRepo.Get(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if key.RevokedAt != nil {
+		return key, nil
+	}
+
+	if err := s.apiKeyRepo.Update(ctx, tenantID, id, []firestore.Update{
+		{Path: "revoked_at", Value: time.Now()},
+		{Path: "revoked_by", Value: actorID},
+	}); err != nil {
+		return nil, err
+	}
+
+	_ = s.logActivity(ctx, tenantID, "api_key_revoked", actorID, map[string]interface{}{
+		"api_key_id": id,
+		"prefix":     key.Prefix,
+		"name":       key.Name,
+	})
+
+	return s.apiKeyRepo.Get(ctx, tenantID, id)
+}
+
+// Authenticate resolves the active API key of a tenant matching a plaintext key
+// Returns ErrInvalidAPIKey for unknown, malformed, revoked or expired keys
+func (s *APIKeyService) Authenticate(ctx context.Context, tenantID, token string) (*models.APIKey, error) {
+	prefix, ok := models.ParseAPIKeyPrefix(token)
+	if !ok || tenantID == "" {
+		return nil, ErrInvalidAPIKey
+	}
+
+	key, err := s.apiKeyRepo.GetByPrefix(ctx, tenantID, prefix)
+	if err != nil {
+		if err == repositories.ErrNotFound {
+			return nil, ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(key.KeyHash), []byte(models.HashAPIKey(token))) != 1 {
+		return nil, ErrInvalidAPIKey
+	}
+	if !key.IsActive(time.Now()) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	return key, nil
+}
+
+// RecordUsage tracks a request made with an API key
+// last_used_at is written at most once per apiKeyUsageInterval; mutating and denied (403) requests
+// are appended to the activity log
+func (s *APIKeyService) RecordUsage(ctx context.Context, key *models.APIKey, method, path string, statusCode int, clientIP string) {
+	now := time.Now()
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= apiKeyUsageInterval {
+		if err := s.apiKeyRepo.RecordUsage(ctx, key.TenantID, key.ID, now, clientIP); err != nil {
+			log.Printf("Failed to record usage of api key %s: %v", key.ID, err)
+		}
+	}
+
+	if method == http.MethodGet || method == http.MethodHead {
+		if statusCode != http.StatusForbidden {
+			return
+		}
+	}
+
+	eventType := "api_key_request"
+	if statusCode == http.StatusForbidden {
+		eventType = "api_key_request_denied"
+	}
+
+	entry := &models.ActivityLog{
+		TenantID:  key.TenantID,
+		EventType: eventType,
+		ActorType: models.ActorTypeAPIKey,
+		ActorID:   key.ID,
+		Metadata: map[string]interface{}{
+			"api_key_id":  key.ID,
+			"prefix":      key.Prefix,
+			"method":      method,
+			"path":        path,
+			"status_code": statusCode,
+			"client_ip":   clientIP,
+		},
+		Timestamp: now,
+	}
+	_ = appendActivityLog(ctx, s.activityLogRepo, entry)
+}
+
+// generateUniqueKey generates a key whose prefix is not used by another key of the tenant
+func (s *APIKeyService) generateUniqueKey(ctx context.Context, tenantID string) (string, string, error) {
+	for i := 0; i < 3; i++ {
+		prefix, token, err := generateAPIKey()
+		if err != nil {
+			return "", "", err
+		}
+
+		_, err = s.apiKeyRepo.GetByPrefix(ctx, tenantID, prefix)
+		if err == repositories.ErrNotFound {
+			return prefix, token, nil
+		}
+		if err != nil {
+			return "", "", err
+		}
+	}
+	return "", "", fmt.Errorf("failed to generate a unique api key prefix")
+}
+
+// logActivity logs a key lifecycle event on behalf of an admin user
+func (s *APIKeyService) logActivity(ctx context.Context, tenantID, eventType, actorID string, metadata map[string]interface{}) error {
+	actorType := models.ActorTypeUser
+	if actorID == "" || actorID == "system" {
+		actorType = models.ActorTypeSystem
+	}
+
+	log := &models.ActivityLog{
+		TenantID:  tenantID,
+		EventType: eventType,
+		ActorType: actorType,
+		ActorID:   actorID,
+		Metadata:  metadata,
+		Timestamp: time.Now(),
+	}
+
+	return appendActivityLog(ctx, s.activityLogRepo, log)
+}
+
+// generateAPIKey returns the prefix and the plaintext key (eik_<prefix>_<secret>)
+func generateAPIKey() (string, string, error) {
+	prefixBytes := make([]byte, models.APIKeyPrefixLength/2)
+	secretBytes := make([]byte, 32)
+	if _, err := rand.Read(prefixBytes); err != nil {
+		return "", "", fmt.Errorf("failed to generate api key: %w", err)
+	}
+	if _, err := rand.Read(secretBytes); err != nil {
+		return "", "", fmt.Errorf("failed to generate api key: %w", err)
+	}
+
+	prefix := hex.EncodeToString(prefixBytes)
+	return prefix, models.APIKeyTokenPrefix + prefix + "_" + base64.RawURLEncoding.EncodeToString(secretBytes), nil
+}
+
+// validateAPIKeyScopes requires at least one known scope
+func validateAPIKeyScopes(scopes []string) error {
+	if len(scopes) == 0 {
+		return fmt.Errorf("at least one scope is required")
+	}
+	for _, scope := range scopes {
+		if !models.IsValidAPIKeyScope(scope) {
+			return fmt.Errorf("invalid scope: %s", scope)
+		}
+	}
+	return nil
+}
+
+// validateAPIKeyRateLimit checks a per-key rate limit (0 uses the default)
+func validateAPIKeyRateLimit(perMinute int) error {
+	if perMinute < 0 || perMinute > maxAPIKeyRateLimitPerMinute {
+		return fmt.Errorf("rate_limit_per_minute must be between 0 and %d", maxAPIKeyRateLimitPerMinute)
+	}
+	return nil
+}
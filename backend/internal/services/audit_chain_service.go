@@ -0,0 +1,330 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+const (
+	// auditVerifyPageSize is the number of chain entries read per query
+	auditVerifyPageSize = 500
+
+	// defaultAuditVerifyMaxEntries bounds a single verification (resume from the latest checkpoint)
+	defaultAuditVerifyMaxEntries = 50000
+)
+
+// AuditChainService verifies the tenant activity log hash chain and signs checkpoints (CRECI/COFECI audits)
+// The chain itself is written by ActivityLogRepository.Create; see models.AuditChainVerifier for the checks
+type AuditChainService struct {
+	activityLogRepo *repositories.ActivityLogRepository
+	checkpointRepo  *repositories.AuditCheckpointRepository
+	tenantRepo      *repositories.TenantRepository
+	signingKey      []byte // AUDIT_CHECKPOINT_KEY (empty = checkpoints disabled)
+}
+
+// NewAuditChainService creates a new audit chain service
+func NewAuditChainService(
+	activityLogRepo *repositories.ActivityLogRepository,
+	checkpointRepo *repositories.AuditCheckpointRepository,
+	tenantRepo *repositories.TenantRepository,
+	signingKey string,
+) *AuditChainService {
+	return &AuditChainService{
+		activityLogRepo: activityLogRepo,
+		checkpointRepo:  checkpointRepo,
+		tenantRepo:      tenantRepo,
+		signingKey:      []byte(signingKey),
+	}
+}
+
+// VerifyAuditChainInput represents a verification request
+type VerifyAuditChainInput struct {
+	TenantID             string
+	FromLatestCheckpoint bool // Start after the latest signed checkpoint instead of the genesis
+	MaxEntries           int  // default 50000
+}
+
+// GetHead returns the current head of the tenant chain
+func (s *AuditChainService) GetHead(ctx context.Context, tenantID string) (*models.AuditChainHead, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+	return s.activityLogRepo.GetChainHead(ctx, tenantID)
+}
+
+// VerifyChain walks the chain in sequence order and reports the first broken link
+// Detects removed entries (gaps and a truncated tail), modified entries and checkpoints that do not match
+func (s *AuditChainService) VerifyChain(ctx context.Context, input VerifyAuditChainInput) (*models.AuditVerificationReport, error) {
+	if input.TenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+	if _, err := s.tenantRepo.Get(ctx, input.TenantID); err != nil {
+		return nil, fmt.Errorf("tenant not found: %w", err)
+	}
+
+	maxEntries := input.MaxEntries
+	if maxEntries <= 0 {
+		maxEntries = defaultAuditVerifyMaxEntries
+	}
+
+	head, err := s.activityLogRepo.GetChainHead(ctx, input.TenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.AuditVerificationReport{
+		TenantID:           input.TenantID,
+		HeadSequence:       head.Sequence,
+		SignaturesVerified: len(s.signingKey) > 0,
+	}
+
+	// Starting point: genesis, or the latest checkpoint (itself verified)
+	var afterSequence int64
+	prevHash := models.AuditGenesisHash
+	if input.FromLatestCheckpoint {
+		checkpoint, err := s.checkpointRepo.GetLatest(ctx, input.TenantID)
+		if err != nil {
+			return nil, err
+		}
+		if checkpoint != nil {
+			if brk, err := s.checkCheckpoint(ctx, checkpoint, nil); err != nil {
+				return nil, err
+			} else if brk != nil {
+				return s.finishReport(ctx, report, brk), nil
+			}
+			report.CheckpointsVerified++
+			afterSequence = checkpoint.Sequence
+			prevHash = checkpoint.ChainHash
+		}
+	}
+	report.FromSequence = afterSequence + 1
+	report.LastSequence = afterSequence
+
+	checkpoints, err := s.checkpointRepo.ListRange(ctx, input.TenantID, afterSequence+1, head.Sequence)
+	if err != nil {
+		return nil, err
+	}
+	checkpointsBySequence := make(map[int64]*models.AuditCheckpoint, len(checkpoints))
+	for _, checkpoint := range checkpoints {
+		checkpointsBySequence[checkpoint.Sequence] = checkpoint
+	}
+
+	verifier := models.NewAuditChainVerifier(afterSequence, prevHash)
+	cursor := afterSequence
+pages:
+	for report.EntriesVerified < maxEntries && cursor < head.Sequence {
+		logs, err := s.activityLogRepo.ListChain(ctx, input.TenantID, cursor, auditVerifyPageSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, activityLog := range logs {
+			// Entries appended after the head was read are left for the next verification
+			if activityLog.Sequence > head.Sequence {
+				break pages
+			}
+			if brk := verifier.Check(activityLog); brk != nil {
+				return s.finishReport(ctx, report, brk), nil
+			}
+			if checkpoint, ok := checkpointsBySequence[activityLog.Sequence]; ok {
+				brk, err := s.checkCheckpoint(ctx, checkpoint, activityLog)
+				if err != nil {
+					return nil, err
+				}
+				if brk != nil {
+					return s.finishReport(ctx, report, brk), nil
+				}
+				report.CheckpointsVerified++
+			}
+
+			report.EntriesVerified++
+			report.LastSequence = activityLog.Sequence
+			cursor = activityLog.Sequence
+			if report.EntriesVerified >= maxEntries {
+				break pages
+			}
+		}
+
+		if len(logs) < auditVerifyPageSize {
+			break
+		}
+	}
+
+	// Walked up to the head: entries removed from the end of the chain leave the head ahead
+	if report.EntriesVerified < maxEntries || report.LastSequence == head.Sequence {
+		report.Complete = true
+		if report.LastSequence != head.Sequence {
+			return s.finishReport(ctx, report, &models.AuditChainBreak{
+				Sequence: verifier.NextSequence(),
+				LogID:    head.LastLogID,
+				Reason:   models.AuditBreakHeadMismatch,
+				Expected: fmt.Sprintf("%d", head.Sequence),
+				Actual:   fmt.Sprintf("%d", report.LastSequence),
+			}), nil
+		}
+	}
+
+	return s.finishReport(ctx, report, nil), nil
+}
+
+// CreateCheckpoint verifies the entries since the latest checkpoint and signs the current head
+// This endpoint should be called by a cron job daily
+func (s *AuditChainService) CreateCheckpoint(ctx context.Context, tenantID, actorID string) (*models.AuditCheckpoint, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+	if len(s.signingKey) == 0 {
+		return nil, fmt.Errorf("audit checkpoints are disabled: AUDIT_CHECKPOINT_KEY is not configured")
+	}
+
+	// Only a valid chain is signed
+	report, err := s.VerifyChain(ctx, VerifyAuditChainInput{TenantID: tenantID, FromLatestCheckpoint: true})
+	if err != nil {
+		return nil, err
+	}
+	if !report.Valid {
+		return nil, fmt.Errorf("audit chain is broken at sequence %d (%s): checkpoint not created", report.FirstBreak.Sequence, report.FirstBreak.Reason)
+	}
+	if !report.Complete {
+		return nil, fmt.Errorf("audit chain verification did not reach the head: checkpoint not created")
+	}
+	if report.LastSequence == 0 {
+		return nil, fmt.Errorf("audit chain is empty")
+	}
+
+	latest, err := s.checkpointRepo.GetLatest(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if latest != nil && latest.Sequence == report.LastSequence {
+		return latest, nil // No entries since the latest checkpoint
+	}
+
+	entry, err := s.activityLogRepo.GetBySequence(ctx, tenantID, report.LastSequence)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load chain entry %d: %w", report.LastSequence, err)
+	}
+
+	if actorID == "" {
+		actorID = "system"
+	}
+	checkpoint := &models.AuditCheckpoint{
+		TenantID:  tenantID,
+		Sequence:  entry.Sequence,
+		ChainHash: entry.ChainHash,
+		LogID:     entry.ID,
+		CreatedBy: actorID,
+		CreatedAt: time.Now().UTC().Truncate(time.Microsecond),
+	}
+	checkpoint.Sign(s.signingKey)
+
+	if err := s.checkpointRepo.Create(ctx, checkpoint); err != nil {
+		if err == repositories.ErrAlreadyExists {
+			return nil, fmt.Errorf("checkpoint for sequence %d was created concurrently", checkpoint.Sequence)
+		}
+		return nil, err
+	}
+
+	_ = s.logActivity(ctx, tenantID, "audit_checkpoint_created", actorID, map[string]interface{}{
+		"checkpoint_id": checkpoint.ID,
+		"sequence":      checkpoint.Sequence,
+		"chain_hash":    checkpoint.ChainHash,
+		"key_id":        checkpoint.KeyID,
+	})
+
+	return checkpoint, nil
+}
+
+// ListCheckpoints lists the most recent checkpoints
+func (s *AuditChainService) ListCheckpoints(ctx context.Context, tenantID string, limit int) ([]*models.AuditCheckpoint, error) {
+	if limit <= 0 {
+		limit = 30
+	}
+	return s.checkpointRepo.List(ctx, tenantID, limit)
+}
+
+// checkCheckpoint verifies the signature of a checkpoint and that it matches its chain entry
+// entry is loaded when nil (checkpoint used as the verification starting point)
+func (s *AuditChainService) checkCheckpoint(ctx context.Context, checkpoint *models.AuditCheckpoint, entry *models.ActivityLog) (*models.AuditChainBreak, error) {
+	if len(s.signingKey) > 0 && !checkpoint.VerifySignature(s.signingKey) {
+		return &models.AuditChainBreak{
+			Sequence: checkpoint.Sequence,
+			LogID:    checkpoint.LogID,
+			Reason:   models.AuditBreakCheckpointSignature,
+			Expected: checkpoint.KeyID,
+			Actual:   models.AuditKeyID(s.signingKey),
+		}, nil
+	}
+
+	if entry == nil {
+		var err error
+		entry, err = s.activityLogRepo.GetBySequence(ctx, checkpoint.TenantID, checkpoint.Sequence)
+		if err == repositories.ErrNotFound {
+			return &models.AuditChainBreak{Sequence: checkpoint.Sequence, LogID: checkpoint.LogID, Reason: models.AuditBreakGap}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hash := entry.ComputeChainHash(); hash != entry.ChainHash {
+			return &models.AuditChainBreak{Sequence: entry.Sequence, LogID: entry.ID, Reason: models.AuditBreakHashMismatch, Expected: entry.ChainHash, Actual: hash}, nil
+		}
+	}
+
+	if entry.ChainHash != checkpoint.ChainHash || entry.ID != checkpoint.LogID {
+		return &models.AuditChainBreak{
+			Sequence: checkpoint.Sequence,
+			LogID:    entry.ID,
+			Reason:   models.AuditBreakCheckpointMismatch,
+			Expected: checkpoint.ChainHash,
+			Actual:   entry.ChainHash,
+		}, nil
+	}
+
+	return nil, nil
+}
+
+// finishReport fills the verification result and logs it
+func (s *AuditChainService) finishReport(ctx context.Context, report *models.AuditVerificationReport, brk *models.AuditChainBreak) *models.AuditVerificationReport {
+	report.FirstBreak = brk
+	report.Valid = brk == nil
+	report.VerifiedAt = time.Now()
+
+	metadata := map[string]interface{}{
+		"valid":            report.Valid,
+		"from_sequence":    report.FromSequence,
+		"last_sequence":    report.LastSequence,
+		"entries_verified": report.EntriesVerified,
+		"complete":         report.Complete,
+	}
+	if brk != nil {
+		metadata["break_sequence"] = brk.Sequence
+		metadata["break_reason"] = brk.Reason
+	}
+	_ = s.logActivity(ctx, report.TenantID, "audit_chain_verified", "system", metadata)
+
+	return report
+}
+
+// logActivity logs an activity
+func (s *AuditChainService) logActivity(ctx context.Context, tenantID, eventType, actorID string, metadata map[string]interface{}) error {
+	actorType := models.ActorTypeUser
+	if actorID == "system" {
+		actorType = models.ActorTypeSystem
+		actorID = ""
+	}
+
+	log := &models.ActivityLog{
+		TenantID:  tenantID,
+		EventType: eventType,
+		ActorType: actorType,
+		ActorID:   actorID,
+		Metadata:  metadata,
+		Timestamp: time.Now(),
+	}
+
+	return appendActivityLog(ctx, s.activityLogRepo, log)
+}
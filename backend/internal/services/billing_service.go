@@ -0,0 +1,428 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"cloud.google.com/go/firestore"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// ErrInvoiceNotPayable is returned when paying an invoice that is already paid or void
+var ErrInvoiceNotPayable = errors.New("invoice is not payable")
+
+// InvoiceRunResult summarizes a monthly invoice generation run
+type InvoiceRunResult struct {
+	Period  string `json:"period"`
+	Created int    `json:"created"`
+	Skipped int    `json:"skipped"` // trials and invoices already generated
+	Failed  int    `json:"failed"`
+}
+
+// DunningResult summarizes a dunning run
+type DunningResult struct {
+	Paid    int `json:"paid"`
+	Retried int `json:"retried"`
+	Overdue int `json:"overdue"` // invoices whose retries were exhausted (subscription expired)
+	Failed  int `json:"failed"`
+}
+
+// BillingService issues the monthly invoices of the tenants (plan fee + metered usage),
+// charges them through the payment gateway and runs the dunning of unpaid invoices
+type BillingService struct {
+	invoiceRepo     *repositories.InvoiceRepository
+	tenantRepo      *repositories.TenantRepository
+	activityLogRepo *repositories.ActivityLogRepository
+	usageService    *UsageService
+	gateway         PaymentGateway
+}
+
+// NewBillingService creates a new billing service
+func NewBillingService(
+	invoiceRepo *repositories.InvoiceRepository,
+	tenantRepo *repositories.TenantRepository,
+	activityLogRepo *repositories.ActivityLogRepository,
+	usageService *UsageService,
+	gateway PaymentGateway,
+) *BillingService {
+	return &BillingService{
+		invoiceRepo:     invoiceRepo,
+		tenantRepo:      tenantRepo,
+		activityLogRepo: activityLogRepo,
+		usageService:    usageService,
+		gateway:         gateway,
+	}
+}
+
+// GenerateInvoices issues the invoices of a period (YYYY-MM, empty = previous month) for every active tenant
+// Tenants in trial are not billed; invoices already issued for the period are skipped (idempotent)
+func (s *BillingService) GenerateInvoices(ctx context.Context, period string) (*InvoiceRunResult, error) {
+	start, end, period, err := ParseSnapshotPeriod(period)
+	if err != nil {
+		return nil, err
+	}
+
+	tenants, err := s.tenantRepo.ListActive(ctx, repositories.PaginationOptions{Limit: 10000})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tenants: %w", err)
+	}
+
+	result := &InvoiceRunResult{Period: period}
+	for _, tenant := range tenants {
+		if tenant.SubscriptionStatus == models.SubscriptionStatusTrial {
+			result.Skipped++
+			continue
+		}
+
+		if _, err := s.generateInvoice(ctx, tenant, period, start, end); err != nil {
+			if errors.Is(err, repositories.ErrAlreadyExists) {
+				result.Skipped++
+				continue
+			}
+			log.Printf("Failed to generate invoice %s for tenant %s: %v", period, tenant.ID, err)
+			result.Failed++
+			continue
+		}
+		result.Created++
+	}
+
+	return result, nil
+}
+
+// generateInvoice prices the usage of a tenant in a period, stores the invoice and makes the first charge
+func (s *BillingService) generateInvoice(ctx context.Context, tenant *models.Tenant, period string, start, end time.Time) (*models.Invoice, error) {
+	usage, err := s.usageService.GetMonthlyUsage(ctx, tenant.ID, period)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate usage: %w", err)
+	}
+
+	now := time.Now()
+	plan := tenant.EffectivePlan(now)
+	items := models.BuildInvoiceLineItems(plan, usage)
+
+	invoice := &models.Invoice{
+		TenantID:    tenant.ID,
+		Number:      models.InvoiceNumber(tenant.ID, period),
+		Period:      period,
+		PeriodStart: start,
+		PeriodEnd:   end,
+		Plan:        plan.ID,
+		Currency:    "BRL",
+		LineItems:   items,
+		TotalCents:  models.InvoiceTotal(items),
+		Usage:       usage,
+		Status:      models.InvoiceStatusOpen,
+		DueAt:       now.AddDate(0, 0, models.InvoicePaymentDays),
+	}
+
+	// Nothing to charge (free plan, or usage within the included units)
+	if invoice.TotalCents == 0 {
+		invoice.Status = models.InvoiceStatusPaid
+		invoice.PaidAt = &now
+	}
+
+	if err := s.invoiceRepo.Create(ctx, invoice); err != nil {
+		return nil, err
+	}
+
+	_ = s.logActivity(ctx, tenant.ID, "invoice_issued", models.ActorTypeSystem, "", map[string]interface{}{
+		"invoice_id":  invoice.ID,
+		"period":      period,
+		"plan":        plan.ID,
+		"total_cents": invoice.TotalCents,
+	})
+
+	if invoice.Status == models.InvoiceStatusOpen {
+		if err := s.attemptCharge(ctx, tenant, invoice); err != nil {
+			log.Printf("Failed to charge invoice %s of tenant %s: %v", invoice.ID, tenant.ID, err)
+		}
+	}
+
+	return invoice, nil
+}
+
+// ListInvoices lists the invoices of a tenant (most recent first)
+func (s *BillingService) ListInvoices(ctx context.Context, tenantID string, opts repositories.PaginationOptions) ([]*models.Invoice, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+	return s.invoiceRepo.List(ctx, tenantID, opts)
+}
+
+// GetInvoice returns an invoice of a tenant
+func (s *BillingService) GetInvoice(ctx context.Context, tenantID, invoiceID string) (*models.Invoice, error) {
+	return s.invoiceRepo.Get(ctx, tenantID, invoiceID)
+}
+
+// PayInvoice charges an open or overdue invoice on demand (ex: the tenant pays with another card or a new PIX)
+// Paying the overdue invoice that expired the subscription reactivates it
+func (s *BillingService) PayInvoice(ctx context.Context, tenantID, invoiceID string, method models.PaymentMethod, cardToken, actorID string) (*models.Invoice, error) {
+	if !models.IsValidPaymentMethod(method) {
+		return nil, fmt.Errorf("invalid payment method: %s", method)
+	}
+
+	invoice, err := s.invoiceRepo.Get(ctx, tenantID, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	if invoice.Status != models.InvoiceStatusOpen && invoice.Status != models.InvoiceStatusOverdue {
+		return nil, ErrInvoiceNotPayable
+	}
+
+	tenant, err := s.tenantRepo.Get(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	charge, err := s.charge(ctx, tenant, invoice, method, cardToken)
+	if err != nil {
+		return nil, err
+	}
+
+	_ = s.logActivity(ctx, tenantID, "invoice_payment_attempted", models.ActorTypeUser, actorID, map[string]interface{}{
+		"invoice_id":     invoice.ID,
+		"payment_method": string(method),
+		"charge_status":  string(charge.Status),
+	})
+
+	return invoice, nil
+}
+
+// RunDunning follows up the open invoices of every tenant: confirms PIX payments, retries failed charges
+// on the DunningRetryDays schedule and, once the retries are exhausted, marks the invoice overdue and
+// expires the subscription (free plan quotas)
+func (s *BillingService) RunDunning(ctx context.Context) (*DunningResult, error) {
+	tenants, err := s.tenantRepo.ListActive(ctx, repositories.PaginationOptions{Limit: 10000})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tenants: %w", err)
+	}
+
+	result := &DunningResult{}
+	now := time.Now()
+
+	for _, tenant := range tenants {
+		invoices, err := s.invoiceRepo.ListOpen(ctx, tenant.ID)
+		if err != nil {
+			log.Printf("Failed to list open invoices of tenant %s: %v", tenant.ID, err)
+			result.Failed++
+			continue
+		}
+
+		for _, invoice := range invoices {
+			if err := s.dunInvoice(ctx, tenant, invoice, now, result); err != nil {
+				log.Printf("Failed to run dunning of invoice %s (tenant %s): %v", invoice.ID, tenant.ID, err)
+				result.Failed++
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// dunInvoice runs one dunning step of an open invoice
+func (s *BillingService) dunInvoice(ctx context.Context, tenant *models.Tenant, invoice *models.Invoice, now time.Time, result *DunningResult) error {
+	if invoice.ChargeID != "" {
+		charge, err := s.gateway.GetCharge(ctx, invoice.ChargeID)
+		if err != nil && !errors.Is(err, ErrChargeNotFound) {
+			return err
+		}
+
+		// An unknown charge is treated as failed and charged again
+		if charge != nil {
+			switch charge.Status {
+			case ChargeStatusPaid:
+				result.Paid++
+				return s.markPaid(ctx, tenant, invoice, charge)
+			case ChargeStatusPending:
+				return nil // PIX aguardando pagamento
+			}
+		}
+	}
+
+	if invoice.NextAttemptAt == nil {
+		result.Overdue++
+		return s.markOverdue(ctx, tenant, invoice)
+	}
+	if now.Before(*invoice.NextAttemptAt) {
+		return nil
+	}
+
+	result.Retried++
+	return s.attemptCharge(ctx, tenant, invoice)
+}
+
+// attemptCharge makes a scheduled charge with the tenant's billing settings and schedules the next retry
+func (s *BillingService) attemptCharge(ctx context.Context, tenant *models.Tenant, invoice *models.Invoice) error {
+	method := models.PaymentMethod(tenant.StringSetting(models.TenantSettingBillingPaymentMethod))
+	if !models.IsValidPaymentMethod(method) {
+		method = models.PaymentMethodPix
+	}
+
+	charge, chargeErr := s.charge(ctx, tenant, invoice, method, tenant.StringSetting(models.TenantSettingBillingCardToken))
+	if charge != nil && charge.Status == ChargeStatusPaid {
+		return nil
+	}
+
+	next := invoice.NextDunningAttempt()
+	update := firestore.Update{Path: "next_attempt_at", Value: firestore.Delete}
+	if next != nil {
+		update.Value = *next
+	}
+	invoice.NextAttemptAt = next
+
+	if err := s.invoiceRepo.Update(ctx, tenant.ID, invoice.ID, []firestore.Update{update}); err != nil {
+		return err
+	}
+	return chargeErr
+}
+
+// charge creates a gateway charge for an invoice and records the attempt (marking the invoice paid when settled)
+func (s *BillingService) charge(ctx context.Context, tenant *models.Tenant, invoice *models.Invoice, method models.PaymentMethod, cardToken string) (*Charge, error) {
+	now := time.Now()
+	invoice.Attempts++
+	invoice.LastAttemptAt = &now
+	invoice.PaymentMethod = method
+
+	charge, err := s.gateway.CreateCharge(ctx, ChargeRequest{
+		TenantID:      tenant.ID,
+		InvoiceID:     invoice.ID,
+		Description:   fmt.Sprintf("Fatura %s", invoice.Number),
+		AmountCents:   invoice.TotalCents,
+		Currency:      invoice.Currency,
+		Method:        method,
+		CardToken:     cardToken,
+		CustomerEmail: tenant.Email,
+	})
+
+	updates := []firestore.Update{
+		{Path: "attempts", Value: invoice.Attempts},
+		{Path: "last_attempt_at", Value: now},
+		{Path: "payment_method", Value: string(method)},
+	}
+
+	switch {
+	case err != nil:
+		invoice.LastError = err.Error()
+	case charge.Status == ChargeStatusFailed:
+		invoice.LastError = charge.FailureReason
+	default:
+		invoice.LastError = ""
+	}
+	if err == nil {
+		invoice.ChargeID = charge.ID
+		invoice.PixCopyPaste = charge.PixCopyPaste
+		invoice.PixExpiresAt = charge.ExpiresAt
+		updates = append(updates,
+			firestore.Update{Path: "charge_id", Value: charge.ID},
+			firestore.Update{Path: "pix_copy_paste", Value: charge.PixCopyPaste},
+			firestore.Update{Path: "pix_expires_at", Value: charge.ExpiresAt},
+		)
+	}
+	updates = append(updates, firestore.Update{Path: "last_error", Value: invoice.LastError})
+
+	if updateErr := s.invoiceRepo.Update(ctx, tenant.ID, invoice.ID, updates); updateErr != nil {
+		return nil, updateErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to charge invoice: %w", err)
+	}
+
+	if charge.Status == ChargeStatusPaid {
+		if err := s.markPaid(ctx, tenant, invoice, charge); err != nil {
+			return nil, err
+		}
+	}
+
+	return charge, nil
+}
+
+// markPaid settles an invoice; paying the overdue invoice reactivates an expired subscription
+func (s *BillingService) markPaid(ctx context.Context, tenant *models.Tenant, invoice *models.Invoice, charge *Charge) error {
+	now := time.Now()
+	wasOverdue := invoice.Status == models.InvoiceStatusOverdue
+
+	if err := s.invoiceRepo.Update(ctx, tenant.ID, invoice.ID, []firestore.Update{
+		{Path: "status", Value: string(models.InvoiceStatusPaid)},
+		{Path: "paid_at", Value: now},
+		{Path: "next_attempt_at", Value: firestore.Delete},
+		{Path: "last_error", Value: ""},
+	}); err != nil {
+		return err
+	}
+	invoice.Status = models.InvoiceStatusPaid
+	invoice.PaidAt = &now
+	invoice.NextAttemptAt = nil
+	invoice.LastError = ""
+
+	_ = s.logActivity(ctx, tenant.ID, "invoice_paid", models.ActorTypeSystem, "", map[string]interface{}{
+		"invoice_id":     invoice.ID,
+		"charge_id":      charge.ID,
+		"payment_method": string(charge.Method),
+		"total_cents":    invoice.TotalCents,
+	})
+
+	if wasOverdue && tenant.SubscriptionStatus == models.SubscriptionStatusExpired {
+		if err := s.tenantRepo.Update(ctx, tenant.ID, map[string]interface{}{
+			"subscription_status": models.SubscriptionStatusActive,
+		}); err != nil {
+			return fmt.Errorf("failed to reactivate subscription: %w", err)
+		}
+		tenant.SubscriptionStatus = models.SubscriptionStatusActive
+
+		_ = s.logActivity(ctx, tenant.ID, "subscription_reactivated", models.ActorTypeSystem, "", map[string]interface{}{
+			"invoice_id":        invoice.ID,
+			"subscription_plan": tenant.SubscriptionPlan,
+		})
+	}
+
+	return nil
+}
+
+// markOverdue closes the dunning of an invoice and expires the subscription of the tenant
+func (s *BillingService) markOverdue(ctx context.Context, tenant *models.Tenant, invoice *models.Invoice) error {
+	if err := s.invoiceRepo.Update(ctx, tenant.ID, invoice.ID, []firestore.Update{
+		{Path: "status", Value: string(models.InvoiceStatusOverdue)},
+	}); err != nil {
+		return err
+	}
+	invoice.Status = models.InvoiceStatusOverdue
+
+	if tenant.SubscriptionStatus == models.SubscriptionStatusExpired {
+		return nil
+	}
+
+	if err := s.tenantRepo.Update(ctx, tenant.ID, map[string]interface{}{
+		"subscription_status": models.SubscriptionStatusExpired,
+	}); err != nil {
+		return fmt.Errorf("failed to expire subscription: %w", err)
+	}
+	tenant.SubscriptionStatus = models.SubscriptionStatusExpired
+
+	_ = s.logActivity(ctx, tenant.ID, "subscription_expired_unpaid", models.ActorTypeSystem, "", map[string]interface{}{
+		"invoice_id":        invoice.ID,
+		"subscription_plan": tenant.SubscriptionPlan,
+		"attempts":          invoice.Attempts,
+		"last_error":        invoice.LastError,
+	})
+
+	return nil
+}
+
+// logActivity logs a billing event
+func (s *BillingService) logActivity(ctx context.Context, tenantID, eventType string, actorType models.ActorType, actorID string, metadata map[string]interface{}) error {
+	log := &models.ActivityLog{
+		TenantID:  tenantID,
+		EventType: eventType,
+		ActorType: actorType,
+		ActorID:   actorID,
+		Metadata:  metadata,
+		Timestamp: time.Now(),
+	}
+
+	return appendActivityLog(ctx, s.activityLogRepo, log)
+}
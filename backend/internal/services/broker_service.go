@@ -19,6 +19,7 @@ type BrokerService struct {
 	propertyBrokerRoleRepo *repositories.PropertyBrokerRoleRepository
 	propertyRepo           *repositories.PropertyRepository
 	listingRepo            *repositories.ListingRepository
+	planService            *PlanService // Optional: subscription plan quotas
 }
 
 // NewBrokerService creates a new broker service
@@ -40,6 +41,11 @@ func NewBrokerService(
 	}
 }
 
+// SetPlanService sets the plan service (for dependency injection)
+func (s *BrokerService) SetPlanService(service *PlanService) {
+	s.planService = service
+}
+
 // CreateBroker creates a new broker with validation
 func (s *BrokerService) CreateBroker(ctx context.Context, broker *models.Broker) error {
 	// Validate required fields
@@ -64,6 +70,13 @@ func (s *BrokerService) CreateBroker(ctx context.Context, broker *models.Broker)
 		return fmt.Errorf("tenant not found: %w", err)
 	}
 
+	// Check the broker quota of the tenant's plan
+	if s.planService != nil {
+		if err := s.planService.CheckBrokerQuota(ctx, broker.TenantID); err != nil {
+			return err
+		}
+	}
+
 	// Validate CRECI
 	if err := s.ValidateCRECI(broker.CRECI); err != nil {
 		return err
@@ -513,7 +526,7 @@ func (s *BrokerService) logActivity(ctx context.Context, tenantID, eventType str
 		Timestamp: time.Now(),
 	}
 
-	return s.activityLogRepo.Create(ctx, log)
+	return appendActivityLog(ctx, s.activityLogRepo, log)
 }
 
 // isPendingEmail checks if an email is a temporary placeholder email
@@ -0,0 +1,299 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// ErrNoMarketingConsent is returned when a marketing communication targets someone without active marketing consent
+var ErrNoMarketingConsent = errors.New("no active marketing consent")
+
+// Consent origins recorded in the ledger
+const (
+	ConsentOriginForm        = "form"
+	ConsentOriginWhatsApp    = "whatsapp"
+	ConsentOriginOwnerPortal = "owner_portal"
+	ConsentOriginBroker      = "broker"
+	ConsentOriginAPI         = "api"
+)
+
+// ConsentService manages the consent ledger (versioned texts, grants/withdrawals per purpose)
+// The ledger is the source of truth; Lead/Owner consent fields are kept as a snapshot of the contact consent
+type ConsentService struct {
+	consentRepo     *repositories.ConsentRepository
+	leadRepo        *repositories.LeadRepository
+	ownerRepo       *repositories.OwnerRepository
+	activityLogRepo *repositories.ActivityLogRepository
+}
+
+// NewConsentService creates a new consent service
+func NewConsentService(
+	consentRepo *repositories.ConsentRepository,
+	leadRepo *repositories.LeadRepository,
+	ownerRepo *repositories.OwnerRepository,
+	activityLogRepo *repositories.ActivityLogRepository,
+) *ConsentService {
+	return &ConsentService{
+		consentRepo:     consentRepo,
+		leadRepo:        leadRepo,
+		ownerRepo:       ownerRepo,
+		activityLogRepo: activityLogRepo,
+	}
+}
+
+// RecordConsentInput represents a grant or withdrawal of consent for one purpose
+type RecordConsentInput struct {
+	TenantID    string
+	SubjectType models.ConsentSubjectType
+	SubjectID   string
+	Purpose     models.ConsentPurpose
+	Granted     bool
+	TextVersion int    // published version shown (0 = current version)
+	Text        string // text shown, when not a published version (e.g. sent by the lead form)
+	IP          string
+	UserAgent   string
+	Origin      string
+	ActorType   models.ActorType
+	ActorID     string
+}
+
+// PublishText publishes a new version of the consent text of a purpose
+func (s *ConsentService) PublishText(ctx context.Context, tenantID string, purpose models.ConsentPurpose, text, actorID string) (*models.ConsentTextVersion, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+	if !models.IsValidConsentPurpose(purpose) {
+		return nil, fmt.Errorf("invalid consent purpose: must be 'contact', 'marketing' or 'data_sharing'")
+	}
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, fmt.Errorf("text is required")
+	}
+
+	version := 1
+	latest, err := s.consentRepo.GetLatestText(ctx, tenantID, purpose)
+	if err != nil && err != repositories.ErrNotFound {
+		return nil, err
+	}
+	if latest != nil {
+		version = latest.Version + 1
+	}
+
+	consentText := &models.ConsentTextVersion{
+		TenantID:  tenantID,
+		Purpose:   purpose,
+		Version:   version,
+		Text:      text,
+		CreatedBy: actorID,
+	}
+	if err := s.consentRepo.CreateText(ctx, consentText); err != nil {
+		if err == repositories.ErrAlreadyExists {
+			return nil, fmt.Errorf("version %d was published concurrently, try again", version)
+		}
+		return nil, err
+	}
+
+	_ = s.logActivity(ctx, tenantID, "consent_text_published", models.ActorTypeUser, actorID, map[string]interface{}{
+		"purpose": string(purpose),
+		"version": version,
+		"text_id": consentText.ID,
+	})
+
+	return consentText, nil
+}
+
+// GetCurrentText returns the current text of a purpose (ErrNotFound when none was published)
+func (s *ConsentService) GetCurrentText(ctx context.Context, tenantID string, purpose models.ConsentPurpose) (*models.ConsentTextVersion, error) {
+	if !models.IsValidConsentPurpose(purpose) {
+		return nil, fmt.Errorf("invalid consent purpose: must be 'contact', 'marketing' or 'data_sharing'")
+	}
+	return s.consentRepo.GetLatestText(ctx, tenantID, purpose)
+}
+
+// ListTexts lists every version of a purpose text (newest first)
+func (s *ConsentService) ListTexts(ctx context.Context, tenantID string, purpose models.ConsentPurpose) ([]*models.ConsentTextVersion, error) {
+	if !models.IsValidConsentPurpose(purpose) {
+		return nil, fmt.Errorf("invalid consent purpose: must be 'contact', 'marketing' or 'data_sharing'")
+	}
+	return s.consentRepo.ListTexts(ctx, tenantID, purpose)
+}
+
+// RecordConsent appends a grant or withdrawal to the ledger
+func (s *ConsentService) RecordConsent(ctx context.Context, input RecordConsentInput) (*models.ConsentRecord, error) {
+	if input.TenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+	if input.SubjectID == "" {
+		return nil, fmt.Errorf("subject_id is required")
+	}
+	if input.SubjectType != models.ConsentSubjectLead && input.SubjectType != models.ConsentSubjectOwner {
+		return nil, fmt.Errorf("invalid subject type: must be 'lead' or 'owner'")
+	}
+	if !models.IsValidConsentPurpose(input.Purpose) {
+		return nil, fmt.Errorf("invalid consent purpose: must be 'contact', 'marketing' or 'data_sharing'")
+	}
+	if input.Origin == "" {
+		input.Origin = ConsentOriginAPI
+	}
+	if input.ActorType == "" {
+		input.ActorType = models.ActorTypeSystem
+	}
+
+	record := &models.ConsentRecord{
+		TenantID:    input.TenantID,
+		SubjectType: input.SubjectType,
+		SubjectID:   input.SubjectID,
+		Purpose:     input.Purpose,
+		Action:      models.ConsentActionWithdrawn,
+		IP:          input.IP,
+		UserAgent:   input.UserAgent,
+		Origin:      input.Origin,
+		ActorType:   input.ActorType,
+		ActorID:     input.ActorID,
+	}
+
+	// Grants must reference the text shown: a published version or the text itself
+	if input.Granted {
+		record.Action = models.ConsentActionGranted
+
+		var text *models.ConsentTextVersion
+		var err error
+		if input.TextVersion > 0 {
+			text, err = s.consentRepo.GetText(ctx, input.TenantID, input.Purpose, input.TextVersion)
+			if err != nil {
+				return nil, fmt.Errorf("consent text version %d not found for %s", input.TextVersion, input.Purpose)
+			}
+		} else if input.Text == "" {
+			text, err = s.consentRepo.GetLatestText(ctx, input.TenantID, input.Purpose)
+			if err == repositories.ErrNotFound {
+				return nil, fmt.Errorf("no consent text published for %s: publish one or send the text shown", input.Purpose)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if text != nil {
+			record.TextID = text.ID
+			record.TextVersion = text.Version
+			record.Text = text.Text
+		} else {
+			record.Text = input.Text
+		}
+	}
+
+	if err := s.consentRepo.CreateRecord(ctx, record); err != nil {
+		return nil, err
+	}
+
+	eventType := "consent_granted"
+	if !input.Granted {
+		eventType = "consent_withdrawn"
+	}
+	_ = s.logActivity(ctx, input.TenantID, eventType, input.ActorType, input.ActorID, map[string]interface{}{
+		string(input.SubjectType) + "_id": input.SubjectID,
+		"subject_type":                    string(input.SubjectType),
+		"purpose":                         string(input.Purpose),
+		"consent_record_id":               record.ID,
+		"text_version":                    record.TextVersion,
+		"origin":                          input.Origin,
+	})
+
+	return record, nil
+}
+
+// WithdrawAll withdraws every purpose currently granted (e.g. the subject revoked all consent)
+func (s *ConsentService) WithdrawAll(ctx context.Context, input RecordConsentInput) error {
+	states, err := s.GetConsentState(ctx, input.TenantID, input.SubjectType, input.SubjectID)
+	if err != nil {
+		return err
+	}
+
+	for _, state := range states {
+		if !state.Granted {
+			continue
+		}
+		withdrawal := input
+		withdrawal.Purpose = state.Purpose
+		withdrawal.Granted = false
+		if _, err := s.RecordConsent(ctx, withdrawal); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetConsentState returns the current consent of a subject for every purpose
+func (s *ConsentService) GetConsentState(ctx context.Context, tenantID string, subjectType models.ConsentSubjectType, subjectID string) ([]models.ConsentState, error) {
+	records, err := s.consentRepo.ListRecordsBySubject(ctx, tenantID, subjectType, subjectID)
+	if err != nil {
+		return nil, err
+	}
+	return models.CurrentConsentStates(records), nil
+}
+
+// GetConsentHistory returns the full ledger of a subject (oldest first)
+func (s *ConsentService) GetConsentHistory(ctx context.Context, tenantID string, subjectType models.ConsentSubjectType, subjectID string) ([]*models.ConsentRecord, error) {
+	return s.consentRepo.ListRecordsBySubject(ctx, tenantID, subjectType, subjectID)
+}
+
+// HasActiveConsent checks whether a subject currently grants a purpose
+func (s *ConsentService) HasActiveConsent(ctx context.Context, tenantID string, subjectType models.ConsentSubjectType, subjectID string, purpose models.ConsentPurpose) (bool, error) {
+	states, err := s.GetConsentState(ctx, tenantID, subjectType, subjectID)
+	if err != nil {
+		return false, err
+	}
+	for _, state := range states {
+		if state.Purpose == purpose {
+			return state.Granted, nil
+		}
+	}
+	return false, nil
+}
+
+// EnsureMarketingConsent must be called before any marketing communication (alerts, campaigns)
+// Returns ErrNoMarketingConsent when the subject has no active marketing consent
+func (s *ConsentService) EnsureMarketingConsent(ctx context.Context, tenantID string, subjectType models.ConsentSubjectType, subjectID string) error {
+	granted, err := s.HasActiveConsent(ctx, tenantID, subjectType, subjectID, models.ConsentPurposeMarketing)
+	if err != nil {
+		return err
+	}
+	if !granted {
+		return ErrNoMarketingConsent
+	}
+	return nil
+}
+
+// SubjectExists checks that the lead/owner exists in the tenant (used by the admin API)
+func (s *ConsentService) SubjectExists(ctx context.Context, tenantID string, subjectType models.ConsentSubjectType, subjectID string) error {
+	switch subjectType {
+	case models.ConsentSubjectLead:
+		_, err := s.leadRepo.Get(ctx, tenantID, subjectID)
+		return err
+	case models.ConsentSubjectOwner:
+		_, err := s.ownerRepo.Get(ctx, tenantID, subjectID)
+		return err
+	}
+	return fmt.Errorf("invalid subject type: must be 'lead' or 'owner'")
+}
+
+// logActivity logs an activity
+func (s *ConsentService) logActivity(ctx context.Context, tenantID, eventType string, actorType models.ActorType, actorID string, metadata map[string]interface{}) error {
+	log := &models.ActivityLog{
+		TenantID:  tenantID,
+		EventType: eventType,
+		ActorType: actorType,
+		ActorID:   actorID,
+		Metadata:  metadata,
+		Timestamp: time.Now(),
+	}
+
+	return appendActivityLog(ctx, s.activityLogRepo, log)
+}
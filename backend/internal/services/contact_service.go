@@ -0,0 +1,400 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"cloud.google.com/go/firestore"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/pii"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+var (
+	// ErrContactMergeForbidden is returned when the actor may not merge contacts (admins or contacts.merge permission)
+	ErrContactMergeForbidden = errors.New("only admins can merge contacts")
+
+	// ErrContactMergeConflict is returned when fields differ on both contacts and no side was chosen for them
+	ErrContactMergeConflict = errors.New("contact merge has unresolved field conflicts")
+
+	// ErrContactMergeSameContact is returned when a contact is merged into itself
+	ErrContactMergeSameContact = errors.New("cannot merge a contact into itself")
+)
+
+// ContactService deduplicates leads into contacts (the same person interested in several properties),
+// merges duplicate contacts and aggregates their timelines
+type ContactService struct {
+	contactRepo        *repositories.ContactRepository
+	leadRepo           *repositories.LeadRepository
+	userRepo           *repositories.UserRepository
+	activityLogRepo    *repositories.ActivityLogRepository
+	activityLogService *ActivityLogService
+}
+
+// NewContactService creates a new contact service
+func NewContactService(
+	contactRepo *repositories.ContactRepository,
+	leadRepo *repositories.LeadRepository,
+	userRepo *repositories.UserRepository,
+	activityLogRepo *repositories.ActivityLogRepository,
+	activityLogService *ActivityLogService,
+) *ContactService {
+	return &ContactService{
+		contactRepo:        contactRepo,
+		leadRepo:           leadRepo,
+		userRepo:           userRepo,
+		activityLogRepo:    activityLogRepo,
+		activityLogService: activityLogService,
+	}
+}
+
+// AssignContact attaches a lead to the contact with the same phone, else the same e-mail, else to a new
+// contact, and sets the lead's contact_id in the same transaction: concurrent leads of the same person
+// share one contact
+// The lead's email and phone must be normalized (utils.NormalizeEmail, NormalizePhoneBR); leads without
+// contact data (WhatsApp leads before the first message) are left without contact
+func (s *ContactService) AssignContact(ctx context.Context, lead *models.Lead) error {
+	if lead.TenantID == "" {
+		return fmt.Errorf("tenant_id is required")
+	}
+
+	keys := contactKeys(lead)
+	if len(keys) == 0 {
+		return nil
+	}
+
+	contact, err := s.contactRepo.AssignLead(ctx, lead.TenantID, keys, func(contact *models.Contact, exists bool) error {
+		contact.AddLead(lead)
+		return nil
+	}, func(contact *models.Contact) ([]repositories.TxWrite, error) {
+		write, err := s.leadRepo.UpdateTx(ctx, lead.TenantID, lead.ID, map[string]interface{}{"contact_id": contact.ID})
+		if err != nil {
+			return nil, err
+		}
+		return []repositories.TxWrite{write}, nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to attach lead to contact: %w", err)
+	}
+
+	lead.ContactID = contact.ID
+	return nil
+}
+
+// ReassignContact moves a lead whose phone or e-mail changed to the contact of its new data
+// previous is the lead before the change (with its contact_id), lead the lead after it
+func (s *ContactService) ReassignContact(ctx context.Context, previous, lead *models.Lead) error {
+	if previous.ContactID != "" {
+		if err := s.DetachLead(ctx, previous); err != nil {
+			return err
+		}
+	}
+
+	lead.ContactID = ""
+	return s.AssignContact(ctx, lead)
+}
+
+// contactKeys returns the phone and the e-mail that identify the contact of a lead, phone first
+func contactKeys(lead *models.Lead) []repositories.ContactKey {
+	keys := make([]repositories.ContactKey, 0, 2)
+	if lead.Phone != "" && lead.Phone != "WhatsApp" {
+		keys = append(keys, repositories.ContactKey{Kind: pii.FieldPhone, Value: lead.Phone})
+	}
+	if lead.Email != "" {
+		keys = append(keys, repositories.ContactKey{Kind: pii.FieldEmail, Value: lead.Email})
+	}
+	return keys
+}
+
+// releasedContactKeys returns the keys of a detached lead that none of the remaining leads uses
+func releasedContactKeys(lead *models.Lead, remaining []*models.Lead) []repositories.ContactKey {
+	used := make(map[string]bool)
+	for _, other := range remaining {
+		for _, key := range contactKeys(other) {
+			used[string(key.Kind)+":"+pii.Normalize(key.Kind, key.Value)] = true
+		}
+	}
+
+	released := make([]repositories.ContactKey, 0, 2)
+	for _, key := range contactKeys(lead) {
+		if !used[string(key.Kind)+":"+pii.Normalize(key.Kind, key.Value)] {
+			released = append(released, key)
+		}
+	}
+	return released
+}
+
+// DetachLead removes a lead being anonymized (LGPD) or deleted from its contact
+// The contact is rebuilt from its remaining leads, so data that only the lead had is dropped; a contact
+// without remaining leads is deleted
+func (s *ContactService) DetachLead(ctx context.Context, lead *models.Lead) error {
+	if lead.ContactID == "" {
+		return nil
+	}
+
+	leads, err := s.leadRepo.ListByContactID(ctx, lead.TenantID, lead.ContactID)
+	if err != nil {
+		return fmt.Errorf("failed to list contact leads: %w", err)
+	}
+	remaining := make([]*models.Lead, 0, len(leads))
+	for _, other := range leads {
+		if other.ID != lead.ID && !other.IsAnonymized {
+			remaining = append(remaining, other)
+		}
+	}
+	sort.SliceStable(remaining, func(i, j int) bool {
+		return remaining[i].CreatedAt.Before(remaining[j].CreatedAt)
+	})
+
+	if err := s.leadRepo.Update(ctx, lead.TenantID, lead.ID, map[string]interface{}{"contact_id": ""}); err != nil {
+		return fmt.Errorf("failed to detach lead from contact: %w", err)
+	}
+
+	if len(remaining) == 0 {
+		if err := s.contactRepo.Delete(ctx, lead.TenantID, lead.ContactID); err != nil && !errors.Is(err, repositories.ErrNotFound) {
+			return err
+		}
+		return nil
+	}
+
+	_, err = s.contactRepo.UpdateContact(ctx, lead.TenantID, lead.ContactID, func(contact *models.Contact, exists bool) error {
+		contact.Name, contact.Email, contact.Phone = "", "", ""
+		contact.LeadCount, contact.PropertyIDs = 0, nil
+		contact.FirstLeadAt, contact.LastLeadAt = time.Time{}, time.Time{}
+		for _, other := range remaining {
+			contact.AddLead(other)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update contact: %w", err)
+	}
+
+	// The phone/e-mail of the lead no longer identify the contact
+	if err := s.contactRepo.ReleaseKeys(ctx, lead.TenantID, lead.ContactID, releasedContactKeys(lead, remaining)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetContact retrieves a contact
+func (s *ContactService) GetContact(ctx context.Context, tenantID, id string) (*models.Contact, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+
+	return s.contactRepo.Get(ctx, tenantID, id)
+}
+
+// ListContacts lists the contacts of a tenant (most recent lead first)
+func (s *ContactService) ListContacts(ctx context.Context, tenantID string, opts repositories.PaginationOptions) ([]*models.Contact, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+
+	contacts, err := s.contactRepo.List(ctx, tenantID, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list contacts: %w", err)
+	}
+
+	return contacts, nil
+}
+
+// ListContactLeads lists the leads (interests) of a contact, most recent first
+func (s *ContactService) ListContactLeads(ctx context.Context, tenantID, contactID string) ([]*models.Lead, error) {
+	if _, err := s.GetContact(ctx, tenantID, contactID); err != nil {
+		return nil, err
+	}
+
+	leads, err := s.leadRepo.ListByContactID(ctx, tenantID, contactID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list contact leads: %w", err)
+	}
+	sort.SliceStable(leads, func(i, j int) bool {
+		return leads[i].CreatedAt.After(leads[j].CreatedAt)
+	})
+
+	return leads, nil
+}
+
+// GetContactTimeline aggregates the lead timelines (GetLeadTimeline) of the contact's leads, most recent first
+func (s *ContactService) GetContactTimeline(ctx context.Context, tenantID, contactID string, opts repositories.PaginationOptions) ([]*models.ActivityLog, error) {
+	leads, err := s.ListContactLeads(ctx, tenantID, contactID)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Limit <= 0 {
+		opts.Limit = repositories.DefaultPaginationOptions().Limit
+	}
+
+	// Each lead contributes at most the requested page (offset included); the merged list is paginated
+	window := repositories.PaginationOptions{
+		Limit:     opts.Offset + opts.Limit,
+		OrderBy:   "timestamp",
+		Direction: firestore.Desc,
+	}
+	timeline := make([]*models.ActivityLog, 0)
+	for _, lead := range leads {
+		logs, err := s.activityLogService.GetLeadTimeline(ctx, tenantID, lead.ID, window)
+		if err != nil {
+			return nil, err
+		}
+		timeline = append(timeline, logs...)
+	}
+
+	sort.SliceStable(timeline, func(i, j int) bool {
+		return timeline[i].Timestamp.After(timeline[j].Timestamp)
+	})
+
+	if opts.Offset >= len(timeline) {
+		return make([]*models.ActivityLog, 0), nil
+	}
+	timeline = timeline[opts.Offset:]
+	if len(timeline) > opts.Limit {
+		timeline = timeline[:opts.Limit]
+	}
+
+	return timeline, nil
+}
+
+// MergeContactsInput merges a duplicate contact (source) into the contact that remains (target)
+type MergeContactsInput struct {
+	SourceID   string
+	Resolution map[models.ContactMergeField]models.ContactMergeSide // side kept for each conflicting field
+}
+
+// PreviewMerge returns the fields that differ on both contacts (to be resolved in MergeContacts)
+func (s *ContactService) PreviewMerge(ctx context.Context, tenantID, targetID, sourceID string) ([]models.ContactMergeConflict, error) {
+	target, source, err := s.getMergePair(ctx, tenantID, targetID, sourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	return target.MergeConflicts(source), nil
+}
+
+// MergeContacts merges the source contact into the target: the source leads move to the target, each
+// conflicting field takes the chosen side and the source contact is deleted
+// Restricted to admins (or the contacts.merge permission); returns the conflicts with
+// ErrContactMergeConflict when a conflicting field has no resolution
+func (s *ContactService) MergeContacts(ctx context.Context, tenantID, targetID, actorID string, input MergeContactsInput) (*models.Contact, []models.ContactMergeConflict, error) {
+	if err := s.authorizeMerge(ctx, tenantID, actorID); err != nil {
+		return nil, nil, err
+	}
+
+	target, source, err := s.getMergePair(ctx, tenantID, targetID, input.SourceID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	conflicts := target.MergeConflicts(source)
+	if unresolved := target.Merge(source, input.Resolution); len(unresolved) > 0 {
+		return nil, conflicts, fmt.Errorf("%w: %v", ErrContactMergeConflict, unresolved)
+	}
+
+	leads, err := s.leadRepo.ListByContactID(ctx, tenantID, source.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list contact leads: %w", err)
+	}
+
+	// Target, source and leads change in one transaction (a concurrent merge of the source fails)
+	writes := make([]repositories.TxWrite, 0, len(leads)+2)
+	write, err := s.contactRepo.SetTx(ctx, target)
+	if err != nil {
+		return nil, nil, err
+	}
+	writes = append(writes, write)
+	if write, err = s.contactRepo.DeleteTx(tenantID, source.ID); err != nil {
+		return nil, nil, err
+	}
+	writes = append(writes, write)
+	if write, err = s.contactRepo.MoveKeysTx(ctx, tenantID, source.ID, target.ID); err != nil {
+		return nil, nil, err
+	}
+	writes = append(writes, write)
+
+	leadIDs := make([]string, 0, len(leads))
+	for _, lead := range leads {
+		write, err := s.leadRepo.UpdateTx(ctx, tenantID, lead.ID, map[string]interface{}{"contact_id": target.ID})
+		if err != nil {
+			return nil, nil, err
+		}
+		writes = append(writes, write)
+		leadIDs = append(leadIDs, lead.ID)
+	}
+
+	if err := s.contactRepo.RunTransaction(ctx, writes...); err != nil {
+		return nil, nil, fmt.Errorf("failed to merge contacts: %w", err)
+	}
+
+	// LGPD: the audit trail records the chosen sides, not the personal data
+	resolution := make(map[string]interface{}, len(conflicts))
+	for _, conflict := range conflicts {
+		resolution[string(conflict.Field)] = string(input.Resolution[conflict.Field])
+	}
+	_ = s.logActivity(ctx, tenantID, "contacts_merged", models.ActorTypeUser, actorID, map[string]interface{}{
+		"contact_id":        target.ID,
+		"source_contact_id": source.ID,
+		"lead_ids":          leadIDs,
+		"resolution":        resolution,
+	})
+
+	return target, nil, nil
+}
+
+// getMergePair retrieves the target and source contacts of a merge
+func (s *ContactService) getMergePair(ctx context.Context, tenantID, targetID, sourceID string) (*models.Contact, *models.Contact, error) {
+	if sourceID == "" {
+		return nil, nil, fmt.Errorf("%w: source_contact_id is required", repositories.ErrInvalidInput)
+	}
+	if targetID == sourceID {
+		return nil, nil, ErrContactMergeSameContact
+	}
+
+	target, err := s.GetContact(ctx, tenantID, targetID)
+	if err != nil {
+		return nil, nil, err
+	}
+	source, err := s.GetContact(ctx, tenantID, sourceID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return target, source, nil
+}
+
+// authorizeMerge checks that the actor is an active tenant user allowed to merge contacts
+func (s *ContactService) authorizeMerge(ctx context.Context, tenantID, actorID string) error {
+	user, err := s.userRepo.GetByFirebaseUID(ctx, tenantID, actorID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return ErrContactMergeForbidden
+		}
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if !user.IsActive || !user.HasPermission(models.PermissionMergeContacts) {
+		return ErrContactMergeForbidden
+	}
+
+	return nil
+}
+
+// logActivity logs an activity (helper method)
+func (s *ContactService) logActivity(ctx context.Context, tenantID, eventType string, actorType models.ActorType, actorID string, metadata map[string]interface{}) error {
+	log := &models.ActivityLog{
+		TenantID:  tenantID,
+		EventType: eventType,
+		ActorType: actorType,
+		ActorID:   actorID,
+		Metadata:  metadata,
+		Timestamp: time.Now(),
+	}
+
+	return appendActivityLog(ctx, s.activityLogRepo, log)
+}
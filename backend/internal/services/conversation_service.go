@@ -0,0 +1,461 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+var (
+	// ErrNoMessagingProvider is returned when no messaging provider sends on the requested channel
+	ErrNoMessagingProvider = errors.New("no messaging provider for this channel")
+
+	// ErrWhatsAppWindowClosed is returned when a free-form WhatsApp reply is sent more than 24 hours
+	// after the lead's last WhatsApp message
+	ErrWhatsAppWindowClosed = errors.New("whatsapp service window is closed: the lead has not written in the last 24 hours")
+
+	// ErrContactConsentRevoked is returned when replying to a lead that revoked its consent (LGPD)
+	ErrContactConsentRevoked = errors.New("lead revoked its consent to be contacted")
+)
+
+// maxConversationBodyLength limits replies, notes and call notes
+const maxConversationBodyLength = 4096
+
+// ConversationService manages the unified inbox of leads: messages across channels, broker notes,
+// call logs, read/unread state, assignment and replies through the messaging providers
+type ConversationService struct {
+	conversationRepo *repositories.ConversationRepository
+	leadRepo         *repositories.LeadRepository
+	brokerRepo       *repositories.BrokerRepository
+	tenantRepo       *repositories.TenantRepository
+	activityLogRepo  *repositories.ActivityLogRepository
+	providers        map[models.LeadChannel]MessagingProvider
+	leadService      *LeadService        // Optional - routed broker assigned to new conversations
+	usageService     *UsageService       // Optional - messages_sent metering
+	scoringService   *LeadScoringService // Optional - lead score (response latency) recalculated on messages
+}
+
+// NewConversationService creates a new conversation service
+func NewConversationService(
+	conversationRepo *repositories.ConversationRepository,
+	leadRepo *repositories.LeadRepository,
+	brokerRepo *repositories.BrokerRepository,
+	tenantRepo *repositories.TenantRepository,
+	activityLogRepo *repositories.ActivityLogRepository,
+) *ConversationService {
+	return &ConversationService{
+		conversationRepo: conversationRepo,
+		leadRepo:         leadRepo,
+		brokerRepo:       brokerRepo,
+		tenantRepo:       tenantRepo,
+		activityLogRepo:  activityLogRepo,
+		providers:        make(map[models.LeadChannel]MessagingProvider),
+	}
+}
+
+// RegisterProvider sets the messaging provider of its channel (replaces the previous one)
+func (s *ConversationService) RegisterProvider(provider MessagingProvider) {
+	s.providers[provider.Channel()] = provider
+}
+
+// SetLeadService sets the lead service used to assign new conversations to the routed broker (for dependency injection)
+func (s *ConversationService) SetLeadService(leadService *LeadService) {
+	s.leadService = leadService
+}
+
+// SetUsageService sets the usage service that meters the messages sent (for dependency injection)
+func (s *ConversationService) SetUsageService(usageService *UsageService) {
+	s.usageService = usageService
+}
+
+// SetLeadScoringService sets the lead scoring service that rescores the lead on each message (for dependency injection)
+func (s *ConversationService) SetLeadScoringService(scoringService *LeadScoringService) {
+	s.scoringService = scoringService
+}
+
+// SendReplyInput is a reply sent to a lead
+type SendReplyInput struct {
+	Channel models.LeadChannel
+	Subject string // e-mail
+	Body    string
+}
+
+// LogCallInput is a call with a lead
+type LogCallInput struct {
+	Direction       models.MessageDirection // outbound = broker called the lead
+	Outcome         models.CallOutcome
+	DurationSeconds int
+	Notes           string
+	CalledAt        time.Time // default: now
+}
+
+// RecordMessage stores an entry in the conversation of a lead and updates the inbox summary
+// A new conversation is assigned to the broker the lead is routed to
+// Returns repositories.ErrAlreadyExists for a provider message already recorded
+func (s *ConversationService) RecordMessage(ctx context.Context, lead *models.Lead, message *models.ConversationMessage) (*models.Conversation, error) {
+	message.TenantID = lead.TenantID
+	message.LeadID = lead.ID
+	if message.Kind == "" {
+		message.Kind = models.MessageKindMessage
+	}
+
+	if err := s.conversationRepo.CreateMessage(ctx, message); err != nil {
+		return nil, err
+	}
+
+	// Routing reads other collections: resolve it before the transaction
+	assignee := ""
+	if s.leadService != nil {
+		if _, err := s.conversationRepo.GetConversation(ctx, lead.TenantID, lead.ID); errors.Is(err, repositories.ErrNotFound) {
+			assignee, _ = s.leadService.routedBrokerID(ctx, lead.TenantID, lead.PropertyID)
+		}
+	}
+
+	conversation, err := s.conversationRepo.UpdateConversation(ctx, lead.TenantID, lead.ID, func(conversation *models.Conversation, exists bool) error {
+		if !exists {
+			conversation.PropertyID = lead.PropertyID
+			if assignee != "" {
+				now := time.Now()
+				conversation.AssignedBrokerID = assignee
+				conversation.AssignedAt = &now
+			}
+		}
+		if lead.Name != "" {
+			conversation.ContactName = lead.Name
+		}
+		conversation.ApplyMessage(message)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update conversation: %w", err)
+	}
+
+	// Outreach and replies change the response latency of the lead
+	if s.scoringService != nil && message.Kind != models.MessageKindNote {
+		if _, err := s.scoringService.ScoreLead(ctx, lead.TenantID, lead.ID); err != nil {
+			log.Printf("Failed to rescore lead %s: %v", lead.ID, err)
+		}
+	}
+
+	return conversation, nil
+}
+
+// ListConversations retrieves the inbox of a tenant (most recent activity first)
+func (s *ConversationService) ListConversations(ctx context.Context, tenantID string, filters *repositories.ConversationFilters, opts repositories.PaginationOptions) ([]*models.Conversation, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+
+	conversations, err := s.conversationRepo.ListConversations(ctx, tenantID, filters, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list conversations: %w", err)
+	}
+
+	return conversations, nil
+}
+
+// GetConversation retrieves the conversation of a lead (empty when nothing was exchanged yet)
+func (s *ConversationService) GetConversation(ctx context.Context, tenantID, leadID string) (*models.Conversation, error) {
+	lead, err := s.getLead(ctx, tenantID, leadID)
+	if err != nil {
+		return nil, err
+	}
+
+	conversation, err := s.conversationRepo.GetConversation(ctx, tenantID, leadID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return &models.Conversation{LeadID: lead.ID, TenantID: tenantID, PropertyID: lead.PropertyID, ContactName: lead.Name}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conversation: %w", err)
+	}
+
+	return conversation, nil
+}
+
+// SendReply sends a reply to the lead through the provider of the channel and records it in the conversation
+func (s *ConversationService) SendReply(ctx context.Context, tenantID, leadID, authorID string, input SendReplyInput) (*models.ConversationMessage, error) {
+	body := strings.TrimSpace(input.Body)
+	if body == "" {
+		return nil, fmt.Errorf("%w: body is required", repositories.ErrInvalidInput)
+	}
+	if len(body) > maxConversationBodyLength {
+		return nil, fmt.Errorf("%w: body must have at most %d characters", repositories.ErrInvalidInput, maxConversationBodyLength)
+	}
+
+	provider, ok := s.providers[input.Channel]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNoMessagingProvider, input.Channel)
+	}
+
+	lead, err := s.getLead(ctx, tenantID, leadID)
+	if err != nil {
+		return nil, err
+	}
+	if lead.ConsentRevoked {
+		return nil, ErrContactConsentRevoked
+	}
+
+	if input.Channel == models.LeadChannelWhatsApp {
+		conversation, err := s.conversationRepo.GetConversation(ctx, tenantID, leadID)
+		if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+			return nil, fmt.Errorf("failed to get conversation: %w", err)
+		}
+		if conversation == nil || !conversation.WhatsAppWindowOpen(time.Now()) {
+			return nil, ErrWhatsAppWindowClosed
+		}
+	}
+
+	tenant, err := s.tenantRepo.Get(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant: %w", err)
+	}
+
+	externalID, err := provider.Send(ctx, OutboundMessage{
+		Tenant:  tenant,
+		Lead:    lead,
+		Subject: input.Subject,
+		Body:    body,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to send reply: %w", err)
+	}
+
+	to := lead.Phone
+	if input.Channel == models.LeadChannelEmail {
+		to = lead.Email
+	}
+	message := &models.ConversationMessage{
+		Channel:    input.Channel,
+		Direction:  models.MessageDirectionOutbound,
+		AuthorID:   authorID,
+		Subject:    input.Subject,
+		Body:       body,
+		To:         to,
+		Provider:   provider.Name(),
+		ExternalID: externalID,
+	}
+	if _, err := s.RecordMessage(ctx, lead, message); err != nil {
+		return nil, fmt.Errorf("reply sent but not recorded: %w", err)
+	}
+
+	if s.usageService != nil {
+		s.usageService.Record(ctx, tenantID, models.UsageMetricMessagesSent, 1)
+	}
+
+	_ = s.logActivity(ctx, tenantID, "conversation_reply_sent", models.ActorTypeUser, authorID, map[string]interface{}{
+		"lead_id":     leadID,
+		"property_id": lead.PropertyID,
+		"message_id":  message.ID,
+		"channel":     input.Channel,
+		"provider":    provider.Name(),
+	})
+
+	return message, nil
+}
+
+// AddNote records an internal note of a broker in the conversation (never sent to the lead)
+func (s *ConversationService) AddNote(ctx context.Context, tenantID, leadID, authorID, body string) (*models.ConversationMessage, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return nil, fmt.Errorf("%w: body is required", repositories.ErrInvalidInput)
+	}
+	if len(body) > maxConversationBodyLength {
+		return nil, fmt.Errorf("%w: body must have at most %d characters", repositories.ErrInvalidInput, maxConversationBodyLength)
+	}
+
+	lead, err := s.getLead(ctx, tenantID, leadID)
+	if err != nil {
+		return nil, err
+	}
+
+	message := &models.ConversationMessage{
+		Kind:      models.MessageKindNote,
+		Direction: models.MessageDirectionOutbound,
+		AuthorID:  authorID,
+		Body:      body,
+	}
+	if _, err := s.RecordMessage(ctx, lead, message); err != nil {
+		return nil, err
+	}
+
+	_ = s.logActivity(ctx, tenantID, "conversation_note_added", models.ActorTypeUser, authorID, map[string]interface{}{
+		"lead_id":    leadID,
+		"message_id": message.ID,
+	})
+
+	return message, nil
+}
+
+// LogCall records a phone call with the lead in the conversation
+func (s *ConversationService) LogCall(ctx context.Context, tenantID, leadID, authorID string, input LogCallInput) (*models.ConversationMessage, error) {
+	if input.Direction == "" {
+		input.Direction = models.MessageDirectionOutbound
+	}
+	if input.Direction != models.MessageDirectionInbound && input.Direction != models.MessageDirectionOutbound {
+		return nil, fmt.Errorf("%w: direction must be inbound or outbound", repositories.ErrInvalidInput)
+	}
+	if !models.IsValidCallOutcome(input.Outcome) {
+		return nil, fmt.Errorf("%w: invalid call outcome", repositories.ErrInvalidInput)
+	}
+	if input.DurationSeconds < 0 {
+		return nil, fmt.Errorf("%w: duration_seconds must not be negative", repositories.ErrInvalidInput)
+	}
+	notes := strings.TrimSpace(input.Notes)
+	if len(notes) > maxConversationBodyLength {
+		return nil, fmt.Errorf("%w: notes must have at most %d characters", repositories.ErrInvalidInput, maxConversationBodyLength)
+	}
+	if input.CalledAt.IsZero() {
+		input.CalledAt = time.Now()
+	}
+
+	lead, err := s.getLead(ctx, tenantID, leadID)
+	if err != nil {
+		return nil, err
+	}
+
+	message := &models.ConversationMessage{
+		Kind:                models.MessageKindCall,
+		Channel:             models.LeadChannelPhone,
+		Direction:           input.Direction,
+		AuthorID:            authorID,
+		Body:                notes,
+		CallOutcome:         input.Outcome,
+		CallDurationSeconds: input.DurationSeconds,
+		SentAt:              input.CalledAt,
+	}
+	if _, err := s.RecordMessage(ctx, lead, message); err != nil {
+		return nil, err
+	}
+
+	_ = s.logActivity(ctx, tenantID, "conversation_call_logged", models.ActorTypeUser, authorID, map[string]interface{}{
+		"lead_id":          leadID,
+		"message_id":       message.ID,
+		"direction":        input.Direction,
+		"outcome":          input.Outcome,
+		"duration_seconds": input.DurationSeconds,
+	})
+
+	return message, nil
+}
+
+// MarkRead marks the conversation of a lead as read
+func (s *ConversationService) MarkRead(ctx context.Context, tenantID, leadID, userID string) (*models.Conversation, error) {
+	return s.updateReadState(ctx, tenantID, leadID, func(conversation *models.Conversation) {
+		conversation.MarkRead(userID, time.Now())
+	})
+}
+
+// MarkUnread flags the conversation of a lead as unread
+func (s *ConversationService) MarkUnread(ctx context.Context, tenantID, leadID string) (*models.Conversation, error) {
+	return s.updateReadState(ctx, tenantID, leadID, func(conversation *models.Conversation) {
+		conversation.MarkUnread()
+	})
+}
+
+// updateReadState changes the read state of an existing conversation
+func (s *ConversationService) updateReadState(ctx context.Context, tenantID, leadID string, change func(conversation *models.Conversation)) (*models.Conversation, error) {
+	if _, err := s.getLead(ctx, tenantID, leadID); err != nil {
+		return nil, err
+	}
+
+	conversation, err := s.conversationRepo.UpdateConversation(ctx, tenantID, leadID, func(conversation *models.Conversation, exists bool) error {
+		if !exists {
+			return repositories.ErrNotFound
+		}
+		change(conversation)
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to update conversation: %w", err)
+	}
+
+	return conversation, nil
+}
+
+// Assign assigns the conversation of a lead to a broker
+func (s *ConversationService) Assign(ctx context.Context, tenantID, leadID, brokerID, actorID string) (*models.Conversation, error) {
+	if brokerID == "" {
+		return nil, fmt.Errorf("%w: broker_id is required", repositories.ErrInvalidInput)
+	}
+
+	lead, err := s.getLead(ctx, tenantID, leadID)
+	if err != nil {
+		return nil, err
+	}
+
+	broker, err := s.brokerRepo.Get(ctx, tenantID, brokerID)
+	if err != nil {
+		return nil, fmt.Errorf("broker not found: %w", err)
+	}
+	if !broker.IsActive {
+		return nil, fmt.Errorf("%w: broker is not active", repositories.ErrInvalidInput)
+	}
+
+	previous := ""
+	conversation, err := s.conversationRepo.UpdateConversation(ctx, tenantID, leadID, func(conversation *models.Conversation, exists bool) error {
+		if !exists {
+			conversation.PropertyID = lead.PropertyID
+			conversation.ContactName = lead.Name
+		}
+		previous = conversation.AssignedBrokerID
+		now := time.Now()
+		conversation.AssignedBrokerID = brokerID
+		conversation.AssignedAt = &now
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to assign conversation: %w", err)
+	}
+
+	_ = s.logActivity(ctx, tenantID, "conversation_assigned", models.ActorTypeUser, actorID, map[string]interface{}{
+		"lead_id":            leadID,
+		"property_id":        lead.PropertyID,
+		"broker_id":          brokerID,
+		"previous_broker_id": previous,
+	})
+
+	return conversation, nil
+}
+
+// getLead retrieves a lead that is not anonymized
+func (s *ConversationService) getLead(ctx context.Context, tenantID, leadID string) (*models.Lead, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+	if leadID == "" {
+		return nil, fmt.Errorf("%w: lead ID is required", repositories.ErrInvalidInput)
+	}
+
+	lead, err := s.leadRepo.Get(ctx, tenantID, leadID)
+	if err != nil {
+		return nil, err
+	}
+	if lead.IsAnonymized {
+		return nil, fmt.Errorf("%w: lead data has been anonymized", repositories.ErrInvalidInput)
+	}
+
+	return lead, nil
+}
+
+// logActivity logs an activity
+func (s *ConversationService) logActivity(ctx context.Context, tenantID, eventType string, actorType models.ActorType, actorID string, metadata map[string]interface{}) error {
+	log := &models.ActivityLog{
+		TenantID:  tenantID,
+		EventType: eventType,
+		ActorType: actorType,
+		ActorID:   actorID,
+		Metadata:  metadata,
+		Timestamp: time.Now(),
+	}
+
+	return appendActivityLog(ctx, s.activityLogRepo, log)
+}
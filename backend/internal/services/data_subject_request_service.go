@@ -0,0 +1,1034 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/utils"
+)
+
+const (
+	// dsrVerificationCodeTTL is the validity of the code sent to confirm the subject's identity
+	dsrVerificationCodeTTL = 30 * time.Minute
+
+	// dsrMaxVerificationAttempts blocks brute forcing the 6-digit code (counted across resent codes)
+	dsrMaxVerificationAttempts = 5
+
+	// dsrResendCooldown is the minimum interval between two verification codes of a request
+	dsrResendCooldown = time.Minute
+
+	// dsrMaxVerificationResends is the number of times a code can be resent for a request
+	dsrMaxVerificationResends = 3
+
+	// dsrActivityLogLimit is the maximum number of activity logs collected per matched record
+	dsrActivityLogLimit = 500
+)
+
+// DSR match actions recorded on erasure
+const (
+	dsrActionAnonymized = "anonymized"
+	dsrActionCancelled  = "cancelled"
+	dsrActionDeleted    = "deleted"
+	dsrActionPIIRemoved = "pii_removed" // activity log mantido sem os metadados pessoais (IP, nome, contato)
+	dsrActionRetained   = "retained"    // trilha de auditoria mantida (obrigação legal - LGPD Art. 16, I)
+)
+
+var (
+	// ErrDSRResendThrottled is returned when a verification code is resent too soon or too many times
+	ErrDSRResendThrottled = errors.New("verification code resend limit reached")
+
+	// ErrDSRWhatsAppUnavailable is returned when a phone-only request cannot receive the code by WhatsApp
+	ErrDSRWhatsAppUnavailable = errors.New("phone verification is not available: please inform an e-mail")
+)
+
+// DataSubjectRequestService handles LGPD data subject requests (acesso, portabilidade, eliminação)
+// Fontes pesquisadas: proprietários, leads, contatos, conversas, activity logs e confirmações agendadas.
+// Buscas salvas ainda não existem como coleção persistida - quando existirem, devem ser
+// adicionadas em collectSubjectData.
+type DataSubjectRequestService struct {
+	requestRepo               *repositories.DataSubjectRequestRepository
+	ownerRepo                 *repositories.OwnerRepository
+	leadRepo                  *repositories.LeadRepository
+	contactRepo               *repositories.ContactRepository
+	conversationRepo          *repositories.ConversationRepository
+	activityLogRepo           *repositories.ActivityLogRepository
+	scheduledConfirmationRepo *repositories.ScheduledConfirmationRepository
+	tenantRepo                *repositories.TenantRepository
+	ownerService              *OwnerService
+	leadService               *LeadService
+	emailService              *EmailService
+	whatsAppProvider          MessagingProvider // Optional - verification codes of phone-only requests
+}
+
+// NewDataSubjectRequestService creates a new data subject request service
+func NewDataSubjectRequestService(
+	requestRepo *repositories.DataSubjectRequestRepository,
+	ownerRepo *repositories.OwnerRepository,
+	leadRepo *repositories.LeadRepository,
+	contactRepo *repositories.ContactRepository,
+	conversationRepo *repositories.ConversationRepository,
+	activityLogRepo *repositories.ActivityLogRepository,
+	scheduledConfirmationRepo *repositories.ScheduledConfirmationRepository,
+	tenantRepo *repositories.TenantRepository,
+	ownerService *OwnerService,
+	leadService *LeadService,
+	emailService *EmailService,
+) *DataSubjectRequestService {
+	return &DataSubjectRequestService{
+		requestRepo:               requestRepo,
+		ownerRepo:                 ownerRepo,
+		leadRepo:                  leadRepo,
+		contactRepo:               contactRepo,
+		conversationRepo:          conversationRepo,
+		activityLogRepo:           activityLogRepo,
+		scheduledConfirmationRepo: scheduledConfirmationRepo,
+		tenantRepo:                tenantRepo,
+		ownerService:              ownerService,
+		leadService:               leadService,
+		emailService:              emailService,
+	}
+}
+
+// SetWhatsAppProvider sets the provider that sends verification codes by WhatsApp (for dependency injection)
+func (s *DataSubjectRequestService) SetWhatsAppProvider(provider MessagingProvider) {
+	s.whatsAppProvider = provider
+}
+
+// CreateDataSubjectRequestInput represents a request submitted by the data subject
+type CreateDataSubjectRequestInput struct {
+	TenantID string
+	Type     models.DSRType
+	Name     string
+	Email    string
+	Phone    string
+	Document string
+	Details  string
+	ClientIP string
+}
+
+// DataSubjectExport is the machine-readable copy of the subject's data (portabilidade)
+type DataSubjectExport struct {
+	RequestID              string                          `json:"request_id"`
+	TenantID               string                          `json:"tenant_id"`
+	GeneratedAt            time.Time                       `json:"generated_at"`
+	MatchedIdentifiers     []string                        `json:"matched_identifiers"`
+	Matches                []models.DSRMatch               `json:"matches"`
+	Owners                 []*models.Owner                 `json:"owners"`
+	Leads                  []*models.Lead                  `json:"leads"`
+	Contacts               []*models.Contact               `json:"contacts"`
+	Conversations          []*DataSubjectConversation      `json:"conversations"`
+	ActivityLogs           []*models.ActivityLog           `json:"activity_logs"`
+	ScheduledConfirmations []*models.ScheduledConfirmation `json:"scheduled_confirmations"`
+}
+
+// DataSubjectConversation is the conversation history of one of the subject's leads
+type DataSubjectConversation struct {
+	LeadID       string                        `json:"lead_id"`
+	Conversation *models.Conversation          `json:"conversation,omitempty"`
+	Messages     []*models.ConversationMessage `json:"messages"`
+}
+
+// DSRAuditReport summarizes the data subject requests of a period (prestação de contas à ANPD)
+type DSRAuditReport struct {
+	TenantID        string                       `json:"tenant_id"`
+	From            time.Time                    `json:"from"`
+	To              time.Time                    `json:"to"`
+	Total           int                          `json:"total"`
+	ByType          map[models.DSRType]int       `json:"by_type"`
+	ByStatus        map[models.DSRStatus]int     `json:"by_status"`
+	AnsweredOnTime  int                          `json:"answered_on_time"`
+	AnsweredLate    int                          `json:"answered_late"`
+	AvgResponseDays float64                      `json:"avg_response_days"`
+	Overdue         []*models.DataSubjectRequest `json:"overdue"`
+}
+
+// subjectData holds every record found for a data subject
+type subjectData struct {
+	owners                 []*models.Owner
+	leads                  []*models.Lead
+	contacts               []*models.Contact
+	conversations          []*DataSubjectConversation
+	activityLogs           []*models.ActivityLog
+	scheduledConfirmations []*models.ScheduledConfirmation
+
+	matchedBy          map[string][]string // collection/document ID -> identifiers (email, phone, document)
+	matchedIdentifiers []string            // informed identifiers that found records
+}
+
+// CreateRequest registers a data subject request and sends the identity verification code
+// The code goes to the email informed (or WhatsApp when only the phone is informed); once verified, every
+// informed identifier is used to find the subject's records and the matches report which one found each record
+func (s *DataSubjectRequestService) CreateRequest(ctx context.Context, input CreateDataSubjectRequestInput) (*models.DataSubjectRequest, error) {
+	if input.TenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+
+	validTypes := map[models.DSRType]bool{
+		models.DSRTypeAccess:      true,
+		models.DSRTypePortability: true,
+		models.DSRTypeErasure:     true,
+	}
+	if !validTypes[input.Type] {
+		return nil, fmt.Errorf("invalid request type: must be 'access', 'portability' or 'erasure'")
+	}
+	if strings.TrimSpace(input.Name) == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	if input.Email == "" && input.Phone == "" {
+		return nil, fmt.Errorf("email or phone is required")
+	}
+
+	request := &models.DataSubjectRequest{
+		TenantID:    input.TenantID,
+		Type:        input.Type,
+		Status:      models.DSRStatusPendingVerification,
+		SubjectName: strings.TrimSpace(input.Name),
+		Details:     strings.TrimSpace(input.Details),
+		RequestIP:   input.ClientIP,
+	}
+
+	if input.Email != "" {
+		if err := utils.ValidateEmail(input.Email); err != nil {
+			return nil, err
+		}
+		request.SubjectEmail = utils.NormalizeEmail(input.Email)
+	}
+	if input.Phone != "" {
+		if err := utils.ValidatePhoneBR(normalizeOwnerPhone(input.Phone)); err != nil {
+			return nil, err
+		}
+		request.SubjectPhone = normalizeOwnerPhone(input.Phone)
+	}
+	if input.Document != "" {
+		if err := utils.ValidateCPF(input.Document); err != nil {
+			return nil, err
+		}
+		request.SubjectDocument = utils.NormalizeCPF(input.Document)
+	}
+
+	request.VerificationChannel = models.DSRVerificationChannelEmail
+	if request.SubjectEmail == "" {
+		request.VerificationChannel = models.DSRVerificationChannelWhatsApp
+		if !s.canSendWhatsApp(ctx, request.TenantID) {
+			return nil, ErrDSRWhatsAppUnavailable
+		}
+	}
+
+	code, err := generateVerificationCode()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	request.VerificationCodeHash = hashPortalToken(code)
+	request.VerificationExpiry = now.Add(dsrVerificationCodeTTL)
+	request.VerificationSentAt = now
+
+	if err := s.requestRepo.Create(ctx, request); err != nil {
+		return nil, err
+	}
+
+	if err := s.sendVerificationCode(ctx, request, code); err != nil {
+		return nil, err
+	}
+
+	_ = s.logActivity(ctx, request.TenantID, "dsr_received", models.ActorTypeSystem, "", map[string]interface{}{
+		"request_id":           request.ID,
+		"request_type":         string(request.Type),
+		"verification_channel": request.VerificationChannel,
+		"client_ip":            input.ClientIP,
+	})
+
+	return request, nil
+}
+
+// ResendVerificationCode issues a new code for a request still pending verification
+// Resends are rate limited and keep the attempt counter, so new codes don't give new guesses
+func (s *DataSubjectRequestService) ResendVerificationCode(ctx context.Context, tenantID, requestID string) error {
+	request, err := s.requestRepo.Get(ctx, tenantID, requestID)
+	if err != nil {
+		return err
+	}
+	if request.Status != models.DSRStatusPendingVerification {
+		return fmt.Errorf("request is not pending verification")
+	}
+	if request.VerificationAttempts >= dsrMaxVerificationAttempts {
+		return fmt.Errorf("too many attempts: submit a new request")
+	}
+
+	now := time.Now()
+	if request.VerificationResends >= dsrMaxVerificationResends {
+		return fmt.Errorf("%w: submit a new request", ErrDSRResendThrottled)
+	}
+	if now.Sub(request.VerificationSentAt) < dsrResendCooldown {
+		return fmt.Errorf("%w: wait before requesting a new code", ErrDSRResendThrottled)
+	}
+
+	code, err := generateVerificationCode()
+	if err != nil {
+		return err
+	}
+	expiry := now.Add(dsrVerificationCodeTTL)
+
+	if err := s.requestRepo.Update(ctx, tenantID, requestID, map[string]interface{}{
+		"verification_code_hash": hashPortalToken(code),
+		"verification_expiry":    expiry,
+		"verification_sent_at":   now,
+		"verification_resends":   request.VerificationResends + 1,
+	}); err != nil {
+		return err
+	}
+	request.VerificationExpiry = expiry
+
+	return s.sendVerificationCode(ctx, request, code)
+}
+
+// VerifyRequest confirms the subject's identity; the 15-day legal deadline starts here
+func (s *DataSubjectRequestService) VerifyRequest(ctx context.Context, tenantID, requestID, code string) (*models.DataSubjectRequest, error) {
+	if code == "" {
+		return nil, fmt.Errorf("code is required")
+	}
+
+	// Every guess takes an attempt (in a transaction) before the code is compared
+	request, err := s.requestRepo.TakeVerificationAttempt(ctx, tenantID, requestID, checkVerificationAttempt)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if now.After(request.VerificationExpiry) || hashPortalToken(strings.TrimSpace(code)) != request.VerificationCodeHash {
+		return nil, fmt.Errorf("invalid or expired code")
+	}
+
+	dueAt := now.AddDate(0, 0, models.DSRResponseDeadlineDays)
+	if err := s.requestRepo.Update(ctx, tenantID, requestID, map[string]interface{}{
+		"status":                 string(models.DSRStatusVerified),
+		"verified_at":            now,
+		"due_at":                 dueAt,
+		"verification_code_hash": "",
+	}); err != nil {
+		return nil, err
+	}
+
+	request.Status = models.DSRStatusVerified
+	request.VerifiedAt = &now
+	request.DueAt = &dueAt
+
+	_ = s.logActivity(ctx, tenantID, "dsr_verified", models.ActorTypeSystem, "", map[string]interface{}{
+		"request_id":   request.ID,
+		"request_type": string(request.Type),
+		"due_at":       dueAt,
+	})
+
+	return request, nil
+}
+
+// checkVerificationAttempt checks that a request can still take a verification attempt
+func checkVerificationAttempt(request *models.DataSubjectRequest) error {
+	if request.Status != models.DSRStatusPendingVerification {
+		return fmt.Errorf("request is not pending verification")
+	}
+	if request.VerificationAttempts >= dsrMaxVerificationAttempts {
+		return fmt.Errorf("too many attempts: submit a new request")
+	}
+	return nil
+}
+
+// GetRequest retrieves a data subject request
+func (s *DataSubjectRequestService) GetRequest(ctx context.Context, tenantID, requestID string) (*models.DataSubjectRequest, error) {
+	return s.requestRepo.Get(ctx, tenantID, requestID)
+}
+
+// ListRequests lists data subject requests, optionally by status or only the overdue ones
+func (s *DataSubjectRequestService) ListRequests(ctx context.Context, tenantID string, status models.DSRStatus, overdueOnly bool) ([]*models.DataSubjectRequest, error) {
+	if overdueOnly {
+		status = models.DSRStatusVerified
+	}
+
+	requests, err := s.requestRepo.List(ctx, tenantID, status)
+	if err != nil {
+		return nil, err
+	}
+	if !overdueOnly {
+		return requests, nil
+	}
+
+	now := time.Now()
+	overdue := make([]*models.DataSubjectRequest, 0)
+	for _, request := range requests {
+		if request.IsOverdue(now) {
+			overdue = append(overdue, request)
+		}
+	}
+	return overdue, nil
+}
+
+// SearchSubjectData looks up the subject in every tenant collection and records the matches
+func (s *DataSubjectRequestService) SearchSubjectData(ctx context.Context, tenantID, requestID, actorID string) (*models.DataSubjectRequest, error) {
+	request, err := s.getVerifiedRequest(ctx, tenantID, requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.collectSubjectData(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := buildDSRMatches(data)
+	now := time.Now()
+	if err := s.requestRepo.Update(ctx, tenantID, requestID, map[string]interface{}{
+		"matches":             matches,
+		"matched_identifiers": data.matchedIdentifiers,
+		"searched_at":         now,
+		"handled_by":          actorID,
+	}); err != nil {
+		return nil, err
+	}
+
+	request.Matches = matches
+	request.MatchedIdentifiers = data.matchedIdentifiers
+	request.SearchedAt = &now
+	request.HandledBy = actorID
+
+	_ = s.logActivity(ctx, tenantID, "dsr_searched", models.ActorTypeUser, actorID, map[string]interface{}{
+		"request_id":          request.ID,
+		"matches_count":       len(matches),
+		"matched_identifiers": data.matchedIdentifiers,
+	})
+
+	return request, nil
+}
+
+// ExportSubjectData builds the JSON export with every record found for the subject
+func (s *DataSubjectRequestService) ExportSubjectData(ctx context.Context, tenantID, requestID, actorID string) (*DataSubjectExport, error) {
+	request, err := s.getVerifiedRequest(ctx, tenantID, requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.collectSubjectData(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	export := &DataSubjectExport{
+		RequestID:              request.ID,
+		TenantID:               tenantID,
+		GeneratedAt:            now,
+		MatchedIdentifiers:     data.matchedIdentifiers,
+		Matches:                buildDSRMatches(data),
+		Owners:                 data.owners,
+		Leads:                  data.leads,
+		Contacts:               data.contacts,
+		Conversations:          data.conversations,
+		ActivityLogs:           data.activityLogs,
+		ScheduledConfirmations: data.scheduledConfirmations,
+	}
+
+	_ = s.requestRepo.Update(ctx, tenantID, requestID, map[string]interface{}{
+		"export_generated_at": now,
+		"handled_by":          actorID,
+	})
+
+	_ = s.logActivity(ctx, tenantID, "dsr_exported", models.ActorTypeUser, actorID, map[string]interface{}{
+		"request_id":                    request.ID,
+		"owners_count":                  len(data.owners),
+		"leads_count":                   len(data.leads),
+		"contacts_count":                len(data.contacts),
+		"conversations_count":           len(data.conversations),
+		"activity_logs_count":           len(data.activityLogs),
+		"scheduled_confirmations_count": len(data.scheduledConfirmations),
+	})
+
+	return export, nil
+}
+
+// EraseSubjectData anonymizes the subject's owners and leads, deletes their contacts, conversations and
+// WhatsApp thread and cancels pending confirmations
+// Activity logs are retained as the legal audit trail without their PII metadata (models.ActivityLogPIIMetadataKeys)
+func (s *DataSubjectRequestService) EraseSubjectData(ctx context.Context, tenantID, requestID, actorID string) (*models.DataSubjectRequest, error) {
+	request, err := s.getVerifiedRequest(ctx, tenantID, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if request.Type != models.DSRTypeErasure {
+		return nil, fmt.Errorf("only erasure requests can erase data")
+	}
+
+	data, err := s.collectSubjectData(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]models.DSRMatch, 0)
+
+	for _, sc := range data.scheduledConfirmations {
+		match := models.DSRMatch{Collection: "scheduled_confirmations", DocumentID: sc.ID, Description: string(sc.Status), Action: dsrActionRetained}
+		if sc.Status == models.ScheduledConfirmationStatusPending {
+			sc.Status = models.ScheduledConfirmationStatusCancelled
+			if err := s.scheduledConfirmationRepo.Update(ctx, sc); err != nil {
+				return nil, fmt.Errorf("failed to cancel scheduled confirmation %s: %w", sc.ID, err)
+			}
+			match.Action = dsrActionCancelled
+		}
+		matches = append(matches, match)
+	}
+
+	for _, owner := range data.owners {
+		if !owner.IsAnonymized {
+			if err := s.ownerService.AnonymizeOwner(ctx, tenantID, owner.ID, "user_request"); err != nil {
+				return nil, fmt.Errorf("failed to anonymize owner %s: %w", owner.ID, err)
+			}
+		}
+		matches = append(matches, models.DSRMatch{Collection: "owners", DocumentID: owner.ID, Action: dsrActionAnonymized})
+	}
+
+	for _, lead := range data.leads {
+		if !lead.IsAnonymized {
+			if err := s.leadService.AnonymizeLead(ctx, tenantID, lead.ID, "user_request"); err != nil {
+				return nil, fmt.Errorf("failed to anonymize lead %s: %w", lead.ID, err)
+			}
+		}
+		matches = append(matches, models.DSRMatch{Collection: "leads", DocumentID: lead.ID, Description: lead.PropertyID, Action: dsrActionAnonymized})
+	}
+
+	// Anonymizing a lead already deletes its conversation and detaches it from its contact; the deletes
+	// below cover conversations and contacts left behind (ex: lead anonymized before they existed)
+	for _, conversation := range data.conversations {
+		if err := s.conversationRepo.DeleteLeadConversation(ctx, tenantID, conversation.LeadID); err != nil {
+			return nil, fmt.Errorf("failed to delete conversation of lead %s: %w", conversation.LeadID, err)
+		}
+		matches = append(matches, models.DSRMatch{Collection: "conversations", DocumentID: conversation.LeadID, Action: dsrActionDeleted})
+	}
+
+	for _, contact := range data.contacts {
+		if err := s.contactRepo.Delete(ctx, tenantID, contact.ID); err != nil && !errors.Is(err, repositories.ErrNotFound) {
+			return nil, fmt.Errorf("failed to delete contact %s: %w", contact.ID, err)
+		}
+		matches = append(matches, models.DSRMatch{Collection: "contacts", DocumentID: contact.ID, Action: dsrActionDeleted})
+	}
+
+	if waID := models.WhatsAppRecipient(request.SubjectPhone); waID != "" {
+		if err := s.conversationRepo.DeleteWhatsAppThread(ctx, tenantID, waID); err != nil {
+			return nil, err
+		}
+	}
+
+	logMatches, logsPruned, err := eraseActivityLogsPII(ctx, s.activityLogRepo, tenantID, data.activityLogs)
+	if err != nil {
+		return nil, err
+	}
+	matches = append(matches, logMatches...)
+
+	matches = data.withMatchedBy(matches)
+	now := time.Now()
+	if err := s.requestRepo.Update(ctx, tenantID, requestID, map[string]interface{}{
+		"matches":             matches,
+		"matched_identifiers": data.matchedIdentifiers,
+		"erased_at":           now,
+		"handled_by":          actorID,
+	}); err != nil {
+		return nil, err
+	}
+
+	request.Matches = matches
+	request.MatchedIdentifiers = data.matchedIdentifiers
+	request.ErasedAt = &now
+	request.HandledBy = actorID
+
+	_ = s.logActivity(ctx, tenantID, "dsr_erased", models.ActorTypeUser, actorID, map[string]interface{}{
+		"request_id":                    request.ID,
+		"owners_anonymized":             len(data.owners),
+		"leads_anonymized":              len(data.leads),
+		"contacts_deleted":              len(data.contacts),
+		"conversations_deleted":         len(data.conversations),
+		"activity_logs_retained":        len(data.activityLogs),
+		"activity_logs_pii_removed":     logsPruned,
+		"scheduled_confirmations_count": len(data.scheduledConfirmations),
+	})
+
+	return request, nil
+}
+
+// CompleteRequest marks a verified request as answered
+func (s *DataSubjectRequestService) CompleteRequest(ctx context.Context, tenantID, requestID, actorID, notes string) (*models.DataSubjectRequest, error) {
+	request, err := s.getVerifiedRequest(ctx, tenantID, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if request.Type == models.DSRTypeErasure && request.ErasedAt == nil {
+		return nil, fmt.Errorf("erasure requests must be erased before completion")
+	}
+
+	return s.closeRequest(ctx, request, models.DSRStatusCompleted, actorID, map[string]interface{}{
+		"resolution_notes": notes,
+	})
+}
+
+// RejectRequest closes a verified request without fulfilling it (e.g. legal retention obligation)
+func (s *DataSubjectRequestService) RejectRequest(ctx context.Context, tenantID, requestID, actorID, reason string) (*models.DataSubjectRequest, error) {
+	if strings.TrimSpace(reason) == "" {
+		return nil, fmt.Errorf("rejection reason is required")
+	}
+
+	request, err := s.getVerifiedRequest(ctx, tenantID, requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.closeRequest(ctx, request, models.DSRStatusRejected, actorID, map[string]interface{}{
+		"rejection_reason": reason,
+	})
+}
+
+// GetAuditReport summarizes the requests created in [from, to)
+func (s *DataSubjectRequestService) GetAuditReport(ctx context.Context, tenantID string, from, to time.Time) (*DSRAuditReport, error) {
+	if !from.Before(to) {
+		return nil, fmt.Errorf("from must be before to")
+	}
+
+	requests, err := s.requestRepo.ListCreatedBetween(ctx, tenantID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildDSRAuditReport(tenantID, from, to, requests, time.Now()), nil
+}
+
+// buildDSRAuditReport aggregates requests into the audit report
+func buildDSRAuditReport(tenantID string, from, to time.Time, requests []*models.DataSubjectRequest, now time.Time) *DSRAuditReport {
+	report := &DSRAuditReport{
+		TenantID: tenantID,
+		From:     from,
+		To:       to,
+		Total:    len(requests),
+		ByType:   make(map[models.DSRType]int),
+		ByStatus: make(map[models.DSRStatus]int),
+		Overdue:  make([]*models.DataSubjectRequest, 0),
+	}
+
+	var totalDays float64
+	answered := 0
+	for _, request := range requests {
+		report.ByType[request.Type]++
+		report.ByStatus[request.Status]++
+
+		if request.IsOverdue(now) {
+			report.Overdue = append(report.Overdue, request)
+		}
+
+		if request.CompletedAt == nil || request.VerifiedAt == nil {
+			continue
+		}
+		answered++
+		totalDays += request.CompletedAt.Sub(*request.VerifiedAt).Hours() / 24
+		if request.AnsweredOnTime() {
+			report.AnsweredOnTime++
+		} else {
+			report.AnsweredLate++
+		}
+	}
+
+	if answered > 0 {
+		report.AvgResponseDays = totalDays / float64(answered)
+	}
+
+	return report
+}
+
+// closeRequest moves a request to a final status
+func (s *DataSubjectRequestService) closeRequest(ctx context.Context, request *models.DataSubjectRequest, status models.DSRStatus, actorID string, updates map[string]interface{}) (*models.DataSubjectRequest, error) {
+	now := time.Now()
+	updates["status"] = string(status)
+	updates["completed_at"] = now
+	updates["handled_by"] = actorID
+
+	if err := s.requestRepo.Update(ctx, request.TenantID, request.ID, updates); err != nil {
+		return nil, err
+	}
+
+	request.Status = status
+	request.CompletedAt = &now
+	request.HandledBy = actorID
+	if notes, ok := updates["resolution_notes"].(string); ok {
+		request.ResolutionNotes = notes
+	}
+	if reason, ok := updates["rejection_reason"].(string); ok {
+		request.RejectionReason = reason
+	}
+
+	eventType := "dsr_completed"
+	if status == models.DSRStatusRejected {
+		eventType = "dsr_rejected"
+	}
+	_ = s.logActivity(ctx, request.TenantID, eventType, models.ActorTypeUser, actorID, map[string]interface{}{
+		"request_id":       request.ID,
+		"request_type":     string(request.Type),
+		"answered_on_time": request.AnsweredOnTime(),
+	})
+
+	return request, nil
+}
+
+// getVerifiedRequest retrieves a request that can still be processed
+func (s *DataSubjectRequestService) getVerifiedRequest(ctx context.Context, tenantID, requestID string) (*models.DataSubjectRequest, error) {
+	request, err := s.requestRepo.Get(ctx, tenantID, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if request.Status != models.DSRStatusVerified {
+		return nil, fmt.Errorf("request must be verified and open (current status: %s)", request.Status)
+	}
+	return request, nil
+}
+
+// collectSubjectData finds the subject in owners, leads, contacts, conversations, activity logs and scheduled confirmations
+// Every identifier informed in the request is searched (e-mail, phone and CPF); data.matchedBy records which
+// identifiers found each record, records found through another record (leads of a contact, conversations,
+// logs, confirmations) inherit its identifiers
+func (s *DataSubjectRequestService) collectSubjectData(ctx context.Context, request *models.DataSubjectRequest) (*subjectData, error) {
+	tenantID := request.TenantID
+	identifiers := request.SubjectIdentifiers()
+	if len(identifiers) == 0 {
+		return nil, fmt.Errorf("request has no verified identifier")
+	}
+	data := &subjectData{matchedBy: make(map[string][]string)}
+
+	seenOwners := make(map[string]bool)
+	seenContacts := make(map[string]bool)
+	seenLeads := make(map[string]bool)
+	addLead := func(lead *models.Lead, identifiers ...string) {
+		data.match("leads", lead.ID, identifiers...)
+		if !seenLeads[lead.ID] {
+			seenLeads[lead.ID] = true
+			data.leads = append(data.leads, lead)
+		}
+	}
+
+	for _, identifier := range identifiers {
+		// Owners (e-mail, telefone e CPF são únicos por tenant)
+		var owner *models.Owner
+		var err error
+		switch identifier.Kind {
+		case models.DSRIdentifierEmail:
+			owner, err = s.ownerRepo.GetByEmail(ctx, tenantID, identifier.Value)
+		case models.DSRIdentifierPhone:
+			owner, err = s.ownerRepo.GetByPhone(ctx, tenantID, identifier.Value)
+		case models.DSRIdentifierDocument:
+			owner, err = s.ownerRepo.GetByDocument(ctx, tenantID, identifier.Value)
+		}
+		if err != nil && err != repositories.ErrNotFound {
+			return nil, fmt.Errorf("failed to search owners: %w", err)
+		}
+		if err == nil {
+			data.match("owners", owner.ID, identifier.Kind)
+			if !seenOwners[owner.ID] {
+				seenOwners[owner.ID] = true
+				data.owners = append(data.owners, owner)
+			}
+		}
+
+		// Contacts and leads have no CPF
+		var email, phone string
+		switch identifier.Kind {
+		case models.DSRIdentifierEmail:
+			email = identifier.Value
+		case models.DSRIdentifierPhone:
+			phone = identifier.Value
+		default:
+			continue
+		}
+
+		// Contacts (leads deduplicated by person)
+		var contact *models.Contact
+		if email != "" {
+			contact, err = s.contactRepo.GetByEmail(ctx, tenantID, email)
+		} else {
+			contact, err = s.contactRepo.GetByPhone(ctx, tenantID, phone)
+		}
+		if err != nil && err != repositories.ErrNotFound {
+			return nil, fmt.Errorf("failed to search contacts: %w", err)
+		}
+		if err == nil {
+			data.match("contacts", contact.ID, identifier.Kind)
+			if !seenContacts[contact.ID] {
+				seenContacts[contact.ID] = true
+				data.contacts = append(data.contacts, contact)
+			}
+		}
+
+		// Leads (um por imóvel de interesse)
+		leads, err := s.leadRepo.ListByContact(ctx, tenantID, email, phone)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search leads: %w", err)
+		}
+		for _, lead := range leads {
+			addLead(lead, identifier.Kind)
+		}
+	}
+
+	// Leads of the matched contacts
+	for _, contact := range data.contacts {
+		contactLeads, err := s.leadRepo.ListByContactID(ctx, tenantID, contact.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search contact leads: %w", err)
+		}
+		for _, lead := range contactLeads {
+			addLead(lead, data.matchedBy[dsrMatchKey("contacts", contact.ID)]...)
+		}
+	}
+
+	// Conversations of the matched leads (messages keep the subject's phone, e-mail and name)
+	for _, lead := range data.leads {
+		conversation, err := s.conversationRepo.GetConversation(ctx, tenantID, lead.ID)
+		if err != nil && err != repositories.ErrNotFound {
+			return nil, fmt.Errorf("failed to search conversations: %w", err)
+		}
+		messages, err := s.conversationRepo.ListMessages(ctx, tenantID, lead.ID, repositories.PaginationOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to search conversation messages: %w", err)
+		}
+		if conversation == nil && len(messages) == 0 {
+			continue
+		}
+		data.match("conversations", lead.ID, data.matchedBy[dsrMatchKey("leads", lead.ID)]...)
+		data.conversations = append(data.conversations, &DataSubjectConversation{
+			LeadID:       lead.ID,
+			Conversation: conversation,
+			Messages:     messages,
+		})
+	}
+
+	// Activity logs referencing the matched owners and leads
+	seenLogs := make(map[string]bool)
+	addLogs := func(logs []*models.ActivityLog, identifiers []string) {
+		for _, activityLog := range logs {
+			data.match("activity_logs", activityLog.ID, identifiers...)
+			if !seenLogs[activityLog.ID] {
+				seenLogs[activityLog.ID] = true
+				data.activityLogs = append(data.activityLogs, activityLog)
+			}
+		}
+	}
+	logOpts := repositories.PaginationOptions{Limit: dsrActivityLogLimit}
+	for _, owner := range data.owners {
+		ownerIdentifiers := data.matchedBy[dsrMatchKey("owners", owner.ID)]
+		logs, err := s.activityLogRepo.ListOwnerLogs(ctx, tenantID, owner.ID, logOpts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search owner activity logs: %w", err)
+		}
+		addLogs(logs, ownerIdentifiers)
+
+		confirmations, err := s.scheduledConfirmationRepo.ListByOwner(ctx, tenantID, owner.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search scheduled confirmations: %w", err)
+		}
+		for _, sc := range confirmations {
+			data.match("scheduled_confirmations", sc.ID, ownerIdentifiers...)
+		}
+		data.scheduledConfirmations = append(data.scheduledConfirmations, confirmations...)
+	}
+	for _, lead := range data.leads {
+		logs, err := s.activityLogRepo.ListLeadLogs(ctx, tenantID, lead.ID, logOpts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search lead activity logs: %w", err)
+		}
+		addLogs(logs, data.matchedBy[dsrMatchKey("leads", lead.ID)])
+	}
+
+	// Logs of the request itself (client_ip of the subject, found by the verified identifier)
+	requestLogs, err := s.activityLogRepo.ListDataSubjectRequestLogs(ctx, tenantID, request.ID, logOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search request activity logs: %w", err)
+	}
+	for _, identifier := range identifiers {
+		if identifier.Verified {
+			addLogs(requestLogs, []string{identifier.Kind})
+		}
+	}
+
+	sort.Slice(data.activityLogs, func(i, j int) bool {
+		return data.activityLogs[i].Timestamp.Before(data.activityLogs[j].Timestamp)
+	})
+
+	// Informed identifiers that found records of their own (owners, contacts, leads)
+	for _, identifier := range identifiers {
+		if data.identifierMatched(identifier.Kind) {
+			data.matchedIdentifiers = append(data.matchedIdentifiers, identifier.Kind)
+		}
+	}
+
+	return data, nil
+}
+
+// dsrMatchKey identifies a record in subjectData.matchedBy
+func dsrMatchKey(collection, documentID string) string {
+	return collection + "/" + documentID
+}
+
+// match records that identifiers found a record
+func (d *subjectData) match(collection, documentID string, identifiers ...string) {
+	key := dsrMatchKey(collection, documentID)
+	for _, identifier := range identifiers {
+		found := false
+		for _, existing := range d.matchedBy[key] {
+			if existing == identifier {
+				found = true
+				break
+			}
+		}
+		if !found {
+			d.matchedBy[key] = append(d.matchedBy[key], identifier)
+		}
+	}
+}
+
+// identifierMatched checks whether an identifier found an owner, a contact or a lead
+func (d *subjectData) identifierMatched(identifier string) bool {
+	for key, identifiers := range d.matchedBy {
+		if !strings.HasPrefix(key, "owners/") && !strings.HasPrefix(key, "contacts/") && !strings.HasPrefix(key, "leads/") {
+			continue
+		}
+		for _, existing := range identifiers {
+			if existing == identifier {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// withMatchedBy sets the identifiers that found each record on the matches
+func (d *subjectData) withMatchedBy(matches []models.DSRMatch) []models.DSRMatch {
+	for i := range matches {
+		matches[i].MatchedBy = d.matchedBy[dsrMatchKey(matches[i].Collection, matches[i].DocumentID)]
+	}
+	return matches
+}
+
+// activityLogPIIRemover removes PII metadata of activity logs (implemented by repositories.ActivityLogRepository)
+type activityLogPIIRemover interface {
+	RemoveMetadataKeys(ctx context.Context, tenantID, id string, keys []string) error
+}
+
+// eraseActivityLogsPII removes the PII metadata keys of the subject's activity logs (the logs themselves are
+// kept as audit trail) and returns their matches and the number of logs pruned
+func eraseActivityLogsPII(ctx context.Context, remover activityLogPIIRemover, tenantID string, logs []*models.ActivityLog) ([]models.DSRMatch, int, error) {
+	matches := make([]models.DSRMatch, 0, len(logs))
+	pruned := 0
+	for _, activityLog := range logs {
+		match := models.DSRMatch{Collection: "activity_logs", DocumentID: activityLog.ID, Description: activityLog.EventType, Action: dsrActionRetained}
+		if keys := activityLogPIIKeys(activityLog); len(keys) > 0 {
+			if err := remover.RemoveMetadataKeys(ctx, tenantID, activityLog.ID, keys); err != nil {
+				return nil, 0, fmt.Errorf("failed to remove personal data of activity log %s: %w", activityLog.ID, err)
+			}
+			for _, key := range keys {
+				delete(activityLog.Metadata, key)
+			}
+			match.Action = dsrActionPIIRemoved
+			pruned++
+		}
+		matches = append(matches, match)
+	}
+	return matches, pruned, nil
+}
+
+// buildDSRMatches lists the records found (no personal data, only references)
+func buildDSRMatches(data *subjectData) []models.DSRMatch {
+	matches := make([]models.DSRMatch, 0, len(data.owners)+len(data.leads)+len(data.contacts)+len(data.conversations)+len(data.activityLogs)+len(data.scheduledConfirmations))
+	for _, owner := range data.owners {
+		matches = append(matches, models.DSRMatch{Collection: "owners", DocumentID: owner.ID})
+	}
+	for _, lead := range data.leads {
+		matches = append(matches, models.DSRMatch{Collection: "leads", DocumentID: lead.ID, Description: lead.PropertyID})
+	}
+	for _, contact := range data.contacts {
+		matches = append(matches, models.DSRMatch{Collection: "contacts", DocumentID: contact.ID})
+	}
+	for _, conversation := range data.conversations {
+		matches = append(matches, models.DSRMatch{Collection: "conversations", DocumentID: conversation.LeadID, Description: fmt.Sprintf("%d messages", len(conversation.Messages))})
+	}
+	for _, activityLog := range data.activityLogs {
+		matches = append(matches, models.DSRMatch{Collection: "activity_logs", DocumentID: activityLog.ID, Description: activityLog.EventType})
+	}
+	for _, sc := range data.scheduledConfirmations {
+		matches = append(matches, models.DSRMatch{Collection: "scheduled_confirmations", DocumentID: sc.ID, Description: string(sc.Status)})
+	}
+	return data.withMatchedBy(matches)
+}
+
+// sendVerificationCode delivers the verification code through the request's channel
+func (s *DataSubjectRequestService) sendVerificationCode(ctx context.Context, request *models.DataSubjectRequest, code string) error {
+	switch request.VerificationChannel {
+	case models.DSRVerificationChannelEmail:
+		branding := s.emailService.PlatformBranding()
+		if tenant, err := s.tenantRepo.Get(ctx, request.TenantID); err == nil {
+			branding = tenant.ResolvedBranding()
+		}
+		if err := s.emailService.SendDSRVerificationCode(request.SubjectEmail, request.SubjectName, branding, code, request.VerificationExpiry); err != nil {
+			return fmt.Errorf("failed to send verification code: %w", err)
+		}
+	case models.DSRVerificationChannelWhatsApp:
+		if s.whatsAppProvider == nil {
+			return ErrDSRWhatsAppUnavailable
+		}
+		tenant, err := s.tenantRepo.Get(ctx, request.TenantID)
+		if err != nil {
+			return fmt.Errorf("failed to get tenant: %w", err)
+		}
+		body := fmt.Sprintf("Seu código para confirmar a solicitação de dados pessoais (LGPD) é %s. Ele vale por %d minutos. Se você não fez esta solicitação, ignore esta mensagem.",
+			code, int(dsrVerificationCodeTTL.Minutes()))
+		if _, err := s.whatsAppProvider.Send(ctx, OutboundMessage{
+			Tenant: tenant,
+			Lead:   &models.Lead{TenantID: request.TenantID, Phone: request.SubjectPhone},
+			Body:   body,
+		}); err != nil {
+			return fmt.Errorf("failed to send verification code: %w", err)
+		}
+	}
+	return nil
+}
+
+// canSendWhatsApp checks whether verification codes can be sent by WhatsApp for the tenant
+func (s *DataSubjectRequestService) canSendWhatsApp(ctx context.Context, tenantID string) bool {
+	if s.whatsAppProvider == nil {
+		return false
+	}
+	tenant, err := s.tenantRepo.Get(ctx, tenantID)
+	if err != nil {
+		return false
+	}
+	return tenant.StringSetting(models.TenantSettingWhatsAppPhoneNumberID) != ""
+}
+
+// logActivity logs an activity
+func (s *DataSubjectRequestService) logActivity(ctx context.Context, tenantID, eventType string, actorType models.ActorType, actorID string, metadata map[string]interface{}) error {
+	log := &models.ActivityLog{
+		TenantID:  tenantID,
+		EventType: eventType,
+		ActorType: actorType,
+		ActorID:   actorID,
+		Metadata:  metadata,
+		Timestamp: time.Now(),
+	}
+
+	return appendActivityLog(ctx, s.activityLogRepo, log)
+}
+
+// generateVerificationCode generates a random 6-digit numeric code
+func generateVerificationCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", fmt.Errorf("failed to generate verification code: %w", err)
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+)
+
+// fakeActivityLogPIIRemover records the metadata keys removed per activity log
+type fakeActivityLogPIIRemover struct {
+	removed map[string][]string
+	err     error
+}
+
+func (r *fakeActivityLogPIIRemover) RemoveMetadataKeys(ctx context.Context, tenantID, id string, keys []string) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.removed[id] = keys
+	return nil
+}
+
+func TestEraseActivityLogsPII(t *testing.T) {
+	logs := []*models.ActivityLog{
+		{ID: "log-lead", EventType: "lead_created_form", Metadata: map[string]interface{}{"lead_id": "lead-1", "consent_ip": "200.1.2.3", "channel": "form"}},
+		{ID: "log-portal", EventType: "owner_portal_session_started", Metadata: map[string]interface{}{"owner_id": "owner-1", "client_ip": "200.1.2.4"}},
+		{ID: "log-broker", EventType: "broker_created", Metadata: map[string]interface{}{"broker_id": "broker-1", "name": "Ana", "email": "ana@example.com"}},
+		{ID: "log-status", EventType: "lead_status_changed", Metadata: map[string]interface{}{"lead_id": "lead-1", "status": "contacted"}},
+	}
+	remover := &fakeActivityLogPIIRemover{removed: make(map[string][]string)}
+
+	matches, pruned, err := eraseActivityLogsPII(context.Background(), remover, "tenant-1", logs)
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, pruned)
+	assert.Equal(t, []string{"consent_ip"}, remover.removed["log-lead"])
+	assert.Equal(t, []string{"client_ip"}, remover.removed["log-portal"])
+	assert.ElementsMatch(t, []string{"name", "email"}, remover.removed["log-broker"])
+	assert.NotContains(t, remover.removed, "log-status")
+
+	// The returned logs no longer carry the removed keys, other metadata is kept
+	assert.Equal(t, map[string]interface{}{"lead_id": "lead-1", "channel": "form"}, logs[0].Metadata)
+	assert.Equal(t, map[string]interface{}{"broker_id": "broker-1"}, logs[2].Metadata)
+
+	require.Len(t, matches, 4)
+	assert.Equal(t, dsrActionPIIRemoved, matches[0].Action)
+	assert.Equal(t, dsrActionPIIRemoved, matches[1].Action)
+	assert.Equal(t, dsrActionPIIRemoved, matches[2].Action)
+	assert.Equal(t, dsrActionRetained, matches[3].Action)
+}
+
+func TestEraseActivityLogsPIIFailure(t *testing.T) {
+	logs := []*models.ActivityLog{
+		{ID: "log-lead", Metadata: map[string]interface{}{"consent_ip": "200.1.2.3"}},
+	}
+	remover := &fakeActivityLogPIIRemover{err: errors.New("unavailable")}
+
+	_, _, err := eraseActivityLogsPII(context.Background(), remover, "tenant-1", logs)
+	assert.Error(t, err)
+	assert.Contains(t, logs[0].Metadata, "consent_ip")
+}
+
+func TestCheckVerificationAttempt(t *testing.T) {
+	request := &models.DataSubjectRequest{Status: models.DSRStatusPendingVerification}
+	assert.NoError(t, checkVerificationAttempt(request))
+
+	request.VerificationAttempts = dsrMaxVerificationAttempts - 1
+	assert.NoError(t, checkVerificationAttempt(request))
+
+	request.VerificationAttempts = dsrMaxVerificationAttempts
+	assert.Error(t, checkVerificationAttempt(request))
+
+	request = &models.DataSubjectRequest{Status: models.DSRStatusVerified}
+	assert.Error(t, checkVerificationAttempt(request))
+}
+
+func TestSubjectDataMatchedBy(t *testing.T) {
+	data := &subjectData{matchedBy: make(map[string][]string)}
+	data.match("owners", "owner-1", models.DSRIdentifierEmail)
+	data.match("owners", "owner-1", models.DSRIdentifierDocument, models.DSRIdentifierEmail)
+	data.match("leads", "lead-1", models.DSRIdentifierPhone)
+	data.match("activity_logs", "log-1", models.DSRIdentifierDocument)
+
+	assert.Equal(t, []string{models.DSRIdentifierEmail, models.DSRIdentifierDocument}, data.matchedBy["owners/owner-1"])
+	assert.True(t, data.identifierMatched(models.DSRIdentifierEmail))
+	assert.True(t, data.identifierMatched(models.DSRIdentifierPhone))
+	assert.True(t, data.identifierMatched(models.DSRIdentifierDocument))
+
+	// Records found through another record do not count as a match of the identifier
+	other := &subjectData{matchedBy: map[string][]string{"activity_logs/log-1": {models.DSRIdentifierPhone}}}
+	assert.False(t, other.identifierMatched(models.DSRIdentifierPhone))
+
+	matches := data.withMatchedBy([]models.DSRMatch{
+		{Collection: "owners", DocumentID: "owner-1"},
+		{Collection: "leads", DocumentID: "lead-1"},
+		{Collection: "contacts", DocumentID: "contact-1"},
+	})
+	assert.Equal(t, []string{models.DSRIdentifierEmail, models.DSRIdentifierDocument}, matches[0].MatchedBy)
+	assert.Equal(t, []string{models.DSRIdentifierPhone}, matches[1].MatchedBy)
+	assert.Empty(t, matches[2].MatchedBy)
+}
@@ -0,0 +1,387 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+const (
+	// hostCacheTTL is how long a resolved host (or a miss) is kept in memory before hitting Firestore again
+	hostCacheTTL = 5 * time.Minute
+)
+
+var (
+	// ErrDomainTaken is returned when the hostname is already verified by a tenant (or already claimed by the same tenant)
+	ErrDomainTaken = errors.New("domain is already registered")
+
+	// ErrDomainClaimExpired is returned when verifying a pending claim past models.DomainClaimTTL
+	ErrDomainClaimExpired = errors.New("domain claim expired, add the domain again to get a new verification token")
+
+	// ErrDomainNotVerified is returned when an operation requires a verified domain
+	ErrDomainNotVerified = errors.New("domain is not verified")
+)
+
+// DNSResolver looks up TXT records (net.DefaultResolver in production, a fake in tests)
+type DNSResolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// hostCacheEntry is a resolved host (tenantID empty = unknown host)
+type hostCacheEntry struct {
+	tenantID  string
+	expiresAt time.Time
+}
+
+// DomainService manages tenant custom domains, resolves tenants from the request host and
+// builds the canonical URLs of the public sites
+// A tenant is reachable at {slug}.{platformDomain} and at any verified custom domain; the primary
+// domain (if set) is the canonical one
+type DomainService struct {
+	domainRepo      *repositories.TenantDomainRepository
+	tenantRepo      *repositories.TenantRepository
+	activityLogRepo *repositories.ActivityLogRepository
+	resolver        DNSResolver
+	platformDomain  string // ex: imob.com.br (empty = subdomains disabled)
+	publicSiteURL   string // fallback base URL when the tenant has no domain
+
+	cacheMu   sync.RWMutex
+	hostCache map[string]hostCacheEntry
+}
+
+// NewDomainService creates a new domain service
+func NewDomainService(
+	domainRepo *repositories.TenantDomainRepository,
+	tenantRepo *repositories.TenantRepository,
+	activityLogRepo *repositories.ActivityLogRepository,
+	platformDomain string,
+	publicSiteURL string,
+) *DomainService {
+	return &DomainService{
+		domainRepo:      domainRepo,
+		tenantRepo:      tenantRepo,
+		activityLogRepo: activityLogRepo,
+		resolver:        net.DefaultResolver,
+		platformDomain:  models.NormalizeHostname(platformDomain),
+		publicSiteURL:   publicSiteURL,
+		hostCache:       make(map[string]hostCacheEntry),
+	}
+}
+
+// SetResolver sets the DNS resolver (for dependency injection)
+func (s *DomainService) SetResolver(resolver DNSResolver) {
+	s.resolver = resolver
+}
+
+// AddDomain registers a tenant's claim of a custom domain; it stays pending until the TXT record is verified
+// Only verified domains are unique: several tenants may claim a hostname, the one that proves the TXT record gets it.
+// An expired claim of the same tenant is renewed with a new token
+func (s *DomainService) AddDomain(ctx context.Context, tenantID, hostname, actorID string) (*models.TenantDomain, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+
+	hostname = models.NormalizeHostname(hostname)
+	if err := models.ValidateHostname(hostname); err != nil {
+		return nil, err
+	}
+	if s.platformDomain != "" && (hostname == s.platformDomain || strings.HasSuffix(hostname, "."+s.platformDomain)) {
+		return nil, fmt.Errorf("subdomains of %s are reserved to the platform", s.platformDomain)
+	}
+
+	if _, err := s.domainRepo.GetVerified(ctx, hostname); err == nil {
+		return nil, ErrDomainTaken
+	} else if !errors.Is(err, repositories.ErrNotFound) {
+		return nil, err
+	}
+
+	now := time.Now()
+	if _, err := s.domainRepo.DeleteExpiredClaims(ctx, hostname, now); err != nil {
+		log.Printf("Failed to delete expired claims of %s: %v", hostname, err)
+	}
+
+	token, err := newDomainVerificationToken()
+	if err != nil {
+		return nil, err
+	}
+	expiresAt := now.Add(models.DomainClaimTTL)
+
+	domain := &models.TenantDomain{
+		Hostname:          hostname,
+		TenantID:          tenantID,
+		Status:            models.DomainStatusPending,
+		VerificationToken: token,
+		ExpiresAt:         &expiresAt,
+		CreatedBy:         actorID,
+	}
+	if err := s.domainRepo.Create(ctx, domain); err != nil {
+		if errors.Is(err, repositories.ErrAlreadyExists) {
+			return nil, ErrDomainTaken // Pending claim of the same tenant, still valid
+		}
+		return nil, err
+	}
+
+	_ = s.logActivity(ctx, tenantID, "tenant_domain_added", models.ActorTypeUser, actorID, map[string]interface{}{
+		"hostname": hostname,
+	})
+
+	return domain, nil
+}
+
+// ListDomains lists the custom domains of a tenant
+func (s *DomainService) ListDomains(ctx context.Context, tenantID string) ([]*models.TenantDomain, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+
+	return s.domainRepo.ListByTenant(ctx, tenantID)
+}
+
+// getTenantDomain retrieves the tenant's claim of a domain (ErrNotFound when the tenant has not claimed it)
+func (s *DomainService) getTenantDomain(ctx context.Context, tenantID, hostname string) (*models.TenantDomain, error) {
+	return s.domainRepo.Get(ctx, tenantID, models.NormalizeHostname(hostname))
+}
+
+// VerifyDomain checks the TXT record of a domain and marks it as verified when the token matches
+// The check result is stored in the domain (last_checked_at, last_error) either way
+// Verifying takes the hostname over from the other tenants' pending claims (ErrDomainTaken when already verified by another tenant)
+func (s *DomainService) VerifyDomain(ctx context.Context, tenantID, hostname, actorID string) (*models.TenantDomain, error) {
+	domain, err := s.getTenantDomain(ctx, tenantID, hostname)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if domain.IsExpired(now) {
+		return nil, ErrDomainClaimExpired
+	}
+	domain.LastCheckedAt = &now
+	domain.LastError = ""
+
+	records, lookupErr := s.resolver.LookupTXT(ctx, domain.VerificationRecordName())
+	if lookupErr != nil {
+		domain.LastError = fmt.Sprintf("TXT lookup failed: %v", lookupErr)
+	} else if !containsRecord(records, domain.VerificationRecordValue()) {
+		domain.LastError = fmt.Sprintf("TXT record %s not found in %s", domain.VerificationRecordValue(), domain.VerificationRecordName())
+	}
+
+	if err := s.domainRepo.Update(ctx, tenantID, domain.Hostname, []firestore.Update{
+		{Path: "last_checked_at", Value: now},
+		{Path: "last_error", Value: domain.LastError},
+	}); err != nil {
+		return nil, err
+	}
+
+	newlyVerified := domain.LastError == "" && domain.Status != models.DomainStatusVerified
+	if newlyVerified {
+		if err := s.domainRepo.Verify(ctx, tenantID, domain.Hostname, now); err != nil {
+			if errors.Is(err, repositories.ErrAlreadyExists) {
+				return nil, ErrDomainTaken
+			}
+			return nil, err
+		}
+		domain.Status = models.DomainStatusVerified
+		domain.VerifiedAt = &now
+		domain.ExpiresAt = nil
+	}
+
+	if newlyVerified {
+		s.invalidateHost(domain.Hostname)
+		_ = s.logActivity(ctx, tenantID, "tenant_domain_verified", models.ActorTypeUser, actorID, map[string]interface{}{
+			"hostname": domain.Hostname,
+		})
+	}
+
+	return domain, nil
+}
+
+// SetPrimaryDomain sets a verified domain as the canonical domain of the tenant's site
+func (s *DomainService) SetPrimaryDomain(ctx context.Context, tenantID, hostname, actorID string) (*models.TenantDomain, error) {
+	domain, err := s.getTenantDomain(ctx, tenantID, hostname)
+	if err != nil {
+		return nil, err
+	}
+	if domain.Status != models.DomainStatusVerified {
+		return nil, ErrDomainNotVerified
+	}
+
+	if err := s.tenantRepo.Update(ctx, tenantID, map[string]interface{}{
+		"primary_domain": domain.Hostname,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to set primary domain: %w", err)
+	}
+
+	_ = s.logActivity(ctx, tenantID, "tenant_primary_domain_set", models.ActorTypeUser, actorID, map[string]interface{}{
+		"hostname": domain.Hostname,
+	})
+
+	return domain, nil
+}
+
+// RemoveDomain removes a custom domain (and unsets it as primary domain)
+func (s *DomainService) RemoveDomain(ctx context.Context, tenantID, hostname, actorID string) error {
+	domain, err := s.getTenantDomain(ctx, tenantID, hostname)
+	if err != nil {
+		return err
+	}
+
+	tenant, err := s.tenantRepo.Get(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	if tenant.PrimaryDomain == domain.Hostname {
+		if err := s.tenantRepo.Update(ctx, tenantID, map[string]interface{}{
+			"primary_domain": "",
+		}); err != nil {
+			return fmt.Errorf("failed to unset primary domain: %w", err)
+		}
+	}
+
+	if err := s.domainRepo.Delete(ctx, tenantID, domain.Hostname); err != nil {
+		return err
+	}
+	s.invalidateHost(domain.Hostname)
+
+	_ = s.logActivity(ctx, tenantID, "tenant_domain_removed", models.ActorTypeUser, actorID, map[string]interface{}{
+		"hostname": domain.Hostname,
+	})
+
+	return nil
+}
+
+// ResolveHost returns the active tenant served at a host ({slug}.{platformDomain} or a verified custom domain)
+// Returns ErrNotFound for unknown hosts, pending domains and inactive tenants
+func (s *DomainService) ResolveHost(ctx context.Context, host string) (*models.Tenant, error) {
+	host = models.NormalizeHostname(host)
+	if host == "" {
+		return nil, repositories.ErrNotFound
+	}
+
+	tenantID, cached := s.cachedHost(host)
+	if !cached {
+		var err error
+		tenantID, err = s.lookupHost(ctx, host)
+		if err != nil && err != repositories.ErrNotFound {
+			return nil, err
+		}
+		s.cacheHost(host, tenantID)
+	}
+	if tenantID == "" {
+		return nil, repositories.ErrNotFound
+	}
+
+	tenant, err := s.tenantRepo.Get(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if !tenant.IsActive {
+		return nil, repositories.ErrNotFound
+	}
+
+	return tenant, nil
+}
+
+// lookupHost returns the ID of the tenant served at a host
+func (s *DomainService) lookupHost(ctx context.Context, host string) (string, error) {
+	if slug, ok := models.PlatformSubdomainSlug(host, s.platformDomain); ok {
+		tenant, err := s.tenantRepo.GetBySlug(ctx, slug)
+		if err != nil {
+			return "", err
+		}
+		return tenant.ID, nil
+	}
+
+	domain, err := s.domainRepo.GetVerified(ctx, host)
+	if err != nil {
+		return "", err
+	}
+
+	return domain.TenantID, nil
+}
+
+// cachedHost returns the cached tenant ID of a host
+func (s *DomainService) cachedHost(host string) (string, bool) {
+	s.cacheMu.RLock()
+	defer s.cacheMu.RUnlock()
+
+	entry, ok := s.hostCache[host]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return "", false
+	}
+	return entry.tenantID, true
+}
+
+// cacheHost caches the tenant ID of a host (empty = unknown host)
+func (s *DomainService) cacheHost(host, tenantID string) {
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+
+	s.hostCache[host] = hostCacheEntry{tenantID: tenantID, expiresAt: time.Now().Add(hostCacheTTL)}
+}
+
+// invalidateHost removes a host from the cache (domain verified or removed)
+func (s *DomainService) invalidateHost(host string) {
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+
+	delete(s.hostCache, host)
+}
+
+// SiteBaseURL returns the canonical base URL of the tenant's public site
+func (s *DomainService) SiteBaseURL(tenant *models.Tenant) string {
+	return tenant.SiteBaseURL(s.platformDomain, s.publicSiteURL)
+}
+
+// PropertyURL returns the canonical URL of a property on its tenant's public site
+func (s *DomainService) PropertyURL(ctx context.Context, property *models.Property) string {
+	tenant, err := s.tenantRepo.Get(ctx, property.TenantID)
+	if err != nil {
+		return models.PropertyCanonicalURL(strings.TrimSuffix(s.publicSiteURL, "/"), property)
+	}
+
+	return models.PropertyCanonicalURL(s.SiteBaseURL(tenant), property)
+}
+
+// newDomainVerificationToken generates the random token of a domain claim
+func newDomainVerificationToken() (string, error) {
+	tokenBytes := make([]byte, 16)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return "", fmt.Errorf("failed to generate verification token: %w", err)
+	}
+	return hex.EncodeToString(tokenBytes), nil
+}
+
+// containsRecord reports whether a TXT record value is present (ignoring quotes and surrounding spaces)
+func containsRecord(records []string, value string) bool {
+	for _, record := range records {
+		if strings.Trim(strings.TrimSpace(record), `"`) == value {
+			return true
+		}
+	}
+	return false
+}
+
+// logActivity logs an activity
+func (s *DomainService) logActivity(ctx context.Context, tenantID, eventType string, actorType models.ActorType, actorID string, metadata map[string]interface{}) error {
+	log := &models.ActivityLog{
+		TenantID:  tenantID,
+		EventType: eventType,
+		ActorType: actorType,
+		ActorID:   actorID,
+		Metadata:  metadata,
+		Timestamp: time.Now(),
+	}
+
+	return appendActivityLog(ctx, s.activityLogRepo, log)
+}
@@ -0,0 +1,347 @@
+package services
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/adapters/portalmail"
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+var (
+	// ErrInboundEmailInvalidSecret is returned when the inbound e-mail webhook secret does not match
+	ErrInboundEmailInvalidSecret = errors.New("invalid inbound email secret")
+
+	// ErrInboundEmailUnknownTenant is returned when no active tenant matches the recipient address
+	ErrInboundEmailUnknownTenant = errors.New("no tenant for the inbound email recipient (expected leads+{tenant-slug}@...)")
+)
+
+// portalEmailConsentText records the legal basis of leads received from portal notification e-mails
+const portalEmailConsentText = "Contato enviado pelo interessado ao anunciante através do portal %s, conforme os termos de uso do portal."
+
+// EmailIngestResult is the outcome of an inbound lead notification e-mail
+type EmailIngestResult struct {
+	Email     *models.InboundEmail `json:"email"`
+	Lead      *models.Lead         `json:"lead,omitempty"`
+	Duplicate bool                 `json:"duplicate"` // e-mail already received: nothing changed
+}
+
+// EmailInboundService turns lead notification e-mails of listing portals (ZAP, VivaReal, OLX) into leads
+type EmailInboundService struct {
+	leadService      *LeadService
+	leadRepo         *repositories.LeadRepository
+	propertyRepo     *repositories.PropertyRepository
+	tenantRepo       *repositories.TenantRepository
+	inboundEmailRepo *repositories.InboundEmailRepository
+	activityLogRepo  *repositories.ActivityLogRepository
+	inbox            *ConversationService // Optional - messages of existing leads threaded into the inbox
+	secret           string               // Shared secret of the mail provider webhook (empty disables the webhook)
+}
+
+// NewEmailInboundService creates a new inbound e-mail service
+func NewEmailInboundService(
+	leadService *LeadService,
+	leadRepo *repositories.LeadRepository,
+	propertyRepo *repositories.PropertyRepository,
+	tenantRepo *repositories.TenantRepository,
+	inboundEmailRepo *repositories.InboundEmailRepository,
+	activityLogRepo *repositories.ActivityLogRepository,
+	secret string,
+) *EmailInboundService {
+	return &EmailInboundService{
+		leadService:      leadService,
+		leadRepo:         leadRepo,
+		propertyRepo:     propertyRepo,
+		tenantRepo:       tenantRepo,
+		inboundEmailRepo: inboundEmailRepo,
+		activityLogRepo:  activityLogRepo,
+		secret:           secret,
+	}
+}
+
+// SetConversationService sets the conversation service of the unified inbox (for dependency injection)
+func (s *EmailInboundService) SetConversationService(service *ConversationService) {
+	s.inbox = service
+}
+
+// VerifySecret checks the shared secret sent by the mail provider
+func (s *EmailInboundService) VerifySecret(secret string) error {
+	if s.secret == "" || subtle.ConstantTimeCompare([]byte(secret), []byte(s.secret)) != 1 {
+		return ErrInboundEmailInvalidSecret
+	}
+	return nil
+}
+
+// ReceiveEmail ingests an e-mail forwarded by the mail provider; the tenant is the plus tag of the
+// recipient (envelope recipient first, then the message recipients): leads+{tenant-slug}@...
+func (s *EmailInboundService) ReceiveEmail(ctx context.Context, raw []byte, recipient string) (*EmailIngestResult, error) {
+	email, err := portalmail.ParseEmail(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", repositories.ErrInvalidInput, err)
+	}
+
+	tenant, err := s.resolveTenant(ctx, append([]string{recipient}, email.Recipients...))
+	if err != nil {
+		return nil, err
+	}
+
+	return s.ingest(ctx, tenant.ID, email, raw, models.ActorTypeSystem, "")
+}
+
+// IngestEmail ingests a raw e-mail uploaded by a user of the tenant
+func (s *EmailInboundService) IngestEmail(ctx context.Context, tenantID string, raw []byte, actorID string) (*EmailIngestResult, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	email, err := portalmail.ParseEmail(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", repositories.ErrInvalidInput, err)
+	}
+
+	return s.ingest(ctx, tenantID, email, raw, models.ActorTypeUser, actorID)
+}
+
+// ListInboundEmails retrieves the e-mails received by a tenant (most recent first)
+func (s *EmailInboundService) ListInboundEmails(ctx context.Context, tenantID string, status *models.InboundEmailStatus, opts repositories.PaginationOptions) ([]*models.InboundEmail, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+
+	emails, err := s.inboundEmailRepo.List(ctx, tenantID, status, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list inbound emails: %w", err)
+	}
+
+	return emails, nil
+}
+
+// resolveTenant returns the active tenant of the first plus-addressed recipient (tag = slug or tenant ID)
+func (s *EmailInboundService) resolveTenant(ctx context.Context, recipients []string) (*models.Tenant, error) {
+	for _, recipient := range recipients {
+		tag := portalmail.PlusTag(strings.ToLower(strings.TrimSpace(recipient)))
+		if tag == "" {
+			continue
+		}
+
+		tenant, err := s.tenantRepo.GetBySlug(ctx, tag)
+		if errors.Is(err, repositories.ErrNotFound) {
+			tenant, err = s.tenantRepo.Get(ctx, tag)
+		}
+		if errors.Is(err, repositories.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get tenant: %w", err)
+		}
+		if tenant.IsActive {
+			return tenant, nil
+		}
+	}
+
+	return nil, ErrInboundEmailUnknownTenant
+}
+
+// ingest parses the portal notification, resolves the property and creates the lead (or threads the
+// message into the conversation of the contact's lead). Each e-mail is processed once
+func (s *EmailInboundService) ingest(ctx context.Context, tenantID string, email *portalmail.Email, raw []byte, actorType models.ActorType, actorID string) (*EmailIngestResult, error) {
+	record := &models.InboundEmail{
+		ID:         models.InboundEmailID(email.MessageID, raw),
+		TenantID:   tenantID,
+		MessageID:  email.MessageID,
+		Sender:     email.From,
+		ReceivedAt: email.Date,
+	}
+
+	existing, err := s.inboundEmailRepo.Get(ctx, tenantID, record.ID)
+	if err == nil {
+		return &EmailIngestResult{Email: existing, Duplicate: true}, nil
+	}
+	if !errors.Is(err, repositories.ErrNotFound) {
+		return nil, fmt.Errorf("failed to get inbound email: %w", err)
+	}
+
+	result := &EmailIngestResult{Email: record}
+	parsed, err := portalmail.Parse(email)
+	if err == nil {
+		record.Portal = string(parsed.Portal)
+		record.ListingID = parsed.ListingID
+		record.Reference = parsed.Reference
+		result.Lead, err = s.resolveLead(ctx, tenantID, record, parsed, email.Subject)
+	}
+	if err != nil {
+		if !errors.Is(err, portalmail.ErrUnknownPortal) && !errors.Is(err, portalmail.ErrNoContact) && !errors.Is(err, repositories.ErrNotFound) {
+			return nil, err
+		}
+		record.Status = models.InboundEmailStatusUnparsed
+		if errors.Is(err, repositories.ErrNotFound) {
+			record.Status = models.InboundEmailStatusUnmatched
+		}
+		record.Error = err.Error()
+	}
+
+	if err := s.inboundEmailRepo.Create(ctx, record); err != nil {
+		if errors.Is(err, repositories.ErrAlreadyExists) {
+			// Concurrent delivery of the same e-mail
+			return &EmailIngestResult{Email: record, Duplicate: true}, nil
+		}
+		log.Printf("Failed to record inbound email %s of tenant %s: %v", record.ID, tenantID, err)
+	}
+
+	_ = s.logActivity(ctx, tenantID, "lead_email_ingested", actorType, actorID, map[string]interface{}{
+		"inbound_email_id": record.ID,
+		"portal":           record.Portal,
+		"status":           record.Status,
+		"lead_id":          record.LeadID,
+		"property_id":      record.PropertyID,
+	})
+
+	return result, nil
+}
+
+// resolveLead finds the property of the listing and returns the lead of the contact for it: the existing
+// lead with the same e-mail or phone (the message is threaded into its conversation), else a new lead
+// Returns repositories.ErrNotFound when the listing matches no property
+func (s *EmailInboundService) resolveLead(ctx context.Context, tenantID string, record *models.InboundEmail, parsed *portalmail.Lead, subject string) (*models.Lead, error) {
+	property, err := s.findProperty(ctx, tenantID, parsed)
+	if err != nil {
+		return nil, err
+	}
+	record.PropertyID = property.ID
+
+	// Invalid phones are dropped: the e-mail may still identify the lead
+	if parsed.Phone != "" {
+		phone, err := normalizeLeadPhone(parsed.Phone)
+		if err != nil {
+			phone = ""
+		}
+		parsed.Phone = phone
+	}
+	if parsed.Email == "" && parsed.Phone == "" {
+		return nil, portalmail.ErrNoContact
+	}
+
+	lead, err := s.findLead(ctx, tenantID, property.ID, parsed)
+	if err != nil {
+		return nil, err
+	}
+
+	if lead != nil {
+		record.Status = models.InboundEmailStatusLeadUpdated
+		record.LeadID = lead.ID
+		if s.inbox != nil && parsed.Message != "" {
+			_, err := s.inbox.RecordMessage(ctx, lead, &models.ConversationMessage{
+				Channel:     models.LeadChannelEmail,
+				Direction:   models.MessageDirectionInbound,
+				Subject:     subject,
+				Body:        parsed.Message,
+				From:        parsed.Email,
+				ContactName: parsed.Name,
+				Provider:    string(parsed.Portal),
+				ExternalID:  record.ID,
+			})
+			if err != nil && !errors.Is(err, repositories.ErrAlreadyExists) {
+				log.Printf("Failed to thread portal email into the conversation of lead %s: %v", lead.ID, err)
+			}
+		}
+		return lead, nil
+	}
+
+	lead = &models.Lead{
+		TenantID:     tenantID,
+		PropertyID:   property.ID,
+		Name:         parsed.Name,
+		Email:        parsed.Email,
+		Phone:        parsed.Phone,
+		Message:      parsed.Message,
+		Channel:      models.LeadChannelEmail,
+		UTMSource:    string(parsed.Portal),
+		UTMMedium:    "email",
+		ConsentGiven: true,
+		ConsentText:  fmt.Sprintf(portalEmailConsentText, parsed.Portal.DisplayName()),
+	}
+	if err := s.leadService.CreateLead(ctx, lead); err != nil {
+		return nil, fmt.Errorf("failed to create lead: %w", err)
+	}
+
+	record.Status = models.InboundEmailStatusLeadCreated
+	record.LeadID = lead.ID
+	return lead, nil
+}
+
+// findProperty resolves the property of the listing: advertiser reference, else the portal listing ID
+// (external_source = portal), else the listing ID used as reference
+func (s *EmailInboundService) findProperty(ctx context.Context, tenantID string, parsed *portalmail.Lead) (*models.Property, error) {
+	lookups := make([]func() (*models.Property, error), 0, 3)
+	if parsed.Reference != "" {
+		lookups = append(lookups, func() (*models.Property, error) {
+			return s.propertyRepo.GetByReference(ctx, tenantID, parsed.Reference)
+		})
+	}
+	if parsed.ListingID != "" {
+		lookups = append(lookups, func() (*models.Property, error) {
+			return s.propertyRepo.GetByExternalID(ctx, tenantID, string(parsed.Portal), parsed.ListingID)
+		}, func() (*models.Property, error) {
+			return s.propertyRepo.GetByReference(ctx, tenantID, strings.ToUpper(parsed.ListingID))
+		})
+	}
+
+	for _, lookup := range lookups {
+		property, err := lookup()
+		if err == nil {
+			return property, nil
+		}
+		if !errors.Is(err, repositories.ErrNotFound) {
+			return nil, fmt.Errorf("failed to get property: %w", err)
+		}
+	}
+
+	return nil, fmt.Errorf("%w: no property for listing %q (reference %q)", repositories.ErrNotFound, parsed.ListingID, parsed.Reference)
+}
+
+// findLead returns the active lead of the contact for the property (nil when none)
+func (s *EmailInboundService) findLead(ctx context.Context, tenantID, propertyID string, parsed *portalmail.Lead) (*models.Lead, error) {
+	lookups := make([]func() (*models.Lead, error), 0, 2)
+	if parsed.Email != "" {
+		lookups = append(lookups, func() (*models.Lead, error) {
+			return s.leadRepo.GetByEmail(ctx, tenantID, propertyID, parsed.Email)
+		})
+	}
+	if parsed.Phone != "" {
+		lookups = append(lookups, func() (*models.Lead, error) {
+			return s.leadRepo.GetByPhone(ctx, tenantID, propertyID, parsed.Phone)
+		})
+	}
+
+	for _, lookup := range lookups {
+		lead, err := lookup()
+		if err == nil && !lead.IsAnonymized && !lead.ConsentRevoked {
+			return lead, nil
+		}
+		if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+			return nil, fmt.Errorf("failed to get lead: %w", err)
+		}
+	}
+
+	return nil, nil
+}
+
+// logActivity logs an activity
+func (s *EmailInboundService) logActivity(ctx context.Context, tenantID, eventType string, actorType models.ActorType, actorID string, metadata map[string]interface{}) error {
+	log := &models.ActivityLog{
+		TenantID:  tenantID,
+		EventType: eventType,
+		ActorType: actorType,
+		ActorID:   actorID,
+		Metadata:  metadata,
+		Timestamp: time.Now(),
+	}
+
+	return appendActivityLog(ctx, s.activityLogRepo, log)
+}
@@ -8,7 +8,10 @@ import (
 	"net/smtp"
 	"os"
 	"strconv"
+	"strings"
 	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
 )
 
 // EmailService handles sending emails
@@ -57,6 +60,14 @@ func NewEmailService() *EmailService {
 	}
 }
 
+// PlatformBranding returns the platform identity (EMAIL_FROM_NAME) for e-mails not tied to a tenant
+func (s *EmailService) PlatformBranding() models.TenantBranding {
+	branding := models.PlatformBranding()
+	branding.SenderName = s.fromName
+	branding.LegalFooter = s.fromName
+	return branding
+}
+
 // getEnv gets environment variable with fallback
 func getEnv(key, fallback string) string {
 	if value := os.Getenv(key); value != "" {
@@ -67,6 +78,7 @@ func getEnv(key, fallback string) string {
 
 // InvitationEmailData contains data for the invitation email template
 type InvitationEmailData struct {
+	Branding      models.TenantBranding
 	InviteeName   string
 	TenantName    string
 	InviterName   string
@@ -77,7 +89,8 @@ type InvitationEmailData struct {
 }
 
 // SendInvitation sends an invitation email to a user
-func (s *EmailService) SendInvitation(email, name, token, tenantID, tenantName, inviterName, role string, expiresAt time.Time) error {
+// The e-mail carries the tenant's branding (sender name, colors, logo, legal footer)
+func (s *EmailService) SendInvitation(email, name, token, tenantID string, branding models.TenantBranding, inviterName, role string, expiresAt time.Time) error {
 	// Build accept URL
 	acceptURL := fmt.Sprintf("%s/auth/accept-invitation?token=%s", s.baseURL, token)
 
@@ -86,8 +99,9 @@ func (s *EmailService) SendInvitation(email, name, token, tenantID, tenantName,
 
 	// Prepare template data
 	data := InvitationEmailData{
+		Branding:      branding,
 		InviteeName:   name,
-		TenantName:    tenantName,
+		TenantName:    branding.SenderName,
 		InviterName:   inviterName,
 		Role:          s.translateRole(role),
 		AcceptURL:     acceptURL,
@@ -108,11 +122,11 @@ func (s *EmailService) SendInvitation(email, name, token, tenantID, tenantName,
 	log.Printf("📧 Sending invitation email to %s (%s)", email, name)
 	log.Printf("   Accept URL: %s", acceptURL)
 
-	subject := fmt.Sprintf("Convite para %s - Ecosistema Imob", tenantName)
+	subject := fmt.Sprintf("Convite para %s", branding.SenderName)
 
 	// If email is enabled, send via SMTP
 	if s.enabled {
-		err = s.sendEmail(email, name, subject, htmlBody, textBody)
+		err = s.sendEmail(branding, email, name, subject, htmlBody, textBody)
 		if err != nil {
 			log.Printf("❌ Error sending email via SMTP: %v", err)
 			return err
@@ -153,10 +167,10 @@ func (s *EmailService) renderInvitationTemplate(data InvitationEmailData) (strin
 <head>
     <meta charset="UTF-8">
     <meta name="viewport" content="width=device-width, initial-scale=1.0">
-    <title>Convite para Ecosistema Imob</title>
+    <title>Convite para {{.Branding.SenderName}}</title>
     <style>
         body {
-            font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, 'Helvetica Neue', Arial, sans-serif;
+            font-family: {{.Branding.FontFamily}};
             line-height: 1.6;
             color: #333;
             background-color: #f4f4f4;
@@ -172,13 +186,14 @@ func (s *EmailService) renderInvitationTemplate(data InvitationEmailData) (strin
             box-shadow: 0 2px 8px rgba(0,0,0,0.1);
         }
         .header {
-            background: linear-gradient(135deg, #667eea 0%, #764ba2 100%);
+            background: linear-gradient(135deg, {{.Branding.PrimaryColor}} 0%, {{.Branding.SecondaryColor}} 100%);
             color: white;
             padding: 40px 30px;
             text-align: center;
         }
         .header h1 {
             margin: 0;
+            font-family: {{.Branding.HeadingFontFamily}};
             font-size: 28px;
             font-weight: 600;
         }
@@ -196,7 +211,7 @@ func (s *EmailService) renderInvitationTemplate(data InvitationEmailData) (strin
         }
         .details {
             background-color: #f8f9fa;
-            border-left: 4px solid #667eea;
+            border-left: 4px solid {{.Branding.PrimaryColor}};
             padding: 20px;
             margin: 20px 0;
         }
@@ -209,7 +224,7 @@ func (s *EmailService) renderInvitationTemplate(data InvitationEmailData) (strin
         .cta-button {
             display: inline-block;
             padding: 14px 32px;
-            background: linear-gradient(135deg, #667eea 0%, #764ba2 100%);
+            background: linear-gradient(135deg, {{.Branding.PrimaryColor}} 0%, {{.Branding.SecondaryColor}} 100%);
             color: white;
             text-decoration: none;
             border-radius: 6px;
@@ -249,7 +264,7 @@ func (s *EmailService) renderInvitationTemplate(data InvitationEmailData) (strin
 <body>
     <div class="container">
         <div class="header">
-            <h1>🏢 Ecosistema Imob</h1>
+            {{if .Branding.LogoURL}}<img src="{{.Branding.LogoURL}}" alt="{{.Branding.SenderName}}" style="max-height: 64px;">{{else}}<h1>🏢 {{.Branding.SenderName}}</h1>{{end}}
         </div>
 
         <div class="content">
@@ -257,7 +272,7 @@ func (s *EmailService) renderInvitationTemplate(data InvitationEmailData) (strin
 
             <p class="message">
                 Você foi convidado por <strong>{{.InviterName}}</strong> para fazer parte da equipe
-                <strong>{{.TenantName}}</strong> no Ecosistema Imob.
+                <strong>{{.TenantName}}</strong>.
             </p>
 
             <div class="details">
@@ -286,7 +301,7 @@ func (s *EmailService) renderInvitationTemplate(data InvitationEmailData) (strin
 
         <div class="footer">
             <p>Este é um e-mail automático. Por favor, não responda.</p>
-            <p>&copy; 2025 Ecosistema Imob. Todos os direitos reservados.</p>
+            <p>{{.Branding.LegalFooter}}</p>
         </div>
     </div>
 </body>
@@ -311,7 +326,7 @@ func (s *EmailService) generatePlainTextInvitation(data InvitationEmailData) str
 	return fmt.Sprintf(`
 Olá, %s!
 
-Você foi convidado por %s para fazer parte da equipe %s no Ecosistema Imob.
+Você foi convidado por %s para fazer parte da equipe %s.
 
 DETALHES DO CONVITE:
 - Empresa: %s
@@ -326,7 +341,7 @@ ATENÇÃO: Este convite expira em %d dias (%s).
 
 ---
 Este é um e-mail automático. Por favor, não responda.
-© 2025 Ecosistema Imob. Todos os direitos reservados.
+%s
 `,
 		data.InviteeName,
 		data.InviterName,
@@ -337,19 +352,28 @@ Este é um e-mail automático. Por favor, não responda.
 		data.AcceptURL,
 		data.ExpiresInDays,
 		data.ExpiresAt.Format("02/01/2006 às 15:04"),
+		data.Branding.LegalFooter,
 	)
 }
 
 // sendEmail sends an email via SMTP
-func (s *EmailService) sendEmail(toEmail, toName, subject, htmlBody, textBody string) error {
+// The sender name and Reply-To come from the branding; the sender address stays the platform's (SPF/DKIM)
+func (s *EmailService) sendEmail(branding models.TenantBranding, toEmail, toName, subject, htmlBody, textBody string) error {
 	// Build email message
-	from := fmt.Sprintf("%s <%s>", s.fromName, s.fromEmail)
+	fromName := branding.SenderName
+	if fromName == "" {
+		fromName = s.fromName
+	}
+	from := fmt.Sprintf("%s <%s>", fromName, s.fromEmail)
 	to := fmt.Sprintf("%s <%s>", toName, toEmail)
 
+	headers := fmt.Sprintf("From: %s\nTo: %s\n", from, to)
+	if branding.ReplyTo != "" {
+		headers += fmt.Sprintf("Reply-To: %s\n", branding.ReplyTo)
+	}
+
 	// Create MIME message with both HTML and plain text parts
-	message := fmt.Sprintf(`From: %s
-To: %s
-Subject: %s
+	message := fmt.Sprintf(`%sSubject: %s
 MIME-Version: 1.0
 Content-Type: multipart/alternative; boundary="boundary123"
 
@@ -364,7 +388,7 @@ Content-Type: text/html; charset="UTF-8"
 %s
 
 --boundary123--
-`, from, to, subject, textBody, htmlBody)
+`, headers, subject, textBody, htmlBody)
 
 	// Setup authentication
 	auth := smtp.PlainAuth("", s.smtpUser, s.smtpPass, s.smtpHost)
@@ -396,3 +420,345 @@ func (s *EmailService) SendWelcomeEmail(email, name, tenantName string) error {
 	log.Printf("📧 Would send welcome email to %s", email)
 	return nil
 }
+
+// OwnerPortalURL builds the owner portal magic link URL
+func (s *EmailService) OwnerPortalURL(token, tenantID string) string {
+	return fmt.Sprintf("%s/proprietario/entrar?token=%s&tenant_id=%s", s.baseURL, token, tenantID)
+}
+
+// SendOwnerMagicLink sends the owner portal access link
+func (s *EmailService) SendOwnerMagicLink(email, name string, branding models.TenantBranding, magicLinkURL string, expiresAt time.Time) error {
+	subject := fmt.Sprintf("Acesso ao portal do proprietário - %s", branding.SenderName)
+
+	htmlBody := wrapBrandedHTML(branding, fmt.Sprintf(`    <p>Olá, %s!</p>
+    <p>Use o botão abaixo para acessar o portal do proprietário de <strong>%s</strong>, onde você acompanha seus imóveis, atualiza preço e disponibilidade e gerencia seus consentimentos.</p>
+    <p>%s</p>
+    <p style="font-size: 13px; color: #888;">O link é de uso único e expira em %s. Se você não solicitou este acesso, ignore este e-mail.</p>`,
+		template.HTMLEscapeString(name),
+		template.HTMLEscapeString(branding.SenderName),
+		brandedButton(branding, magicLinkURL, "Acessar portal"),
+		expiresAt.Format("02/01/2006 às 15:04"),
+	))
+
+	textBody := fmt.Sprintf(`
+Olá, %s!
+
+Acesse o portal do proprietário de %s pelo link abaixo:
+
+%s
+
+O link é de uso único e expira em %s. Se você não solicitou este acesso, ignore este e-mail.
+%s`, name, branding.SenderName, magicLinkURL, expiresAt.Format("02/01/2006 às 15:04"), brandedTextFooter(branding))
+
+	if s.enabled {
+		if err := s.sendEmail(branding, email, name, subject, htmlBody, textBody); err != nil {
+			log.Printf("❌ Error sending owner magic link via SMTP: %v", err)
+			return err
+		}
+		log.Printf("✅ Owner magic link sent to %s", email)
+		return nil
+	}
+
+	// If email is disabled, just log the recipient (the link is a credential and never goes to the logs)
+	log.Printf("⚠️  Email service disabled - would send owner magic link to: %s", email)
+
+	return nil
+}
+
+// SendSaleAuthorizationExpiryAlert alerts the originating broker that a sale authorization is about to expire
+func (s *EmailService) SendSaleAuthorizationExpiryAlert(email, name string, branding models.TenantBranding, propertyReference string, isExclusive bool, endDate time.Time) error {
+	kind := "Autorização de venda"
+	if isExclusive {
+		kind = "Autorização de venda com exclusividade"
+	}
+	subject := fmt.Sprintf("%s vence em %s - imóvel %s", kind, endDate.Format("02/01/2006"), propertyReference)
+
+	htmlBody := wrapBrandedHTML(branding, fmt.Sprintf(`    <p>Olá, %s!</p>
+    <p>A <strong>%s</strong> do imóvel <strong>%s</strong> vence em <strong>%s</strong>.</p>
+    <p>Renove a autorização com o proprietário para manter o imóvel anunciado. Sem uma autorização válida, o imóvel pode deixar de ser exibido publicamente.</p>`,
+		template.HTMLEscapeString(name),
+		template.HTMLEscapeString(strings.ToLower(kind)),
+		template.HTMLEscapeString(propertyReference),
+		endDate.Format("02/01/2006"),
+	))
+
+	textBody := fmt.Sprintf(`
+Olá, %s!
+
+A %s do imóvel %s vence em %s.
+
+Renove a autorização com o proprietário para manter o imóvel anunciado. Sem uma autorização válida, o imóvel pode deixar de ser exibido publicamente.
+%s`, name, strings.ToLower(kind), propertyReference, endDate.Format("02/01/2006"), brandedTextFooter(branding))
+
+	if s.enabled {
+		if err := s.sendEmail(branding, email, name, subject, htmlBody, textBody); err != nil {
+			log.Printf("❌ Error sending sale authorization alert via SMTP: %v", err)
+			return err
+		}
+		log.Printf("✅ Sale authorization expiry alert sent to %s", email)
+		return nil
+	}
+
+	// If email is disabled, just log the content
+	log.Printf("⚠️  Email service disabled - would send sale authorization alert to: %s", email)
+	log.Printf("📧 EMAIL CONTENT (TEXT):\n%s", textBody)
+
+	return nil
+}
+
+// SendDSRVerificationCode sends the code that confirms the identity of an LGPD data subject request
+func (s *EmailService) SendDSRVerificationCode(email, name string, branding models.TenantBranding, code string, expiresAt time.Time) error {
+	subject := fmt.Sprintf("Código de verificação da sua solicitação LGPD - %s", branding.SenderName)
+
+	htmlBody := wrapBrandedHTML(branding, fmt.Sprintf(`    <p>Olá, %s!</p>
+    <p>Recebemos uma solicitação sobre seus dados pessoais tratados por <strong>%s</strong>. Para confirmar sua identidade, informe o código abaixo:</p>
+    <p style="font-size: 28px; font-weight: bold; letter-spacing: 6px;">%s</p>
+    <p style="font-size: 13px; color: #888;">O código expira em %s. Se você não fez esta solicitação, ignore este e-mail.</p>`,
+		template.HTMLEscapeString(name),
+		template.HTMLEscapeString(branding.SenderName),
+		template.HTMLEscapeString(code),
+		expiresAt.Format("02/01/2006 às 15:04"),
+	))
+
+	textBody := fmt.Sprintf(`
+Olá, %s!
+
+Recebemos uma solicitação sobre seus dados pessoais tratados por %s. Para confirmar sua identidade, informe o código:
+
+%s
+
+O código expira em %s. Se você não fez esta solicitação, ignore este e-mail.
+%s`, name, branding.SenderName, code, expiresAt.Format("02/01/2006 às 15:04"), brandedTextFooter(branding))
+
+	if s.enabled {
+		if err := s.sendEmail(branding, email, name, subject, htmlBody, textBody); err != nil {
+			log.Printf("❌ Error sending DSR verification code via SMTP: %v", err)
+			return err
+		}
+		log.Printf("✅ DSR verification code sent to %s", email)
+		return nil
+	}
+
+	// If email is disabled, just log the recipient (the code proves the subject's identity and never goes to the logs)
+	log.Printf("⚠️  Email service disabled - would send DSR verification code to: %s", email)
+
+	return nil
+}
+
+// SendPriceDropAlert notifies an interested lead that the price of a property dropped (marketing: requires consent)
+func (s *EmailService) SendPriceDropAlert(email, name string, branding models.TenantBranding, propertyLabel, oldPrice, newPrice, propertyURL string) error {
+	subject := fmt.Sprintf("O preço de um imóvel do seu interesse baixou - %s", branding.SenderName)
+
+	htmlBody := wrapBrandedHTML(branding, fmt.Sprintf(`    <p>Olá, %s!</p>
+    <p>O imóvel <strong>%s</strong>, pelo qual você demonstrou interesse, teve o preço reduzido:</p>
+    <p style="font-size: 18px;"><span style="text-decoration: line-through; color: #888;">%s</span> &rarr; <strong>%s</strong></p>
+    <p>%s</p>
+    <p style="font-size: 13px; color: #888;">Você recebe este e-mail porque autorizou comunicações de marketing de %s. Para deixar de recebê-las, responda a este e-mail solicitando o descadastro.</p>`,
+		template.HTMLEscapeString(name),
+		template.HTMLEscapeString(propertyLabel),
+		template.HTMLEscapeString(oldPrice),
+		template.HTMLEscapeString(newPrice),
+		brandedButton(branding, propertyURL, "Ver imóvel"),
+		template.HTMLEscapeString(branding.SenderName),
+	))
+
+	textBody := fmt.Sprintf(`
+Olá, %s!
+
+O imóvel %s, pelo qual você demonstrou interesse, teve o preço reduzido:
+
+De %s por %s
+
+Ver imóvel: %s
+
+Você recebe este e-mail porque autorizou comunicações de marketing de %s. Para deixar de recebê-las, responda a este e-mail solicitando o descadastro.
+%s`, name, propertyLabel, oldPrice, newPrice, propertyURL, branding.SenderName, brandedTextFooter(branding))
+
+	if s.enabled {
+		if err := s.sendEmail(branding, email, name, subject, htmlBody, textBody); err != nil {
+			log.Printf("❌ Error sending price drop alert via SMTP: %v", err)
+			return err
+		}
+		log.Printf("✅ Price drop alert sent to %s", email)
+		return nil
+	}
+
+	// If email is disabled, just log the content
+	log.Printf("⚠️  Email service disabled - would send price drop alert to: %s", email)
+	log.Printf("📧 EMAIL CONTENT (TEXT):\n%s", textBody)
+
+	return nil
+}
+
+// LeadURL builds the admin URL of a lead
+func (s *EmailService) LeadURL(leadID string) string {
+	return fmt.Sprintf("%s/dashboard/leads/%s", s.baseURL, leadID)
+}
+
+// SendNewLeadNotification notifies a broker of a new lead of a property (or of a lead assigned to them)
+func (s *EmailService) SendNewLeadNotification(email, name string, branding models.TenantBranding, contactName, channelLabel, propertyLabel, message, leadURL string, assigned bool) error {
+	subject := fmt.Sprintf("Novo lead via %s: %s - %s", channelLabel, contactName, propertyLabel)
+	intro := fmt.Sprintf("Você recebeu um novo lead via %s: %s tem interesse no imóvel", channelLabel, contactName)
+	if assigned {
+		subject = fmt.Sprintf("Lead atribuído a você: %s - %s", contactName, propertyLabel)
+		intro = fmt.Sprintf("O lead %s foi atribuído a você. Interesse no imóvel", contactName)
+	}
+
+	quote := ""
+	textQuote := ""
+	if message != "" {
+		quote = fmt.Sprintf(`
+    <blockquote style="border-left: 4px solid #ddd; margin: 0; padding: 8px 16px; color: #555;">%s</blockquote>`, template.HTMLEscapeString(message))
+		textQuote = fmt.Sprintf("\n\"%s\"\n", message)
+	}
+
+	htmlBody := wrapBrandedHTML(branding, fmt.Sprintf(`    <p>Olá, %s!</p>
+    <p>%s <strong>%s</strong>.</p>%s
+    <p>%s</p>`,
+		template.HTMLEscapeString(name),
+		template.HTMLEscapeString(intro),
+		template.HTMLEscapeString(propertyLabel),
+		quote,
+		brandedButton(branding, leadURL, "Ver lead"),
+	))
+
+	textBody := fmt.Sprintf(`
+Olá, %s!
+
+%s %s.
+%s
+Ver lead: %s
+%s`, name, intro, propertyLabel, textQuote, leadURL, brandedTextFooter(branding))
+
+	if s.enabled {
+		if err := s.sendEmail(branding, email, name, subject, htmlBody, textBody); err != nil {
+			log.Printf("❌ Error sending new lead notification via SMTP: %v", err)
+			return err
+		}
+		log.Printf("✅ New lead notification sent to %s", email)
+		return nil
+	}
+
+	// If email is disabled, just log the content
+	log.Printf("⚠️  Email service disabled - would send new lead notification to: %s", email)
+	log.Printf("📧 EMAIL CONTENT (TEXT):\n%s", textBody)
+
+	return nil
+}
+
+// SendLeadMessageNotification notifies the routed broker of a WhatsApp message received from a lead
+func (s *EmailService) SendLeadMessageNotification(email, name string, branding models.TenantBranding, contactName, propertyLabel, message, leadURL string, isNewLead bool) error {
+	subject := fmt.Sprintf("Nova mensagem de %s no WhatsApp - %s", contactName, propertyLabel)
+	intro := fmt.Sprintf("%s enviou uma mensagem no WhatsApp sobre o imóvel", contactName)
+	if isNewLead {
+		subject = fmt.Sprintf("Novo lead via WhatsApp: %s - %s", contactName, propertyLabel)
+		intro = fmt.Sprintf("Você recebeu um novo lead via WhatsApp: %s tem interesse no imóvel", contactName)
+	}
+
+	htmlBody := wrapBrandedHTML(branding, fmt.Sprintf(`    <p>Olá, %s!</p>
+    <p>%s <strong>%s</strong>:</p>
+    <blockquote style="border-left: 4px solid #ddd; margin: 0; padding: 8px 16px; color: #555;">%s</blockquote>
+    <p>%s</p>`,
+		template.HTMLEscapeString(name),
+		template.HTMLEscapeString(intro),
+		template.HTMLEscapeString(propertyLabel),
+		template.HTMLEscapeString(message),
+		brandedButton(branding, leadURL, "Ver conversa"),
+	))
+
+	textBody := fmt.Sprintf(`
+Olá, %s!
+
+%s %s:
+
+"%s"
+
+Ver conversa: %s
+%s`, name, intro, propertyLabel, message, leadURL, brandedTextFooter(branding))
+
+	if s.enabled {
+		if err := s.sendEmail(branding, email, name, subject, htmlBody, textBody); err != nil {
+			log.Printf("❌ Error sending lead message notification via SMTP: %v", err)
+			return err
+		}
+		log.Printf("✅ Lead message notification sent to %s", email)
+		return nil
+	}
+
+	// If email is disabled, just log the content
+	log.Printf("⚠️  Email service disabled - would send lead message notification to: %s", email)
+	log.Printf("📧 EMAIL CONTENT (TEXT):\n%s", textBody)
+
+	return nil
+}
+
+// SendLeadReply sends a broker's reply to a lead (conversation inbox, e-mail channel)
+func (s *EmailService) SendLeadReply(email, name string, branding models.TenantBranding, subject, body string) error {
+	htmlBody := wrapBrandedHTML(branding, fmt.Sprintf(`    <p>%s</p>`,
+		strings.ReplaceAll(template.HTMLEscapeString(body), "\n", "<br>"),
+	))
+
+	textBody := fmt.Sprintf(`
+%s
+%s`, body, brandedTextFooter(branding))
+
+	if s.enabled {
+		if err := s.sendEmail(branding, email, name, subject, htmlBody, textBody); err != nil {
+			log.Printf("❌ Error sending lead reply via SMTP: %v", err)
+			return err
+		}
+		log.Printf("✅ Lead reply sent to %s", email)
+		return nil
+	}
+
+	// If email is disabled, just log the content
+	log.Printf("⚠️  Email service disabled - would send lead reply to: %s", email)
+	log.Printf("📧 EMAIL CONTENT (TEXT):\n%s", textBody)
+
+	return nil
+}
+
+// wrapBrandedHTML wraps the body of a transactional e-mail with the header (logo or sender name)
+// and the legal footer of the branding
+func wrapBrandedHTML(branding models.TenantBranding, body string) string {
+	header := fmt.Sprintf(`<h2 style="font-family: %s; color: %s;">%s</h2>`,
+		template.HTMLEscapeString(branding.HeadingFontFamily),
+		template.HTMLEscapeString(branding.PrimaryColor),
+		template.HTMLEscapeString(branding.SenderName),
+	)
+	if branding.LogoURL != "" {
+		header = fmt.Sprintf(`<p><img src="%s" alt="%s" style="max-height: 48px;"></p>`,
+			template.HTMLEscapeString(branding.LogoURL),
+			template.HTMLEscapeString(branding.SenderName),
+		)
+	}
+
+	return fmt.Sprintf(`<!DOCTYPE html>
+<html lang="pt-BR">
+<body style="font-family: %s; color: #333;">
+    %s
+%s
+    <hr style="border: none; border-top: 1px solid #eee; margin-top: 24px;">
+    <p style="font-size: 12px; color: #888;">%s</p>
+</body>
+</html>`,
+		template.HTMLEscapeString(branding.FontFamily),
+		header,
+		body,
+		template.HTMLEscapeString(branding.LegalFooter),
+	)
+}
+
+// brandedButton renders a call-to-action link in the primary color of the branding
+func brandedButton(branding models.TenantBranding, url, label string) string {
+	return fmt.Sprintf(`<a href="%s" style="display: inline-block; padding: 12px 24px; background-color: %s; color: #ffffff; text-decoration: none; border-radius: 6px;">%s</a>`,
+		template.HTMLEscapeString(url),
+		template.HTMLEscapeString(branding.PrimaryColor),
+		template.HTMLEscapeString(label),
+	)
+}
+
+// brandedTextFooter returns the legal footer of the plain text version of an e-mail
+func brandedTextFooter(branding models.TenantBranding) string {
+	return fmt.Sprintf("\n---\n%s\n", branding.LegalFooter)
+}
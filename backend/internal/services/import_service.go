@@ -10,6 +10,8 @@ import (
 	"cloud.google.com/go/firestore"
 	"github.com/altatech/ecosistema-imob/backend/internal/adapters/union"
 	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/pii"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
 	"github.com/google/uuid"
 )
 
@@ -18,6 +20,9 @@ type ImportService struct {
 	db                   *firestore.Client
 	deduplicationService *DeduplicationService
 	photoProcessor       *PhotoProcessor // Optional - nil if GCS not configured
+	encryptor            *pii.Encryptor  // Optional - field-level encryption of owner PII
+	planService          *PlanService    // Optional - subscription plan quotas
+	usageService         *UsageService   // Optional - usage metering (billing)
 }
 
 // NewImportService creates a new import service
@@ -43,6 +48,21 @@ func (s *ImportService) SetPhotoProcessor(photoProcessor *PhotoProcessor) {
 	s.photoProcessor = photoProcessor
 }
 
+// SetEncryptor enables field-level encryption of owner PII (optional)
+func (s *ImportService) SetEncryptor(encryptor *pii.Encryptor) {
+	s.encryptor = encryptor
+}
+
+// SetPlanService enables the subscription plan quotas (optional)
+func (s *ImportService) SetPlanService(planService *PlanService) {
+	s.planService = planService
+}
+
+// SetUsageService enables the metering of import batches (optional)
+func (s *ImportService) SetUsageService(usageService *UsageService) {
+	s.usageService = usageService
+}
+
 // GetDB returns the Firestore client
 func (s *ImportService) GetDB() *firestore.Client {
 	return s.db
@@ -59,6 +79,11 @@ type ImportBatchRequest struct {
 
 // ImportProperty imports a single property with all related entities
 func (s *ImportService) ImportProperty(ctx context.Context, batch *models.ImportBatch, payload union.PropertyPayload) error {
+	// Photos beyond the photos-per-listing quota of the plan are not imported
+	if s.planService != nil {
+		payload.Photos = s.limitPhotos(ctx, batch, payload)
+	}
+
 	// 1. Check for duplicates
 	dedupResult, err := s.deduplicationService.CheckDuplicate(ctx, &payload.Property)
 	if err != nil {
@@ -152,6 +177,13 @@ func (s *ImportService) ImportProperty(ctx context.Context, batch *models.Import
 		log.Printf("Property %s is a possible duplicate (fingerprint match)", payload.Property.Reference)
 	}
 
+	// New properties must fit the property quota of the plan
+	if s.planService != nil {
+		if err := s.planService.CheckPropertyQuota(ctx, batch.TenantID, 1); err != nil {
+			return err
+		}
+	}
+
 	// 2. Create Owner
 	ownerID, enrichedFromXLS, err := s.createOwner(ctx, batch.TenantID, payload.Owner, payload.Property.Reference)
 	if err != nil {
@@ -369,9 +401,14 @@ func (s *ImportService) createOwner(ctx context.Context, tenantID string, ownerP
 		UpdatedAt: now,
 	}
 
+	stored, err := repositories.EncryptOwner(ctx, s.encryptor, &owner)
+	if err != nil {
+		return "", false, err
+	}
+
 	// Use tenant-scoped collection path
 	ownersPath := fmt.Sprintf("tenants/%s/owners", tenantID)
-	_, err := s.db.Collection(ownersPath).Doc(ownerID).Set(ctx, owner)
+	_, err = s.db.Collection(ownersPath).Doc(ownerID).Set(ctx, stored)
 	if err != nil {
 		return "", false, fmt.Errorf("failed to save owner: %w", err)
 	}
@@ -406,6 +443,9 @@ func (s *ImportService) updateOwnerFromXLS(ctx context.Context, tenantID, ownerI
 	if err := ownerDoc.DataTo(&existingOwner); err != nil {
 		return fmt.Errorf("failed to parse existing owner: %w", err)
 	}
+	if err := repositories.DecryptOwner(ctx, s.encryptor, &existingOwner); err != nil {
+		return err
+	}
 
 	// Prepare updates
 	updates := []firestore.Update{
@@ -459,7 +499,11 @@ func (s *ImportService) updateOwnerFromXLS(ctx context.Context, tenantID, ownerI
 		return nil
 	}
 
-	// Apply updates
+	// Apply updates (email/phone encrypted when field-level encryption is enabled)
+	updates, err = repositories.EncryptPIIFirestoreUpdates(ctx, s.encryptor, repositories.OwnerPIIFields, updates)
+	if err != nil {
+		return err
+	}
 	_, err = s.db.Collection(ownersPath).Doc(ownerID).Update(ctx, updates)
 	if err != nil {
 		return fmt.Errorf("failed to update owner: %w", err)
@@ -570,8 +614,20 @@ func (s *ImportService) logActivity(ctx context.Context, tenantID, eventType str
 }
 
 // CreateBatch creates a new import batch
+// Fails with a PlanLimitError when the monthly imports of the plan are used up
 func (s *ImportService) CreateBatch(ctx context.Context, tenantID, source, createdBy string) (*models.ImportBatch, error) {
 	now := time.Now()
+
+	if s.planService != nil {
+		used, err := s.CountMonthlyBatches(ctx, tenantID, now)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.planService.CheckLimit(ctx, tenantID, models.PlanResourceImportsPerMonth, used, 1); err != nil {
+			return nil, err
+		}
+	}
+
 	batchID := uuid.New().String()
 
 	batch := &models.ImportBatch{
@@ -593,9 +649,47 @@ func (s *ImportService) CreateBatch(ctx context.Context, tenantID, source, creat
 		"source":   source,
 	})
 
+	if s.usageService != nil {
+		s.usageService.Record(ctx, tenantID, models.UsageMetricImports, 1)
+	}
+
 	return batch, nil
 }
 
+// CountMonthlyBatches returns the number of import batches started by a tenant in the calendar month of now
+func (s *ImportService) CountMonthlyBatches(ctx context.Context, tenantID string, now time.Time) (int, error) {
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+
+	docs, err := s.db.Collection("import_batches").
+		Where("tenant_id", "==", tenantID).
+		Where("started_at", ">=", monthStart).
+		Select().
+		Documents(ctx).
+		GetAll()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count import batches: %w", err)
+	}
+
+	return len(docs), nil
+}
+
+// limitPhotos truncates the photos of a payload to the photos-per-listing quota of the tenant's plan
+func (s *ImportService) limitPhotos(ctx context.Context, batch *models.ImportBatch, payload union.PropertyPayload) []string {
+	plan, err := s.planService.GetTenantPlan(ctx, batch.TenantID)
+	if err != nil {
+		log.Printf("Warning: failed to get plan of tenant %s: %v", batch.TenantID, err)
+		return payload.Photos
+	}
+
+	limit := plan.MaxPhotosPerListing
+	if limit == 0 || len(payload.Photos) <= limit {
+		return payload.Photos
+	}
+
+	log.Printf("ℹ️  Property %s has %d photos - importing the first %d (%s plan)", payload.Property.Reference, len(payload.Photos), limit, plan.ID)
+	return payload.Photos[:limit]
+}
+
 // CompleteBatch marks batch as completed
 func (s *ImportService) CompleteBatch(ctx context.Context, batch *models.ImportBatch) error {
 	log.Printf("🏁 CompleteBatch called for batch %s", batch.ID)
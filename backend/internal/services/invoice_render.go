@@ -0,0 +1,101 @@
+package services
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/utils"
+)
+
+// invoiceStatusLabels are the Portuguese labels of the invoice statuses
+var invoiceStatusLabels = map[models.InvoiceStatus]string{
+	models.InvoiceStatusOpen:    "Em aberto",
+	models.InvoiceStatusPaid:    "Paga",
+	models.InvoiceStatusOverdue: "Vencida",
+	models.InvoiceStatusVoid:    "Cancelada",
+}
+
+// WriteInvoicePDF renders an invoice as a PDF document
+func WriteInvoicePDF(w io.Writer, invoice *models.Invoice) error {
+	doc := utils.NewPDFDocument()
+	doc.Title(fmt.Sprintf("Fatura %s", invoice.Number))
+
+	doc.Heading("Resumo")
+	doc.Text(fmt.Sprintf("Período: %s a %s",
+		invoice.PeriodStart.Format("02/01/2006"), invoice.PeriodEnd.AddDate(0, 0, -1).Format("02/01/2006")))
+	doc.Text(fmt.Sprintf("Plano: %s", models.GetPlan(invoice.Plan).Name))
+	doc.Text(fmt.Sprintf("Vencimento: %s", invoice.DueAt.In(metricsLocation).Format("02/01/2006")))
+	doc.Text(fmt.Sprintf("Situação: %s", invoiceStatusLabels[invoice.Status]))
+
+	doc.Heading("Itens")
+	for _, item := range invoice.LineItems {
+		doc.Text(fmt.Sprintf("%s: %d x %s = %s", item.Description, item.Quantity,
+			formatBRL(centsToReais(item.UnitPriceCents)), formatBRL(centsToReais(item.AmountCents))))
+		if item.Metric != "" {
+			doc.Text(fmt.Sprintf("  Utilizado: %d (incluso: %d)", item.Used, item.Included))
+		}
+	}
+
+	doc.Space(8)
+	doc.Heading(fmt.Sprintf("Total: %s", formatBRL(centsToReais(invoice.TotalCents))))
+
+	if invoice.Status == models.InvoiceStatusOpen && invoice.PixCopyPaste != "" {
+		doc.Space(8)
+		doc.Text("PIX copia e cola:")
+		doc.Text(invoice.PixCopyPaste)
+	}
+	if invoice.PaidAt != nil {
+		doc.Space(8)
+		doc.Text(fmt.Sprintf("Paga em %s.", invoice.PaidAt.In(metricsLocation).Format("02/01/2006 15:04")))
+	}
+
+	_, err := doc.WriteTo(w)
+	return err
+}
+
+// WriteInvoiceCSV writes the line items of an invoice as CSV (one row per line item)
+func WriteInvoiceCSV(w io.Writer, invoice *models.Invoice) error {
+	writer := csv.NewWriter(w)
+
+	header := []string{
+		"invoice_number", "period", "plan", "metric", "description",
+		"used", "included", "quantity", "unit_price", "amount",
+	}
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	for _, item := range invoice.LineItems {
+		row := []string{
+			invoice.Number,
+			invoice.Period,
+			invoice.Plan,
+			item.Metric,
+			item.Description,
+			strconv.FormatInt(item.Used, 10),
+			strconv.FormatInt(item.Included, 10),
+			strconv.FormatInt(item.Quantity, 10),
+			formatDecimal(centsToReais(item.UnitPriceCents)),
+			formatDecimal(centsToReais(item.AmountCents)),
+		}
+		if err := writer.Write(row); err != nil {
+			return fmt.Errorf("failed to write CSV row: %w", err)
+		}
+	}
+
+	total := []string{invoice.Number, invoice.Period, invoice.Plan, "", "Total", "", "", "", "", formatDecimal(centsToReais(invoice.TotalCents))}
+	if err := writer.Write(total); err != nil {
+		return fmt.Errorf("failed to write CSV row: %w", err)
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// centsToReais converts an amount in cents to reais
+func centsToReais(cents int64) float64 {
+	return float64(cents) / 100
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// leadNotificationChannels are the lead channels whose new leads are e-mailed to the routed broker
+// WhatsApp leads are notified by WhatsAppInboundService with the message received
+var leadNotificationChannels = map[string]string{
+	"lead_created_form":  "formulário",
+	"lead_created_email": "e-mail",
+	"lead_created_phone": "telefone",
+}
+
+// LeadNotificationService e-mails brokers about their new and assigned leads
+// It runs as an outbox consumer (NewNotificationConsumer), so notifications follow committed lead writes only
+type LeadNotificationService struct {
+	leadService  *LeadService
+	leadRepo     *repositories.LeadRepository
+	brokerRepo   *repositories.BrokerRepository
+	propertyRepo *repositories.PropertyRepository
+	tenantRepo   *repositories.TenantRepository
+	emailService *EmailService
+}
+
+// NewLeadNotificationService creates a new lead notification service
+func NewLeadNotificationService(
+	leadService *LeadService,
+	leadRepo *repositories.LeadRepository,
+	brokerRepo *repositories.BrokerRepository,
+	propertyRepo *repositories.PropertyRepository,
+	tenantRepo *repositories.TenantRepository,
+	emailService *EmailService,
+) *LeadNotificationService {
+	return &LeadNotificationService{
+		leadService:  leadService,
+		leadRepo:     leadRepo,
+		brokerRepo:   brokerRepo,
+		propertyRepo: propertyRepo,
+		tenantRepo:   tenantRepo,
+		emailService: emailService,
+	}
+}
+
+// NotifyEvent sends the notification of a lead event (other events are ignored)
+func (s *LeadNotificationService) NotifyEvent(ctx context.Context, event *models.OutboxEvent) error {
+	channelLabel, isNewLead := leadNotificationChannels[event.EventType]
+	if !isNewLead && event.EventType != "lead_assigned_to_broker" {
+		return nil
+	}
+
+	lead, err := s.leadRepo.Get(ctx, event.TenantID, event.AggregateID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil // Deleted before the notification
+	}
+	if err != nil {
+		return err
+	}
+	if lead.IsAnonymized || lead.ConsentRevoked {
+		return nil
+	}
+
+	brokerID, _ := event.Metadata["broker_id"].(string)
+	if isNewLead {
+		brokerID, err = s.leadService.RouteToAvailableBroker(ctx, event.TenantID, lead.ID)
+		if err != nil {
+			return nil // No broker to notify (the lead stays in the inbox)
+		}
+	}
+	if brokerID == "" {
+		return nil
+	}
+
+	broker, err := s.brokerRepo.Get(ctx, event.TenantID, brokerID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get broker: %w", err)
+	}
+	if broker.Email == "" {
+		return nil
+	}
+
+	tenant, err := s.tenantRepo.Get(ctx, event.TenantID)
+	if err != nil {
+		return fmt.Errorf("failed to get tenant: %w", err)
+	}
+
+	propertyLabel := lead.PropertyID
+	if property, err := s.propertyRepo.Get(ctx, event.TenantID, lead.PropertyID); err == nil {
+		propertyLabel = property.Reference
+		if propertyLabel == "" {
+			propertyLabel = fmt.Sprintf("%s - %s", property.Neighborhood, property.City)
+		}
+	}
+
+	if channelLabel == "" {
+		channelLabel = string(lead.Channel)
+	}
+	return s.emailService.SendNewLeadNotification(broker.Email, broker.Name, tenant.ResolvedBranding(), lead.Name, channelLabel, propertyLabel, lead.Message, s.emailService.LeadURL(lead.ID), !isNewLead)
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// defaultLeadRescoreBatchSize is the number of leads read per page when rescoring a tenant
+const defaultLeadRescoreBatchSize = 500
+
+// LeadScoreStore is the lead persistence used by LeadScoringService (implemented by repositories.LeadRepository)
+type LeadScoreStore interface {
+	Get(ctx context.Context, tenantID, id string) (*models.Lead, error)
+	List(ctx context.Context, tenantID string, filters *repositories.LeadFilters, opts repositories.PaginationOptions) ([]*models.Lead, error)
+	ListByContactID(ctx context.Context, tenantID, contactID string) ([]*models.Lead, error)
+	UpdateScore(ctx context.Context, tenantID, id string, score int, factors []models.LeadScoreFactor, scoredAt time.Time) error
+}
+
+// ConversationGetter reads the conversation of a lead (implemented by repositories.ConversationRepository)
+type ConversationGetter interface {
+	GetConversation(ctx context.Context, tenantID, leadID string) (*models.Conversation, error)
+}
+
+// LeadScoringService computes the rule-based lead score (models.ScoreLead) from the lead, its property,
+// the other leads of its contact and its conversation, and stores it on the lead with the contributing factors
+// Scores are recalculated on lead events (outbox consumer) and on conversation messages
+type LeadScoringService struct {
+	leadRepo         LeadScoreStore
+	propertyRepo     PropertyGetter
+	conversationRepo ConversationGetter
+}
+
+// NewLeadScoringService creates a new lead scoring service
+func NewLeadScoringService(
+	leadRepo LeadScoreStore,
+	propertyRepo PropertyGetter,
+	conversationRepo ConversationGetter,
+) *LeadScoringService {
+	return &LeadScoringService{
+		leadRepo:         leadRepo,
+		propertyRepo:     propertyRepo,
+		conversationRepo: conversationRepo,
+	}
+}
+
+// ScoreLead recalculates and stores the score of a lead (anonymized leads keep their last score)
+func (s *LeadScoringService) ScoreLead(ctx context.Context, tenantID, leadID string) (*models.Lead, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+	if leadID == "" {
+		return nil, fmt.Errorf("lead ID is required")
+	}
+
+	lead, err := s.leadRepo.Get(ctx, tenantID, leadID)
+	if err != nil {
+		return nil, err
+	}
+
+	return lead, s.score(ctx, lead)
+}
+
+// RescoreLeads recalculates the scores of every lead of a tenant and returns how many were scored
+// Time-based signals (unanswered outreach) change without events, and leads created before scoring have
+// no score (they are left out when sorting by score): this endpoint should be called by a daily cron job
+// Leads are read in pages of batchSize ordered by ID, so leads created during the run do not shift pages
+func (s *LeadScoringService) RescoreLeads(ctx context.Context, tenantID string, batchSize int) (int, error) {
+	if tenantID == "" {
+		return 0, fmt.Errorf("tenant_id is required")
+	}
+	if batchSize <= 0 {
+		batchSize = defaultLeadRescoreBatchSize
+	}
+
+	scored := 0
+	opts := repositories.PaginationOptions{
+		Limit:     batchSize,
+		OrderBy:   firestore.DocumentID,
+		Direction: firestore.Asc,
+	}
+	for {
+		leads, err := s.leadRepo.List(ctx, tenantID, nil, opts)
+		if err != nil {
+			return scored, fmt.Errorf("failed to list leads: %w", err)
+		}
+
+		for _, lead := range leads {
+			if lead.IsAnonymized {
+				continue
+			}
+			if err := s.score(ctx, lead); err != nil {
+				return scored, err
+			}
+			scored++
+		}
+
+		if len(leads) < batchSize {
+			return scored, nil
+		}
+		opts.StartAfter = leads[len(leads)-1].ID
+	}
+}
+
+// score computes the score of a lead and stores it
+func (s *LeadScoringService) score(ctx context.Context, lead *models.Lead) error {
+	if lead.IsAnonymized {
+		return nil
+	}
+
+	signals, err := s.signals(ctx, lead)
+	if err != nil {
+		return err
+	}
+
+	score, factors := models.ScoreLead(signals)
+	if err := s.leadRepo.UpdateScore(ctx, lead.TenantID, lead.ID, score, factors, signals.Now); err != nil {
+		return fmt.Errorf("failed to store lead score: %w", err)
+	}
+
+	lead.Score = score
+	lead.ScoreFactors = factors
+	lead.ScoredAt = &signals.Now
+	return nil
+}
+
+// signals gathers the scoring signals of a lead
+func (s *LeadScoringService) signals(ctx context.Context, lead *models.Lead) (models.LeadScoreSignals, error) {
+	signals := models.LeadScoreSignals{
+		Channel:            lead.Channel,
+		Message:            lead.Message,
+		FinancingSimulated: lead.FinancingSimulated,
+		PropertiesViewed:   lead.PropertiesViewed,
+		PropertiesInquired: 1,
+		Budget:             lead.Budget,
+		Now:                time.Now(),
+	}
+
+	if lead.Phone != "" && lead.Phone != "WhatsApp" {
+		_, err := normalizeLeadPhone(lead.Phone)
+		signals.PhoneValid = err == nil
+	}
+
+	if lead.Budget > 0 {
+		property, err := s.propertyRepo.Get(ctx, lead.TenantID, lead.PropertyID)
+		if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+			return signals, fmt.Errorf("failed to get property: %w", err)
+		}
+		if err == nil {
+			signals.PropertyPrice = property.PriceAmount
+		}
+	}
+
+	// Properties the same person asked about (leads of the contact)
+	if lead.ContactID != "" {
+		leads, err := s.leadRepo.ListByContactID(ctx, lead.TenantID, lead.ContactID)
+		if err != nil {
+			return signals, fmt.Errorf("failed to list contact leads: %w", err)
+		}
+		properties := map[string]bool{lead.PropertyID: true}
+		for _, other := range leads {
+			if !other.IsAnonymized {
+				properties[other.PropertyID] = true
+			}
+		}
+		signals.PropertiesInquired = len(properties)
+	}
+
+	conversation, err := s.conversationRepo.GetConversation(ctx, lead.TenantID, lead.ID)
+	if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+		return signals, fmt.Errorf("failed to get conversation: %w", err)
+	}
+	if err == nil {
+		signals.OutreachAt = conversation.FirstOutreachAt
+		signals.RepliedAt = conversation.FirstReplyAt
+	}
+
+	return signals, nil
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// fakeLeadScoreStore keeps the leads of one tenant and pages them by ID like the Firestore query
+type fakeLeadScoreStore struct {
+	leads  map[string]*models.Lead
+	pages  int
+	scored map[string]int
+}
+
+func newFakeLeadScoreStore(leads ...*models.Lead) *fakeLeadScoreStore {
+	store := &fakeLeadScoreStore{leads: make(map[string]*models.Lead), scored: make(map[string]int)}
+	for _, lead := range leads {
+		store.leads[lead.ID] = lead
+	}
+	return store
+}
+
+func (f *fakeLeadScoreStore) Get(ctx context.Context, tenantID, id string) (*models.Lead, error) {
+	lead, ok := f.leads[id]
+	if !ok {
+		return nil, repositories.ErrNotFound
+	}
+	return lead, nil
+}
+
+func (f *fakeLeadScoreStore) List(ctx context.Context, tenantID string, filters *repositories.LeadFilters, opts repositories.PaginationOptions) ([]*models.Lead, error) {
+	f.pages++
+
+	ids := make([]string, 0, len(f.leads))
+	for id := range f.leads {
+		if after, ok := opts.StartAfter.(string); !ok || id > after {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	if len(ids) > opts.Limit {
+		ids = ids[:opts.Limit]
+	}
+
+	leads := make([]*models.Lead, 0, len(ids))
+	for _, id := range ids {
+		leads = append(leads, f.leads[id])
+	}
+	return leads, nil
+}
+
+func (f *fakeLeadScoreStore) ListByContactID(ctx context.Context, tenantID, contactID string) ([]*models.Lead, error) {
+	return nil, nil
+}
+
+func (f *fakeLeadScoreStore) UpdateScore(ctx context.Context, tenantID, id string, score int, factors []models.LeadScoreFactor, scoredAt time.Time) error {
+	f.scored[id] = score
+	return nil
+}
+
+// fakeConversationGetter has no conversations
+type fakeConversationGetter struct{}
+
+func (fakeConversationGetter) GetConversation(ctx context.Context, tenantID, leadID string) (*models.Conversation, error) {
+	return nil, repositories.ErrNotFound
+}
+
+func TestRescoreLeadsPagesThroughEveryLead(t *testing.T) {
+	leads := make([]*models.Lead, 0, 12)
+	for i := 1; i <= 12; i++ {
+		leads = append(leads, &models.Lead{ID: fmt.Sprintf("lead-%02d", i), TenantID: "tenant-1", Channel: models.LeadChannelForm})
+	}
+	leads[4].IsAnonymized = true
+
+	store := newFakeLeadScoreStore(leads...)
+	service := NewLeadScoringService(store, fakePropertyGetter{}, fakeConversationGetter{})
+
+	scored, err := service.RescoreLeads(context.Background(), "tenant-1", 5)
+	require.NoError(t, err)
+
+	assert.Equal(t, 11, scored, "every lead but the anonymized one")
+	assert.Len(t, store.scored, 11)
+	assert.NotContains(t, store.scored, "lead-05")
+	assert.Equal(t, 3, store.pages, "pages of 5, 5 and 2 leads")
+}
+
+func TestScoreLeadUsesThePropertiesViewed(t *testing.T) {
+	store := newFakeLeadScoreStore(&models.Lead{ID: "lead-1", TenantID: "tenant-1", Channel: models.LeadChannelForm, PropertiesViewed: 6})
+	service := NewLeadScoringService(store, fakePropertyGetter{}, fakeConversationGetter{})
+
+	lead, err := service.ScoreLead(context.Background(), "tenant-1", "lead-1")
+	require.NoError(t, err)
+
+	codes := make([]string, 0, len(lead.ScoreFactors))
+	for _, factor := range lead.ScoreFactors {
+		codes = append(codes, factor.Code)
+	}
+	assert.Contains(t, codes, models.LeadScoreFactorPropertiesViewed)
+	assert.Equal(t, lead.Score, store.scored["lead-1"])
+}
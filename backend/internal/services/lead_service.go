@@ -3,6 +3,8 @@ package services
 import (
 	"context"
 	"fmt"
+	"log"
+	"strings"
 	"time"
 
 	"github.com/altatech/ecosistema-imob/backend/internal/models"
@@ -17,6 +19,15 @@ type LeadService struct {
 	roleRepo        *repositories.PropertyBrokerRoleRepository
 	tenantRepo      *repositories.TenantRepository
 	activityLogRepo *repositories.ActivityLogRepository
+	metricsService  *ListingMetricsService               // optional: lead counters in listing performance rollups
+	consentService  *ConsentService                      // optional: consent ledger (grants/withdrawals per purpose)
+	outbox          *OutboxRelay                         // optional: transactional outbox (writes + domain events)
+	domainService   *DomainService                       // optional: canonical property URLs (tenant domains)
+	brokerRepo      *repositories.BrokerRepository       // optional: WhatsApp number of the routed broker
+	whatsAppLinks   *WhatsAppLinkService                 // optional: click-tracking short links of WhatsApp deep links
+	conversations   *repositories.ConversationRepository // optional: conversation history (messages) of leads
+	inbox           *ConversationService                 // optional: unified inbox (first message of form/e-mail/phone leads)
+	contacts        *ContactService                      // optional: deduplication of leads into contacts (same phone/e-mail)
 }
 
 // NewLeadService creates a new lead service
@@ -76,10 +87,11 @@ func (s *LeadService) CreateLead(ctx context.Context, lead *models.Lead) error {
 
 	// Validate phone if provided (skip validation for WhatsApp placeholder)
 	if lead.Phone != "" && lead.Phone != "WhatsApp" {
-		if err := utils.ValidatePhoneBR(lead.Phone); err != nil {
-			return fmt.Errorf("invalid phone: %w", err)
+		phone, err := normalizeLeadPhone(lead.Phone)
+		if err != nil {
+			return err
 		}
-		lead.Phone = utils.NormalizePhoneBR(lead.Phone)
+		lead.Phone = phone
 	}
 
 	// Validate channel
@@ -87,6 +99,15 @@ func (s *LeadService) CreateLead(ctx context.Context, lead *models.Lead) error {
 		return err
 	}
 
+	if lead.Budget < 0 {
+		return fmt.Errorf("budget must not be negative")
+	}
+
+	// The score is calculated by LeadScoringService on the lead_created event
+	lead.Score = 0
+	lead.ScoreFactors = nil
+	lead.ScoredAt = nil
+
 	// Set default status
 	if lead.Status == "" {
 		lead.Status = models.LeadStatusNew
@@ -102,28 +123,173 @@ func (s *LeadService) CreateLead(ctx context.Context, lead *models.Lead) error {
 		lead.ConsentText = "Autorizo o uso dos meus dados pessoais para contato sobre este imóvel, conforme a Lei Geral de Proteção de Dados (LGPD)."
 	}
 
+	// LGPD: Contact consent is always part of the captured purposes
+	lead.ConsentPurposes = withContactPurpose(lead.ConsentPurposes)
+
 	// LGPD: Initialize anonymization flags
 	lead.ConsentRevoked = false
 	lead.IsAnonymized = false
 
-	// Create lead in repository
-	if err := s.leadRepo.Create(ctx, lead); err != nil {
+	// The contact is assigned once the lead exists (AssignContact sets contact_id)
+	lead.ContactID = ""
+
+	// Create lead and its event (based on channel) in one transaction
+	write, err := s.leadRepo.CreateTx(ctx, lead)
+	if err != nil {
 		return fmt.Errorf("failed to create lead: %w", err)
 	}
-
-	// Log activity based on channel
 	eventType := fmt.Sprintf("lead_created_%s", lead.Channel)
-	_ = s.logActivity(ctx, lead.TenantID, eventType, models.ActorTypeSystem, "", map[string]interface{}{
+	event := models.NewOutboxEvent(lead.TenantID, eventType, "lead", lead.ID, models.ActorTypeSystem, "", map[string]interface{}{
 		"lead_id":       lead.ID,
 		"property_id":   lead.PropertyID,
 		"channel":       lead.Channel,
 		"consent_given": lead.ConsentGiven,
 		"consent_ip":    lead.ConsentIP,
 	})
+	if err := s.commit(ctx, event, write); err != nil {
+		return fmt.Errorf("failed to create lead: %w", err)
+	}
+
+	// LGPD: Record each captured purpose in the consent ledger
+	if s.consentService != nil {
+		origin := ConsentOriginForm
+		if lead.Channel == models.LeadChannelWhatsApp {
+			origin = ConsentOriginWhatsApp
+		}
+		for _, purpose := range lead.ConsentPurposes {
+			input := RecordConsentInput{
+				TenantID:    lead.TenantID,
+				SubjectType: models.ConsentSubjectLead,
+				SubjectID:   lead.ID,
+				Purpose:     purpose,
+				Granted:     true,
+				IP:          lead.ConsentIP,
+				UserAgent:   lead.ConsentUserAgent,
+				Origin:      origin,
+				ActorType:   models.ActorTypeSystem,
+			}
+			// Each purpose is recorded against its own text: the contact checkbox text, or the
+			// marketing/data sharing text shown by the form (published version, current one by default)
+			if purpose == models.ConsentPurposeContact {
+				input.Text = lead.ConsentText
+			} else {
+				input.TextVersion = lead.ConsentTextVersions[purpose]
+				input.Text = lead.ConsentPurposeTexts[purpose]
+			}
+			if _, err := s.consentService.RecordConsent(ctx, input); err != nil {
+				log.Printf("Failed to record %s consent of lead %s: %v", purpose, lead.ID, err)
+			}
+		}
+	}
+
+	// Count lead in the listing performance rollups (views → leads conversion)
+	if s.metricsService != nil {
+		_ = s.metricsService.RecordLead(ctx, lead.TenantID, lead.PropertyID)
+	}
+
+	// The same person (phone or e-mail) asking about several properties is one contact
+	if s.contacts != nil {
+		if err := s.contacts.AssignContact(ctx, lead); err != nil {
+			log.Printf("Failed to assign contact of lead %s: %v", lead.ID, err)
+		}
+	}
+
+	// Thread the first message into the inbox (WhatsApp messages are threaded by the inbound webhook)
+	if s.inbox != nil && lead.Message != "" && lead.Channel != models.LeadChannelWhatsApp {
+		from := lead.Email
+		if from == "" {
+			from = lead.Phone
+		}
+		if _, err := s.inbox.RecordMessage(ctx, lead, &models.ConversationMessage{
+			Channel:     lead.Channel,
+			Direction:   models.MessageDirectionInbound,
+			Body:        lead.Message,
+			From:        from,
+			ContactName: lead.Name,
+			SentAt:      lead.CreatedAt,
+		}); err != nil {
+			log.Printf("Failed to thread message of lead %s into the inbox: %v", lead.ID, err)
+		}
+	}
 
 	return nil
 }
 
+// SetListingMetricsService sets the listing metrics service (for dependency injection)
+func (s *LeadService) SetListingMetricsService(service *ListingMetricsService) {
+	s.metricsService = service
+}
+
+// SetOutboxRelay sets the transactional outbox (for dependency injection)
+func (s *LeadService) SetOutboxRelay(outbox *OutboxRelay) {
+	s.outbox = outbox
+}
+
+// SetConsentService sets the consent ledger service (for dependency injection)
+func (s *LeadService) SetConsentService(service *ConsentService) {
+	s.consentService = service
+}
+
+// SetDomainService sets the domain service that builds the canonical property URLs (for dependency injection)
+func (s *LeadService) SetDomainService(service *DomainService) {
+	s.domainService = service
+}
+
+// SetBrokerRepository sets the broker repository used to resolve the routed broker's WhatsApp number (for dependency injection)
+func (s *LeadService) SetBrokerRepository(brokerRepo *repositories.BrokerRepository) {
+	s.brokerRepo = brokerRepo
+}
+
+// SetWhatsAppLinkService sets the WhatsApp short link service (for dependency injection)
+func (s *LeadService) SetWhatsAppLinkService(service *WhatsAppLinkService) {
+	s.whatsAppLinks = service
+}
+
+// SetConversationRepository sets the conversation repository of lead messages (for dependency injection)
+func (s *LeadService) SetConversationRepository(conversations *repositories.ConversationRepository) {
+	s.conversations = conversations
+}
+
+// SetConversationService sets the conversation service of the unified inbox (for dependency injection)
+func (s *LeadService) SetConversationService(service *ConversationService) {
+	s.inbox = service
+}
+
+// SetContactService sets the contact service that deduplicates leads into contacts (for dependency injection)
+func (s *LeadService) SetContactService(service *ContactService) {
+	s.contacts = service
+}
+
+// normalizeLeadPhone validates and normalizes a lead phone: Brazilian numbers as (XX) XXXXX-XXXX,
+// foreign numbers (WhatsApp contacts from other countries) kept in E.164
+func normalizeLeadPhone(phone string) (string, error) {
+	if strings.HasPrefix(phone, "+") && !strings.HasPrefix(phone, "+55") {
+		phone = utils.NormalizePhoneE164(phone, "")
+		if err := utils.ValidatePhoneE164(phone); err != nil {
+			return "", fmt.Errorf("invalid phone: %w", err)
+		}
+		return phone, nil
+	}
+
+	if err := utils.ValidatePhoneBR(phone); err != nil {
+		return "", fmt.Errorf("invalid phone: %w", err)
+	}
+	return utils.NormalizePhoneBR(phone), nil
+}
+
+// withContactPurpose returns the valid purposes with contact included (deduplicated)
+func withContactPurpose(purposes []models.ConsentPurpose) []models.ConsentPurpose {
+	result := []models.ConsentPurpose{models.ConsentPurposeContact}
+	seen := map[models.ConsentPurpose]bool{models.ConsentPurposeContact: true}
+	for _, purpose := range purposes {
+		if models.IsValidConsentPurpose(purpose) && !seen[purpose] {
+			seen[purpose] = true
+			result = append(result, purpose)
+		}
+	}
+	return result
+}
+
 // GetLead retrieves a lead by ID
 func (s *LeadService) GetLead(ctx context.Context, tenantID, id string) (*models.Lead, error) {
 	if tenantID == "" {
@@ -175,10 +341,11 @@ func (s *LeadService) UpdateLead(ctx context.Context, tenantID, id string, updat
 
 	// Validate phone if being updated
 	if phone, ok := updates["phone"].(string); ok && phone != "" {
-		if err := utils.ValidatePhoneBR(phone); err != nil {
-			return fmt.Errorf("invalid phone: %w", err)
+		normalized, err := normalizeLeadPhone(phone)
+		if err != nil {
+			return err
 		}
-		updates["phone"] = utils.NormalizePhoneBR(phone)
+		updates["phone"] = normalized
 	}
 
 	// Validate status if being updated
@@ -188,10 +355,24 @@ func (s *LeadService) UpdateLead(ctx context.Context, tenantID, id string, updat
 		}
 	}
 
+	// Validate budget if being updated
+	if budget, ok := updates["budget"].(float64); ok && budget < 0 {
+		return fmt.Errorf("budget must not be negative")
+	}
+
+	// Score and contact are maintained by LeadScoringService and ContactService
+	delete(updates, "score")
+	delete(updates, "score_factors")
+	delete(updates, "scored_at")
+	delete(updates, "contact_id")
+
 	// Prevent updating LGPD fields directly
 	delete(updates, "consent_given")
 	delete(updates, "consent_date")
 	delete(updates, "consent_revoked")
+	delete(updates, "consent_purposes")
+	delete(updates, "consent_text_versions")
+	delete(updates, "consent_purpose_texts")
 	delete(updates, "revoked_at")
 	delete(updates, "is_anonymized")
 	delete(updates, "anonymized_at")
@@ -201,21 +382,48 @@ func (s *LeadService) UpdateLead(ctx context.Context, tenantID, id string, updat
 	delete(updates, "tenant_id")
 	delete(updates, "property_id")
 
-	// Update lead in repository
-	if err := s.leadRepo.Update(ctx, tenantID, id, updates); err != nil {
+	// Update lead and log activity in one transaction
+	write, err := s.leadRepo.UpdateTx(ctx, tenantID, id, updates)
+	if err != nil {
 		return fmt.Errorf("failed to update lead: %w", err)
 	}
-
-	// Log activity
-	_ = s.logActivity(ctx, tenantID, "lead_updated", models.ActorTypeSystem, "", map[string]interface{}{
+	event := models.NewOutboxEvent(tenantID, "lead_updated", "lead", id, models.ActorTypeSystem, "", map[string]interface{}{
 		"lead_id":     id,
 		"property_id": existing.PropertyID,
 		"updates":     updates,
 	})
+	if err := s.commit(ctx, event, write); err != nil {
+		return fmt.Errorf("failed to update lead: %w", err)
+	}
+
+	// The contact follows the lead's phone and e-mail (ex: WhatsApp placeholder replaced by the sender)
+	if s.contacts != nil {
+		if updated, changed := withContactUpdates(existing, updates); changed {
+			if err := s.contacts.ReassignContact(ctx, existing, updated); err != nil {
+				log.Printf("Failed to reassign contact of lead %s: %v", id, err)
+			}
+		}
+	}
 
 	return nil
 }
 
+// withContactUpdates returns a copy of the lead with the name, e-mail and phone of the updates and whether the
+// e-mail or the phone changed
+func withContactUpdates(lead *models.Lead, updates map[string]interface{}) (*models.Lead, bool) {
+	updated := *lead
+	if name, ok := updates["name"].(string); ok {
+		updated.Name = name
+	}
+	if email, ok := updates["email"].(string); ok {
+		updated.Email = email
+	}
+	if phone, ok := updates["phone"].(string); ok {
+		updated.Phone = phone
+	}
+	return &updated, updated.Email != lead.Email || updated.Phone != lead.Phone
+}
+
 // DeleteLead deletes a lead (should be rare - prefer anonymization)
 func (s *LeadService) DeleteLead(ctx context.Context, tenantID, id string) error {
 	if tenantID == "" {
@@ -231,16 +439,25 @@ func (s *LeadService) DeleteLead(ctx context.Context, tenantID, id string) error
 		return fmt.Errorf("lead not found: %w", err)
 	}
 
-	// Delete lead from repository
-	if err := s.leadRepo.Delete(ctx, tenantID, id); err != nil {
-		return fmt.Errorf("failed to delete lead: %w", err)
+	// The lead is no longer an interest of its contact
+	if s.contacts != nil {
+		if err := s.contacts.DetachLead(ctx, lead); err != nil {
+			return fmt.Errorf("failed to detach lead from contact: %w", err)
+		}
 	}
 
-	// Log activity
-	_ = s.logActivity(ctx, tenantID, "lead_deleted", models.ActorTypeSystem, "", map[string]interface{}{
+	// Delete lead and log activity in one transaction
+	write, err := s.leadRepo.DeleteTx(tenantID, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete lead: %w", err)
+	}
+	event := models.NewOutboxEvent(tenantID, "lead_deleted", "lead", id, models.ActorTypeSystem, "", map[string]interface{}{
 		"lead_id":     id,
 		"property_id": lead.PropertyID,
 	})
+	if err := s.commit(ctx, event, write); err != nil {
+		return fmt.Errorf("failed to delete lead: %w", err)
+	}
 
 	return nil
 }
@@ -276,6 +493,25 @@ func (s *LeadService) ListLeadsByProperty(ctx context.Context, tenantID, propert
 	return leads, nil
 }
 
+// ListLeadMessages retrieves the conversation history of a lead (chronological order)
+func (s *LeadService) ListLeadMessages(ctx context.Context, tenantID, leadID string, opts repositories.PaginationOptions) ([]*models.ConversationMessage, error) {
+	if s.conversations == nil {
+		return nil, fmt.Errorf("conversation history is not configured")
+	}
+
+	// Validate lead exists and not anonymized
+	if _, err := s.GetLead(ctx, tenantID, leadID); err != nil {
+		return nil, err
+	}
+
+	messages, err := s.conversations.ListMessages(ctx, tenantID, leadID, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list lead messages: %w", err)
+	}
+
+	return messages, nil
+}
+
 // UpdateStatus updates the status of a lead
 func (s *LeadService) UpdateStatus(ctx context.Context, tenantID, id string, status models.LeadStatus) error {
 	if tenantID == "" {
@@ -299,17 +535,20 @@ func (s *LeadService) UpdateStatus(ctx context.Context, tenantID, id string, sta
 		"status": status,
 	}
 
-	if err := s.leadRepo.Update(ctx, tenantID, id, updates); err != nil {
+	// Update status and log activity in one transaction
+	write, err := s.leadRepo.UpdateTx(ctx, tenantID, id, updates)
+	if err != nil {
 		return fmt.Errorf("failed to update lead status: %w", err)
 	}
-
-	// Log activity
-	_ = s.logActivity(ctx, tenantID, "lead_status_changed", models.ActorTypeSystem, "", map[string]interface{}{
+	event := models.NewOutboxEvent(tenantID, "lead_status_changed", "lead", id, models.ActorTypeSystem, "", map[string]interface{}{
 		"lead_id":     id,
 		"property_id": lead.PropertyID,
 		"old_status":  lead.Status,
 		"new_status":  status,
 	})
+	if err := s.commit(ctx, event, write); err != nil {
+		return fmt.Errorf("failed to update lead status: %w", err)
+	}
 
 	return nil
 }
@@ -336,12 +575,15 @@ func (s *LeadService) AssignToBroker(ctx context.Context, tenantID, leadID, brok
 	// This is for future implementation in MVP+1
 	// For now, we just log the assignment
 
-	// Log activity
-	_ = s.logActivity(ctx, tenantID, "lead_assigned_to_broker", models.ActorTypeSystem, "", map[string]interface{}{
+	// Publish the assignment event (no write yet)
+	event := models.NewOutboxEvent(tenantID, "lead_assigned_to_broker", "lead", leadID, models.ActorTypeSystem, "", map[string]interface{}{
 		"lead_id":     leadID,
 		"property_id": lead.PropertyID,
 		"broker_id":   brokerID,
 	})
+	if err := s.commit(ctx, event); err != nil {
+		return fmt.Errorf("failed to assign lead: %w", err)
+	}
 
 	return nil
 }
@@ -362,12 +604,17 @@ func (s *LeadService) RouteToAvailableBroker(ctx context.Context, tenantID, lead
 		return "", fmt.Errorf("lead not found: %w", err)
 	}
 
+	return s.routedBrokerID(ctx, tenantID, lead.PropertyID)
+}
+
+// routedBrokerID returns the broker that receives the leads of a property (primary broker, else originating broker)
+func (s *LeadService) routedBrokerID(ctx context.Context, tenantID, propertyID string) (string, error) {
 	// Get primary broker for the property
-	primaryRole, err := s.roleRepo.GetPrimaryBroker(ctx, tenantID, lead.PropertyID)
+	primaryRole, err := s.roleRepo.GetPrimaryBroker(ctx, tenantID, propertyID)
 	if err != nil {
 		// If no primary broker, try to get originating broker
 		if err == repositories.ErrNotFound {
-			originatingRole, err := s.roleRepo.GetOriginatingBroker(ctx, tenantID, lead.PropertyID)
+			originatingRole, err := s.roleRepo.GetOriginatingBroker(ctx, tenantID, propertyID)
 			if err != nil {
 				return "", fmt.Errorf("no broker available for routing: %w", err)
 			}
@@ -398,6 +645,19 @@ func (s *LeadService) RevokeConsent(ctx context.Context, tenantID, id string) er
 		return fmt.Errorf("failed to revoke consent: %w", err)
 	}
 
+	// LGPD: Revoking the lead consent withdraws every purpose in the ledger
+	if s.consentService != nil {
+		if err := s.consentService.WithdrawAll(ctx, RecordConsentInput{
+			TenantID:    tenantID,
+			SubjectType: models.ConsentSubjectLead,
+			SubjectID:   id,
+			Origin:      ConsentOriginAPI,
+			ActorType:   models.ActorTypeSystem,
+		}); err != nil {
+			return fmt.Errorf("failed to record consent withdrawal: %w", err)
+		}
+	}
+
 	// Log activity
 	_ = s.logActivity(ctx, tenantID, "lead_consent_revoked", models.ActorTypeSystem, id, map[string]interface{}{
 		"lead_id":     id,
@@ -438,6 +698,20 @@ func (s *LeadService) AnonymizeLead(ctx context.Context, tenantID, id, reason st
 		return fmt.Errorf("lead is already anonymized")
 	}
 
+	// LGPD: The conversation history holds the lead's messages and phone
+	if s.conversations != nil {
+		if err := s.conversations.DeleteLeadConversation(ctx, tenantID, id); err != nil {
+			return fmt.Errorf("failed to delete lead conversation: %w", err)
+		}
+	}
+
+	// LGPD: The contact keeps only the data of the person's other leads
+	if s.contacts != nil {
+		if err := s.contacts.DetachLead(ctx, lead); err != nil {
+			return fmt.Errorf("failed to detach lead from contact: %w", err)
+		}
+	}
+
 	if err := s.leadRepo.Anonymize(ctx, tenantID, id, reason); err != nil {
 		return fmt.Errorf("failed to anonymize lead: %w", err)
 	}
@@ -484,6 +758,22 @@ func (s *LeadService) validateStatus(status models.LeadStatus) error {
 	return nil
 }
 
+// commit applies the writes and their domain event atomically through the transactional outbox
+// Without an outbox (CLI tools, tests) the writes run in a transaction and the event is logged directly
+func (s *LeadService) commit(ctx context.Context, event *models.OutboxEvent, writes ...repositories.TxWrite) error {
+	if s.outbox != nil {
+		return s.outbox.Commit(ctx, []*models.OutboxEvent{event}, writes...)
+	}
+
+	if len(writes) > 0 {
+		if err := s.leadRepo.RunTransaction(ctx, writes...); err != nil {
+			return err
+		}
+	}
+	_ = appendActivityLog(ctx, s.activityLogRepo, event.ActivityLog())
+	return nil
+}
+
 // logActivity logs an activity (helper method)
 func (s *LeadService) logActivity(ctx context.Context, tenantID, eventType string, actorType models.ActorType, actorID string, metadata map[string]interface{}) error {
 	log := &models.ActivityLog{
@@ -495,7 +785,7 @@ func (s *LeadService) logActivity(ctx context.Context, tenantID, eventType strin
 		Timestamp: time.Now(),
 	}
 
-	return s.activityLogRepo.Create(ctx, log)
+	return appendActivityLog(ctx, s.activityLogRepo, log)
 }
 
 // ============================================================================
@@ -504,12 +794,16 @@ func (s *LeadService) logActivity(ctx context.Context, tenantID, eventType strin
 
 // WhatsAppData represents the data needed to redirect to WhatsApp
 type WhatsAppData struct {
-	URL     string
-	Message string
-	Phone   string
+	URL         string
+	ShortURL    string // click-tracking short link (empty when short links are disabled)
+	Message     string
+	Phone       string
+	PhoneSource models.WhatsAppPhoneSource
+	BrokerID    string
 }
 
-// GenerateWhatsAppURL generates a WhatsApp URL with pre-formatted message
+// GenerateWhatsAppURL generates a WhatsApp URL with the tenant's message template
+// The number is the routed broker's phone, falling back to the tenant branding/phone (see models.ResolveWhatsAppPhone)
 func (s *LeadService) GenerateWhatsAppURL(ctx context.Context, tenantID, propertyID, leadID string) (*WhatsAppData, error) {
 	if tenantID == "" {
 		return nil, fmt.Errorf("tenant_id is required")
@@ -521,95 +815,80 @@ func (s *LeadService) GenerateWhatsAppURL(ctx context.Context, tenantID, propert
 		return nil, fmt.Errorf("lead_id is required")
 	}
 
-	// Get property to extract broker phone and details
+	// Get property details for the message
 	property, err := s.propertyRepo.Get(ctx, tenantID, propertyID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get property: %w", err)
 	}
 
-	// Get tenant info for branding
+	// Get tenant info for branding, template and fallback phone
 	tenant, err := s.tenantRepo.Get(ctx, tenantID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get tenant: %w", err)
 	}
+	branding := tenant.ResolvedBranding()
 
-	// Try to get broker phone from captador or primary broker
-	var brokerPhone string
-
-	// First try: Get captador phone if captador_id is set
-	if property.CaptadorID != "" {
-		// In the future, when we have broker.Phone, we'll fetch it here
-		// For now, we'll use a default from tenant or property
-	}
-
-	// Fallback: Use tenant phone or default
-	// TODO: Get broker phone from broker model when available
-	// For MVP, we can use the tenant's contact phone or a default
-	brokerPhone = "5535998671079" // Test number - should come from broker or tenant
-
-	// Build property URL - using tenant slug and property slug
-	// Format: https://{tenant-slug}.site.com/imoveis/{property-slug}
-	// For now, using localhost or production domain if available
-	propertyURL := fmt.Sprintf("http://localhost:3001/imoveis/%s", property.Slug)
-	if property.Slug == "" {
-		propertyURL = fmt.Sprintf("http://localhost:3001/imoveis/%s", propertyID)
-	}
-
-	// Build pre-formatted message with property link
-	message := fmt.Sprintf(
-		"Olá! Tenho interesse no imóvel:\n\n"+
-			"📍 %s - %s, %s\n"+
-			"💰 R$ %.2f\n"+
-			"🏠 %s\n\n"+
-			"🔗 Link: %s\n\n"+
-			"Protocolo: #%s\n"+
-			"Via: %s",
-		property.Street,
-		property.Neighborhood,
-		property.City,
-		property.PriceAmount,
-		property.PropertyType,
-		propertyURL,
-		leadID,
-		tenant.Name,
-	)
-
-	// Build WhatsApp URL
-	whatsappURL := fmt.Sprintf(
-		"https://wa.me/%s?text=%s",
-		brokerPhone,
-		urlEncode(message),
-	)
-
-	return &WhatsAppData{
-		URL:     whatsappURL,
-		Message: message,
-		Phone:   brokerPhone,
-	}, nil
-}
-
-// urlEncode encodes a string for URL query parameters
-func urlEncode(s string) string {
-	// Simple URL encoding for WhatsApp
-	// In production, use url.QueryEscape from net/url
-	encoded := ""
-	for _, char := range s {
-		switch char {
-		case '\n':
-			encoded += "%0A"
-		case ' ':
-			encoded += "%20"
-		case '#':
-			encoded += "%23"
-		case '$':
-			encoded += "%24"
-		case ':':
-			encoded += "%3A"
-		case ',':
-			encoded += "%2C"
-		default:
-			encoded += string(char)
+	// Phone of the broker the lead is routed to (primary, else originating broker)
+	var broker *models.Broker
+	brokerID, err := s.routedBrokerID(ctx, tenantID, propertyID)
+	if err == nil && s.brokerRepo != nil {
+		broker, err = s.brokerRepo.Get(ctx, tenantID, brokerID)
+		if err != nil {
+			log.Printf("Failed to get routed broker %s for WhatsApp link: %v", brokerID, err)
 		}
 	}
-	return encoded
+	phone, phoneSource := models.ResolveWhatsAppPhone(broker, tenant)
+
+	// Build canonical property URL on the tenant site
+	// Format: https://{primary-domain or tenant-slug.platform-domain}/imoveis/{property-slug}
+	siteURL := tenant.SiteBaseURL("", "http://localhost:3001")
+	if s.domainService != nil {
+		siteURL = s.domainService.SiteBaseURL(tenant)
+	}
+	propertyURL := models.PropertyCanonicalURL(siteURL, property)
+
+	// Build message from the tenant template
+	message := models.RenderWhatsAppMessage(tenant.WhatsAppMessageTemplate(), models.WhatsAppMessageData{
+		Reference:    property.Reference,
+		Price:        formatBRL(property.PriceAmount),
+		Link:         propertyURL,
+		Protocol:     leadID,
+		Address:      fmt.Sprintf("%s - %s, %s", property.Street, property.Neighborhood, property.City),
+		PropertyType: string(property.PropertyType),
+		Sender:       branding.SenderName,
+	})
+
+	data := &WhatsAppData{
+		URL:         models.WhatsAppURL(phone, message),
+		Message:     message,
+		Phone:       phone,
+		PhoneSource: phoneSource,
+		BrokerID:    brokerID,
+	}
+
+	// Click-tracking short link (the direct URL still works when it cannot be created)
+	if s.whatsAppLinks != nil {
+		link := &models.WhatsAppLink{
+			TenantID:    tenantID,
+			LeadID:      leadID,
+			PropertyID:  propertyID,
+			BrokerID:    brokerID,
+			Channel:     models.LeadChannelWhatsApp,
+			TargetURL:   data.URL,
+			PhoneSource: phoneSource,
+		}
+		if lead, err := s.leadRepo.Get(ctx, tenantID, leadID); err == nil {
+			link.Channel = lead.Channel
+			link.UTMSource = lead.UTMSource
+		}
+
+		shortURL, err := s.whatsAppLinks.CreateLink(ctx, link)
+		if err != nil {
+			log.Printf("Failed to create WhatsApp short link for lead %s: %v", leadID, err)
+		} else {
+			data.ShortURL = shortURL
+		}
+	}
+
+	return data, nil
 }
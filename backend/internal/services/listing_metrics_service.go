@@ -0,0 +1,189 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/utils"
+)
+
+// metricsLocation is the timezone used to bucket daily rollups (Brasília time)
+var metricsLocation = loadMetricsLocation()
+
+func loadMetricsLocation() *time.Location {
+	if loc, err := time.LoadLocation("America/Sao_Paulo"); err == nil {
+		return loc
+	}
+	return time.FixedZone("BRT", -3*60*60)
+}
+
+// MetricsDate returns the rollup date (YYYY-MM-DD) of a timestamp
+func MetricsDate(t time.Time) string {
+	return t.In(metricsLocation).Format("2006-01-02")
+}
+
+// ParseMetricsDate parses a YYYY-MM-DD date as midnight in the rollup timezone
+// (so MetricsDate of the result is the same day)
+func ParseMetricsDate(value string) (time.Time, error) {
+	return time.ParseInLocation("2006-01-02", value, metricsLocation)
+}
+
+// ListingMetricsService handles view/impression/click tracking and listing performance
+type ListingMetricsService struct {
+	metricsRepo  *repositories.MetricsRepository
+	propertyRepo *repositories.PropertyRepository
+	listingRepo  *repositories.ListingRepository
+}
+
+// NewListingMetricsService creates a new listing metrics service
+func NewListingMetricsService(
+	metricsRepo *repositories.MetricsRepository,
+	propertyRepo *repositories.PropertyRepository,
+	listingRepo *repositories.ListingRepository,
+) *ListingMetricsService {
+	return &ListingMetricsService{
+		metricsRepo:  metricsRepo,
+		propertyRepo: propertyRepo,
+		listingRepo:  listingRepo,
+	}
+}
+
+// TrackEventInput represents an engagement event sent by a public frontend
+type TrackEventInput struct {
+	TenantID   string // optional: resolved from property when empty (portal agregador)
+	PropertyID string
+	ListingID  string // optional: defaults to the canonical listing
+	EventType  models.TrackingEventType
+	UserAgent  string
+}
+
+// ListingPerformance represents engagement and conversion for a property, listing or broker
+type ListingPerformance struct {
+	Scope    models.MetricScope `json:"scope"`
+	EntityID string             `json:"entity_id"`
+	From     string             `json:"from"`
+	To       string             `json:"to"`
+
+	Views          int64 `json:"views"`
+	Impressions    int64 `json:"impressions"`
+	WhatsAppClicks int64 `json:"whatsapp_clicks"`
+	Leads          int64 `json:"leads"`
+
+	ClickThroughRate float64 `json:"click_through_rate"` // views / impressions (%)
+	ConversionRate   float64 `json:"conversion_rate"`    // leads / views (%)
+
+	Daily []*models.DailyMetrics `json:"daily"`
+}
+
+// TrackEvent records an engagement event in the daily rollups.
+// Returns false (without error) when the event is ignored because it comes from a bot.
+func (s *ListingMetricsService) TrackEvent(ctx context.Context, input TrackEventInput) (bool, error) {
+	if input.PropertyID == "" {
+		return false, fmt.Errorf("property_id is required")
+	}
+
+	field := models.MetricsCounterField(input.EventType)
+	if field == "" {
+		return false, fmt.Errorf("invalid event_type: must be view, impression or whatsapp_click")
+	}
+
+	// Bot filtering: crawlers and link previews never count
+	if utils.IsBotUserAgent(input.UserAgent) {
+		return false, nil
+	}
+
+	property, err := s.propertyRepo.Get(ctx, input.TenantID, input.PropertyID)
+	if err != nil {
+		return false, fmt.Errorf("property not found: %w", err)
+	}
+
+	targets := s.resolveTargets(ctx, property, input.ListingID)
+	if err := s.metricsRepo.Increment(ctx, property.TenantID, MetricsDate(time.Now()), targets, field, 1); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// RecordLead increments the lead counter of the property, its canonical listing and broker
+func (s *ListingMetricsService) RecordLead(ctx context.Context, tenantID, propertyID string) error {
+	property, err := s.propertyRepo.Get(ctx, tenantID, propertyID)
+	if err != nil {
+		return fmt.Errorf("property not found: %w", err)
+	}
+
+	targets := s.resolveTargets(ctx, property, "")
+	return s.metricsRepo.Increment(ctx, tenantID, MetricsDate(time.Now()), targets, "leads", 1)
+}
+
+// GetPerformance aggregates the daily rollups of an entity between two dates
+func (s *ListingMetricsService) GetPerformance(ctx context.Context, tenantID string, scope models.MetricScope, entityID string, from, to time.Time) (*ListingPerformance, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+	if entityID == "" {
+		return nil, fmt.Errorf("entity ID is required")
+	}
+	if to.Before(from) {
+		return nil, fmt.Errorf("invalid date range: 'to' is before 'from'")
+	}
+
+	fromDate, toDate := MetricsDate(from), MetricsDate(to)
+	daily, err := s.metricsRepo.ListRange(ctx, tenantID, scope, entityID, fromDate, toDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily metrics: %w", err)
+	}
+
+	performance := &ListingPerformance{
+		Scope:    scope,
+		EntityID: entityID,
+		From:     fromDate,
+		To:       toDate,
+		Daily:    daily,
+	}
+	for _, day := range daily {
+		performance.Views += day.Views
+		performance.Impressions += day.Impressions
+		performance.WhatsAppClicks += day.WhatsAppClicks
+		performance.Leads += day.Leads
+	}
+
+	if performance.Impressions > 0 {
+		performance.ClickThroughRate = roundTo(float64(performance.Views)/float64(performance.Impressions)*100, 2)
+	}
+	if performance.Views > 0 {
+		performance.ConversionRate = roundTo(float64(performance.Leads)/float64(performance.Views)*100, 2)
+	}
+
+	return performance, nil
+}
+
+// resolveTargets returns the property, listing and broker rollups affected by an event
+func (s *ListingMetricsService) resolveTargets(ctx context.Context, property *models.Property, listingID string) []repositories.MetricTarget {
+	targets := []repositories.MetricTarget{
+		{Scope: models.MetricScopeProperty, EntityID: property.ID},
+	}
+
+	if listingID == "" {
+		listingID = property.CanonicalListingID
+	}
+
+	brokerID := property.CaptadorID
+	if listingID != "" {
+		if listing, err := s.listingRepo.Get(ctx, property.TenantID, listingID); err == nil && listing.PropertyID == property.ID {
+			targets = append(targets, repositories.MetricTarget{Scope: models.MetricScopeListing, EntityID: listing.ID})
+			if listing.BrokerID != "" {
+				brokerID = listing.BrokerID
+			}
+		}
+	}
+
+	if brokerID != "" {
+		targets = append(targets, repositories.MetricTarget{Scope: models.MetricScopeBroker, EntityID: brokerID})
+	}
+
+	return targets
+}
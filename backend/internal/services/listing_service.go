@@ -16,6 +16,7 @@ type ListingService struct {
 	brokerRepo      *repositories.BrokerRepository
 	tenantRepo      *repositories.TenantRepository
 	activityLogRepo *repositories.ActivityLogRepository
+	planService     *PlanService // Optional: subscription plan quotas
 }
 
 // NewListingService creates a new listing service
@@ -35,6 +36,11 @@ func NewListingService(
 	}
 }
 
+// SetPlanService sets the plan service (for dependency injection)
+func (s *ListingService) SetPlanService(service *PlanService) {
+	s.planService = service
+}
+
 // CreateListing creates a new listing with validation and canonical logic
 func (s *ListingService) CreateListing(ctx context.Context, listing *models.Listing) error {
 	// Validate required fields
@@ -70,6 +76,13 @@ func (s *ListingService) CreateListing(ctx context.Context, listing *models.List
 		return fmt.Errorf("broker not found: %w", err)
 	}
 
+	// Check the photos-per-listing quota of the tenant's plan
+	if s.planService != nil {
+		if err := s.planService.CheckPhotoQuota(ctx, listing.TenantID, len(listing.Photos)); err != nil {
+			return err
+		}
+	}
+
 	// Set defaults
 	listing.IsActive = true
 
@@ -186,6 +199,13 @@ func (s *ListingService) UpdateListing(ctx context.Context, tenantID, id string,
 	// Prevent directly updating is_canonical (use SetCanonical instead)
 	delete(updates, "is_canonical")
 
+	// Check the photos-per-listing quota of the tenant's plan
+	if photos, ok := updates["photos"].([]interface{}); ok && s.planService != nil {
+		if err := s.planService.CheckPhotoQuota(ctx, tenantID, len(photos)); err != nil {
+			return err
+		}
+	}
+
 	// Update listing in repository
 	if err := s.listingRepo.Update(ctx, tenantID, id, updates); err != nil {
 		return fmt.Errorf("failed to update listing: %w", err)
@@ -487,5 +507,5 @@ func (s *ListingService) logActivity(ctx context.Context, tenantID, eventType st
 		Timestamp: time.Now(),
 	}
 
-	return s.activityLogRepo.Create(ctx, log)
+	return appendActivityLog(ctx, s.activityLogRepo, log)
 }
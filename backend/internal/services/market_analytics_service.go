@@ -0,0 +1,438 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// minTenantsPerMarketplaceSegment is the k-anonymity threshold for marketplace segments:
+// segments with fewer distinct tenants are dropped so no agency can be singled out
+const minTenantsPerMarketplaceSegment = 3
+
+// statusHistoryEvents are the activity log events that record property status changes
+var statusHistoryEvents = []string{"property_status_changed", "owner_confirmed_status"}
+
+// MarketAnalyticsService computes and serves periodic market snapshots
+// (price/m², inventory absorption, days on market and rent yield by neighborhood)
+type MarketAnalyticsService struct {
+	snapshotRepo    *repositories.MarketSnapshotRepository
+	propertyRepo    *repositories.PropertyRepository
+	tenantRepo      *repositories.TenantRepository
+	activityLogRepo *repositories.ActivityLogRepository
+}
+
+// NewMarketAnalyticsService creates a new market analytics service
+func NewMarketAnalyticsService(
+	snapshotRepo *repositories.MarketSnapshotRepository,
+	propertyRepo *repositories.PropertyRepository,
+	tenantRepo *repositories.TenantRepository,
+	activityLogRepo *repositories.ActivityLogRepository,
+) *MarketAnalyticsService {
+	return &MarketAnalyticsService{
+		snapshotRepo:    snapshotRepo,
+		propertyRepo:    propertyRepo,
+		tenantRepo:      tenantRepo,
+		activityLogRepo: activityLogRepo,
+	}
+}
+
+// segmentKey identifies a neighborhood + property type segment
+type segmentKey struct {
+	City         string
+	State        string
+	Neighborhood string
+	PropertyType models.PropertyType
+}
+
+// segmentAccumulator keeps the raw samples of a segment so tenant accumulators
+// can be merged into the marketplace snapshot before computing medians
+type segmentAccumulator struct {
+	properties  int
+	inventory   int
+	absorbed    int
+	pricesPerM2 []float64
+	prices      []float64
+	daysOnMkt   []float64
+	rentYields  []float64
+	tenants     map[string]bool
+}
+
+// ParseSnapshotPeriod parses a "YYYY-MM" period; empty means the previous (closed) month
+func ParseSnapshotPeriod(period string) (time.Time, time.Time, string, error) {
+	var start time.Time
+	if period == "" {
+		now := time.Now()
+		start = time.Date(now.Year(), now.Month()-1, 1, 0, 0, 0, 0, time.UTC)
+	} else {
+		parsed, err := time.Parse("2006-01", period)
+		if err != nil {
+			return time.Time{}, time.Time{}, "", fmt.Errorf("invalid period format, expected YYYY-MM")
+		}
+		start = parsed
+	}
+
+	end := start.AddDate(0, 1, 0)
+	return start, end, start.Format("2006-01"), nil
+}
+
+// GenerateTenantSnapshot computes and stores the snapshot of a tenant for the given period
+// This should be run by a periodic job (e.g., on the 1st of each month)
+func (s *MarketAnalyticsService) GenerateTenantSnapshot(ctx context.Context, tenantID, period string) (*models.MarketSnapshot, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+
+	start, end, period, err := ParseSnapshotPeriod(period)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.tenantRepo.Get(ctx, tenantID); err != nil {
+		return nil, fmt.Errorf("tenant not found: %w", err)
+	}
+
+	segments, err := s.accumulateTenant(ctx, tenantID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := buildSnapshot(models.MarketSnapshotScopeTenant, tenantID, period, start, end, segments, 0)
+	if err := s.snapshotRepo.Save(ctx, snapshot); err != nil {
+		return nil, err
+	}
+
+	log.Printf("📊 Market snapshot %s generated for tenant %s: %d segments", period, tenantID, len(snapshot.Segments))
+	return snapshot, nil
+}
+
+// GenerateMarketplaceSnapshot computes the anonymized cross-tenant snapshot for the given period.
+// Tenant snapshots are generated along the way so a single job run refreshes everything.
+func (s *MarketAnalyticsService) GenerateMarketplaceSnapshot(ctx context.Context, period string) (*models.MarketSnapshot, error) {
+	start, end, period, err := ParseSnapshotPeriod(period)
+	if err != nil {
+		return nil, err
+	}
+
+	tenants, err := s.tenantRepo.ListActive(ctx, repositories.PaginationOptions{Limit: 10000})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tenants: %w", err)
+	}
+
+	merged := make(map[segmentKey]*segmentAccumulator)
+	for _, tenant := range tenants {
+		segments, err := s.accumulateTenant(ctx, tenant.ID, start, end)
+		if err != nil {
+			log.Printf("⚠️  Skipping tenant %s in market snapshot: %v", tenant.ID, err)
+			continue
+		}
+
+		tenantSnapshot := buildSnapshot(models.MarketSnapshotScopeTenant, tenant.ID, period, start, end, segments, 0)
+		if err := s.snapshotRepo.Save(ctx, tenantSnapshot); err != nil {
+			log.Printf("⚠️  Failed to save market snapshot for tenant %s: %v", tenant.ID, err)
+		}
+
+		for key, acc := range segments {
+			target, ok := merged[key]
+			if !ok {
+				target = &segmentAccumulator{tenants: make(map[string]bool)}
+				merged[key] = target
+			}
+			target.merge(acc)
+		}
+	}
+
+	snapshot := buildSnapshot(models.MarketSnapshotScopeMarketplace, "", period, start, end, merged, minTenantsPerMarketplaceSegment)
+	if err := s.snapshotRepo.Save(ctx, snapshot); err != nil {
+		return nil, err
+	}
+
+	log.Printf("📊 Marketplace snapshot %s generated: %d tenants, %d segments", period, len(tenants), len(snapshot.Segments))
+	return snapshot, nil
+}
+
+// GetTenantSnapshot returns a stored tenant snapshot (latest when period is empty)
+func (s *MarketAnalyticsService) GetTenantSnapshot(ctx context.Context, tenantID, period string) (*models.MarketSnapshot, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+
+	if period == "" {
+		return s.snapshotRepo.GetLatest(ctx, tenantID)
+	}
+	return s.snapshotRepo.Get(ctx, tenantID, period)
+}
+
+// GetMarketplaceSnapshot returns a stored marketplace snapshot (latest when period is empty)
+func (s *MarketAnalyticsService) GetMarketplaceSnapshot(ctx context.Context, period string) (*models.MarketSnapshot, error) {
+	if period == "" {
+		return s.snapshotRepo.GetLatestMarketplace(ctx)
+	}
+	return s.snapshotRepo.GetMarketplace(ctx, period)
+}
+
+// WriteSnapshotCSV exports the segments of a snapshot as CSV
+func WriteSnapshotCSV(w io.Writer, snapshot *models.MarketSnapshot) error {
+	writer := csv.NewWriter(w)
+
+	header := []string{
+		"period", "city", "state", "neighborhood", "property_type",
+		"inventory_count", "absorbed_count", "absorption_rate",
+		"price_sample_size", "median_price", "median_price_per_m2",
+		"avg_days_on_market", "rent_yield_sample_size", "median_rent_yield",
+	}
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	for _, seg := range snapshot.Segments {
+		row := []string{
+			snapshot.Period,
+			seg.City,
+			seg.State,
+			seg.Neighborhood,
+			string(seg.PropertyType),
+			strconv.Itoa(seg.InventoryCount),
+			strconv.Itoa(seg.AbsorbedCount),
+			formatDecimal(seg.AbsorptionRate),
+			strconv.Itoa(seg.PriceSampleSize),
+			formatDecimal(seg.MedianPrice),
+			formatDecimal(seg.MedianPricePerM2),
+			formatDecimal(seg.AvgDaysOnMarket),
+			strconv.Itoa(seg.RentYieldSampleSize),
+			formatDecimal(seg.MedianRentYield),
+		}
+		if err := writer.Write(row); err != nil {
+			return fmt.Errorf("failed to write CSV row: %w", err)
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// accumulateTenant collects the raw samples of every segment of a tenant for the period
+func (s *MarketAnalyticsService) accumulateTenant(ctx context.Context, tenantID string, start, end time.Time) (map[segmentKey]*segmentAccumulator, error) {
+	properties, err := s.propertyRepo.List(ctx, tenantID, nil, repositories.PaginationOptions{
+		Limit: 10000, // High limit to get all properties
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list properties: %w", err)
+	}
+
+	exits, err := s.loadStatusExits(ctx, tenantID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	segments := make(map[segmentKey]*segmentAccumulator)
+	for _, p := range properties {
+		if !p.CreatedAt.IsZero() && !p.CreatedAt.Before(end) {
+			continue // Created after the period
+		}
+
+		key := segmentKey{
+			City:         strings.TrimSpace(p.City),
+			State:        strings.ToUpper(strings.TrimSpace(p.State)),
+			Neighborhood: strings.TrimSpace(p.Neighborhood),
+			PropertyType: p.PropertyType,
+		}
+		acc, ok := segments[key]
+		if !ok {
+			acc = &segmentAccumulator{tenants: map[string]bool{tenantID: true}}
+			segments[key] = acc
+		}
+		acc.properties++
+
+		exitedAt, absorbed := exits[p.ID]
+		switch {
+		case absorbed && p.Status != models.PropertyStatusAvailable:
+			acc.absorbed++
+			if !p.CreatedAt.IsZero() {
+				acc.daysOnMkt = append(acc.daysOnMkt, exitedAt.Sub(p.CreatedAt).Hours()/24)
+			}
+		case p.Status == models.PropertyStatusAvailable:
+			acc.inventory++
+			if !p.CreatedAt.IsZero() {
+				acc.daysOnMkt = append(acc.daysOnMkt, end.Sub(p.CreatedAt).Hours()/24)
+			}
+		default:
+			continue // Off market before the period: not part of price indicators
+		}
+
+		area := propertyArea(p)
+		if isForSale(p) && p.PriceAmount > 0 {
+			acc.prices = append(acc.prices, p.PriceAmount)
+			if area > 0 {
+				acc.pricesPerM2 = append(acc.pricesPerM2, p.PriceAmount/area)
+			}
+		}
+		if isForSale(p) && p.PriceAmount > 0 && p.RentalInfo != nil && p.RentalInfo.MonthlyRent > 0 {
+			acc.rentYields = append(acc.rentYields, p.RentalInfo.MonthlyRent*12/p.PriceAmount*100)
+		}
+	}
+
+	return segments, nil
+}
+
+// loadStatusExits returns, per property, the last time it became unavailable within the period
+func (s *MarketAnalyticsService) loadStatusExits(ctx context.Context, tenantID string, start, end time.Time) (map[string]time.Time, error) {
+	exits := make(map[string]time.Time)
+
+	for _, eventType := range statusHistoryEvents {
+		filters := &repositories.ActivityLogFilters{
+			EventType: eventType,
+			StartDate: &start,
+			EndDate:   &end,
+		}
+		logs, err := s.activityLogRepo.List(ctx, tenantID, filters, repositories.PaginationOptions{
+			Limit:   10000,
+			OrderBy: "timestamp",
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to load status history: %w", err)
+		}
+
+		for _, entry := range logs {
+			propertyID, _ := entry.Metadata["property_id"].(string)
+			if propertyID == "" {
+				continue
+			}
+			if fmt.Sprint(entry.Metadata["status"]) != string(models.PropertyStatusUnavailable) {
+				continue
+			}
+			if last, ok := exits[propertyID]; !ok || entry.Timestamp.After(last) {
+				exits[propertyID] = entry.Timestamp
+			}
+		}
+	}
+
+	return exits, nil
+}
+
+// merge adds the samples of another accumulator
+func (a *segmentAccumulator) merge(other *segmentAccumulator) {
+	a.properties += other.properties
+	a.inventory += other.inventory
+	a.absorbed += other.absorbed
+	a.pricesPerM2 = append(a.pricesPerM2, other.pricesPerM2...)
+	a.prices = append(a.prices, other.prices...)
+	a.daysOnMkt = append(a.daysOnMkt, other.daysOnMkt...)
+	a.rentYields = append(a.rentYields, other.rentYields...)
+	for tenantID := range other.tenants {
+		a.tenants[tenantID] = true
+	}
+}
+
+// buildSnapshot turns accumulators into a snapshot; minTenants > 0 enables k-anonymity filtering
+// Suppressed segments are left out of the totals too, otherwise they could be recovered by
+// subtracting the published segments from the totals
+func buildSnapshot(scope models.MarketSnapshotScope, tenantID, period string, start, end time.Time, segments map[segmentKey]*segmentAccumulator, minTenants int) *models.MarketSnapshot {
+	snapshot := &models.MarketSnapshot{
+		TenantID:    tenantID,
+		Scope:       scope,
+		Period:      period,
+		PeriodStart: start,
+		PeriodEnd:   end,
+		Segments:    []models.MarketSegmentStats{},
+		GeneratedAt: time.Now(),
+	}
+
+	for key, acc := range segments {
+		if minTenants > 0 && len(acc.tenants) < minTenants {
+			continue
+		}
+		snapshot.TotalProperties += acc.properties
+		snapshot.TotalInventory += acc.inventory
+		if acc.inventory+acc.absorbed == 0 {
+			continue
+		}
+
+		stats := models.MarketSegmentStats{
+			City:                key.City,
+			State:               key.State,
+			Neighborhood:        key.Neighborhood,
+			PropertyType:        key.PropertyType,
+			InventoryCount:      acc.inventory,
+			AbsorbedCount:       acc.absorbed,
+			AbsorptionRate:      roundTo(float64(acc.absorbed)/float64(acc.inventory+acc.absorbed)*100, 2),
+			PriceSampleSize:     len(acc.pricesPerM2),
+			MedianPricePerM2:    roundTo(median(acc.pricesPerM2), 2),
+			MedianPrice:         roundTo(median(acc.prices), 2),
+			AvgDaysOnMarket:     roundTo(mean(acc.daysOnMkt), 1),
+			RentYieldSampleSize: len(acc.rentYields),
+			MedianRentYield:     roundTo(median(acc.rentYields), 2),
+		}
+		if scope == models.MarketSnapshotScopeMarketplace {
+			stats.TenantCount = len(acc.tenants)
+		}
+		snapshot.Segments = append(snapshot.Segments, stats)
+	}
+
+	sort.Slice(snapshot.Segments, func(i, j int) bool {
+		a, b := snapshot.Segments[i], snapshot.Segments[j]
+		if a.City != b.City {
+			return a.City < b.City
+		}
+		if a.Neighborhood != b.Neighborhood {
+			return a.Neighborhood < b.Neighborhood
+		}
+		return a.PropertyType < b.PropertyType
+	})
+
+	return snapshot
+}
+
+// isForSale checks whether the property has a sale price (sale or sale+rent)
+func isForSale(p *models.Property) bool {
+	return p.TransactionType == nil || *p.TransactionType != models.TransactionTypeRent
+}
+
+// median returns the median of the values (0 for empty input)
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+// mean returns the arithmetic mean of the values (0 for empty input)
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// roundTo rounds a value to the given number of decimal places
+func roundTo(value float64, places int) float64 {
+	parsed, _ := strconv.ParseFloat(strconv.FormatFloat(value, 'f', places, 64), 64)
+	return parsed
+}
+
+// formatDecimal formats a float for CSV output
+func formatDecimal(value float64) string {
+	return strconv.FormatFloat(value, 'f', 2, 64)
+}
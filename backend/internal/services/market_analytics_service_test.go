@@ -0,0 +1,122 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+)
+
+func TestMedian(t *testing.T) {
+	assert.Equal(t, 0.0, median(nil))
+	assert.Equal(t, 5.0, median([]float64{5}))
+	assert.Equal(t, 3.0, median([]float64{9, 1, 3}))
+	assert.Equal(t, 2.5, median([]float64{4, 1, 3, 2}))
+
+	// Input is not reordered
+	values := []float64{3, 1, 2}
+	median(values)
+	assert.Equal(t, []float64{3, 1, 2}, values)
+}
+
+func TestMean(t *testing.T) {
+	assert.Equal(t, 0.0, mean(nil))
+	assert.Equal(t, 2.0, mean([]float64{1, 2, 3}))
+}
+
+func marketTestAccumulator(properties, inventory, absorbed int, tenants ...string) *segmentAccumulator {
+	acc := &segmentAccumulator{
+		properties: properties,
+		inventory:  inventory,
+		absorbed:   absorbed,
+		tenants:    make(map[string]bool),
+	}
+	for _, tenantID := range tenants {
+		acc.tenants[tenantID] = true
+	}
+	return acc
+}
+
+func TestBuildSnapshotSuppressesSmallMarketplaceSegments(t *testing.T) {
+	start := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+
+	public := segmentKey{City: "Curitiba", State: "PR", Neighborhood: "Batel", PropertyType: models.PropertyTypeApartment}
+	small := segmentKey{City: "Curitiba", State: "PR", Neighborhood: "Mercês", PropertyType: models.PropertyTypeHouse}
+
+	publicAcc := marketTestAccumulator(12, 8, 2, "t1", "t2", "t3")
+	publicAcc.prices = []float64{500000, 700000, 600000}
+	publicAcc.pricesPerM2 = []float64{10000, 14000, 12000}
+	segments := map[segmentKey]*segmentAccumulator{
+		public: publicAcc,
+		small:  marketTestAccumulator(5, 4, 1, "t1", "t2"),
+	}
+
+	snapshot := buildSnapshot(models.MarketSnapshotScopeMarketplace, "", "2026-09", start, end, segments, minTenantsPerMarketplaceSegment)
+
+	require.Len(t, snapshot.Segments, 1)
+	seg := snapshot.Segments[0]
+	assert.Equal(t, "Batel", seg.Neighborhood)
+	assert.Equal(t, 3, seg.TenantCount)
+	assert.Equal(t, 20.0, seg.AbsorptionRate)
+	assert.Equal(t, 600000.0, seg.MedianPrice)
+	assert.Equal(t, 12000.0, seg.MedianPricePerM2)
+
+	// Suppressed segments must not be recoverable from the totals
+	assert.Equal(t, 12, snapshot.TotalProperties)
+	assert.Equal(t, 8, snapshot.TotalInventory)
+}
+
+func TestBuildSnapshotTenantScopeKeepsAllSegments(t *testing.T) {
+	start := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+
+	segments := map[segmentKey]*segmentAccumulator{
+		{City: "Curitiba", Neighborhood: "Batel"}:      marketTestAccumulator(3, 2, 1, "t1"),
+		{City: "Curitiba", Neighborhood: "Água Verde"}: marketTestAccumulator(2, 1, 0, "t1"),
+		{City: "Curitiba", Neighborhood: "Centro"}:     marketTestAccumulator(1, 0, 0, "t1"), // off market only
+	}
+
+	snapshot := buildSnapshot(models.MarketSnapshotScopeTenant, "t1", "2026-09", start, start.AddDate(0, 1, 0), segments, 0)
+
+	require.Len(t, snapshot.Segments, 2)
+	assert.Equal(t, "Batel", snapshot.Segments[0].Neighborhood)
+	assert.Equal(t, "Água Verde", snapshot.Segments[1].Neighborhood)
+	assert.Zero(t, snapshot.Segments[0].TenantCount)
+	assert.Equal(t, 6, snapshot.TotalProperties)
+	assert.Equal(t, 3, snapshot.TotalInventory)
+}
+
+func TestWriteSnapshotCSV(t *testing.T) {
+	snapshot := &models.MarketSnapshot{
+		Period: "2026-09",
+		Segments: []models.MarketSegmentStats{{
+			City:             "Curitiba",
+			State:            "PR",
+			Neighborhood:     "Batel",
+			PropertyType:     models.PropertyTypeApartment,
+			InventoryCount:   8,
+			AbsorbedCount:    2,
+			AbsorptionRate:   20,
+			PriceSampleSize:  3,
+			MedianPrice:      600000,
+			MedianPricePerM2: 12000.5,
+		}},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteSnapshotCSV(&buf, snapshot))
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, "period", rows[0][0])
+	assert.Equal(t, []string{
+		"2026-09", "Curitiba", "PR", "Batel", string(models.PropertyTypeApartment),
+		"8", "2", "20.00", "3", "600000.00", "12000.50", "0.00", "0", "0.00",
+	}, rows[1])
+}
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+const (
+	// maxPriceDropAlertLeads bounds the leads alerted per price drop
+	maxPriceDropAlertLeads = 500
+)
+
+// MarketingAlertService sends marketing alerts (price drops) to interested leads
+// LGPD: every alert requires an active marketing consent in the ledger (ConsentService.EnsureMarketingConsent)
+type MarketingAlertService struct {
+	leadRepo        *repositories.LeadRepository
+	propertyRepo    *repositories.PropertyRepository
+	tenantRepo      *repositories.TenantRepository
+	activityLogRepo *repositories.ActivityLogRepository
+	consentService  *ConsentService
+	emailService    *EmailService
+	usageService    *UsageService  // Optional - usage metering (billing)
+	domainService   *DomainService // Optional - canonical property URLs (tenant domains)
+	publicSiteURL   string         // Public site URL for property links (tenants without domain)
+}
+
+// NewMarketingAlertService creates a new marketing alert service
+func NewMarketingAlertService(
+	leadRepo *repositories.LeadRepository,
+	propertyRepo *repositories.PropertyRepository,
+	tenantRepo *repositories.TenantRepository,
+	activityLogRepo *repositories.ActivityLogRepository,
+	consentService *ConsentService,
+	emailService *EmailService,
+) *MarketingAlertService {
+	return &MarketingAlertService{
+		leadRepo:        leadRepo,
+		propertyRepo:    propertyRepo,
+		tenantRepo:      tenantRepo,
+		activityLogRepo: activityLogRepo,
+		consentService:  consentService,
+		emailService:    emailService,
+		publicSiteURL:   getEnv("PUBLIC_SITE_URL", "http://localhost:3001"),
+	}
+}
+
+// SetUsageService sets the usage service that meters the messages sent (for dependency injection)
+func (s *MarketingAlertService) SetUsageService(usageService *UsageService) {
+	s.usageService = usageService
+}
+
+// SetDomainService sets the domain service that builds the canonical property URLs (for dependency injection)
+func (s *MarketingAlertService) SetDomainService(domainService *DomainService) {
+	s.domainService = domainService
+}
+
+// MarketingAlertResult summarizes an alert dispatch
+type MarketingAlertResult struct {
+	Sent                int `json:"sent"`
+	SkippedNoConsent    int `json:"skipped_no_consent"`
+	SkippedUnreachable  int `json:"skipped_unreachable"` // anonimizado, consentimento revogado ou sem e-mail
+	Failed              int `json:"failed"`
+	DuplicatesCollapsed int `json:"duplicates_collapsed"` // mesmo e-mail em vários leads do imóvel
+}
+
+// SendPriceDropAlerts emails the leads of a property about a price drop (only leads with marketing consent)
+func (s *MarketingAlertService) SendPriceDropAlerts(ctx context.Context, tenantID, propertyID string, oldPrice, newPrice float64) (*MarketingAlertResult, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+	if newPrice <= 0 || newPrice >= oldPrice {
+		return nil, fmt.Errorf("new price must be lower than the old price")
+	}
+
+	tenant, err := s.tenantRepo.Get(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("tenant not found: %w", err)
+	}
+
+	property, err := s.propertyRepo.Get(ctx, tenantID, propertyID)
+	if err != nil {
+		return nil, fmt.Errorf("property not found: %w", err)
+	}
+
+	leads, err := s.leadRepo.ListByProperty(ctx, tenantID, propertyID, repositories.PaginationOptions{Limit: maxPriceDropAlertLeads})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list leads: %w", err)
+	}
+
+	propertyLabel := property.Reference
+	if propertyLabel == "" {
+		propertyLabel = fmt.Sprintf("%s - %s", property.Neighborhood, property.City)
+	}
+	siteURL := tenant.SiteBaseURL("", s.publicSiteURL)
+	if s.domainService != nil {
+		siteURL = s.domainService.SiteBaseURL(tenant)
+	}
+	propertyURL := models.PropertyCanonicalURL(siteURL, property)
+	branding := tenant.ResolvedBranding()
+
+	result := &MarketingAlertResult{}
+	alerted := make(map[string]bool)
+	for _, lead := range leads {
+		if lead.IsAnonymized || lead.ConsentRevoked || lead.Email == "" {
+			result.SkippedUnreachable++
+			continue
+		}
+		if alerted[lead.Email] {
+			result.DuplicatesCollapsed++
+			continue
+		}
+
+		if err := s.consentService.EnsureMarketingConsent(ctx, tenantID, models.ConsentSubjectLead, lead.ID); err != nil {
+			if err != ErrNoMarketingConsent {
+				log.Printf("⚠️  Failed to check marketing consent for lead %s: %v", lead.ID, err)
+				result.Failed++
+				continue
+			}
+			result.SkippedNoConsent++
+			continue
+		}
+
+		if err := s.emailService.SendPriceDropAlert(lead.Email, lead.Name, branding, propertyLabel, formatBRL(oldPrice), formatBRL(newPrice), propertyURL); err != nil {
+			result.Failed++
+			continue
+		}
+		alerted[lead.Email] = true
+		result.Sent++
+	}
+
+	_ = s.logActivity(ctx, tenantID, "price_drop_alerts_sent", map[string]interface{}{
+		"property_id":          propertyID,
+		"old_price":            oldPrice,
+		"new_price":            newPrice,
+		"sent":                 result.Sent,
+		"skipped_no_consent":   result.SkippedNoConsent,
+		"skipped_unreachable":  result.SkippedUnreachable,
+		"failed":               result.Failed,
+		"duplicates_collapsed": result.DuplicatesCollapsed,
+	})
+
+	if s.usageService != nil {
+		s.usageService.Record(ctx, tenantID, models.UsageMetricMessagesSent, int64(result.Sent))
+	}
+
+	return result, nil
+}
+
+// logActivity logs an activity
+func (s *MarketingAlertService) logActivity(ctx context.Context, tenantID, eventType string, metadata map[string]interface{}) error {
+	log := &models.ActivityLog{
+		TenantID:  tenantID,
+		EventType: eventType,
+		ActorType: models.ActorTypeSystem,
+		Metadata:  metadata,
+		Timestamp: time.Now(),
+	}
+
+	return appendActivityLog(ctx, s.activityLogRepo, log)
+}
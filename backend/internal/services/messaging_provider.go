@@ -0,0 +1,174 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+)
+
+const (
+	// whatsAppCloudRequestTimeout bounds each call to the WhatsApp Cloud API
+	whatsAppCloudRequestTimeout = 10 * time.Second
+
+	// whatsAppCloudErrorMaxSize limits the error response kept from the WhatsApp Cloud API
+	whatsAppCloudErrorMaxSize = 4 << 10
+)
+
+// ErrMessagingNotConfigured is returned when the provider is not configured for the tenant or the lead
+// has no address on the channel
+var ErrMessagingNotConfigured = errors.New("messaging provider is not configured")
+
+// OutboundMessage is a reply sent to a lead through a messaging provider
+type OutboundMessage struct {
+	Tenant  *models.Tenant
+	Lead    *models.Lead
+	Subject string // e-mail
+	Body    string
+}
+
+// MessagingProvider delivers replies to leads on a channel
+// Implemented by EmailMessagingProvider (SMTP) and WhatsAppCloudMessagingProvider; other providers plug in here
+type MessagingProvider interface {
+	Name() string
+	Channel() models.LeadChannel
+	// Send delivers the message and returns the provider message ID (empty when the provider has none)
+	Send(ctx context.Context, message OutboundMessage) (string, error)
+}
+
+// EmailMessagingProvider sends replies by e-mail with the tenant branding
+type EmailMessagingProvider struct {
+	emailService *EmailService
+}
+
+// NewEmailMessagingProvider creates an e-mail messaging provider
+func NewEmailMessagingProvider(emailService *EmailService) *EmailMessagingProvider {
+	return &EmailMessagingProvider{
+		emailService: emailService,
+	}
+}
+
+// Name returns the provider name
+func (p *EmailMessagingProvider) Name() string {
+	return "smtp"
+}
+
+// Channel returns the channel of the provider
+func (p *EmailMessagingProvider) Channel() models.LeadChannel {
+	return models.LeadChannelEmail
+}
+
+// Send e-mails the reply to the lead
+func (p *EmailMessagingProvider) Send(ctx context.Context, message OutboundMessage) (string, error) {
+	if message.Lead.Email == "" {
+		return "", fmt.Errorf("%w: lead has no email", ErrMessagingNotConfigured)
+	}
+
+	branding := p.emailService.PlatformBranding()
+	if message.Tenant != nil {
+		branding = message.Tenant.ResolvedBranding()
+	}
+
+	subject := message.Subject
+	if subject == "" {
+		subject = fmt.Sprintf("Re: seu contato com %s", branding.SenderName)
+	}
+
+	return "", p.emailService.SendLeadReply(message.Lead.Email, message.Lead.Name, branding, subject, message.Body)
+}
+
+// WhatsAppCloudMessagingProvider sends text replies through the WhatsApp Cloud API from the tenant's
+// business number (tenant setting whatsapp_phone_number_id)
+type WhatsAppCloudMessagingProvider struct {
+	accessToken string
+	apiBaseURL  string // ex: https://graph.facebook.com/v21.0
+	httpClient  *http.Client
+}
+
+// NewWhatsAppCloudMessagingProvider creates a WhatsApp Cloud API messaging provider
+func NewWhatsAppCloudMessagingProvider(accessToken, apiBaseURL string) *WhatsAppCloudMessagingProvider {
+	return &WhatsAppCloudMessagingProvider{
+		accessToken: accessToken,
+		apiBaseURL:  strings.TrimSuffix(apiBaseURL, "/"),
+		httpClient:  &http.Client{Timeout: whatsAppCloudRequestTimeout},
+	}
+}
+
+// Name returns the provider name
+func (p *WhatsAppCloudMessagingProvider) Name() string {
+	return models.WhatsAppCloudProvider
+}
+
+// Channel returns the channel of the provider
+func (p *WhatsAppCloudMessagingProvider) Channel() models.LeadChannel {
+	return models.LeadChannelWhatsApp
+}
+
+// whatsAppCloudSendResponse is the response of POST /{phone-number-id}/messages
+type whatsAppCloudSendResponse struct {
+	Messages []struct {
+		ID string `json:"id"`
+	} `json:"messages"`
+}
+
+// Send sends the reply as a WhatsApp text message
+func (p *WhatsAppCloudMessagingProvider) Send(ctx context.Context, message OutboundMessage) (string, error) {
+	phoneNumberID := ""
+	if message.Tenant != nil {
+		phoneNumberID = message.Tenant.StringSetting(models.TenantSettingWhatsAppPhoneNumberID)
+	}
+	if phoneNumberID == "" {
+		return "", fmt.Errorf("%w: tenant has no whatsapp business number", ErrMessagingNotConfigured)
+	}
+
+	recipient := models.WhatsAppRecipient(message.Lead.Phone)
+	if recipient == "" {
+		return "", fmt.Errorf("%w: lead has no whatsapp number", ErrMessagingNotConfigured)
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"messaging_product": "whatsapp",
+		"recipient_type":    "individual",
+		"to":                recipient,
+		"type":              "text",
+		"text":              map[string]interface{}{"body": message.Body, "preview_url": true},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode whatsapp message: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/%s/messages", p.apiBaseURL, phoneNumberID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to build whatsapp request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+p.accessToken)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to send whatsapp message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		response, _ := io.ReadAll(io.LimitReader(resp.Body, whatsAppCloudErrorMaxSize))
+		return "", fmt.Errorf("whatsapp cloud api returned %d: %s", resp.StatusCode, strings.TrimSpace(string(response)))
+	}
+
+	var sent whatsAppCloudSendResponse
+	if err := json.NewDecoder(resp.Body).Decode(&sent); err != nil {
+		return "", fmt.Errorf("failed to decode whatsapp response: %w", err)
+	}
+	if len(sent.Messages) == 0 {
+		return "", nil
+	}
+	return sent.Messages[0].ID, nil
+}
@@ -16,6 +16,7 @@ type MonthlyConfirmationScheduler struct {
 	propertyRepo              *repositories.PropertyRepository
 	ownerRepo                 *repositories.OwnerRepository
 	ownerConfirmationService  *OwnerConfirmationService
+	ownerReportService        *OwnerReportService
 }
 
 // NewMonthlyConfirmationScheduler creates a new monthly confirmation scheduler
@@ -33,6 +34,12 @@ func NewMonthlyConfirmationScheduler(
 	}
 }
 
+// SetOwnerReportService sets the owner report service (injected after construction)
+// When set, the monthly report of the previous month is generated and attached to each confirmation
+func (s *MonthlyConfirmationScheduler) SetOwnerReportService(ownerReportService *OwnerReportService) {
+	s.ownerReportService = ownerReportService
+}
+
 // ScheduleMonthlyConfirmationsRequest represents the request to schedule monthly confirmations
 type ScheduleMonthlyConfirmationsRequest struct {
 	TenantID     string    `json:"tenant_id"`
@@ -151,6 +158,18 @@ func (s *MonthlyConfirmationScheduler) ScheduleMonthlyConfirmations(ctx context.
 			DeliveryMethod:  "manual", // Will be updated when WhatsApp API is integrated
 		}
 
+		// Attach the owner report of the month before the confirmation
+		if s.ownerReportService != nil {
+			reportPeriod := time.Date(req.ScheduledFor.Year(), req.ScheduledFor.Month()-1, 1, 0, 0, 0, 0, time.UTC).Format("2006-01")
+			report, err := s.ownerReportService.GenerateReport(ctx, req.TenantID, property.ID, reportPeriod)
+			if err != nil {
+				log.Printf("⚠️  Warning: failed to generate owner report for property %s: %v", property.Reference, err)
+			} else {
+				scheduledConfirmation.ReportID = report.ID
+				scheduledConfirmation.ReportPeriod = report.Period
+			}
+		}
+
 		if err := s.scheduledConfirmationRepo.Create(ctx, scheduledConfirmation); err != nil {
 			log.Printf("❌ Failed to create scheduled confirmation for property %s: %v", property.Reference, err)
 			response.SkippedCount++
@@ -0,0 +1,282 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+const (
+	// defaultOutboxRelayBatchSize is the number of pending events relayed in a single run
+	defaultOutboxRelayBatchSize = 200
+
+	// outboxClaimLease keeps a claimed event away from concurrent relays while its consumers run
+	outboxClaimLease = time.Minute
+)
+
+// OutboxConsumer processes the domain events published by the OutboxRelay
+// Handle may be called again for an event it already processed (relay stopped between Handle and the
+// consumer mark): consumers use event.ID as idempotency key so every event takes effect once per consumer
+type OutboxConsumer interface {
+	Name() string
+	Handle(ctx context.Context, event *models.OutboxEvent) error
+}
+
+// OutboxRelay implements the transactional outbox
+// Services commit their writes and domain events in one Firestore transaction (Commit); the relay then
+// publishes each event to the registered consumers in registration order, recording per consumer which
+// events were processed. Events whose publication fails stay pending and are retried by RelayPending
+// Publishers: LeadService and PropertyService; the other services still write their activity log directly
+// and move to Commit as they are changed. Consumers: activity log, webhooks, notifications and lead scoring
+// (the platform has no search index yet - a search consumer plugs in with Register)
+type OutboxRelay struct {
+	outboxRepo *repositories.OutboxRepository
+	consumers  []OutboxConsumer
+}
+
+// NewOutboxRelay creates a new outbox relay
+func NewOutboxRelay(outboxRepo *repositories.OutboxRepository) *OutboxRelay {
+	return &OutboxRelay{
+		outboxRepo: outboxRepo,
+	}
+}
+
+// OutboxRelayResult summarizes a relay run
+type OutboxRelayResult struct {
+	Published int `json:"published"`
+	Retrying  int `json:"retrying"`
+	Failed    int `json:"failed"` // attempts exhausted
+	Skipped   int `json:"skipped"`
+}
+
+// Register adds a consumer (consumers run in registration order)
+func (r *OutboxRelay) Register(consumer OutboxConsumer) {
+	r.consumers = append(r.consumers, consumer)
+}
+
+// Commit applies the writes and creates their events atomically, then publishes the events right away
+// A publication failure does not fail the call: the event is committed and retried by RelayPending
+func (r *OutboxRelay) Commit(ctx context.Context, events []*models.OutboxEvent, writes ...repositories.TxWrite) error {
+	if err := r.outboxRepo.Commit(ctx, events, writes...); err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		if _, err := r.publish(ctx, event.TenantID, event.ID); err != nil {
+			log.Printf("Outbox event %s (%s) left pending: %v", event.ID, event.EventType, err)
+		}
+	}
+	return nil
+}
+
+// RelayPending publishes the pending events of a tenant whose next attempt is due
+// This endpoint should be called by a cron job every minute
+func (r *OutboxRelay) RelayPending(ctx context.Context, tenantID string, limit int) (*OutboxRelayResult, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+	if limit <= 0 {
+		limit = defaultOutboxRelayBatchSize
+	}
+
+	events, err := r.outboxRepo.ListDue(ctx, tenantID, time.Now(), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &OutboxRelayResult{}
+	for _, event := range events {
+		status, err := r.publish(ctx, tenantID, event.ID)
+		if err != nil {
+			log.Printf("Outbox event %s (%s) not published: %v", event.ID, event.EventType, err)
+		}
+
+		switch status {
+		case models.OutboxEventPublished:
+			result.Published++
+		case models.OutboxEventPending:
+			result.Retrying++
+		case models.OutboxEventFailed:
+			result.Failed++
+		default:
+			result.Skipped++ // Claimed by a concurrent relay
+		}
+	}
+
+	return result, nil
+}
+
+// ListEvents lists the outbox events of a tenant by status (newest first)
+func (r *OutboxRelay) ListEvents(ctx context.Context, tenantID string, status models.OutboxEventStatus, limit int) ([]*models.OutboxEvent, error) {
+	if status == "" {
+		status = models.OutboxEventFailed
+	}
+	if limit <= 0 {
+		limit = 50
+	}
+	return r.outboxRepo.ListByStatus(ctx, tenantID, status, limit)
+}
+
+// RetryEvent makes a failed event pending again and publishes it (consumers already done are skipped)
+func (r *OutboxRelay) RetryEvent(ctx context.Context, tenantID, id string) (*models.OutboxEvent, error) {
+	event, err := r.outboxRepo.Get(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if event.Status != models.OutboxEventFailed {
+		return nil, fmt.Errorf("only failed events can be retried (status: %s)", event.Status)
+	}
+
+	if err := r.outboxRepo.Reset(ctx, tenantID, id); err != nil {
+		return nil, err
+	}
+	if _, err := r.publish(ctx, tenantID, id); err != nil {
+		log.Printf("Outbox event %s (%s) left pending: %v", id, event.EventType, err)
+	}
+
+	return r.outboxRepo.Get(ctx, tenantID, id)
+}
+
+// publish claims an event and runs the consumers that have not processed it yet
+// Returns an empty status when the event was not claimed
+func (r *OutboxRelay) publish(ctx context.Context, tenantID, id string) (models.OutboxEventStatus, error) {
+	event, err := r.outboxRepo.Claim(ctx, tenantID, id, time.Now(), outboxClaimLease)
+	if err != nil || event == nil {
+		return "", err
+	}
+
+	for _, consumer := range r.consumers {
+		if event.HandledBy(consumer.Name()) {
+			continue
+		}
+
+		if err := consumer.Handle(ctx, event); err != nil {
+			return r.recordFailure(ctx, event, fmt.Errorf("%s: %w", consumer.Name(), err))
+		}
+		if err := r.outboxRepo.MarkConsumed(ctx, tenantID, id, consumer.Name()); err != nil {
+			return r.recordFailure(ctx, event, err)
+		}
+	}
+
+	if err := r.outboxRepo.MarkPublished(ctx, tenantID, id); err != nil {
+		return r.recordFailure(ctx, event, err)
+	}
+	return models.OutboxEventPublished, nil
+}
+
+// recordFailure schedules the next attempt of an event (or marks it failed when attempts are exhausted)
+func (r *OutboxRelay) recordFailure(ctx context.Context, event *models.OutboxEvent, cause error) (models.OutboxEventStatus, error) {
+	attempts := event.Attempts + 1
+	status := models.OutboxEventPending
+	if attempts >= models.OutboxMaxAttempts {
+		status = models.OutboxEventFailed
+	}
+
+	if err := r.outboxRepo.RecordFailure(ctx, event.TenantID, event.ID, attempts, status, time.Now().Add(models.OutboxRetryDelay(attempts)), cause.Error()); err != nil {
+		log.Printf("Failed to record outbox failure for event %s: %v", event.ID, err)
+	}
+	return status, cause
+}
+
+// activityLogConsumer appends each event to the activity log hash chain (log ID = event ID)
+type activityLogConsumer struct {
+	activityLogRepo *repositories.ActivityLogRepository
+}
+
+// NewActivityLogConsumer creates the outbox consumer that writes the activity log
+func NewActivityLogConsumer(activityLogRepo *repositories.ActivityLogRepository) OutboxConsumer {
+	return &activityLogConsumer{activityLogRepo: activityLogRepo}
+}
+
+// Name returns the consumer name recorded on processed events
+func (c *activityLogConsumer) Name() string {
+	return "activity_log"
+}
+
+// Handle appends the event to the activity log (an existing entry means it was already processed)
+func (c *activityLogConsumer) Handle(ctx context.Context, event *models.OutboxEvent) error {
+	if err := c.activityLogRepo.Create(ctx, event.ActivityLog()); err != nil && err != repositories.ErrAlreadyExists {
+		return err
+	}
+	return nil
+}
+
+// webhookConsumer queues webhook deliveries as soon as the event is in the activity log
+// Must be registered after the activity log consumer (deliveries carry the chain sequence)
+type webhookConsumer struct {
+	webhookService *WebhookService
+}
+
+// NewWebhookConsumer creates the outbox consumer that queues webhook deliveries
+func NewWebhookConsumer(webhookService *WebhookService) OutboxConsumer {
+	return &webhookConsumer{webhookService: webhookService}
+}
+
+// Name returns the consumer name recorded on processed events
+func (c *webhookConsumer) Name() string {
+	return "webhooks"
+}
+
+// Handle queues the deliveries of the event (delivery IDs are deterministic per subscription and event)
+func (c *webhookConsumer) Handle(ctx context.Context, event *models.OutboxEvent) error {
+	if !models.IsValidWebhookEvent(event.EventType) {
+		return nil
+	}
+	return c.webhookService.QueueEvent(ctx, event.TenantID, event.ID)
+}
+
+// leadScoringConsumer recalculates the score of the lead of each lead event
+type leadScoringConsumer struct {
+	scoringService *LeadScoringService
+}
+
+// NewLeadScoringConsumer creates the outbox consumer that rescores leads
+func NewLeadScoringConsumer(scoringService *LeadScoringService) OutboxConsumer {
+	return &leadScoringConsumer{scoringService: scoringService}
+}
+
+// Name returns the consumer name recorded on processed events
+func (c *leadScoringConsumer) Name() string {
+	return "lead_scoring"
+}
+
+// Handle rescores the lead (the score reflects the current lead, so processing an event again is harmless)
+func (c *leadScoringConsumer) Handle(ctx context.Context, event *models.OutboxEvent) error {
+	if event.AggregateType != "lead" || event.EventType == "lead_deleted" {
+		return nil
+	}
+	if _, err := c.scoringService.ScoreLead(ctx, event.TenantID, event.AggregateID); err != nil && !errors.Is(err, repositories.ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+// notificationConsumer e-mails brokers about their new and assigned leads (see LeadNotificationService)
+// E-mails cannot be taken back: an event handled again (relay stopped between Handle and the consumer
+// mark) may send the notification twice
+type notificationConsumer struct {
+	notificationService *LeadNotificationService
+}
+
+// NewNotificationConsumer creates the outbox consumer that sends lead notifications
+func NewNotificationConsumer(notificationService *LeadNotificationService) OutboxConsumer {
+	return &notificationConsumer{notificationService: notificationService}
+}
+
+// Name returns the consumer name recorded on processed events
+func (c *notificationConsumer) Name() string {
+	return "notifications"
+}
+
+// Handle sends the notification of the event
+func (c *notificationConsumer) Handle(ctx context.Context, event *models.OutboxEvent) error {
+	if event.AggregateType != "lead" {
+		return nil
+	}
+	return c.notificationService.NotifyEvent(ctx, event)
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/altatech/ecosistema-imob/backend/internal/models"
 	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/utils"
 )
 
 // OwnerConfirmationService handles owner confirmation token logic
@@ -20,6 +21,9 @@ type OwnerConfirmationService struct {
 	brokerRepo      *repositories.BrokerRepository
 	listingRepo     *repositories.ListingRepository
 	activityLogRepo *repositories.ActivityLogRepository
+
+	ownerReportService *OwnerReportService            // Optional: monthly report shown on the owner page
+	tenantRepo         *repositories.TenantRepository // Optional: tenant branding of the owner page
 }
 
 // NewOwnerConfirmationService creates a new owner confirmation service
@@ -41,6 +45,16 @@ func NewOwnerConfirmationService(
 	}
 }
 
+// SetOwnerReportService sets the owner report service (injected after construction)
+func (s *OwnerConfirmationService) SetOwnerReportService(ownerReportService *OwnerReportService) {
+	s.ownerReportService = ownerReportService
+}
+
+// SetTenantRepository sets the tenant repository used to brand the owner page (injected after construction)
+func (s *OwnerConfirmationService) SetTenantRepository(tenantRepo *repositories.TenantRepository) {
+	s.tenantRepo = tenantRepo
+}
+
 // GenerateOwnerConfirmationLink generates a secure confirmation link for property owner
 // Returns: confirmationURL, tokenID, expiresAt, error
 func (s *OwnerConfirmationService) GenerateOwnerConfirmationLink(
@@ -77,9 +91,9 @@ func (s *OwnerConfirmationService) GenerateOwnerConfirmationLink(
 		owner, err := s.ownerRepo.Get(ctx, tenantID, *ownerID)
 		if err == nil && owner.OwnerStatus != models.OwnerStatusIncomplete {
 			ownerSnapshot = &models.OwnerSnapshotMinimal{
-				Name:  maskName(owner.Name),
-				Phone: maskPhone(owner.Phone),
-				Email: maskEmail(owner.Email),
+				Name:  utils.MaskName(owner.Name),
+				Phone: utils.MaskPhone(owner.Phone),
+				Email: utils.MaskEmail(owner.Email),
 			}
 		}
 	} else if property.OwnerID != "" {
@@ -87,9 +101,9 @@ func (s *OwnerConfirmationService) GenerateOwnerConfirmationLink(
 		owner, err := s.ownerRepo.Get(ctx, tenantID, property.OwnerID)
 		if err == nil && owner.OwnerStatus != models.OwnerStatusIncomplete {
 			ownerSnapshot = &models.OwnerSnapshotMinimal{
-				Name:  maskName(owner.Name),
-				Phone: maskPhone(owner.Phone),
-				Email: maskEmail(owner.Email),
+				Name:  utils.MaskName(owner.Name),
+				Phone: utils.MaskPhone(owner.Phone),
+				Email: utils.MaskEmail(owner.Email),
 			}
 			ownerIDCopy := property.OwnerID
 			ownerID = &ownerIDCopy
@@ -147,6 +161,9 @@ type GetConfirmationPageResponse struct {
 	BrokerPhone   string  `json:"broker_phone,omitempty"`    // Telefone do corretor
 	ExpiresAt     string  `json:"expires_at,omitempty"`
 	Error         string  `json:"error,omitempty"`
+
+	Report   *models.OwnerReport          `json:"report,omitempty"`   // Relatório mensal mais recente do imóvel
+	Branding *models.PublicTenantBranding `json:"branding,omitempty"` // Identidade visual da imobiliária
 }
 
 // ValidateTokenAndGetPropertyInfo validates token and returns minimal property info for display
@@ -225,6 +242,20 @@ func (s *OwnerConfirmationService) ValidateTokenAndGetPropertyInfo(
 		}
 	}
 
+	// Latest monthly report (aggregated data only)
+	var report *models.OwnerReport
+	if s.ownerReportService != nil {
+		report, _ = s.ownerReportService.GetReport(ctx, tenantID, property.ID, "")
+	}
+
+	// Tenant branding (logo, colors, legal footer) of the page
+	var branding *models.PublicTenantBranding
+	if s.tenantRepo != nil {
+		if tenant, err := s.tenantRepo.Get(ctx, tenantID); err == nil {
+			branding = tenant.PublicBranding()
+		}
+	}
+
 	// Return minimal property info (don't expose sensitive data)
 	return &GetConfirmationPageResponse{
 		Valid:         true,
@@ -240,9 +271,39 @@ func (s *OwnerConfirmationService) ValidateTokenAndGetPropertyInfo(
 		BrokerPhoto:   brokerPhoto,   // Foto do corretor
 		BrokerPhone:   brokerPhone,   // Telefone do corretor
 		ExpiresAt:     confirmationToken.ExpiresAt.Format("2006-01-02T15:04:05Z07:00"),
+		Report:        report,
+		Branding:      branding,
 	}, nil
 }
 
+// GetOwnerReportByToken returns the monthly report of the token's property (latest when period is empty)
+// Unlike the confirmation itself, the report stays viewable after the token is used, until it expires
+func (s *OwnerConfirmationService) GetOwnerReportByToken(
+	ctx context.Context,
+	tenantID string,
+	token string,
+	period string,
+) (*models.OwnerReport, error) {
+	if s.ownerReportService == nil {
+		return nil, fmt.Errorf("owner reports are not enabled")
+	}
+
+	// Hash the provided token
+	hash := sha256.Sum256([]byte(token))
+	tokenHash := fmt.Sprintf("%x", hash)
+
+	confirmationToken, err := s.tokenRepo.GetByTokenHash(ctx, tenantID, tokenHash)
+	if err != nil {
+		return nil, repositories.ErrNotFound
+	}
+
+	if time.Now().After(confirmationToken.ExpiresAt) {
+		return nil, fmt.Errorf("token expired")
+	}
+
+	return s.ownerReportService.GetReport(ctx, tenantID, confirmationToken.PropertyID, period)
+}
+
 // SubmitOwnerConfirmation processes the owner's confirmation action
 func (s *OwnerConfirmationService) SubmitOwnerConfirmation(
 	ctx context.Context,
@@ -354,57 +415,5 @@ func (s *OwnerConfirmationService) logActivity(
 		Timestamp: time.Now(),
 	}
 
-	return s.activityLogRepo.Create(ctx, log)
-}
-
-// Helper functions for masking sensitive data
-
-func maskName(name string) string {
-	if name == "" {
-		return ""
-	}
-	if len(name) <= 2 {
-		return name
-	}
-	// "João Silva" -> "João S."
-	parts := []rune(name)
-	if len(parts) > 5 {
-		return string(parts[0:5]) + "..."
-	}
-	return name
-}
-
-func maskPhone(phone string) string {
-	if phone == "" {
-		return ""
-	}
-	// "(11) 98765-4321" -> "(11) 9****-4321"
-	if len(phone) > 8 {
-		return phone[0:len(phone)-8] + "****" + phone[len(phone)-4:]
-	}
-	return "****" + phone[len(phone)-4:]
-}
-
-func maskEmail(email string) string {
-	if email == "" {
-		return ""
-	}
-	// "joao@example.com" -> "j***@example.com"
-	atIndex := -1
-	for i, c := range email {
-		if c == '@' {
-			atIndex = i
-			break
-		}
-	}
-
-	if atIndex <= 0 {
-		return email
-	}
-
-	if atIndex == 1 {
-		return email[0:1] + "***" + email[atIndex:]
-	}
-
-	return email[0:1] + "***" + email[atIndex:]
+	return appendActivityLog(ctx, s.activityLogRepo, log)
 }
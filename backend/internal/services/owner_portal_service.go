@@ -0,0 +1,712 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"mime/multipart"
+	"regexp"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/utils"
+)
+
+const (
+	// ownerMagicLinkTTL is the validity of an owner portal magic link (single use)
+	ownerMagicLinkTTL = 15 * time.Minute
+
+	// ownerSessionTTL is the validity of an owner portal session
+	ownerSessionTTL = 12 * time.Hour
+
+	// ownerConsentOriginSelfService marks consents given or revoked by the owner in the portal
+	ownerConsentOriginSelfService = "self_service"
+
+	// defaultOwnerConsentText is recorded when the portal does not send the displayed text
+	defaultOwnerConsentText = "Autorizo o tratamento dos meus dados pessoais para a divulgação e intermediação dos meus imóveis, conforme a LGPD."
+)
+
+var nonDigitRegex = regexp.MustCompile(`\D`)
+
+// ErrOwnerPortalWhatsAppUnavailable is returned when a magic link is requested by phone and the tenant
+// cannot send WhatsApp messages
+var ErrOwnerPortalWhatsAppUnavailable = errors.New("whatsapp access links are not available: please inform an e-mail")
+
+// OwnerPortalService handles the owner self-service portal (magic link sessions and owner actions)
+// Todas as ações do proprietário são registradas no ActivityLog como ActorTypeOwner
+type OwnerPortalService struct {
+	tokenRepo        *repositories.OwnerPortalTokenRepository
+	ownerRepo        *repositories.OwnerRepository
+	propertyRepo     *repositories.PropertyRepository
+	listingRepo      *repositories.ListingRepository
+	leadRepo         *repositories.LeadRepository
+	tenantRepo       *repositories.TenantRepository
+	activityLogRepo  *repositories.ActivityLogRepository
+	emailService     *EmailService
+	documentService  *PropertyDocumentService
+	consentService   *ConsentService        // optional: consent ledger
+	alertService     *MarketingAlertService // optional: price drop alerts to interested leads
+	whatsAppProvider MessagingProvider      // optional: magic links requested by phone
+}
+
+// NewOwnerPortalService creates a new owner portal service
+func NewOwnerPortalService(
+	tokenRepo *repositories.OwnerPortalTokenRepository,
+	ownerRepo *repositories.OwnerRepository,
+	propertyRepo *repositories.PropertyRepository,
+	listingRepo *repositories.ListingRepository,
+	leadRepo *repositories.LeadRepository,
+	tenantRepo *repositories.TenantRepository,
+	activityLogRepo *repositories.ActivityLogRepository,
+	emailService *EmailService,
+	documentService *PropertyDocumentService,
+) *OwnerPortalService {
+	return &OwnerPortalService{
+		tokenRepo:       tokenRepo,
+		ownerRepo:       ownerRepo,
+		propertyRepo:    propertyRepo,
+		listingRepo:     listingRepo,
+		leadRepo:        leadRepo,
+		tenantRepo:      tenantRepo,
+		activityLogRepo: activityLogRepo,
+		emailService:    emailService,
+		documentService: documentService,
+	}
+}
+
+// SetConsentService sets the consent ledger service (for dependency injection)
+func (s *OwnerPortalService) SetConsentService(service *ConsentService) {
+	s.consentService = service
+}
+
+// SetMarketingAlertService sets the marketing alert service (for dependency injection)
+func (s *OwnerPortalService) SetMarketingAlertService(service *MarketingAlertService) {
+	s.alertService = service
+}
+
+// SetWhatsAppProvider sets the provider that delivers magic links requested by phone (for dependency injection)
+func (s *OwnerPortalService) SetWhatsAppProvider(provider MessagingProvider) {
+	s.whatsAppProvider = provider
+}
+
+// RequestOwnerMagicLinkInput represents a magic link request (email OR phone)
+type RequestOwnerMagicLinkInput struct {
+	TenantID string
+	Email    string
+	Phone    string
+}
+
+// OwnerPortalSessionResult is returned when a magic link is exchanged for a session
+type OwnerPortalSessionResult struct {
+	SessionToken string              `json:"session_token"`
+	ExpiresAt    time.Time           `json:"expires_at"`
+	Owner        *OwnerPortalProfile `json:"owner"`
+}
+
+// OwnerPortalProfile is the owner data shown in the portal (contact data masked)
+type OwnerPortalProfile struct {
+	ID            string     `json:"id"`
+	Name          string     `json:"name"`
+	Email         string     `json:"email,omitempty"`
+	Phone         string     `json:"phone,omitempty"`
+	ConsentGiven  bool       `json:"consent_given"`
+	ConsentDate   *time.Time `json:"consent_date,omitempty"`
+	ConsentOrigin string     `json:"consent_origin,omitempty"`
+}
+
+// OwnerPortalProperty is a property of the owner with its engagement counters
+type OwnerPortalProperty struct {
+	ID            string                    `json:"id"`
+	Reference     string                    `json:"reference,omitempty"`
+	Title         string                    `json:"title,omitempty"`
+	PropertyType  models.PropertyType       `json:"property_type"`
+	Neighborhood  string                    `json:"neighborhood"`
+	City          string                    `json:"city"`
+	Status        models.PropertyStatus     `json:"status"`
+	Visibility    models.PropertyVisibility `json:"visibility"`
+	PriceAmount   float64                   `json:"price_amount"`
+	CoverImageURL string                    `json:"cover_image_url,omitempty"`
+	ListingsCount int                       `json:"listings_count"`
+
+	LeadsTotal       int `json:"leads_total"`
+	LeadsLast30Days  int `json:"leads_last_30_days"`
+	VisitsTotal      int `json:"visits_total"`
+	VisitsLast30Days int `json:"visits_last_30_days"`
+}
+
+// RequestMagicLink creates a single-use access link and delivers it by email or WhatsApp
+// Never reveals whether the owner exists (returns nil for unknown contacts); requests by phone are rejected
+// with ErrOwnerPortalWhatsAppUnavailable when the tenant cannot send WhatsApp messages
+func (s *OwnerPortalService) RequestMagicLink(ctx context.Context, input RequestOwnerMagicLinkInput) error {
+	if input.TenantID == "" {
+		return fmt.Errorf("tenant_id is required")
+	}
+	if input.Email == "" && input.Phone == "" {
+		return fmt.Errorf("email or phone is required")
+	}
+
+	var tenant *models.Tenant
+	if input.Email == "" {
+		tenant = s.whatsAppTenant(ctx, input.TenantID)
+		if tenant == nil {
+			return ErrOwnerPortalWhatsAppUnavailable
+		}
+	}
+
+	var owner *models.Owner
+	var err error
+	channel := "email"
+	if input.Email != "" {
+		owner, err = s.ownerRepo.GetByEmail(ctx, input.TenantID, utils.NormalizeEmail(input.Email))
+	} else {
+		channel = "whatsapp"
+		owner, err = s.ownerRepo.GetByPhone(ctx, input.TenantID, normalizeOwnerPhone(input.Phone))
+	}
+	if err != nil || owner.IsAnonymized {
+		log.Printf("⚠️  Owner portal magic link requested for unknown contact (tenant %s, channel %s)", input.TenantID, channel)
+		return nil
+	}
+
+	token, tokenHash, err := generatePortalToken()
+	if err != nil {
+		return err
+	}
+
+	magicLink := &models.OwnerPortalToken{
+		TenantID:  input.TenantID,
+		OwnerID:   owner.ID,
+		Type:      models.OwnerPortalTokenMagicLink,
+		TokenHash: tokenHash,
+		Channel:   channel,
+		ExpiresAt: time.Now().Add(ownerMagicLinkTTL),
+	}
+	if err := s.tokenRepo.Create(ctx, magicLink); err != nil {
+		return err
+	}
+
+	magicLinkURL := s.emailService.OwnerPortalURL(token, input.TenantID)
+
+	switch channel {
+	case "email":
+		branding := s.emailService.PlatformBranding()
+		if tenant, err := s.tenantRepo.Get(ctx, input.TenantID); err == nil {
+			branding = tenant.ResolvedBranding()
+		}
+		if err := s.emailService.SendOwnerMagicLink(owner.Email, owner.Name, branding, magicLinkURL, magicLink.ExpiresAt); err != nil {
+			return fmt.Errorf("failed to send magic link: %w", err)
+		}
+	case "whatsapp":
+		if err := s.sendMagicLinkWhatsApp(ctx, tenant, owner, magicLinkURL); err != nil {
+			return err
+		}
+	}
+
+	_ = s.logActivity(ctx, input.TenantID, "owner_portal_magic_link_requested", owner.ID, map[string]interface{}{
+		"owner_id":      owner.ID,
+		"magic_link_id": magicLink.ID,
+		"channel":       channel,
+		"expires_at":    magicLink.ExpiresAt,
+	})
+
+	return nil
+}
+
+// whatsAppTenant returns the tenant when it can send WhatsApp messages (nil otherwise)
+func (s *OwnerPortalService) whatsAppTenant(ctx context.Context, tenantID string) *models.Tenant {
+	if s.whatsAppProvider == nil {
+		return nil
+	}
+	tenant, err := s.tenantRepo.Get(ctx, tenantID)
+	if err != nil || tenant.StringSetting(models.TenantSettingWhatsAppPhoneNumberID) == "" {
+		return nil
+	}
+	return tenant
+}
+
+// sendMagicLinkWhatsApp sends the magic link to the owner's phone from the tenant's business number
+// The link is a credential: it only goes to the provider, never to the logs
+func (s *OwnerPortalService) sendMagicLinkWhatsApp(ctx context.Context, tenant *models.Tenant, owner *models.Owner, magicLinkURL string) error {
+	branding := tenant.ResolvedBranding()
+	body := fmt.Sprintf("Olá, %s! Acesse o portal do proprietário da %s pelo link abaixo. Ele vale por %d minutos e só pode ser usado uma vez.\n\n%s\n\nSe você não pediu este acesso, ignore esta mensagem.",
+		owner.Name, branding.SenderName, int(ownerMagicLinkTTL.Minutes()), magicLinkURL)
+
+	if _, err := s.whatsAppProvider.Send(ctx, OutboundMessage{
+		Tenant: tenant,
+		Lead:   &models.Lead{TenantID: tenant.ID, Name: owner.Name, Phone: owner.Phone},
+		Body:   body,
+	}); err != nil {
+		return fmt.Errorf("failed to send magic link: %w", err)
+	}
+	return nil
+}
+
+// ExchangeMagicLink consumes a magic link and starts a portal session
+func (s *OwnerPortalService) ExchangeMagicLink(ctx context.Context, tenantID, token, clientIP, userAgent string) (*OwnerPortalSessionResult, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+	if token == "" {
+		return nil, fmt.Errorf("token is required")
+	}
+
+	magicLink, err := s.tokenRepo.GetByTokenHash(ctx, tenantID, models.OwnerPortalTokenMagicLink, hashPortalToken(token))
+	if err != nil {
+		return nil, fmt.Errorf("invalid or expired link")
+	}
+	if !magicLink.IsActive(time.Now()) {
+		return nil, fmt.Errorf("invalid or expired link")
+	}
+
+	// Single use: consumed atomically so the same link cannot open two sessions
+	if err := s.tokenRepo.MarkUsed(ctx, tenantID, magicLink.ID); err != nil {
+		return nil, fmt.Errorf("invalid or expired link")
+	}
+
+	owner, err := s.ownerRepo.Get(ctx, tenantID, magicLink.OwnerID)
+	if err != nil || owner.IsAnonymized {
+		return nil, fmt.Errorf("invalid or expired link")
+	}
+
+	sessionToken, sessionHash, err := generatePortalToken()
+	if err != nil {
+		return nil, err
+	}
+
+	session := &models.OwnerPortalToken{
+		TenantID:    tenantID,
+		OwnerID:     owner.ID,
+		Type:        models.OwnerPortalTokenSession,
+		TokenHash:   sessionHash,
+		MagicLinkID: magicLink.ID,
+		ClientIP:    clientIP,
+		UserAgent:   userAgent,
+		ExpiresAt:   time.Now().Add(ownerSessionTTL),
+	}
+	if err := s.tokenRepo.Create(ctx, session); err != nil {
+		return nil, err
+	}
+
+	_ = s.logActivity(ctx, tenantID, "owner_portal_session_started", owner.ID, map[string]interface{}{
+		"owner_id":      owner.ID,
+		"session_id":    session.ID,
+		"magic_link_id": magicLink.ID,
+		"client_ip":     clientIP,
+	})
+
+	return &OwnerPortalSessionResult{
+		SessionToken: sessionToken,
+		ExpiresAt:    session.ExpiresAt,
+		Owner:        buildOwnerPortalProfile(owner),
+	}, nil
+}
+
+// Authenticate validates a session token and returns the session
+func (s *OwnerPortalService) Authenticate(ctx context.Context, tenantID, sessionToken string) (*models.OwnerPortalToken, error) {
+	if tenantID == "" || sessionToken == "" {
+		return nil, repositories.ErrNotFound
+	}
+
+	session, err := s.tokenRepo.GetByTokenHash(ctx, tenantID, models.OwnerPortalTokenSession, hashPortalToken(sessionToken))
+	if err != nil {
+		return nil, repositories.ErrNotFound
+	}
+	if !session.IsActive(time.Now()) {
+		return nil, repositories.ErrNotFound
+	}
+
+	_ = s.tokenRepo.Update(ctx, tenantID, session.ID, map[string]interface{}{
+		"last_seen_at": time.Now(),
+	})
+
+	return session, nil
+}
+
+// Logout revokes a portal session
+func (s *OwnerPortalService) Logout(ctx context.Context, session *models.OwnerPortalToken) error {
+	if err := s.tokenRepo.Update(ctx, session.TenantID, session.ID, map[string]interface{}{
+		"revoked_at": time.Now(),
+	}); err != nil {
+		return err
+	}
+
+	_ = s.logActivity(ctx, session.TenantID, "owner_portal_session_ended", session.OwnerID, map[string]interface{}{
+		"owner_id":   session.OwnerID,
+		"session_id": session.ID,
+	})
+
+	return nil
+}
+
+// GetProfile returns the owner profile of the session
+func (s *OwnerPortalService) GetProfile(ctx context.Context, session *models.OwnerPortalToken) (*OwnerPortalProfile, error) {
+	owner, err := s.ownerRepo.Get(ctx, session.TenantID, session.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildOwnerPortalProfile(owner), nil
+}
+
+// ListProperties lists all properties of the owner (across listings) with lead and visit counts
+func (s *OwnerPortalService) ListProperties(ctx context.Context, session *models.OwnerPortalToken) ([]*OwnerPortalProperty, error) {
+	properties, err := s.propertyRepo.ListByOwner(ctx, session.TenantID, session.OwnerID, repositories.PaginationOptions{
+		Limit: 1000,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list properties: %w", err)
+	}
+
+	since := time.Now().AddDate(0, 0, -30)
+
+	results := make([]*OwnerPortalProperty, 0, len(properties))
+	for _, property := range properties {
+		visitsTotal, visitsRecent, err := s.countPropertyVisits(ctx, session.TenantID, property.ID, since)
+		if err != nil {
+			return nil, err
+		}
+
+		item := &OwnerPortalProperty{
+			ID:               property.ID,
+			Reference:        property.Reference,
+			PropertyType:     property.PropertyType,
+			Neighborhood:     property.Neighborhood,
+			City:             property.City,
+			Status:           property.Status,
+			Visibility:       property.Visibility,
+			PriceAmount:      property.PriceAmount,
+			VisitsTotal:      visitsTotal,
+			VisitsLast30Days: visitsRecent,
+		}
+
+		if listings, err := s.listingRepo.ListByProperty(ctx, session.TenantID, property.ID, repositories.PaginationOptions{Limit: 100}); err == nil {
+			item.ListingsCount = len(listings)
+			for _, listing := range listings {
+				if listing.ID != property.CanonicalListingID && item.Title != "" {
+					continue
+				}
+				item.Title = listing.Title
+				if len(listing.Photos) > 0 {
+					item.CoverImageURL = listing.Photos[0].ThumbURL
+					for _, photo := range listing.Photos {
+						if photo.IsCover {
+							item.CoverImageURL = photo.ThumbURL
+							break
+						}
+					}
+				}
+			}
+		}
+
+		leads, err := s.leadRepo.ListByProperty(ctx, session.TenantID, property.ID, repositories.PaginationOptions{Limit: 1000})
+		if err != nil {
+			log.Printf("⚠️  Warning: failed to count leads for property %s: %v", property.ID, err)
+		}
+		for _, lead := range leads {
+			item.LeadsTotal++
+			if lead.CreatedAt.After(since) {
+				item.LeadsLast30Days++
+			}
+		}
+
+		results = append(results, item)
+	}
+
+	return results, nil
+}
+
+// UpdatePrice updates the price of an owned property
+func (s *OwnerPortalService) UpdatePrice(ctx context.Context, session *models.OwnerPortalToken, propertyID string, priceAmount float64) error {
+	if priceAmount <= 0 {
+		return fmt.Errorf("valid price_amount is required")
+	}
+
+	property, err := s.getOwnedProperty(ctx, session, propertyID)
+	if err != nil {
+		return err
+	}
+
+	updates := map[string]interface{}{
+		"price_amount":       priceAmount,
+		"price_confirmed_at": time.Now(),
+	}
+	if err := s.propertyRepo.Update(ctx, session.TenantID, property.ID, updates); err != nil {
+		return fmt.Errorf("failed to update property: %w", err)
+	}
+
+	_ = s.logActivity(ctx, session.TenantID, "owner_confirmed_price", session.OwnerID, map[string]interface{}{
+		"property_id":  property.ID,
+		"owner_id":     session.OwnerID,
+		"session_id":   session.ID,
+		"old_price":    property.PriceAmount,
+		"price_amount": priceAmount,
+		"source":       "owner_portal",
+	})
+
+	// Price drop: alert interested leads (only those with marketing consent)
+	if s.alertService != nil && priceAmount < property.PriceAmount {
+		go func() {
+			if _, err := s.alertService.SendPriceDropAlerts(context.Background(), session.TenantID, property.ID, property.PriceAmount, priceAmount); err != nil {
+				log.Printf("⚠️  Failed to send price drop alerts for property %s: %v", property.ID, err)
+			}
+		}()
+	}
+
+	return nil
+}
+
+// UpdateAvailability marks an owned property as available or unavailable
+func (s *OwnerPortalService) UpdateAvailability(ctx context.Context, session *models.OwnerPortalToken, propertyID string, available bool) error {
+	property, err := s.getOwnedProperty(ctx, session, propertyID)
+	if err != nil {
+		return err
+	}
+
+	status := models.PropertyStatusAvailable
+	updates := map[string]interface{}{
+		"status_confirmed_at": time.Now(),
+	}
+	if !available {
+		status = models.PropertyStatusUnavailable
+		updates["visibility"] = models.PropertyVisibilityPrivate // Hide unavailable properties
+	}
+	updates["status"] = status
+
+	if err := s.propertyRepo.Update(ctx, session.TenantID, property.ID, updates); err != nil {
+		return fmt.Errorf("failed to update property: %w", err)
+	}
+
+	// Same event as the confirmation link, so status history (market analytics) stays consistent
+	_ = s.logActivity(ctx, session.TenantID, "owner_confirmed_status", session.OwnerID, map[string]interface{}{
+		"property_id": property.ID,
+		"owner_id":    session.OwnerID,
+		"session_id":  session.ID,
+		"old_status":  property.Status,
+		"status":      status,
+		"source":      "owner_portal",
+	})
+
+	return nil
+}
+
+// UploadDocument stores a document sent by the owner for one of their properties
+func (s *OwnerPortalService) UploadDocument(ctx context.Context, session *models.OwnerPortalToken, propertyID string, category models.DocumentCategory, file multipart.File, header *multipart.FileHeader) (*models.PropertyDocument, error) {
+	property, err := s.getOwnedProperty(ctx, session, propertyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.documentService.UploadDocument(ctx, UploadPropertyDocumentInput{
+		TenantID:       session.TenantID,
+		PropertyID:     property.ID,
+		OwnerID:        session.OwnerID,
+		Category:       category,
+		File:           file,
+		Header:         header,
+		UploadedByType: models.ActorTypeOwner,
+		UploadedByID:   session.OwnerID,
+	})
+}
+
+// ListDocuments lists the documents of an owned property
+func (s *OwnerPortalService) ListDocuments(ctx context.Context, session *models.OwnerPortalToken, propertyID string) ([]*models.PropertyDocument, error) {
+	property, err := s.getOwnedProperty(ctx, session, propertyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.documentService.ListDocuments(ctx, session.TenantID, property.ID, "")
+}
+
+// GetDocumentURL returns a short-lived download URL for a document of an owned property
+func (s *OwnerPortalService) GetDocumentURL(ctx context.Context, session *models.OwnerPortalToken, documentID string) (*DocumentURLResponse, error) {
+	document, err := s.documentService.GetDocument(ctx, session.TenantID, documentID)
+	if err != nil {
+		return nil, repositories.ErrNotFound
+	}
+	if _, err := s.getOwnedProperty(ctx, session, document.PropertyID); err != nil {
+		return nil, err
+	}
+
+	return s.documentService.GetDownloadURL(ctx, document, models.ActorTypeOwner, session.OwnerID)
+}
+
+// UpdateConsent gives or revokes the owner's LGPD consent (ConsentOrigin = self_service)
+func (s *OwnerPortalService) UpdateConsent(ctx context.Context, session *models.OwnerPortalToken, given bool, consentText string) (*OwnerPortalProfile, error) {
+	now := time.Now()
+	updates := map[string]interface{}{
+		"consent_given":  given,
+		"consent_origin": ownerConsentOriginSelfService,
+		"consent_date":   now,
+	}
+
+	eventType := "owner_consent_given"
+	if given {
+		if consentText == "" {
+			consentText = defaultOwnerConsentText
+		}
+		updates["consent_text"] = consentText
+		updates["consent_revoked"] = false
+	} else {
+		eventType = "owner_consent_revoked"
+		updates["consent_revoked"] = true
+		updates["revoked_at"] = now
+	}
+
+	if err := s.ownerRepo.Update(ctx, session.TenantID, session.OwnerID, updates); err != nil {
+		return nil, fmt.Errorf("failed to update consent: %w", err)
+	}
+
+	// Ledger: the portal consent is the contact purpose; revoking it withdraws every purpose
+	if s.consentService != nil {
+		var err error
+		input := s.portalConsentInput(session, models.ConsentPurposeContact, given)
+		if given {
+			input.Text = consentText
+			_, err = s.consentService.RecordConsent(ctx, input)
+		} else {
+			err = s.consentService.WithdrawAll(ctx, input)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to record consent: %w", err)
+		}
+	}
+
+	_ = s.logActivity(ctx, session.TenantID, eventType, session.OwnerID, map[string]interface{}{
+		"owner_id":       session.OwnerID,
+		"session_id":     session.ID,
+		"consent_given":  given,
+		"consent_origin": ownerConsentOriginSelfService,
+	})
+
+	return s.GetProfile(ctx, session)
+}
+
+// GetConsents returns the current consent of the session owner for every purpose
+func (s *OwnerPortalService) GetConsents(ctx context.Context, session *models.OwnerPortalToken) ([]models.ConsentState, error) {
+	if s.consentService == nil {
+		return nil, fmt.Errorf("consent ledger is not configured")
+	}
+	return s.consentService.GetConsentState(ctx, session.TenantID, models.ConsentSubjectOwner, session.OwnerID)
+}
+
+// UpdatePurposeConsent grants or withdraws one purpose (textVersion = published version shown, 0 = current)
+func (s *OwnerPortalService) UpdatePurposeConsent(ctx context.Context, session *models.OwnerPortalToken, purpose models.ConsentPurpose, granted bool, textVersion int) ([]models.ConsentState, error) {
+	if s.consentService == nil {
+		return nil, fmt.Errorf("consent ledger is not configured")
+	}
+
+	input := s.portalConsentInput(session, purpose, granted)
+	input.TextVersion = textVersion
+	if _, err := s.consentService.RecordConsent(ctx, input); err != nil {
+		return nil, err
+	}
+
+	return s.GetConsents(ctx, session)
+}
+
+// portalConsentInput builds a ledger entry for an action of the session owner
+func (s *OwnerPortalService) portalConsentInput(session *models.OwnerPortalToken, purpose models.ConsentPurpose, granted bool) RecordConsentInput {
+	return RecordConsentInput{
+		TenantID:    session.TenantID,
+		SubjectType: models.ConsentSubjectOwner,
+		SubjectID:   session.OwnerID,
+		Purpose:     purpose,
+		Granted:     granted,
+		IP:          session.ClientIP,
+		UserAgent:   session.UserAgent,
+		Origin:      ConsentOriginOwnerPortal,
+		ActorType:   models.ActorTypeOwner,
+		ActorID:     session.OwnerID,
+	}
+}
+
+// getOwnedProperty loads a property and checks it belongs to the session owner
+func (s *OwnerPortalService) getOwnedProperty(ctx context.Context, session *models.OwnerPortalToken, propertyID string) (*models.Property, error) {
+	if propertyID == "" {
+		return nil, fmt.Errorf("property_id is required")
+	}
+
+	property, err := s.propertyRepo.Get(ctx, session.TenantID, propertyID)
+	if err != nil {
+		return nil, repositories.ErrNotFound
+	}
+	if property.OwnerID != session.OwnerID {
+		return nil, repositories.ErrNotFound // Don't reveal properties of other owners
+	}
+
+	return property, nil
+}
+
+// countPropertyVisits counts the visits recorded for a property (total and since the given date)
+// Uses count aggregations per property instead of loading the tenant's visit logs
+func (s *OwnerPortalService) countPropertyVisits(ctx context.Context, tenantID, propertyID string, since time.Time) (int, int, error) {
+	total, err := s.activityLogRepo.CountPropertyEvents(ctx, tenantID, propertyID, visitCompletedEvent, nil)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to count visits: %w", err)
+	}
+
+	recent, err := s.activityLogRepo.CountPropertyEvents(ctx, tenantID, propertyID, visitCompletedEvent, &since)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to count visits: %w", err)
+	}
+
+	return total, recent, nil
+}
+
+// logActivity logs an owner action (helper method)
+func (s *OwnerPortalService) logActivity(ctx context.Context, tenantID, eventType, ownerID string, metadata map[string]interface{}) error {
+	log := &models.ActivityLog{
+		TenantID:  tenantID,
+		EventType: eventType,
+		ActorType: models.ActorTypeOwner,
+		ActorID:   ownerID,
+		Metadata:  metadata,
+		Timestamp: time.Now(),
+	}
+
+	return appendActivityLog(ctx, s.activityLogRepo, log)
+}
+
+// buildOwnerPortalProfile builds the portal profile with masked contact data
+func buildOwnerPortalProfile(owner *models.Owner) *OwnerPortalProfile {
+	return &OwnerPortalProfile{
+		ID:            owner.ID,
+		Name:          owner.Name,
+		Email:         utils.MaskEmail(owner.Email),
+		Phone:         utils.MaskPhone(owner.Phone),
+		ConsentGiven:  owner.ConsentGiven,
+		ConsentDate:   owner.ConsentDate,
+		ConsentOrigin: owner.ConsentOrigin,
+	}
+}
+
+// generatePortalToken generates a random URL-safe token and its SHA-256 hash
+func generatePortalToken() (string, string, error) {
+	tokenBytes := make([]byte, 32)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return "", "", fmt.Errorf("failed to generate random token: %w", err)
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(tokenBytes)
+	return token, hashPortalToken(token), nil
+}
+
+// hashPortalToken returns the SHA-256 hex hash stored instead of the token
+func hashPortalToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return fmt.Sprintf("%x", hash)
+}
+
+// normalizeOwnerPhone normalizes a phone (with or without +55) to the stored owner format
+func normalizeOwnerPhone(phone string) string {
+	digits := nonDigitRegex.ReplaceAllString(phone, "")
+	if len(digits) >= 12 && digits[:2] == "55" {
+		digits = digits[2:]
+	}
+	return utils.NormalizePhoneBR(digits)
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+)
+
+// fakeMessagingProvider records the messages it is asked to send
+type fakeMessagingProvider struct {
+	sent []OutboundMessage
+	err  error
+}
+
+func (p *fakeMessagingProvider) Name() string { return "fake" }
+
+func (p *fakeMessagingProvider) Channel() models.LeadChannel { return models.LeadChannelWhatsApp }
+
+func (p *fakeMessagingProvider) Send(ctx context.Context, message OutboundMessage) (string, error) {
+	if p.err != nil {
+		return "", p.err
+	}
+	p.sent = append(p.sent, message)
+	return "wamid.1", nil
+}
+
+func TestRequestMagicLinkByPhoneWithoutWhatsApp(t *testing.T) {
+	service := &OwnerPortalService{}
+
+	err := service.RequestMagicLink(context.Background(), RequestOwnerMagicLinkInput{TenantID: "tenant-1", Phone: "(11) 98765-4321"})
+	assert.ErrorIs(t, err, ErrOwnerPortalWhatsAppUnavailable)
+}
+
+func TestRequestMagicLinkValidation(t *testing.T) {
+	service := &OwnerPortalService{}
+
+	assert.Error(t, service.RequestMagicLink(context.Background(), RequestOwnerMagicLinkInput{Email: "dono@example.com"}))
+	assert.Error(t, service.RequestMagicLink(context.Background(), RequestOwnerMagicLinkInput{TenantID: "tenant-1"}))
+}
+
+func TestSendMagicLinkWhatsApp(t *testing.T) {
+	provider := &fakeMessagingProvider{}
+	service := &OwnerPortalService{whatsAppProvider: provider}
+	tenant := &models.Tenant{ID: "tenant-1", Name: "Imobiliária Sul"}
+	owner := &models.Owner{ID: "owner-1", Name: "Carlos", Phone: "(11) 98765-4321"}
+	link := "https://app.example.com/owner-portal/tenant-1?token=secret-token"
+
+	require.NoError(t, service.sendMagicLinkWhatsApp(context.Background(), tenant, owner, link))
+	require.Len(t, provider.sent, 1)
+
+	message := provider.sent[0]
+	assert.Equal(t, tenant, message.Tenant)
+	assert.Equal(t, owner.Phone, message.Lead.Phone)
+	assert.Contains(t, message.Body, link)
+	assert.Contains(t, message.Body, "Imobiliária Sul")
+	assert.Contains(t, message.Body, "15 minutos")
+
+	provider.err = ErrMessagingNotConfigured
+	assert.ErrorIs(t, service.sendMagicLinkWhatsApp(context.Background(), tenant, owner, link), ErrMessagingNotConfigured)
+}
+
+func TestPortalTokenHash(t *testing.T) {
+	token, hash, err := generatePortalToken()
+	require.NoError(t, err)
+
+	other, otherHash, err := generatePortalToken()
+	require.NoError(t, err)
+
+	assert.Len(t, token, 43) // 32 random bytes, base64 URL without padding
+	assert.NotEqual(t, token, other)
+	assert.Equal(t, hashPortalToken(token), hash)
+	assert.NotEqual(t, hash, otherHash)
+	assert.NotContains(t, hash, token)
+}
+
+func TestNormalizeOwnerPhone(t *testing.T) {
+	expected := normalizeOwnerPhone("11987654321")
+
+	assert.Equal(t, expected, normalizeOwnerPhone("(11) 98765-4321"))
+	assert.Equal(t, expected, normalizeOwnerPhone("+55 11 98765-4321"))
+	assert.Equal(t, expected, normalizeOwnerPhone("5511987654321"))
+}
+
+func TestBuildOwnerPortalProfileMasksContactData(t *testing.T) {
+	profile := buildOwnerPortalProfile(&models.Owner{ID: "owner-1", Name: "Carlos", Email: "carlos.silva@example.com", Phone: "(11) 98765-4321"})
+
+	assert.Equal(t, "Carlos", profile.Name)
+	assert.NotEqual(t, "carlos.silva@example.com", profile.Email)
+	assert.NotContains(t, profile.Phone, "98765-4321")
+}
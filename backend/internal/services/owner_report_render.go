@@ -0,0 +1,197 @@
+package services
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/utils"
+)
+
+// ownerReportSection is a titled group of label/value rows shared by the HTML and PDF renderers
+type ownerReportSection struct {
+	Title string
+	Rows  []ownerReportRow
+}
+
+type ownerReportRow struct {
+	Label string
+	Value string
+}
+
+// leadChannelLabels translates lead channels for the owner
+var leadChannelLabels = map[string]string{
+	string(models.LeadChannelWhatsApp): "WhatsApp",
+	string(models.LeadChannelForm):     "Formulário",
+	string(models.LeadChannelPhone):    "Telefone",
+	string(models.LeadChannelEmail):    "E-mail",
+}
+
+// ownerReportTitle returns the report title (ex: "Relatório mensal AP00335 - 2026-09")
+func ownerReportTitle(report *models.OwnerReport) string {
+	title := "Relatório mensal do imóvel"
+	if report.PropertyReference != "" {
+		title = fmt.Sprintf("Relatório mensal - %s", report.PropertyReference)
+	}
+	return fmt.Sprintf("%s (%s)", title, report.Period)
+}
+
+// buildOwnerReportSections organizes the report in sections for rendering
+func buildOwnerReportSections(report *models.OwnerReport) []ownerReportSection {
+	location := strings.Trim(strings.Join([]string{report.Neighborhood, report.City}, ", "), ", ")
+
+	property := ownerReportSection{Title: "Imóvel", Rows: []ownerReportRow{
+		{"Localização", location},
+		{"Preço anunciado", formatBRL(report.PriceAmount)},
+	}}
+	if report.PricePerM2 > 0 {
+		property.Rows = append(property.Rows, ownerReportRow{"Preço por m²", formatBRL(report.PricePerM2)})
+	}
+	property.Rows = append(property.Rows, ownerReportRow{"Dias no mercado", strconv.Itoa(report.DaysOnMarket)})
+
+	engagement := ownerReportSection{Title: "Desempenho no mês", Rows: []ownerReportRow{
+		{"Visualizações do anúncio", strconv.FormatInt(report.Views, 10)},
+		{"Exibições em buscas", strconv.FormatInt(report.Impressions, 10)},
+		{"Cliques no WhatsApp", strconv.FormatInt(report.WhatsAppClicks, 10)},
+		{"Visitas realizadas", strconv.Itoa(report.Visits)},
+	}}
+
+	leads := ownerReportSection{Title: "Interessados (leads)", Rows: []ownerReportRow{
+		{"Total", strconv.Itoa(report.LeadsTotal)},
+	}}
+	channels := make([]string, 0, len(report.LeadsByChannel))
+	for channel := range report.LeadsByChannel {
+		channels = append(channels, channel)
+	}
+	sort.Strings(channels)
+	for _, channel := range channels {
+		label := leadChannelLabels[channel]
+		if label == "" {
+			label = channel
+		}
+		leads.Rows = append(leads.Rows, ownerReportRow{label, strconv.Itoa(report.LeadsByChannel[channel])})
+	}
+
+	market := ownerReportSection{Title: "Comparação com o bairro"}
+	if report.NeighborhoodMedianPricePerM2 > 0 {
+		market.Rows = append(market.Rows,
+			ownerReportRow{"Mediana do m² no bairro", formatBRL(report.NeighborhoodMedianPricePerM2)},
+			ownerReportRow{"Imóveis comparados", strconv.Itoa(report.NeighborhoodSampleSize)},
+		)
+		if report.PriceVsMedianPercent != nil {
+			market.Rows = append(market.Rows, ownerReportRow{"Seu preço vs. mediana", formatPercentDiff(*report.PriceVsMedianPercent)})
+		}
+	} else {
+		market.Rows = append(market.Rows, ownerReportRow{"Mediana do m² no bairro", "Dados insuficientes"})
+	}
+
+	return []ownerReportSection{property, engagement, leads, market}
+}
+
+// ownerReportTemplate is the standalone HTML version of the report (also embedded in the owner page)
+var ownerReportTemplate = template.Must(template.New("owner_report").Parse(`<!DOCTYPE html>
+<html lang="pt-BR">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>{{.Title}}</title>
+    <style>
+        body { font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, Arial, sans-serif; color: #333; background-color: #f4f4f4; margin: 0; padding: 0; }
+        .container { max-width: 600px; margin: 40px auto; background-color: #ffffff; border-radius: 8px; overflow: hidden; box-shadow: 0 2px 8px rgba(0,0,0,0.1); }
+        .header { background: linear-gradient(135deg, #667eea 0%, #764ba2 100%); color: white; padding: 30px; }
+        .header h1 { margin: 0; font-size: 22px; font-weight: 600; }
+        .content { padding: 20px 30px 30px; }
+        h2 { font-size: 16px; color: #667eea; margin: 24px 0 8px; }
+        table { width: 100%; border-collapse: collapse; }
+        td { padding: 8px 0; border-bottom: 1px solid #eee; font-size: 14px; }
+        td.value { text-align: right; font-weight: 600; }
+        .footer { padding: 20px 30px; font-size: 12px; color: #888; background-color: #f8f9fa; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header"><h1>{{.Title}}</h1></div>
+        <div class="content">
+            {{range .Sections}}
+            <h2>{{.Title}}</h2>
+            <table>
+                {{range .Rows}}<tr><td>{{.Label}}</td><td class="value">{{.Value}}</td></tr>
+                {{end}}
+            </table>
+            {{end}}
+        </div>
+        <div class="footer">Gerado em {{.GeneratedAt}}. Dados agregados, sem informações pessoais dos interessados.</div>
+    </div>
+</body>
+</html>
+`))
+
+// RenderOwnerReportHTML renders the report as a standalone HTML page
+func RenderOwnerReportHTML(w io.Writer, report *models.OwnerReport) error {
+	return ownerReportTemplate.Execute(w, map[string]interface{}{
+		"Title":       ownerReportTitle(report),
+		"Sections":    buildOwnerReportSections(report),
+		"GeneratedAt": report.GeneratedAt.In(metricsLocation).Format("02/01/2006 15:04"),
+	})
+}
+
+// WriteOwnerReportPDF renders the report as PDF
+func WriteOwnerReportPDF(w io.Writer, report *models.OwnerReport) error {
+	doc := utils.NewPDFDocument()
+	doc.Title(ownerReportTitle(report))
+
+	for _, section := range buildOwnerReportSections(report) {
+		doc.Heading(section.Title)
+		for _, row := range section.Rows {
+			doc.Text(fmt.Sprintf("%s: %s", row.Label, row.Value))
+		}
+	}
+
+	doc.Space(16)
+	doc.Text(fmt.Sprintf("Gerado em %s. Dados agregados, sem informações pessoais dos interessados.",
+		report.GeneratedAt.In(metricsLocation).Format("02/01/2006 15:04")))
+
+	_, err := doc.WriteTo(w)
+	return err
+}
+
+// formatBRL formats a value as Brazilian currency (ex: R$ 1.250.000,00)
+func formatBRL(value float64) string {
+	negative := value < 0
+	if negative {
+		value = -value
+	}
+
+	formatted := strconv.FormatFloat(value, 'f', 2, 64)
+	intPart, decPart := formatted[:len(formatted)-3], formatted[len(formatted)-2:]
+
+	var sb strings.Builder
+	for i, digit := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			sb.WriteByte('.')
+		}
+		sb.WriteRune(digit)
+	}
+
+	result := "R$ " + sb.String() + "," + decPart
+	if negative {
+		result = "-" + result
+	}
+	return result
+}
+
+// formatPercentDiff formats a percentage difference (ex: "+12,5% acima")
+func formatPercentDiff(diff float64) string {
+	value := strings.Replace(strconv.FormatFloat(diff, 'f', 1, 64), ".", ",", 1)
+	switch {
+	case diff > 0:
+		return "+" + value + "% acima"
+	case diff < 0:
+		return value + "% abaixo"
+	}
+	return "igual à mediana"
+}
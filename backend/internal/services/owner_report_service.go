@@ -0,0 +1,345 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// visitCompletedEvent is the activity event recorded when a broker completes a visit to a property
+const visitCompletedEvent = "property_visit_completed"
+
+// OwnerReportService generates the monthly performance report sent to property owners
+// (views, leads by channel, visits, price vs. neighborhood median, days on market)
+type OwnerReportService struct {
+	reportRepo      *repositories.OwnerReportRepository
+	propertyRepo    *repositories.PropertyRepository
+	leadRepo        *repositories.LeadRepository
+	snapshotRepo    *repositories.MarketSnapshotRepository
+	activityLogRepo *repositories.ActivityLogRepository
+	metricsService  *ListingMetricsService
+}
+
+// NewOwnerReportService creates a new owner report service
+func NewOwnerReportService(
+	reportRepo *repositories.OwnerReportRepository,
+	propertyRepo *repositories.PropertyRepository,
+	leadRepo *repositories.LeadRepository,
+	snapshotRepo *repositories.MarketSnapshotRepository,
+	activityLogRepo *repositories.ActivityLogRepository,
+	metricsService *ListingMetricsService,
+) *OwnerReportService {
+	return &OwnerReportService{
+		reportRepo:      reportRepo,
+		propertyRepo:    propertyRepo,
+		leadRepo:        leadRepo,
+		snapshotRepo:    snapshotRepo,
+		activityLogRepo: activityLogRepo,
+		metricsService:  metricsService,
+	}
+}
+
+// GenerateOwnerReportsResponse represents the result of a batch report generation
+type GenerateOwnerReportsResponse struct {
+	Period          string   `json:"period"`
+	TotalProperties int      `json:"total_properties"`
+	GeneratedCount  int      `json:"generated_count"`
+	SkippedCount    int      `json:"skipped_count"`
+	SkippedReasons  []string `json:"skipped_reasons,omitempty"`
+}
+
+// GenerateReport computes and stores the report of a property for the given period (YYYY-MM, empty = previous month)
+func (s *OwnerReportService) GenerateReport(ctx context.Context, tenantID, propertyID, period string) (*models.OwnerReport, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+	if propertyID == "" {
+		return nil, fmt.Errorf("property_id is required")
+	}
+
+	start, end, period, err := ParseSnapshotPeriod(period)
+	if err != nil {
+		return nil, err
+	}
+
+	property, err := s.propertyRepo.Get(ctx, tenantID, propertyID)
+	if err != nil {
+		return nil, fmt.Errorf("property not found: %w", err)
+	}
+
+	report := &models.OwnerReport{
+		TenantID:          tenantID,
+		PropertyID:        property.ID,
+		OwnerID:           property.OwnerID,
+		Period:            period,
+		PeriodStart:       start,
+		PeriodEnd:         end,
+		PropertyReference: property.Reference,
+		PropertyType:      property.PropertyType,
+		Neighborhood:      property.Neighborhood,
+		City:              property.City,
+		PriceAmount:       property.PriceAmount,
+		LeadsByChannel:    make(map[string]int),
+		GeneratedAt:       time.Now(),
+	}
+
+	if area := propertyArea(property); area > 0 && property.PriceAmount > 0 {
+		report.PricePerM2 = roundTo(property.PriceAmount/area, 2)
+	}
+
+	// Engajamento: rollups diários (datas ao meio-dia para não cruzar o fuso de Brasília)
+	if s.metricsService != nil {
+		performance, err := s.metricsService.GetPerformance(ctx, tenantID, models.MetricScopeProperty, property.ID,
+			start.Add(12*time.Hour), end.AddDate(0, 0, -1).Add(12*time.Hour))
+		if err != nil {
+			log.Printf("⚠️  Warning: failed to load metrics for property %s: %v", property.ID, err)
+		} else {
+			report.Views = performance.Views
+			report.Impressions = performance.Impressions
+			report.WhatsAppClicks = performance.WhatsAppClicks
+		}
+	}
+
+	if err := s.countLeads(ctx, report, start, end); err != nil {
+		return nil, err
+	}
+
+	visits, err := s.countVisits(ctx, tenantID, property.ID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	report.Visits = visits
+
+	s.compareWithMarket(ctx, report, property)
+
+	if !property.CreatedAt.IsZero() {
+		until := end
+		if now := time.Now(); now.Before(until) {
+			until = now
+		}
+		if days := int(until.Sub(property.CreatedAt).Hours() / 24); days > 0 {
+			report.DaysOnMarket = days
+		}
+	}
+
+	if err := s.reportRepo.Save(ctx, report); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// GenerateForTenant generates the reports of every owned property of a tenant
+// This should be run monthly, before the monthly confirmations are sent
+func (s *OwnerReportService) GenerateForTenant(ctx context.Context, tenantID, period string) (*GenerateOwnerReportsResponse, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+
+	_, _, period, err := ParseSnapshotPeriod(period)
+	if err != nil {
+		return nil, err
+	}
+
+	properties, err := s.propertyRepo.List(ctx, tenantID, &repositories.PropertyFilters{}, repositories.PaginationOptions{
+		Limit: 10000, // High limit to get all properties
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list properties: %w", err)
+	}
+
+	response := &GenerateOwnerReportsResponse{
+		Period:          period,
+		TotalProperties: len(properties),
+		SkippedReasons:  []string{},
+	}
+
+	for _, property := range properties {
+		if property.OwnerID == "" {
+			response.SkippedCount++
+			response.SkippedReasons = append(response.SkippedReasons, fmt.Sprintf("Property %s: no owner", property.Reference))
+			continue
+		}
+
+		if _, err := s.GenerateReport(ctx, tenantID, property.ID, period); err != nil {
+			log.Printf("❌ Failed to generate owner report for property %s: %v", property.Reference, err)
+			response.SkippedCount++
+			response.SkippedReasons = append(response.SkippedReasons, fmt.Sprintf("Property %s: failed to generate report", property.Reference))
+			continue
+		}
+
+		response.GeneratedCount++
+	}
+
+	log.Printf("📊 Owner reports %s: %d generated, %d skipped out of %d properties",
+		period, response.GeneratedCount, response.SkippedCount, response.TotalProperties)
+
+	return response, nil
+}
+
+// GetReport returns a stored report (latest when period is empty)
+func (s *OwnerReportService) GetReport(ctx context.Context, tenantID, propertyID, period string) (*models.OwnerReport, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+	if propertyID == "" {
+		return nil, fmt.Errorf("property_id is required")
+	}
+
+	if period == "" {
+		return s.reportRepo.GetLatestForProperty(ctx, tenantID, propertyID)
+	}
+	return s.reportRepo.Get(ctx, tenantID, propertyID, period)
+}
+
+// RecordVisit registers a completed visit to a property (counted in the owner report)
+func (s *OwnerReportService) RecordVisit(ctx context.Context, tenantID, propertyID, brokerID, leadID string, visitedAt time.Time) error {
+	if tenantID == "" {
+		return fmt.Errorf("tenant_id is required")
+	}
+	if propertyID == "" {
+		return fmt.Errorf("property_id is required")
+	}
+
+	if _, err := s.propertyRepo.Get(ctx, tenantID, propertyID); err != nil {
+		return fmt.Errorf("property not found: %w", err)
+	}
+
+	if visitedAt.IsZero() {
+		visitedAt = time.Now()
+	}
+
+	metadata := map[string]interface{}{
+		"property_id": propertyID,
+		"visited_at":  visitedAt,
+	}
+	if leadID != "" {
+		metadata["lead_id"] = leadID
+	}
+
+	entry := &models.ActivityLog{
+		TenantID:  tenantID,
+		EventType: visitCompletedEvent,
+		ActorType: models.ActorTypeUser,
+		ActorID:   brokerID,
+		Metadata:  metadata,
+		Timestamp: visitedAt,
+	}
+
+	if err := s.activityLogRepo.Create(ctx, entry); err != nil {
+		return fmt.Errorf("failed to record visit: %w", err)
+	}
+
+	return nil
+}
+
+// countLeads fills the lead totals of the report (by channel) for the period
+func (s *OwnerReportService) countLeads(ctx context.Context, report *models.OwnerReport, start, end time.Time) error {
+	leads, err := s.leadRepo.ListByProperty(ctx, report.TenantID, report.PropertyID, repositories.PaginationOptions{
+		Limit:     1000,
+		OrderBy:   "created_at",
+		Direction: firestore.Desc,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list leads: %w", err)
+	}
+
+	for _, lead := range leads {
+		if lead.CreatedAt.Before(start) || !lead.CreatedAt.Before(end) {
+			continue
+		}
+		report.LeadsTotal++
+		report.LeadsByChannel[string(lead.Channel)]++
+	}
+
+	return nil
+}
+
+// countVisits counts the visits recorded for a property within the period
+func (s *OwnerReportService) countVisits(ctx context.Context, tenantID, propertyID string, start, end time.Time) (int, error) {
+	filters := &repositories.ActivityLogFilters{
+		EventType: visitCompletedEvent,
+		StartDate: &start,
+		EndDate:   &end,
+	}
+	logs, err := s.activityLogRepo.List(ctx, tenantID, filters, repositories.PaginationOptions{
+		Limit:   10000,
+		OrderBy: "timestamp",
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to load visits: %w", err)
+	}
+
+	visits := 0
+	for _, entry := range logs {
+		if id, _ := entry.Metadata["property_id"].(string); id == propertyID {
+			visits++
+		}
+	}
+
+	return visits, nil
+}
+
+// compareWithMarket compares the price/m² with the neighborhood median of the market snapshot
+// Uses the tenant snapshot of the period, falling back to the anonymized marketplace snapshot
+func (s *OwnerReportService) compareWithMarket(ctx context.Context, report *models.OwnerReport, property *models.Property) {
+	if s.snapshotRepo == nil {
+		return
+	}
+
+	var segment *models.MarketSegmentStats
+	if snapshot, err := s.snapshotRepo.Get(ctx, report.TenantID, report.Period); err == nil {
+		segment = findMarketSegment(snapshot, property)
+		if segment != nil {
+			report.MarketSnapshotPeriod = snapshot.Period
+		}
+	}
+	if segment == nil {
+		if snapshot, err := s.snapshotRepo.GetMarketplace(ctx, report.Period); err == nil {
+			segment = findMarketSegment(snapshot, property)
+			if segment != nil {
+				report.MarketSnapshotPeriod = snapshot.Period
+			}
+		}
+	}
+	if segment == nil || segment.MedianPricePerM2 <= 0 {
+		return
+	}
+
+	report.NeighborhoodMedianPricePerM2 = segment.MedianPricePerM2
+	report.NeighborhoodSampleSize = segment.PriceSampleSize
+
+	if report.PricePerM2 > 0 {
+		diff := roundTo((report.PricePerM2/segment.MedianPricePerM2-1)*100, 1)
+		report.PriceVsMedianPercent = &diff
+	}
+}
+
+// findMarketSegment finds the neighborhood + property type segment of a property in a snapshot
+func findMarketSegment(snapshot *models.MarketSnapshot, property *models.Property) *models.MarketSegmentStats {
+	for i := range snapshot.Segments {
+		seg := &snapshot.Segments[i]
+		if seg.PropertyType == property.PropertyType &&
+			strings.EqualFold(seg.Neighborhood, strings.TrimSpace(property.Neighborhood)) &&
+			strings.EqualFold(seg.City, strings.TrimSpace(property.City)) &&
+			strings.EqualFold(seg.State, strings.TrimSpace(property.State)) {
+			return seg
+		}
+	}
+	return nil
+}
+
+// propertyArea returns the usable area, falling back to total area
+func propertyArea(p *models.Property) float64 {
+	if p.UsableArea > 0 {
+		return p.UsableArea
+	}
+	return p.TotalArea
+}
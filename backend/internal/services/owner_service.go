@@ -15,6 +15,7 @@ type OwnerService struct {
 	ownerRepo       *repositories.OwnerRepository
 	tenantRepo      *repositories.TenantRepository
 	activityLogRepo *repositories.ActivityLogRepository
+	consentService  *ConsentService // optional: consent ledger
 }
 
 // NewOwnerService creates a new owner service
@@ -332,6 +333,11 @@ func (s *OwnerService) ValidateDocument(owner *models.Owner) error {
 	return nil
 }
 
+// SetConsentService sets the consent ledger service (for dependency injection)
+func (s *OwnerService) SetConsentService(service *ConsentService) {
+	s.consentService = service
+}
+
 // RevokeConsent revokes owner consent (LGPD)
 func (s *OwnerService) RevokeConsent(ctx context.Context, tenantID, id string) error {
 	if tenantID == "" {
@@ -351,6 +357,20 @@ func (s *OwnerService) RevokeConsent(ctx context.Context, tenantID, id string) e
 		return fmt.Errorf("failed to revoke consent: %w", err)
 	}
 
+	// LGPD: Revoking the owner consent withdraws every purpose in the ledger
+	if s.consentService != nil {
+		if err := s.consentService.WithdrawAll(ctx, RecordConsentInput{
+			TenantID:    tenantID,
+			SubjectType: models.ConsentSubjectOwner,
+			SubjectID:   id,
+			Origin:      ConsentOriginAPI,
+			ActorType:   models.ActorTypeOwner,
+			ActorID:     id,
+		}); err != nil {
+			return fmt.Errorf("failed to record consent withdrawal: %w", err)
+		}
+	}
+
 	// Log activity
 	_ = s.logActivity(ctx, tenantID, "owner_consent_revoked", models.ActorTypeOwner, id, map[string]interface{}{
 		"owner_id": id,
@@ -475,5 +495,5 @@ func (s *OwnerService) logActivity(ctx context.Context, tenantID, eventType stri
 		Timestamp: time.Now(),
 	}
 
-	return s.activityLogRepo.Create(ctx, log)
+	return appendActivityLog(ctx, s.activityLogRepo, log)
 }
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+)
+
+// ErrChargeNotFound is returned when a gateway charge does not exist
+var ErrChargeNotFound = errors.New("charge not found")
+
+// ChargeStatus defines the status of a gateway charge
+type ChargeStatus string
+
+const (
+	ChargeStatusPending ChargeStatus = "pending" // PIX aguardando pagamento
+	ChargeStatusPaid    ChargeStatus = "paid"
+	ChargeStatusFailed  ChargeStatus = "failed" // cartão recusado, PIX expirado
+)
+
+// ChargeRequest is a charge of an invoice
+type ChargeRequest struct {
+	TenantID      string
+	InvoiceID     string
+	Description   string
+	AmountCents   int64
+	Currency      string
+	Method        models.PaymentMethod
+	CardToken     string // tokenized card (card charges)
+	CustomerEmail string
+}
+
+// Charge is the result of a gateway charge
+type Charge struct {
+	ID            string
+	Status        ChargeStatus
+	Method        models.PaymentMethod
+	AmountCents   int64
+	PixCopyPaste  string     // PIX "copia e cola" (pending PIX charges)
+	ExpiresAt     *time.Time // PIX expiry
+	FailureReason string
+}
+
+// PaymentGateway charges invoices by card or PIX
+// Card charges settle synchronously; PIX charges stay pending until paid (see GetCharge)
+// Implemented by FakePaymentGateway (local development); production gateways plug in here
+type PaymentGateway interface {
+	CreateCharge(ctx context.Context, req ChargeRequest) (*Charge, error)
+	GetCharge(ctx context.Context, chargeID string) (*Charge, error)
+}
+
+// fakePixTTL is how long a fake PIX charge can be paid
+const fakePixTTL = 24 * time.Hour
+
+// FakePaymentGateway is an in-memory PaymentGateway for local development and tests
+// Card tokens starting with "tok_fail" are declined; PIX charges are paid with MarkPaid
+type FakePaymentGateway struct {
+	charges map[string]*Charge
+	mu      sync.Mutex
+}
+
+// NewFakePaymentGateway creates an in-memory payment gateway
+func NewFakePaymentGateway() *FakePaymentGateway {
+	return &FakePaymentGateway{
+		charges: make(map[string]*Charge),
+	}
+}
+
+// CreateCharge creates a charge
+func (g *FakePaymentGateway) CreateCharge(ctx context.Context, req ChargeRequest) (*Charge, error) {
+	if req.AmountCents <= 0 {
+		return nil, fmt.Errorf("amount must be positive")
+	}
+
+	charge := &Charge{
+		ID:          "ch_" + strings.ReplaceAll(uuid.New().String(), "-", ""),
+		Method:      req.Method,
+		AmountCents: req.AmountCents,
+	}
+
+	switch req.Method {
+	case models.PaymentMethodCard:
+		if req.CardToken == "" {
+			return nil, fmt.Errorf("card_token is required for card charges")
+		}
+		if strings.HasPrefix(req.CardToken, "tok_fail") {
+			charge.Status = ChargeStatusFailed
+			charge.FailureReason = "card_declined"
+		} else {
+			charge.Status = ChargeStatusPaid
+		}
+	case models.PaymentMethodPix:
+		expiresAt := time.Now().Add(fakePixTTL)
+		charge.Status = ChargeStatusPending
+		charge.ExpiresAt = &expiresAt
+		charge.PixCopyPaste = fmt.Sprintf("00020126580014BR.GOV.BCB.PIX0136%s5204000053039865406%.2f5802BR6304FAKE", charge.ID, float64(req.AmountCents)/100)
+	default:
+		return nil, fmt.Errorf("unsupported payment method: %s", req.Method)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.charges[charge.ID] = charge
+
+	copied := *charge
+	return &copied, nil
+}
+
+// GetCharge returns the current status of a charge (pending PIX charges fail once expired)
+func (g *FakePaymentGateway) GetCharge(ctx context.Context, chargeID string) (*Charge, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	charge, exists := g.charges[chargeID]
+	if !exists {
+		return nil, ErrChargeNotFound
+	}
+	if charge.Status == ChargeStatusPending && charge.ExpiresAt != nil && time.Now().After(*charge.ExpiresAt) {
+		charge.Status = ChargeStatusFailed
+		charge.FailureReason = "pix_expired"
+	}
+
+	copied := *charge
+	return &copied, nil
+}
+
+// MarkPaid simulates the payment of a pending PIX charge
+func (g *FakePaymentGateway) MarkPaid(chargeID string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	charge, exists := g.charges[chargeID]
+	if !exists {
+		return ErrChargeNotFound
+	}
+	charge.Status = ChargeStatusPaid
+	return nil
+}
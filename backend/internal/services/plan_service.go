@@ -0,0 +1,239 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// ErrPlanLimitReached is returned (wrapped in a PlanLimitError) when an operation exceeds the tenant's plan
+var ErrPlanLimitReached = errors.New("plan limit reached")
+
+// PlanLimitError describes the quota or feature of the plan that blocked an operation
+type PlanLimitError struct {
+	Plan     string `json:"plan"`
+	Resource string `json:"resource,omitempty"` // quota that was reached (models.PlanResource*)
+	Limit    int    `json:"limit,omitempty"`
+	Feature  string `json:"feature,omitempty"` // feature not included in the plan (models.PlanFeature*)
+}
+
+// Error implements error
+func (e *PlanLimitError) Error() string {
+	if e.Feature != "" {
+		return fmt.Sprintf("feature '%s' is not available on the %s plan", e.Feature, e.Plan)
+	}
+	return fmt.Sprintf("%s limit of the %s plan reached (%d)", e.Resource, e.Plan, e.Limit)
+}
+
+// Unwrap makes errors.Is(err, ErrPlanLimitReached) match
+func (e *PlanLimitError) Unwrap() error {
+	return ErrPlanLimitReached
+}
+
+// PlanUsage is the plan of a tenant with its current usage
+type PlanUsage struct {
+	Plan               models.Plan `json:"plan"`
+	SubscriptionPlan   string      `json:"subscription_plan"` // contracted plan (Plan is the effective one)
+	SubscriptionStatus string      `json:"subscription_status"`
+	TrialEndsAt        *time.Time  `json:"trial_ends_at,omitempty"`
+	Properties         int         `json:"properties"`
+	Brokers            int         `json:"brokers"`
+	ImportsThisMonth   int         `json:"imports_this_month"`
+}
+
+// PlanService enforces the quotas and features of subscription plans and runs the trial lifecycle
+// Quotas come from the tenant's effective plan (models.Tenant.EffectivePlan)
+type PlanService struct {
+	tenantRepo      *repositories.TenantRepository
+	propertyRepo    *repositories.PropertyRepository
+	brokerRepo      *repositories.BrokerRepository
+	activityLogRepo *repositories.ActivityLogRepository
+	importService   *ImportService // Optional: monthly import usage
+}
+
+// NewPlanService creates a new plan service
+func NewPlanService(
+	tenantRepo *repositories.TenantRepository,
+	propertyRepo *repositories.PropertyRepository,
+	brokerRepo *repositories.BrokerRepository,
+	activityLogRepo *repositories.ActivityLogRepository,
+) *PlanService {
+	return &PlanService{
+		tenantRepo:      tenantRepo,
+		propertyRepo:    propertyRepo,
+		brokerRepo:      brokerRepo,
+		activityLogRepo: activityLogRepo,
+	}
+}
+
+// SetImportService sets the import service used to report monthly imports (for dependency injection)
+func (s *PlanService) SetImportService(service *ImportService) {
+	s.importService = service
+}
+
+// GetTenantPlan returns the effective plan of a tenant
+func (s *PlanService) GetTenantPlan(ctx context.Context, tenantID string) (models.Plan, error) {
+	if tenantID == "" {
+		return models.Plan{}, fmt.Errorf("tenant_id is required")
+	}
+
+	tenant, err := s.tenantRepo.Get(ctx, tenantID)
+	if err != nil {
+		return models.Plan{}, fmt.Errorf("tenant not found: %w", err)
+	}
+
+	return tenant.EffectivePlan(time.Now()), nil
+}
+
+// CheckLimit checks if adding resources on top of the used ones stays within the tenant's plan
+func (s *PlanService) CheckLimit(ctx context.Context, tenantID, resource string, used, adding int) error {
+	plan, err := s.GetTenantPlan(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+
+	return checkPlanLimit(plan, resource, used, adding)
+}
+
+// CheckFeature checks if the tenant's plan includes a feature
+func (s *PlanService) CheckFeature(ctx context.Context, tenantID, feature string) error {
+	plan, err := s.GetTenantPlan(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+
+	if !plan.HasFeature(feature) {
+		return &PlanLimitError{Plan: plan.ID, Feature: feature}
+	}
+	return nil
+}
+
+// CheckPropertyQuota checks if the tenant can create more properties
+func (s *PlanService) CheckPropertyQuota(ctx context.Context, tenantID string, adding int) error {
+	plan, err := s.GetTenantPlan(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	if plan.MaxProperties == 0 {
+		return nil
+	}
+
+	count, err := s.propertyRepo.Count(ctx, tenantID, nil)
+	if err != nil {
+		return fmt.Errorf("failed to count properties: %w", err)
+	}
+
+	return checkPlanLimit(plan, models.PlanResourceProperties, count, adding)
+}
+
+// CheckBrokerQuota checks if the tenant can add another active broker
+func (s *PlanService) CheckBrokerQuota(ctx context.Context, tenantID string) error {
+	plan, err := s.GetTenantPlan(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	if plan.MaxBrokers == 0 {
+		return nil
+	}
+
+	count, err := s.brokerRepo.CountActive(ctx, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to count brokers: %w", err)
+	}
+
+	return checkPlanLimit(plan, models.PlanResourceBrokers, count, 1)
+}
+
+// CheckPhotoQuota checks if a listing with the given number of photos fits the tenant's plan
+func (s *PlanService) CheckPhotoQuota(ctx context.Context, tenantID string, photos int) error {
+	return s.CheckLimit(ctx, tenantID, models.PlanResourcePhotosPerListing, 0, photos)
+}
+
+// GetUsage returns the effective plan of a tenant with its current usage
+func (s *PlanService) GetUsage(ctx context.Context, tenantID string) (*PlanUsage, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+
+	tenant, err := s.tenantRepo.Get(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("tenant not found: %w", err)
+	}
+
+	now := time.Now()
+	usage := &PlanUsage{
+		Plan:               tenant.EffectivePlan(now),
+		SubscriptionPlan:   tenant.SubscriptionPlan,
+		SubscriptionStatus: tenant.SubscriptionStatus,
+		TrialEndsAt:        tenant.TrialEndsAt,
+	}
+
+	if usage.Properties, err = s.propertyRepo.Count(ctx, tenantID, nil); err != nil {
+		return nil, fmt.Errorf("failed to count properties: %w", err)
+	}
+	if usage.Brokers, err = s.brokerRepo.CountActive(ctx, tenantID); err != nil {
+		return nil, fmt.Errorf("failed to count brokers: %w", err)
+	}
+	if s.importService != nil {
+		if usage.ImportsThisMonth, err = s.importService.CountMonthlyBatches(ctx, tenantID, now); err != nil {
+			return nil, fmt.Errorf("failed to count imports: %w", err)
+		}
+	}
+
+	return usage, nil
+}
+
+// ExpireTrials moves the tenants whose trial ended to the expired status (free plan quotas)
+// Returns the number of expired trials
+func (s *PlanService) ExpireTrials(ctx context.Context) (int, error) {
+	now := time.Now()
+
+	tenants, err := s.tenantRepo.ListTrialsEndingBefore(ctx, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list ended trials: %w", err)
+	}
+
+	expired := 0
+	for _, tenant := range tenants {
+		if err := s.tenantRepo.Update(ctx, tenant.ID, map[string]interface{}{
+			"subscription_status": models.SubscriptionStatusExpired,
+		}); err != nil {
+			log.Printf("Failed to expire trial of tenant %s: %v", tenant.ID, err)
+			continue
+		}
+		expired++
+
+		_ = s.logActivity(ctx, tenant.ID, "subscription_trial_expired", map[string]interface{}{
+			"subscription_plan": tenant.SubscriptionPlan,
+			"trial_ends_at":     tenant.TrialEndsAt,
+		})
+	}
+
+	return expired, nil
+}
+
+// checkPlanLimit returns a PlanLimitError when the quota of a resource would be exceeded
+func checkPlanLimit(plan models.Plan, resource string, used, adding int) error {
+	if !plan.Allows(resource, used, adding) {
+		return &PlanLimitError{Plan: plan.ID, Resource: resource, Limit: plan.Limit(resource)}
+	}
+	return nil
+}
+
+// logActivity logs a subscription event
+func (s *PlanService) logActivity(ctx context.Context, tenantID, eventType string, metadata map[string]interface{}) error {
+	log := &models.ActivityLog{
+		TenantID:  tenantID,
+		EventType: eventType,
+		ActorType: models.ActorTypeSystem,
+		Metadata:  metadata,
+		Timestamp: time.Now(),
+	}
+
+	return appendActivityLog(ctx, s.activityLogRepo, log)
+}
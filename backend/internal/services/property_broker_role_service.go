@@ -386,5 +386,5 @@ func (s *PropertyBrokerRoleService) logActivity(ctx context.Context, tenantID, e
 		Timestamp: time.Now(),
 	}
 
-	return s.activityLogRepo.Create(ctx, log)
+	return appendActivityLog(ctx, s.activityLogRepo, log)
 }
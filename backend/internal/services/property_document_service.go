@@ -0,0 +1,481 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"mime/multipart"
+	"strings"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/storage"
+)
+
+// documentCategoryLabels translates document categories for display
+var documentCategoryLabels = map[models.DocumentCategory]string{
+	models.DocumentCategoryMatricula:            "Matrícula atualizada",
+	models.DocumentCategoryIPTU:                 "Carnê de IPTU",
+	models.DocumentCategoryHabiteSe:             "Habite-se",
+	models.DocumentCategoryCondominiumStatement: "Declaração de quitação condominial",
+	models.DocumentCategoryOwnerID:              "Documento de identidade do proprietário",
+	models.DocumentCategorySaleAuthorization:    "Autorização de venda assinada",
+	models.DocumentCategoryOther:                "Outros",
+}
+
+// dueDiligenceChecklists lists the documents required to close a sale, per property type
+var dueDiligenceChecklists = map[models.PropertyType][]models.DocumentCategory{
+	models.PropertyTypeApartment: {
+		models.DocumentCategoryMatricula,
+		models.DocumentCategoryIPTU,
+		models.DocumentCategoryCondominiumStatement,
+		models.DocumentCategoryOwnerID,
+		models.DocumentCategorySaleAuthorization,
+	},
+	models.PropertyTypeHouse: {
+		models.DocumentCategoryMatricula,
+		models.DocumentCategoryIPTU,
+		models.DocumentCategoryHabiteSe,
+		models.DocumentCategoryOwnerID,
+		models.DocumentCategorySaleAuthorization,
+	},
+	models.PropertyTypeCommercial: {
+		models.DocumentCategoryMatricula,
+		models.DocumentCategoryIPTU,
+		models.DocumentCategoryHabiteSe,
+		models.DocumentCategoryOwnerID,
+		models.DocumentCategorySaleAuthorization,
+	},
+	models.PropertyTypeCondoLot: {
+		models.DocumentCategoryMatricula,
+		models.DocumentCategoryIPTU,
+		models.DocumentCategoryCondominiumStatement,
+		models.DocumentCategoryOwnerID,
+		models.DocumentCategorySaleAuthorization,
+	},
+}
+
+// defaultDueDiligenceChecklist applies to property types without a specific checklist (land, lots...)
+var defaultDueDiligenceChecklist = []models.DocumentCategory{
+	models.DocumentCategoryMatricula,
+	models.DocumentCategoryIPTU,
+	models.DocumentCategoryOwnerID,
+	models.DocumentCategorySaleAuthorization,
+}
+
+// PropertyDocumentStore is the document persistence used by PropertyDocumentService
+// (implemented by repositories.PropertyDocumentRepository)
+type PropertyDocumentStore interface {
+	Create(ctx context.Context, document *models.PropertyDocument) error
+	Get(ctx context.Context, tenantID, id string) (*models.PropertyDocument, error)
+	ListByProperty(ctx context.Context, tenantID, propertyID string) ([]*models.PropertyDocument, error)
+	Update(ctx context.Context, tenantID, id string, updates map[string]interface{}) error
+	Delete(ctx context.Context, tenantID, id string) error
+	ListExpiringBefore(ctx context.Context, tenantID string, before time.Time) ([]*models.PropertyDocument, error)
+}
+
+// PropertyGetter reads properties (implemented by repositories.PropertyRepository)
+type PropertyGetter interface {
+	Get(ctx context.Context, tenantID, id string) (*models.Property, error)
+}
+
+// PropertyDocumentStorage keeps the private document objects (implemented by storage.StorageService)
+type PropertyDocumentStorage interface {
+	UploadPropertyDocument(ctx context.Context, tenantID, propertyID string, file multipart.File, header *multipart.FileHeader) (string, error)
+	GetPropertyDocumentURL(ctx context.Context, storagePath string) (string, time.Time, error)
+	DeletePropertyDocument(ctx context.Context, storagePath string) error
+}
+
+// PropertyDocumentService handles the property document vault (private files, expiry and checklist)
+type PropertyDocumentService struct {
+	documentRepo    PropertyDocumentStore
+	propertyRepo    PropertyGetter
+	activityLogRepo ActivityLogWriter
+	storageService  PropertyDocumentStorage // Optional: uploads and downloads are disabled without storage
+}
+
+// NewPropertyDocumentService creates a new property document service
+func NewPropertyDocumentService(
+	documentRepo PropertyDocumentStore,
+	propertyRepo PropertyGetter,
+	activityLogRepo ActivityLogWriter,
+	storageService *storage.StorageService,
+) *PropertyDocumentService {
+	service := &PropertyDocumentService{
+		documentRepo:    documentRepo,
+		propertyRepo:    propertyRepo,
+		activityLogRepo: activityLogRepo,
+	}
+	if storageService != nil {
+		service.storageService = storageService
+	}
+	return service
+}
+
+// UploadPropertyDocumentInput represents a document upload
+type UploadPropertyDocumentInput struct {
+	TenantID       string
+	PropertyID     string
+	OwnerID        string
+	Category       models.DocumentCategory
+	Description    string
+	ExpiresAt      *time.Time
+	File           multipart.File
+	Header         *multipart.FileHeader
+	UploadedByType models.ActorType
+	UploadedByID   string
+}
+
+// DocumentURLResponse is a short-lived signed URL to download a document
+type DocumentURLResponse struct {
+	DocumentID string    `json:"document_id"`
+	URL        string    `json:"url"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// DocumentChecklistItem is one required document of the due-diligence checklist
+type DocumentChecklistItem struct {
+	Category   models.DocumentCategory `json:"category"`
+	Label      string                  `json:"label"`
+	Status     string                  `json:"status"` // ok, missing, expired
+	DocumentID string                  `json:"document_id,omitempty"`
+	ExpiresAt  *time.Time              `json:"expires_at,omitempty"`
+}
+
+// DocumentChecklist shows which documents are still missing before a sale can close
+type DocumentChecklist struct {
+	PropertyID   string                    `json:"property_id"`
+	PropertyType models.PropertyType       `json:"property_type"`
+	Items        []DocumentChecklistItem   `json:"items"`
+	Missing      []models.DocumentCategory `json:"missing"`
+	Complete     bool                      `json:"complete"`
+}
+
+// UploadDocument stores a private document and records it in the vault
+func (s *PropertyDocumentService) UploadDocument(ctx context.Context, input UploadPropertyDocumentInput) (*models.PropertyDocument, error) {
+	if input.TenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+	if input.PropertyID == "" {
+		return nil, fmt.Errorf("property_id is required")
+	}
+	if input.Category == "" {
+		input.Category = models.DocumentCategoryOther
+	}
+	if err := validateDocumentCategory(input.Category); err != nil {
+		return nil, err
+	}
+	if s.storageService == nil {
+		return nil, fmt.Errorf("document storage is not configured")
+	}
+
+	property, err := s.propertyRepo.Get(ctx, input.TenantID, input.PropertyID)
+	if err != nil {
+		return nil, fmt.Errorf("property not found: %w", err)
+	}
+
+	storagePath, err := s.storageService.UploadPropertyDocument(ctx, input.TenantID, property.ID, input.File, input.Header)
+	if err != nil {
+		return nil, err
+	}
+
+	ownerID := input.OwnerID
+	if ownerID == "" {
+		ownerID = property.OwnerID
+	}
+
+	document := &models.PropertyDocument{
+		TenantID:       input.TenantID,
+		PropertyID:     property.ID,
+		OwnerID:        ownerID,
+		Category:       input.Category,
+		Description:    input.Description,
+		ExpiresAt:      input.ExpiresAt,
+		FileName:       input.Header.Filename,
+		ContentType:    input.Header.Header.Get("Content-Type"),
+		Size:           input.Header.Size,
+		StoragePath:    storagePath,
+		UploadedByType: input.UploadedByType,
+		UploadedByID:   input.UploadedByID,
+	}
+	if err := s.documentRepo.Create(ctx, document); err != nil {
+		return nil, err
+	}
+
+	_ = s.logActivity(ctx, input.TenantID, "property_document_uploaded", input.UploadedByType, input.UploadedByID, map[string]interface{}{
+		"property_id":  property.ID,
+		"document_id":  document.ID,
+		"category":     document.Category,
+		"file_name":    document.FileName,
+		"content_type": document.ContentType,
+		"size":         document.Size,
+	})
+
+	return document, nil
+}
+
+// GetDocument retrieves a document record
+func (s *PropertyDocumentService) GetDocument(ctx context.Context, tenantID, id string) (*models.PropertyDocument, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+	if id == "" {
+		return nil, fmt.Errorf("document ID is required")
+	}
+
+	return s.documentRepo.Get(ctx, tenantID, id)
+}
+
+// ListDocuments lists the documents of a property (optionally filtered by category)
+func (s *PropertyDocumentService) ListDocuments(ctx context.Context, tenantID, propertyID string, category models.DocumentCategory) ([]*models.PropertyDocument, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+	if propertyID == "" {
+		return nil, fmt.Errorf("property_id is required")
+	}
+
+	documents, err := s.documentRepo.ListByProperty(ctx, tenantID, propertyID)
+	if err != nil {
+		return nil, err
+	}
+	if category == "" {
+		return documents, nil
+	}
+
+	filtered := make([]*models.PropertyDocument, 0, len(documents))
+	for _, document := range documents {
+		if document.Category == category {
+			filtered = append(filtered, document)
+		}
+	}
+	return filtered, nil
+}
+
+// ListExpiring lists the documents of the tenant that expire within the given number of days (including expired ones)
+func (s *PropertyDocumentService) ListExpiring(ctx context.Context, tenantID string, days int) ([]*models.PropertyDocument, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+	if days <= 0 {
+		days = 30
+	}
+
+	return s.documentRepo.ListExpiringBefore(ctx, tenantID, time.Now().AddDate(0, 0, days))
+}
+
+// GetDownloadURL generates a short-lived signed URL for a document (every access is logged)
+func (s *PropertyDocumentService) GetDownloadURL(ctx context.Context, document *models.PropertyDocument, actorType models.ActorType, actorID string) (*DocumentURLResponse, error) {
+	if s.storageService == nil {
+		return nil, fmt.Errorf("document storage is not configured")
+	}
+
+	// Only objects under the document's own tenant and property are signed (a record pointing
+	// elsewhere must not become a download of another tenant's file)
+	if !strings.HasPrefix(document.StoragePath, propertyDocumentStoragePrefix(document.TenantID, document.PropertyID)) {
+		return nil, repositories.ErrNotFound
+	}
+
+	url, expiresAt, err := s.storageService.GetPropertyDocumentURL(ctx, document.StoragePath)
+	if err != nil {
+		return nil, err
+	}
+
+	_ = s.logActivity(ctx, document.TenantID, "property_document_accessed", actorType, actorID, map[string]interface{}{
+		"property_id": document.PropertyID,
+		"document_id": document.ID,
+		"category":    document.Category,
+	})
+
+	return &DocumentURLResponse{
+		DocumentID: document.ID,
+		URL:        url,
+		ExpiresAt:  expiresAt,
+	}, nil
+}
+
+// UpdateDocument updates the classification or expiry date of a document
+func (s *PropertyDocumentService) UpdateDocument(ctx context.Context, tenantID, id string, updates map[string]interface{}, actorID string) error {
+	document, err := s.GetDocument(ctx, tenantID, id)
+	if err != nil {
+		return err
+	}
+
+	allowed := map[string]bool{"category": true, "description": true, "expires_at": true}
+	for key := range updates {
+		if !allowed[key] {
+			return fmt.Errorf("field %s cannot be updated", key)
+		}
+	}
+	if value, ok := updates["expires_at"]; ok {
+		expiresAt, err := ParseDocumentExpiry(fmt.Sprint(value))
+		if value == nil {
+			expiresAt, err = nil, nil // remove expiry
+		}
+		if err != nil {
+			return err
+		}
+		updates["expires_at"] = expiresAt
+	}
+	if category, ok := updates["category"]; ok {
+		value := models.DocumentCategory(fmt.Sprint(category))
+		if err := validateDocumentCategory(value); err != nil {
+			return err
+		}
+		updates["category"] = value
+	}
+
+	if err := s.documentRepo.Update(ctx, tenantID, document.ID, updates); err != nil {
+		return err
+	}
+
+	_ = s.logActivity(ctx, tenantID, "property_document_updated", models.ActorTypeUser, actorID, map[string]interface{}{
+		"property_id": document.PropertyID,
+		"document_id": document.ID,
+		"updates":     updates,
+	})
+
+	return nil
+}
+
+// DeleteDocument removes a document (private object and record)
+func (s *PropertyDocumentService) DeleteDocument(ctx context.Context, tenantID, id, actorID string) error {
+	document, err := s.GetDocument(ctx, tenantID, id)
+	if err != nil {
+		return err
+	}
+
+	if s.storageService != nil {
+		if err := s.storageService.DeletePropertyDocument(ctx, document.StoragePath); err != nil && err != storage.ErrDocumentNotFound {
+			return err
+		}
+	}
+
+	if err := s.documentRepo.Delete(ctx, tenantID, document.ID); err != nil {
+		return err
+	}
+
+	_ = s.logActivity(ctx, tenantID, "property_document_deleted", models.ActorTypeUser, actorID, map[string]interface{}{
+		"property_id": document.PropertyID,
+		"document_id": document.ID,
+		"category":    document.Category,
+	})
+
+	return nil
+}
+
+// GetChecklist builds the due-diligence checklist of a property (per property type)
+func (s *PropertyDocumentService) GetChecklist(ctx context.Context, tenantID, propertyID string) (*DocumentChecklist, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+	if propertyID == "" {
+		return nil, fmt.Errorf("property_id is required")
+	}
+
+	property, err := s.propertyRepo.Get(ctx, tenantID, propertyID)
+	if err != nil {
+		return nil, fmt.Errorf("property not found: %w", err)
+	}
+
+	documents, err := s.documentRepo.ListByProperty(ctx, tenantID, property.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildDocumentChecklist(property, documents, time.Now()), nil
+}
+
+// buildDocumentChecklist matches the required categories with the most recent valid document of each one
+func buildDocumentChecklist(property *models.Property, documents []*models.PropertyDocument, now time.Time) *DocumentChecklist {
+	required, ok := dueDiligenceChecklists[property.PropertyType]
+	if !ok {
+		required = defaultDueDiligenceChecklist
+	}
+
+	checklist := &DocumentChecklist{
+		PropertyID:   property.ID,
+		PropertyType: property.PropertyType,
+		Items:        make([]DocumentChecklistItem, 0, len(required)),
+		Missing:      []models.DocumentCategory{},
+	}
+
+	for _, category := range required {
+		item := DocumentChecklistItem{
+			Category: category,
+			Label:    documentCategoryLabels[category],
+			Status:   "missing",
+		}
+
+		// Documents are newest first: prefer the newest valid one, otherwise report the newest expired
+		for _, document := range documents {
+			if document.Category != category {
+				continue
+			}
+			if !document.IsExpired(now) {
+				item.Status = "ok"
+				item.DocumentID = document.ID
+				item.ExpiresAt = document.ExpiresAt
+				break
+			}
+			if item.Status == "missing" {
+				item.Status = "expired"
+				item.DocumentID = document.ID
+				item.ExpiresAt = document.ExpiresAt
+			}
+		}
+
+		if item.Status != "ok" {
+			checklist.Missing = append(checklist.Missing, category)
+		}
+		checklist.Items = append(checklist.Items, item)
+	}
+
+	checklist.Complete = len(checklist.Missing) == 0
+	return checklist
+}
+
+// ParseDocumentExpiry parses an expiry date (YYYY-MM-DD or RFC3339); empty means no expiry
+// Dates without time expire at the end of the day (Brasília)
+func ParseDocumentExpiry(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	day, err := time.ParseInLocation("2006-01-02", value, metricsLocation)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expires_at, expected YYYY-MM-DD")
+	}
+	endOfDay := day.AddDate(0, 0, 1).Add(-time.Second)
+	return &endOfDay, nil
+}
+
+// propertyDocumentStoragePrefix is the object prefix of the documents of a property
+// (see storage.StorageService.UploadPropertyDocument)
+func propertyDocumentStoragePrefix(tenantID, propertyID string) string {
+	return fmt.Sprintf("documents/%s/%s/", tenantID, propertyID)
+}
+
+// validateDocumentCategory validates a document category
+func validateDocumentCategory(category models.DocumentCategory) error {
+	if _, ok := documentCategoryLabels[category]; !ok {
+		return fmt.Errorf("invalid document category")
+	}
+	return nil
+}
+
+// logActivity logs a document event (helper method)
+func (s *PropertyDocumentService) logActivity(ctx context.Context, tenantID, eventType string, actorType models.ActorType, actorID string, metadata map[string]interface{}) error {
+	log := &models.ActivityLog{
+		TenantID:  tenantID,
+		EventType: eventType,
+		ActorType: actorType,
+		ActorID:   actorID,
+		Metadata:  metadata,
+		Timestamp: time.Now(),
+	}
+
+	return appendActivityLog(ctx, s.activityLogRepo, log)
+}
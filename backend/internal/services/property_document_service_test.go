@@ -0,0 +1,241 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"mime/multipart"
+	"net/textproto"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// fakePropertyDocumentStore keeps documents in memory, per tenant
+type fakePropertyDocumentStore struct {
+	documents map[string]map[string]*models.PropertyDocument // tenant ID -> document ID -> document
+	nextID    int
+}
+
+func newFakePropertyDocumentStore() *fakePropertyDocumentStore {
+	return &fakePropertyDocumentStore{documents: make(map[string]map[string]*models.PropertyDocument)}
+}
+
+func (f *fakePropertyDocumentStore) Create(ctx context.Context, document *models.PropertyDocument) error {
+	f.nextID++
+	document.ID = fmt.Sprintf("doc-%d", f.nextID)
+	if f.documents[document.TenantID] == nil {
+		f.documents[document.TenantID] = make(map[string]*models.PropertyDocument)
+	}
+	f.documents[document.TenantID][document.ID] = document
+	return nil
+}
+
+func (f *fakePropertyDocumentStore) Get(ctx context.Context, tenantID, id string) (*models.PropertyDocument, error) {
+	document, ok := f.documents[tenantID][id]
+	if !ok {
+		return nil, repositories.ErrNotFound
+	}
+	return document, nil
+}
+
+func (f *fakePropertyDocumentStore) ListByProperty(ctx context.Context, tenantID, propertyID string) ([]*models.PropertyDocument, error) {
+	var documents []*models.PropertyDocument
+	for _, document := range f.documents[tenantID] {
+		if document.PropertyID == propertyID {
+			documents = append(documents, document)
+		}
+	}
+	return documents, nil
+}
+
+func (f *fakePropertyDocumentStore) Update(ctx context.Context, tenantID, id string, updates map[string]interface{}) error {
+	return nil
+}
+
+func (f *fakePropertyDocumentStore) Delete(ctx context.Context, tenantID, id string) error {
+	delete(f.documents[tenantID], id)
+	return nil
+}
+
+func (f *fakePropertyDocumentStore) ListExpiringBefore(ctx context.Context, tenantID string, before time.Time) ([]*models.PropertyDocument, error) {
+	return nil, nil
+}
+
+// fakePropertyGetter returns properties by tenant and ID
+type fakePropertyGetter map[string]*models.Property // tenant ID + "/" + property ID -> property
+
+func (f fakePropertyGetter) Get(ctx context.Context, tenantID, id string) (*models.Property, error) {
+	property, ok := f[tenantID+"/"+id]
+	if !ok {
+		return nil, repositories.ErrNotFound
+	}
+	return property, nil
+}
+
+// fakeDocumentStorage records uploads and signs URLs for the stored objects
+type fakeDocumentStorage struct {
+	objects map[string]bool
+	signed  []string
+}
+
+func (f *fakeDocumentStorage) UploadPropertyDocument(ctx context.Context, tenantID, propertyID string, file multipart.File, header *multipart.FileHeader) (string, error) {
+	path := fmt.Sprintf("documents/%s/%s/object-%d", tenantID, propertyID, len(f.objects)+1)
+	f.objects[path] = true
+	return path, nil
+}
+
+func (f *fakeDocumentStorage) GetPropertyDocumentURL(ctx context.Context, storagePath string) (string, time.Time, error) {
+	f.signed = append(f.signed, storagePath)
+	return "https://storage.example.com/" + storagePath + "?signature=x", time.Now().Add(15 * time.Minute), nil
+}
+
+func (f *fakeDocumentStorage) DeletePropertyDocument(ctx context.Context, storagePath string) error {
+	delete(f.objects, storagePath)
+	return nil
+}
+
+// newDocumentTestService creates a vault with one property in tenant-1 (prop-1, owner-1) and one in tenant-2 (prop-2)
+func newDocumentTestService() (*PropertyDocumentService, *fakeDocumentStorage, *MockActivityLogRepository) {
+	storage := &fakeDocumentStorage{objects: make(map[string]bool)}
+	activityLogs := NewMockActivityLogRepository()
+
+	service := &PropertyDocumentService{
+		documentRepo: newFakePropertyDocumentStore(),
+		propertyRepo: fakePropertyGetter{
+			"tenant-1/prop-1": {ID: "prop-1", TenantID: "tenant-1", OwnerID: "owner-1", PropertyType: models.PropertyTypeApartment},
+			"tenant-2/prop-2": {ID: "prop-2", TenantID: "tenant-2", OwnerID: "owner-2", PropertyType: models.PropertyTypeHouse},
+		},
+		activityLogRepo: activityLogs,
+		storageService:  storage,
+	}
+	return service, storage, activityLogs
+}
+
+// uploadTestDocument uploads a PDF of the category to the property
+func uploadTestDocument(t *testing.T, service *PropertyDocumentService, tenantID, propertyID string, category models.DocumentCategory) *models.PropertyDocument {
+	header := &multipart.FileHeader{Filename: "matricula.pdf", Size: 1024, Header: textproto.MIMEHeader{}}
+	header.Header.Set("Content-Type", "application/pdf")
+
+	document, err := service.UploadDocument(context.Background(), UploadPropertyDocumentInput{
+		TenantID:       tenantID,
+		PropertyID:     propertyID,
+		Category:       category,
+		Header:         header,
+		UploadedByType: models.ActorTypeUser,
+		UploadedByID:   "user-1",
+	})
+	require.NoError(t, err)
+	return document
+}
+
+func TestUploadDocumentStoresAPrivateObjectOfTheProperty(t *testing.T) {
+	service, storage, activityLogs := newDocumentTestService()
+
+	document := uploadTestDocument(t, service, "tenant-1", "prop-1", models.DocumentCategoryMatricula)
+
+	assert.Equal(t, "tenant-1", document.TenantID)
+	assert.Equal(t, "prop-1", document.PropertyID)
+	assert.Equal(t, "owner-1", document.OwnerID, "the owner defaults to the property owner")
+	assert.Equal(t, "application/pdf", document.ContentType)
+	assert.True(t, storage.objects[document.StoragePath])
+	assert.Contains(t, document.StoragePath, "documents/tenant-1/prop-1/")
+
+	require.Len(t, activityLogs.logs, 1)
+	assert.Equal(t, "property_document_uploaded", activityLogs.logs[0].EventType)
+	assert.Equal(t, document.ID, activityLogs.logs[0].Metadata["document_id"])
+}
+
+func TestUploadDocumentValidation(t *testing.T) {
+	service, storage, _ := newDocumentTestService()
+	header := &multipart.FileHeader{Filename: "iptu.pdf", Header: textproto.MIMEHeader{}}
+
+	_, err := service.UploadDocument(context.Background(), UploadPropertyDocumentInput{TenantID: "tenant-1", PropertyID: "prop-1", Category: "passport", Header: header})
+	assert.Error(t, err)
+
+	// A tenant cannot attach documents to another tenant's property
+	_, err = service.UploadDocument(context.Background(), UploadPropertyDocumentInput{TenantID: "tenant-1", PropertyID: "prop-2", Category: models.DocumentCategoryIPTU, Header: header})
+	assert.ErrorIs(t, err, repositories.ErrNotFound)
+	assert.Empty(t, storage.objects)
+
+	service.storageService = nil
+	_, err = service.UploadDocument(context.Background(), UploadPropertyDocumentInput{TenantID: "tenant-1", PropertyID: "prop-1", Category: models.DocumentCategoryIPTU, Header: header})
+	assert.Error(t, err, "uploads are disabled without storage")
+}
+
+func TestDocumentsAreNotReadableByAnotherTenant(t *testing.T) {
+	service, _, _ := newDocumentTestService()
+	document := uploadTestDocument(t, service, "tenant-1", "prop-1", models.DocumentCategoryOwnerID)
+	ctx := context.Background()
+
+	found, err := service.GetDocument(ctx, "tenant-1", document.ID)
+	require.NoError(t, err)
+	assert.Equal(t, document.ID, found.ID)
+
+	_, err = service.GetDocument(ctx, "tenant-2", document.ID)
+	assert.ErrorIs(t, err, repositories.ErrNotFound)
+
+	documents, err := service.ListDocuments(ctx, "tenant-2", "prop-1", "")
+	require.NoError(t, err)
+	assert.Empty(t, documents)
+
+	_, err = service.GetChecklist(ctx, "tenant-2", "prop-1")
+	assert.ErrorIs(t, err, repositories.ErrNotFound)
+}
+
+func TestGetDownloadURLSignsTheDocumentAndLogsTheAccess(t *testing.T) {
+	service, storage, activityLogs := newDocumentTestService()
+	document := uploadTestDocument(t, service, "tenant-1", "prop-1", models.DocumentCategoryMatricula)
+
+	response, err := service.GetDownloadURL(context.Background(), document, models.ActorTypeOwner, "owner-1")
+	require.NoError(t, err)
+
+	assert.Equal(t, document.ID, response.DocumentID)
+	assert.Contains(t, response.URL, document.StoragePath)
+	assert.True(t, response.ExpiresAt.After(time.Now()))
+	assert.Equal(t, []string{document.StoragePath}, storage.signed)
+
+	access := activityLogs.logs[len(activityLogs.logs)-1]
+	assert.Equal(t, "property_document_accessed", access.EventType)
+	assert.Equal(t, models.ActorTypeOwner, access.ActorType)
+	assert.Equal(t, "owner-1", access.ActorID)
+}
+
+func TestGetDownloadURLRejectsObjectsOutsideTheDocumentTenant(t *testing.T) {
+	service, storage, _ := newDocumentTestService()
+	other := uploadTestDocument(t, service, "tenant-2", "prop-2", models.DocumentCategoryMatricula)
+
+	// A tenant-1 record pointing at the object of tenant-2 must not be signed
+	forged := &models.PropertyDocument{ID: "doc-forged", TenantID: "tenant-1", PropertyID: "prop-1", StoragePath: other.StoragePath}
+	_, err := service.GetDownloadURL(context.Background(), forged, models.ActorTypeUser, "user-1")
+	assert.ErrorIs(t, err, repositories.ErrNotFound)
+
+	// Nor the object of another property of the same tenant
+	forged.StoragePath = "documents/tenant-1/prop-9/object-1"
+	_, err = service.GetDownloadURL(context.Background(), forged, models.ActorTypeUser, "user-1")
+	assert.ErrorIs(t, err, repositories.ErrNotFound)
+
+	assert.Empty(t, storage.signed)
+}
+
+func TestBuildDocumentChecklist(t *testing.T) {
+	now := time.Now()
+	expired := now.AddDate(0, 0, -1)
+	property := &models.Property{ID: "prop-1", PropertyType: models.PropertyTypeApartment}
+
+	checklist := buildDocumentChecklist(property, []*models.PropertyDocument{
+		{ID: "doc-1", Category: models.DocumentCategoryMatricula},
+		{ID: "doc-2", Category: models.DocumentCategoryIPTU, ExpiresAt: &expired},
+	}, now)
+
+	assert.False(t, checklist.Complete)
+	assert.Equal(t, "ok", checklist.Items[0].Status)
+	assert.Equal(t, "expired", checklist.Items[1].Status)
+	assert.NotContains(t, checklist.Missing, models.DocumentCategoryMatricula)
+	assert.Contains(t, checklist.Missing, models.DocumentCategoryIPTU)
+	assert.Contains(t, checklist.Missing, models.DocumentCategorySaleAuthorization)
+}
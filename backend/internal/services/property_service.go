@@ -26,6 +26,9 @@ type PropertyService struct {
 	tenantRepo               *repositories.TenantRepository
 	activityLogRepo          *repositories.ActivityLogRepository
 	ownerConfirmationService *OwnerConfirmationService // PROMPT 08: for generating owner confirmation links
+	saleAuthorizationService *SaleAuthorizationService // Optional: enforces sale authorization for public/marketplace
+	outbox                   *OutboxRelay              // Optional: transactional outbox (writes + domain events)
+	planService              *PlanService              // Optional: subscription plan quotas and features
 }
 
 // NewPropertyService creates a new property service
@@ -71,6 +74,13 @@ func (s *PropertyService) CreateProperty(ctx context.Context, property *models.P
 		return fmt.Errorf("tenant not found: %w", err)
 	}
 
+	// Check the property quota of the tenant's plan
+	if s.planService != nil {
+		if err := s.planService.CheckPropertyQuota(ctx, property.TenantID, 1); err != nil {
+			return err
+		}
+	}
+
 	// Validate owner exists
 	if _, err := s.ownerRepo.Get(ctx, property.TenantID, property.OwnerID); err != nil {
 		return fmt.Errorf("owner not found: %w", err)
@@ -94,6 +104,14 @@ func (s *PropertyService) CreateProperty(ctx context.Context, property *models.P
 	if property.Visibility == "" {
 		property.Visibility = models.PropertyVisibilityPrivate
 	}
+	if err := s.checkVisibilityPlan(ctx, property.TenantID, property.Visibility); err != nil {
+		return err
+	}
+
+	// A new property has no sale authorization yet: keep it inside the tenant until one is signed
+	if s.saleAuthorizationService != nil && s.saleAuthorizationService.RequiresAuthorization(ctx, property.TenantID, property.Visibility) {
+		property.Visibility = models.PropertyVisibilityNetwork
+	}
 
 	// Generate slug if not provided
 	if property.Slug == "" {
@@ -121,13 +139,12 @@ func (s *PropertyService) CreateProperty(ctx context.Context, property *models.P
 	// Determine data completeness
 	property.DataCompleteness = s.determineDataCompleteness(property)
 
-	// Create property in repository
-	if err := s.propertyRepo.Create(ctx, property); err != nil {
+	// Create property and log activity in one transaction
+	write, err := s.propertyRepo.CreateTx(property)
+	if err != nil {
 		return fmt.Errorf("failed to create property: %w", err)
 	}
-
-	// Log activity
-	_ = s.logActivity(ctx, property.TenantID, "property_created", models.ActorTypeSystem, "", map[string]interface{}{
+	event := models.NewOutboxEvent(property.TenantID, "property_created", "property", property.ID, models.ActorTypeSystem, "", map[string]interface{}{
 		"property_id":        property.ID,
 		"slug":               property.Slug,
 		"fingerprint":        property.Fingerprint,
@@ -135,6 +152,9 @@ func (s *PropertyService) CreateProperty(ctx context.Context, property *models.P
 		"external_source":    property.ExternalSource,
 		"external_id":        property.ExternalID,
 	})
+	if err := s.commit(ctx, event, write); err != nil {
+		return fmt.Errorf("failed to create property: %w", err)
+	}
 
 	return nil
 }
@@ -296,6 +316,20 @@ func (s *PropertyService) UpdateProperty(ctx context.Context, tenantID, id strin
 		}
 	}
 
+	// Marketplace visibility depends on the tenant's plan
+	if visibility, ok := updates["visibility"]; ok {
+		if err := s.checkVisibilityPlan(ctx, tenantID, models.PropertyVisibility(fmt.Sprint(visibility))); err != nil {
+			return err
+		}
+	}
+
+	// Public/marketplace visibility may require a valid sale authorization (tenant setting)
+	if visibility, ok := updates["visibility"]; ok && s.saleAuthorizationService != nil {
+		if err := s.saleAuthorizationService.CheckVisibilityAllowed(ctx, tenantID, id, models.PropertyVisibility(fmt.Sprint(visibility))); err != nil {
+			return err
+		}
+	}
+
 	// Normalize slug if being updated
 	if slug, ok := updates["slug"].(string); ok && slug != "" {
 		updates["slug"] = s.NormalizeSlug(slug)
@@ -334,16 +368,18 @@ func (s *PropertyService) UpdateProperty(ctx context.Context, tenantID, id strin
 	// Prevent updating tenant_id
 	delete(updates, "tenant_id")
 
-	// Update property in repository
-	if err := s.propertyRepo.Update(ctx, tenantID, id, updates); err != nil {
+	// Update property and log activity in one transaction
+	write, err := s.propertyRepo.UpdateTx(tenantID, id, updates)
+	if err != nil {
 		return fmt.Errorf("failed to update property: %w", err)
 	}
-
-	// Log activity
-	_ = s.logActivity(ctx, tenantID, "property_updated", models.ActorTypeSystem, "", map[string]interface{}{
+	event := models.NewOutboxEvent(tenantID, "property_updated", "property", id, models.ActorTypeSystem, "", map[string]interface{}{
 		"property_id": id,
 		"updates":     updates,
 	})
+	if err := s.commit(ctx, event, write); err != nil {
+		return fmt.Errorf("failed to update property: %w", err)
+	}
 
 	return nil
 }
@@ -362,15 +398,17 @@ func (s *PropertyService) DeleteProperty(ctx context.Context, tenantID, id strin
 		return fmt.Errorf("property not found: %w", err)
 	}
 
-	// Delete property from repository
-	if err := s.propertyRepo.Delete(ctx, tenantID, id); err != nil {
+	// Delete property and log activity in one transaction
+	write, err := s.propertyRepo.DeleteTx(tenantID, id)
+	if err != nil {
 		return fmt.Errorf("failed to delete property: %w", err)
 	}
-
-	// Log activity
-	_ = s.logActivity(ctx, tenantID, "property_deleted", models.ActorTypeSystem, "", map[string]interface{}{
+	event := models.NewOutboxEvent(tenantID, "property_deleted", "property", id, models.ActorTypeSystem, "", map[string]interface{}{
 		"property_id": id,
 	})
+	if err := s.commit(ctx, event, write); err != nil {
+		return fmt.Errorf("failed to delete property: %w", err)
+	}
 
 	return nil
 }
@@ -540,15 +578,18 @@ func (s *PropertyService) UpdateStatus(ctx context.Context, tenantID, id string,
 		"status_confirmed_at":  now,
 	}
 
-	if err := s.propertyRepo.Update(ctx, tenantID, id, updates); err != nil {
+	// Update status and log activity in one transaction
+	write, err := s.propertyRepo.UpdateTx(tenantID, id, updates)
+	if err != nil {
 		return fmt.Errorf("failed to update property status: %w", err)
 	}
-
-	// Log activity
-	_ = s.logActivity(ctx, tenantID, "property_status_changed", models.ActorTypeSystem, "", map[string]interface{}{
+	event := models.NewOutboxEvent(tenantID, "property_status_changed", "property", id, models.ActorTypeSystem, "", map[string]interface{}{
 		"property_id": id,
 		"status":      status,
 	})
+	if err := s.commit(ctx, event, write); err != nil {
+		return fmt.Errorf("failed to update property status: %w", err)
+	}
 
 	return nil
 }
@@ -566,19 +607,34 @@ func (s *PropertyService) UpdateVisibility(ctx context.Context, tenantID, id str
 		return err
 	}
 
+	// Marketplace visibility depends on the tenant's plan
+	if err := s.checkVisibilityPlan(ctx, tenantID, visibility); err != nil {
+		return err
+	}
+
+	// Public/marketplace visibility may require a valid sale authorization (tenant setting)
+	if s.saleAuthorizationService != nil {
+		if err := s.saleAuthorizationService.CheckVisibilityAllowed(ctx, tenantID, id, visibility); err != nil {
+			return err
+		}
+	}
+
 	updates := map[string]interface{}{
 		"visibility": visibility,
 	}
 
-	if err := s.propertyRepo.Update(ctx, tenantID, id, updates); err != nil {
+	// Update visibility and log activity in one transaction
+	write, err := s.propertyRepo.UpdateTx(tenantID, id, updates)
+	if err != nil {
 		return fmt.Errorf("failed to update property visibility: %w", err)
 	}
-
-	// Log activity
-	_ = s.logActivity(ctx, tenantID, "property_visibility_changed", models.ActorTypeSystem, "", map[string]interface{}{
+	event := models.NewOutboxEvent(tenantID, "property_visibility_changed", "property", id, models.ActorTypeSystem, "", map[string]interface{}{
 		"property_id": id,
 		"visibility":  visibility,
 	})
+	if err := s.commit(ctx, event, write); err != nil {
+		return fmt.Errorf("failed to update property visibility: %w", err)
+	}
 
 	return nil
 }
@@ -825,6 +881,22 @@ func (s *PropertyService) validatePropertyVisibility(visibility models.PropertyV
 	return nil
 }
 
+// commit applies the writes and their domain event atomically through the transactional outbox
+// Without an outbox (CLI tools, tests) the writes run in a transaction and the event is logged directly
+func (s *PropertyService) commit(ctx context.Context, event *models.OutboxEvent, writes ...repositories.TxWrite) error {
+	if s.outbox != nil {
+		return s.outbox.Commit(ctx, []*models.OutboxEvent{event}, writes...)
+	}
+
+	if len(writes) > 0 {
+		if err := s.propertyRepo.RunTransaction(ctx, writes...); err != nil {
+			return err
+		}
+	}
+	_ = appendActivityLog(ctx, s.activityLogRepo, event.ActivityLog())
+	return nil
+}
+
 // logActivity logs an activity (helper method)
 func (s *PropertyService) logActivity(ctx context.Context, tenantID, eventType string, actorType models.ActorType, actorID string, metadata map[string]interface{}) error {
 	log := &models.ActivityLog{
@@ -836,7 +908,7 @@ func (s *PropertyService) logActivity(ctx context.Context, tenantID, eventType s
 		Timestamp: time.Now(),
 	}
 
-	return s.activityLogRepo.Create(ctx, log)
+	return appendActivityLog(ctx, s.activityLogRepo, log)
 }
 
 // ========== PROMPT 08: Property Status Confirmation ==========
@@ -955,6 +1027,29 @@ func (s *PropertyService) SetOwnerConfirmationService(service *OwnerConfirmation
 	s.ownerConfirmationService = service
 }
 
+// SetOutboxRelay sets the transactional outbox (for dependency injection)
+func (s *PropertyService) SetOutboxRelay(outbox *OutboxRelay) {
+	s.outbox = outbox
+}
+
+// SetSaleAuthorizationService sets the sale authorization service (for dependency injection)
+func (s *PropertyService) SetSaleAuthorizationService(service *SaleAuthorizationService) {
+	s.saleAuthorizationService = service
+}
+
+// SetPlanService sets the plan service (for dependency injection)
+func (s *PropertyService) SetPlanService(service *PlanService) {
+	s.planService = service
+}
+
+// checkVisibilityPlan checks that the tenant's plan includes marketplace visibility when it is requested
+func (s *PropertyService) checkVisibilityPlan(ctx context.Context, tenantID string, visibility models.PropertyVisibility) error {
+	if s.planService == nil || visibility != models.PropertyVisibilityMarketplace {
+		return nil
+	}
+	return s.planService.CheckFeature(ctx, tenantID, models.PlanFeatureMarketplaceVisibility)
+}
+
 // calculateVisibility determines the visibility based on status and confirmation time
 // PROMPT 08: Business logic for hiding stale/unavailable properties
 func (s *PropertyService) calculateVisibility(
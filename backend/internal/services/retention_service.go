@@ -0,0 +1,280 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+const (
+	// defaultRetentionBatchSize is the number of records processed per rule in a sweep
+	defaultRetentionBatchSize = 200
+
+	// maxRetentionBatchSize bounds a single sweep (Firestore request/time limits)
+	maxRetentionBatchSize = 1000
+
+	// retentionAnonymizationReason is recorded on leads anonymized by the sweeper
+	retentionAnonymizationReason = "retention_policy"
+)
+
+// RetentionService applies the tenant LGPD retention policy (see models.RetentionPolicy)
+// Runs in batches; a dry-run only reports what would be changed
+// Each lead anonymization is logged by LeadService.AnonymizeLead (lead_anonymized, reason retention_policy)
+type RetentionService struct {
+	runRepo         *repositories.RetentionRunRepository
+	leadRepo        *repositories.LeadRepository
+	activityLogRepo *repositories.ActivityLogRepository
+	tenantRepo      *repositories.TenantRepository
+	leadService     *LeadService
+}
+
+// NewRetentionService creates a new retention service
+func NewRetentionService(
+	runRepo *repositories.RetentionRunRepository,
+	leadRepo *repositories.LeadRepository,
+	activityLogRepo *repositories.ActivityLogRepository,
+	tenantRepo *repositories.TenantRepository,
+	leadService *LeadService,
+) *RetentionService {
+	return &RetentionService{
+		runRepo:         runRepo,
+		leadRepo:        leadRepo,
+		activityLogRepo: activityLogRepo,
+		tenantRepo:      tenantRepo,
+		leadService:     leadService,
+	}
+}
+
+// RetentionSweepInput represents a sweep request
+type RetentionSweepInput struct {
+	TenantID    string
+	DryRun      bool
+	BatchSize   int // default 200, max 1000
+	TriggeredBy string
+}
+
+// GetPolicy returns the effective retention policy of a tenant
+func (s *RetentionService) GetPolicy(ctx context.Context, tenantID string) (*models.RetentionPolicy, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+
+	tenant, err := s.tenantRepo.Get(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("tenant not found: %w", err)
+	}
+
+	policy := models.RetentionPolicyFor(tenant)
+	return &policy, nil
+}
+
+// Sweep applies (or reports, in dry-run) one batch of every retention rule and records the run
+func (s *RetentionService) Sweep(ctx context.Context, input RetentionSweepInput) (*models.RetentionRun, error) {
+	if input.TenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+
+	batchSize := input.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultRetentionBatchSize
+	}
+	if batchSize > maxRetentionBatchSize {
+		batchSize = maxRetentionBatchSize
+	}
+
+	policy, err := s.GetPolicy(ctx, input.TenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	triggeredBy := input.TriggeredBy
+	if triggeredBy == "" {
+		triggeredBy = "system"
+	}
+
+	now := time.Now()
+	run := &models.RetentionRun{
+		TenantID:    input.TenantID,
+		DryRun:      input.DryRun,
+		BatchSize:   batchSize,
+		Policy:      *policy,
+		TriggeredBy: triggeredBy,
+		StartedAt:   now,
+	}
+
+	// Resume activity log pruning after the last applied sweep
+	var cursor time.Time
+	lastRun, err := s.runRepo.GetLastApplied(ctx, input.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	if lastRun != nil && lastRun.ActivityLogCursor != nil {
+		cursor = *lastRun.ActivityLogCursor
+		run.ActivityLogCursor = lastRun.ActivityLogCursor
+	}
+
+	run.Rules = append(run.Rules,
+		s.sweepLeads(ctx, input.TenantID, models.RetentionRuleLeadInactivity, *policy, now, batchSize, input.DryRun),
+		s.sweepLeads(ctx, input.TenantID, models.RetentionRuleRevokedConsent, *policy, now, batchSize, input.DryRun),
+	)
+
+	logResult, newCursor := s.sweepActivityLogs(ctx, input.TenantID, *policy, now, cursor, batchSize, input.DryRun)
+	run.Rules = append(run.Rules, logResult)
+	if !input.DryRun && !newCursor.IsZero() {
+		run.ActivityLogCursor = &newCursor
+	}
+
+	run.FinishedAt = time.Now()
+	if err := s.runRepo.Create(ctx, run); err != nil {
+		return nil, err
+	}
+
+	metadata := map[string]interface{}{
+		"run_id":  run.ID,
+		"dry_run": run.DryRun,
+	}
+	for _, result := range run.Rules {
+		metadata[result.Rule+"_matched"] = result.Matched
+		metadata[result.Rule+"_applied"] = result.Applied
+		metadata[result.Rule+"_failed"] = result.Failed
+	}
+	_ = s.logActivity(ctx, input.TenantID, "retention_sweep_completed", triggeredBy, metadata)
+
+	return run, nil
+}
+
+// ListRuns lists the most recent sweeps (dry-run reports included)
+func (s *RetentionService) ListRuns(ctx context.Context, tenantID string, limit int) ([]*models.RetentionRun, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	return s.runRepo.List(ctx, tenantID, limit)
+}
+
+// GetRun retrieves a sweep report
+func (s *RetentionService) GetRun(ctx context.Context, tenantID, runID string) (*models.RetentionRun, error) {
+	return s.runRepo.Get(ctx, tenantID, runID)
+}
+
+// sweepLeads anonymizes one batch of leads matched by a lead rule
+func (s *RetentionService) sweepLeads(ctx context.Context, tenantID, rule string, policy models.RetentionPolicy, now time.Time, batchSize int, dryRun bool) models.RetentionRuleResult {
+	result := models.RetentionRuleResult{Rule: rule}
+
+	cutoff, enabled := policy.Cutoff(rule, now)
+	if !enabled {
+		return result
+	}
+	result.Enabled = true
+	result.Cutoff = cutoff
+
+	var leads []*models.Lead
+	var err error
+	if rule == models.RetentionRuleRevokedConsent {
+		leads, err = s.leadRepo.ListRevokedConsentBefore(ctx, tenantID, cutoff, batchSize)
+	} else {
+		leads, err = s.leadRepo.ListInactiveBefore(ctx, tenantID, cutoff, batchSize)
+	}
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+		return result
+	}
+
+	result.Matched = len(leads)
+	result.HasMore = len(leads) == batchSize
+
+	for _, lead := range leads {
+		if dryRun {
+			result.RecordIDs = append(result.RecordIDs, lead.ID)
+			continue
+		}
+
+		if err := s.leadService.AnonymizeLead(ctx, tenantID, lead.ID, retentionAnonymizationReason); err != nil {
+			log.Printf("⚠️  Retention: failed to anonymize lead %s (tenant %s): %v", lead.ID, tenantID, err)
+			result.Failed++
+			result.Errors = append(result.Errors, fmt.Sprintf("lead %s: %v", lead.ID, err))
+			continue
+		}
+		result.Applied++
+		result.RecordIDs = append(result.RecordIDs, lead.ID)
+	}
+
+	return result
+}
+
+// sweepActivityLogs removes PII metadata from one batch of old activity logs
+// Returns the timestamp of the last log scanned, used as cursor by the next sweep
+func (s *RetentionService) sweepActivityLogs(ctx context.Context, tenantID string, policy models.RetentionPolicy, now, cursor time.Time, batchSize int, dryRun bool) (models.RetentionRuleResult, time.Time) {
+	result := models.RetentionRuleResult{Rule: models.RetentionRuleActivityLogPII}
+
+	cutoff, enabled := policy.Cutoff(models.RetentionRuleActivityLogPII, now)
+	if !enabled {
+		return result, time.Time{}
+	}
+	result.Enabled = true
+	result.Cutoff = cutoff
+
+	logs, err := s.activityLogRepo.ListOlderThan(ctx, tenantID, cursor, cutoff, batchSize)
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+		return result, time.Time{}
+	}
+	result.HasMore = len(logs) == batchSize
+
+	var lastScanned time.Time
+	for _, activityLog := range logs {
+		keys := activityLogPIIKeys(activityLog)
+		if len(keys) > 0 {
+			result.Matched++
+			if dryRun {
+				result.RecordIDs = append(result.RecordIDs, activityLog.ID)
+			} else if err := s.activityLogRepo.RemoveMetadataKeys(ctx, tenantID, activityLog.ID, keys); err != nil {
+				// Stop here so the cursor does not skip the failed log
+				result.Failed++
+				result.Errors = append(result.Errors, fmt.Sprintf("activity log %s: %v", activityLog.ID, err))
+				result.HasMore = true
+				break
+			} else {
+				result.Applied++
+				result.RecordIDs = append(result.RecordIDs, activityLog.ID)
+			}
+		}
+		lastScanned = activityLog.Timestamp
+	}
+
+	return result, lastScanned
+}
+
+// activityLogPIIKeys returns the PII metadata keys present in a log
+func activityLogPIIKeys(activityLog *models.ActivityLog) []string {
+	keys := make([]string, 0)
+	for _, key := range models.ActivityLogPIIMetadataKeys {
+		if _, ok := activityLog.Metadata[key]; ok {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// logActivity logs an activity
+func (s *RetentionService) logActivity(ctx context.Context, tenantID, eventType, actorID string, metadata map[string]interface{}) error {
+	actorType := models.ActorTypeUser
+	if actorID == "system" {
+		actorType = models.ActorTypeSystem
+		actorID = ""
+	}
+
+	log := &models.ActivityLog{
+		TenantID:  tenantID,
+		EventType: eventType,
+		ActorType: actorType,
+		ActorID:   actorID,
+		Metadata:  metadata,
+		Timestamp: time.Now(),
+	}
+
+	return appendActivityLog(ctx, s.activityLogRepo, log)
+}
@@ -0,0 +1,500 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"mime/multipart"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// defaultSaleAuthorizationAlertDays is how many days before expiry the broker is alerted
+const defaultSaleAuthorizationAlertDays = 15
+
+// ErrSaleAuthorizationRequired is returned when public/marketplace visibility needs a valid sale authorization
+var ErrSaleAuthorizationRequired = errors.New("a valid signed sale authorization is required for public or marketplace visibility")
+
+// SaleAuthorizationStore is the sale authorization persistence used by SaleAuthorizationService
+// (implemented by repositories.SaleAuthorizationRepository)
+type SaleAuthorizationStore interface {
+	Create(ctx context.Context, authorization *models.SaleAuthorization) error
+	Get(ctx context.Context, tenantID, id string) (*models.SaleAuthorization, error)
+	Update(ctx context.Context, tenantID, id string, updates map[string]interface{}) error
+	ListByProperty(ctx context.Context, tenantID, propertyID string) ([]*models.SaleAuthorization, error)
+	ListActiveEndingBefore(ctx context.Context, tenantID string, before time.Time) ([]*models.SaleAuthorization, error)
+}
+
+// SaleAuthorizationService handles sale authorizations ("autorização de venda") and their expiry
+type SaleAuthorizationService struct {
+	authorizationRepo SaleAuthorizationStore
+	propertyRepo      *repositories.PropertyRepository
+	brokerRepo        *repositories.BrokerRepository
+	roleRepo          *repositories.PropertyBrokerRoleRepository
+	tenantRepo        TenantGetter
+	activityLogRepo   *repositories.ActivityLogRepository
+	emailService      *EmailService
+	documentService   *PropertyDocumentService
+}
+
+// NewSaleAuthorizationService creates a new sale authorization service
+func NewSaleAuthorizationService(
+	authorizationRepo SaleAuthorizationStore,
+	propertyRepo *repositories.PropertyRepository,
+	brokerRepo *repositories.BrokerRepository,
+	roleRepo *repositories.PropertyBrokerRoleRepository,
+	tenantRepo TenantGetter,
+	activityLogRepo *repositories.ActivityLogRepository,
+	emailService *EmailService,
+	documentService *PropertyDocumentService,
+) *SaleAuthorizationService {
+	return &SaleAuthorizationService{
+		authorizationRepo: authorizationRepo,
+		propertyRepo:      propertyRepo,
+		brokerRepo:        brokerRepo,
+		roleRepo:          roleRepo,
+		tenantRepo:        tenantRepo,
+		activityLogRepo:   activityLogRepo,
+		emailService:      emailService,
+		documentService:   documentService,
+	}
+}
+
+// CreateSaleAuthorizationInput represents the data of a new sale authorization
+type CreateSaleAuthorizationInput struct {
+	TenantID             string
+	PropertyID           string
+	BrokerID             string // default: originating broker of the property
+	IsExclusive          bool
+	CommissionPercentage float64
+	StartDate            time.Time
+	EndDate              time.Time
+	Notes                string
+}
+
+// SaleAuthorizationExpiryResponse represents the result of the expiry job
+type SaleAuthorizationExpiryResponse struct {
+	AlertsSent       int      `json:"alerts_sent"`
+	Expired          int      `json:"expired"`
+	PropertiesHidden int      `json:"properties_hidden"`
+	Errors           []string `json:"errors,omitempty"`
+}
+
+// CreateAuthorization registers a sale authorization for a property
+// The authorization is only valid (for advertising) after the signed document is attached
+func (s *SaleAuthorizationService) CreateAuthorization(ctx context.Context, input CreateSaleAuthorizationInput, actorID string) (*models.SaleAuthorization, error) {
+	if input.TenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+	if input.PropertyID == "" {
+		return nil, fmt.Errorf("property_id is required")
+	}
+	if input.StartDate.IsZero() || input.EndDate.IsZero() {
+		return nil, fmt.Errorf("start_date and end_date are required")
+	}
+	if !input.EndDate.After(input.StartDate) {
+		return nil, fmt.Errorf("end_date must be after start_date")
+	}
+	if input.CommissionPercentage < 0 || input.CommissionPercentage > 100 {
+		return nil, fmt.Errorf("commission_percentage must be between 0 and 100")
+	}
+
+	property, err := s.propertyRepo.Get(ctx, input.TenantID, input.PropertyID)
+	if err != nil {
+		return nil, fmt.Errorf("property not found: %w", err)
+	}
+
+	brokerID := input.BrokerID
+	if brokerID == "" {
+		brokerID = s.originatingBrokerID(ctx, property)
+	}
+	if brokerID == "" {
+		return nil, fmt.Errorf("broker_id is required (property has no originating broker)")
+	}
+	if _, err := s.brokerRepo.Get(ctx, input.TenantID, brokerID); err != nil {
+		return nil, fmt.Errorf("broker not found: %w", err)
+	}
+
+	// Exclusividade: não pode haver outra autorização ativa no mesmo período
+	existing, err := s.authorizationRepo.ListByProperty(ctx, input.TenantID, property.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, other := range existing {
+		if other.Status != models.SaleAuthorizationStatusActive || !(input.IsExclusive || other.IsExclusive) {
+			continue
+		}
+		if input.StartDate.Before(other.EndDate) && other.StartDate.Before(input.EndDate) {
+			return nil, fmt.Errorf("an exclusive sale authorization (%s) already covers this period", other.ID)
+		}
+	}
+
+	authorization := &models.SaleAuthorization{
+		TenantID:             input.TenantID,
+		PropertyID:           property.ID,
+		OwnerID:              property.OwnerID,
+		BrokerID:             brokerID,
+		IsExclusive:          input.IsExclusive,
+		CommissionPercentage: input.CommissionPercentage,
+		StartDate:            input.StartDate,
+		EndDate:              input.EndDate,
+		Status:               models.SaleAuthorizationStatusActive,
+		Notes:                input.Notes,
+		CreatedBy:            actorID,
+	}
+	if err := s.authorizationRepo.Create(ctx, authorization); err != nil {
+		return nil, err
+	}
+
+	_ = s.logActivity(ctx, input.TenantID, "sale_authorization_created", actorID, map[string]interface{}{
+		"authorization_id":      authorization.ID,
+		"property_id":           property.ID,
+		"owner_id":              property.OwnerID,
+		"broker_id":             brokerID,
+		"is_exclusive":          authorization.IsExclusive,
+		"commission_percentage": authorization.CommissionPercentage,
+		"start_date":            authorization.StartDate,
+		"end_date":              authorization.EndDate,
+	})
+
+	return authorization, nil
+}
+
+// GetAuthorization retrieves a sale authorization
+func (s *SaleAuthorizationService) GetAuthorization(ctx context.Context, tenantID, id string) (*models.SaleAuthorization, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+	if id == "" {
+		return nil, fmt.Errorf("authorization ID is required")
+	}
+
+	return s.authorizationRepo.Get(ctx, tenantID, id)
+}
+
+// ListByProperty lists the sale authorizations of a property
+func (s *SaleAuthorizationService) ListByProperty(ctx context.Context, tenantID, propertyID string) ([]*models.SaleAuthorization, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+	if propertyID == "" {
+		return nil, fmt.Errorf("property_id is required")
+	}
+
+	return s.authorizationRepo.ListByProperty(ctx, tenantID, propertyID)
+}
+
+// AttachSignedDocument uploads the signed authorization (private object) and links it
+func (s *SaleAuthorizationService) AttachSignedDocument(ctx context.Context, tenantID, id string, file multipart.File, header *multipart.FileHeader, actorID string) (*models.SaleAuthorization, error) {
+	authorization, err := s.GetAuthorization(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if authorization.Status != models.SaleAuthorizationStatusActive {
+		return nil, fmt.Errorf("sale authorization is %s", authorization.Status)
+	}
+
+	document, err := s.documentService.UploadDocument(ctx, UploadPropertyDocumentInput{
+		TenantID:       tenantID,
+		PropertyID:     authorization.PropertyID,
+		OwnerID:        authorization.OwnerID,
+		Category:       models.DocumentCategorySaleAuthorization,
+		ExpiresAt:      &authorization.EndDate,
+		File:           file,
+		Header:         header,
+		UploadedByType: models.ActorTypeUser,
+		UploadedByID:   actorID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := s.authorizationRepo.Update(ctx, tenantID, authorization.ID, map[string]interface{}{
+		"signed_document_id": document.ID,
+		"signed_at":          now,
+	}); err != nil {
+		return nil, err
+	}
+	authorization.SignedDocumentID = document.ID
+	authorization.SignedAt = &now
+
+	_ = s.logActivity(ctx, tenantID, "sale_authorization_signed", actorID, map[string]interface{}{
+		"authorization_id": authorization.ID,
+		"property_id":      authorization.PropertyID,
+		"document_id":      document.ID,
+	})
+
+	return authorization, nil
+}
+
+// RevokeAuthorization cancels an authorization before its end date
+// If the tenant requires authorizations, the property stops being advertised when no other valid one exists
+func (s *SaleAuthorizationService) RevokeAuthorization(ctx context.Context, tenantID, id, reason, actorID string) error {
+	authorization, err := s.GetAuthorization(ctx, tenantID, id)
+	if err != nil {
+		return err
+	}
+	if authorization.Status != models.SaleAuthorizationStatusActive {
+		return fmt.Errorf("sale authorization is already %s", authorization.Status)
+	}
+
+	if err := s.authorizationRepo.Update(ctx, tenantID, authorization.ID, map[string]interface{}{
+		"status":         models.SaleAuthorizationStatusRevoked,
+		"revoked_at":     time.Now(),
+		"revoked_reason": reason,
+	}); err != nil {
+		return err
+	}
+
+	_ = s.logActivity(ctx, tenantID, "sale_authorization_revoked", actorID, map[string]interface{}{
+		"authorization_id": authorization.ID,
+		"property_id":      authorization.PropertyID,
+		"reason":           reason,
+	})
+
+	tenant, err := s.tenantRepo.Get(ctx, tenantID)
+	if err != nil {
+		return nil
+	}
+	if _, err := s.enforceVisibility(ctx, tenant, authorization.PropertyID); err != nil {
+		log.Printf("⚠️  Warning: failed to enforce visibility for property %s: %v", authorization.PropertyID, err)
+	}
+
+	return nil
+}
+
+// HasValidAuthorization checks whether the property has a valid (signed, in force) authorization
+func (s *SaleAuthorizationService) HasValidAuthorization(ctx context.Context, tenantID, propertyID string) (bool, error) {
+	authorizations, err := s.authorizationRepo.ListByProperty(ctx, tenantID, propertyID)
+	if err != nil {
+		return false, err
+	}
+
+	now := time.Now()
+	for _, authorization := range authorizations {
+		if authorization.IsValid(now) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// CheckVisibilityAllowed returns ErrSaleAuthorizationRequired when the tenant requires a valid
+// authorization for public/marketplace visibility and the property has none
+func (s *SaleAuthorizationService) CheckVisibilityAllowed(ctx context.Context, tenantID, propertyID string, visibility models.PropertyVisibility) error {
+	if !visibilityRequiresAuthorization(visibility) {
+		return nil
+	}
+
+	tenant, err := s.tenantRepo.Get(ctx, tenantID)
+	if err != nil {
+		return fmt.Errorf("tenant not found: %w", err)
+	}
+	if !tenant.BoolSetting(models.TenantSettingRequireSaleAuthorization) {
+		return nil
+	}
+
+	valid, err := s.HasValidAuthorization(ctx, tenantID, propertyID)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return ErrSaleAuthorizationRequired
+	}
+
+	return nil
+}
+
+// RequiresAuthorization reports whether the tenant requires authorizations for the given visibility
+func (s *SaleAuthorizationService) RequiresAuthorization(ctx context.Context, tenantID string, visibility models.PropertyVisibility) bool {
+	if !visibilityRequiresAuthorization(visibility) {
+		return false
+	}
+
+	tenant, err := s.tenantRepo.Get(ctx, tenantID)
+	if err != nil {
+		return false
+	}
+	return tenant.BoolSetting(models.TenantSettingRequireSaleAuthorization)
+}
+
+// ProcessExpirations alerts the originating broker of authorizations about to expire and expires lapsed ones
+// This should be run daily by a cron job
+func (s *SaleAuthorizationService) ProcessExpirations(ctx context.Context, tenantID string) (*SaleAuthorizationExpiryResponse, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+
+	tenant, err := s.tenantRepo.Get(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("tenant not found: %w", err)
+	}
+
+	now := time.Now()
+	alertDays := tenant.IntSetting(models.TenantSettingSaleAuthorizationAlertDays, defaultSaleAuthorizationAlertDays)
+
+	authorizations, err := s.authorizationRepo.ListActiveEndingBefore(ctx, tenantID, now.AddDate(0, 0, alertDays))
+	if err != nil {
+		return nil, err
+	}
+
+	response := &SaleAuthorizationExpiryResponse{Errors: []string{}}
+	for _, authorization := range authorizations {
+		if !authorization.EndDate.After(now) {
+			if err := s.expire(ctx, tenant, authorization, response); err != nil {
+				response.Errors = append(response.Errors, fmt.Sprintf("Authorization %s: %v", authorization.ID, err))
+			}
+			continue
+		}
+
+		if authorization.ExpiryAlertSentAt != nil {
+			continue
+		}
+		if err := s.sendExpiryAlert(ctx, authorization); err != nil {
+			response.Errors = append(response.Errors, fmt.Sprintf("Authorization %s: %v", authorization.ID, err))
+			continue
+		}
+		response.AlertsSent++
+	}
+
+	log.Printf("📊 Sale authorizations (tenant %s): %d alerts sent, %d expired, %d properties hidden",
+		tenantID, response.AlertsSent, response.Expired, response.PropertiesHidden)
+
+	return response, nil
+}
+
+// expire marks a lapsed authorization as expired and hides the property if required
+func (s *SaleAuthorizationService) expire(ctx context.Context, tenant *models.Tenant, authorization *models.SaleAuthorization, response *SaleAuthorizationExpiryResponse) error {
+	if err := s.authorizationRepo.Update(ctx, tenant.ID, authorization.ID, map[string]interface{}{
+		"status": models.SaleAuthorizationStatusExpired,
+	}); err != nil {
+		return err
+	}
+	response.Expired++
+
+	_ = s.logActivity(ctx, tenant.ID, "sale_authorization_expired", "", map[string]interface{}{
+		"authorization_id": authorization.ID,
+		"property_id":      authorization.PropertyID,
+		"end_date":         authorization.EndDate,
+	})
+
+	hidden, err := s.enforceVisibility(ctx, tenant, authorization.PropertyID)
+	if err != nil {
+		return err
+	}
+	if hidden {
+		response.PropertiesHidden++
+	}
+
+	return nil
+}
+
+// sendExpiryAlert emails the broker responsible for the authorization
+func (s *SaleAuthorizationService) sendExpiryAlert(ctx context.Context, authorization *models.SaleAuthorization) error {
+	broker, err := s.brokerRepo.Get(ctx, authorization.TenantID, authorization.BrokerID)
+	if err != nil {
+		return fmt.Errorf("broker not found: %w", err)
+	}
+
+	reference := authorization.PropertyID
+	if property, err := s.propertyRepo.Get(ctx, authorization.TenantID, authorization.PropertyID); err == nil && property.Reference != "" {
+		reference = property.Reference
+	}
+
+	branding := s.emailService.PlatformBranding()
+	if tenant, err := s.tenantRepo.Get(ctx, authorization.TenantID); err == nil {
+		branding = tenant.ResolvedBranding()
+	}
+
+	if err := s.emailService.SendSaleAuthorizationExpiryAlert(broker.Email, broker.Name, branding, reference, authorization.IsExclusive, authorization.EndDate); err != nil {
+		return fmt.Errorf("failed to send alert: %w", err)
+	}
+
+	if err := s.authorizationRepo.Update(ctx, authorization.TenantID, authorization.ID, map[string]interface{}{
+		"expiry_alert_sent_at": time.Now(),
+	}); err != nil {
+		return err
+	}
+
+	_ = s.logActivity(ctx, authorization.TenantID, "sale_authorization_expiry_alert_sent", "", map[string]interface{}{
+		"authorization_id": authorization.ID,
+		"property_id":      authorization.PropertyID,
+		"broker_id":        broker.ID,
+		"end_date":         authorization.EndDate,
+	})
+
+	return nil
+}
+
+// enforceVisibility moves a public/marketplace property back to network when the tenant
+// requires authorizations and the property no longer has a valid one
+func (s *SaleAuthorizationService) enforceVisibility(ctx context.Context, tenant *models.Tenant, propertyID string) (bool, error) {
+	if !tenant.BoolSetting(models.TenantSettingRequireSaleAuthorization) {
+		return false, nil
+	}
+
+	property, err := s.propertyRepo.Get(ctx, tenant.ID, propertyID)
+	if err != nil {
+		return false, err
+	}
+	if !visibilityRequiresAuthorization(property.Visibility) {
+		return false, nil
+	}
+
+	valid, err := s.HasValidAuthorization(ctx, tenant.ID, propertyID)
+	if err != nil || valid {
+		return false, err
+	}
+
+	if err := s.propertyRepo.Update(ctx, tenant.ID, propertyID, map[string]interface{}{
+		"visibility": models.PropertyVisibilityNetwork,
+	}); err != nil {
+		return false, err
+	}
+
+	_ = s.logActivity(ctx, tenant.ID, "property_visibility_changed", "", map[string]interface{}{
+		"property_id":    propertyID,
+		"old_visibility": property.Visibility,
+		"visibility":     models.PropertyVisibilityNetwork,
+		"reason":         "sale_authorization_missing",
+	})
+
+	return true, nil
+}
+
+// originatingBrokerID resolves the originating broker (captador) of a property
+func (s *SaleAuthorizationService) originatingBrokerID(ctx context.Context, property *models.Property) string {
+	if role, err := s.roleRepo.GetOriginatingBroker(ctx, property.TenantID, property.ID); err == nil {
+		return role.BrokerID
+	}
+	return property.CaptadorID
+}
+
+// logActivity logs a sale authorization event (system actor when actorID is empty)
+func (s *SaleAuthorizationService) logActivity(ctx context.Context, tenantID, eventType, actorID string, metadata map[string]interface{}) error {
+	actorType := models.ActorTypeUser
+	if actorID == "" || actorID == "system" {
+		actorType = models.ActorTypeSystem
+	}
+
+	log := &models.ActivityLog{
+		TenantID:  tenantID,
+		EventType: eventType,
+		ActorType: actorType,
+		ActorID:   actorID,
+		Metadata:  metadata,
+		Timestamp: time.Now(),
+	}
+
+	return appendActivityLog(ctx, s.activityLogRepo, log)
+}
+
+// visibilityRequiresAuthorization reports whether the visibility exposes the property outside the tenant
+func visibilityRequiresAuthorization(visibility models.PropertyVisibility) bool {
+	return visibility == models.PropertyVisibilityPublic || visibility == models.PropertyVisibilityMarketplace
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// fakeSaleAuthorizationStore keeps sale authorizations in memory, per tenant
+type fakeSaleAuthorizationStore struct {
+	authorizations map[string][]*models.SaleAuthorization // tenant ID -> authorizations
+}
+
+func newFakeSaleAuthorizationStore(authorizations ...*models.SaleAuthorization) *fakeSaleAuthorizationStore {
+	store := &fakeSaleAuthorizationStore{authorizations: make(map[string][]*models.SaleAuthorization)}
+	for _, authorization := range authorizations {
+		store.authorizations[authorization.TenantID] = append(store.authorizations[authorization.TenantID], authorization)
+	}
+	return store
+}
+
+func (f *fakeSaleAuthorizationStore) Create(ctx context.Context, authorization *models.SaleAuthorization) error {
+	f.authorizations[authorization.TenantID] = append(f.authorizations[authorization.TenantID], authorization)
+	return nil
+}
+
+func (f *fakeSaleAuthorizationStore) Get(ctx context.Context, tenantID, id string) (*models.SaleAuthorization, error) {
+	for _, authorization := range f.authorizations[tenantID] {
+		if authorization.ID == id {
+			return authorization, nil
+		}
+	}
+	return nil, repositories.ErrNotFound
+}
+
+func (f *fakeSaleAuthorizationStore) Update(ctx context.Context, tenantID, id string, updates map[string]interface{}) error {
+	return nil
+}
+
+func (f *fakeSaleAuthorizationStore) ListByProperty(ctx context.Context, tenantID, propertyID string) ([]*models.SaleAuthorization, error) {
+	var authorizations []*models.SaleAuthorization
+	for _, authorization := range f.authorizations[tenantID] {
+		if authorization.PropertyID == propertyID {
+			authorizations = append(authorizations, authorization)
+		}
+	}
+	return authorizations, nil
+}
+
+func (f *fakeSaleAuthorizationStore) ListActiveEndingBefore(ctx context.Context, tenantID string, before time.Time) ([]*models.SaleAuthorization, error) {
+	return nil, nil
+}
+
+// newSaleAuthorizationTestService creates a service for two tenants: tenant-1 requires sale authorizations, tenant-2 does not
+func newSaleAuthorizationTestService(authorizations ...*models.SaleAuthorization) *SaleAuthorizationService {
+	tenants := NewMockTenantRepository()
+	tenants.tenants["tenant-1"] = &models.Tenant{ID: "tenant-1", Settings: map[string]interface{}{models.TenantSettingRequireSaleAuthorization: true}}
+	tenants.tenants["tenant-2"] = &models.Tenant{ID: "tenant-2"}
+
+	return NewSaleAuthorizationService(newFakeSaleAuthorizationStore(authorizations...), nil, nil, nil, tenants, nil, nil, nil)
+}
+
+// saleAuthorization returns a signed authorization in force for the property
+func saleAuthorization(tenantID, propertyID string) *models.SaleAuthorization {
+	now := time.Now()
+	return &models.SaleAuthorization{
+		ID:               "auth-" + propertyID,
+		TenantID:         tenantID,
+		PropertyID:       propertyID,
+		StartDate:        now.AddDate(0, -1, 0),
+		EndDate:          now.AddDate(0, 2, 0),
+		SignedDocumentID: "doc-" + propertyID,
+		Status:           models.SaleAuthorizationStatusActive,
+	}
+}
+
+func TestCheckVisibilityAllowedRequiresAValidAuthorization(t *testing.T) {
+	unsigned := saleAuthorization("tenant-1", "prop-unsigned")
+	unsigned.SignedDocumentID = ""
+
+	expired := saleAuthorization("tenant-1", "prop-expired")
+	expired.EndDate = time.Now().AddDate(0, 0, -1)
+
+	revoked := saleAuthorization("tenant-1", "prop-revoked")
+	revoked.Status = models.SaleAuthorizationStatusRevoked
+
+	service := newSaleAuthorizationTestService(
+		saleAuthorization("tenant-1", "prop-authorized"),
+		unsigned,
+		expired,
+		revoked,
+		saleAuthorization("tenant-2", "prop-other-tenant"),
+	)
+	ctx := context.Background()
+
+	for _, visibility := range []models.PropertyVisibility{models.PropertyVisibilityPublic, models.PropertyVisibilityMarketplace} {
+		assert.NoError(t, service.CheckVisibilityAllowed(ctx, "tenant-1", "prop-authorized", visibility), visibility)
+
+		for _, propertyID := range []string{"prop-missing", "prop-unsigned", "prop-expired", "prop-revoked"} {
+			assert.ErrorIs(t, service.CheckVisibilityAllowed(ctx, "tenant-1", propertyID, visibility), ErrSaleAuthorizationRequired, propertyID)
+		}
+
+		// The authorization of another tenant does not count
+		assert.ErrorIs(t, service.CheckVisibilityAllowed(ctx, "tenant-1", "prop-other-tenant", visibility), ErrSaleAuthorizationRequired)
+	}
+
+	// Private and network listings stay inside the tenant and never need an authorization
+	assert.NoError(t, service.CheckVisibilityAllowed(ctx, "tenant-1", "prop-missing", models.PropertyVisibilityPrivate))
+	assert.NoError(t, service.CheckVisibilityAllowed(ctx, "tenant-1", "prop-missing", models.PropertyVisibilityNetwork))
+}
+
+func TestCheckVisibilityAllowedWithoutTenantRequirement(t *testing.T) {
+	service := newSaleAuthorizationTestService()
+
+	assert.NoError(t, service.CheckVisibilityAllowed(context.Background(), "tenant-2", "prop-missing", models.PropertyVisibilityPublic))
+	assert.Error(t, service.CheckVisibilityAllowed(context.Background(), "tenant-unknown", "prop-missing", models.PropertyVisibilityPublic))
+}
+
+func TestRequiresAuthorization(t *testing.T) {
+	service := newSaleAuthorizationTestService()
+	ctx := context.Background()
+
+	assert.True(t, service.RequiresAuthorization(ctx, "tenant-1", models.PropertyVisibilityPublic))
+	assert.True(t, service.RequiresAuthorization(ctx, "tenant-1", models.PropertyVisibilityMarketplace))
+	assert.False(t, service.RequiresAuthorization(ctx, "tenant-1", models.PropertyVisibilityNetwork))
+	assert.False(t, service.RequiresAuthorization(ctx, "tenant-2", models.PropertyVisibilityPublic))
+}
+
+func TestHasValidAuthorization(t *testing.T) {
+	pending := saleAuthorization("tenant-1", "prop-pending")
+	pending.StartDate = time.Now().AddDate(0, 0, 7)
+
+	service := newSaleAuthorizationTestService(saleAuthorization("tenant-1", "prop-authorized"), pending)
+	ctx := context.Background()
+
+	valid, err := service.HasValidAuthorization(ctx, "tenant-1", "prop-authorized")
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	// Signed but not in force yet
+	valid, err = service.HasValidAuthorization(ctx, "tenant-1", "prop-pending")
+	assert.NoError(t, err)
+	assert.False(t, valid)
+
+	valid, err = service.HasValidAuthorization(ctx, "tenant-2", "prop-authorized")
+	assert.NoError(t, err)
+	assert.False(t, valid)
+}
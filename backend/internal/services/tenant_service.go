@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"regexp"
 	"strings"
@@ -16,6 +17,19 @@ import (
 	"github.com/altatech/ecosistema-imob/backend/internal/utils"
 )
 
+// ErrTenantFieldReadOnly is returned when a tenant update sets a field owned by the plan and billing flows
+var ErrTenantFieldReadOnly = errors.New("field cannot be updated through the tenant endpoint")
+
+// tenantReadOnlyFields are changed only by PlanService and BillingService (subscription) or directly in the
+// database (platform admin flag)
+var tenantReadOnlyFields = []string{
+	"subscription_plan",
+	"subscription_status",
+	"trial_ends_at",
+	"subscription_started_at",
+	"is_platform_admin",
+}
+
 // TenantService handles business logic for tenant management
 type TenantService struct {
 	tenantRepo      *repositories.TenantRepository
@@ -134,6 +148,13 @@ func (s *TenantService) CreateTenant(ctx context.Context, tenant *models.Tenant)
 
 	// Set active by default
 	tenant.IsActive = true
+	tenant.IsPlatformAdmin = false
+
+	// New tenants start with a trial of the full plan (expired by PlanService.ExpireTrials)
+	trialEndsAt := time.Now().AddDate(0, 0, models.DefaultTrialDays)
+	tenant.SubscriptionPlan = models.SubscriptionPlanFull
+	tenant.SubscriptionStatus = models.SubscriptionStatusTrial
+	tenant.TrialEndsAt = &trialEndsAt
 
 	// Create tenant in repository
 	if err := s.tenantRepo.Create(ctx, tenant); err != nil {
@@ -186,12 +207,39 @@ func (s *TenantService) UpdateTenant(ctx context.Context, id string, updates map
 		return fmt.Errorf("tenant ID is required")
 	}
 
+	for _, field := range tenantReadOnlyFields {
+		if _, ok := updates[field]; ok {
+			return fmt.Errorf("%w: %s", ErrTenantFieldReadOnly, field)
+		}
+	}
+
 	// Validate tenant exists
 	existing, err := s.tenantRepo.Get(ctx, id)
 	if err != nil {
 		return fmt.Errorf("tenant not found: %w", err)
 	}
 
+	// Branding is validated as a whole by UpdateBranding
+	if _, ok := updates["branding"]; ok {
+		return fmt.Errorf("branding must be updated through the branding endpoint")
+	}
+
+	// Validate the WhatsApp message template if being updated
+	template, hasTemplate := updates["settings."+models.TenantSettingWhatsAppMessageTemplate].(string)
+	if settings, ok := updates["settings"].(map[string]interface{}); ok {
+		template, hasTemplate = settings[models.TenantSettingWhatsAppMessageTemplate].(string)
+	}
+	if hasTemplate {
+		if err := models.ValidateWhatsAppMessageTemplate(template); err != nil {
+			return err
+		}
+	}
+
+	// The primary domain must be a verified custom domain (DomainService.SetPrimaryDomain)
+	if _, ok := updates["primary_domain"]; ok {
+		return fmt.Errorf("primary_domain must be updated through the domains endpoint")
+	}
+
 	// Validate slug if being updated
 	if slug, ok := updates["slug"].(string); ok {
 		normalized := s.NormalizeSlug(slug)
@@ -299,6 +347,73 @@ func (s *TenantService) UpdateTenant(ctx context.Context, id string, updates map
 	return nil
 }
 
+// GetBranding returns the stored branding of a tenant and the resolved branding (with the fallbacks applied)
+func (s *TenantService) GetBranding(ctx context.Context, tenantID string) (*models.TenantBranding, *models.PublicTenantBranding, error) {
+	tenant, err := s.GetTenant(ctx, tenantID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	branding := tenant.Branding
+	if branding == nil {
+		branding = &models.TenantBranding{}
+	}
+
+	return branding, tenant.PublicBranding(), nil
+}
+
+// GetPublicBranding returns the resolved branding of an active tenant by slug (public sites)
+func (s *TenantService) GetPublicBranding(ctx context.Context, slug string) (*models.PublicTenantBranding, error) {
+	tenant, err := s.GetTenantBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	if !tenant.IsActive {
+		return nil, fmt.Errorf("failed to get tenant by slug: %w", repositories.ErrNotFound)
+	}
+
+	return tenant.PublicBranding(), nil
+}
+
+// UpdateBranding validates and replaces the branding of a tenant
+func (s *TenantService) UpdateBranding(ctx context.Context, tenantID string, branding *models.TenantBranding, actorID string) (*models.PublicTenantBranding, error) {
+	tenant, err := s.GetTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	branding.WhatsAppNumber = regexp.MustCompile(`[^\d]`).ReplaceAllString(branding.WhatsAppNumber, "")
+	if err := branding.Validate(); err != nil {
+		return nil, err
+	}
+	if branding.ReplyTo != "" {
+		if err := utils.ValidateEmail(branding.ReplyTo); err != nil {
+			return nil, fmt.Errorf("invalid reply_to: %w", err)
+		}
+		branding.ReplyTo = utils.NormalizeEmail(branding.ReplyTo)
+	}
+	if branding.CRECI != "" {
+		if err := utils.ValidateCRECI(branding.CRECI); err != nil {
+			return nil, fmt.Errorf("invalid CRECI: %w", err)
+		}
+		branding.CRECI = utils.NormalizeCRECI(branding.CRECI)
+	}
+
+	if err := s.tenantRepo.Update(ctx, tenantID, map[string]interface{}{
+		"branding": branding,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to update branding: %w", err)
+	}
+
+	_ = s.logActivity(ctx, tenantID, "tenant_branding_updated", models.ActorTypeUser, actorID, map[string]interface{}{
+		"sender_name": branding.SenderName,
+		"has_logo":    branding.LogoURL != "",
+	})
+
+	tenant.Branding = branding
+	return tenant.PublicBranding(), nil
+}
+
 // DeleteTenant deletes a tenant
 func (s *TenantService) DeleteTenant(ctx context.Context, id string) error {
 	if id == "" {
@@ -485,5 +600,5 @@ func (s *TenantService) logActivity(ctx context.Context, tenantID, eventType str
 		Timestamp: time.Now(),
 	}
 
-	return s.activityLogRepo.Create(ctx, log)
+	return appendActivityLog(ctx, s.activityLogRepo, log)
 }
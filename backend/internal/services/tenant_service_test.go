@@ -0,0 +1,20 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateTenantRejectsReadOnlyFields(t *testing.T) {
+	service := NewTenantService(nil, nil)
+
+	for _, field := range tenantReadOnlyFields {
+		err := service.UpdateTenant(context.Background(), "tenant-1", map[string]interface{}{
+			"name": "Imobiliária Sul",
+			field:  "full",
+		})
+		assert.ErrorIs(t, err, ErrTenantFieldReadOnly, field)
+	}
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// StorageUsageReader reports the bytes stored by a tenant (implemented by storage.StorageService)
+type StorageUsageReader interface {
+	TenantStorageBytes(ctx context.Context, tenantID string) (int64, error)
+}
+
+// UsageSnapshotResult summarizes a daily usage snapshot run
+type UsageSnapshotResult struct {
+	Date          string `json:"date"`
+	TenantsTotal  int    `json:"tenants_total"`
+	TenantsFailed int    `json:"tenants_failed"`
+}
+
+// UsageService meters the billable usage of tenants in daily rollups
+// Counters (imports, messages sent) are recorded when the event happens; gauges (active listings,
+// storage bytes) are snapshotted once a day by SnapshotDaily
+type UsageService struct {
+	usageRepo   *repositories.UsageRepository
+	tenantRepo  *repositories.TenantRepository
+	listingRepo *repositories.ListingRepository
+	storage     StorageUsageReader // Optional: storage bytes are not metered without it
+}
+
+// NewUsageService creates a new usage service
+func NewUsageService(
+	usageRepo *repositories.UsageRepository,
+	tenantRepo *repositories.TenantRepository,
+	listingRepo *repositories.ListingRepository,
+) *UsageService {
+	return &UsageService{
+		usageRepo:   usageRepo,
+		tenantRepo:  tenantRepo,
+		listingRepo: listingRepo,
+	}
+}
+
+// SetStorageUsageReader sets the storage usage reader (for dependency injection)
+func (s *UsageService) SetStorageUsageReader(storage StorageUsageReader) {
+	s.storage = storage
+}
+
+// Record increments a usage counter of today's rollup
+// Metering never blocks the metered operation: failures are only logged
+func (s *UsageService) Record(ctx context.Context, tenantID, metric string, delta int64) {
+	if tenantID == "" || delta == 0 {
+		return
+	}
+
+	if err := s.usageRepo.Increment(ctx, tenantID, MetricsDate(time.Now()), metric, delta); err != nil {
+		log.Printf("Failed to record %s usage for tenant %s: %v", metric, tenantID, err)
+	}
+}
+
+// SnapshotDaily records today's gauges (active listings, storage bytes) for every active tenant
+func (s *UsageService) SnapshotDaily(ctx context.Context) (*UsageSnapshotResult, error) {
+	tenants, err := s.tenantRepo.ListActive(ctx, repositories.PaginationOptions{Limit: 10000})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tenants: %w", err)
+	}
+
+	result := &UsageSnapshotResult{Date: MetricsDate(time.Now()), TenantsTotal: len(tenants)}
+	for _, tenant := range tenants {
+		if err := s.snapshotTenant(ctx, tenant.ID, result.Date); err != nil {
+			log.Printf("Failed to snapshot usage of tenant %s: %v", tenant.ID, err)
+			result.TenantsFailed++
+		}
+	}
+
+	return result, nil
+}
+
+// snapshotTenant records the gauges of a tenant for a day
+func (s *UsageService) snapshotTenant(ctx context.Context, tenantID, date string) error {
+	activeListings, err := s.listingRepo.CountActive(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+
+	gauges := map[string]int64{
+		models.UsageMetricActiveListings: int64(activeListings),
+	}
+	if s.storage != nil {
+		storageBytes, err := s.storage.TenantStorageBytes(ctx, tenantID)
+		if err != nil {
+			return err
+		}
+		gauges[models.UsageMetricStorageBytes] = storageBytes
+	}
+
+	return s.usageRepo.SetGauges(ctx, tenantID, date, gauges)
+}
+
+// GetMonthlyUsage aggregates the usage of a tenant for a period (YYYY-MM, empty = current month)
+func (s *UsageService) GetMonthlyUsage(ctx context.Context, tenantID, period string) (*models.MonthlyUsage, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+	if period == "" {
+		period = time.Now().In(metricsLocation).Format("2006-01")
+	}
+
+	start, end, period, err := ParseSnapshotPeriod(period)
+	if err != nil {
+		return nil, err
+	}
+
+	days, err := s.usageRepo.ListRange(ctx, tenantID, start.Format("2006-01-02"), end.AddDate(0, 0, -1).Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+
+	return models.AggregateMonthlyUsage(period, days), nil
+}
@@ -10,18 +10,40 @@ import (
 	"github.com/altatech/ecosistema-imob/backend/internal/utils"
 )
 
+// UserStore is the user persistence used by UserService (implemented by repositories.UserRepository)
+type UserStore interface {
+	Create(ctx context.Context, user *models.User) error
+	Get(ctx context.Context, tenantID, userID string) (*models.User, error)
+	GetByEmail(ctx context.Context, tenantID, email string) (*models.User, error)
+	GetByFirebaseUID(ctx context.Context, tenantID, firebaseUID string) (*models.User, error)
+	List(ctx context.Context, tenantID string) ([]*models.User, error)
+	ListActive(ctx context.Context, tenantID string) ([]*models.User, error)
+	Update(ctx context.Context, tenantID, userID string, updates map[string]interface{}) error
+	Delete(ctx context.Context, tenantID, userID string) error
+}
+
+// TenantGetter reads tenants (implemented by repositories.TenantRepository)
+type TenantGetter interface {
+	Get(ctx context.Context, id string) (*models.Tenant, error)
+}
+
+// ActivityLogWriter appends activity logs (implemented by repositories.ActivityLogRepository)
+type ActivityLogWriter interface {
+	Create(ctx context.Context, log *models.ActivityLog) error
+}
+
 // UserService handles business logic for administrative user management
 type UserService struct {
-	userRepo        *repositories.UserRepository
-	tenantRepo      *repositories.TenantRepository
-	activityLogRepo *repositories.ActivityLogRepository
+	userRepo        UserStore
+	tenantRepo      TenantGetter
+	activityLogRepo ActivityLogWriter
 }
 
 // NewUserService creates a new user service
 func NewUserService(
-	userRepo *repositories.UserRepository,
-	tenantRepo *repositories.TenantRepository,
-	activityLogRepo *repositories.ActivityLogRepository,
+	userRepo UserStore,
+	tenantRepo TenantGetter,
+	activityLogRepo ActivityLogWriter,
 ) *UserService {
 	return &UserService{
 		userRepo:        userRepo,
@@ -394,5 +416,5 @@ func (s *UserService) logActivity(ctx context.Context, tenantID, eventType strin
 		Timestamp: time.Now(),
 	}
 
-	return s.activityLogRepo.Create(ctx, log)
+	return appendActivityLog(ctx, s.activityLogRepo, log)
 }
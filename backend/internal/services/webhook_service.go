@@ -0,0 +1,798 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"cloud.google.com/go/firestore"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+const (
+	// webhookDispatchPageSize is the number of activity log entries read per query
+	webhookDispatchPageSize = 200
+
+	// maxWebhookDispatchEntries bounds the events turned into deliveries in a single run
+	maxWebhookDispatchEntries = 2000
+
+	// defaultWebhookSendBatchSize is the number of due deliveries sent in a single run
+	defaultWebhookSendBatchSize = 100
+
+	// maxWebhookReplayEntries bounds a replay by sequence range
+	maxWebhookReplayEntries = 1000
+
+	// webhookClaimLease keeps a claimed delivery away from concurrent runs while it is sent
+	webhookClaimLease = 2 * time.Minute
+
+	// webhookRequestTimeout bounds each HTTP attempt
+	webhookRequestTimeout = 10 * time.Second
+
+	// webhookResponseDrainSize is the response body read (and discarded) so connections can be reused
+	webhookResponseDrainSize = 4 << 10
+)
+
+// ErrWebhookAddressBlocked is returned when a webhook URL points to a loopback, private or link-local address
+var ErrWebhookAddressBlocked = errors.New("webhook url must point to a public address")
+
+// WebhookService manages outbound webhooks (site builders, BI, partner CRMs)
+// Events come from the ActivityLog hash chain: DispatchEvents turns new entries into one delivery per
+// matching subscription and ProcessDeliveries sends them, signed with the subscription secret
+// Failed deliveries are retried with exponential backoff (models.WebhookRetryDelay) and dead-lettered
+// after models.WebhookMaxAttempts; any delivery can be replayed
+type WebhookService struct {
+	webhookRepo     *repositories.WebhookRepository
+	activityLogRepo *repositories.ActivityLogRepository
+	httpClient      *http.Client
+	allowInsecure   bool // plain HTTP and private addresses (local development only)
+}
+
+// NewWebhookService creates a new webhook service
+func NewWebhookService(
+	webhookRepo *repositories.WebhookRepository,
+	activityLogRepo *repositories.ActivityLogRepository,
+) *WebhookService {
+	return &WebhookService{
+		webhookRepo:     webhookRepo,
+		activityLogRepo: activityLogRepo,
+		httpClient:      newWebhookHTTPClient(false),
+	}
+}
+
+// SetAllowInsecureURLs allows plain HTTP and private/loopback webhook URLs (for local development receivers)
+func (s *WebhookService) SetAllowInsecureURLs(allow bool) {
+	s.allowInsecure = allow
+	s.httpClient = newWebhookHTTPClient(allow)
+}
+
+// newWebhookHTTPClient creates the client that posts deliveries
+// Tenant-supplied URLs must not reach internal services: every connection is checked after DNS resolution
+// (blocks rebinding to private addresses), no proxy is used and redirects are not followed (a 3xx is a failed attempt)
+func newWebhookHTTPClient(allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: webhookRequestTimeout}
+	if !allowPrivate {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || isBlockedWebhookIP(ip) {
+				return fmt.Errorf("%w: %s", ErrWebhookAddressBlocked, host)
+			}
+			return nil
+		}
+	}
+
+	return &http.Client{
+		Timeout: webhookRequestTimeout,
+		Transport: &http.Transport{
+			Proxy:               nil,
+			DialContext:         dialer.DialContext,
+			ForceAttemptHTTP2:   true,
+			TLSHandshakeTimeout: webhookRequestTimeout,
+			MaxIdleConns:        100,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// CreateWebhookSubscriptionInput represents a new subscription
+type CreateWebhookSubscriptionInput struct {
+	TenantID    string
+	Name        string
+	URL         string
+	Events      []string
+	Description string
+	CreatedBy   string
+}
+
+// UpdateWebhookSubscriptionInput represents a subscription update (nil fields are unchanged)
+type UpdateWebhookSubscriptionInput struct {
+	Name        *string
+	URL         *string
+	Events      []string
+	Description *string
+	Active      *bool
+}
+
+// WebhookRunResult summarizes a webhook run (dispatch + send)
+type WebhookRunResult struct {
+	EventsDispatched   int   `json:"events_dispatched"`
+	DeliveriesQueued   int   `json:"deliveries_queued"`
+	DeliveriesSent     int   `json:"deliveries_sent"`
+	Delivered          int   `json:"delivered"`
+	Failed             int   `json:"failed"`        // retry scheduled
+	DeadLettered       int   `json:"dead_lettered"` // attempts exhausted
+	LastSequence       int64 `json:"last_sequence"` // dispatcher cursor after the run
+	DispatchInProgress bool  `json:"dispatch_in_progress"`
+}
+
+// CreateSubscription creates a webhook subscription with a new signing secret
+// The secret is only returned here and by RotateSecret
+func (s *WebhookService) CreateSubscription(ctx context.Context, input CreateWebhookSubscriptionInput) (*models.WebhookSubscription, error) {
+	if input.TenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+	if strings.TrimSpace(input.Name) == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	if err := validateWebhookURL(input.URL, s.allowInsecure); err != nil {
+		return nil, err
+	}
+	if err := validateWebhookEvents(input.Events); err != nil {
+		return nil, err
+	}
+
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	subscription := &models.WebhookSubscription{
+		TenantID:    input.TenantID,
+		Name:        strings.TrimSpace(input.Name),
+		URL:         input.URL,
+		Events:      input.Events,
+		Description: input.Description,
+		Active:      true,
+		Secret:      secret,
+		CreatedBy:   input.CreatedBy,
+	}
+
+	if err := s.webhookRepo.CreateSubscription(ctx, subscription); err != nil {
+		return nil, err
+	}
+
+	// First subscription: start the dispatcher at the current head (past events are only sent by replay)
+	if _, err := s.ensureDispatchState(ctx, input.TenantID); err != nil {
+		log.Printf("Failed to initialize webhook dispatcher for tenant %s: %v", input.TenantID, err)
+	}
+
+	_ = s.logActivity(ctx, input.TenantID, "webhook_subscription_created", input.CreatedBy, map[string]interface{}{
+		"subscription_id": subscription.ID,
+		"url":             subscription.URL,
+		"events":          subscription.Events,
+	})
+
+	return subscription, nil
+}
+
+// GetSubscription retrieves a webhook subscription
+func (s *WebhookService) GetSubscription(ctx context.Context, tenantID, id string) (*models.WebhookSubscription, error) {
+	return s.webhookRepo.GetSubscription(ctx, tenantID, id)
+}
+
+// ListSubscriptions lists the webhook subscriptions of a tenant
+func (s *WebhookService) ListSubscriptions(ctx context.Context, tenantID string) ([]*models.WebhookSubscription, error) {
+	return s.webhookRepo.ListSubscriptions(ctx, tenantID, false)
+}
+
+// UpdateSubscription updates a webhook subscription
+func (s *WebhookService) UpdateSubscription(ctx context.Context, tenantID, id string, input UpdateWebhookSubscriptionInput, actorID string) (*models.WebhookSubscription, error) {
+	if _, err := s.webhookRepo.GetSubscription(ctx, tenantID, id); err != nil {
+		return nil, err
+	}
+
+	updates := []firestore.Update{}
+	changed := []string{}
+	if input.Name != nil {
+		if strings.TrimSpace(*input.Name) == "" {
+			return nil, fmt.Errorf("name is required")
+		}
+		updates = append(updates, firestore.Update{Path: "name", Value: strings.TrimSpace(*input.Name)})
+		changed = append(changed, "name")
+	}
+	if input.URL != nil {
+		if err := validateWebhookURL(*input.URL, s.allowInsecure); err != nil {
+			return nil, err
+		}
+		updates = append(updates, firestore.Update{Path: "url", Value: *input.URL})
+		changed = append(changed, "url")
+	}
+	if input.Events != nil {
+		if err := validateWebhookEvents(input.Events); err != nil {
+			return nil, err
+		}
+		updates = append(updates, firestore.Update{Path: "events", Value: input.Events})
+		changed = append(changed, "events")
+	}
+	if input.Description != nil {
+		updates = append(updates, firestore.Update{Path: "description", Value: *input.Description})
+		changed = append(changed, "description")
+	}
+	if input.Active != nil {
+		updates = append(updates, firestore.Update{Path: "active", Value: *input.Active})
+		changed = append(changed, "active")
+	}
+	if len(updates) == 0 {
+		return nil, fmt.Errorf("no fields to update")
+	}
+
+	if err := s.webhookRepo.UpdateSubscription(ctx, tenantID, id, updates); err != nil {
+		return nil, err
+	}
+
+	_ = s.logActivity(ctx, tenantID, "webhook_subscription_updated", actorID, map[string]interface{}{
+		"subscription_id": id,
+		"changed_fields":  changed,
+	})
+
+	return s.webhookRepo.GetSubscription(ctx, tenantID, id)
+}
+
+// DeleteSubscription deletes a webhook subscription
+// Queued deliveries are dead-lettered when their next attempt is due
+func (s *WebhookService) DeleteSubscription(ctx context.Context, tenantID, id, actorID string) error {
+	if err := s.webhookRepo.DeleteSubscription(ctx, tenantID, id); err != nil {
+		return err
+	}
+
+	_ = s.logActivity(ctx, tenantID, "webhook_subscription_deleted", actorID, map[string]interface{}{
+		"subscription_id": id,
+	})
+
+	return nil
+}
+
+// RotateSecret replaces the signing secret of a subscription
+// Queued deliveries are signed with the new secret when sent
+func (s *WebhookService) RotateSecret(ctx context.Context, tenantID, id, actorID string) (*models.WebhookSubscription, error) {
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.webhookRepo.UpdateSubscription(ctx, tenantID, id, []firestore.Update{
+		{Path: "secret", Value: secret},
+	}); err != nil {
+		return nil, err
+	}
+
+	_ = s.logActivity(ctx, tenantID, "webhook_secret_rotated", actorID, map[string]interface{}{
+		"subscription_id": id,
+	})
+
+	return s.webhookRepo.GetSubscription(ctx, tenantID, id)
+}
+
+// RunWebhooks dispatches new events and sends the due deliveries of a tenant
+// This endpoint should be called by a cron job every minute
+func (s *WebhookService) RunWebhooks(ctx context.Context, tenantID string) (*WebhookRunResult, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+
+	result := &WebhookRunResult{}
+	if err := s.DispatchEvents(ctx, tenantID, result); err != nil {
+		return nil, err
+	}
+	if err := s.ProcessDeliveries(ctx, tenantID, defaultWebhookSendBatchSize, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// DispatchEvents reads the activity log chain after the dispatcher cursor and queues one delivery per
+// matching subscription. The delivery ID ({subscriptionId}_{activityLogId}) makes re-runs idempotent
+func (s *WebhookService) DispatchEvents(ctx context.Context, tenantID string, result *WebhookRunResult) error {
+	state, err := s.ensureDispatchState(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	cursor := state.LastSequence
+	result.LastSequence = cursor
+
+	subscriptions, err := s.webhookRepo.ListSubscriptions(ctx, tenantID, true)
+	if err != nil {
+		return err
+	}
+
+	for result.EventsDispatched < maxWebhookDispatchEntries {
+		logs, err := s.activityLogRepo.ListChain(ctx, tenantID, cursor, webhookDispatchPageSize)
+		if err != nil {
+			return err
+		}
+
+		for _, activityLog := range logs {
+			for _, subscription := range subscriptions {
+				if !subscription.Matches(activityLog.EventType) {
+					continue
+				}
+				queued, err := s.queueDelivery(ctx, subscription, activityLog, webhookDeliveryID(subscription.ID, activityLog.ID), "")
+				if err != nil {
+					return err
+				}
+				if queued {
+					result.DeliveriesQueued++
+				}
+			}
+			cursor = activityLog.Sequence
+			result.EventsDispatched++
+		}
+
+		if len(logs) > 0 {
+			if err := s.webhookRepo.SetDispatchState(ctx, tenantID, cursor); err != nil {
+				return err
+			}
+		}
+		if len(logs) < webhookDispatchPageSize {
+			result.LastSequence = cursor
+			return nil
+		}
+	}
+
+	result.LastSequence = cursor
+	result.DispatchInProgress = true
+	return nil
+}
+
+// QueueEvent queues the deliveries of one activity log entry without waiting for the next dispatch
+// (outbox consumer); DispatchEvents finds the same deliveries already queued when it reaches the entry
+func (s *WebhookService) QueueEvent(ctx context.Context, tenantID, activityLogID string) error {
+	activityLog, err := s.activityLogRepo.Get(ctx, tenantID, activityLogID)
+	if err != nil {
+		return err
+	}
+
+	subscriptions, err := s.webhookRepo.ListSubscriptions(ctx, tenantID, true)
+	if err != nil {
+		return err
+	}
+
+	for _, subscription := range subscriptions {
+		if !subscription.Matches(activityLog.EventType) {
+			continue
+		}
+		if _, err := s.queueDelivery(ctx, subscription, activityLog, webhookDeliveryID(subscription.ID, activityLog.ID), ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ProcessDeliveries sends up to limit due deliveries (pending or retrying)
+func (s *WebhookService) ProcessDeliveries(ctx context.Context, tenantID string, limit int, result *WebhookRunResult) error {
+	now := time.Now()
+	deliveries, err := s.webhookRepo.ListDueDeliveries(ctx, tenantID, now, limit)
+	if err != nil {
+		return err
+	}
+
+	subscriptions := make(map[string]*models.WebhookSubscription)
+	for _, delivery := range deliveries {
+		claimed, err := s.webhookRepo.ClaimDelivery(ctx, tenantID, delivery.ID, now, webhookClaimLease)
+		if err != nil {
+			log.Printf("Failed to claim webhook delivery %s: %v", delivery.ID, err)
+			continue
+		}
+		if !claimed {
+			continue // Sent by a concurrent run
+		}
+
+		subscription, ok := subscriptions[delivery.SubscriptionID]
+		if !ok {
+			subscription, err = s.webhookRepo.GetSubscription(ctx, tenantID, delivery.SubscriptionID)
+			if err != nil && err != repositories.ErrNotFound {
+				return err
+			}
+			subscriptions[delivery.SubscriptionID] = subscription
+		}
+
+		var status models.WebhookDeliveryStatus
+		if subscription == nil || !subscription.Active {
+			status, err = s.deadLetter(ctx, delivery, "subscription deleted or inactive")
+		} else {
+			status, err = s.attemptDelivery(ctx, subscription, delivery)
+		}
+		if err != nil {
+			log.Printf("Failed to record webhook delivery %s: %v", delivery.ID, err)
+			continue
+		}
+
+		result.DeliveriesSent++
+		switch status {
+		case models.WebhookDeliveryDelivered:
+			result.Delivered++
+		case models.WebhookDeliveryDead:
+			result.DeadLettered++
+		default:
+			result.Failed++
+		}
+	}
+
+	return nil
+}
+
+// ListDeliveries lists the delivery log (newest first)
+func (s *WebhookService) ListDeliveries(ctx context.Context, tenantID string, filters *repositories.WebhookDeliveryFilters, limit int) ([]*models.WebhookDelivery, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	return s.webhookRepo.ListDeliveries(ctx, tenantID, filters, repositories.PaginationOptions{Limit: limit})
+}
+
+// GetDelivery retrieves a delivery with its attempt log
+func (s *WebhookService) GetDelivery(ctx context.Context, tenantID, id string) (*models.WebhookDelivery, error) {
+	return s.webhookRepo.GetDelivery(ctx, tenantID, id)
+}
+
+// ReplayDelivery queues a new delivery of the same event (e.g. after fixing the endpoint of a dead-lettered one)
+func (s *WebhookService) ReplayDelivery(ctx context.Context, tenantID, deliveryID, actorID string) (*models.WebhookDelivery, error) {
+	original, err := s.webhookRepo.GetDelivery(ctx, tenantID, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	subscription, err := s.webhookRepo.GetSubscription(ctx, tenantID, original.SubscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	if !subscription.Active {
+		return nil, fmt.Errorf("subscription is inactive")
+	}
+
+	replay := &models.WebhookDelivery{
+		TenantID:       tenantID,
+		SubscriptionID: original.SubscriptionID,
+		EventType:      original.EventType,
+		ActivityLogID:  original.ActivityLogID,
+		Sequence:       original.Sequence,
+		Payload:        original.Payload,
+		Status:         models.WebhookDeliveryPending,
+		NextAttemptAt:  time.Now(),
+		ReplayOf:       original.ID,
+	}
+	if err := s.webhookRepo.CreateDelivery(ctx, replay); err != nil {
+		return nil, err
+	}
+
+	_ = s.logActivity(ctx, tenantID, "webhook_replay_requested", actorID, map[string]interface{}{
+		"subscription_id": original.SubscriptionID,
+		"delivery_id":     original.ID,
+		"replay_id":       replay.ID,
+	})
+
+	return replay, nil
+}
+
+// ReplaySubscription queues the events with fromSequence <= sequence <= toSequence again for a subscription
+// (e.g. to backfill a new integration); toSequence 0 means up to the dispatcher cursor
+func (s *WebhookService) ReplaySubscription(ctx context.Context, tenantID, subscriptionID string, fromSequence, toSequence int64, actorID string) (int, error) {
+	if fromSequence <= 0 {
+		return 0, fmt.Errorf("from_sequence must be greater than zero")
+	}
+
+	subscription, err := s.webhookRepo.GetSubscription(ctx, tenantID, subscriptionID)
+	if err != nil {
+		return 0, err
+	}
+	if !subscription.Active {
+		return 0, fmt.Errorf("subscription is inactive")
+	}
+
+	// Events after the cursor are still dispatched normally
+	state, err := s.ensureDispatchState(ctx, tenantID)
+	if err != nil {
+		return 0, err
+	}
+	if toSequence <= 0 || toSequence > state.LastSequence {
+		toSequence = state.LastSequence
+	}
+	if toSequence < fromSequence {
+		return 0, fmt.Errorf("no dispatched events in the requested range")
+	}
+	if toSequence-fromSequence+1 > maxWebhookReplayEntries {
+		return 0, fmt.Errorf("replay range is limited to %d events", maxWebhookReplayEntries)
+	}
+
+	queued := 0
+	cursor := fromSequence - 1
+	for cursor < toSequence {
+		logs, err := s.activityLogRepo.ListChain(ctx, tenantID, cursor, webhookDispatchPageSize)
+		if err != nil {
+			return queued, err
+		}
+
+		for _, activityLog := range logs {
+			if activityLog.Sequence > toSequence {
+				break
+			}
+			cursor = activityLog.Sequence
+			if !subscription.Matches(activityLog.EventType) {
+				continue
+			}
+			ok, err := s.queueDelivery(ctx, subscription, activityLog, "", webhookDeliveryID(subscription.ID, activityLog.ID))
+			if err != nil {
+				return queued, err
+			}
+			if ok {
+				queued++
+			}
+		}
+
+		if len(logs) < webhookDispatchPageSize {
+			break
+		}
+	}
+
+	_ = s.logActivity(ctx, tenantID, "webhook_replay_requested", actorID, map[string]interface{}{
+		"subscription_id": subscriptionID,
+		"from_sequence":   fromSequence,
+		"to_sequence":     toSequence,
+		"queued":          queued,
+	})
+
+	return queued, nil
+}
+
+// ensureDispatchState returns the dispatcher cursor, starting it at the current chain head on the first run
+func (s *WebhookService) ensureDispatchState(ctx context.Context, tenantID string) (*models.WebhookDispatchState, error) {
+	state, err := s.webhookRepo.GetDispatchState(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if state != nil {
+		return state, nil
+	}
+
+	head, err := s.activityLogRepo.GetChainHead(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.webhookRepo.SetDispatchState(ctx, tenantID, head.Sequence); err != nil {
+		return nil, err
+	}
+	return &models.WebhookDispatchState{LastSequence: head.Sequence, UpdatedAt: time.Now()}, nil
+}
+
+// queueDelivery creates a pending delivery of an event (false when it was already queued)
+func (s *WebhookService) queueDelivery(ctx context.Context, subscription *models.WebhookSubscription, activityLog *models.ActivityLog, deliveryID, replayOf string) (bool, error) {
+	payload, err := json.Marshal(models.NewWebhookEventPayload(activityLog))
+	if err != nil {
+		return false, fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	delivery := &models.WebhookDelivery{
+		ID:             deliveryID,
+		TenantID:       subscription.TenantID,
+		SubscriptionID: subscription.ID,
+		EventType:      activityLog.EventType,
+		ActivityLogID:  activityLog.ID,
+		Sequence:       activityLog.Sequence,
+		Payload:        string(payload),
+		Status:         models.WebhookDeliveryPending,
+		NextAttemptAt:  time.Now(),
+		ReplayOf:       replayOf,
+	}
+	if err := s.webhookRepo.CreateDelivery(ctx, delivery); err != nil {
+		if err == repositories.ErrAlreadyExists {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// attemptDelivery posts a delivery and records the attempt (delivered, retry scheduled or dead-lettered)
+func (s *WebhookService) attemptDelivery(ctx context.Context, subscription *models.WebhookSubscription, delivery *models.WebhookDelivery) (models.WebhookDeliveryStatus, error) {
+	attempt := s.send(ctx, subscription, delivery)
+	attempts := delivery.Attempts + 1
+
+	updates := []firestore.Update{
+		{Path: "attempts", Value: attempts},
+		{Path: "attempt_log", Value: firestore.ArrayUnion(attempt)},
+	}
+
+	var status models.WebhookDeliveryStatus
+	switch {
+	case attempt.StatusCode >= 200 && attempt.StatusCode < 300:
+		status = models.WebhookDeliveryDelivered
+		updates = append(updates, firestore.Update{Path: "delivered_at", Value: attempt.AttemptedAt})
+	case attempts >= models.WebhookMaxAttempts:
+		status = models.WebhookDeliveryDead
+	default:
+		status = models.WebhookDeliveryRetrying
+		updates = append(updates, firestore.Update{Path: "next_attempt_at", Value: attempt.AttemptedAt.Add(models.WebhookRetryDelay(attempts))})
+	}
+	updates = append(updates, firestore.Update{Path: "status", Value: string(status)})
+
+	if err := s.webhookRepo.UpdateDelivery(ctx, delivery.TenantID, delivery.ID, updates); err != nil {
+		return "", err
+	}
+
+	if status == models.WebhookDeliveryDead {
+		s.logDeadLetter(ctx, delivery, attempts, attemptError(attempt))
+	}
+	return status, nil
+}
+
+// deadLetter moves a delivery to the dead-letter state without sending it
+func (s *WebhookService) deadLetter(ctx context.Context, delivery *models.WebhookDelivery, reason string) (models.WebhookDeliveryStatus, error) {
+	if err := s.webhookRepo.UpdateDelivery(ctx, delivery.TenantID, delivery.ID, []firestore.Update{
+		{Path: "status", Value: string(models.WebhookDeliveryDead)},
+		{Path: "attempt_log", Value: firestore.ArrayUnion(models.WebhookAttempt{AttemptedAt: time.Now(), Error: reason})},
+	}); err != nil {
+		return "", err
+	}
+
+	s.logDeadLetter(ctx, delivery, delivery.Attempts, reason)
+	return models.WebhookDeliveryDead, nil
+}
+
+// send performs one signed HTTP POST of a delivery
+func (s *WebhookService) send(ctx context.Context, subscription *models.WebhookSubscription, delivery *models.WebhookDelivery) models.WebhookAttempt {
+	started := time.Now()
+	attempt := models.WebhookAttempt{AttemptedAt: started}
+
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "EcosistemaImob-Webhooks/1.0")
+	req.Header.Set("X-Webhook-ID", delivery.ActivityLogID) // Idempotency key (same for retries and replays)
+	req.Header.Set("X-Webhook-Delivery", delivery.ID)
+	req.Header.Set("X-Webhook-Event", delivery.EventType)
+	req.Header.Set("X-Webhook-Signature", models.SignWebhookPayload(subscription.Secret, started, body))
+
+	resp, err := s.httpClient.Do(req)
+	attempt.DurationMs = time.Since(started).Milliseconds()
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	defer resp.Body.Close()
+
+	// Only the status code is recorded: response bodies of tenant-supplied URLs are never stored
+	attempt.StatusCode = resp.StatusCode
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, webhookResponseDrainSize))
+	return attempt
+}
+
+// logDeadLetter logs a dead-lettered delivery (tenant admins are expected to fix the endpoint and replay)
+func (s *WebhookService) logDeadLetter(ctx context.Context, delivery *models.WebhookDelivery, attempts int, lastError string) {
+	_ = s.logActivity(ctx, delivery.TenantID, "webhook_delivery_dead", "system", map[string]interface{}{
+		"subscription_id": delivery.SubscriptionID,
+		"delivery_id":     delivery.ID,
+		"event_type":      delivery.EventType,
+		"attempts":        attempts,
+		"last_error":      lastError,
+	})
+}
+
+// logActivity logs an activity
+func (s *WebhookService) logActivity(ctx context.Context, tenantID, eventType, actorID string, metadata map[string]interface{}) error {
+	actorType := models.ActorTypeUser
+	if actorID == "system" || actorID == "" {
+		actorType = models.ActorTypeSystem
+		actorID = ""
+	}
+
+	log := &models.ActivityLog{
+		TenantID:  tenantID,
+		EventType: eventType,
+		ActorType: actorType,
+		ActorID:   actorID,
+		Metadata:  metadata,
+		Timestamp: time.Now(),
+	}
+
+	return appendActivityLog(ctx, s.activityLogRepo, log)
+}
+
+// attemptError describes a failed attempt
+func attemptError(attempt models.WebhookAttempt) string {
+	if attempt.Error != "" {
+		return attempt.Error
+	}
+	return fmt.Sprintf("HTTP %d", attempt.StatusCode)
+}
+
+// webhookDeliveryID returns the deterministic ID of the delivery of an event to a subscription
+func webhookDeliveryID(subscriptionID, activityLogID string) string {
+	return subscriptionID + "_" + activityLogID
+}
+
+// generateWebhookSecret generates a random signing secret
+func generateWebhookSecret() (string, error) {
+	secretBytes := make([]byte, 32)
+	if _, err := rand.Read(secretBytes); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(secretBytes), nil
+}
+
+// validateWebhookURL requires an absolute HTTPS URL to a public host
+// allowInsecure (local development) also accepts plain HTTP and private/loopback hosts
+// Host names are checked again on each connection, after DNS resolution (see newWebhookHTTPClient)
+func validateWebhookURL(rawURL string, allowInsecure bool) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" {
+		return fmt.Errorf("invalid webhook url")
+	}
+
+	if allowInsecure {
+		if parsed.Scheme != "https" && parsed.Scheme != "http" {
+			return fmt.Errorf("webhook url must use http or https")
+		}
+		return nil
+	}
+
+	if parsed.Scheme != "https" {
+		return fmt.Errorf("webhook url must use https")
+	}
+	host := strings.ToLower(parsed.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") || strings.HasSuffix(host, ".internal") {
+		return ErrWebhookAddressBlocked
+	}
+	if ip := net.ParseIP(host); ip != nil && isBlockedWebhookIP(ip) {
+		return ErrWebhookAddressBlocked
+	}
+	return nil
+}
+
+// isBlockedWebhookIP checks whether a webhook must not connect to the address
+// (loopback, private, link-local - including cloud metadata 169.254.169.254 -, CGNAT, unspecified and multicast)
+func isBlockedWebhookIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return true
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		// 0.0.0.0/8, 100.64.0.0/10 (carrier-grade NAT) and 192.0.0.0/24 (IETF protocol assignments)
+		return ip4[0] == 0 || (ip4[0] == 100 && ip4[1]&0xc0 == 64) || (ip4[0] == 192 && ip4[1] == 0 && ip4[2] == 0)
+	}
+	return false
+}
+
+// validateWebhookEvents requires at least one supported event type
+func validateWebhookEvents(events []string) error {
+	if len(events) == 0 {
+		return fmt.Errorf("at least one event is required")
+	}
+	for _, event := range events {
+		if !models.IsValidWebhookEvent(event) {
+			return fmt.Errorf("unsupported webhook event: %s", event)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsBlockedWebhookIP(t *testing.T) {
+	blocked := []string{
+		"127.0.0.1", "10.0.0.5", "172.16.3.4", "192.168.1.10", "169.254.169.254",
+		"100.64.0.1", "0.0.0.0", "::1", "fc00::1", "fe80::1", "::ffff:127.0.0.1",
+	}
+	for _, addr := range blocked {
+		assert.True(t, isBlockedWebhookIP(net.ParseIP(addr)), addr)
+	}
+
+	for _, addr := range []string{"8.8.8.8", "200.147.67.142", "2001:4860:4860::8888"} {
+		assert.False(t, isBlockedWebhookIP(net.ParseIP(addr)), addr)
+	}
+}
+
+func TestValidateWebhookURL(t *testing.T) {
+	assert.NoError(t, validateWebhookURL("https://hooks.example.com/imob", false))
+	assert.Error(t, validateWebhookURL("http://hooks.example.com/imob", false))
+	assert.ErrorIs(t, validateWebhookURL("https://localhost/hook", false), ErrWebhookAddressBlocked)
+	assert.ErrorIs(t, validateWebhookURL("https://169.254.169.254/latest/meta-data", false), ErrWebhookAddressBlocked)
+	assert.ErrorIs(t, validateWebhookURL("https://[::1]:8443/hook", false), ErrWebhookAddressBlocked)
+
+	// Local development receivers
+	assert.NoError(t, validateWebhookURL("http://localhost:3000/hook", true))
+	assert.Error(t, validateWebhookURL("ftp://localhost/hook", true))
+}
+
+func TestWebhookHTTPClientBlocksPrivateAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, server.URL, nil)
+	require.NoError(t, err)
+	_, err = newWebhookHTTPClient(false).Do(req)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrWebhookAddressBlocked), err.Error())
+
+	req, err = http.NewRequestWithContext(context.Background(), http.MethodPost, server.URL, nil)
+	require.NoError(t, err)
+	resp, err := newWebhookHTTPClient(true).Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
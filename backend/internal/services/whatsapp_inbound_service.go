@@ -0,0 +1,374 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+const (
+	// whatsAppBrokerNotifyInterval is the minimum interval between two notifications of the same conversation
+	// (a burst of messages notifies the broker once)
+	whatsAppBrokerNotifyInterval = 30 * time.Minute
+
+	// whatsAppInboundConsentText is the consent text of leads created by an inbound message
+	whatsAppInboundConsentText = "Contato iniciado pelo interessado via WhatsApp. Os dados (nome do perfil e telefone) são usados somente para responder sobre o imóvel, conforme a Lei Geral de Proteção de Dados (LGPD)."
+)
+
+var (
+	// ErrWhatsAppInvalidVerifyToken is returned when the webhook subscription verification token does not match
+	ErrWhatsAppInvalidVerifyToken = errors.New("invalid whatsapp verify token")
+
+	// ErrWhatsAppInvalidSignature is returned when the X-Hub-Signature-256 of a webhook delivery is invalid
+	ErrWhatsAppInvalidSignature = errors.New("invalid whatsapp webhook signature")
+)
+
+// WhatsAppInboundResult summarizes the processing of a webhook delivery
+type WhatsAppInboundResult struct {
+	Received     int `json:"received"`
+	Threaded     int `json:"threaded"`      // messages added to a lead conversation
+	LeadsCreated int `json:"leads_created"` // new leads (no lead of the contact for the property)
+	Duplicates   int `json:"duplicates"`    // messages already received (webhook retries)
+	Unmatched    int `json:"unmatched"`     // no tenant or property identified
+	Failed       int `json:"failed"`
+}
+
+// WhatsAppInboundService turns messages received by the WhatsApp Cloud API webhook into leads and conversations
+type WhatsAppInboundService struct {
+	leadService         *LeadService
+	conversationService *ConversationService
+	leadRepo            *repositories.LeadRepository
+	propertyRepo        *repositories.PropertyRepository
+	tenantRepo          *repositories.TenantRepository
+	brokerRepo          *repositories.BrokerRepository
+	conversationRepo    *repositories.ConversationRepository
+	linkRepo            *repositories.WhatsAppLinkRepository
+	activityLogRepo     *repositories.ActivityLogRepository
+	emailService        *EmailService // Optional - notification of the routed broker
+	verifyToken         string        // hub.verify_token configured in the Meta app
+	appSecret           string        // App secret that signs deliveries (empty skips the check - development only)
+}
+
+// NewWhatsAppInboundService creates a new WhatsApp inbound service
+func NewWhatsAppInboundService(
+	leadService *LeadService,
+	conversationService *ConversationService,
+	leadRepo *repositories.LeadRepository,
+	propertyRepo *repositories.PropertyRepository,
+	tenantRepo *repositories.TenantRepository,
+	brokerRepo *repositories.BrokerRepository,
+	conversationRepo *repositories.ConversationRepository,
+	linkRepo *repositories.WhatsAppLinkRepository,
+	activityLogRepo *repositories.ActivityLogRepository,
+	verifyToken, appSecret string,
+) *WhatsAppInboundService {
+	return &WhatsAppInboundService{
+		leadService:         leadService,
+		conversationService: conversationService,
+		leadRepo:            leadRepo,
+		propertyRepo:        propertyRepo,
+		tenantRepo:          tenantRepo,
+		brokerRepo:          brokerRepo,
+		conversationRepo:    conversationRepo,
+		linkRepo:            linkRepo,
+		activityLogRepo:     activityLogRepo,
+		verifyToken:         verifyToken,
+		appSecret:           appSecret,
+	}
+}
+
+// SetEmailService sets the email service used to notify the routed broker (for dependency injection)
+func (s *WhatsAppInboundService) SetEmailService(emailService *EmailService) {
+	s.emailService = emailService
+}
+
+// VerifySubscription answers the webhook verification request of Meta (hub.mode=subscribe) with the challenge
+func (s *WhatsAppInboundService) VerifySubscription(mode, token, challenge string) (string, error) {
+	if mode != "subscribe" || s.verifyToken == "" || token != s.verifyToken {
+		return "", ErrWhatsAppInvalidVerifyToken
+	}
+	return challenge, nil
+}
+
+// VerifySignature checks the signature of a webhook delivery
+// Fails closed: every delivery is rejected while no app secret is configured
+func (s *WhatsAppInboundService) VerifySignature(body []byte, signature string) error {
+	if s.appSecret == "" || !models.VerifyWhatsAppSignature(s.appSecret, body, signature) {
+		return ErrWhatsAppInvalidSignature
+	}
+	return nil
+}
+
+// HandleWebhook processes the messages of a webhook delivery
+// Failures of a message are counted and logged: the delivery is acknowledged so Meta does not retry it forever
+func (s *WhatsAppInboundService) HandleWebhook(ctx context.Context, payload *models.WhatsAppWebhookPayload) *WhatsAppInboundResult {
+	result := &WhatsAppInboundResult{}
+	for _, message := range payload.InboundMessages() {
+		result.Received++
+		if err := s.handleMessage(ctx, message, result); err != nil {
+			log.Printf("Failed to process whatsapp message %s: %v", message.MessageID, err)
+			result.Failed++
+		}
+	}
+	return result
+}
+
+// whatsAppMatch is the tenant, property and (when identified) lead an inbound message belongs to
+type whatsAppMatch struct {
+	tenant     *models.Tenant
+	propertyID string
+	leadID     string
+	thread     *models.WhatsAppThread
+}
+
+// handleMessage threads a message into the conversation of the contact's lead for the property,
+// creating the lead when the contact has none
+func (s *WhatsAppInboundService) handleMessage(ctx context.Context, message models.WhatsAppInboundMessage, result *WhatsAppInboundResult) error {
+	match, err := s.match(ctx, message)
+	if err != nil {
+		return err
+	}
+	if match == nil {
+		result.Unmatched++
+		return nil
+	}
+	tenantID := match.tenant.ID
+
+	contactName := message.ContactName
+	if contactName == "" {
+		contactName = "Lead via WhatsApp"
+	}
+	phone := models.LeadPhoneFromWhatsAppID(message.WaID)
+
+	lead, isNewLead, err := s.resolveLead(ctx, tenantID, match, phone, contactName, message.Body)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.conversationService.RecordMessage(ctx, lead, &models.ConversationMessage{
+		Channel:     models.LeadChannelWhatsApp,
+		Direction:   models.MessageDirectionInbound,
+		Body:        message.Body,
+		ContentType: message.ContentType,
+		From:        "+" + message.WaID,
+		ContactName: message.ContactName,
+		Provider:    models.WhatsAppCloudProvider,
+		ExternalID:  message.MessageID,
+		SentAt:      message.SentAt,
+	})
+	if errors.Is(err, repositories.ErrAlreadyExists) {
+		result.Duplicates++
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to store message: %w", err)
+	}
+	result.Threaded++
+	if isNewLead {
+		result.LeadsCreated++
+	}
+
+	// Keep the contact's following messages in this conversation
+	thread := &models.WhatsAppThread{
+		WaID:          message.WaID,
+		LeadID:        lead.ID,
+		PropertyID:    lead.PropertyID,
+		ContactName:   message.ContactName,
+		LastInboundAt: message.SentAt,
+	}
+	if match.thread != nil && match.thread.LeadID == lead.ID {
+		thread.BrokerNotifiedAt = match.thread.BrokerNotifiedAt
+	}
+
+	brokerNotified := false
+	if isNewLead || thread.BrokerNotifiedAt == nil || time.Since(*thread.BrokerNotifiedAt) >= whatsAppBrokerNotifyInterval {
+		if err := s.notifyBroker(ctx, match.tenant, lead, contactName, message.Body, isNewLead); err != nil {
+			log.Printf("Failed to notify broker of whatsapp message for lead %s: %v", lead.ID, err)
+		} else {
+			now := time.Now()
+			thread.BrokerNotifiedAt = &now
+			brokerNotified = true
+		}
+	}
+
+	if err := s.conversationRepo.SetWhatsAppThread(ctx, tenantID, thread); err != nil {
+		log.Printf("Failed to update whatsapp thread of lead %s: %v", lead.ID, err)
+	}
+
+	_ = s.logActivity(ctx, tenantID, "lead_whatsapp_message_received", models.ActorTypeSystem, "", map[string]interface{}{
+		"lead_id":         lead.ID,
+		"property_id":     lead.PropertyID,
+		"message_id":      message.MessageID,
+		"content_type":    message.ContentType,
+		"new_lead":        isNewLead,
+		"broker_notified": brokerNotified,
+	})
+
+	return nil
+}
+
+// match identifies the tenant and property of a message: the short link or the business number that
+// received it gives the tenant; the protocol, the property link or reference, else the contact's
+// previous conversation give the property. Returns nil when the message cannot be matched
+func (s *WhatsAppInboundService) match(ctx context.Context, message models.WhatsAppInboundMessage) (*whatsAppMatch, error) {
+	refs := models.ParseWhatsAppMessageRefs(message.Body)
+	match := &whatsAppMatch{}
+
+	tenantID := ""
+	if refs.ShortLinkCode != "" {
+		if link, err := s.linkRepo.Get(ctx, refs.ShortLinkCode); err == nil {
+			tenantID = link.TenantID
+			match.propertyID = link.PropertyID
+			match.leadID = link.LeadID
+		}
+	}
+
+	if tenantID != "" {
+		tenant, err := s.tenantRepo.Get(ctx, tenantID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get tenant: %w", err)
+		}
+		match.tenant = tenant
+	} else if message.PhoneNumberID != "" {
+		tenant, err := s.tenantRepo.GetByWhatsAppPhoneNumberID(ctx, message.PhoneNumberID)
+		if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+			return nil, fmt.Errorf("failed to get tenant by phone number id: %w", err)
+		}
+		match.tenant = tenant
+	}
+	if match.tenant == nil || !match.tenant.IsActive {
+		return nil, nil
+	}
+	tenantID = match.tenant.ID
+
+	// Protocol = lead ID of the deep link message
+	if match.leadID == "" && refs.Protocol != "" {
+		if lead, err := s.leadRepo.Get(ctx, tenantID, refs.Protocol); err == nil {
+			match.leadID = lead.ID
+			match.propertyID = lead.PropertyID
+		}
+	}
+
+	if match.propertyID == "" && refs.PropertySlug != "" {
+		if property, err := s.propertyRepo.GetBySlug(ctx, tenantID, refs.PropertySlug); err == nil {
+			match.propertyID = property.ID
+		}
+	}
+
+	if match.propertyID == "" && refs.Reference != "" {
+		if property, err := s.propertyRepo.GetByReference(ctx, tenantID, refs.Reference); err == nil {
+			match.propertyID = property.ID
+		}
+	}
+
+	thread, err := s.conversationRepo.GetWhatsAppThread(ctx, tenantID, message.WaID)
+	if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+		return nil, fmt.Errorf("failed to get whatsapp thread: %w", err)
+	}
+	match.thread = thread
+
+	// Follow-up message without identifiers: continue the contact's last conversation
+	if match.propertyID == "" && thread != nil {
+		match.leadID = thread.LeadID
+		match.propertyID = thread.PropertyID
+	}
+
+	if match.propertyID == "" {
+		return nil, nil
+	}
+	return match, nil
+}
+
+// resolveLead returns the lead of the contact for the matched property: the lead identified by the
+// message (its WhatsApp placeholder contact is replaced by the sender), else the lead with the same
+// phone (dedup), else a new WhatsApp lead. The bool reports whether the lead was created
+func (s *WhatsAppInboundService) resolveLead(ctx context.Context, tenantID string, match *whatsAppMatch, phone, contactName, body string) (*models.Lead, bool, error) {
+	if match.leadID != "" {
+		lead, err := s.leadRepo.Get(ctx, tenantID, match.leadID)
+		if err == nil && !lead.IsAnonymized && lead.PropertyID == match.propertyID {
+			if lead.Phone == "" || lead.Phone == "WhatsApp" {
+				updates := map[string]interface{}{"phone": phone}
+				if lead.Name == "" || lead.Name == "Lead via WhatsApp" {
+					updates["name"] = contactName
+				}
+				if err := s.leadService.UpdateLead(ctx, tenantID, lead.ID, updates); err != nil {
+					log.Printf("Failed to set whatsapp contact of lead %s: %v", lead.ID, err)
+				}
+			}
+			return lead, false, nil
+		}
+	}
+
+	lead, err := s.leadRepo.GetByPhone(ctx, tenantID, match.propertyID, phone)
+	if err == nil && !lead.IsAnonymized && !lead.ConsentRevoked {
+		return lead, false, nil
+	}
+	if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+		return nil, false, fmt.Errorf("failed to get lead by phone: %w", err)
+	}
+
+	lead = &models.Lead{
+		TenantID:     tenantID,
+		PropertyID:   match.propertyID,
+		Name:         contactName,
+		Phone:        phone,
+		Message:      body,
+		Channel:      models.LeadChannelWhatsApp,
+		UTMSource:    "whatsapp",
+		ConsentGiven: true,
+		ConsentText:  whatsAppInboundConsentText,
+	}
+	if err := s.leadService.CreateLead(ctx, lead); err != nil {
+		return nil, false, fmt.Errorf("failed to create lead: %w", err)
+	}
+
+	return lead, true, nil
+}
+
+// notifyBroker emails the broker the lead is routed to about the message
+func (s *WhatsAppInboundService) notifyBroker(ctx context.Context, tenant *models.Tenant, lead *models.Lead, contactName, body string, isNewLead bool) error {
+	if s.emailService == nil {
+		return fmt.Errorf("email service is not configured")
+	}
+
+	brokerID, err := s.leadService.RouteToAvailableBroker(ctx, tenant.ID, lead.ID)
+	if err != nil {
+		return err
+	}
+	broker, err := s.brokerRepo.Get(ctx, tenant.ID, brokerID)
+	if err != nil {
+		return fmt.Errorf("broker not found: %w", err)
+	}
+	if broker.Email == "" {
+		return fmt.Errorf("broker %s has no email", brokerID)
+	}
+
+	propertyLabel := lead.PropertyID
+	if property, err := s.propertyRepo.Get(ctx, tenant.ID, lead.PropertyID); err == nil {
+		propertyLabel = property.Reference
+		if propertyLabel == "" {
+			propertyLabel = fmt.Sprintf("%s - %s", property.Neighborhood, property.City)
+		}
+	}
+
+	return s.emailService.SendLeadMessageNotification(broker.Email, broker.Name, tenant.ResolvedBranding(), contactName, propertyLabel, body, s.emailService.LeadURL(lead.ID), isNewLead)
+}
+
+// logActivity logs an activity
+func (s *WhatsAppInboundService) logActivity(ctx context.Context, tenantID, eventType string, actorType models.ActorType, actorID string, metadata map[string]interface{}) error {
+	log := &models.ActivityLog{
+		TenantID:  tenantID,
+		EventType: eventType,
+		ActorType: actorType,
+		ActorID:   actorID,
+		Metadata:  metadata,
+		Timestamp: time.Now(),
+	}
+
+	return appendActivityLog(ctx, s.activityLogRepo, log)
+}
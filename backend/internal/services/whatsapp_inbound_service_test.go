@@ -0,0 +1,33 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func whatsAppTestSignature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestWhatsAppVerifySignature(t *testing.T) {
+	body := []byte(`{"object":"whatsapp_business_account"}`)
+	service := &WhatsAppInboundService{appSecret: "app-secret"}
+
+	assert.NoError(t, service.VerifySignature(body, whatsAppTestSignature("app-secret", body)))
+	assert.ErrorIs(t, service.VerifySignature(body, whatsAppTestSignature("other-secret", body)), ErrWhatsAppInvalidSignature)
+	assert.ErrorIs(t, service.VerifySignature(body, ""), ErrWhatsAppInvalidSignature)
+}
+
+func TestWhatsAppVerifySignatureFailsClosedWithoutSecret(t *testing.T) {
+	body := []byte(`{"object":"whatsapp_business_account"}`)
+	service := &WhatsAppInboundService{}
+
+	assert.ErrorIs(t, service.VerifySignature(body, ""), ErrWhatsAppInvalidSignature)
+	assert.ErrorIs(t, service.VerifySignature(body, whatsAppTestSignature("", body)), ErrWhatsAppInvalidSignature)
+}
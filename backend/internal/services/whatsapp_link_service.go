@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/utils"
+)
+
+// whatsAppLinkCodeAttempts is how many random codes are tried before giving up on a collision
+const whatsAppLinkCodeAttempts = 3
+
+// WhatsAppLinkService creates click-tracking short links for WhatsApp deep links and records their clicks
+type WhatsAppLinkService struct {
+	linkRepo        *repositories.WhatsAppLinkRepository
+	activityLogRepo *repositories.ActivityLogRepository
+	metricsService  *ListingMetricsService // Optional - whatsapp_click counters of the property
+	baseURL         string                 // Base URL of the short links (ex: https://api.imob.com.br)
+}
+
+// NewWhatsAppLinkService creates a new WhatsApp link service
+func NewWhatsAppLinkService(
+	linkRepo *repositories.WhatsAppLinkRepository,
+	activityLogRepo *repositories.ActivityLogRepository,
+	baseURL string,
+) *WhatsAppLinkService {
+	return &WhatsAppLinkService{
+		linkRepo:        linkRepo,
+		activityLogRepo: activityLogRepo,
+		baseURL:         strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+// SetListingMetricsService sets the listing metrics service (for dependency injection)
+func (s *WhatsAppLinkService) SetListingMetricsService(service *ListingMetricsService) {
+	s.metricsService = service
+}
+
+// CreateLink stores a short link for a WhatsApp deep link and returns its short URL
+func (s *WhatsAppLinkService) CreateLink(ctx context.Context, link *models.WhatsAppLink) (string, error) {
+	if link.LeadID == "" {
+		return "", fmt.Errorf("lead_id is required")
+	}
+	if link.TargetURL == "" {
+		return "", fmt.Errorf("target_url is required")
+	}
+
+	for attempt := 0; attempt < whatsAppLinkCodeAttempts; attempt++ {
+		codeBytes := make([]byte, 6)
+		if _, err := rand.Read(codeBytes); err != nil {
+			return "", fmt.Errorf("failed to generate link code: %w", err)
+		}
+		link.Code = base64.RawURLEncoding.EncodeToString(codeBytes)
+
+		err := s.linkRepo.Create(ctx, link)
+		if err == nil {
+			return s.ShortURL(link.Code), nil
+		}
+		if !errors.Is(err, repositories.ErrAlreadyExists) {
+			return "", err
+		}
+	}
+
+	return "", fmt.Errorf("failed to generate a unique link code")
+}
+
+// ShortURL returns the short URL of a link code
+func (s *WhatsAppLinkService) ShortURL(code string) string {
+	return fmt.Sprintf("%s/w/%s", s.baseURL, code)
+}
+
+// Click records a click on a short link (lead, channel and property metrics) and returns the link to redirect to
+// Clicks from bots (link previews, crawlers) are not counted; recording failures never block the redirect
+func (s *WhatsAppLinkService) Click(ctx context.Context, code, userAgent string) (*models.WhatsAppLink, error) {
+	link, err := s.linkRepo.Get(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	if utils.IsBotUserAgent(userAgent) {
+		return link, nil
+	}
+
+	if err := s.linkRepo.RecordClick(ctx, code); err != nil {
+		log.Printf("Failed to record click of whatsapp link %s: %v", code, err)
+	}
+
+	if s.metricsService != nil && link.PropertyID != "" {
+		if _, err := s.metricsService.TrackEvent(ctx, TrackEventInput{
+			TenantID:   link.TenantID,
+			PropertyID: link.PropertyID,
+			EventType:  models.TrackingEventWhatsAppClick,
+			UserAgent:  userAgent,
+		}); err != nil {
+			log.Printf("Failed to track whatsapp click of property %s: %v", link.PropertyID, err)
+		}
+	}
+
+	_ = s.logActivity(ctx, link.TenantID, "whatsapp_link_clicked", models.ActorTypeSystem, "", map[string]interface{}{
+		"code":         code,
+		"lead_id":      link.LeadID,
+		"property_id":  link.PropertyID,
+		"broker_id":    link.BrokerID,
+		"channel":      link.Channel,
+		"utm_source":   link.UTMSource,
+		"phone_source": link.PhoneSource,
+	})
+
+	return link, nil
+}
+
+// logActivity logs an activity
+func (s *WhatsAppLinkService) logActivity(ctx context.Context, tenantID, eventType string, actorType models.ActorType, actorID string, metadata map[string]interface{}) error {
+	log := &models.ActivityLog{
+		TenantID:  tenantID,
+		EventType: eventType,
+		ActorType: actorType,
+		ActorID:   actorID,
+		Metadata:  metadata,
+		Timestamp: time.Now(),
+	}
+
+	return appendActivityLog(ctx, s.activityLogRepo, log)
+}
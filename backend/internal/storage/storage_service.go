@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"log"
 	"mime/multipart"
 	"net/url"
 	"strings"
@@ -23,6 +24,9 @@ const (
 
 	// SignedURLExpiration is the duration for signed URLs
 	SignedURLExpiration = 1 * time.Hour
+
+	// DocumentSignedURLExpiration is the duration of signed URLs for private property documents
+	DocumentSignedURLExpiration = 5 * time.Minute
 )
 
 var (
@@ -33,14 +37,27 @@ var (
 		"image/webp": true,
 	}
 
+	// AllowedDocumentContentTypes defines the allowed MIME types for property documents
+	AllowedDocumentContentTypes = map[string]bool{
+		"application/pdf": true,
+		"image/jpeg":      true,
+		"image/png":       true,
+	}
+
 	// ErrFileTooLarge is returned when file size exceeds the limit
 	ErrFileTooLarge = fmt.Errorf("file size exceeds maximum allowed size of %d bytes", MaxFileSize)
 
 	// ErrInvalidFileType is returned when file type is not allowed
 	ErrInvalidFileType = fmt.Errorf("invalid file type, allowed types: image/jpeg, image/png, image/webp")
 
+	// ErrInvalidDocumentType is returned when document type is not allowed
+	ErrInvalidDocumentType = fmt.Errorf("invalid document type, allowed types: application/pdf, image/jpeg, image/png")
+
 	// ErrImageNotFound is returned when image is not found
 	ErrImageNotFound = fmt.Errorf("image not found")
+
+	// ErrDocumentNotFound is returned when a document object is not found
+	ErrDocumentNotFound = fmt.Errorf("document not found")
 )
 
 // ImageMetadata represents metadata for an uploaded image
@@ -358,6 +375,103 @@ func (s *StorageService) generateSignedURL(ctx context.Context, objectPath strin
 	// return storage.SignedURL(s.bucketName, objectPath, opts)
 }
 
+// UploadPropertyDocument uploads a property document to a PRIVATE object (no public URL is generated)
+// Returns the storage path, to be kept in the PropertyDocument record
+func (s *StorageService) UploadPropertyDocument(
+	ctx context.Context,
+	tenantID, propertyID string,
+	file multipart.File,
+	header *multipart.FileHeader,
+) (string, error) {
+	// Validate inputs
+	if tenantID == "" {
+		return "", fmt.Errorf("tenant_id is required")
+	}
+	if propertyID == "" {
+		return "", fmt.Errorf("property_id is required")
+	}
+
+	// Validate file size
+	if header.Size > MaxFileSize {
+		return "", ErrFileTooLarge
+	}
+
+	// Validate content type
+	contentType := header.Header.Get("Content-Type")
+	if !AllowedDocumentContentTypes[contentType] {
+		return "", ErrInvalidDocumentType
+	}
+
+	// Build storage path: /documents/{tenantID}/{propertyID}/{documentID}
+	storagePath := fmt.Sprintf("documents/%s/%s/%s", tenantID, propertyID, uuid.New().String())
+
+	writer := s.storageClient.Bucket(s.bucketName).Object(storagePath).NewWriter(ctx)
+	writer.ContentType = contentType
+	writer.Metadata = map[string]string{
+		"tenant_id":         tenantID,
+		"property_id":       propertyID,
+		"original_filename": header.Filename,
+		"uploaded_at":       time.Now().Format(time.RFC3339),
+	}
+
+	// Copy file data to storage
+	if _, err := io.Copy(writer, file); err != nil {
+		writer.Close()
+		return "", fmt.Errorf("failed to upload document: %w", err)
+	}
+
+	// Close the writer
+	if err := writer.Close(); err != nil {
+		return "", fmt.Errorf("failed to finalize document upload: %w", err)
+	}
+
+	return storagePath, nil
+}
+
+// GetPropertyDocumentURL generates a short-lived V4 signed URL for a private property document
+// Requires credentials able to sign (service account key or IAM signBlob permission)
+func (s *StorageService) GetPropertyDocumentURL(ctx context.Context, storagePath string) (string, time.Time, error) {
+	if storagePath == "" {
+		return "", time.Time{}, fmt.Errorf("storage_path is required")
+	}
+
+	bucket := s.storageClient.Bucket(s.bucketName)
+	if _, err := bucket.Object(storagePath).Attrs(ctx); err != nil {
+		if err == storage.ErrObjectNotExist {
+			return "", time.Time{}, ErrDocumentNotFound
+		}
+		return "", time.Time{}, fmt.Errorf("failed to check document: %w", err)
+	}
+
+	expiresAt := time.Now().Add(DocumentSignedURLExpiration)
+	signedURL, err := bucket.SignedURL(storagePath, &storage.SignedURLOptions{
+		Scheme:  storage.SigningSchemeV4,
+		Method:  "GET",
+		Expires: expiresAt,
+	})
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to generate signed URL: %w", err)
+	}
+
+	return signedURL, expiresAt, nil
+}
+
+// DeletePropertyDocument deletes a private property document object
+func (s *StorageService) DeletePropertyDocument(ctx context.Context, storagePath string) error {
+	if storagePath == "" {
+		return fmt.Errorf("storage_path is required")
+	}
+
+	if err := s.storageClient.Bucket(s.bucketName).Object(storagePath).Delete(ctx); err != nil {
+		if err == storage.ErrObjectNotExist {
+			return ErrDocumentNotFound
+		}
+		return fmt.Errorf("failed to delete document: %w", err)
+	}
+
+	return nil
+}
+
 // ValidateContentType validates if the content type is allowed
 func (s *StorageService) ValidateContentType(contentType string) error {
 	if !AllowedContentTypes[contentType] {
@@ -517,6 +631,32 @@ func (s *StorageService) DeleteBrokerPhoto(
 	return nil
 }
 
+// TenantStorageBytes returns the bytes stored by a tenant (property images, documents and broker photos)
+func (s *StorageService) TenantStorageBytes(ctx context.Context, tenantID string) (int64, error) {
+	if tenantID == "" {
+		return 0, fmt.Errorf("tenant_id is required")
+	}
+
+	bucket := s.storageClient.Bucket(s.bucketName)
+
+	var total int64
+	for _, root := range []string{"properties", "documents", "brokers"} {
+		it := bucket.Objects(ctx, &storage.Query{Prefix: fmt.Sprintf("%s/%s/", root, tenantID)})
+		for {
+			attrs, err := it.Next()
+			if err == iterator.Done {
+				break
+			}
+			if err != nil {
+				return 0, fmt.Errorf("failed to list %s objects: %w", root, err)
+			}
+			total += attrs.Size
+		}
+	}
+
+	return total, nil
+}
+
 // logActivity logs an activity
 func (s *StorageService) logActivity(
 	ctx context.Context,
@@ -525,7 +665,7 @@ func (s *StorageService) logActivity(
 	actorID string,
 	metadata map[string]interface{},
 ) error {
-	log := &models.ActivityLog{
+	entry := &models.ActivityLog{
 		TenantID:  tenantID,
 		EventType: eventType,
 		ActorType: actorType,
@@ -534,5 +674,10 @@ func (s *StorageService) logActivity(
 		Timestamp: time.Now(),
 	}
 
-	return s.activityLogRepo.Create(ctx, log)
+	// Callers do not fail the upload or deletion when the entry is lost, so the failure is logged here
+	err := s.activityLogRepo.Create(ctx, entry)
+	if err != nil && err != repositories.ErrAlreadyExists {
+		log.Printf("⚠️  Activity log %s not appended for tenant %s: %v", eventType, tenantID, err)
+	}
+	return err
 }
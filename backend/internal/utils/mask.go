@@ -0,0 +1,100 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ============================================================================
+// PII Masking (LGPD)
+// Used in API responses for roles without PII permission and in public pages
+// ============================================================================
+
+var maskNonDigitRegex = regexp.MustCompile(`[^\d]`)
+
+// MaskName keeps only the beginning of a name
+// "João Silva" -> "João ..."
+func MaskName(name string) string {
+	if name == "" {
+		return ""
+	}
+	if len(name) <= 2 {
+		return name
+	}
+	parts := []rune(name)
+	if len(parts) > 5 {
+		return string(parts[0:5]) + "..."
+	}
+	return name
+}
+
+// MaskPhone hides the middle digits of a phone number, keeping its formatting
+// "(11) 98765-4321" -> "(11) 9****-4321"
+func MaskPhone(phone string) string {
+	if phone == "" {
+		return ""
+	}
+
+	digits := len(maskNonDigitRegex.ReplaceAllString(phone, ""))
+	keepStart, keepEnd := 3, 4 // DDD + first digit, last 4 digits
+	if digits < keepStart+keepEnd+2 {
+		keepStart = 0
+	}
+	if digits <= keepEnd {
+		keepEnd = 0
+	}
+
+	var b strings.Builder
+	position := 0
+	for _, r := range phone {
+		if r < '0' || r > '9' {
+			b.WriteRune(r)
+			continue
+		}
+		if position < keepStart || position >= digits-keepEnd {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('*')
+		}
+		position++
+	}
+	return b.String()
+}
+
+// MaskEmail keeps the first character of the local part and the domain
+// "joao@example.com" -> "j***@example.com"
+func MaskEmail(email string) string {
+	if email == "" {
+		return ""
+	}
+
+	atIndex := strings.Index(email, "@")
+	if atIndex <= 0 {
+		return email
+	}
+
+	return email[0:1] + "***" + email[atIndex:]
+}
+
+// MaskDocument hides a CPF/CNPJ following the usual public format
+// CPF "123.456.789-09" -> "***.456.789-**"
+// CNPJ "11.222.333/0001-81" -> "**.***.***/0001-**"
+func MaskDocument(document string) string {
+	if document == "" {
+		return ""
+	}
+
+	digits := maskNonDigitRegex.ReplaceAllString(document, "")
+	switch len(digits) {
+	case 11:
+		return fmt.Sprintf("***.%s.%s-**", digits[3:6], digits[6:9])
+	case 14:
+		return fmt.Sprintf("**.***.***/%s-**", digits[8:12])
+	default:
+		if len(digits) <= 2 {
+			return "****"
+		}
+		return "****" + digits[len(digits)-2:]
+	}
+}
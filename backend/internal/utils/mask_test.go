@@ -0,0 +1,76 @@
+package utils
+
+import "testing"
+
+func TestMaskPhone(t *testing.T) {
+	tests := []struct {
+		phone string
+		want  string
+	}{
+		{"(11) 98765-4321", "(11) 9****-4321"},
+		{"+55 11 98765-4321", "+55 1* *****-4321"},
+		{"4321", "****"},
+		{"987654", "**7654"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := MaskPhone(tt.phone); got != tt.want {
+			t.Errorf("MaskPhone(%q) = %q, want %q", tt.phone, got, tt.want)
+		}
+	}
+}
+
+func TestMaskEmail(t *testing.T) {
+	tests := []struct {
+		email string
+		want  string
+	}{
+		{"joao@example.com", "j***@example.com"},
+		{"j@example.com", "j***@example.com"},
+		{"invalid", "invalid"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := MaskEmail(tt.email); got != tt.want {
+			t.Errorf("MaskEmail(%q) = %q, want %q", tt.email, got, tt.want)
+		}
+	}
+}
+
+func TestMaskDocument(t *testing.T) {
+	tests := []struct {
+		document string
+		want     string
+	}{
+		{"123.456.789-09", "***.456.789-**"},
+		{"12345678909", "***.456.789-**"},
+		{"11.222.333/0001-81", "**.***.***/0001-**"},
+		{"12345", "****45"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := MaskDocument(tt.document); got != tt.want {
+			t.Errorf("MaskDocument(%q) = %q, want %q", tt.document, got, tt.want)
+		}
+	}
+}
+
+func TestMaskName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"João Silva", "João ..."},
+		{"Ana", "Ana"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := MaskName(tt.name); got != tt.want {
+			t.Errorf("MaskName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
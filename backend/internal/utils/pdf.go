@@ -0,0 +1,148 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// PDF page layout (A4 in points)
+const (
+	pdfPageWidth  = 595.0
+	pdfPageHeight = 842.0
+	pdfMargin     = 50.0
+)
+
+// pdfLine is a single text line positioned on a page
+type pdfLine struct {
+	text string
+	size float64
+	bold bool
+	y    float64
+}
+
+// PDFDocument is a minimal text-only PDF writer (Helvetica, WinAnsi encoding)
+// Suficiente para relatórios e faturas simples, sem dependências externas
+type PDFDocument struct {
+	pages [][]pdfLine
+	y     float64
+}
+
+// NewPDFDocument creates an empty PDF document with one A4 page
+func NewPDFDocument() *PDFDocument {
+	return &PDFDocument{
+		pages: [][]pdfLine{{}},
+		y:     pdfPageHeight - pdfMargin,
+	}
+}
+
+// Title adds a large bold line
+func (d *PDFDocument) Title(text string) {
+	d.addLine(text, 18, true)
+	d.Space(6)
+}
+
+// Heading adds a bold section heading
+func (d *PDFDocument) Heading(text string) {
+	d.Space(8)
+	d.addLine(text, 13, true)
+	d.Space(2)
+}
+
+// Text adds a regular text line
+func (d *PDFDocument) Text(text string) {
+	d.addLine(text, 11, false)
+}
+
+// Space adds vertical space (in points)
+func (d *PDFDocument) Space(points float64) {
+	d.y -= points
+}
+
+// addLine places a line at the current position, breaking to a new page when needed
+func (d *PDFDocument) addLine(text string, size float64, bold bool) {
+	leading := size * 1.4
+	if d.y-leading < pdfMargin {
+		d.pages = append(d.pages, []pdfLine{})
+		d.y = pdfPageHeight - pdfMargin
+	}
+	d.y -= leading
+
+	current := len(d.pages) - 1
+	d.pages[current] = append(d.pages[current], pdfLine{text: text, size: size, bold: bold, y: d.y})
+}
+
+// WriteTo renders the document as PDF 1.4
+func (d *PDFDocument) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	offsets := make([]int, 0)
+
+	writeObject := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// Objects 1-4: catalog, page tree, fonts (page objects start at 5)
+	pageCount := len(d.pages)
+	kids := make([]string, pageCount)
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+i*2)
+	}
+
+	writeObject("<< /Type /Catalog /Pages 2 0 R >>")
+	writeObject(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), pageCount))
+	writeObject("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	writeObject("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, lines := range d.pages {
+		var content bytes.Buffer
+		for _, line := range lines {
+			font := "F1"
+			if line.bold {
+				font = "F2"
+			}
+			fmt.Fprintf(&content, "BT /%s %.0f Tf %.2f %.2f Td (%s) Tj ET\n", font, line.size, pdfMargin, line.y, pdfEscape(line.text))
+		}
+
+		writeObject(fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, 6+i*2,
+		))
+		writeObject(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()))
+	}
+
+	xrefOffset := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xrefOffset)
+
+	n, err := w.Write(buf.Bytes())
+	return int64(n), err
+}
+
+// pdfEscape converts text to WinAnsi (acentos em português) and escapes PDF string delimiters
+func pdfEscape(text string) string {
+	encoded, err := charmap.Windows1252.NewEncoder().String(text)
+	if err != nil {
+		// Fallback: replace characters outside Windows-1252
+		var sb strings.Builder
+		for _, r := range text {
+			if _, ok := charmap.Windows1252.EncodeRune(r); ok {
+				sb.WriteRune(r)
+			} else {
+				sb.WriteRune('?')
+			}
+		}
+		encoded, _ = charmap.Windows1252.NewEncoder().String(sb.String())
+	}
+
+	replacer := strings.NewReplacer(`\`, `\\`, `(`, `\(`, `)`, `\)`, "\r", "", "\n", " ")
+	return replacer.Replace(encoded)
+}
@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestPDFDocument_WriteTo(t *testing.T) {
+	doc := NewPDFDocument()
+	doc.Title("Relatório mensal")
+	doc.Heading("Desempenho")
+	doc.Text("Visualizações: 120 (alta)")
+
+	var buf bytes.Buffer
+	if _, err := doc.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo() error = %v", err)
+	}
+
+	out := buf.String()
+	if !strings.HasPrefix(out, "%PDF-1.4") {
+		t.Errorf("output does not start with PDF header")
+	}
+	if !strings.HasSuffix(out, "%%EOF\n") {
+		t.Errorf("output does not end with EOF marker")
+	}
+	if !strings.Contains(out, `\(alta\)`) {
+		t.Errorf("parentheses were not escaped")
+	}
+	if !strings.Contains(out, "Relat\xf3rio") {
+		t.Errorf("accented text was not encoded as WinAnsi")
+	}
+}
+
+func TestPDFDocument_PageBreak(t *testing.T) {
+	doc := NewPDFDocument()
+	for i := 0; i < 100; i++ {
+		doc.Text("linha")
+	}
+
+	var buf bytes.Buffer
+	if _, err := doc.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo() error = %v", err)
+	}
+
+	if len(doc.pages) < 2 {
+		t.Fatalf("expected multiple pages, got %d", len(doc.pages))
+	}
+	if !strings.Contains(buf.String(), "/Count 2") && !strings.Contains(buf.String(), "/Count 3") {
+		t.Errorf("page tree does not reflect page count")
+	}
+}
+
+func TestPDFEscape(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"Plain text", "Imóvel AP001", "Im\xf3vel AP001"},
+		{"Backslash", `a\b`, `a\\b`},
+		{"Unsupported rune", "casa 🏠", "casa ?"},
+		{"Newline", "a\nb", "a b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pdfEscape(tt.input); got != tt.want {
+				t.Errorf("pdfEscape(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ============================================================================
+// Bot Detection (tracking de visualizações)
+// ============================================================================
+
+// botUserAgentRegex matches crawlers, link previewers and headless browsers.
+// Link previews (WhatsApp, Facebook, Telegram) are excluded on purpose: sharing a
+// property link in a chat must not count as a view.
+var botUserAgentRegex = regexp.MustCompile(`(?i)(bot|crawler|spider|crawl|slurp|mediapartners|facebookexternalhit|whatsapp|telegram|preview|headless|phantomjs|puppeteer|playwright|selenium|lighthouse|pingdom|uptime|monitor|curl|wget|python-requests|python-urllib|go-http-client|java/|okhttp|axios|node-fetch|postman|insomnia)`)
+
+// IsBotUserAgent reports whether the User-Agent belongs to a bot or automated client.
+// An empty User-Agent is treated as a bot (real browsers always send one).
+func IsBotUserAgent(userAgent string) bool {
+	userAgent = strings.TrimSpace(userAgent)
+	if userAgent == "" {
+		return true
+	}
+
+	return botUserAgentRegex.MatchString(userAgent)
+}